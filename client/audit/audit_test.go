@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"gophkeeper/client/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		pass     string
+		inputs   []string
+		maxScore int
+		minScore int
+		warning  string
+	}{
+		{name: "empty", pass: "", maxScore: 0, warning: WarnShort},
+		{name: "common", pass: "password", maxScore: 0, warning: WarnCommon},
+		{name: "common leet", pass: "P4ssword", maxScore: 1, warning: WarnCommon},
+		{name: "sequence", pass: "abcdefgh", maxScore: 0, warning: WarnSequence},
+		{name: "repeat", pass: "zzzzzzzzzz", maxScore: 0, warning: WarnRepeat},
+		{name: "repeated block", pass: "k2#k2#k2#k2#", maxScore: 2, warning: WarnRepeat},
+		{name: "keyboard", pass: "asdfghjkl", maxScore: 1, warning: WarnKeyboard},
+		{name: "login", pass: "ci-bot", inputs: []string{"ci-bot"}, maxScore: 0, warning: WarnUserInput},
+		{name: "short random", pass: "kP3x", maxScore: 2, warning: WarnShort},
+		{name: "passphrase", pass: "correct-horse-battery-staple", minScore: 4, maxScore: 4},
+		{name: "random", pass: "xK9#mQ2$vL8!pZ4w", minScore: 4, maxScore: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Estimate(tt.pass, tt.inputs...)
+
+			assert.LessOrEqual(t, got.Score, tt.maxScore)
+			assert.GreaterOrEqual(t, got.Score, tt.minScore)
+			assert.Equal(t, tt.warning, got.Warning)
+		})
+	}
+
+	assert.Less(t, Estimate("password").Guesses, Estimate("Password2019").Guesses)
+}
+
+func TestParseCardExpiry(t *testing.T) {
+	tests := []struct {
+		expiry string
+		want   time.Time
+		ok     bool
+	}{
+		{expiry: "08/27", want: time.Date(2027, 9, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		{expiry: "12/2026", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		{expiry: " 3-29 ", want: time.Date(2029, 4, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		{expiry: "0125", want: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		{expiry: "13/25"},
+		{expiry: "soon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expiry, func(t *testing.T) {
+			got, ok := ParseCardExpiry(tt.expiry, time.UTC)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	fresh := uint64(now.Add(-24 * time.Hour).Unix())
+	old := uint64(now.Add(-400 * 24 * time.Hour).Unix())
+
+	records := []domain.Data{
+		{ID: 1, Name: "mail", Login: "me", Pass: "qwerty123", Version: fresh},
+		{ID: 2, Name: "bank", Pass: "Wq7#zP2!mK9$rT4x", Version: old},
+		{ID: 3, Name: "shop", Pass: "Wq7#zP2!mK9$rT4x", Version: fresh},
+		{ID: 4, Name: "git", Pass: "vN8@cX3%hJ6^eB1y", Version: fresh},
+		{ID: 5, Name: "visa", CardNum: "4505 0000 0000 1234", CardExp: "11/26", Version: old},
+		{ID: 6, Name: "old visa", CardExp: "01/25", Version: fresh},
+		{ID: 7, Name: "new visa", CardExp: "01/30", Version: fresh},
+		{ID: 8, Name: "broken visa", CardExp: "someday", Version: fresh},
+		{ID: 9, Name: "note", Text: "text", Version: old},
+	}
+
+	report, err := Build(records, Options{MaxPasswordAge: DefaultMaxPasswordAge, CardExpiryWarn: DefaultCardExpiryWarn, Now: now})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9, report.Records)
+	assert.Equal(t, 4, report.Passwords)
+	assert.Equal(t, 6, report.Issues)
+	assert.False(t, report.BreachChecked)
+	assert.Empty(t, report.Breached)
+
+	require.Len(t, report.Weak, 1)
+	assert.Equal(t, uint64(1), report.Weak[0].ID)
+
+	require.Len(t, report.Reused, 1)
+	assert.Equal(t, []Record{{ID: 2, Name: "bank"}, {ID: 3, Name: "shop"}}, report.Reused[0].Records)
+
+	require.Len(t, report.Old, 1)
+	assert.Equal(t, uint64(2), report.Old[0].ID)
+	assert.Equal(t, 400, report.Old[0].AgeDays)
+
+	require.Len(t, report.Cards, 3)
+	assert.Equal(t, "broken visa", report.Cards[0].Name)
+	assert.True(t, report.Cards[0].Invalid)
+	assert.Equal(t, "old visa", report.Cards[1].Name)
+	assert.True(t, report.Cards[1].Expired)
+	assert.Equal(t, "visa", report.Cards[2].Name)
+	assert.False(t, report.Cards[2].Expired)
+
+	// пароли не попадают в отчет
+	raw, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "qwerty123")
+	assert.NotContains(t, string(raw), "Wq7#zP2!mK9$rT4x")
+}
+
+// breachList база утечек в памяти
+type breachList map[string]int
+
+func (b breachList) Count(pass string) (int, error) {
+	if pass == "unavailable" {
+		return 0, errors.New("breach source unavailable")
+	}
+
+	return b[pass], nil
+}
+
+func TestBuild_Breach(t *testing.T) {
+	records := []domain.Data{
+		{ID: 1, Name: "mail", Pass: "Wq7#zP2!mK9$rT4x"},
+		{ID: 2, Name: "bank", Pass: "vN8@cX3%hJ6^eB1y"},
+		{ID: 3, Name: "shop", Pass: "vN8@cX3%hJ6^eB1y"},
+	}
+
+	report, err := Build(records, Options{Breach: breachList{"vN8@cX3%hJ6^eB1y": 12}})
+	require.NoError(t, err)
+
+	assert.True(t, report.BreachChecked)
+	assert.Equal(t, []BreachedPassword{
+		{Record: Record{ID: 2, Name: "bank"}, Count: 12},
+		{Record: Record{ID: 3, Name: "shop"}, Count: 12},
+	}, report.Breached)
+	assert.Equal(t, 2, report.Issues)
+
+	records = append(records, domain.Data{ID: 4, Name: "down", Pass: "unavailable"})
+	_, err = Build(records, Options{Breach: breachList{}})
+	assert.Error(t, err)
+}
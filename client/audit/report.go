@@ -0,0 +1,233 @@
+// Package audit отчет о состоянии хранилища: слабые, повторяющиеся, давно не менявшиеся и попавшие в утечки пароли,
+// карты с истекающим сроком действия. Отчет строится на клиенте по расшифрованным данным,
+// в сам отчет пароли не попадают
+package audit
+
+import (
+	"gophkeeper/client/domain"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Параметры отчета по умолчанию
+const (
+	DefaultMaxPasswordAge = 365 * 24 * time.Hour
+	DefaultCardExpiryWarn = 60 * 24 * time.Hour
+	// WeakScore пароли с оценкой ниже считаются слабыми
+	WeakScore = 3
+)
+
+// cardExpPattern срок действия карты: MM/YY, MM/YYYY, MM-YY или MMYY
+var cardExpPattern = regexp.MustCompile(`^\s*(\d{1,2})\s*[/.\-]?\s*(\d{2}|\d{4})\s*$`)
+
+// Options параметры отчета
+type Options struct {
+	// MaxPasswordAge пароль старше считается устаревшим, 0 - не проверять
+	MaxPasswordAge time.Duration
+	// CardExpiryWarn карта, срок действия которой истекает раньше, попадает в отчет
+	CardExpiryWarn time.Duration
+	// Now время построения отчета, по умолчанию текущее
+	Now time.Time
+	// Breach проверка паролей по базе утечек, nil - не проверять
+	Breach BreachChecker
+}
+
+// BreachChecker проверка пароля по базе утечек, возвращает сколько раз пароль встречался в утечках
+type BreachChecker interface {
+	Count(pass string) (int, error)
+}
+
+// DefaultOptions параметры по умолчанию
+func DefaultOptions() Options {
+	return Options{
+		MaxPasswordAge: DefaultMaxPasswordAge,
+		CardExpiryWarn: DefaultCardExpiryWarn,
+	}
+}
+
+// Record запись в отчете
+type Record struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+// WeakPassword запись со слабым паролем
+type WeakPassword struct {
+	Record
+	Strength
+}
+
+// ReusedPassword записи с одинаковым паролем
+type ReusedPassword struct {
+	Records []Record `json:"records"`
+}
+
+// OldPassword запись, которая давно не менялась
+// время изменения берется из версии записи, поэтому учитывается любое изменение записи, а не только пароля
+type OldPassword struct {
+	Record
+	ChangedAt time.Time `json:"changed_at"`
+	AgeDays   int       `json:"age_days"`
+}
+
+// BreachedPassword запись с паролем из базы утечек, Count - сколько раз пароль встречался в утечках
+type BreachedPassword struct {
+	Record
+	Count int `json:"count"`
+}
+
+// ExpiringCard карта с истекающим или истекшим сроком действия
+// Invalid - срок действия указан в неизвестном формате
+type ExpiringCard struct {
+	Record
+	Expiry    string    `json:"expiry"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Expired   bool      `json:"expired,omitempty"`
+	Invalid   bool      `json:"invalid,omitempty"`
+}
+
+// Report отчет о состоянии хранилища
+// Records - количество проверенных записей, Passwords - из них с паролем, Issues - количество записей с замечаниями
+// BreachChecked - проверялись ли пароли по базе утечек
+type Report struct {
+	GeneratedAt   time.Time          `json:"generated_at"`
+	Records       int                `json:"records"`
+	Passwords     int                `json:"passwords"`
+	Issues        int                `json:"issues"`
+	BreachChecked bool               `json:"breach_checked"`
+	Breached      []BreachedPassword `json:"breached"`
+	Weak          []WeakPassword     `json:"weak"`
+	Reused        []ReusedPassword   `json:"reused"`
+	Old           []OldPassword      `json:"old"`
+	Cards         []ExpiringCard     `json:"cards"`
+}
+
+// Build построение отчета по расшифрованным записям
+// ошибка возвращается только при недоступности базы утечек
+func Build(records []domain.Data, o Options) (Report, error) {
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+
+	res := Report{
+		GeneratedAt:   o.Now,
+		Records:       len(records),
+		BreachChecked: o.Breach != nil,
+		Breached:      []BreachedPassword{},
+		Weak:          []WeakPassword{},
+		Reused:        []ReusedPassword{},
+		Old:           []OldPassword{},
+		Cards:         []ExpiringCard{},
+	}
+
+	issues := make(map[uint64]struct{})
+	byPass := make(map[string][]Record)
+
+	for _, d := range records {
+		rec := Record{ID: d.ID, Name: d.Name}
+
+		if d.CardExp != "" {
+			if card, ok := checkCard(rec, d.CardExp, o); ok {
+				res.Cards = append(res.Cards, card)
+				issues[d.ID] = struct{}{}
+			}
+		}
+
+		if d.Pass == "" {
+			continue
+		}
+
+		res.Passwords++
+		byPass[d.Pass] = append(byPass[d.Pass], rec)
+
+		if s := Estimate(d.Pass, d.Login, d.Name); s.Score < WeakScore {
+			res.Weak = append(res.Weak, WeakPassword{Record: rec, Strength: s})
+			issues[d.ID] = struct{}{}
+		}
+
+		// версия записи - время ее последнего изменения на сервере
+		changedAt := time.Unix(int64(d.Version), 0)
+		if age := o.Now.Sub(changedAt); o.MaxPasswordAge > 0 && d.Version != 0 && age > o.MaxPasswordAge {
+			res.Old = append(res.Old, OldPassword{Record: rec, ChangedAt: changedAt, AgeDays: int(age.Hours() / 24)})
+			issues[d.ID] = struct{}{}
+		}
+	}
+
+	for pass, recs := range byPass {
+		if o.Breach != nil {
+			count, err := o.Breach.Count(pass)
+			if err != nil {
+				return Report{}, err
+			}
+
+			for _, r := range recs {
+				if count == 0 {
+					break
+				}
+
+				res.Breached = append(res.Breached, BreachedPassword{Record: r, Count: count})
+				issues[r.ID] = struct{}{}
+			}
+		}
+
+		if len(recs) < 2 {
+			continue
+		}
+
+		sort.Slice(recs, func(i, j int) bool { return recs[i].ID < recs[j].ID })
+		res.Reused = append(res.Reused, ReusedPassword{Records: recs})
+
+		for _, r := range recs {
+			issues[r.ID] = struct{}{}
+		}
+	}
+
+	// порядок групп не должен зависеть от обхода map
+	sort.Slice(res.Breached, func(i, j int) bool { return res.Breached[i].ID < res.Breached[j].ID })
+	sort.Slice(res.Reused, func(i, j int) bool { return res.Reused[i].Records[0].ID < res.Reused[j].Records[0].ID })
+	sort.Slice(res.Old, func(i, j int) bool { return res.Old[i].ChangedAt.Before(res.Old[j].ChangedAt) })
+	sort.Slice(res.Cards, func(i, j int) bool { return res.Cards[i].ExpiresAt.Before(res.Cards[j].ExpiresAt) })
+
+	res.Issues = len(issues)
+
+	return res, nil
+}
+
+// checkCard проверка срока действия карты, карта действует до конца указанного месяца
+func checkCard(rec Record, expiry string, o Options) (ExpiringCard, bool) {
+	card := ExpiringCard{Record: rec, Expiry: expiry}
+
+	expiresAt, ok := ParseCardExpiry(expiry, o.Now.Location())
+	if !ok {
+		card.Invalid = true
+		return card, true
+	}
+
+	card.ExpiresAt = expiresAt
+	card.Expired = !o.Now.Before(expiresAt)
+
+	return card, expiresAt.Sub(o.Now) < o.CardExpiryWarn
+}
+
+// ParseCardExpiry момент окончания срока действия карты (начало следующего за указанным месяца)
+func ParseCardExpiry(expiry string, loc *time.Location) (time.Time, bool) {
+	parts := cardExpPattern.FindStringSubmatch(expiry)
+	if parts == nil {
+		return time.Time{}, false
+	}
+
+	month, _ := strconv.Atoi(parts[1])
+	year, _ := strconv.Atoi(parts[2])
+
+	if month < 1 || month > 12 {
+		return time.Time{}, false
+	}
+
+	if year < 100 {
+		year += 2000
+	}
+
+	return time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, loc), true
+}
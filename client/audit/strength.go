@@ -0,0 +1,513 @@
+package audit
+
+import (
+	"bufio"
+	_ "embed"
+	"gophkeeper/client/generator"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Оценка стойкости пароля по схеме zxcvbn: пароль разбивается на фрагменты, для каждого фрагмента
+// оценивается число попыток подбора (словарь, последовательность, повтор, клавиатурный шаблон, год или перебор)
+// и выбирается разбиение с наименьшим общим числом попыток. Все вычисления выполняются в log10
+
+// maxAnalyzedLength длиннее этого пароль разбирается только в начале, остаток считается перебором
+const maxAnalyzedLength = 64
+
+// Пороги шкалы оценки (log10 числа попыток), как в zxcvbn
+var scoreThresholds = []float64{3, 6, 8, 10}
+
+// Предупреждения о найденных слабостях пароля
+const (
+	WarnShort     = "too short"
+	WarnCommon    = "common password"
+	WarnWord      = "dictionary word"
+	WarnUserInput = "contains login or record name"
+	WarnSequence  = "sequence like abc or 123"
+	WarnRepeat    = "repeated characters"
+	WarnKeyboard  = "keyboard pattern"
+	WarnYear      = "year"
+)
+
+// Strength оценка стойкости пароля
+// Score от 0 (угадывается мгновенно) до 4 (очень стойкий), Guesses - десятичный логарифм числа попыток подбора
+type Strength struct {
+	Score   int     `json:"score"`
+	Guesses float64 `json:"guesses_log10"`
+	Warning string  `json:"warning,omitempty"`
+}
+
+// match фрагмент пароля [i, j) и log10 числа попыток для его подбора
+type match struct {
+	i, j    int
+	guesses float64
+	warning string
+}
+
+//go:embed common_passwords.txt
+var commonPasswordsList string
+
+// commonPasswords популярные пароли с их местом в списке
+var commonPasswords = sync.OnceValue(func() map[string]int {
+	res := make(map[string]int)
+
+	scanner := bufio.NewScanner(strings.NewReader(commonPasswordsList))
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			if _, ok := res[word]; !ok {
+				res[word] = len(res) + 1
+			}
+		}
+	}
+
+	return res
+})
+
+// dictionaryWords слова словаря парольных фраз
+var dictionaryWords = sync.OnceValue(func() map[string]struct{} {
+	words := generator.Words()
+	res := make(map[string]struct{}, len(words))
+
+	for _, w := range words {
+		res[w] = struct{}{}
+	}
+
+	return res
+})
+
+// keyboardRows ряды и столбцы клавиатуры QWERTY
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+	"1qaz", "2wsx", "3edc", "4rfv", "5tgb", "6yhn", "7ujm", "8ik,", "9ol.", "0p;/",
+}
+
+// leet замены символов, которыми маскируют буквы в словарных словах
+var leet = map[rune]rune{
+	'4': 'a', '@': 'a', '3': 'e', '1': 'i', '!': 'i', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't',
+}
+
+// Estimate оценка стойкости пароля
+// userInputs - связанные с записью строки (логин, название), пароль на их основе считается слабым
+func Estimate(pass string, userInputs ...string) Strength {
+	runes := []rune(pass)
+	if len(runes) == 0 {
+		return Strength{Warning: WarnShort}
+	}
+
+	var tail float64
+	if len(runes) > maxAnalyzedLength {
+		tail = bruteforce(runes[maxAnalyzedLength:])
+		runes = runes[:maxAnalyzedLength]
+	}
+
+	guesses, path := minGuesses(runes, findMatches(runes, userInputs))
+	guesses += tail
+
+	res := Strength{Guesses: guesses, Score: len(scoreThresholds)}
+	for i, threshold := range scoreThresholds {
+		if guesses < threshold {
+			res.Score = i
+			break
+		}
+	}
+
+	if res.Score < 3 {
+		res.Warning = warning(path, len(runes))
+	}
+
+	return res
+}
+
+// minGuesses разбиение пароля на фрагменты с минимальным числом попыток
+// к произведению попыток по фрагментам добавляется k! за порядок k фрагментов
+func minGuesses(runes []rune, matches []match) (float64, []match) {
+	n := len(runes)
+
+	ending := make([][]match, n+1)
+	for _, m := range matches {
+		ending[m.j] = append(ending[m.j], m)
+	}
+
+	for j := 1; j <= n; j++ {
+		for i := 0; i < j; i++ {
+			ending[j] = append(ending[j], match{i: i, j: j, guesses: bruteforce(runes[i:j])})
+		}
+	}
+
+	// best[k][j] минимум для первых j символов, разбитых на k фрагментов
+	inf := math.Inf(1)
+	best := make([][]float64, n+1)
+	prev := make([][]match, n+1)
+
+	for k := range best {
+		best[k] = make([]float64, n+1)
+		prev[k] = make([]match, n+1)
+
+		for j := range best[k] {
+			best[k][j] = inf
+		}
+	}
+
+	best[0][0] = 0
+
+	for j := 1; j <= n; j++ {
+		for _, m := range ending[j] {
+			for k := 1; k <= j; k++ {
+				if best[k-1][m.i] == inf {
+					continue
+				}
+
+				if g := best[k-1][m.i] + m.guesses; g < best[k][j] {
+					best[k][j] = g
+					prev[k][j] = m
+				}
+			}
+		}
+	}
+
+	res, parts := inf, 0
+	for k := 1; k <= n; k++ {
+		if g := best[k][n] + logFactorial(k); g < res {
+			res, parts = g, k
+		}
+	}
+
+	path := make([]match, 0, parts)
+	for j, k := n, parts; k > 0; k-- {
+		m := prev[k][j]
+		path = append(path, m)
+		j = m.i
+	}
+
+	return res, path
+}
+
+// findMatches поиск всех известных шаблонов в пароле
+func findMatches(runes []rune, userInputs []string) []match {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var res []match
+	res = append(res, dictionaryMatches(runes, lower, userInputs)...)
+	res = append(res, sequenceMatches(runes)...)
+	res = append(res, repeatMatches(runes)...)
+	res = append(res, keyboardMatches(lower)...)
+	res = append(res, yearMatches(runes)...)
+
+	return res
+}
+
+func dictionaryMatches(runes, lower []rune, userInputs []string) []match {
+	inputs := make(map[string]int)
+	for _, input := range userInputs {
+		fields := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		for _, f := range append(fields, strings.ToLower(input)) {
+			if _, ok := inputs[f]; !ok && len([]rune(f)) >= 3 {
+				inputs[f] = len(inputs) + 1
+			}
+		}
+	}
+
+	common := commonPasswords()
+	words := dictionaryWords()
+
+	var res []match
+
+	for i := range lower {
+		for j := i + 3; j <= len(lower); j++ {
+			variants := []struct {
+				word       string
+				multiplier float64
+			}{
+				{string(lower[i:j]), 0},
+				{reverse(lower[i:j]), math.Log10(2)},
+			}
+
+			if word, subs := unleet(lower[i:j]); subs > 0 {
+				variants = append(variants, struct {
+					word       string
+					multiplier float64
+				}{word, float64(subs) * math.Log10(2)})
+			}
+
+			for _, v := range variants {
+				rank, warn := 0, ""
+
+				if r, ok := inputs[v.word]; ok {
+					rank, warn = r, WarnUserInput
+				} else if r, ok := common[v.word]; ok {
+					rank, warn = r, WarnCommon
+				} else if _, ok := words[v.word]; ok {
+					rank, warn = len(words), WarnWord
+				}
+
+				if rank == 0 {
+					continue
+				}
+
+				res = append(res, match{
+					i:       i,
+					j:       j,
+					guesses: math.Max(math.Log10(float64(rank))+v.multiplier+uppercaseVariations(runes[i:j]), 1),
+					warning: warn,
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+// sequenceMatches последовательности вида abc, 987, XYZ
+func sequenceMatches(runes []rune) []match {
+	var res []match
+
+	for i := 0; i < len(runes)-2; {
+		delta := runes[i+1] - runes[i]
+		if (delta != 1 && delta != -1) || charClass(runes[i]) != charClass(runes[i+1]) {
+			i++
+			continue
+		}
+
+		j := i + 2
+		for j < len(runes) && runes[j]-runes[j-1] == delta && charClass(runes[j]) == charClass(runes[i]) {
+			j++
+		}
+
+		if j-i >= 3 {
+			base := 26.0
+			switch {
+			case strings.ContainsRune("aAzZ019", runes[i]):
+				base = 4
+			case unicode.IsDigit(runes[i]):
+				base = 10
+			}
+
+			guesses := math.Log10(base * float64(j-i))
+			if delta < 0 {
+				guesses += math.Log10(2)
+			}
+
+			res = append(res, match{i: i, j: j, guesses: guesses, warning: WarnSequence})
+		}
+
+		i = j - 1
+	}
+
+	return res
+}
+
+// repeatMatches повторы символа или блока символов: aaaa, abcabc
+func repeatMatches(runes []rune) []match {
+	var res []match
+
+	for i := range runes {
+		for size := 1; size <= (len(runes)-i)/2; size++ {
+			count := 1
+			for i+(count+1)*size <= len(runes) && equalRunes(runes[i:i+size], runes[i+count*size:i+(count+1)*size]) {
+				count++
+			}
+
+			if count < 2 || count*size < 3 {
+				continue
+			}
+
+			block := Estimate(string(runes[i : i+size])).Guesses
+			res = append(res, match{
+				i:       i,
+				j:       i + count*size,
+				guesses: block + math.Log10(float64(count)),
+				warning: WarnRepeat,
+			})
+
+			break
+		}
+	}
+
+	return res
+}
+
+// keyboardMatches соседние клавиши в ряду или столбце клавиатуры
+func keyboardMatches(lower []rune) []match {
+	var res []match
+
+	for i := range lower {
+		for j := len(lower); j >= i+4; j-- {
+			sub := string(lower[i:j])
+			rev := reverse(lower[i:j])
+
+			found := false
+			for _, row := range keyboardRows {
+				if strings.Contains(row, sub) || strings.Contains(row, rev) {
+					found = true
+					break
+				}
+			}
+
+			if found {
+				// стартовая клавиша и направление, как в оценке zxcvbn для одного поворота
+				res = append(res, match{i: i, j: j, guesses: math.Log10(float64(j-i-1) * 216), warning: WarnKeyboard})
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// yearMatches годы 1900-2099
+func yearMatches(runes []rune) []match {
+	var res []match
+
+	for i := 0; i+4 <= len(runes); i++ {
+		s := string(runes[i : i+4])
+		if !strings.HasPrefix(s, "19") && !strings.HasPrefix(s, "20") {
+			continue
+		}
+
+		year, err := strconv.Atoi(s)
+		if err != nil {
+			continue
+		}
+
+		space := math.Max(math.Abs(float64(year-time.Now().Year())), 20)
+		res = append(res, match{i: i, j: i + 4, guesses: math.Log10(space), warning: WarnYear})
+	}
+
+	return res
+}
+
+// bruteforce число попыток полного перебора по алфавиту встречающихся классов символов
+func bruteforce(runes []rune) float64 {
+	var classes [5]bool
+
+	for _, r := range runes {
+		classes[charClass(r)] = true
+	}
+
+	var cardinality float64
+	for class, sizes := range []float64{26, 26, 10, 33, 100} {
+		if classes[class] {
+			cardinality += sizes
+		}
+	}
+
+	return float64(len(runes)) * math.Log10(cardinality)
+}
+
+// charClass класс символа: строчная, заглавная, цифра, символ ASCII, прочие
+func charClass(r rune) int {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return 0
+	case r >= 'A' && r <= 'Z':
+		return 1
+	case r >= '0' && r <= '9':
+		return 2
+	case r < unicode.MaxASCII:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// uppercaseVariations log10 числа вариантов регистра слова
+func uppercaseVariations(runes []rune) float64 {
+	var upper, lower int
+
+	for _, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 0
+	case lower == 0, upper == 1 && (unicode.IsUpper(runes[0]) || unicode.IsUpper(runes[len(runes)-1])):
+		return math.Log10(2)
+	}
+
+	var variations float64
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+
+	return math.Log10(variations)
+}
+
+func unleet(runes []rune) (string, int) {
+	res := make([]rune, len(runes))
+	subs := 0
+
+	for i, r := range runes {
+		if l, ok := leet[r]; ok {
+			res[i] = l
+			subs++
+		} else {
+			res[i] = r
+		}
+	}
+
+	return string(res), subs
+}
+
+func warning(path []match, length int) string {
+	var res match
+
+	for _, m := range path {
+		if m.warning != "" && m.j-m.i > res.j-res.i {
+			res = m
+		}
+	}
+
+	if res.warning == "" && length < 8 {
+		return WarnShort
+	}
+
+	return res.warning
+}
+
+func reverse(runes []rune) string {
+	res := make([]rune, len(runes))
+	for i, r := range runes {
+		res[len(runes)-1-i] = r
+	}
+
+	return string(res)
+}
+
+func equalRunes(a, b []rune) bool {
+	return string(a) == string(b)
+}
+
+func binomial(n, k int) float64 {
+	res := 1.0
+	for i := 1; i <= k; i++ {
+		res = res * float64(n-k+i) / float64(i)
+	}
+
+	return res
+}
+
+func logFactorial(k int) float64 {
+	var res float64
+	for i := 2; i <= k; i++ {
+		res += math.Log10(float64(i))
+	}
+
+	return res
+}
@@ -0,0 +1,439 @@
+// Package backup формат архива полной выгрузки хранилища
+// архив - zip с открытым манифестом manifest.json и зашифрованными элементами:
+// records.json со всеми записями и папками и files/<n> с содержимым файлов записей.
+// элементы шифруются AES-256-GCM ключом, полученным из пароля выгрузки через Argon2id,
+// параметры KDF и SHA-256 зашифрованных элементов хранятся в манифесте
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/importer"
+	"io"
+	"path"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Format идентификатор формата в манифесте
+const Format = "gophkeeper-backup"
+
+// Version версия формата, архивы более новой версии не читаются
+const Version = 1
+
+// Алгоритмы, указываемые в манифесте
+const (
+	KDFArgon2id     = "argon2id"
+	CipherAES256GCM = "aes-256-gcm"
+)
+
+// Имена элементов архива
+const (
+	ManifestName = "manifest.json"
+	RecordsName  = "records.json"
+	filesDir     = "files"
+)
+
+// DefaultKDF параметры Argon2id для новых архивов (рекомендация RFC 9106 для ограниченной памяти)
+var DefaultKDF = KDF{Name: KDFArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// ограничения параметров KDF при чтении, чтобы поврежденный манифест не занял всю память
+const (
+	maxKDFMemory  = 4 * 1024 * 1024
+	maxKDFTime    = 100
+	saltSize      = 16
+	minPassphrase = 8
+)
+
+var (
+	ErrFormat     = errors.New("not a gophkeeper backup")
+	ErrVersion    = errors.New("unsupported backup version")
+	ErrCorrupted  = errors.New("backup is corrupted")
+	ErrPassphrase = errors.New("wrong backup passphrase")
+	ErrWeakPass   = fmt.Errorf("backup passphrase must be at least %d characters", minPassphrase)
+)
+
+// KDF параметры получения ключа из пароля, Memory в KiB
+type KDF struct {
+	Name    string `json:"name"`
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// Entry элемент архива в манифесте, Size и SHA256 - зашифрованного содержимого
+type Entry struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest открытая часть архива
+type Manifest struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	KDF     KDF       `json:"kdf"`
+	Cipher  string    `json:"cipher"`
+	Entries []Entry   `json:"entries"`
+}
+
+// File файл записи
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Record запись хранилища
+// Folder - путь папки, Version - версия записи на сервере на момент выгрузки (время изменения в unix секундах)
+type Record struct {
+	Name    string   `json:"name"`
+	Login   string   `json:"login,omitempty"`
+	Pass    string   `json:"pass,omitempty"`
+	CardNum string   `json:"card,omitempty"`
+	CardExp string   `json:"exp,omitempty"`
+	Otp     string   `json:"otp,omitempty"`
+	Text    string   `json:"text,omitempty"`
+	Meta    string   `json:"meta,omitempty"`
+	Folder  string   `json:"folder,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Version uint64   `json:"version"`
+	File    *File    `json:"-"`
+}
+
+// Backup содержимое архива
+// Folders - пути всех папок, включая пустые
+type Backup struct {
+	Created time.Time
+	Folders []string
+	Records []Record
+}
+
+// fileRef ссылка записи на зашифрованный файл, SHA256 - открытого содержимого
+type fileRef struct {
+	Name   string `json:"name"`
+	Entry  string `json:"entry"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type recordJSON struct {
+	Record
+	File *fileRef `json:"file,omitempty"`
+}
+
+type recordsJSON struct {
+	Folders []string     `json:"folders"`
+	Records []recordJSON `json:"records"`
+}
+
+// Write запись архива, параметры KDF берутся из kdf, соль генерируется
+func Write(w io.Writer, b Backup, passphrase string, kdf KDF) error {
+	if len([]rune(passphrase)) < minPassphrase {
+		return ErrWeakPass
+	}
+
+	kdf.Name, kdf.Salt = KDFArgon2id, make([]byte, saltSize)
+	if _, err := rand.Read(kdf.Salt); err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(passphrase, kdf)
+	if err != nil {
+		return err
+	}
+
+	m := Manifest{Format: Format, Version: Version, Created: b.Created.UTC(), KDF: kdf, Cipher: CipherAES256GCM}
+	payload := recordsJSON{Folders: b.Folders, Records: make([]recordJSON, 0, len(b.Records))}
+	entries := make(map[string][]byte)
+
+	for i, r := range b.Records {
+		rec := recordJSON{Record: r}
+
+		if r.File != nil {
+			sum := sha256.Sum256(r.File.Content)
+			rec.File = &fileRef{
+				Name:   r.File.Name,
+				Entry:  path.Join(filesDir, strconv.Itoa(i+1)),
+				Size:   len(r.File.Content),
+				SHA256: hex.EncodeToString(sum[:]),
+			}
+
+			if entries[rec.File.Entry], err = seal(aead, rec.File.Entry, r.File.Content); err != nil {
+				return err
+			}
+		}
+
+		payload.Records = append(payload.Records, rec)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if entries[RecordsName], err = seal(aead, RecordsName, raw); err != nil {
+		return err
+	}
+
+	// records.json первым, затем файлы в порядке записей
+	names := []string{RecordsName}
+	for _, r := range payload.Records {
+		if r.File != nil {
+			names = append(names, r.File.Entry)
+		}
+	}
+
+	for _, name := range names {
+		sum := sha256.Sum256(entries[name])
+		m.Entries = append(m.Entries, Entry{Name: name, Size: int64(len(entries[name])), SHA256: hex.EncodeToString(sum[:])})
+	}
+
+	zw := zip.NewWriter(w)
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = writeEntry(zw, ManifestName, manifest, zip.Deflate); err != nil {
+		return err
+	}
+
+	// зашифрованные данные не сжимаются
+	for _, name := range names {
+		if err = writeEntry(zw, name, entries[name], zip.Store); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// ReadManifest чтение манифеста и проверка контрольных сумм всех элементов, пароль не нужен
+func ReadManifest(r io.ReaderAt, size int64) (*Manifest, error) {
+	m, _, err := open(r, size)
+
+	return m, err
+}
+
+// Read чтение и расшифровка архива
+func Read(r io.ReaderAt, size int64, passphrase string) (*Backup, error) {
+	m, entries, err := open(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, m.KDF)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := unseal(aead, RecordsName, entries[RecordsName])
+	if err != nil {
+		// контрольная сумма уже проверена, поэтому ошибка расшифровки означает неверный пароль
+		return nil, ErrPassphrase
+	}
+
+	var payload recordsJSON
+	if err = json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorrupted, err.Error())
+	}
+
+	b := &Backup{Created: m.Created, Folders: payload.Folders, Records: make([]Record, 0, len(payload.Records))}
+
+	for _, rec := range payload.Records {
+		r := rec.Record
+
+		if ref := rec.File; ref != nil {
+			content, err := unseal(aead, ref.Entry, entries[ref.Entry])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrCorrupted, ref.Entry)
+			}
+
+			sum := sha256.Sum256(content)
+			if len(content) != ref.Size || hex.EncodeToString(sum[:]) != ref.SHA256 {
+				return nil, fmt.Errorf("%w: %s checksum mismatch", ErrCorrupted, ref.Entry)
+			}
+
+			r.File = &File{Name: ref.Name, Content: content}
+		}
+
+		b.Records = append(b.Records, r)
+	}
+
+	return b, nil
+}
+
+// Items записи архива для сохранения через план импорта client/data
+// без потерь переносится содержимое: поля, теги, папка и файл записи; версия не переносится - сервер хранит
+// только текущую версию записи и при сохранении назначает новую, поэтому Version в архиве только справочная
+func (b Backup) Items() []importer.Item {
+	res := make([]importer.Item, 0, len(b.Records))
+
+	for _, r := range b.Records {
+		it := importer.Item{
+			Data: domain.Data{
+				Name:    r.Name,
+				Login:   r.Login,
+				Pass:    r.Pass,
+				CardNum: r.CardNum,
+				CardExp: r.CardExp,
+				Otp:     r.Otp,
+				Text:    r.Text,
+				Meta:    r.Meta,
+				Tags:    r.Tags,
+			},
+			Folder: r.Folder,
+		}
+
+		if r.File != nil {
+			it.Attachment = &importer.Attachment{Name: r.File.Name, Content: r.File.Content}
+		}
+
+		res = append(res, it)
+	}
+
+	return res
+}
+
+// open разбор манифеста и чтение элементов с проверкой размера и контрольной суммы
+func open(r io.ReaderAt, size int64) (*Manifest, map[string][]byte, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, ErrFormat
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	mf, ok := files[ManifestName]
+	if !ok {
+		return nil, nil, ErrFormat
+	}
+
+	raw, err := readEntry(mf, 1<<20)
+	if err != nil {
+		return nil, nil, ErrFormat
+	}
+
+	var m Manifest
+	if err = json.Unmarshal(raw, &m); err != nil || m.Format != Format {
+		return nil, nil, ErrFormat
+	}
+
+	if m.Version > Version || m.Version < 1 {
+		return nil, nil, fmt.Errorf("%w %d, supported %d", ErrVersion, m.Version, Version)
+	}
+
+	if m.Cipher != CipherAES256GCM || m.KDF.Name != KDFArgon2id {
+		return nil, nil, fmt.Errorf("%w: cipher %q, kdf %q", ErrVersion, m.Cipher, m.KDF.Name)
+	}
+
+	if m.KDF.Memory > maxKDFMemory || m.KDF.Time > maxKDFTime || m.KDF.Time == 0 || m.KDF.Threads == 0 || len(m.KDF.Salt) < saltSize {
+		return nil, nil, fmt.Errorf("%w: bad kdf parameters", ErrCorrupted)
+	}
+
+	entries := make(map[string][]byte, len(m.Entries))
+
+	for _, e := range m.Entries {
+		f, ok := files[e.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s is missing", ErrCorrupted, e.Name)
+		}
+
+		content, err := readEntry(f, e.Size)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrCorrupted, e.Name)
+		}
+
+		sum := sha256.Sum256(content)
+		if int64(len(content)) != e.Size || hex.EncodeToString(sum[:]) != e.SHA256 {
+			return nil, nil, fmt.Errorf("%w: %s checksum mismatch", ErrCorrupted, e.Name)
+		}
+
+		entries[e.Name] = content
+	}
+
+	if _, ok = entries[RecordsName]; !ok {
+		return nil, nil, fmt.Errorf("%w: %s is missing", ErrCorrupted, RecordsName)
+	}
+
+	return &m, entries, nil
+}
+
+func newAEAD(passphrase string, kdf KDF) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), kdf.Salt, kdf.Time, kdf.Memory, kdf.Threads, 32)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal шифрование элемента, имя элемента - дополнительные данные, чтобы элементы нельзя было подменить друг другом
+func seal(aead cipher.AEAD, name string, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, []byte(name)), nil
+}
+
+func unseal(aead cipher.AEAD, name string, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrCorrupted
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	return aead.Open(make([]byte, 0, len(ciphertext)), nonce, ciphertext, []byte(name))
+}
+
+func writeEntry(zw *zip.Writer, name string, content []byte, method uint16) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, bytes.NewReader(content))
+
+	return err
+}
+
+// readEntry чтение элемента не больше limit байт
+func readEntry(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(raw)) > limit {
+		return nil, ErrCorrupted
+	}
+
+	return raw, nil
+}
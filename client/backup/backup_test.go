@@ -0,0 +1,248 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKDF = KDF{Time: 1, Memory: 64, Threads: 1}
+
+func testBackup() Backup {
+	return Backup{
+		Created: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Folders: []string{"Work", "Work/Servers", "Empty"},
+		Records: []Record{
+			{Name: "db", Login: "admin", Pass: "p@ss", Folder: "Work/Servers", Tags: []string{"prod"}, Version: 1780000000},
+			{Name: "card", CardNum: "4111111111111111", CardExp: "08/27", Version: 1780000001},
+			{Name: "keys", Text: "note", Otp: "JBSWY3DPEHPK3PXP", Meta: "m", Version: 1780000002, File: &File{Name: "id_rsa", Content: []byte("private key")}},
+			{Name: "empty file", Version: 1780000003, File: &File{Name: "empty.txt", Content: []byte{}}},
+		},
+	}
+}
+
+func write(t *testing.T, b Backup, passphrase string) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, b, passphrase, testKDF))
+
+	return buf.Bytes()
+}
+
+func TestWriteRead(t *testing.T) {
+	b := testBackup()
+	raw := write(t, b, "correct horse")
+
+	got, err := Read(bytes.NewReader(raw), int64(len(raw)), "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, b, *got)
+
+	m, err := ReadManifest(bytes.NewReader(raw), int64(len(raw)))
+	require.NoError(t, err)
+	assert.Equal(t, Format, m.Format)
+	assert.Equal(t, Version, m.Version)
+	assert.Equal(t, KDFArgon2id, m.KDF.Name)
+	assert.Len(t, m.KDF.Salt, saltSize)
+	assert.Equal(t, []string{RecordsName, "files/3", "files/4"}, entryNames(m.Entries))
+
+	// секреты и названия файлов не попадают в архив открытым текстом
+	for _, secret := range []string{"p@ss", "admin", "id_rsa", "private key", "Servers"} {
+		assert.NotContains(t, string(raw), secret)
+	}
+
+	_, err = Read(bytes.NewReader(raw), int64(len(raw)), "wrong horse")
+	assert.ErrorIs(t, err, ErrPassphrase)
+}
+
+func TestWrite_WeakPassphrase(t *testing.T) {
+	assert.ErrorIs(t, Write(io.Discard, testBackup(), "short", testKDF), ErrWeakPass)
+}
+
+func TestRead_Damaged(t *testing.T) {
+	const pass = "correct horse"
+	raw := write(t, testBackup(), pass)
+
+	tests := []struct {
+		name    string
+		archive func() []byte
+		wantErr error
+	}{
+		{
+			name:    "not zip",
+			archive: func() []byte { return []byte("plain text") },
+			wantErr: ErrFormat,
+		},
+		{
+			name: "other zip",
+			archive: func() []byte {
+				return rewrite(t, raw, func(name string, content []byte) (string, []byte) {
+					if name == ManifestName {
+						return "readme.txt", content
+					}
+
+					return name, content
+				})
+			},
+			wantErr: ErrFormat,
+		},
+		{
+			name: "newer version",
+			archive: func() []byte {
+				return rewriteManifest(t, raw, func(m *Manifest) { m.Version = Version + 1 })
+			},
+			wantErr: ErrVersion,
+		},
+		{
+			name: "huge kdf memory",
+			archive: func() []byte {
+				return rewriteManifest(t, raw, func(m *Manifest) { m.KDF.Memory = maxKDFMemory + 1 })
+			},
+			wantErr: ErrCorrupted,
+		},
+		{
+			name: "flipped byte",
+			archive: func() []byte {
+				return rewrite(t, raw, func(name string, content []byte) (string, []byte) {
+					if name == "files/3" {
+						content = bytes.Clone(content)
+						content[len(content)-1] ^= 1
+					}
+
+					return name, content
+				})
+			},
+			wantErr: ErrCorrupted,
+		},
+		{
+			name: "missing file",
+			archive: func() []byte {
+				return rewrite(t, raw, func(name string, content []byte) (string, []byte) {
+					if name == "files/4" {
+						return "", nil
+					}
+
+					return name, content
+				})
+			},
+			wantErr: ErrCorrupted,
+		},
+		{
+			// контрольные суммы в манифесте пересчитаны, подмену выявляет шифрование с именем элемента
+			name: "swapped files",
+			archive: func() []byte {
+				var a, b []byte
+				rewrite(t, raw, func(name string, content []byte) (string, []byte) {
+					switch name {
+					case "files/3":
+						a = content
+					case "files/4":
+						b = content
+					}
+
+					return name, content
+				})
+
+				swapped := rewrite(t, raw, func(name string, content []byte) (string, []byte) {
+					switch name {
+					case "files/3":
+						return name, b
+					case "files/4":
+						return name, a
+					}
+
+					return name, content
+				})
+
+				return rewriteManifest(t, swapped, func(m *Manifest) {
+					m.Entries[1].Size, m.Entries[2].Size = m.Entries[2].Size, m.Entries[1].Size
+					m.Entries[1].SHA256, m.Entries[2].SHA256 = m.Entries[2].SHA256, m.Entries[1].SHA256
+				})
+			},
+			wantErr: ErrCorrupted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := tt.archive()
+
+			_, err := Read(bytes.NewReader(archive), int64(len(archive)), pass)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBackup_Items(t *testing.T) {
+	items := testBackup().Items()
+	require.Len(t, items, 4)
+
+	assert.Equal(t, "db", items[0].Data.Name)
+	assert.Equal(t, "Work/Servers", items[0].Folder)
+	assert.Zero(t, items[0].Data.Version)
+	assert.Nil(t, items[0].Attachment)
+
+	require.NotNil(t, items[2].Attachment)
+	assert.Equal(t, "id_rsa", items[2].Attachment.Name)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", items[2].Data.Otp)
+}
+
+func entryNames(entries []Entry) []string {
+	res := make([]string, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e.Name)
+	}
+
+	return res
+}
+
+// rewrite копия архива с измененными элементами, пустое имя удаляет элемент
+func rewrite(t *testing.T, raw []byte, change func(name string, content []byte) (string, []byte)) []byte {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+
+		name, content := change(f.Name, content)
+		if name == "" {
+			continue
+		}
+
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func rewriteManifest(t *testing.T, raw []byte, change func(m *Manifest)) []byte {
+	return rewrite(t, raw, func(name string, content []byte) (string, []byte) {
+		if name != ManifestName {
+			return name, content
+		}
+
+		var m Manifest
+		require.NoError(t, json.Unmarshal(content, &m))
+		change(&m)
+
+		content, err := json.Marshal(m)
+		require.NoError(t, err)
+
+		return name, content
+	})
+}
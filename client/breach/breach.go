@@ -0,0 +1,267 @@
+// Package breach проверка паролей по базе утечек в формате Pwned Passwords
+// используется k-анонимность: источнику передаются только первые 5 символов SHA-1 пароля,
+// сравнение с остальной частью хеша выполняется на клиенте
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrefixLength длина префикса хеша, который передается источнику
+const PrefixLength = 5
+
+// hashLength длина SHA-1 в шестнадцатеричном виде
+const hashLength = 40
+
+// requestTimeout время ожидания ответа HTTP источника
+const requestTimeout = 10 * time.Second
+
+var (
+	ErrBadPrefix   = errors.New("bad hash prefix")
+	ErrBadResponse = errors.New("bad range response")
+)
+
+// Source источник диапазонов хешей
+// Range возвращает окончания хешей (без префикса, в верхнем регистре), начинающихся с prefix, и сколько раз они встречались в утечках
+type Source interface {
+	Range(prefix string) (map[string]int, error)
+}
+
+// Open источник по адресу: http(s) URL сервиса с API диапазонов или путь к отсортированному файлу хешей
+func Open(location string) (Source, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return HTTPSource{URL: location, Client: &http.Client{Timeout: requestTimeout}}, nil
+	}
+
+	if _, err := os.Stat(location); err != nil {
+		return nil, err
+	}
+
+	return FileSource{Path: location}, nil
+}
+
+// Checker проверка паролей с кешированием полученных диапазонов
+type Checker struct {
+	src    Source
+	ranges map[string]map[string]int
+}
+
+func NewChecker(src Source) *Checker {
+	return &Checker{
+		src:    src,
+		ranges: make(map[string]map[string]int),
+	}
+}
+
+// Count сколько раз пароль встречался в утечках, 0 - пароль не найден
+func (c *Checker) Count(pass string) (int, error) {
+	sum := sha1.Sum([]byte(pass))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:PrefixLength], hash[PrefixLength:]
+
+	suffixes, ok := c.ranges[prefix]
+	if !ok {
+		var err error
+		if suffixes, err = c.src.Range(prefix); err != nil {
+			return 0, err
+		}
+
+		c.ranges[prefix] = suffixes
+	}
+
+	return suffixes[suffix], nil
+}
+
+// HTTPSource сервис с API диапазонов Pwned Passwords: GET <URL>/range/<prefix>
+// ответ - строки вида SUFFIX:COUNT, строки с COUNT = 0 (дополнение ответа) пропускаются
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s HTTPSource) Range(prefix string) (map[string]int, error) {
+	if !validPrefix(prefix) {
+		return nil, ErrBadPrefix
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(strings.TrimRight(s.URL, "/") + "/range/" + prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrBadResponse, resp.StatusCode)
+	}
+
+	res := make(map[string]int)
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		suffix, count, ok := parseLine(line)
+		if !ok || len(suffix) != hashLength-PrefixLength {
+			return nil, fmt.Errorf("%w: %q", ErrBadResponse, line)
+		}
+
+		if count > 0 {
+			res[suffix] = count
+		}
+	}
+
+	return res, scanner.Err()
+}
+
+// FileSource отсортированный по хешу файл вида HASH:COUNT (как в выгрузке Pwned Passwords ordered by hash)
+// диапазон находится двоичным поиском, файл целиком не читается
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Range(prefix string) (map[string]int, error) {
+	if !validPrefix(prefix) {
+		return nil, ErrBadPrefix
+	}
+
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// наименьшая позиция, начиная с которой первая строка не меньше префикса
+	lo, hi := int64(0), info.Size()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		line, err := lineAt(f, mid)
+		if err != nil {
+			return nil, err
+		}
+
+		if line != "" && strings.ToUpper(line[:min(len(line), PrefixLength)]) < prefix {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	start, err := lineStart(f, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int)
+
+	reader := bufio.NewReader(io.NewSectionReader(f, start, info.Size()-start))
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+
+		if line != "" {
+			hash, count, ok := parseLine(line)
+			if !ok || len(hash) != hashLength {
+				return nil, fmt.Errorf("%w: %q", ErrBadResponse, line)
+			}
+
+			if !strings.HasPrefix(hash, prefix) {
+				break
+			}
+
+			res[hash[PrefixLength:]] = count
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// lineStart начало первой строки, которая начинается в позиции pos или после нее
+func lineStart(f *os.File, pos int64) (int64, error) {
+	if pos == 0 {
+		return 0, nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(f, pos-1, 1<<62))
+
+	skipped, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+
+	return pos - 1 + int64(len(skipped)), nil
+}
+
+// lineAt первая строка, которая начинается в позиции pos или после нее, пустая - если строк больше нет
+func lineAt(f *os.File, pos int64) (string, error) {
+	start, err := lineStart(f, pos)
+	if err != nil {
+		return "", err
+	}
+
+	line, err := bufio.NewReader(io.NewSectionReader(f, start, 1<<62)).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+func parseLine(line string) (string, int, bool) {
+	hash, rawCount, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", 0, false
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(rawCount))
+	if err != nil {
+		return "", 0, false
+	}
+
+	return strings.ToUpper(hash), count, true
+}
+
+func validPrefix(prefix string) bool {
+	if len(prefix) != PrefixLength {
+		return false
+	}
+
+	for _, r := range prefix {
+		if !strings.ContainsRune("0123456789ABCDEF", r) {
+			return false
+		}
+	}
+
+	return true
+}
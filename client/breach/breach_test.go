@@ -0,0 +1,141 @@
+package breach
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fixture = filepath.Join("testdata", "pwned-passwords-sha1-ordered.txt")
+
+func TestFileSource_Range(t *testing.T) {
+	src := FileSource{Path: fixture}
+
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	got, err := src.Range("5BAA6")
+	require.NoError(t, err)
+	assert.Len(t, got, 4)
+	assert.Equal(t, 9659365, got["1E4C9B93F3F0682250B6CF8331B7EE68FD8"])
+
+	got, err = src.Range("FFFFF")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = src.Range("00000")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = src.Range("5baa6")
+	assert.ErrorIs(t, err, ErrBadPrefix)
+}
+
+func TestFileSource_RangeAll(t *testing.T) {
+	// каждая строка файла должна находиться по своему префиксу
+	src := FileSource{Path: fixture}
+
+	raw, err := readLines(fixture)
+	require.NoError(t, err)
+
+	for _, line := range raw {
+		hash, count, ok := parseLine(line)
+		require.True(t, ok)
+
+		got, err := src.Range(hash[:PrefixLength])
+		require.NoError(t, err)
+		assert.Equal(t, count, got[hash[PrefixLength:]], hash)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+
+	// локальная замена API диапазонов, отвечает по тому же файлу
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+
+		mu.Lock()
+		requested = append(requested, r.URL.String())
+		mu.Unlock()
+
+		suffixes, err := FileSource{Path: fixture}.Range(prefix)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for suffix, count := range suffixes {
+			fmt.Fprintf(w, "%s:%d\r\n", suffix, count)
+		}
+
+		// дополнение ответа, как в Pwned Passwords с Add-Padding
+		fmt.Fprint(w, "0000000000000000000000000000000000A:0\r\n")
+	}))
+	defer server.Close()
+
+	sources := map[string]string{"file": fixture, "http": server.URL}
+	for name, location := range sources {
+		t.Run(name, func(t *testing.T) {
+			src, err := Open(location)
+			require.NoError(t, err)
+
+			c := NewChecker(src)
+
+			count, err := c.Count("password")
+			require.NoError(t, err)
+			assert.Equal(t, 9659365, count)
+
+			count, err = c.Count("Summer2019")
+			require.NoError(t, err)
+			assert.Equal(t, 3842, count)
+
+			count, err = c.Count("xK9#mQ2$vL8!pZ4w")
+			require.NoError(t, err)
+			assert.Zero(t, count)
+
+			// повторная проверка берет диапазон из кеша
+			_, err = c.Count("password")
+			require.NoError(t, err)
+		})
+	}
+
+	// сервису передаются только префиксы хешей
+	assert.Equal(t, []string{"/range/5BAA6", "/range/C28C9", "/range/02F26"}, requested)
+	for _, r := range requested {
+		assert.Len(t, strings.TrimPrefix(r, "/range/"), PrefixLength)
+	}
+
+	_, err := Open(filepath.Join("testdata", "absent.txt"))
+	assert.Error(t, err)
+}
+
+func TestHTTPSource_BadResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "not a range")
+	}))
+	defer server.Close()
+
+	_, err := HTTPSource{URL: server.URL}.Range("5BAA6")
+	assert.ErrorIs(t, err, ErrBadResponse)
+
+	server.Config.Handler = http.NotFoundHandler()
+	_, err = HTTPSource{URL: server.URL}.Range("5BAA6")
+	assert.ErrorIs(t, err, ErrBadResponse)
+}
+
+func readLines(path string) ([]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(raw)), nil
+}
@@ -0,0 +1,181 @@
+// Package clipboard копирование секретов в буфер обмена с автоматической очисткой
+// используется системный буфер обмена, в SSH сессии без графического окружения - escape-последовательность OSC 52,
+// которую передает в буфер обмена локальный терминал
+package clipboard
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// DefaultClearAfter время, через которое буфер обмена очищается по умолчанию
+const DefaultClearAfter = 30 * time.Second
+
+var ErrEmpty = errors.New("nothing to copy")
+
+// Backend буфер обмена
+type Backend interface {
+	Write(text string) error
+}
+
+// Reader буфер обмена, содержимое которого можно прочитать
+// если буфер читать нельзя, перед очисткой не проверяется, изменилось ли его содержимое
+type Reader interface {
+	Read() (string, error)
+}
+
+// System системный буфер обмена (pbcopy, xclip, xsel, wl-copy, clip.exe)
+type System struct{}
+
+func (System) Write(text string) error {
+	return clipboard.WriteAll(text)
+}
+
+func (System) Read() (string, error) {
+	return clipboard.ReadAll()
+}
+
+// OSC52 буфер обмена терминала, последовательность пишется в Out
+// внутри tmux и screen последовательность оборачивается, чтобы дойти до внешнего терминала
+type OSC52 struct {
+	Out io.Writer
+}
+
+func (o OSC52) Write(text string) error {
+	seq := osc52.New(text)
+	if text == "" {
+		seq = osc52.Clear()
+	}
+
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+
+	_, err := seq.WriteTo(o.Out)
+
+	return err
+}
+
+// fallback запись в системный буфер, при ошибке - через OSC 52
+type fallback struct {
+	primary, secondary Backend
+}
+
+func (f fallback) Write(text string) error {
+	if err := f.primary.Write(text); err == nil {
+		return nil
+	}
+
+	return f.secondary.Write(text)
+}
+
+func (f fallback) Read() (string, error) {
+	if r, ok := f.primary.(Reader); ok {
+		return r.Read()
+	}
+
+	return "", errors.ErrUnsupported
+}
+
+// Detect буфер обмена для текущего окружения
+// в SSH сессии без DISPLAY и WAYLAND_DISPLAY системный буфер обмена принадлежит удаленной машине, поэтому сразу используется OSC 52;
+// в остальных случаях OSC 52 используется, если системный буфер недоступен
+func Detect(out io.Writer) Backend {
+	term := OSC52{Out: out}
+
+	remote := os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+	graphical := os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+
+	if clipboard.Unsupported || (remote && !graphical) {
+		return term
+	}
+
+	return fallback{primary: System{}, secondary: term}
+}
+
+// Clipboard буфер обмена, который очищается через заданное время после копирования,
+// если его содержимое с тех пор не изменилось
+// хранится только хеш скопированного значения
+type Clipboard struct {
+	mu         sync.Mutex
+	backend    Backend
+	clearAfter time.Duration
+	timer      *time.Timer
+	copied     [sha256.Size]byte
+	pending    bool
+}
+
+// New clearAfter - время до очистки, 0 - буфер не очищается
+func New(backend Backend, clearAfter time.Duration) *Clipboard {
+	return &Clipboard{backend: backend, clearAfter: clearAfter}
+}
+
+// ClearAfter время до очистки буфера
+func (c *Clipboard) ClearAfter() time.Duration {
+	return c.clearAfter
+}
+
+// Copy копирование значения, ранее запланированная очистка переносится
+func (c *Clipboard) Copy(text string) error {
+	if text == "" {
+		return ErrEmpty
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.backend.Write(text); err != nil {
+		return err
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+
+	c.copied = sha256.Sum256([]byte(text))
+	c.pending = c.clearAfter > 0
+
+	if c.pending {
+		c.timer = time.AfterFunc(c.clearAfter, func() {
+			_ = c.Clear()
+		})
+	}
+
+	return nil
+}
+
+// Clear очистка буфера, если в нем все еще скопированное значение
+func (c *Clipboard) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.pending {
+		return nil
+	}
+
+	c.pending = false
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+
+	if r, ok := c.backend.(Reader); ok {
+		current, err := r.Read()
+		// значение заменено пользователем, его не трогаем
+		if err == nil && sha256.Sum256([]byte(current)) != c.copied {
+			return nil
+		}
+	}
+
+	return c.backend.Write("")
+}
@@ -0,0 +1,122 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memory буфер обмена в памяти
+type memory struct {
+	mu     sync.Mutex
+	value  string
+	writes int
+}
+
+func (m *memory) Write(text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.value = text
+	m.writes++
+
+	return nil
+}
+
+func (m *memory) Read() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.value, nil
+}
+
+func TestClipboard_Copy(t *testing.T) {
+	t.Run("cleared after timeout", func(t *testing.T) {
+		b := &memory{}
+		c := New(b, 20*time.Millisecond)
+
+		require.NoError(t, c.Copy("secret"))
+		value, _ := b.Read()
+		assert.Equal(t, "secret", value)
+
+		assert.Eventually(t, func() bool {
+			value, _ := b.Read()
+			return value == ""
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("changed content is kept", func(t *testing.T) {
+		b := &memory{}
+		c := New(b, time.Hour)
+
+		require.NoError(t, c.Copy("secret"))
+		require.NoError(t, b.Write("copied by user"))
+		require.NoError(t, c.Clear())
+
+		value, _ := b.Read()
+		assert.Equal(t, "copied by user", value)
+	})
+
+	t.Run("new copy restarts timer", func(t *testing.T) {
+		b := &memory{}
+		c := New(b, time.Hour)
+
+		require.NoError(t, c.Copy("first"))
+		require.NoError(t, c.Copy("second"))
+		require.NoError(t, c.Clear())
+
+		value, _ := b.Read()
+		assert.Equal(t, "", value)
+
+		// повторная очистка ничего не пишет
+		writes := b.writes
+		require.NoError(t, c.Clear())
+		assert.Equal(t, writes, b.writes)
+	})
+
+	t.Run("clearing disabled", func(t *testing.T) {
+		b := &memory{}
+		c := New(b, 0)
+
+		require.NoError(t, c.Copy("secret"))
+		require.NoError(t, c.Clear())
+
+		value, _ := b.Read()
+		assert.Equal(t, "secret", value)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.ErrorIs(t, New(&memory{}, time.Second).Copy(""), ErrEmpty)
+	})
+}
+
+func TestOSC52_Write(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	var out bytes.Buffer
+	require.NoError(t, OSC52{Out: &out}.Write("secret"))
+	assert.Equal(t, "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte("secret"))+"\x07", out.String())
+
+	out.Reset()
+	require.NoError(t, OSC52{Out: &out}.Write(""))
+	assert.Equal(t, "\x1b]52;c;!\x07", out.String())
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	out.Reset()
+	require.NoError(t, OSC52{Out: &out}.Write("secret"))
+	assert.Contains(t, out.String(), "\x1bPtmux;")
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("SSH_TTY", "/dev/pts/1")
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	assert.IsType(t, OSC52{}, Detect(&bytes.Buffer{}))
+}
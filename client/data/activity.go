@@ -0,0 +1,27 @@
+package data
+
+import (
+	"gophkeeper/internal/client"
+	domain2 "gophkeeper/server/domain"
+	"strconv"
+)
+
+// GetActivity страница журнала аудита пользователя на сервере: входы, чтение, изменение и скачивание записей
+// журнал личный, поэтому запрашивается без выбранного хранилища организации
+func GetActivity(filter domain2.AuditFilter) ([]domain2.AuditEvent, error) {
+	return client.AppInstance.ActivityClient.List(userContext(), filter)
+}
+
+// ActivityRecordName название записи события для показа пользователю
+// название известно, только если запись расшифрована в текущем хранилище, иначе выводится ее ИД
+func ActivityRecordName(dataID uint64) string {
+	if dataID == 0 {
+		return ""
+	}
+
+	if d, ok := client.AppInstance.DecryptedData[dataID]; ok && d.Name != "" {
+		return d.Name
+	}
+
+	return "#" + strconv.FormatUint(dataID, 10)
+}
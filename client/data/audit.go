@@ -0,0 +1,59 @@
+package data
+
+import (
+	"gophkeeper/client/audit"
+	"gophkeeper/client/breach"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+)
+
+// AuditOptions параметры отчета по умолчанию с проверкой по базе утечек из настроек клиента
+// source переопределяет базу утечек из настроек
+func AuditOptions(source string) (audit.Options, error) {
+	o := audit.DefaultOptions()
+
+	if source == "" {
+		source = client.AppInstance.BreachSource
+	}
+
+	if source == "" {
+		return o, nil
+	}
+
+	src, err := breach.Open(source)
+	if err != nil {
+		return o, err
+	}
+
+	o.Breach = breach.NewChecker(src)
+
+	return o, nil
+}
+
+// AuditVault отчет о состоянии хранилища
+// все записи расшифровываются и проверяются на клиенте, на сервер пароли не передаются,
+// базе утечек передаются только первые символы SHA-1 паролей
+func AuditVault(o audit.Options) (audit.Report, error) {
+	list, err := ListAll(domain.DataListFilter{})
+	if err != nil {
+		return audit.Report{}, err
+	}
+
+	records := make([]domain.Data, 0, len(list))
+
+	for _, d := range list {
+		// в кеше могла остаться устаревшая версия записи
+		if cached, ok := client.AppInstance.DecryptedData[d.ID]; ok && cached.Version != d.Version {
+			delete(client.AppInstance.DecryptedData, d.ID)
+		}
+
+		data, err := GetData(d.ID)
+		if err != nil {
+			return audit.Report{}, err
+		}
+
+		records = append(records, *data)
+	}
+
+	return audit.Build(records, o)
+}
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"errors"
+	"gophkeeper/client/backup"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ExportBackup выгрузка всех записей с файлами и всех папок для архива client/backup
+func ExportBackup() (backup.Backup, error) {
+	b := backup.Backup{Created: time.Now()}
+
+	folders, err := GetFolders()
+	if err != nil {
+		return b, err
+	}
+
+	for _, f := range folders {
+		b.Folders = append(b.Folders, FolderPath(folders, f.ID))
+	}
+
+	list, err := ListAll(domain.DataListFilter{})
+	if err != nil {
+		return b, err
+	}
+
+	for _, d := range list {
+		// чужие записи, к которым предоставлен доступ, в архив не попадают
+		if d.Share != nil {
+			continue
+		}
+
+		// в кеше могла остаться устаревшая версия записи
+		if cached, ok := client.AppInstance.DecryptedData[d.ID]; ok && cached.Version != d.Version {
+			delete(client.AppInstance.DecryptedData, d.ID)
+		}
+
+		data, err := GetData(d.ID)
+		if err != nil {
+			return b, err
+		}
+
+		r := backup.Record{
+			Name:    data.Name,
+			Login:   data.Login,
+			Pass:    data.Pass,
+			CardNum: data.CardNum,
+			CardExp: data.CardExp,
+			Otp:     data.Otp,
+			Text:    data.Text,
+			Meta:    data.Meta,
+			Folder:  FolderPath(folders, data.FolderID),
+			Tags:    data.Tags,
+			Version: data.Version,
+		}
+
+		if data.FileName != "" {
+			content, err := downloadContent(*data)
+			if err != nil {
+				return b, err
+			}
+
+			r.File = &backup.File{Name: data.FileName, Content: content}
+		}
+
+		b.Records = append(b.Records, r)
+	}
+
+	return b, nil
+}
+
+// RestoreFolders создание папок по путям, существующие папки не изменяются
+func RestoreFolders(paths []string) error {
+	folders, err := GetFolders()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if _, err = ensureFolder(&folders, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// downloadContent содержимое файла записи
+// расшифрованная копия, которой не было до выгрузки, удаляется
+func downloadContent(data domain.Data) ([]byte, error) {
+	saved := filepath.Join(client.AppInstance.DataSavePath, client.AppInstance.User.Login, strconv.FormatUint(data.ID, 10), data.FileName)
+	_, err := os.Stat(saved)
+	existed := !errors.Is(err, fs.ErrNotExist)
+
+	path, err := DownloadFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !existed {
+		_ = os.Remove(path)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/crypto"
+	domain2 "gophkeeper/server/domain"
+	"time"
+)
+
+// EmergencyVaultPrefix префикс ссылки на хранилище, открытое по экстренному доступу: emergency:<логин владельца>
+const EmergencyVaultPrefix = "emergency:"
+
+// CreateGrant назначение пользователя login доверенным лицом
+// ключ личного хранилища шифруется открытым ключом доверенного лица, сервер отдаст его только после одобрения запроса
+func CreateGrant(login string, wait time.Duration) error {
+	ctx := userContext()
+
+	public, err := publicKey(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := crypto.SealKey(public, client.AppInstance.User.StorageKey)
+	if err != nil {
+		return domain.ErrEncryptData
+	}
+
+	_, err = client.AppInstance.EmergencyClient.Create(ctx, login, wait, sealed)
+
+	return err
+}
+
+// GetGrants экстренные доступы пользователя как владельца и как доверенного лица
+func GetGrants() ([]domain.EmergencyGrant, error) {
+	return client.AppInstance.EmergencyClient.GetList(userContext())
+}
+
+// AcceptGrant согласие стать доверенным лицом пользователя login
+func AcceptGrant(login string) error {
+	return changeGrant(login, false, client.AppInstance.EmergencyClient.Accept)
+}
+
+// RequestAccess запрос доступа к хранилищу пользователя login
+func RequestAccess(login string) error {
+	return changeGrant(login, false, client.AppInstance.EmergencyClient.Request)
+}
+
+// ApproveAccess одобрение запроса доверенного лица login до истечения времени ожидания
+func ApproveAccess(login string) error {
+	return changeGrant(login, true, client.AppInstance.EmergencyClient.Approve)
+}
+
+// RejectAccess отказ доверенному лицу login в доступе, в том числе уже предоставленном
+func RejectAccess(login string) error {
+	return changeGrant(login, true, client.AppInstance.EmergencyClient.Reject)
+}
+
+// RevokeGrant удаление экстренного доступа с пользователем login
+// сначала ищется доступ, где пользователь владелец, затем - где он доверенное лицо
+func RevokeGrant(login string) error {
+	grant, err := findGrant(login, true)
+	if errors.Is(err, domain.ErrGrantNotFound) {
+		grant, err = findGrant(login, false)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return client.AppInstance.EmergencyClient.Revoke(userContext(), grant.ID)
+}
+
+// emergencyVault хранилище пользователя login, доступ к которому предоставлен, открывается только для чтения
+func emergencyVault(login string) (*client.AppVault, error) {
+	grant, err := findGrant(login, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if grant.VaultKey == "" {
+		return nil, domain.ErrVaultNotFound
+	}
+
+	private, err := privateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := crypto.OpenKey(private, grant.VaultKey)
+	if err != nil {
+		return nil, domain.ErrEncryptData
+	}
+
+	return &client.AppVault{Name: EmergencyVaultPrefix + grant.Grantor, Role: domain2.OrgRoleViewer, Key: key, GrantID: grant.ID}, nil
+}
+
+func changeGrant(login string, grantor bool, change func(ctx context.Context, id uint64) error) error {
+	grant, err := findGrant(login, grantor)
+	if err != nil {
+		return err
+	}
+
+	return change(userContext(), grant.ID)
+}
+
+// findGrant поиск экстренного доступа с пользователем login
+// grantor - пользователь владелец хранилища, а login - доверенное лицо
+func findGrant(login string, grantor bool) (domain.EmergencyGrant, error) {
+	list, err := GetGrants()
+	if err != nil {
+		return domain.EmergencyGrant{}, err
+	}
+
+	for _, g := range list {
+		if grantor && g.Grantee == login && g.Grantor == client.AppInstance.User.Login ||
+			!grantor && g.Grantor == login && g.Grantee == client.AppInstance.User.Login {
+			return g, nil
+		}
+	}
+
+	return domain.EmergencyGrant{}, domain.ErrGrantNotFound
+}
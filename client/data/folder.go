@@ -0,0 +1,99 @@
+package data
+
+import (
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	"strings"
+)
+
+// FolderPathSeparator разделитель папок в пути
+const FolderPathSeparator = "/"
+
+// GetFolders получить все папки пользователя с расшифрованными названиями
+func GetFolders() ([]domain.Folder, error) {
+	ctx := requestContext()
+
+	folders, err := client.AppInstance.DataClient.GetFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range folders {
+		folders[i].Name = decryptLabel(folders[i].Name)
+	}
+
+	return folders, nil
+}
+
+// SaveFolder добавить или переименовать папку
+func SaveFolder(folder domain.Folder) (domain.Folder, error) {
+	if err := checkVaultWrite(); err != nil {
+		return folder, err
+	}
+
+	ctx := requestContext()
+
+	name, err := encryptLabel(folder.Name)
+	if err != nil {
+		return folder, domain.ErrEncryptData
+	}
+
+	encrypted := folder
+	encrypted.Name = name
+
+	if err = client.AppInstance.DataClient.SaveFolder(ctx, &encrypted); err != nil {
+		return folder, err
+	}
+
+	folder.ID = encrypted.ID
+
+	return folder, nil
+}
+
+// DeleteFolder удалить папку вместе с вложенными, данные из папок остаются без папки
+func DeleteFolder(id uint64) error {
+	if err := checkVaultWrite(); err != nil {
+		return err
+	}
+
+	ctx := requestContext()
+
+	return client.AppInstance.DataClient.DeleteFolder(ctx, id)
+}
+
+// FolderPath полный путь папки вида parent/child, пустая строка для ИД 0
+func FolderPath(folders []domain.Folder, id uint64) string {
+	byID := make(map[uint64]domain.Folder, len(folders))
+	for _, f := range folders {
+		byID[f.ID] = f
+	}
+
+	var parts []string
+	for id != 0 && len(parts) <= len(folders) {
+		f, ok := byID[id]
+		if !ok {
+			break
+		}
+
+		parts = append([]string{f.Name}, parts...)
+		id = f.ParentID
+	}
+
+	return strings.Join(parts, FolderPathSeparator)
+}
+
+// FindFolder поиск ИД папки по полному пути, для пустого пути возвращается 0
+func FindFolder(folders []domain.Folder, path string) (uint64, error) {
+	path = strings.Trim(strings.TrimSpace(path), FolderPathSeparator)
+	if path == "" {
+		return 0, nil
+	}
+
+	for _, f := range folders {
+		if FolderPath(folders, f.ID) == path {
+			return f.ID, nil
+		}
+	}
+
+	return 0, domain.ErrFolderNotFound
+}
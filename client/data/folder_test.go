@@ -0,0 +1,38 @@
+package data
+
+import (
+	"gophkeeper/client/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindFolder(t *testing.T) {
+	folders := []domain.Folder{
+		{ID: 1, Name: "work"},
+		{ID: 2, Name: "bank", ParentID: 1},
+		{ID: 3, Name: "bank"},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    uint64
+		wantErr error
+	}{
+		{name: "empty path", path: " "},
+		{name: "root folder", path: "bank", want: 3},
+		{name: "nested folder", path: "/work/bank/", want: 2},
+		{name: "absent folder", path: "work/home", wantErr: domain.ErrFolderNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindFolder(folders, tt.path)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, "work/bank", FolderPath(folders, 2))
+	assert.Equal(t, "", FolderPath(folders, 0))
+}
@@ -0,0 +1,210 @@
+package data
+
+import (
+	"fmt"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/importer"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Поведение при совпадении названия импортируемой записи с существующей
+const (
+	ImportSkip      = "skip"
+	ImportRename    = "rename"
+	ImportOverwrite = "overwrite"
+)
+
+// Действия плана импорта
+const (
+	ImportActionCreate    = "create"
+	ImportActionSkip      = "skip"
+	ImportActionRename    = "rename"
+	ImportActionOverwrite = "overwrite"
+)
+
+// ImportStep запись плана импорта
+// Name - название, под которым запись будет сохранена, ExistingID - перезаписываемая запись
+type ImportStep struct {
+	Item       importer.Item
+	Action     string
+	Name       string
+	ExistingID uint64
+}
+
+// ImportFailure запись, которую не удалось сохранить
+type ImportFailure struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// ImportResult итог импорта
+type ImportResult struct {
+	Created     int             `json:"created"`
+	Overwritten int             `json:"overwritten"`
+	Skipped     int             `json:"skipped"`
+	Failed      []ImportFailure `json:"failed"`
+}
+
+// PlanImport план импорта: для каждой записи определяется, будет ли она создана, пропущена, переименована или перезаписана
+// названия сравниваются с существующими записями и с уже запланированными, так как название записи уникально
+func PlanImport(items []importer.Item, onConflict string) ([]ImportStep, error) {
+	list, err := ListAll(domain.DataListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	// названия уникальны только среди своих записей, чужие записи не перезаписываются
+	existing := make(map[string]uint64, len(list))
+	for _, d := range list {
+		if d.Share == nil {
+			existing[d.Name] = d.ID
+		}
+	}
+
+	planned := make(map[string]bool, len(items))
+	taken := func(name string) bool {
+		_, ok := existing[name]
+		return ok || planned[name]
+	}
+
+	plan := make([]ImportStep, 0, len(items))
+
+	for _, it := range items {
+		step := ImportStep{Item: it, Action: ImportActionCreate, Name: it.Data.Name}
+
+		if taken(step.Name) {
+			id, onServer := existing[step.Name]
+
+			switch {
+			// записи с одинаковым названием внутри экспорта - разные записи, поэтому они не перезаписывают друг друга
+			case onConflict == ImportOverwrite && onServer && !planned[step.Name]:
+				step.Action, step.ExistingID = ImportActionOverwrite, id
+			case onConflict == ImportSkip:
+				step.Action = ImportActionSkip
+			default:
+				step.Action = ImportActionRename
+				for n := 2; taken(step.Name); n++ {
+					step.Name = fmt.Sprintf("%s (%d)", it.Data.Name, n)
+				}
+			}
+		}
+
+		if step.Action != ImportActionSkip {
+			planned[step.Name] = true
+		}
+
+		plan = append(plan, step)
+	}
+
+	return plan, nil
+}
+
+// ApplyImport сохранение записей по плану
+// недостающие папки создаются, ошибка сохранения одной записи не прерывает импорт остальных
+func ApplyImport(plan []ImportStep) (ImportResult, error) {
+	res := ImportResult{Failed: []ImportFailure{}}
+
+	folders, err := GetFolders()
+	if err != nil {
+		return res, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gophkeeper-import")
+	if err != nil {
+		return res, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for i, step := range plan {
+		if step.Action == ImportActionSkip {
+			res.Skipped++
+			continue
+		}
+
+		if err = importStep(step, &folders, filepath.Join(tmpDir, fmt.Sprint(i))); err != nil {
+			res.Failed = append(res.Failed, ImportFailure{Name: step.Name, Error: err.Error()})
+			continue
+		}
+
+		if step.Action == ImportActionOverwrite {
+			res.Overwritten++
+		} else {
+			res.Created++
+		}
+	}
+
+	return res, nil
+}
+
+func importStep(step ImportStep, folders *[]domain.Folder, tmpDir string) error {
+	d := step.Item.Data
+	d.Name = step.Name
+
+	if step.ExistingID != 0 {
+		old, err := GetData(step.ExistingID)
+		if err != nil {
+			return err
+		}
+
+		// файл и ключ записи сохраняются, если в экспорте нет нового файла
+		d.ID, d.Version, d.FileID, d.FileName, d.RecordKey = old.ID, old.Version, old.FileID, old.FileName, old.RecordKey
+	}
+
+	folderID, err := ensureFolder(folders, step.Item.Folder)
+	if err != nil {
+		return err
+	}
+
+	d.FolderID = folderID
+
+	if a := step.Item.Attachment; a != nil {
+		if err = os.MkdirAll(tmpDir, 0o700); err != nil {
+			return err
+		}
+
+		// имя загружаемого файла берется из пути
+		d.FilePath = filepath.Join(tmpDir, filepath.Base(filepath.Clean("/"+a.Name)))
+		if err = os.WriteFile(d.FilePath, a.Content, 0o600); err != nil {
+			return err
+		}
+	}
+
+	_, err = SaveData(d)
+
+	return err
+}
+
+// ensureFolder ИД папки по пути, недостающие папки создаются
+func ensureFolder(folders *[]domain.Folder, path string) (uint64, error) {
+	var parentID uint64
+
+	for _, name := range strings.Split(strings.Trim(path, FolderPathSeparator), FolderPathSeparator) {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+
+		found := false
+		for _, f := range *folders {
+			if f.ParentID == parentID && f.Name == name {
+				parentID, found = f.ID, true
+				break
+			}
+		}
+
+		if found {
+			continue
+		}
+
+		f, err := SaveFolder(domain.Folder{ParentID: parentID, Name: name})
+		if err != nil {
+			return 0, err
+		}
+
+		*folders = append(*folders, f)
+		parentID = f.ID
+	}
+
+	return parentID, nil
+}
@@ -0,0 +1,112 @@
+package data
+
+import (
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/crypto"
+	"strings"
+)
+
+// encryptLabel подготовка тега или названия папки к отправке на сервер
+// при включенном шифровании используется детерминированное шифрование, чтобы сервер мог фильтровать по тегам
+func encryptLabel(label string) (string, error) {
+	if !client.AppInstance.EncryptTags {
+		return label, nil
+	}
+
+	return encryptLabelWith(vaultKey(), label)
+}
+
+// encryptLabelWith шифрование тега или названия папки ключом key, нужно при смене ключа хранилища
+func encryptLabelWith(key []byte, label string) (string, error) {
+	if !client.AppInstance.EncryptTags {
+		return label, nil
+	}
+
+	return crypto.EncryptDeterministic(key, []byte(label))
+}
+
+// decryptLabel расшифровка тега или названия папки
+// значения, сохраненные до включения шифрования, возвращаются как есть
+func decryptLabel(label string) string {
+	if !client.AppInstance.EncryptTags {
+		return label
+	}
+
+	decrypted, err := crypto.Decrypt(vaultKey(), label)
+	if err != nil {
+		return label
+	}
+
+	return decrypted
+}
+
+func encryptTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	res := make([]string, len(tags))
+	for i, tag := range tags {
+		encrypted, err := encryptLabel(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = encrypted
+	}
+
+	return res, nil
+}
+
+func decryptTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	res := make([]string, len(tags))
+	for i, tag := range tags {
+		res[i] = decryptLabel(tag)
+	}
+
+	return res
+}
+
+// encryptName подготовка названия данных к отправке на сервер, key - ключ записи
+// при включенном шифровании возвращается зашифрованное название и его слепой индекс для проверки уникальности
+// слепой индекс всегда вычисляется на ключе хранилища: уникальность названий проверяется среди записей хранилища
+func encryptName(key []byte, name string) (string, string, error) {
+	if !client.AppInstance.EncryptNames {
+		return name, "", nil
+	}
+
+	encrypted, err := crypto.Encrypt(key, []byte(name))
+	if err != nil {
+		return "", "", err
+	}
+
+	return encrypted, crypto.BlindIndex(vaultKey(), []byte(name)), nil
+}
+
+// decryptName расшифровка названия данных ключом записи key
+// названия, сохраненные до включения шифрования, возвращаются как есть
+func decryptName(key []byte, name string) string {
+	if !client.AppInstance.EncryptNames {
+		return name
+	}
+
+	decrypted, err := crypto.Decrypt(key, name)
+	if err != nil {
+		return name
+	}
+
+	return decrypted
+}
+
+// matchName проверка названия на соответствие фильтру, аналог фильтра на сервере
+func matchName(name, prefix, contains string) bool {
+	if prefix != "" && !strings.HasPrefix(name, prefix) {
+		return false
+	}
+
+	return contains == "" || strings.Contains(strings.ToLower(name), strings.ToLower(contains))
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"context"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	grpc2 "gophkeeper/internal/server/grpc"
+	"gophkeeper/internal/server/grpc/interceptors"
+	"gophkeeper/internal/server/repository/memory"
+	pb "gophkeeper/proto"
+	"gophkeeper/server/data"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/file"
+	"gophkeeper/server/folder"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGetDataList_EncryptNames(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	userRepo := memory.NewUserRepository()
+	fileRepo := memory.NewFileRepository()
+	repo := memory.NewDataRepository()
+
+	user := &domain2.User{
+		Login:    "names",
+		Password: "names",
+	}
+	userID, err := userRepo.Store(ctx, *user)
+	require.NoError(t, err)
+	user.ID = userID
+
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := grpc2.NewDataServer(service, t.TempDir(), file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
+
+	lis = bufconn.Listen(bufSize)
+	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth), grpc.StreamInterceptor(interceptors.StreamAuth))
+	pb.RegisterDataServiceServer(s, server)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	defer s.Stop()
+
+	conn := initTestAppInstance(t, user)
+	defer func(conn *grpc.ClientConn) {
+		assert.NoError(t, conn.Close())
+	}(conn)
+
+	client.AppInstance.EncryptNames = true
+
+	// подходящие под фильтр записи перемешаны с остальными и сохранены не по алфавиту
+	for _, name := range []string{"mail-c", "bank", "mail-a", "notes", "mail-e", "mail-b", "wiki", "mail-d"} {
+		_, err = SaveData(domain.Data{Name: name, Text: name})
+		require.NoError(t, err)
+	}
+
+	filter := domain.DataListFilter{NamePrefix: "mail-", Sort: domain2.DataSortNameDesc, PageSize: 2}
+
+	var names []string
+	pages := 0
+	for {
+		list, next, err := GetDataList(filter)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(list), 2)
+
+		for _, d := range list {
+			names = append(names, d.Name)
+		}
+
+		pages++
+		if next == "" {
+			break
+		}
+
+		filter.PageToken = next
+	}
+
+	assert.Equal(t, []string{"mail-e", "mail-d", "mail-c", "mail-b", "mail-a"}, names)
+	assert.Equal(t, 3, pages)
+
+	list, err := ListAll(domain.DataListFilter{NameContains: "MAIL", Sort: domain2.DataSortNameAsc})
+	require.NoError(t, err)
+	require.Len(t, list, 5)
+	assert.Equal(t, "mail-a", list[0].Name)
+
+	_, _, err = GetDataList(domain.DataListFilter{PageToken: "bad"})
+	assert.ErrorIs(t, err, domain.ErrBadPageToken)
+}
@@ -0,0 +1,388 @@
+package data
+
+import (
+	"context"
+	"encoding/base64"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/client/workers/grpc/interceptors"
+	"gophkeeper/internal/crypto"
+	domain2 "gophkeeper/server/domain"
+	"strconv"
+	"strings"
+)
+
+// CreateOrganization создание организации, пользователь становится ее владельцем
+// ключ хранилища создается на клиенте и шифруется открытым ключом пользователя
+func CreateOrganization(name string) (uint64, error) {
+	ctx := userContext()
+
+	public, err := publicKey(ctx, client.AppInstance.User.Login)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := crypto.NewRecordKey()
+	if err != nil {
+		return 0, domain.ErrEncryptData
+	}
+
+	sealed, err := crypto.SealKey(public, key)
+	if err != nil {
+		return 0, domain.ErrEncryptData
+	}
+
+	return client.AppInstance.OrgClient.Create(ctx, name, sealed)
+}
+
+// GetOrganizations организации пользователя и приглашения в них
+func GetOrganizations() ([]domain.Organization, error) {
+	return client.AppInstance.OrgClient.GetList(userContext())
+}
+
+// UseVault переключение на хранилище организации ref (название или ИД), пустая строка - личное хранилище
+// ref с префиксом emergency: - хранилище пользователя, открытое по экстренному доступу
+// кеш расшифрованных данных и индекс поиска относятся к хранилищу, поэтому сбрасываются
+func UseVault(ref string) error {
+	var vault *client.AppVault
+
+	if login, ok := strings.CutPrefix(ref, EmergencyVaultPrefix); ok {
+		var err error
+		if vault, err = emergencyVault(login); err != nil {
+			return err
+		}
+	} else if ref != "" {
+		org, err := findOrganization(ref, true)
+		if err != nil {
+			return err
+		}
+
+		key, err := collectionKey(org)
+		if err != nil {
+			return err
+		}
+
+		vault = &client.AppVault{OrgID: org.ID, KeyVersion: org.KeyVersion, Name: org.Name, Role: org.Role, Key: key}
+	}
+
+	client.AppInstance.Vault = vault
+	client.AppInstance.DecryptedData = make(map[uint64]domain.Data)
+	client.AppInstance.SearchIndex = nil
+
+	return nil
+}
+
+// AcceptInvite принятие приглашения в организацию ref
+func AcceptInvite(ref string) error {
+	org, err := findOrganization(ref, false)
+	if err != nil {
+		return err
+	}
+
+	return client.AppInstance.OrgClient.Accept(userContext(), org.ID)
+}
+
+// GetMembers участники организации ref
+func GetMembers(ref string) ([]domain.OrgMember, error) {
+	org, err := findOrganization(ref, true)
+	if err != nil {
+		return nil, err
+	}
+
+	members, _, err := client.AppInstance.OrgClient.GetMembers(userContext(), org.ID)
+
+	return members, err
+}
+
+// InviteMember приглашение пользователя login в организацию ref с ролью role
+// ключ хранилища шифруется открытым ключом приглашенного, поэтому у него должна быть пара ключей
+func InviteMember(ref, login string, role domain2.OrgRole) error {
+	ctx := userContext()
+
+	org, err := findOrganization(ref, true)
+	if err != nil {
+		return err
+	}
+
+	key, err := collectionKey(org)
+	if err != nil {
+		return err
+	}
+
+	public, err := publicKey(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := crypto.SealKey(public, key)
+	if err != nil {
+		return domain.ErrEncryptData
+	}
+
+	return client.AppInstance.OrgClient.Invite(ctx, org.ID, login, role, sealed, org.KeyVersion)
+}
+
+// SetMemberRole изменение роли участника login организации ref
+func SetMemberRole(ref, login string, role domain2.OrgRole) error {
+	org, err := findOrganization(ref, true)
+	if err != nil {
+		return err
+	}
+
+	return client.AppInstance.OrgClient.SetRole(userContext(), org.ID, login, role)
+}
+
+// RemoveMember исключение участника login из организации ref или отказ от своего приглашения
+// исключенный участник знает ключ хранилища, поэтому создается новый ключ: он шифруется для оставшихся участников,
+// ключи записей, слепые индексы названий, теги и названия папок перешифровываются на нем
+// сами данные записей не меняются: исключенный участник мог их сохранить, поэтому секреты стоит сменить
+func RemoveMember(ref, login string) error {
+	ctx := userContext()
+
+	org, err := findOrganization(ref, login != client.AppInstance.User.Login)
+	if err != nil {
+		return err
+	}
+
+	if !org.Accepted {
+		return client.AppInstance.OrgClient.Remove(ctx, domain.RemoveMember{OrgID: org.ID, Login: login})
+	}
+
+	members, keyVersion, err := client.AppInstance.OrgClient.GetMembers(ctx, org.ID)
+	if err != nil {
+		return err
+	}
+
+	var target *domain.OrgMember
+	for i := range members {
+		if members[i].Login == login {
+			target = &members[i]
+		}
+	}
+
+	// приглашенный, но не принявший приглашение пользователь ключ хранилища не получал
+	if target == nil || !target.Accepted {
+		return client.AppInstance.OrgClient.Remove(ctx, domain.RemoveMember{OrgID: org.ID, Login: login})
+	}
+
+	org.KeyVersion = keyVersion
+
+	oldKey, err := collectionKey(org)
+	if err != nil {
+		return err
+	}
+
+	newKey, err := crypto.NewRecordKey()
+	if err != nil {
+		return domain.ErrEncryptData
+	}
+
+	req := domain.RemoveMember{OrgID: org.ID, Login: login, KeyVersion: keyVersion + 1, Keys: make(map[string]string, len(members))}
+
+	for _, m := range members {
+		if m.Login == login {
+			continue
+		}
+
+		public, err := publicKey(ctx, m.Login)
+		if err != nil {
+			return err
+		}
+
+		if req.Keys[m.Login], err = crypto.SealKey(public, newKey); err != nil {
+			return domain.ErrEncryptData
+		}
+	}
+
+	if err = rotateVault(org, oldKey, newKey, &req); err != nil {
+		return err
+	}
+
+	if err = client.AppInstance.OrgClient.Remove(ctx, req); err != nil {
+		return err
+	}
+
+	if vault := client.AppInstance.Vault; vault != nil && vault.OrgID == org.ID {
+		vault.Key, vault.KeyVersion = newKey, req.KeyVersion
+		client.AppInstance.DecryptedData = make(map[uint64]domain.Data)
+	}
+
+	return nil
+}
+
+// rotateVault перешифровка ключей записей, тегов и названий папок хранилища организации на новый ключ
+// записи без своего ключа (сохраненные до смены ключа старыми клиентами) сначала получают ключ записи
+func rotateVault(org domain.Organization, oldKey, newKey []byte, req *domain.RemoveMember) error {
+	current, cache := client.AppInstance.Vault, client.AppInstance.DecryptedData
+	client.AppInstance.Vault = &client.AppVault{OrgID: org.ID, KeyVersion: org.KeyVersion, Name: org.Name, Role: org.Role, Key: oldKey}
+	client.AppInstance.DecryptedData = make(map[uint64]domain.Data)
+
+	defer func() {
+		client.AppInstance.Vault, client.AppInstance.DecryptedData = current, cache
+	}()
+
+	list, err := ListAll(domain.DataListFilter{})
+	if err != nil {
+		return err
+	}
+
+	for _, d := range list {
+		var wrapped string
+
+		if d.RecordKey != nil {
+			wrapped = *d.RecordKey
+		}
+
+		if wrapped == "" {
+			data, err := GetData(d.ID)
+			if err != nil {
+				return err
+			}
+
+			saved, err := rekey(*data)
+			if err != nil {
+				return err
+			}
+
+			wrapped = saved.RecordKey
+		}
+
+		key, err := crypto.Decrypt(oldKey, wrapped)
+		if err != nil {
+			return domain.ErrEncryptData
+		}
+
+		record := domain.RotatedRecord{ID: d.ID}
+
+		if record.RecordKey, err = crypto.Encrypt(newKey, []byte(key)); err != nil {
+			return domain.ErrEncryptData
+		}
+
+		if client.AppInstance.EncryptNames {
+			record.NameHash = crypto.BlindIndex(newKey, []byte(d.Name))
+		}
+
+		for _, tag := range d.Tags {
+			encrypted, err := encryptLabelWith(newKey, tag)
+			if err != nil {
+				return domain.ErrEncryptData
+			}
+
+			record.Tags = append(record.Tags, encrypted)
+		}
+
+		req.Records = append(req.Records, record)
+	}
+
+	folders, err := GetFolders()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range folders {
+		if f.Name, err = encryptLabelWith(newKey, f.Name); err != nil {
+			return domain.ErrEncryptData
+		}
+
+		req.Folders = append(req.Folders, f)
+	}
+
+	return nil
+}
+
+// findOrganization поиск организации пользователя по названию или ИД
+// accepted - искать только среди организаций, приглашение в которые принято
+func findOrganization(ref string, accepted bool) (domain.Organization, error) {
+	list, err := GetOrganizations()
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
+	id, _ := strconv.ParseUint(ref, 10, 64)
+
+	for _, org := range list {
+		if (org.Name == ref || org.ID == id) && (org.Accepted || !accepted) {
+			return org, nil
+		}
+	}
+
+	return domain.Organization{}, domain.ErrVaultNotFound
+}
+
+// collectionKey расшифровка ключа хранилища организации закрытым ключом пользователя
+func collectionKey(org domain.Organization) ([]byte, error) {
+	if org.CollectionKey == "" {
+		return nil, domain.ErrVaultNotFound
+	}
+
+	private, err := privateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := crypto.OpenKey(private, org.CollectionKey)
+	if err != nil {
+		return nil, domain.ErrEncryptData
+	}
+
+	return key, nil
+}
+
+// publicKey открытый ключ пользователя login
+func publicKey(ctx context.Context, login string) ([]byte, error) {
+	encoded, err := client.AppInstance.UserClient.GetPublicKey(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	if encoded == "" {
+		return nil, domain.ErrNoKeyPair
+	}
+
+	public, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, domain.ErrEncryptData
+	}
+
+	return public, nil
+}
+
+// vaultKey ключ текущего хранилища: ключ организации или ключ, выведенный из пароля пользователя
+func vaultKey() []byte {
+	if client.AppInstance.Vault != nil {
+		return client.AppInstance.Vault.Key
+	}
+
+	return client.AppInstance.User.StorageKey
+}
+
+// checkVaultWrite изменять данные организации могут редакторы и выше, данные по экстренному доступу только читаются
+func checkVaultWrite() error {
+	vault := client.AppInstance.Vault
+
+	if vault != nil && vault.GrantID != 0 {
+		return domain.ErrEmergencyReadOnly
+	}
+
+	if vault != nil && vault.Role < domain2.OrgRoleEditor {
+		return domain.ErrVaultReadOnly
+	}
+
+	return nil
+}
+
+// requestContext контекст запроса к данным: токен пользователя и выбранное хранилище организации или экстренного доступа
+func requestContext() context.Context {
+	ctx := userContext()
+
+	if vault := client.AppInstance.Vault; vault != nil {
+		ctx = context.WithValue(ctx, interceptors.ContextVaultKey{}, interceptors.Vault{OrgID: vault.OrgID, KeyVersion: vault.KeyVersion, GrantID: vault.GrantID})
+	}
+
+	return ctx
+}
+
+// userContext контекст запроса от имени пользователя без хранилища организации
+func userContext() context.Context {
+	return context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+}
@@ -0,0 +1,84 @@
+package data
+
+import (
+	"gophkeeper/client/domain"
+	"gophkeeper/client/otp"
+	"gophkeeper/internal/client"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// otpSaveAttempts количество попыток сохранить счетчик HOTP при конфликте версий
+const otpSaveAttempts = 3
+
+// OTPCode одноразовый пароль записи
+// Remaining - время до смены пароля TOTP, для HOTP - 0; Version - версия записи после сохранения счетчика HOTP
+type OTPCode struct {
+	Code      string
+	Remaining time.Duration
+	Key       *otp.Key
+	Version   uint64
+}
+
+// GenerateOTP одноразовый пароль по ключу записи
+// для HOTP счетчик увеличивается и сохраняется на сервере до выдачи пароля, поэтому один пароль не выдается дважды;
+// если запись изменилась на другом устройстве, она перечитывается и сохранение повторяется
+func GenerateOTP(id uint64) (OTPCode, error) {
+	var err error
+
+	for attempt := 0; attempt < otpSaveAttempts; attempt++ {
+		var res OTPCode
+		if res, err = nextOTP(id); err == nil {
+			return res, nil
+		}
+
+		if status.Code(err) != codes.FailedPrecondition {
+			return OTPCode{}, err
+		}
+
+		// запись изменилась на другом устройстве, в кеше устаревшая версия
+		delete(client.AppInstance.DecryptedData, id)
+	}
+
+	return OTPCode{}, err
+}
+
+// nextOTP одноразовый пароль по текущей версии записи, для HOTP сохраняется увеличенный счетчик
+func nextOTP(id uint64) (OTPCode, error) {
+	d, err := GetData(id)
+	if err != nil {
+		return OTPCode{}, err
+	}
+
+	if d.Otp == "" {
+		return OTPCode{}, domain.ErrNoOTP
+	}
+
+	key, err := otp.Parse(d.Otp)
+	if err != nil {
+		return OTPCode{}, err
+	}
+
+	now := time.Now()
+
+	code, err := key.Code(now)
+	if err != nil {
+		return OTPCode{}, err
+	}
+
+	if key.Type == otp.TypeTOTP {
+		return OTPCode{Code: code, Remaining: key.Remaining(now), Key: key, Version: d.Version}, nil
+	}
+
+	key.Counter++
+	d.Otp = key.URI()
+
+	saved, err := SaveData(*d)
+	if err != nil {
+		return OTPCode{}, err
+	}
+
+	return OTPCode{Code: code, Key: key, Version: saved.Version}, nil
+}
@@ -0,0 +1,132 @@
+package data
+
+import (
+	"gophkeeper/client/domain"
+	"gophkeeper/client/search"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	domain2 "gophkeeper/server/domain"
+	"path/filepath"
+	"strconv"
+)
+
+// listPageSize размер страницы при получении полного списка
+const listPageSize = 500
+
+// SearchData поиск по локальному индексу расшифрованных данных
+func SearchData(query string, limit int) ([]search.Result, error) {
+	idx, err := searchIndex()
+	if err != nil || idx == nil {
+		return nil, err
+	}
+
+	return idx.Search(query, limit), nil
+}
+
+// SyncSearchIndex обновление локального индекса по списку данных на сервере
+// повторно запрашиваются только записи, версия которых изменилась, удаленные на сервере записи убираются из индекса
+func SyncSearchIndex() error {
+	idx, err := searchIndex()
+	if err != nil || idx == nil {
+		return err
+	}
+
+	filter := domain.DataListFilter{Sort: domain2.DataSortIDAsc, PageSize: listPageSize}
+	actual := make(map[uint64]struct{})
+
+	for {
+		list, next, err := GetDataList(filter)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range list {
+			actual[d.ID] = struct{}{}
+
+			if version, ok := idx.Version(d.ID); ok && version == d.Version {
+				continue
+			}
+
+			// в кеше могла остаться устаревшая версия записи
+			delete(client.AppInstance.DecryptedData, d.ID)
+
+			data, _, err := loadData(d.ID)
+			if err != nil {
+				return err
+			}
+
+			idx.Put(searchEntry(*data))
+		}
+
+		if next == "" {
+			break
+		}
+
+		filter.PageToken = next
+	}
+
+	for _, id := range idx.IDs() {
+		if _, ok := actual[id]; !ok {
+			idx.Delete(id)
+		}
+	}
+
+	return idx.Save()
+}
+
+// searchIndex индекс текущего пользователя, nil - если каталог для данных клиента не задан
+func searchIndex() (*search.Index, error) {
+	if client.AppInstance.SearchIndex != nil {
+		return client.AppInstance.SearchIndex, nil
+	}
+
+	if client.AppInstance.DataSavePath == "" || client.AppInstance.User.StorageKey == nil {
+		return nil, nil
+	}
+
+	path := filepath.Join(client.AppInstance.DataSavePath, client.AppInstance.User.Login, search.IndexFileName)
+
+	// у каждого хранилища свой индекс, индекс шифруется ключом пользователя, который не меняется при смене ключа хранилища
+	if vault := client.AppInstance.Vault; vault != nil && vault.GrantID != 0 {
+		path = filepath.Join(client.AppInstance.DataSavePath, client.AppInstance.User.Login, "emergency-"+strconv.FormatUint(vault.GrantID, 10), search.IndexFileName)
+	} else if vault != nil {
+		path = filepath.Join(client.AppInstance.DataSavePath, client.AppInstance.User.Login, "vault-"+strconv.FormatUint(vault.OrgID, 10), search.IndexFileName)
+	}
+
+	idx, err := search.Open(path, client.AppInstance.User.StorageKey)
+	if err != nil {
+		internal.Logger.Errorw("error opening search index", "error", err)
+		return nil, domain.ErrSearchIndex
+	}
+
+	client.AppInstance.SearchIndex = idx
+
+	return idx, nil
+}
+
+// updateSearchIndex изменение индекса после операций с данными
+// ошибка индекса не мешает основной операции, индекс восстановится при следующей синхронизации
+func updateSearchIndex(update func(idx *search.Index)) {
+	idx, err := searchIndex()
+	if err != nil || idx == nil {
+		return
+	}
+
+	update(idx)
+
+	if err = idx.Save(); err != nil {
+		internal.Logger.Errorw("error saving search index", "error", err)
+	}
+}
+
+// searchEntry запись индекса, пароль и номер карты не индексируются
+func searchEntry(data domain.Data) search.Entry {
+	return search.Entry{
+		ID:      data.ID,
+		Version: data.Version,
+		Name:    data.Name,
+		Login:   data.Login,
+		Text:    data.Text,
+		Meta:    data.Meta,
+	}
+}
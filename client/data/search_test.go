@@ -0,0 +1,96 @@
+package data
+
+import (
+	"context"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/search"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	grpc2 "gophkeeper/internal/server/grpc"
+	"gophkeeper/internal/server/grpc/interceptors"
+	"gophkeeper/internal/server/repository/memory"
+	pb "gophkeeper/proto"
+	"gophkeeper/server/data"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/file"
+	"gophkeeper/server/folder"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestSyncSearchIndex(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	userRepo := memory.NewUserRepository()
+	fileRepo := memory.NewFileRepository()
+	repo := memory.NewDataRepository()
+
+	user := &domain2.User{
+		Login:    "search",
+		Password: "search",
+	}
+	userID, err := userRepo.Store(ctx, *user)
+	require.NoError(t, err)
+	user.ID = userID
+
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := grpc2.NewDataServer(service, t.TempDir(), file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
+
+	lis = bufconn.Listen(bufSize)
+	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth), grpc.StreamInterceptor(interceptors.StreamAuth))
+	pb.RegisterDataServiceServer(s, server)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	defer s.Stop()
+
+	conn := initTestAppInstance(t, user)
+	defer func(conn *grpc.ClientConn) {
+		assert.NoError(t, conn.Close())
+	}(conn)
+
+	// без каталога данных индекс не ведется
+	_, err = SaveData(domain.Data{Name: "GitHub", Login: "ci-bot", Pass: "secret-pass"})
+	require.NoError(t, err)
+	assert.Nil(t, client.AppInstance.SearchIndex)
+
+	client.AppInstance.DataSavePath = t.TempDir()
+
+	second, err := SaveData(domain.Data{Name: "Bank", CardNum: "4111111111111111", Meta: "visa gold"})
+	require.NoError(t, err)
+	require.NotNil(t, client.AppInstance.SearchIndex)
+	assert.Equal(t, 1, client.AppInstance.SearchIndex.Len())
+
+	require.NoError(t, SyncSearchIndex())
+	assert.Equal(t, 2, client.AppInstance.SearchIndex.Len())
+
+	res, err := SearchData("login:ci-bot", 10)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "GitHub", res[0].Name)
+
+	res, err = SearchData("secret", 10)
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	res, err = SearchData("4111", 10)
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	require.NoError(t, DeleteData(second.ID))
+	res, err = SearchData("visa", 10)
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	// индекс хранится на диске зашифрованным
+	raw, err := os.ReadFile(filepath.Join(client.AppInstance.DataSavePath, user.Login, search.IndexFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "ci-bot")
+}
@@ -0,0 +1,31 @@
+package data
+
+import (
+	"encoding/base64"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/crypto"
+	"time"
+)
+
+// CreateSecretLink одноразовая ссылка на секрет для человека без учетной записи
+// секрет шифруется случайным ключом, который добавляется во фрагмент ссылки: браузер фрагмент на сервер не отправляет,
+// поэтому сервер хранит только шифротекст, который отдает не больше maxViews раз до истечения expire
+func CreateSecretLink(secret []byte, expire time.Duration, maxViews int) (string, error) {
+	key, err := crypto.NewRecordKey()
+	if err != nil {
+		return "", domain.ErrEncryptData
+	}
+
+	blob, err := crypto.Encrypt(key, secret)
+	if err != nil {
+		return "", domain.ErrEncryptData
+	}
+
+	url, err := client.AppInstance.SecretClient.Create(userContext(), blob, expire, maxViews)
+	if err != nil {
+		return "", err
+	}
+
+	return url + "#" + base64.RawURLEncoding.EncodeToString(key), nil
+}
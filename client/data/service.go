@@ -2,16 +2,16 @@
 package data
 
 import (
-	"context"
 	"errors"
 	"gophkeeper/client/domain"
+	"gophkeeper/client/search"
 	"gophkeeper/internal"
 	"gophkeeper/internal/client"
-	"gophkeeper/internal/client/workers/grpc/interceptors"
 	"gophkeeper/internal/crypto"
 	domain2 "gophkeeper/server/domain"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 )
 
@@ -21,7 +21,27 @@ import (
 func SaveData(data domain.Data) (domain.Data, error) {
 	var encryptedFilePath string
 
-	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+	if data.SharedBy != "" && data.Permission != domain2.SharePermissionWrite {
+		return data, domain.ErrSharedReadOnly
+	}
+
+	if err := checkVaultWrite(); err != nil {
+		return data, err
+	}
+
+	// у записей организации всегда свой ключ: при смене ключа хранилища перешифровываются только ключи записей
+	if client.AppInstance.Vault != nil && data.RecordKey == "" {
+		key, err := crypto.NewRecordKey()
+		if err != nil {
+			return data, domain.ErrEncryptData
+		}
+
+		if data.RecordKey, err = crypto.Encrypt(vaultKey(), key); err != nil {
+			return data, domain.ErrEncryptData
+		}
+	}
+
+	ctx := requestContext()
 	// hash data
 	hashedData, err := encryptData(data)
 	if err != nil {
@@ -42,7 +62,12 @@ func SaveData(data domain.Data) (domain.Data, error) {
 
 	// upload file
 	if data.FilePath != "" {
-		encryptedFilePath, err = encryptFile(data.FilePath)
+		var key []byte
+		if key, err = recordKey(data.RecordKey, data.SharedBy); err != nil {
+			return data, domain.ErrEncryptData
+		}
+
+		encryptedFilePath, err = encryptFile(key, data.FilePath)
 		err = client.AppInstance.DataClient.UploadFile(ctx, &data, encryptedFilePath, filepath.Base(data.FilePath))
 		if err != nil {
 			return data, err
@@ -54,51 +79,260 @@ func SaveData(data domain.Data) (domain.Data, error) {
 		client.AppInstance.DecryptedData[data.ID] = data
 	}
 
+	updateSearchIndex(func(idx *search.Index) {
+		idx.Put(searchEntry(data))
+	})
+
 	return data, nil
 }
 
 // GetData получение данных с сервера
 // после получения, данные раскодируются паролем пользователя
 func GetData(id uint64) (*domain.Data, error) {
+	data, fetched, err := loadData(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if fetched {
+		updateSearchIndex(func(idx *search.Index) {
+			idx.Put(searchEntry(*data))
+		})
+	}
+
+	return data, nil
+}
+
+// loadData данные из кеша или с сервера, второе значение - были ли данные запрошены с сервера
+func loadData(id uint64) (*domain.Data, bool, error) {
 	var err error
 	data, ok := client.AppInstance.DecryptedData[id]
-	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+	ctx := requestContext()
 
 	if !ok {
 		var gotData, decrypted *domain.Data
 		gotData, err = client.AppInstance.DataClient.Get(ctx, id)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		if gotData == nil {
-			return nil, domain.ErrDataNotFound
+			return nil, false, domain.ErrDataNotFound
 		}
 
 		decrypted, err = decryptData(*gotData)
+		if err != nil {
+			return nil, false, domain.ErrEncryptData
+		}
+
 		data = *decrypted
 	}
 
 	client.AppInstance.DecryptedData[id] = data
 
-	return &data, nil
+	return &data, !ok, nil
+}
+
+// GetDataList получить страницу списка данных пользователя в кратком формате (ID, Name, папка и теги)
+// вторым значением возвращается токен следующей страницы, пустой - если страница последняя
+func GetDataList(filter domain.DataListFilter) ([]domain2.DataName, string, error) {
+	// сервер не может ни искать, ни сортировать по зашифрованным названиям, поэтому список получается целиком,
+	// а фильтр, сортировка и разбиение на страницы выполняются на клиенте
+	if client.AppInstance.EncryptNames {
+		list, err := listDecrypted(filter)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return pageList(list, int(filter.PageSize), filter.PageToken)
+	}
+
+	tags, err := encryptTags(filter.Tags)
+	if err != nil {
+		return nil, "", domain.ErrEncryptData
+	}
+
+	filter.Tags = tags
+
+	list, next, err := client.AppInstance.DataClient.GetList(requestContext(), filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res := make([]domain2.DataName, 0, len(list))
+	for _, d := range list {
+		if d, ok := decryptListItem(d); ok {
+			res = append(res, d)
+		}
+	}
+
+	return res, next, nil
+}
+
+// listDecrypted полный список данных по фильтру с фильтрацией по расшифрованным названиям
+// и сортировкой на клиенте, токен и размер страницы фильтра не учитываются
+func listDecrypted(filter domain.DataListFilter) ([]domain2.DataName, error) {
+	ctx := requestContext()
+
+	tags, err := encryptTags(filter.Tags)
+	if err != nil {
+		return nil, domain.ErrEncryptData
+	}
+
+	prefix, contains, order := filter.NamePrefix, filter.NameContains, filter.Sort
+
+	filter.Tags = tags
+	filter.NamePrefix, filter.NameContains = "", ""
+	filter.Sort = domain2.DataSortIDAsc
+	filter.PageSize = listPageSize
+	filter.PageToken = ""
+
+	var res []domain2.DataName
+
+	for {
+		list, next, err := client.AppInstance.DataClient.GetList(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range list {
+			d, ok := decryptListItem(d)
+			if ok && matchName(d.Name, prefix, contains) {
+				res = append(res, d)
+			}
+		}
+
+		if next == "" {
+			break
+		}
+
+		filter.PageToken = next
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return lessData(res[i], res[j], order)
+	})
+
+	return res, nil
+}
+
+// decryptListItem расшифровка названия и меток элемента списка, false - элемент не показывается
+func decryptListItem(d domain2.DataName) (domain2.DataName, bool) {
+	// записи, которыми с владельцем поделились другие, зашифрованы для него и по экстренному доступу не открываются
+	if vault := client.AppInstance.Vault; vault != nil && vault.GrantID != 0 && d.Share != nil {
+		return d, false
+	}
+
+	d.Name = decryptListName(d)
+	d.Tags = decryptTags(d.Tags)
+
+	return d, true
+}
+
+// lessData порядок элементов списка, аналог сортировки на сервере
+func lessData(a, b domain2.DataName, order domain2.DataSort) bool {
+	switch order {
+	case domain2.DataSortNameDesc:
+		return a.Name > b.Name || (a.Name == b.Name && a.ID > b.ID)
+	case domain2.DataSortIDAsc:
+		return a.ID < b.ID
+	case domain2.DataSortIDDesc:
+		return a.ID > b.ID
+	default:
+		return a.Name < b.Name || (a.Name == b.Name && a.ID < b.ID)
+	}
 }
 
-// GetDataList получить список данных пользователя в кратком формате (ID, Name)
-func GetDataList() ([]domain2.DataName, error) {
-	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+// pageList страница списка, полученного целиком; токен страницы - смещение первого элемента
+func pageList(list []domain2.DataName, size int, token string) ([]domain2.DataName, string, error) {
+	if size <= 0 {
+		size = domain2.DataListDefaultPageSize
+	}
+
+	size = min(size, domain2.DataListMaxPageSize)
+
+	offset := 0
+	if token != "" {
+		var err error
+		offset, err = strconv.Atoi(token)
+		if err != nil || offset < 0 || offset > len(list) {
+			return nil, "", domain.ErrBadPageToken
+		}
+	}
+
+	end := min(offset+size, len(list))
+	if end == len(list) {
+		return list[offset:end], "", nil
+	}
+
+	return list[offset:end], strconv.Itoa(end), nil
+}
 
-	list, err := client.AppInstance.DataClient.GetList(ctx)
+// FindData поиск данных по точному названию, а если не нашлось - по ИД
+func FindData(ref string) (*domain.Data, error) {
+	list, err := ListAll(domain.DataListFilter{NamePrefix: ref})
 	if err != nil {
 		return nil, err
 	}
 
-	return list, nil
+	// своя запись важнее чужой с тем же названием
+	var shared uint64
+
+	for _, d := range list {
+		if d.Name != ref {
+			continue
+		}
+
+		if d.Share == nil {
+			return GetData(d.ID)
+		}
+
+		if shared == 0 {
+			shared = d.ID
+		}
+	}
+
+	if shared != 0 {
+		return GetData(shared)
+	}
+
+	id, err := strconv.ParseUint(ref, 10, 64)
+	if err != nil || id == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return GetData(id)
+}
+
+// ListAll получение всех страниц списка данных
+func ListAll(filter domain.DataListFilter) ([]domain2.DataName, error) {
+	if client.AppInstance.EncryptNames {
+		return listDecrypted(filter)
+	}
+
+	var res []domain2.DataName
+
+	filter.PageSize = listPageSize
+
+	for {
+		list, next, err := GetDataList(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, list...)
+
+		if next == "" {
+			return res, nil
+		}
+
+		filter.PageToken = next
+	}
 }
 
 // DownloadFile скачать файл пользователя с сервера
 // после скачивания файл раскодируется
 func DownloadFile(data domain.Data) (string, error) {
-	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+	ctx := requestContext()
 
 	dataSavePath := filepath.Join(client.AppInstance.DataSavePath, client.AppInstance.User.Login, strconv.FormatUint(data.ID, 10))
 	tmpSavePath, err := os.MkdirTemp(filepath.FromSlash("/tmp"), client.AppInstance.User.Login)
@@ -111,12 +345,21 @@ func DownloadFile(data domain.Data) (string, error) {
 		return tmpFilePath, err
 	}
 
-	return decryptFile(tmpFilePath, filepath.Join(dataSavePath, data.FileName))
+	key, err := recordKey(data.RecordKey, data.SharedBy)
+	if err != nil {
+		return "", domain.ErrEncryptData
+	}
+
+	return decryptFile(key, tmpFilePath, filepath.Join(dataSavePath, data.FileName))
 }
 
 // DeleteData удалить данные
 func DeleteData(id uint64) error {
-	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+	if err := checkVaultWrite(); err != nil {
+		return err
+	}
+
+	ctx := requestContext()
 	err := client.AppInstance.DataClient.DeleteData(ctx, id)
 
 	delete(client.AppInstance.DecryptedData, id)
@@ -125,98 +368,157 @@ func DeleteData(id uint64) error {
 		return err
 	}
 
+	updateSearchIndex(func(idx *search.Index) {
+		idx.Delete(id)
+	})
+
 	return nil
 }
 
 func encryptData(data domain.Data) (*domain.Data, error) {
-	var pass, login, cardNum, text, meta string
+	var pass, login, cardNum, cardExp, otp, text, meta string
 	var err error
 	var hashedData *domain.Data
 
+	key, err := recordKey(data.RecordKey, data.SharedBy)
+	if err != nil {
+		return nil, err
+	}
+
 	if data.Pass != "" {
-		pass, err = crypto.Encrypt(client.AppInstance.User.StorageKey, []byte(data.Pass))
+		pass, err = crypto.Encrypt(key, []byte(data.Pass))
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if data.Login != "" {
-		login, err = crypto.Encrypt(client.AppInstance.User.StorageKey, []byte(data.Login))
+		login, err = crypto.Encrypt(key, []byte(data.Login))
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if data.CardNum != "" {
-		cardNum, err = crypto.Encrypt(client.AppInstance.User.StorageKey, []byte(data.CardNum))
+		cardNum, err = crypto.Encrypt(key, []byte(data.CardNum))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.CardExp != "" {
+		cardExp, err = crypto.Encrypt(key, []byte(data.CardExp))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.Otp != "" {
+		otp, err = crypto.Encrypt(key, []byte(data.Otp))
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if data.Text != "" {
-		text, err = crypto.Encrypt(client.AppInstance.User.StorageKey, []byte(data.Text))
+		text, err = crypto.Encrypt(key, []byte(data.Text))
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if data.Meta != "" {
-		meta, err = crypto.Encrypt(client.AppInstance.User.StorageKey, []byte(data.Meta))
+		meta, err = crypto.Encrypt(key, []byte(data.Meta))
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	tags, err := encryptTags(data.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	name, nameHash, err := encryptName(key, data.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	hashedData = &domain.Data{
-		Version: data.Version,
-		ID:      data.ID,
-		Name:    data.Name,
-		Pass:    pass,
-		CardNum: cardNum,
-		Text:    text,
-		Login:   login,
-		Meta:    meta,
+		Version:  data.Version,
+		ID:       data.ID,
+		Name:     name,
+		NameHash: nameHash,
+		Pass:     pass,
+		CardNum:  cardNum,
+		CardExp:  cardExp,
+		Otp:      otp,
+		Text:     text,
+		Login:    login,
+		Meta:     meta,
+		FolderID: data.FolderID,
+		Tags:     tags,
+
+		RecordKey: data.RecordKey,
 	}
 
 	return hashedData, nil
 }
 
 func decryptData(data domain.Data) (*domain.Data, error) {
-	var pass, login, cardNum, text, meta string
+	var pass, login, cardNum, cardExp, otp, text, meta string
 	var err error
 	var decryptedData *domain.Data
 
+	key, err := recordKey(data.RecordKey, data.SharedBy)
+	if err != nil {
+		return nil, err
+	}
+
 	if data.Pass != "" {
-		pass, err = crypto.Decrypt(client.AppInstance.User.StorageKey, data.Pass)
+		pass, err = crypto.Decrypt(key, data.Pass)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if data.Login != "" {
-		login, err = crypto.Decrypt(client.AppInstance.User.StorageKey, data.Login)
+		login, err = crypto.Decrypt(key, data.Login)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if data.CardNum != "" {
-		cardNum, err = crypto.Decrypt(client.AppInstance.User.StorageKey, data.CardNum)
+		cardNum, err = crypto.Decrypt(key, data.CardNum)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.CardExp != "" {
+		cardExp, err = crypto.Decrypt(key, data.CardExp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.Otp != "" {
+		otp, err = crypto.Decrypt(key, data.Otp)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if data.Text != "" {
-		text, err = crypto.Decrypt(client.AppInstance.User.StorageKey, data.Text)
+		text, err = crypto.Decrypt(key, data.Text)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if data.Meta != "" {
-		meta, err = crypto.Decrypt(client.AppInstance.User.StorageKey, data.Meta)
+		meta, err = crypto.Decrypt(key, data.Meta)
 		if err != nil {
 			return nil, err
 		}
@@ -225,27 +527,35 @@ func decryptData(data domain.Data) (*domain.Data, error) {
 	decryptedData = &domain.Data{
 		Version:  data.Version,
 		ID:       data.ID,
-		Name:     data.Name,
+		Name:     decryptName(key, data.Name),
 		Pass:     pass,
 		CardNum:  cardNum,
+		CardExp:  cardExp,
+		Otp:      otp,
 		Text:     text,
 		Login:    login,
 		Meta:     meta,
 		FileName: data.FileName,
 		FileID:   data.FileID,
+		FolderID: data.FolderID,
+		Tags:     decryptTags(data.Tags),
+
+		RecordKey:  data.RecordKey,
+		SharedBy:   data.SharedBy,
+		Permission: data.Permission,
 	}
 
 	return decryptedData, nil
 }
 
-func encryptFile(filePath string) (string, error) {
+func encryptFile(key []byte, filePath string) (string, error) {
 	text, err := os.ReadFile(filePath)
 	if err != nil {
 		internal.Logger.Errorw("error reading file", "error", err)
 		return "", domain.ErrReadingFile
 	}
 
-	cryptedText, err := crypto.Encrypt(client.AppInstance.User.StorageKey, text)
+	cryptedText, err := crypto.Encrypt(key, text)
 	if err != nil {
 		internal.Logger.Errorw("error encrypting file", "error", err)
 		return "", domain.ErrEncryptData
@@ -269,14 +579,14 @@ func encryptFile(filePath string) (string, error) {
 	return f.Name(), nil
 }
 
-func decryptFile(inputFile, outputFile string) (string, error) {
+func decryptFile(key []byte, inputFile, outputFile string) (string, error) {
 	text, err := os.ReadFile(inputFile)
 	if err != nil {
 		internal.Logger.Errorw("error reading file", "error", err)
 		return "", domain.ErrReadingFile
 	}
 
-	cryptedText, err := crypto.Decrypt(client.AppInstance.User.StorageKey, string(text))
+	cryptedText, err := crypto.Decrypt(key, string(text))
 	if err != nil {
 		internal.Logger.Errorw("error encrypting file", "error", err)
 		return "", domain.ErrEncryptData
@@ -10,12 +10,14 @@ import (
 	"gophkeeper/internal/server/auth"
 	grpc2 "gophkeeper/internal/server/grpc"
 	"gophkeeper/internal/server/grpc/interceptors"
+	"gophkeeper/internal/server/repository/memory"
 	"gophkeeper/internal/server/repository/pgsql"
 	"gophkeeper/internal/test"
 	pb "gophkeeper/proto"
 	"gophkeeper/server/data"
 	domain2 "gophkeeper/server/domain"
 	"gophkeeper/server/file"
+	"gophkeeper/server/folder"
 	"net"
 	"os"
 	"testing"
@@ -68,6 +70,8 @@ func Test_hashData(t *testing.T) {
 }
 
 func TestSaveData(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	testFile := createTestFile(t)
 	ctx := context.Background()
 
@@ -104,8 +108,8 @@ func TestSaveData(t *testing.T) {
 	assert.NoError(t, err)
 
 	// server grpc server
-	service := data.NewService(repo, fileRepo)
-	server := grpc2.NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo))
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := grpc2.NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth), grpc.StreamInterceptor(interceptors.StreamAuth))
@@ -173,6 +177,8 @@ func TestSaveData(t *testing.T) {
 }
 
 func TestGet(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	ctx := context.Background()
 
 	// prepare server side
@@ -208,8 +214,8 @@ func TestGet(t *testing.T) {
 	assert.NoError(t, err)
 
 	// server grpc server
-	service := data.NewService(repo, fileRepo)
-	server := grpc2.NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo))
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := grpc2.NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth), grpc.StreamInterceptor(interceptors.StreamAuth))
@@ -265,6 +271,8 @@ func TestGet(t *testing.T) {
 }
 
 func TestGetDataList(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	ctx := context.Background()
 
 	// prepare server side
@@ -300,8 +308,8 @@ func TestGetDataList(t *testing.T) {
 	assert.NoError(t, err)
 
 	// server grpc server
-	service := data.NewService(repo, fileRepo)
-	server := grpc2.NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo))
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := grpc2.NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth), grpc.StreamInterceptor(interceptors.StreamAuth))
@@ -336,9 +344,11 @@ func TestGetDataList(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var got []domain2.DataName
-			got, err = GetDataList()
+			var next string
+			got, next, err = GetDataList(domain.DataListFilter{})
 			assert.NoError(t, err)
 
+			assert.Empty(t, next)
 			assert.Equal(t, 1, len(got))
 			assert.Equal(t, got[0].Name, testData.Name)
 			assert.Equal(t, got[0].ID, testData.ID)
@@ -0,0 +1,194 @@
+package data
+
+import (
+	"context"
+	"encoding/base64"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/client/workers/grpc/interceptors"
+	"gophkeeper/internal/crypto"
+	domain2 "gophkeeper/server/domain"
+	"os"
+	"path/filepath"
+)
+
+// ShareData предоставить пользователю login доступ к своей записи на чтение или на изменение
+// ключ записи шифруется открытым ключом получателя, запись без своего ключа сначала перешифровывается новым ключом
+func ShareData(data domain.Data, login string, write bool) error {
+	if data.SharedBy != "" {
+		return domain.ErrShareNotOwner
+	}
+
+	// записи организации доступны ее участникам через ключ хранилища
+	if client.AppInstance.Vault != nil {
+		return domain.ErrVaultShare
+	}
+
+	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+
+	// открытый ключ запрашивается до перешифровки, чтобы не менять запись, если получателя нет
+	encoded, err := client.AppInstance.UserClient.GetPublicKey(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	public, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return domain.ErrEncryptData
+	}
+
+	if data.RecordKey == "" {
+		if data, err = rekey(data); err != nil {
+			return err
+		}
+	}
+
+	key, err := recordKey(data.RecordKey, "")
+	if err != nil {
+		return domain.ErrEncryptData
+	}
+
+	sealed, err := crypto.SealKey(public, key)
+	if err != nil {
+		return domain.ErrEncryptData
+	}
+
+	permission := domain2.SharePermissionRead
+	if write {
+		permission = domain2.SharePermissionWrite
+	}
+
+	return client.AppInstance.DataClient.ShareData(ctx, data.ID, login, sealed, permission)
+}
+
+// UnshareData отозвать доступ пользователя login к своей записи
+// ключ записи не меняется: получатель мог сохранить расшифрованные данные, поэтому секреты стоит сменить
+func UnshareData(data domain.Data, login string) error {
+	if data.SharedBy != "" {
+		return domain.ErrShareNotOwner
+	}
+
+	if client.AppInstance.Vault != nil {
+		return domain.ErrVaultShare
+	}
+
+	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+
+	return client.AppInstance.DataClient.UnshareData(ctx, data.ID, login)
+}
+
+// rekey перешифровка записи новым ключом записи, файл записи скачивается и загружается заново
+func rekey(data domain.Data) (domain.Data, error) {
+	key, err := crypto.NewRecordKey()
+	if err != nil {
+		return data, domain.ErrEncryptData
+	}
+
+	wrapped, err := crypto.Encrypt(vaultKey(), key)
+	if err != nil {
+		return data, domain.ErrEncryptData
+	}
+
+	if data.FileName != "" {
+		content, err := downloadContent(data)
+		if err != nil {
+			return data, err
+		}
+
+		tmpDir, err := os.MkdirTemp("", "gophkeeper-share")
+		if err != nil {
+			return data, err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		data.FilePath = filepath.Join(tmpDir, data.FileName)
+		if err = os.WriteFile(data.FilePath, content, 0o600); err != nil {
+			return data, err
+		}
+	}
+
+	data.RecordKey = wrapped
+
+	saved, err := SaveData(data)
+	if err != nil {
+		return data, err
+	}
+
+	// временный файл удаляется, повторно его загружать не нужно
+	saved.FilePath = ""
+	client.AppInstance.DecryptedData[saved.ID] = saved
+
+	return saved, nil
+}
+
+// recordKey ключ шифрования полей и файла записи
+// wrapped - ключ записи в том виде, в котором он хранится на сервере, owner - логин владельца чужой записи
+// запись без своего ключа зашифрована ключом хранилища
+func recordKey(wrapped, owner string) ([]byte, error) {
+	if wrapped == "" {
+		return vaultKey(), nil
+	}
+
+	if owner == "" {
+		key, err := crypto.Decrypt(vaultKey(), wrapped)
+		return []byte(key), err
+	}
+
+	private, err := privateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.OpenKey(private, wrapped)
+}
+
+// privateKey закрытый ключ пользователя, при первом обращении загружается с сервера и расшифровывается
+func privateKey() ([]byte, error) {
+	if client.AppInstance.User.PrivateKey != nil {
+		return client.AppInstance.User.PrivateKey, nil
+	}
+
+	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+
+	keys, err := client.AppInstance.UserClient.GetKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys.PrivateKey == "" {
+		return nil, domain.ErrNoKeyPair
+	}
+
+	private, err := crypto.Decrypt(client.AppInstance.User.StorageKey, keys.PrivateKey)
+	if err != nil {
+		return nil, domain.ErrEncryptData
+	}
+
+	client.AppInstance.User.PrivateKey = []byte(private)
+
+	return client.AppInstance.User.PrivateKey, nil
+}
+
+// decryptListName расшифровка названия из списка данных ключом записи
+func decryptListName(d domain2.DataName) string {
+	if !client.AppInstance.EncryptNames {
+		return d.Name
+	}
+
+	var wrapped, owner string
+
+	if d.RecordKey != nil {
+		wrapped = *d.RecordKey
+	}
+
+	if d.Share != nil {
+		wrapped, owner = d.Share.RecordKey, d.Share.Owner
+	}
+
+	key, err := recordKey(wrapped, owner)
+	if err != nil {
+		return d.Name
+	}
+
+	return decryptName(key, d.Name)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "gophkeeper/server/domain"
+
+// auditActionNames названия действий журнала аудита для командной строки и интерфейса
+var auditActionNames = map[domain.AuditAction]string{
+	domain.AuditRegister:       "register",
+	domain.AuditLogin:          "login",
+	domain.AuditRefresh:        "refresh",
+	domain.AuditRead:           "read",
+	domain.AuditSave:           "save",
+	domain.AuditDelete:         "delete",
+	domain.AuditDownload:       "download",
+	domain.AuditUpload:         "upload",
+	domain.AuditShare:          "share",
+	domain.AuditUnshare:        "unshare",
+	domain.AuditDeleteAccount:  "delete-account",
+	domain.AuditRestoreAccount: "restore-account",
+	domain.AuditPurgeAccount:   "purge-account",
+}
+
+// auditOutcomeNames названия результатов действий журнала аудита
+var auditOutcomeNames = map[domain.AuditOutcome]string{
+	domain.AuditSuccess: "ok",
+	domain.AuditDenied:  "denied",
+	domain.AuditFailure: "failed",
+}
+
+// AuditActionName название действия журнала аудита
+func AuditActionName(action domain.AuditAction) string {
+	return auditActionNames[action]
+}
+
+// AuditOutcomeName название результата действия журнала аудита
+func AuditOutcomeName(outcome domain.AuditOutcome) string {
+	return auditOutcomeNames[outcome]
+}
+
+// ParseAuditAction действие журнала аудита по названию, false - название неизвестно
+func ParseAuditAction(name string) (domain.AuditAction, bool) {
+	for action, n := range auditActionNames {
+		if n == name {
+			return action, true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseAuditOutcome результат действия журнала аудита по названию, false - название неизвестно
+func ParseAuditOutcome(name string) (domain.AuditOutcome, bool) {
+	for outcome, n := range auditOutcomeNames {
+		if n == name {
+			return outcome, true
+		}
+	}
+
+	return 0, false
+}
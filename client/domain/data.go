@@ -1,16 +1,55 @@
 // Package domain в данном пакете представлены модели данных
 package domain
 
+import "gophkeeper/server/domain"
+
 type Data struct {
 	ID,
 	FileID,
+	FolderID,
 	Version uint64
+	Tags []string
 	Name,
 	Pass,
 	CardNum,
+	// CardExp срок действия карты в виде MM/YY
+	CardExp,
+	// Otp ключ одноразовых паролей в виде otpauth:// URI
+	Otp,
 	Text,
 	FilePath,
 	FileName,
 	Login,
-	Meta string
+	Meta,
+	// NameHash слепой индекс названия, заполняется только при отправке зашифрованного названия
+	NameHash,
+	// RecordKey ключ записи в том виде, в котором он хранится на сервере: зашифрованный ключом хранилища
+	// для своей записи или открытым ключом пользователя для чужой, пустой - запись зашифрована ключом хранилища
+	RecordKey,
+	// SharedBy логин владельца чужой записи, к которой пользователю предоставлен доступ
+	SharedBy string
+	// Permission права на чужую запись
+	Permission domain.SharePermission
+}
+
+// DataListFilter параметры запроса списка данных
+// PageToken - токен страницы, полученный в предыдущем ответе сервера
+type DataListFilter struct {
+	NamePrefix,
+	NameContains,
+	PageToken string
+	Type     domain.DataType
+	Sort     domain.DataSort
+	PageSize uint32
+	FolderID uint64
+	Tags     []string
+	// Shared только чужие записи, к которым пользователю предоставлен доступ
+	Shared bool
+}
+
+// Folder папка пользователя, ParentID = 0 для папок верхнего уровня
+type Folder struct {
+	ID,
+	ParentID uint64
+	Name string
 }
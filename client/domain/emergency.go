@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"gophkeeper/server/domain"
+	"time"
+)
+
+// EmergencyGrant экстренный доступ: Grantee может получить доступ на чтение к хранилищу Grantor
+type EmergencyGrant struct {
+	ID uint64
+	Grantor,
+	Grantee string
+	State domain.EmergencyState
+	// Wait время, через которое запрос доступа одобряется, если владелец его не отклонил
+	Wait time.Duration
+	// RequestedAt время последнего запроса доступа, nil - доступ не запрашивался
+	RequestedAt *time.Time
+	// VaultKey ключ хранилища владельца, зашифрованный открытым ключом доверенного лица, только после одобрения
+	VaultKey string
+}
+
+// emergencyStateNames названия состояний для командной строки и интерфейса
+var emergencyStateNames = map[domain.EmergencyState]string{
+	domain.EmergencyInvited:      "invited",
+	domain.EmergencyAccepted:     "accepted",
+	domain.EmergencyRequested:    "requested",
+	domain.EmergencyApproved:     "approved",
+	domain.EmergencyRejected:     "rejected",
+	domain.EmergencyAutoApproved: "auto-approved",
+}
+
+// EmergencyStateName название состояния экстренного доступа
+func EmergencyStateName(state domain.EmergencyState) string {
+	return emergencyStateNames[state]
+}
@@ -13,9 +13,27 @@ var (
 	ErrReadingFile            = errors.New("error in reading file")
 	ErrUploadFile             = errors.New("error in upload file")
 	ErrGetDataList            = errors.New("error in get data list")
+	ErrBadPageToken           = errors.New("bad page token")
 	ErrGetData                = errors.New("error in get data")
 	ErrDataNotFound           = errors.New("data not found")
 	ErrCreationFileSaveDir    = errors.New("error in creation save dir")
 	ErrDownloadFile           = errors.New("error in download file request")
 	ErrDeleteData             = errors.New("error in delete request")
+	ErrFolderRequest          = errors.New("error in folder request")
+	ErrFolderNotFound         = errors.New("folder not found")
+	ErrSearchIndex            = errors.New("error in search index")
+	ErrNotLoggedIn            = errors.New("not logged in")
+	ErrSessionLocked          = errors.New("session locked, log in again")
+	ErrNoRuntimeDir           = errors.New("no private runtime directory, session is not saved")
+	ErrNoOTP                  = errors.New("data has no otp key")
+	ErrNoKeyPair              = errors.New("user has no key pair, log in again")
+	ErrShareNotOwner          = errors.New("only owner can share data")
+	ErrSharedReadOnly         = errors.New("data is shared read-only")
+	ErrVaultNotFound          = errors.New("organization vault not found")
+	ErrVaultReadOnly          = errors.New("organization vault is read-only for your role")
+	ErrVaultShare             = errors.New("organization records can not be shared")
+	ErrOrgRole                = errors.New("unknown organization role")
+	ErrGrantNotFound          = errors.New("emergency access not found")
+	ErrEmergencyReadOnly      = errors.New("emergency access is read-only")
+	ErrTooManyAttempts        = errors.New("too many attempts")
 )
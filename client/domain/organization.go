@@ -0,0 +1,65 @@
+package domain
+
+import "gophkeeper/server/domain"
+
+// Organization организация пользователя или приглашение в нее
+type Organization struct {
+	ID,
+	KeyVersion uint64
+	Name string
+	// CollectionKey ключ хранилища, зашифрованный открытым ключом пользователя, пустой для непринятого приглашения
+	CollectionKey string
+	Role          domain.OrgRole
+	Accepted      bool
+}
+
+// OrgMember участник организации
+type OrgMember struct {
+	Login    string
+	Role     domain.OrgRole
+	Accepted bool
+}
+
+// RemoveMember исключение участника: новый ключ хранилища для оставшихся участников
+// и записи и папки хранилища, перешифрованные на нем
+type RemoveMember struct {
+	OrgID,
+	KeyVersion uint64
+	Login string
+	// Keys новый ключ хранилища, зашифрованный открытым ключом участника, по логину
+	Keys    map[string]string
+	Records []RotatedRecord
+	Folders []Folder
+}
+
+// RotatedRecord ключ записи, слепой индекс названия и теги на новом ключе хранилища
+type RotatedRecord struct {
+	ID uint64
+	RecordKey,
+	NameHash string
+	Tags []string
+}
+
+// orgRoleNames названия ролей для командной строки и интерфейса
+var orgRoleNames = map[domain.OrgRole]string{
+	domain.OrgRoleViewer: "viewer",
+	domain.OrgRoleEditor: "editor",
+	domain.OrgRoleAdmin:  "admin",
+	domain.OrgRoleOwner:  "owner",
+}
+
+// OrgRoleName название роли участника
+func OrgRoleName(role domain.OrgRole) string {
+	return orgRoleNames[role]
+}
+
+// ParseOrgRole роль участника по названию
+func ParseOrgRole(name string) (domain.OrgRole, error) {
+	for role, n := range orgRoleNames {
+		if n == name {
+			return role, nil
+		}
+	}
+
+	return 0, ErrOrgRole
+}
@@ -0,0 +1,340 @@
+// Package generator генерация паролей и парольных фраз
+// все случайные значения берутся из crypto/rand, вместе с паролем возвращается оценка его энтропии
+package generator
+
+import (
+	"bufio"
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Режимы генерации
+type Mode int
+
+const (
+	// ModeRandom случайные символы из выбранных классов
+	ModeRandom Mode = iota
+	// ModePronounceable чередование согласных и гласных, такой пароль легче произнести и запомнить
+	ModePronounceable
+	// ModePassphrase фраза из случайных слов словаря diceware
+	ModePassphrase
+)
+
+// ModeNames названия режимов для CLI и TUI
+var ModeNames = []string{"random", "pronounceable", "passphrase"}
+
+// Ограничения параметров
+const (
+	MaxLength = 1024
+	MaxWords  = 64
+)
+
+// Классы символов
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!@#$%^&*()-_=+[]{};:,.<>?/~"
+	// ambiguousChars символы, которые легко спутать при чтении или вводе
+	ambiguousChars = "Il1O0o|`'\";:.,"
+	consonants     = "bcdfghjklmnprstvwxz"
+	vowels         = "aeiouy"
+)
+
+var (
+	ErrNoClasses = errors.New("at least one character class must be selected")
+	ErrLength    = errors.New("bad password length")
+	ErrWords     = errors.New("bad number of words")
+)
+
+// Options параметры генерации
+// Length используется для случайного и произносимого пароля, Words и Separator - для парольной фразы
+// в произносимом пароле и парольной фразе Upper делает первые буквы заглавными, а Digits добавляет цифры
+type Options struct {
+	Mode Mode
+	Length,
+	Words int
+	Separator string
+	Lower,
+	Upper,
+	Digits,
+	Symbols,
+	ExcludeAmbiguous bool
+}
+
+// DefaultOptions параметры по умолчанию
+func DefaultOptions() Options {
+	return Options{
+		Mode:             ModeRandom,
+		Length:           20,
+		Words:            6,
+		Separator:        "-",
+		Lower:            true,
+		Upper:            true,
+		Digits:           true,
+		Symbols:          true,
+		ExcludeAmbiguous: true,
+	}
+}
+
+// Result сгенерированный пароль и оценка энтропии в битах
+type Result struct {
+	Value   string
+	Entropy float64
+}
+
+// Strength словесная оценка стойкости
+func (r Result) Strength() string {
+	switch {
+	case r.Entropy < 40:
+		return "weak"
+	case r.Entropy < 60:
+		return "fair"
+	case r.Entropy < 80:
+		return "strong"
+	default:
+		return "very strong"
+	}
+}
+
+// Generate генерация пароля по параметрам
+func Generate(o Options) (Result, error) {
+	switch o.Mode {
+	case ModePronounceable:
+		return pronounceable(o)
+	case ModePassphrase:
+		return passphrase(o)
+	default:
+		return random(o)
+	}
+}
+
+// random пароль, в котором есть хотя бы один символ каждого выбранного класса
+// энтропия оценивается сверху как Length * log2(размер алфавита)
+func random(o Options) (Result, error) {
+	var classes []string
+
+	for _, c := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{o.Lower, lowerChars},
+		{o.Upper, upperChars},
+		{o.Digits, digitChars},
+		{o.Symbols, symbolChars},
+	} {
+		if !c.enabled {
+			continue
+		}
+
+		chars := c.chars
+		if o.ExcludeAmbiguous {
+			chars = exclude(chars, ambiguousChars)
+		}
+
+		classes = append(classes, chars)
+	}
+
+	if len(classes) == 0 {
+		return Result{}, ErrNoClasses
+	}
+
+	if o.Length < len(classes) || o.Length > MaxLength {
+		return Result{}, ErrLength
+	}
+
+	alphabet := strings.Join(classes, "")
+	res := make([]byte, 0, o.Length)
+
+	for _, chars := range classes {
+		c, err := pick(chars)
+		if err != nil {
+			return Result{}, err
+		}
+
+		res = append(res, c)
+	}
+
+	for len(res) < o.Length {
+		c, err := pick(alphabet)
+		if err != nil {
+			return Result{}, err
+		}
+
+		res = append(res, c)
+	}
+
+	if err := shuffle(res); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Value:   string(res),
+		Entropy: float64(o.Length) * math.Log2(float64(len(alphabet))),
+	}, nil
+}
+
+// pronounceable пароль из чередующихся согласных и гласных, при Digits последние два символа - цифры
+func pronounceable(o Options) (Result, error) {
+	if o.Length < 4 || o.Length > MaxLength {
+		return Result{}, ErrLength
+	}
+
+	cons := consonants
+	if o.ExcludeAmbiguous {
+		cons = exclude(cons, ambiguousChars)
+	}
+
+	letters := o.Length
+	if o.Digits {
+		letters -= 2
+	}
+
+	var b strings.Builder
+	var entropy float64
+
+	for i := 0; i < letters; i++ {
+		chars := cons
+		if i%2 == 1 {
+			chars = vowels
+		}
+
+		c, err := pick(chars)
+		if err != nil {
+			return Result{}, err
+		}
+
+		if i == 0 && o.Upper {
+			c = byte(unicode.ToUpper(rune(c)))
+		}
+
+		b.WriteByte(c)
+		entropy += math.Log2(float64(len(chars)))
+	}
+
+	for i := letters; i < o.Length; i++ {
+		c, err := pick(digitChars)
+		if err != nil {
+			return Result{}, err
+		}
+
+		b.WriteByte(c)
+		entropy += math.Log2(float64(len(digitChars)))
+	}
+
+	return Result{Value: b.String(), Entropy: entropy}, nil
+}
+
+// passphrase фраза из слов словаря EFF, каждое слово дает log2(7776) ≈ 12.9 бит
+func passphrase(o Options) (Result, error) {
+	if o.Words < 1 || o.Words > MaxWords {
+		return Result{}, ErrWords
+	}
+
+	list := wordList()
+	words := make([]string, o.Words)
+
+	for i := range words {
+		n, err := randInt(len(list))
+		if err != nil {
+			return Result{}, err
+		}
+
+		words[i] = list[n]
+		if o.Upper {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+
+	entropy := float64(o.Words) * math.Log2(float64(len(list)))
+
+	if o.Digits {
+		n, err := randInt(len(words))
+		if err != nil {
+			return Result{}, err
+		}
+
+		d, err := pick(digitChars)
+		if err != nil {
+			return Result{}, err
+		}
+
+		words[n] += string(d)
+		entropy += math.Log2(float64(len(digitChars)))
+	}
+
+	return Result{Value: strings.Join(words, o.Separator), Entropy: entropy}, nil
+}
+
+// eff_large_wordlist.txt - словарь EFF для diceware (https://www.eff.org/dice), лицензия CC BY 3.0 US
+//
+//go:embed eff_large_wordlist.txt
+var effWordList string
+
+var wordList = sync.OnceValue(func() []string {
+	words := make([]string, 0, 7776)
+
+	scanner := bufio.NewScanner(strings.NewReader(effWordList))
+	for scanner.Scan() {
+		if _, word, ok := strings.Cut(scanner.Text(), "\t"); ok {
+			words = append(words, word)
+		}
+	}
+
+	return words
+})
+
+// Words слова словаря парольных фраз, используются и для проверки паролей на словарные слова
+func Words() []string {
+	return wordList()
+}
+
+func exclude(chars, excluded string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(excluded, r) {
+			return -1
+		}
+
+		return r
+	}, chars)
+}
+
+// pick случайный символ строки
+func pick(chars string) (byte, error) {
+	n, err := randInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+
+	return chars[n], nil
+}
+
+// shuffle перемешивание Фишера-Йетса
+func shuffle(b []byte) error {
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return err
+		}
+
+		b[i], b[j] = b[j], b[i]
+	}
+
+	return nil
+}
+
+// randInt равномерно распределенное число в [0, n)
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(v.Int64()), nil
+}
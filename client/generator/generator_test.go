@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_Random(t *testing.T) {
+	o := DefaultOptions()
+
+	for i := 0; i < 100; i++ {
+		res, err := Generate(o)
+		require.NoError(t, err)
+
+		assert.Len(t, res.Value, o.Length)
+		assert.True(t, strings.ContainsAny(res.Value, lowerChars))
+		assert.True(t, strings.ContainsAny(res.Value, upperChars))
+		assert.True(t, strings.ContainsAny(res.Value, digitChars))
+		assert.True(t, strings.ContainsAny(res.Value, symbolChars))
+		assert.False(t, strings.ContainsAny(res.Value, ambiguousChars), res.Value)
+	}
+
+	o = Options{Length: 8, Digits: true}
+	res, err := Generate(o)
+	require.NoError(t, err)
+	assert.Equal(t, "", strings.Trim(res.Value, digitChars))
+	assert.InDelta(t, 8*math.Log2(10), res.Entropy, 0.001)
+	assert.Equal(t, "weak", res.Strength())
+
+	first, err := Generate(DefaultOptions())
+	require.NoError(t, err)
+	second, err := Generate(DefaultOptions())
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Value, second.Value)
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		o    Options
+		want error
+	}{
+		{name: "no classes", o: Options{Length: 10}, want: ErrNoClasses},
+		{name: "shorter than classes", o: Options{Length: 2, Lower: true, Upper: true, Digits: true}, want: ErrLength},
+		{name: "too long", o: Options{Length: MaxLength + 1, Lower: true}, want: ErrLength},
+		{name: "short pronounceable", o: Options{Mode: ModePronounceable, Length: 3}, want: ErrLength},
+		{name: "no words", o: Options{Mode: ModePassphrase}, want: ErrWords},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.o)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestGenerate_Pronounceable(t *testing.T) {
+	res, err := Generate(Options{Mode: ModePronounceable, Length: 12, Upper: true, Digits: true, ExcludeAmbiguous: true})
+	require.NoError(t, err)
+
+	assert.Len(t, res.Value, 12)
+	assert.True(t, strings.ContainsAny(res.Value[:1], upperChars))
+	assert.True(t, strings.ContainsAny(res.Value[1:2], vowels))
+	assert.Equal(t, "", strings.Trim(res.Value[10:], digitChars))
+	assert.NotContains(t, res.Value, "l")
+	assert.Greater(t, res.Entropy, 30.0)
+}
+
+func TestGenerate_Passphrase(t *testing.T) {
+	assert.Len(t, wordList(), 7776)
+
+	res, err := Generate(Options{Mode: ModePassphrase, Words: 6, Separator: " "})
+	require.NoError(t, err)
+
+	words := strings.Split(res.Value, " ")
+	require.Len(t, words, 6)
+	for _, w := range words {
+		assert.Contains(t, wordList(), w)
+	}
+
+	assert.InDelta(t, 6*math.Log2(7776), res.Entropy, 0.001)
+	assert.Equal(t, "strong", res.Strength())
+}
@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Типы записей Bitwarden
+const (
+	bitwardenLogin    = 1
+	bitwardenNote     = 2
+	bitwardenCard     = 3
+	bitwardenIdentity = 4
+)
+
+// bitwardenExport незашифрованный JSON экспорт Bitwarden (личный или организации)
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Collections []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"collections"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type          int      `json:"type"`
+	Name          string   `json:"name"`
+	Notes         string   `json:"notes"`
+	FolderID      string   `json:"folderId"`
+	CollectionIDs []string `json:"collectionIds"`
+	Fields        []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"fields"`
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Totp     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Brand          string `json:"brand"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity map[string]any `json:"identity"`
+}
+
+// identityFields порядок полей личных данных в meta
+var identityFields = []string{"title", "firstName", "middleName", "lastName", "company", "email", "phone", "username",
+	"address1", "address2", "address3", "city", "state", "postalCode", "country", "ssn", "passportNumber", "licenseNumber"}
+
+func parseBitwarden(data []byte) (Result, error) {
+	var (
+		export bitwardenExport
+		res    Result
+	)
+
+	if err := json.Unmarshal(data, &export); err != nil {
+		return res, fmt.Errorf("bitwarden: %w", err)
+	}
+
+	if export.Encrypted {
+		return res, ErrEncrypted
+	}
+
+	folders := make(map[string]string, len(export.Folders)+len(export.Collections))
+	for _, f := range export.Folders {
+		folders[f.ID] = f.Name
+	}
+
+	for _, c := range export.Collections {
+		folders[c.ID] = c.Name
+	}
+
+	for _, bi := range export.Items {
+		it := Item{Folder: folders[bi.FolderID]}
+		// в экспорте организации записи лежат в коллекциях
+		if it.Folder == "" && len(bi.CollectionIDs) > 0 {
+			it.Folder = folders[bi.CollectionIDs[0]]
+		}
+
+		d := &it.Data
+		d.Name = bi.Name
+		d.Text = bi.Notes
+
+		switch {
+		case bi.Type == bitwardenLogin && bi.Login != nil:
+			d.Login = bi.Login.Username
+			d.Pass = bi.Login.Password
+			d.Otp = bi.Login.Totp
+
+			for _, u := range bi.Login.URIs {
+				d.Meta = addMeta(d.Meta, "url", u.URI)
+			}
+		case bi.Type == bitwardenCard && bi.Card != nil:
+			d.CardNum = bi.Card.Number
+			d.CardExp = cardExpiry(bi.Card.ExpMonth, bi.Card.ExpYear)
+			d.Meta = addMeta(d.Meta, "cardholder", bi.Card.CardholderName)
+			d.Meta = addMeta(d.Meta, "brand", bi.Card.Brand)
+			d.Meta = addMeta(d.Meta, "cvv", bi.Card.Code)
+		case bi.Type == bitwardenIdentity:
+			for _, key := range identityFields {
+				d.Meta = addMeta(d.Meta, key, identityValue(bi.Identity[key]))
+			}
+		case bi.Type == bitwardenNote:
+		default:
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s: unknown bitwarden item type %d, only name, notes and fields imported", bi.Name, bi.Type))
+		}
+
+		for _, f := range bi.Fields {
+			d.Meta = addMeta(d.Meta, f.Name, f.Value)
+		}
+
+		res.Items = append(res.Items, it)
+	}
+
+	return res, nil
+}
+
+func identityValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return ""
+}
@@ -0,0 +1,195 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Поля записи, которым сопоставляются колонки CSV
+const (
+	fieldName   = "name"
+	fieldLogin  = "login"
+	fieldPass   = "pass"
+	fieldCard   = "card"
+	fieldExp    = "exp"
+	fieldOtp    = "otp"
+	fieldText   = "text"
+	fieldMeta   = "meta"
+	fieldFolder = "folder"
+	fieldTags   = "tags"
+)
+
+// columnAliases названия колонок, по которым поле определяется автоматически (в нижнем регистре)
+// включают заголовки экспортов Bitwarden, 1Password, LastPass, Chrome и KeePassXC
+var columnAliases = map[string][]string{
+	fieldName:   {"name", "title"},
+	fieldLogin:  {"login", "username", "user name", "user", "login_username", "email"},
+	fieldPass:   {"pass", "password", "login_password"},
+	fieldCard:   {"card", "card number", "cardnumber", "number"},
+	fieldExp:    {"exp", "expiry", "expiration", "expiration date", "expires"},
+	fieldOtp:    {"otp", "totp", "otpauth", "login_totp", "one-time password"},
+	fieldText:   {"text", "notes", "note", "extra", "comments"},
+	fieldMeta:   {"meta", "url", "uri", "website", "login_uri"},
+	fieldFolder: {"folder", "group", "grouping", "path"},
+	fieldTags:   {"tags", "tag"},
+}
+
+// ParseMapping разбор соответствия колонок вида "name=Title,login=Username"
+func ParseMapping(value string) (map[string]string, error) {
+	res := make(map[string]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		field, column, ok := strings.Cut(pair, "=")
+		field, column = strings.ToLower(strings.TrimSpace(field)), strings.TrimSpace(column)
+
+		if _, known := columnAliases[field]; !ok || !known || column == "" {
+			return nil, fmt.Errorf("%w: %q, fields: %s", ErrMapping, pair, strings.Join(mappingFields(), ", "))
+		}
+
+		res[field] = column
+	}
+
+	return res, nil
+}
+
+func mappingFields() []string {
+	res := make([]string, 0, len(columnAliases))
+	for f := range columnAliases {
+		res = append(res, f)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// parseCSV разбор CSV с заголовком
+// явно сопоставленные колонки имеют приоритет, остальные поля определяются по названиям колонок;
+// несопоставленные колонки, кроме ignore, добавляются в meta
+func parseCSV(data []byte, mapping map[string]string, ignore []string) (Result, error) {
+	var res Result
+
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return res, fmt.Errorf("csv: %w", err)
+	}
+
+	if len(rows) < 2 {
+		return res, ErrEmpty
+	}
+
+	header := rows[0]
+	columns, err := mapColumns(header, mapping)
+	if err != nil {
+		return res, err
+	}
+
+	skip := make(map[int]bool)
+	for i, h := range header {
+		for _, ig := range ignore {
+			if strings.EqualFold(strings.TrimSpace(h), ig) {
+				skip[i] = true
+			}
+		}
+	}
+
+	for _, row := range rows[1:] {
+		var it Item
+
+		for i, value := range row {
+			if i >= len(header) || skip[i] {
+				continue
+			}
+
+			field, ok := columns[i]
+			if !ok {
+				it.Data.Meta = addMeta(it.Data.Meta, strings.TrimSpace(header[i]), value)
+				continue
+			}
+
+			setCSVField(&it, field, value)
+		}
+
+		res.Items = append(res.Items, it)
+	}
+
+	return res, nil
+}
+
+// mapColumns номер колонки -> поле записи
+func mapColumns(header []string, mapping map[string]string) (map[int]string, error) {
+	res := make(map[int]string)
+	mapped := make(map[string]bool)
+
+	for field, column := range mapping {
+		found := false
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), column) {
+				res[i], mapped[field], found = field, true, true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: column %q not found", ErrMapping, column)
+		}
+	}
+
+	for i, h := range header {
+		if _, ok := res[i]; ok {
+			continue
+		}
+
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, field := range mappingFields() {
+			if mapped[field] {
+				continue
+			}
+
+			for _, alias := range columnAliases[field] {
+				if h == alias {
+					res[i], mapped[field] = field, true
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func setCSVField(it *Item, field, value string) {
+	d := &it.Data
+
+	switch field {
+	case fieldName:
+		d.Name = value
+	case fieldLogin:
+		d.Login = value
+	case fieldPass:
+		d.Pass = value
+	case fieldCard:
+		d.CardNum = value
+	case fieldExp:
+		d.CardExp = strings.TrimSpace(value)
+	case fieldOtp:
+		d.Otp = strings.TrimSpace(value)
+	case fieldText:
+		d.Text = value
+	case fieldMeta:
+		d.Meta = addMeta(d.Meta, "", value)
+	case fieldFolder:
+		it.Folder = strings.TrimSpace(value)
+	case fieldTags:
+		d.Tags = splitTags(value)
+	}
+}
@@ -0,0 +1,173 @@
+// Package importer разбор экспортов других менеджеров паролей: Bitwarden (JSON), 1Password (1PUX и CSV),
+// KeePass (KDBX 4) и CSV с произвольными колонками
+// записи разбираются в client/domain.Data, сохранением занимается client/data
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/otp"
+	"strings"
+)
+
+// Форматы импорта
+const (
+	FormatBitwarden   = "bitwarden"
+	FormatOnePassword = "1password"
+	FormatKeePass     = "keepass"
+	FormatCSV         = "csv"
+)
+
+// Formats поддерживаемые форматы
+var Formats = []string{FormatBitwarden, FormatOnePassword, FormatKeePass, FormatCSV}
+
+// FolderSeparator разделитель папок в пути, совпадает с разделителем в client/data
+const FolderSeparator = "/"
+
+var (
+	ErrFormat    = errors.New("unknown import format")
+	ErrEncrypted = errors.New("password protected export is not supported, export unencrypted file")
+	ErrMapping   = errors.New("bad csv column mapping")
+	ErrEmpty     = errors.New("nothing to import")
+)
+
+// Attachment вложение записи
+type Attachment struct {
+	Name    string
+	Content []byte
+}
+
+// Item запись для импорта
+// Folder - путь папки, Attachment - файл записи (в gophkeeper у записи может быть только один файл)
+type Item struct {
+	Data       domain.Data
+	Folder     string
+	Attachment *Attachment
+}
+
+// Result разобранные записи и предупреждения о данных, которые не удалось перенести
+type Result struct {
+	Items    []Item
+	Warnings []string
+}
+
+// Options параметры разбора
+type Options struct {
+	// Password и KeyFile мастер-ключ базы KeePass
+	Password string
+	KeyFile  []byte
+	// Mapping соответствие полей записи колонкам CSV, по умолчанию колонки определяются по заголовку
+	Mapping map[string]string
+}
+
+// Parse разбор экспорта в указанном формате
+func Parse(format string, data []byte, o Options) (Result, error) {
+	var (
+		res Result
+		err error
+	)
+
+	switch format {
+	case FormatBitwarden:
+		res, err = parseBitwarden(data)
+	case FormatOnePassword:
+		res, err = parseOnePassword(data)
+	case FormatKeePass:
+		res, err = parseKeePass(data, o)
+	case FormatCSV:
+		res, err = parseCSV(data, o.Mapping, nil)
+	default:
+		return res, fmt.Errorf("%w %q, supported: %s", ErrFormat, format, strings.Join(Formats, ", "))
+	}
+
+	if err != nil {
+		return res, err
+	}
+
+	if len(res.Items) == 0 {
+		return res, ErrEmpty
+	}
+
+	for i := range res.Items {
+		res.Items[i].normalize(i, &res)
+	}
+
+	return res, nil
+}
+
+// normalize проверка записи перед сохранением: название обязательно, некорректный ключ OTP переносится в meta
+func (it *Item) normalize(i int, res *Result) {
+	d := &it.Data
+
+	d.Name = strings.TrimSpace(d.Name)
+	if d.Name == "" {
+		d.Name = fmt.Sprintf("imported %d", i+1)
+		res.Warnings = append(res.Warnings, fmt.Sprintf("item %d has no name, saved as %q", i+1, d.Name))
+	}
+
+	if d.Otp != "" {
+		if _, err := otp.Parse(d.Otp); err != nil {
+			d.Meta = addMeta(d.Meta, "otp", d.Otp)
+			d.Otp = ""
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s: unsupported otp key moved to meta (%s)", d.Name, err.Error()))
+		}
+	}
+
+	it.Folder = strings.Trim(it.Folder, FolderSeparator)
+}
+
+// addMeta добавление строки "key: value" к meta
+func addMeta(meta, key, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return meta
+	}
+
+	line := value
+	if key != "" {
+		line = key + ": " + value
+	}
+
+	if meta == "" {
+		return line
+	}
+
+	return meta + "\n" + line
+}
+
+// folderName название папки как один элемент пути
+func folderName(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), FolderSeparator, "-")
+}
+
+// cardExpiry срок действия карты в формате MM/YY
+func cardExpiry(month, year string) string {
+	month, year = strings.TrimSpace(month), strings.TrimSpace(year)
+	if month == "" || year == "" {
+		return ""
+	}
+
+	if len(month) == 1 {
+		month = "0" + month
+	}
+
+	if len(year) == 4 {
+		year = year[2:]
+	}
+
+	return month + "/" + year
+}
+
+// splitTags теги, разделенные запятыми или точками с запятой
+func splitTags(value string) []string {
+	var res []string
+
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			res = append(res, tag)
+		}
+	}
+
+	return res
+}
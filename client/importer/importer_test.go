@@ -0,0 +1,201 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"gophkeeper/client/importer/kdbx"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestData(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestParse_Bitwarden(t *testing.T) {
+	res, err := Parse(FormatBitwarden, readTestData(t, "bitwarden.json"), Options{})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 4)
+
+	aws := res.Items[0]
+	assert.Equal(t, "Work/Infra", aws.Folder)
+	assert.Equal(t, "AWS", aws.Data.Name)
+	assert.Equal(t, "admin@example.com", aws.Data.Login)
+	assert.Equal(t, "aws-pass", aws.Data.Pass)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", aws.Data.Otp)
+	assert.Equal(t, "root account", aws.Data.Text)
+	assert.Equal(t, "url: https://console.aws.amazon.com\naccount id: 123456789012", aws.Data.Meta)
+
+	card := res.Items[1].Data
+	assert.Equal(t, "4111111111111111", card.CardNum)
+	assert.Equal(t, "07/27", card.CardExp)
+	assert.Equal(t, "cardholder: J Doe\nbrand: Visa\ncvv: 123", card.Meta)
+
+	assert.Equal(t, "guest / welcome", res.Items[2].Data.Text)
+
+	// ключ Steam Guard не поддерживается и переносится в meta
+	steam := res.Items[3].Data
+	assert.Empty(t, steam.Otp)
+	assert.Equal(t, "otp: steam://ABCDEF", steam.Meta)
+	require.Len(t, res.Warnings, 1)
+	assert.Contains(t, res.Warnings[0], "Steam")
+
+	_, err = Parse(FormatBitwarden, []byte(`{"encrypted": true, "passwordProtected": true}`), Options{})
+	assert.ErrorIs(t, err, ErrEncrypted)
+}
+
+func TestParse_OnePassword(t *testing.T) {
+	t.Run("1pux", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+
+		files := map[string]string{
+			"export.attributes": `{"version":3,"description":"1Password Unencrypted Export","createdAt":1700000000}`,
+			"export.data": `{"accounts":[{"attrs":{"accountName":"Test"},"vaults":[
+				{"attrs":{"name":"Private"},"items":[
+					{"state":"active","categoryUuid":"001",
+					 "details":{"loginFields":[
+						{"value":"octocat","name":"username","fieldType":"T","designation":"username"},
+						{"value":"gh-pass","name":"password","fieldType":"P","designation":"password"}],
+					  "notesPlain":"main account",
+					  "sections":[{"title":"","fields":[
+						{"title":"one-time password","id":"TOTP_1","value":{"totp":"otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP"}},
+						{"title":"pin","id":"pin","value":{"concealed":"4321"}},
+						{"title":"codes","id":"f1","value":{"file":{"fileName":"codes.txt","documentId":"doc1","decryptedSize":5}}}]}]},
+					 "overview":{"title":"GitHub","url":"https://github.com","urls":[{"label":"","url":"https://github.com"}],"tags":["dev"]}},
+					{"state":"archived","categoryUuid":"003","details":{"notesPlain":"old"},"overview":{"title":"Old note"}}]},
+				{"attrs":{"name":"Shared/Team"},"items":[
+					{"state":"active","categoryUuid":"002",
+					 "details":{"sections":[{"title":"","fields":[
+						{"title":"number","id":"ccnum","value":{"creditCardNumber":"4111111111111111"}},
+						{"title":"expiry date","id":"expiry","value":{"monthYear":202703}},
+						{"title":"cardholder name","id":"cardholder","value":{"string":"J Doe"}}]}]},
+					 "overview":{"title":"Team card"}}]}]}]}`,
+			"files/doc1__codes.txt": "codes",
+		}
+
+		for name, content := range files {
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, _ = w.Write([]byte(content))
+		}
+		require.NoError(t, zw.Close())
+
+		res, err := Parse(FormatOnePassword, buf.Bytes(), Options{})
+		require.NoError(t, err)
+		require.Len(t, res.Items, 2)
+
+		gh := res.Items[0]
+		assert.Equal(t, "Private", gh.Folder)
+		assert.Equal(t, "GitHub", gh.Data.Name)
+		assert.Equal(t, "octocat", gh.Data.Login)
+		assert.Equal(t, "gh-pass", gh.Data.Pass)
+		assert.Equal(t, "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP", gh.Data.Otp)
+		assert.Equal(t, "main account", gh.Data.Text)
+		assert.Equal(t, []string{"dev"}, gh.Data.Tags)
+		assert.Equal(t, "url: https://github.com\npin: 4321", gh.Data.Meta)
+		require.NotNil(t, gh.Attachment)
+		assert.Equal(t, Attachment{Name: "codes.txt", Content: []byte("codes")}, *gh.Attachment)
+
+		card := res.Items[1]
+		assert.Equal(t, "Shared-Team", card.Folder)
+		assert.Equal(t, "4111111111111111", card.Data.CardNum)
+		assert.Equal(t, "03/27", card.Data.CardExp)
+		assert.Equal(t, "cardholder name: J Doe", card.Data.Meta)
+
+		assert.Equal(t, []string{"Old note: archived item skipped"}, res.Warnings)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		data := "Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\n" +
+			"GitLab,https://gitlab.com,dev,gl-pass,,false,false,\"work,git\",\"multi\nline\"\n"
+
+		res, err := Parse(FormatOnePassword, []byte(data), Options{})
+		require.NoError(t, err)
+		require.Len(t, res.Items, 1)
+
+		d := res.Items[0].Data
+		assert.Equal(t, "GitLab", d.Name)
+		assert.Equal(t, "dev", d.Login)
+		assert.Equal(t, "gl-pass", d.Pass)
+		assert.Equal(t, "https://gitlab.com", d.Meta)
+		assert.Equal(t, []string{"work", "git"}, d.Tags)
+		assert.Equal(t, "multi\nline", d.Text)
+	})
+}
+
+func TestParse_KeePass(t *testing.T) {
+	data := readTestData(t, "keepass.kdbx")
+
+	res, err := Parse(FormatKeePass, data, Options{Password: "import-test"})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 2)
+
+	gh := res.Items[0]
+	assert.Equal(t, "", gh.Folder)
+	assert.Equal(t, "GitHub", gh.Data.Name)
+	assert.Equal(t, "octocat", gh.Data.Login)
+	assert.Equal(t, "gh-pass", gh.Data.Pass)
+	assert.Equal(t, "otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&issuer=GitHub", gh.Data.Otp)
+	assert.Equal(t, "personal account", gh.Data.Text)
+	assert.Equal(t, "url: https://github.com\nRecovery email: octo@example.com", gh.Data.Meta)
+	assert.Equal(t, []string{"dev", "oss"}, gh.Data.Tags)
+	require.NotNil(t, gh.Attachment)
+	assert.Equal(t, "recovery-codes.txt", gh.Attachment.Name)
+	assert.Equal(t, "1111-2222\n3333-4444\n", string(gh.Attachment.Content))
+
+	// записи из корзины не импортируются
+	db := res.Items[1]
+	assert.Equal(t, "Work/Servers", db.Folder)
+	assert.Equal(t, "db-primary", db.Data.Name)
+	assert.Equal(t, "pg-pass", db.Data.Pass)
+
+	assert.Equal(t, []string{`GitHub: only one file per item is supported, "avatar.png" skipped`}, res.Warnings)
+
+	_, err = Parse(FormatKeePass, data, Options{Password: "wrong"})
+	assert.ErrorIs(t, err, kdbx.ErrCredentials)
+}
+
+func TestParse_CSV(t *testing.T) {
+	data := "\xef\xbb\xbfService,Account,Secret,Card,Valid thru,Group,Comment\n" +
+		"bank,jdoe,b@nk,4111 1111 1111 1111,12/26,finance/cards,main card\n" +
+		",nobody,x,,,,\n"
+
+	mapping, err := ParseMapping("name=Service, login=Account, pass=Secret, exp=Valid thru")
+	require.NoError(t, err)
+
+	res, err := Parse(FormatCSV, []byte(data), Options{Mapping: mapping})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 2)
+
+	bank := res.Items[0]
+	assert.Equal(t, "bank", bank.Data.Name)
+	assert.Equal(t, "jdoe", bank.Data.Login)
+	assert.Equal(t, "b@nk", bank.Data.Pass)
+	assert.Equal(t, "4111 1111 1111 1111", bank.Data.CardNum)
+	assert.Equal(t, "12/26", bank.Data.CardExp)
+	assert.Equal(t, "finance/cards", bank.Folder)
+	assert.Equal(t, "Comment: main card", bank.Data.Meta)
+
+	assert.Equal(t, "imported 2", res.Items[1].Data.Name)
+	assert.Len(t, res.Warnings, 1)
+
+	_, err = Parse(FormatCSV, []byte(data), Options{Mapping: map[string]string{"name": "Absent"}})
+	assert.ErrorIs(t, err, ErrMapping)
+
+	_, err = ParseMapping("colour=Service")
+	assert.ErrorIs(t, err, ErrMapping)
+
+	_, err = Parse(FormatCSV, []byte("name,pass\n"), Options{})
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	_, err = Parse("lastpass", []byte(data), Options{})
+	assert.ErrorIs(t, err, ErrFormat)
+}
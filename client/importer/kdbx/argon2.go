@@ -0,0 +1,323 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+//
+// Derived from golang.org/x/crypto v0.23.0, files argon2/argon2.go (processBlocks, indexAlpha, phi, initHash),
+// argon2/blake2b.go (blake2bHash) and argon2/blamka_generic.go (processBlockGeneric, blamkaGeneric):
+// the unexported Argon2d variant is made available and lanes are processed sequentially.
+// The original code is distributed under the following license:
+//
+// Copyright (c) 2009 The Go Authors. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//    * Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//    * Redistributions in binary form must reproduce the above
+// copyright notice, this list of conditions and the following disclaimer
+// in the documentation and/or other materials provided with the
+// distribution.
+//    * Neither the name of Google Inc. nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+// LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+// DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+// THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package kdbx
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Argon2 (RFC 9106) с поддержкой Argon2d, который по умолчанию используется в KDBX 4
+// и отсутствует в golang.org/x/crypto/argon2; полосы вычисляются последовательно
+
+// Варианты Argon2
+const (
+	argon2d  = 0
+	argon2id = 2
+)
+
+const (
+	argon2Version = 0x13
+	// argon2SyncPoints количество отрезков в полосе
+	argon2SyncPoints = 4
+	// argon2BlockWords размер блока в 64-битных словах (1024 байта)
+	argon2BlockWords = 128
+)
+
+type argon2Block [argon2BlockWords]uint64
+
+// argon2Key вычисление ключа длиной keyLen, memory - объем памяти в КиБ
+func argon2Key(mode int, password, salt, secret, data []byte, time, memory, threads, keyLen uint32) []byte {
+	h0 := argon2InitHash(mode, password, salt, secret, data, time, memory, threads, keyLen)
+
+	memory = memory / (argon2SyncPoints * threads) * (argon2SyncPoints * threads)
+	if memory < 2*argon2SyncPoints*threads {
+		memory = 2 * argon2SyncPoints * threads
+	}
+
+	B := make([]argon2Block, memory)
+	lanes := memory / threads
+
+	// первые два блока каждой полосы
+	var buf [1024]byte
+	for lane := uint32(0); lane < threads; lane++ {
+		for i := uint32(0); i < 2; i++ {
+			binary.LittleEndian.PutUint32(h0[blake2b.Size:], i)
+			binary.LittleEndian.PutUint32(h0[blake2b.Size+4:], lane)
+			argon2Hash(buf[:], h0[:])
+
+			for w := range B[lane*lanes+i] {
+				B[lane*lanes+i][w] = binary.LittleEndian.Uint64(buf[w*8:])
+			}
+		}
+	}
+
+	argon2Fill(B, mode, time, memory, threads)
+
+	// итог - XOR последних блоков всех полос
+	final := B[memory-1]
+	for lane := uint32(0); lane < threads-1; lane++ {
+		for i, v := range B[lane*lanes+lanes-1] {
+			final[i] ^= v
+		}
+	}
+
+	for i, v := range final {
+		binary.LittleEndian.PutUint64(buf[i*8:], v)
+	}
+
+	key := make([]byte, keyLen)
+	argon2Hash(key, buf[:])
+
+	return key
+}
+
+func argon2InitHash(mode int, password, salt, secret, data []byte, time, memory, threads, keyLen uint32) [blake2b.Size + 8]byte {
+	var h0 [blake2b.Size + 8]byte
+	var params [24]byte
+	var n [4]byte
+
+	b2, _ := blake2b.New512(nil)
+
+	binary.LittleEndian.PutUint32(params[0:4], threads)
+	binary.LittleEndian.PutUint32(params[4:8], keyLen)
+	binary.LittleEndian.PutUint32(params[8:12], memory)
+	binary.LittleEndian.PutUint32(params[12:16], time)
+	binary.LittleEndian.PutUint32(params[16:20], argon2Version)
+	binary.LittleEndian.PutUint32(params[20:24], uint32(mode))
+	b2.Write(params[:])
+
+	for _, v := range [][]byte{password, salt, secret, data} {
+		binary.LittleEndian.PutUint32(n[:], uint32(len(v)))
+		b2.Write(n[:])
+		b2.Write(v)
+	}
+
+	b2.Sum(h0[:0])
+
+	return h0
+}
+
+// argon2Hash хеш-функция переменной длины H'
+func argon2Hash(out, in []byte) {
+	var b2 hash.Hash
+	if n := len(out); n < blake2b.Size {
+		b2, _ = blake2b.New(n, nil)
+	} else {
+		b2, _ = blake2b.New512(nil)
+	}
+
+	var buf [blake2b.Size]byte
+	binary.LittleEndian.PutUint32(buf[:4], uint32(len(out)))
+	b2.Write(buf[:4])
+	b2.Write(in)
+
+	if len(out) <= blake2b.Size {
+		b2.Sum(out[:0])
+		return
+	}
+
+	outLen := len(out)
+	b2.Sum(buf[:0])
+	b2.Reset()
+	copy(out, buf[:32])
+	out = out[32:]
+
+	for len(out) > blake2b.Size {
+		b2.Write(buf[:])
+		b2.Sum(buf[:0])
+		copy(out, buf[:32])
+		out = out[32:]
+		b2.Reset()
+	}
+
+	if outLen%blake2b.Size > 0 {
+		r := ((outLen + 31) / 32) - 2
+		b2, _ = blake2b.New(outLen-32*r, nil)
+	}
+
+	b2.Write(buf[:])
+	b2.Sum(out[:0])
+}
+
+func argon2Fill(B []argon2Block, mode int, time, memory, threads uint32) {
+	lanes := memory / threads
+	segments := lanes / argon2SyncPoints
+
+	for n := uint32(0); n < time; n++ {
+		for slice := uint32(0); slice < argon2SyncPoints; slice++ {
+			for lane := uint32(0); lane < threads; lane++ {
+				argon2FillSegment(B, mode, n, slice, lane, lanes, segments, time, memory, threads)
+			}
+		}
+	}
+}
+
+func argon2FillSegment(B []argon2Block, mode int, n, slice, lane, lanes, segments, time, memory, threads uint32) {
+	var addresses, in, zero argon2Block
+
+	// в Argon2id первая половина первого прохода не зависит от данных
+	independent := mode == argon2id && n == 0 && slice < argon2SyncPoints/2
+	if independent {
+		in[0] = uint64(n)
+		in[1] = uint64(lane)
+		in[2] = uint64(slice)
+		in[3] = uint64(memory)
+		in[4] = uint64(time)
+		in[5] = uint64(mode)
+	}
+
+	index := uint32(0)
+	if n == 0 && slice == 0 {
+		index = 2
+		if independent {
+			in[6]++
+			argon2Compress(&addresses, &in, &zero, false)
+			argon2Compress(&addresses, &addresses, &zero, false)
+		}
+	}
+
+	offset := lane*lanes + slice*segments + index
+
+	for index < segments {
+		prev := offset - 1
+		if index == 0 && slice == 0 {
+			prev += lanes
+		}
+
+		var random uint64
+		if independent {
+			if index%argon2BlockWords == 0 {
+				in[6]++
+				argon2Compress(&addresses, &in, &zero, false)
+				argon2Compress(&addresses, &addresses, &zero, false)
+			}
+
+			random = addresses[index%argon2BlockWords]
+		} else {
+			random = B[prev][0]
+		}
+
+		ref := argon2Index(random, lanes, segments, threads, n, slice, lane, index)
+		argon2Compress(&B[offset], &B[prev], &B[ref], true)
+
+		index, offset = index+1, offset+1
+	}
+}
+
+// argon2Index номер блока, на который ссылается вычисляемый блок
+func argon2Index(random uint64, lanes, segments, threads, n, slice, lane, index uint32) uint32 {
+	refLane := uint32(random>>32) % threads
+	if n == 0 && slice == 0 {
+		refLane = lane
+	}
+
+	m, s := 3*segments, ((slice+1)%argon2SyncPoints)*segments
+	if lane == refLane {
+		m += index
+	}
+
+	if n == 0 {
+		m, s = slice*segments, 0
+		if slice == 0 || lane == refLane {
+			m += index
+		}
+	}
+
+	if index == 0 || lane == refLane {
+		m--
+	}
+
+	p := random & 0xFFFFFFFF
+	p = (p * p) >> 32
+	p = (p * uint64(m)) >> 32
+
+	return refLane*lanes + uint32((uint64(s)+uint64(m)-(p+1))%uint64(lanes))
+}
+
+// argon2Compress функция сжатия G, при xor результат добавляется к out (проходы после первого)
+func argon2Compress(out, in1, in2 *argon2Block, xor bool) {
+	var t argon2Block
+	for i := range t {
+		t[i] = in1[i] ^ in2[i]
+	}
+
+	// строки
+	for i := 0; i < argon2BlockWords; i += 16 {
+		blamka(&t, i, i+1, i+2, i+3, i+4, i+5, i+6, i+7, i+8, i+9, i+10, i+11, i+12, i+13, i+14, i+15)
+	}
+
+	// столбцы
+	for i := 0; i < argon2BlockWords/8; i += 2 {
+		blamka(&t, i, i+1, 16+i, 16+i+1, 32+i, 32+i+1, 48+i, 48+i+1, 64+i, 64+i+1, 80+i, 80+i+1, 96+i, 96+i+1, 112+i, 112+i+1)
+	}
+
+	for i := range t {
+		v := in1[i] ^ in2[i] ^ t[i]
+		if xor {
+			out[i] ^= v
+		} else {
+			out[i] = v
+		}
+	}
+}
+
+// blamka перестановка P над 16 словами блока
+func blamka(t *argon2Block, i ...int) {
+	gb(t, i[0], i[4], i[8], i[12])
+	gb(t, i[1], i[5], i[9], i[13])
+	gb(t, i[2], i[6], i[10], i[14])
+	gb(t, i[3], i[7], i[11], i[15])
+
+	gb(t, i[0], i[5], i[10], i[15])
+	gb(t, i[1], i[6], i[11], i[12])
+	gb(t, i[2], i[7], i[8], i[13])
+	gb(t, i[3], i[4], i[9], i[14])
+}
+
+func gb(t *argon2Block, a, b, c, d int) {
+	t[a] += t[b] + 2*uint64(uint32(t[a]))*uint64(uint32(t[b]))
+	t[d] = bits.RotateLeft64(t[d]^t[a], -32)
+	t[c] += t[d] + 2*uint64(uint32(t[c]))*uint64(uint32(t[d]))
+	t[b] = bits.RotateLeft64(t[b]^t[c], -24)
+	t[a] += t[b] + 2*uint64(uint32(t[a]))*uint64(uint32(t[b]))
+	t[d] = bits.RotateLeft64(t[d]^t[a], -16)
+	t[c] += t[d] + 2*uint64(uint32(t[c]))*uint64(uint32(t[d]))
+	t[b] = bits.RotateLeft64(t[b]^t[c], -63)
+}
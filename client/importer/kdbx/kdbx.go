@@ -0,0 +1,402 @@
+// Package kdbx чтение баз KeePass в формате KDBX 4: расшифровка, проверка целостности и разбор XML
+// поддерживаются шифры AES-256, ChaCha20 и Twofish, функции ключа AES-KDF, Argon2d и Argon2id
+package kdbx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/twofish"
+)
+
+// Сигнатура файла KDBX
+const (
+	signature1 = 0x9AA2D903
+	signature2 = 0xB54BFB67
+)
+
+// Поля внешнего заголовка
+const (
+	headerEnd         = 0
+	headerCipherID    = 2
+	headerCompression = 3
+	headerMasterSeed  = 4
+	headerEncryptIV   = 7
+	headerKdfParams   = 11
+)
+
+// Поля внутреннего заголовка
+const (
+	innerEnd       = 0
+	innerStreamID  = 1
+	innerStreamKey = 2
+	innerBinary    = 3
+)
+
+// Потоковые шифры защищенных значений
+const (
+	streamSalsa20  = 2
+	streamChaCha20 = 3
+)
+
+var (
+	cipherAES256   = uuid("31c1f2e6bf714350be5805216afc5aff")
+	cipherChaCha20 = uuid("d6038a2b8b6f4cb5a524339a31dbb59a")
+	cipherTwofish  = uuid("ad68f29f576f4bb9a36ad47af965346c")
+
+	kdfAES      = uuid("c9d9f39a628a4460bf740d08c18a4fea")
+	kdfArgon2d  = uuid("ef636ddf8c29444b91f7a9a403e30a0c")
+	kdfArgon2id = uuid("9e298b1956db4773b23dfc3ec6f0a1e6")
+)
+
+var (
+	ErrSignature   = errors.New("not a keepass database")
+	ErrVersion     = errors.New("unsupported kdbx version, only kdbx 4 is supported")
+	ErrHeader      = errors.New("corrupted kdbx header")
+	ErrCredentials = errors.New("wrong keepass password or key file")
+	ErrCorrupted   = errors.New("corrupted kdbx data")
+	ErrUnsupported = errors.New("unsupported kdbx cipher or key derivation")
+)
+
+// Credentials мастер-ключ базы: пароль и (или) содержимое файла ключа
+type Credentials struct {
+	Password string
+	KeyFile  []byte
+}
+
+// header внешний заголовок
+type header struct {
+	cipherID   [16]byte
+	compressed bool
+	masterSeed []byte
+	iv         []byte
+	kdf        map[string]any
+}
+
+// Open расшифровка и разбор базы
+func Open(r io.Reader, c Credentials) (*Database, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	h, headerLen, err := readHeader(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) < headerLen+2*sha256.Size {
+		return nil, ErrHeader
+	}
+
+	sum := sha256.Sum256(raw[:headerLen])
+	if !bytes.Equal(sum[:], raw[headerLen:headerLen+sha256.Size]) {
+		return nil, ErrHeader
+	}
+
+	composite, err := compositeKey(c)
+	if err != nil {
+		return nil, err
+	}
+
+	transformed, err := transformKey(h.kdf, composite)
+	if err != nil {
+		return nil, err
+	}
+
+	encKey := sha256.Sum256(append(append([]byte{}, h.masterSeed...), transformed...))
+	hmacKey := sha512.Sum512(append(append(append([]byte{}, h.masterSeed...), transformed...), 1))
+
+	// подпись заголовка проверяет мастер-ключ до расшифровки данных
+	mac := hmac.New(sha256.New, blockKey(math.MaxUint64, hmacKey[:]))
+	mac.Write(raw[:headerLen])
+	if !hmac.Equal(mac.Sum(nil), raw[headerLen+sha256.Size:headerLen+2*sha256.Size]) {
+		return nil, ErrCredentials
+	}
+
+	payload, err := readBlocks(raw[headerLen+2*sha256.Size:], hmacKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := decrypt(h, encKey[:], payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.compressed {
+		gz, err := gzip.NewReader(bytes.NewReader(plain))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCorrupted, err.Error())
+		}
+
+		if plain, err = io.ReadAll(gz); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCorrupted, err.Error())
+		}
+	}
+
+	return readInner(plain)
+}
+
+func readHeader(raw []byte) (header, int, error) {
+	var h header
+
+	if len(raw) < 12 || binary.LittleEndian.Uint32(raw[0:4]) != signature1 || binary.LittleEndian.Uint32(raw[4:8]) != signature2 {
+		return h, 0, ErrSignature
+	}
+
+	if major := binary.LittleEndian.Uint16(raw[10:12]); major != 4 {
+		return h, 0, fmt.Errorf("%w: %d", ErrVersion, major)
+	}
+
+	pos := 12
+	for {
+		if pos+5 > len(raw) {
+			return h, 0, ErrHeader
+		}
+
+		id := raw[pos]
+		size := int(binary.LittleEndian.Uint32(raw[pos+1 : pos+5]))
+		pos += 5
+
+		if size < 0 || pos+size > len(raw) {
+			return h, 0, ErrHeader
+		}
+
+		value := raw[pos : pos+size]
+		pos += size
+
+		switch id {
+		case headerEnd:
+			if h.masterSeed == nil || h.iv == nil || h.kdf == nil {
+				return h, 0, ErrHeader
+			}
+
+			return h, pos, nil
+		case headerCipherID:
+			if len(value) != 16 {
+				return h, 0, ErrHeader
+			}
+
+			copy(h.cipherID[:], value)
+		case headerCompression:
+			if len(value) != 4 {
+				return h, 0, ErrHeader
+			}
+
+			h.compressed = binary.LittleEndian.Uint32(value) == 1
+		case headerMasterSeed:
+			if len(value) != 32 {
+				return h, 0, ErrHeader
+			}
+
+			h.masterSeed = value
+		case headerEncryptIV:
+			h.iv = value
+		case headerKdfParams:
+			var err error
+			if h.kdf, err = readVariantDictionary(value); err != nil {
+				return h, 0, err
+			}
+		}
+	}
+}
+
+// blockKey ключ HMAC блока с номером index
+func blockKey(index uint64, hmacKey []byte) []byte {
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], index)
+
+	sum := sha512.Sum512(append(n[:], hmacKey...))
+
+	return sum[:]
+}
+
+// readBlocks сборка зашифрованных данных из блоков с проверкой HMAC каждого блока
+func readBlocks(raw, hmacKey []byte) ([]byte, error) {
+	var res []byte
+
+	for index := uint64(0); ; index++ {
+		if len(raw) < sha256.Size+4 {
+			return nil, ErrCorrupted
+		}
+
+		sum := raw[:sha256.Size]
+		size := int(binary.LittleEndian.Uint32(raw[sha256.Size : sha256.Size+4]))
+		raw = raw[sha256.Size+4:]
+
+		if size < 0 || size > len(raw) {
+			return nil, ErrCorrupted
+		}
+
+		var meta [12]byte
+		binary.LittleEndian.PutUint64(meta[:8], index)
+		binary.LittleEndian.PutUint32(meta[8:], uint32(size))
+
+		mac := hmac.New(sha256.New, blockKey(index, hmacKey))
+		mac.Write(meta[:])
+		mac.Write(raw[:size])
+
+		if !hmac.Equal(mac.Sum(nil), sum) {
+			return nil, fmt.Errorf("%w: block %d", ErrCorrupted, index)
+		}
+
+		if size == 0 {
+			return res, nil
+		}
+
+		res = append(res, raw[:size]...)
+		raw = raw[size:]
+	}
+}
+
+func decrypt(h header, key, payload []byte) ([]byte, error) {
+	switch h.cipherID {
+	case cipherChaCha20:
+		c, err := chacha20.NewUnauthenticatedCipher(key, h.iv)
+		if err != nil {
+			return nil, ErrHeader
+		}
+
+		res := make([]byte, len(payload))
+		c.XORKeyStream(res, payload)
+
+		return res, nil
+	case cipherAES256:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return decryptCBC(block, h.iv, payload)
+	case cipherTwofish:
+		block, err := twofish.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return decryptCBC(block, h.iv, payload)
+	}
+
+	return nil, ErrUnsupported
+}
+
+func decryptCBC(block cipher.Block, iv, payload []byte) ([]byte, error) {
+	if len(iv) != block.BlockSize() || len(payload) == 0 || len(payload)%block.BlockSize() != 0 {
+		return nil, ErrCorrupted
+	}
+
+	res := make([]byte, len(payload))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(res, payload)
+
+	// PKCS#7
+	pad := int(res[len(res)-1])
+	if pad == 0 || pad > block.BlockSize() {
+		return nil, ErrCorrupted
+	}
+
+	for _, b := range res[len(res)-pad:] {
+		if int(b) != pad {
+			return nil, ErrCorrupted
+		}
+	}
+
+	return res[:len(res)-pad], nil
+}
+
+// readInner внутренний заголовок (ключ защищенных значений и вложения) и XML базы
+func readInner(plain []byte) (*Database, error) {
+	var (
+		streamID  uint32
+		streamKey []byte
+		binaries  [][]byte
+	)
+
+	for {
+		if len(plain) < 5 {
+			return nil, ErrCorrupted
+		}
+
+		id := plain[0]
+		size := int(binary.LittleEndian.Uint32(plain[1:5]))
+		plain = plain[5:]
+
+		if size < 0 || size > len(plain) {
+			return nil, ErrCorrupted
+		}
+
+		value := plain[:size]
+		plain = plain[size:]
+
+		switch id {
+		case innerStreamID:
+			if len(value) != 4 {
+				return nil, ErrCorrupted
+			}
+
+			streamID = binary.LittleEndian.Uint32(value)
+		case innerStreamKey:
+			streamKey = value
+		case innerBinary:
+			// первый байт - флаги вложения
+			if len(value) == 0 {
+				return nil, ErrCorrupted
+			}
+
+			binaries = append(binaries, value[1:])
+		}
+
+		if id == innerEnd {
+			break
+		}
+	}
+
+	stream, err := innerStream(streamID, streamKey)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := parseXML(plain, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	db.Binaries = binaries
+
+	return db, nil
+}
+
+func innerStream(id uint32, key []byte) (cipher.Stream, error) {
+	switch id {
+	case streamChaCha20:
+		sum := sha512.Sum512(key)
+
+		return chacha20.NewUnauthenticatedCipher(sum[:32], sum[32:44])
+	case streamSalsa20:
+		return newSalsaStream(sha256.Sum256(key)), nil
+	}
+
+	return nil, fmt.Errorf("%w: inner stream %d", ErrUnsupported, id)
+}
+
+func uuid(s string) [16]byte {
+	var res [16]byte
+
+	if n, err := hex.Decode(res[:], []byte(s)); err != nil || n != len(res) {
+		panic("bad uuid " + s)
+	}
+
+	return res
+}
@@ -0,0 +1,390 @@
+package kdbx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/twofish"
+)
+
+func TestArgon2Key(t *testing.T) {
+	// RFC 9106, раздел 5
+	password := bytes.Repeat([]byte{0x01}, 32)
+	salt := bytes.Repeat([]byte{0x02}, 16)
+	secret := bytes.Repeat([]byte{0x03}, 8)
+	data := bytes.Repeat([]byte{0x04}, 12)
+
+	assert.Equal(t, "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb",
+		hex.EncodeToString(argon2Key(argon2d, password, salt, secret, data, 3, 32, 4, 32)))
+	assert.Equal(t, "0d640df58d78766c08c037a34a8b53c9d01ef0452d75b65eb52520e96b01e659",
+		hex.EncodeToString(argon2Key(argon2id, password, salt, secret, data, 3, 32, 4, 32)))
+
+	// совпадение с golang.org/x/crypto для Argon2id без секрета
+	assert.Equal(t, argon2.IDKey(password, salt, 2, 256, 3, 32), argon2Key(argon2id, password, salt, nil, nil, 2, 256, 3, 32))
+}
+
+// testDB параметры тестовой базы
+type testDB struct {
+	cipher   [16]byte
+	kdf      map[string]any
+	stream   uint32
+	password string
+	keyFile  []byte
+	xml      string
+	binaries [][]byte
+	version  uint16
+}
+
+func aesKdfParams() map[string]any {
+	return map[string]any{"$UUID": kdfAES[:], "S": bytes.Repeat([]byte{7}, 32), "R": uint64(10)}
+}
+
+func argon2Params(id [16]byte) map[string]any {
+	return map[string]any{"$UUID": id[:], "S": bytes.Repeat([]byte{9}, 32), "P": uint32(2), "M": uint64(64 * 1024), "I": uint64(2), "V": uint32(argon2Version)}
+}
+
+const testXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta>
+		<RecycleBinEnabled>True</RecycleBinEnabled>
+		<RecycleBinUUID>YmluYmluYmluYmluYmluYg==</RecycleBinUUID>
+	</Meta>
+	<Root>
+		<Group>
+			<UUID>cm9vdHJvb3Ryb290cm9vdA==</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<UUID>ZW50cnllbnRyeWVudHJ5ZQ==</UUID>
+				<Tags>work;ci</Tags>
+				<String><Key>Title</Key><Value>GitHub</Value></String>
+				<String><Key>UserName</Key><Value>octocat</Value></String>
+				<String><Key>Password</Key><Value Protected="True">{{s3cr&lt;et}}</Value></String>
+				<String><Key>otp</Key><Value Protected="True">{{otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP}}</Value></String>
+				<Binary><Key>recovery.txt</Key><Value Ref="0"/></Binary>
+				<History>
+					<Entry>
+						<String><Key>Password</Key><Value Protected="True">{{old password}}</Value></String>
+					</Entry>
+				</History>
+			</Entry>
+			<Group>
+				<UUID>YmluYmluYmluYmluYmluYg==</UUID>
+				<Name>Recycle Bin</Name>
+			</Group>
+			<Group>
+				<UUID>d29ya3dvcmt3b3Jrd29yaw==</UUID>
+				<Name>Work</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>VPN</Value></String>
+					<String><Key>Password</Key><Value Protected="True">{{vpn-pass}}</Value></String>
+					<String><Key>Empty</Key><Value Protected="True"></Value></String>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+func writeField(buf *bytes.Buffer, id byte, value []byte) {
+	buf.WriteByte(id)
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(value)))
+	buf.Write(value)
+}
+
+func writeVariantDictionary(params map[string]any) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0x00, 0x01})
+
+	for key, v := range params {
+		var kind byte
+		var value []byte
+
+		switch v := v.(type) {
+		case uint32:
+			kind, value = variantUInt32, binary.LittleEndian.AppendUint32(nil, v)
+		case uint64:
+			kind, value = variantUInt64, binary.LittleEndian.AppendUint64(nil, v)
+		case []byte:
+			kind, value = variantByteArray, v
+		}
+
+		buf.WriteByte(kind)
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(key)))
+		buf.WriteString(key)
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(value)))
+		buf.Write(value)
+	}
+
+	buf.WriteByte(variantEnd)
+
+	return buf.Bytes()
+}
+
+// protect шифрование значений, отмеченных {{...}}, в порядке следования
+func protect(doc string, stream cipher.Stream) string {
+	var res strings.Builder
+
+	for {
+		start := strings.Index(doc, "{{")
+		if start < 0 {
+			res.WriteString(doc)
+			return res.String()
+		}
+
+		end := strings.Index(doc, "}}")
+		res.WriteString(doc[:start])
+
+		value := []byte(strings.NewReplacer("&lt;", "<").Replace(doc[start+2 : end]))
+		stream.XORKeyStream(value, value)
+		res.WriteString(base64.StdEncoding.EncodeToString(value))
+
+		doc = doc[end+2:]
+	}
+}
+
+// write запись базы по спецификации KDBX 4
+func (d testDB) write(t *testing.T) []byte {
+	masterSeed := make([]byte, 32)
+	_, _ = rand.Read(masterSeed)
+
+	iv := make([]byte, 16)
+	if d.cipher == cipherChaCha20 {
+		iv = iv[:12]
+	}
+	_, _ = rand.Read(iv)
+
+	version := d.version
+	if version == 0 {
+		version = 4
+	}
+
+	var head bytes.Buffer
+	_ = binary.Write(&head, binary.LittleEndian, uint32(signature1))
+	_ = binary.Write(&head, binary.LittleEndian, uint32(signature2))
+	_ = binary.Write(&head, binary.LittleEndian, uint16(1))
+	_ = binary.Write(&head, binary.LittleEndian, version)
+	writeField(&head, headerCipherID, d.cipher[:])
+	writeField(&head, headerCompression, binary.LittleEndian.AppendUint32(nil, 1))
+	writeField(&head, headerMasterSeed, masterSeed)
+	writeField(&head, headerEncryptIV, iv)
+	writeField(&head, headerKdfParams, writeVariantDictionary(d.kdf))
+	writeField(&head, headerEnd, []byte("\r\n\r\n"))
+
+	composite, err := compositeKey(Credentials{Password: d.password, KeyFile: d.keyFile})
+	require.NoError(t, err)
+	transformed, err := transformKey(d.kdf, composite)
+	require.NoError(t, err)
+
+	encKey := sha256.Sum256(append(append([]byte{}, masterSeed...), transformed...))
+	hmacKey := sha512.Sum512(append(append(append([]byte{}, masterSeed...), transformed...), 1))
+
+	// внутренний заголовок и XML
+	streamKey := make([]byte, 64)
+	_, _ = rand.Read(streamKey)
+
+	stream, err := innerStream(d.stream, streamKey)
+	require.NoError(t, err)
+
+	var inner bytes.Buffer
+	writeField(&inner, innerStreamID, binary.LittleEndian.AppendUint32(nil, d.stream))
+	writeField(&inner, innerStreamKey, streamKey)
+	for _, b := range d.binaries {
+		writeField(&inner, innerBinary, append([]byte{0}, b...))
+	}
+	writeField(&inner, innerEnd, nil)
+	inner.WriteString(protect(d.xml, stream))
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, _ = gz.Write(inner.Bytes())
+	require.NoError(t, gz.Close())
+
+	var payload []byte
+	switch d.cipher {
+	case cipherChaCha20:
+		c, err := chacha20.NewUnauthenticatedCipher(encKey[:], iv)
+		require.NoError(t, err)
+		payload = make([]byte, compressed.Len())
+		c.XORKeyStream(payload, compressed.Bytes())
+	default:
+		newCipher := aes.NewCipher
+		if d.cipher == cipherTwofish {
+			newCipher = func(key []byte) (cipher.Block, error) { return twofish.NewCipher(key) }
+		}
+
+		block, err := newCipher(encKey[:])
+		require.NoError(t, err)
+		pad := aes.BlockSize - compressed.Len()%aes.BlockSize
+		plain := append(compressed.Bytes(), bytes.Repeat([]byte{byte(pad)}, pad)...)
+		payload = make([]byte, len(plain))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(payload, plain)
+	}
+
+	out := bytes.NewBuffer(append([]byte{}, head.Bytes()...))
+	sum := sha256.Sum256(head.Bytes())
+	out.Write(sum[:])
+
+	mac := hmac.New(sha256.New, blockKey(math.MaxUint64, hmacKey[:]))
+	mac.Write(head.Bytes())
+	out.Write(mac.Sum(nil))
+
+	// данные делятся на два блока и завершающий пустой блок
+	half := len(payload) / 2
+	for i, block := range [][]byte{payload[:half], payload[half:], nil} {
+		meta := binary.LittleEndian.AppendUint64(nil, uint64(i))
+		meta = binary.LittleEndian.AppendUint32(meta, uint32(len(block)))
+
+		mac := hmac.New(sha256.New, blockKey(uint64(i), hmacKey[:]))
+		mac.Write(meta)
+		mac.Write(block)
+
+		out.Write(mac.Sum(nil))
+		out.Write(meta[8:])
+		out.Write(block)
+	}
+
+	return out.Bytes()
+}
+
+func TestOpen(t *testing.T) {
+	key := bytes.Repeat([]byte{0x5a}, 32)
+	keySum := sha256.Sum256(key)
+	keyFile := []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<KeyFile>
+	<Meta><Version>2.0</Version></Meta>
+	<Key><Data Hash="%X">
+		%X %X
+	</Data></Key>
+</KeyFile>`, keySum[:4], key[:16], key[16:]))
+
+	tests := []struct {
+		name string
+		db   testDB
+	}{
+		{name: "aes, aes-kdf, chacha20 stream", db: testDB{cipher: cipherAES256, kdf: aesKdfParams(), stream: streamChaCha20, password: "master"}},
+		{name: "chacha20, argon2d, salsa20 stream", db: testDB{cipher: cipherChaCha20, kdf: argon2Params(kdfArgon2d), stream: streamSalsa20, password: "master"}},
+		{name: "twofish, argon2id, key file", db: testDB{cipher: cipherTwofish, kdf: argon2Params(kdfArgon2id), stream: streamChaCha20, password: "master", keyFile: keyFile}},
+		{name: "key file only", db: testDB{cipher: cipherAES256, kdf: aesKdfParams(), stream: streamChaCha20, keyFile: keyFile}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.db.xml = testXML
+			tt.db.binaries = [][]byte{[]byte("recovery codes")}
+			raw := tt.db.write(t)
+
+			db, err := Open(bytes.NewReader(raw), Credentials{Password: tt.db.password, KeyFile: tt.db.keyFile})
+			require.NoError(t, err)
+
+			root := db.Root.Group
+			assert.Equal(t, "Database", root.Name)
+			require.Len(t, root.Entries, 1)
+
+			e := root.Entries[0]
+			assert.Equal(t, "GitHub", e.Get(FieldTitle))
+			assert.Equal(t, "octocat", e.Get(FieldUserName))
+			assert.Equal(t, "s3cr<et", e.Get(FieldPassword))
+			assert.Equal(t, "otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP", e.Get("otp"))
+			assert.Equal(t, "work;ci", e.Tags)
+			require.Len(t, e.Binaries, 1)
+			assert.Equal(t, "recovery.txt", e.Binaries[0].Key)
+			assert.Equal(t, []byte("recovery codes"), db.Binaries[e.Binaries[0].Value.Ref])
+
+			// значение из истории расшифровано раньше, иначе следующий пароль был бы испорчен
+			require.Len(t, root.Groups, 2)
+			assert.Equal(t, "vpn-pass", root.Groups[1].Entries[0].Get(FieldPassword))
+			assert.Equal(t, root.Groups[0].UUID, db.RecycleBin())
+
+			_, err = Open(bytes.NewReader(raw), Credentials{Password: "wrong", KeyFile: tt.db.keyFile})
+			assert.ErrorIs(t, err, ErrCredentials)
+
+			// порча данных обнаруживается по HMAC блока
+			corrupted := append([]byte{}, raw...)
+			corrupted[len(corrupted)-40] ^= 0xff
+			_, err = Open(bytes.NewReader(corrupted), Credentials{Password: tt.db.password, KeyFile: tt.db.keyFile})
+			assert.ErrorIs(t, err, ErrCorrupted)
+		})
+	}
+
+	t.Run("kdbx 3", func(t *testing.T) {
+		raw := testDB{cipher: cipherAES256, kdf: aesKdfParams(), stream: streamChaCha20, xml: testXML, version: 3}.write(t)
+		_, err := Open(bytes.NewReader(raw), Credentials{})
+		assert.ErrorIs(t, err, ErrVersion)
+	})
+
+	t.Run("not a database", func(t *testing.T) {
+		_, err := Open(strings.NewReader("title,username,password\n"), Credentials{})
+		assert.ErrorIs(t, err, ErrSignature)
+	})
+}
+
+func TestTransformKey_Limits(t *testing.T) {
+	with := func(params map[string]any, key string, value any) map[string]any {
+		params[key] = value
+		return params
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]any
+	}{
+		{name: "aes-kdf rounds", params: with(aesKdfParams(), "R", uint64(maxAESRounds+1))},
+		{name: "aes-kdf max rounds", params: with(aesKdfParams(), "R", uint64(math.MaxUint64))},
+		{name: "argon2 iterations", params: with(argon2Params(kdfArgon2d), "I", uint64(maxArgon2Iterations+1))},
+		{name: "argon2 max iterations", params: with(argon2Params(kdfArgon2id), "I", uint64(math.MaxUint32))},
+		{name: "argon2 memory and iterations", params: with(with(argon2Params(kdfArgon2d), "M", uint64(maxArgon2Memory)), "I", uint64(maxArgon2Work/maxArgon2Memory+1))},
+		{name: "argon2 memory", params: with(argon2Params(kdfArgon2id), "M", uint64(maxArgon2Memory+1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := transformKey(tt.params, make([]byte, 32))
+			assert.ErrorIs(t, err, ErrHeader)
+		})
+	}
+}
+
+func TestKeyFileKey(t *testing.T) {
+	raw := bytes.Repeat([]byte{0xab}, 32)
+	arbitrary := []byte("any file content")
+	arbitrarySum := sha256.Sum256(arbitrary)
+
+	tests := []struct {
+		name    string
+		file    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{name: "raw 32 bytes", file: raw, want: raw},
+		{name: "hex", file: []byte(hex.EncodeToString(raw) + "\n"), want: raw},
+		{name: "xml v1", file: []byte("<KeyFile><Meta><Version>1.00</Version></Meta><Key><Data>" + base64.StdEncoding.EncodeToString(raw) + "</Data></Key></KeyFile>"), want: raw},
+		{name: "xml v2 bad hash", file: []byte(`<KeyFile><Meta><Version>2.0</Version></Meta><Key><Data Hash="00000000">` + hex.EncodeToString(raw) + `</Data></Key></KeyFile>`), wantErr: true},
+		{name: "arbitrary file", file: arbitrary, want: arbitrarySum[:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keyFileKey(tt.file)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrCredentials)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package kdbx
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Типы значений VariantDictionary
+const (
+	variantEnd       = 0x00
+	variantUInt32    = 0x04
+	variantUInt64    = 0x05
+	variantBool      = 0x08
+	variantInt32     = 0x0C
+	variantInt64     = 0x0D
+	variantString    = 0x18
+	variantByteArray = 0x42
+)
+
+// Ограничения параметров функций преобразования ключа, чтобы испорченный заголовок не приводил
+// к выделению огромной памяти или многочасовому вычислению ключа
+const (
+	maxArgon2Memory      = 4 << 30
+	maxArgon2Parallelism = 64
+	maxArgon2Iterations  = 10000
+	// maxArgon2Work наибольший объем памяти, умноженный на количество проходов
+	maxArgon2Work = 64 << 30
+	// maxAESRounds количество раундов AES-KDF, вычисляемое за десятки секунд
+	maxAESRounds = 1 << 30
+)
+
+// compositeKey составной ключ: SHA-256 от SHA-256 пароля и ключа из файла
+func compositeKey(c Credentials) ([]byte, error) {
+	h := sha256.New()
+
+	if c.Password != "" || c.KeyFile == nil {
+		sum := sha256.Sum256([]byte(c.Password))
+		h.Write(sum[:])
+	}
+
+	if c.KeyFile != nil {
+		key, err := keyFileKey(c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		h.Write(key)
+	}
+
+	return h.Sum(nil), nil
+}
+
+// keyFile XML файл ключа KeePass версий 1.0 и 2.0
+type keyFile struct {
+	Version string `xml:"Meta>Version"`
+	Data    struct {
+		Hash  string `xml:"Hash,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"Key>Data"`
+}
+
+// keyFileKey ключ из файла: XML файл KeePass, 32 байта, 64 шестнадцатеричных символа или SHA-256 произвольного файла
+func keyFileKey(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(trimmed, []byte("<?xml")) || bytes.HasPrefix(trimmed, []byte("<KeyFile")) {
+		var kf keyFile
+		if err := xml.Unmarshal(trimmed, &kf); err == nil && kf.Data.Value != "" {
+			return xmlKeyFileKey(kf)
+		}
+	}
+
+	if len(data) == 32 {
+		return data, nil
+	}
+
+	if len(trimmed) == 64 {
+		if key, err := hex.DecodeString(string(trimmed)); err == nil {
+			return key, nil
+		}
+	}
+
+	sum := sha256.Sum256(data)
+
+	return sum[:], nil
+}
+
+func xmlKeyFileKey(kf keyFile) ([]byte, error) {
+	value := strings.Join(strings.Fields(kf.Data.Value), "")
+
+	if strings.HasPrefix(kf.Version, "2.") {
+		key, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad key file", ErrCredentials)
+		}
+
+		// в версии 2.0 хранится начало SHA-256 ключа для проверки
+		if kf.Data.Hash != "" {
+			sum := sha256.Sum256(key)
+			if !strings.EqualFold(hex.EncodeToString(sum[:4]), kf.Data.Hash) {
+				return nil, fmt.Errorf("%w: key file checksum mismatch", ErrCredentials)
+			}
+		}
+
+		return key, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad key file", ErrCredentials)
+	}
+
+	return key, nil
+}
+
+// transformKey преобразование составного ключа функцией из заголовка
+func transformKey(params map[string]any, composite []byte) ([]byte, error) {
+	rawID, _ := params["$UUID"].([]byte)
+	if len(rawID) != 16 {
+		return nil, ErrHeader
+	}
+
+	var id [16]byte
+	copy(id[:], rawID)
+
+	switch id {
+	case kdfAES:
+		seed, _ := params["S"].([]byte)
+		rounds, ok := params["R"].(uint64)
+		if len(seed) != 32 || !ok || rounds > maxAESRounds {
+			return nil, ErrHeader
+		}
+
+		return aesKDF(composite, seed, rounds)
+	case kdfArgon2d, kdfArgon2id:
+		salt, _ := params["S"].([]byte)
+		parallelism, okP := params["P"].(uint32)
+		memory, okM := params["M"].(uint64)
+		iterations, okI := params["I"].(uint64)
+		secret, _ := params["K"].([]byte)
+		data, _ := params["A"].([]byte)
+
+		if !okP || !okM || !okI || parallelism == 0 || parallelism > maxArgon2Parallelism ||
+			memory < 8*1024 || memory > maxArgon2Memory || iterations == 0 || iterations > maxArgon2Iterations ||
+			memory*iterations > maxArgon2Work {
+			return nil, ErrHeader
+		}
+
+		if version, ok := params["V"].(uint32); ok && version != argon2Version {
+			return nil, fmt.Errorf("%w: argon2 version %#x", ErrUnsupported, version)
+		}
+
+		mode := argon2d
+		if id == kdfArgon2id {
+			mode = argon2id
+		}
+
+		return argon2Key(mode, composite, salt, secret, data, uint32(iterations), uint32(memory/1024), parallelism, 32), nil
+	}
+
+	return nil, fmt.Errorf("%w: kdf %x", ErrUnsupported, id)
+}
+
+// aesKDF многократное шифрование ключа AES-256 в режиме ECB
+func aesKDF(composite, seed []byte, rounds uint64) ([]byte, error) {
+	block, err := aes.NewCipher(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	key := append([]byte{}, composite...)
+	for i := uint64(0); i < rounds; i++ {
+		block.Encrypt(key[:16], key[:16])
+		block.Encrypt(key[16:], key[16:])
+	}
+
+	sum := sha256.Sum256(key)
+
+	return sum[:], nil
+}
+
+// readVariantDictionary словарь параметров KDBX 4
+func readVariantDictionary(raw []byte) (map[string]any, error) {
+	if len(raw) < 2 || raw[1] != 1 {
+		return nil, ErrHeader
+	}
+
+	raw = raw[2:]
+	res := make(map[string]any)
+
+	for {
+		if len(raw) < 1 {
+			return nil, ErrHeader
+		}
+
+		kind := raw[0]
+		if kind == variantEnd {
+			return res, nil
+		}
+
+		if len(raw) < 5 {
+			return nil, ErrHeader
+		}
+
+		keyLen := int(binary.LittleEndian.Uint32(raw[1:5]))
+		raw = raw[5:]
+		if keyLen < 0 || keyLen+4 > len(raw) {
+			return nil, ErrHeader
+		}
+
+		key := string(raw[:keyLen])
+		valueLen := int(binary.LittleEndian.Uint32(raw[keyLen : keyLen+4]))
+		raw = raw[keyLen+4:]
+		if valueLen < 0 || valueLen > len(raw) {
+			return nil, ErrHeader
+		}
+
+		value := raw[:valueLen]
+		raw = raw[valueLen:]
+
+		switch kind {
+		case variantUInt32, variantInt32:
+			if len(value) != 4 {
+				return nil, ErrHeader
+			}
+
+			res[key] = binary.LittleEndian.Uint32(value)
+		case variantUInt64, variantInt64:
+			if len(value) != 8 {
+				return nil, ErrHeader
+			}
+
+			res[key] = binary.LittleEndian.Uint64(value)
+		case variantBool:
+			res[key] = len(value) == 1 && value[0] != 0
+		case variantString:
+			res[key] = string(value)
+		case variantByteArray:
+			res[key] = value
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package kdbx
+
+import (
+	"encoding/binary"
+
+	"golang.org/x/crypto/salsa20/salsa"
+)
+
+// salsaNonce фиксированный nonce Salsa20 для защищенных значений KeePass
+var salsaNonce = [8]byte{0xE8, 0x30, 0x09, 0x4B, 0x97, 0x20, 0x5D, 0x2A}
+
+// salsaStream потоковый Salsa20, ключевой поток вычисляется блоками по 64 байта
+type salsaStream struct {
+	key   [32]byte
+	block uint64
+	buf   [64]byte
+	pos   int
+}
+
+func newSalsaStream(key [32]byte) *salsaStream {
+	return &salsaStream{key: key, pos: 64}
+}
+
+func (s *salsaStream) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if s.pos == len(s.buf) {
+			var counter [16]byte
+			copy(counter[:8], salsaNonce[:])
+			binary.LittleEndian.PutUint64(counter[8:], s.block)
+
+			var zero [64]byte
+			salsa.XORKeyStream(s.buf[:], zero[:], &counter, &s.key)
+
+			s.block++
+			s.pos = 0
+		}
+
+		dst[i] = src[i] ^ s.buf[s.pos]
+		s.pos++
+	}
+}
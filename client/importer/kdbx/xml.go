@@ -0,0 +1,173 @@
+package kdbx
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Стандартные поля записи
+const (
+	FieldTitle    = "Title"
+	FieldUserName = "UserName"
+	FieldPassword = "Password"
+	FieldURL      = "URL"
+	FieldNotes    = "Notes"
+)
+
+// Database расшифрованная база
+// Binaries - вложения из внутреннего заголовка, записи ссылаются на них по номеру
+type Database struct {
+	Meta struct {
+		RecycleBinEnabled string `xml:"RecycleBinEnabled"`
+		RecycleBinUUID    string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Group Group `xml:"Group"`
+	} `xml:"Root"`
+	Binaries [][]byte `xml:"-"`
+}
+
+// Group группа записей
+type Group struct {
+	UUID    string  `xml:"UUID"`
+	Name    string  `xml:"Name"`
+	Entries []Entry `xml:"Entry"`
+	Groups  []Group `xml:"Group"`
+}
+
+// Entry запись, история изменений записи не разбирается
+type Entry struct {
+	UUID     string   `xml:"UUID"`
+	Tags     string   `xml:"Tags"`
+	Strings  []String `xml:"String"`
+	Binaries []Binary `xml:"Binary"`
+}
+
+// String поле записи, защищенные значения уже расшифрованы
+type String struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// Binary ссылка на вложение
+type Binary struct {
+	Key   string `xml:"Key"`
+	Value struct {
+		Ref int `xml:"Ref,attr"`
+	} `xml:"Value"`
+}
+
+// Get значение поля записи
+func (e Entry) Get(key string) string {
+	for _, s := range e.Strings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+
+	return ""
+}
+
+// RecycleBin ИД корзины, записи из которой не нужно импортировать
+func (db *Database) RecycleBin() string {
+	if !strings.EqualFold(db.Meta.RecycleBinEnabled, "true") {
+		return ""
+	}
+
+	return db.Meta.RecycleBinUUID
+}
+
+func parseXML(raw []byte, stream cipher.Stream) (*Database, error) {
+	plain, err := unprotect(raw, stream)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorrupted, err.Error())
+	}
+
+	db := new(Database)
+	if err = xml.Unmarshal(plain, db); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorrupted, err.Error())
+	}
+
+	return db, nil
+}
+
+// unprotect расшифровка защищенных значений
+// ключевой поток общий для всего документа, поэтому значения расшифровываются строго в порядке следования,
+// включая значения из истории изменений
+func unprotect(raw []byte, stream cipher.Stream) ([]byte, error) {
+	var out bytes.Buffer
+
+	dec := xml.NewDecoder(bytes.NewReader(raw))
+	enc := xml.NewEncoder(&out)
+
+	protected := false
+	var value strings.Builder
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.ProcInst:
+			// объявление кодировки повторно не нужно, документ уже в UTF-8
+			continue
+		case xml.StartElement:
+			if t.Name.Local == "Value" && isProtected(t) {
+				protected = true
+				value.Reset()
+			}
+		case xml.CharData:
+			if protected {
+				value.Write(t)
+				continue
+			}
+		case xml.EndElement:
+			if protected && t.Name.Local == "Value" {
+				protected = false
+
+				data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value.String()))
+				if err != nil {
+					return nil, err
+				}
+
+				stream.XORKeyStream(data, data)
+
+				if err = enc.EncodeToken(xml.CharData(data)); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err = enc.EncodeToken(xml.CopyToken(tok)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func isProtected(t xml.StartElement) bool {
+	for _, a := range t.Attr {
+		if a.Name.Local == "Protected" && strings.EqualFold(a.Value, "true") {
+			return true
+		}
+	}
+
+	return false
+}
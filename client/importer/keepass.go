@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"gophkeeper/client/importer/kdbx"
+	"strings"
+)
+
+// Поля записей KeePass с ключом одноразовых паролей: KeePassXC и встроенный TOTP KeePass 2.47+
+const (
+	keePassOtp        = "otp"
+	keePassTimeOtpB32 = "TimeOtp-Secret-Base32"
+)
+
+func parseKeePass(data []byte, o Options) (Result, error) {
+	var res Result
+
+	db, err := kdbx.Open(bytes.NewReader(data), kdbx.Credentials{Password: o.Password, KeyFile: o.KeyFile})
+	if err != nil {
+		return res, err
+	}
+
+	recycleBin := db.RecycleBin()
+
+	var walk func(g kdbx.Group, path []string)
+	walk = func(g kdbx.Group, path []string) {
+		if recycleBin != "" && g.UUID == recycleBin {
+			return
+		}
+
+		for _, e := range g.Entries {
+			res.Items = append(res.Items, keePassEntry(db, e, strings.Join(path, FolderSeparator), &res))
+		}
+
+		for _, child := range g.Groups {
+			walk(child, append(append([]string{}, path...), folderName(child.Name)))
+		}
+	}
+
+	// корневая группа - сама база, ее название в путь не входит
+	walk(db.Root.Group, nil)
+
+	return res, nil
+}
+
+func keePassEntry(db *kdbx.Database, e kdbx.Entry, folder string, res *Result) Item {
+	it := Item{Folder: folder}
+
+	d := &it.Data
+	d.Tags = splitTags(e.Tags)
+
+	for _, s := range e.Strings {
+		switch s.Key {
+		case kdbx.FieldTitle:
+			d.Name = s.Value
+		case kdbx.FieldUserName:
+			d.Login = s.Value
+		case kdbx.FieldPassword:
+			d.Pass = s.Value
+		case kdbx.FieldNotes:
+			d.Text = s.Value
+		case kdbx.FieldURL:
+			d.Meta = addMeta(d.Meta, "url", s.Value)
+		case keePassOtp, keePassTimeOtpB32:
+			d.Otp = strings.TrimSpace(s.Value)
+		default:
+			d.Meta = addMeta(d.Meta, s.Key, s.Value)
+		}
+	}
+
+	for i, b := range e.Binaries {
+		if i > 0 {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s: only one file per item is supported, %q skipped", d.Name, b.Key))
+			continue
+		}
+
+		if b.Value.Ref < 0 || b.Value.Ref >= len(db.Binaries) {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s: file %q not found in database", d.Name, b.Key))
+			continue
+		}
+
+		it.Attachment = &Attachment{Name: b.Key, Content: db.Binaries[b.Value.Ref]}
+	}
+
+	return it
+}
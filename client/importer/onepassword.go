@@ -0,0 +1,263 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Категории записей 1Password
+const (
+	onePasswordLogin    = "001"
+	onePasswordCard     = "002"
+	onePasswordNote     = "003"
+	onePasswordPassword = "005"
+	onePasswordDocument = "006"
+)
+
+// onePasswordDataFile файл с данными внутри архива 1PUX
+const onePasswordDataFile = "export.data"
+
+// onePasswordCSVIgnore служебные колонки CSV экспорта 1Password
+var onePasswordCSVIgnore = []string{"favorite", "archived"}
+
+type onePasswordExport struct {
+	Accounts []struct {
+		Vaults []struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []onePasswordItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePasswordItem struct {
+	State        string `json:"state"`
+	CategoryUUID string `json:"categoryUuid"`
+	Details      struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Name        string `json:"name"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain         string                   `json:"notesPlain"`
+		Password           string                   `json:"password"`
+		Sections           []onePasswordSection     `json:"sections"`
+		DocumentAttributes *onePasswordDocumentAttr `json:"documentAttributes"`
+	} `json:"details"`
+	Overview struct {
+		Title string   `json:"title"`
+		URL   string   `json:"url"`
+		Tags  []string `json:"tags"`
+		URLs  []struct {
+			URL string `json:"url"`
+		} `json:"urls"`
+	} `json:"overview"`
+}
+
+type onePasswordSection struct {
+	Title  string `json:"title"`
+	Fields []struct {
+		Title string                     `json:"title"`
+		ID    string                     `json:"id"`
+		Value map[string]json.RawMessage `json:"value"`
+	} `json:"fields"`
+}
+
+type onePasswordDocumentAttr struct {
+	FileName   string `json:"fileName"`
+	DocumentID string `json:"documentId"`
+}
+
+// parseOnePassword экспорт 1Password: архив 1PUX или CSV
+func parseOnePassword(data []byte) (Result, error) {
+	if !bytes.HasPrefix(data, []byte("PK")) {
+		return parseCSV(data, nil, onePasswordCSVIgnore)
+	}
+
+	var res Result
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return res, fmt.Errorf("1pux: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	raw, err := readZipFile(files[onePasswordDataFile])
+	if err != nil {
+		return res, fmt.Errorf("1pux: %w", err)
+	}
+
+	var export onePasswordExport
+	if err = json.Unmarshal(raw, &export); err != nil {
+		return res, fmt.Errorf("1pux: %w", err)
+	}
+
+	vaults := 0
+	for _, a := range export.Accounts {
+		vaults += len(a.Vaults)
+	}
+
+	for _, a := range export.Accounts {
+		for _, v := range a.Vaults {
+			for _, oi := range v.Items {
+				if oi.State == "archived" {
+					res.Warnings = append(res.Warnings, fmt.Sprintf("%s: archived item skipped", oi.Overview.Title))
+					continue
+				}
+
+				it := onePasswordEntry(oi, files, &res)
+				// хранилища 1Password становятся папками, если их несколько
+				if vaults > 1 {
+					it.Folder = folderName(v.Attrs.Name)
+				}
+
+				res.Items = append(res.Items, it)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func onePasswordEntry(oi onePasswordItem, files map[string]*zip.File, res *Result) Item {
+	var it Item
+
+	d := &it.Data
+	d.Name = oi.Overview.Title
+	d.Text = oi.Details.NotesPlain
+	d.Tags = oi.Overview.Tags
+
+	if len(oi.Overview.URLs) == 0 {
+		d.Meta = addMeta(d.Meta, "url", oi.Overview.URL)
+	}
+
+	for _, u := range oi.Overview.URLs {
+		d.Meta = addMeta(d.Meta, "url", u.URL)
+	}
+
+	for _, f := range oi.Details.LoginFields {
+		switch f.Designation {
+		case "username":
+			d.Login = f.Value
+		case "password":
+			d.Pass = f.Value
+		}
+	}
+
+	if oi.CategoryUUID == onePasswordPassword && d.Pass == "" {
+		d.Pass = oi.Details.Password
+	}
+
+	var attachments []onePasswordDocumentAttr
+	if oi.Details.DocumentAttributes != nil {
+		attachments = append(attachments, *oi.Details.DocumentAttributes)
+	}
+
+	for _, s := range oi.Details.Sections {
+		for _, f := range s.Fields {
+			for kind, raw := range f.Value {
+				switch {
+				case kind == "totp" && d.Otp == "":
+					_ = json.Unmarshal(raw, &d.Otp)
+				case kind == "creditCardNumber" && d.CardNum == "":
+					_ = json.Unmarshal(raw, &d.CardNum)
+				case kind == "monthYear" && f.ID == "expiry":
+					var monthYear int
+					if json.Unmarshal(raw, &monthYear) == nil && monthYear > 0 {
+						d.CardExp = cardExpiry(strconv.Itoa(monthYear%100), strconv.Itoa(monthYear/100))
+					}
+				case kind == "file":
+					var doc onePasswordDocumentAttr
+					if json.Unmarshal(raw, &doc) == nil {
+						attachments = append(attachments, doc)
+					}
+				default:
+					d.Meta = addMeta(d.Meta, f.Title, onePasswordValue(kind, raw))
+				}
+			}
+		}
+	}
+
+	for i, doc := range attachments {
+		if i > 0 {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s: only one file per item is supported, %q skipped", d.Name, doc.FileName))
+			continue
+		}
+
+		content, err := readZipFile(onePasswordFile(files, doc))
+		if err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s: file %q not found in export", d.Name, doc.FileName))
+			continue
+		}
+
+		it.Attachment = &Attachment{Name: doc.FileName, Content: content}
+	}
+
+	if oi.CategoryUUID != onePasswordLogin && oi.CategoryUUID != onePasswordCard && oi.CategoryUUID != onePasswordNote &&
+		oi.CategoryUUID != onePasswordPassword && oi.CategoryUUID != onePasswordDocument {
+		d.Meta = addMeta(d.Meta, "1password category", oi.CategoryUUID)
+	}
+
+	return it
+}
+
+// onePasswordValue строковое представление значения поля
+func onePasswordValue(kind string, raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+
+	var n int64
+	if json.Unmarshal(raw, &n) == nil {
+		if kind == "date" {
+			return time.Unix(n, 0).UTC().Format(time.DateOnly)
+		}
+
+		return strconv.FormatInt(n, 10)
+	}
+
+	// составные значения (адрес, ссылки) сохраняются как есть
+	return strings.TrimSpace(string(raw))
+}
+
+// onePasswordFile файл вложения в архиве: files/<documentId>__<fileName>
+func onePasswordFile(files map[string]*zip.File, doc onePasswordDocumentAttr) *zip.File {
+	if f, ok := files["files/"+doc.DocumentID+"__"+doc.FileName]; ok {
+		return f
+	}
+
+	for name, f := range files {
+		if path.Dir(name) == "files" && strings.HasPrefix(path.Base(name), doc.DocumentID) {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f == nil {
+		return nil, ErrEmpty
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
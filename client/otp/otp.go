@@ -0,0 +1,256 @@
+// Package otp одноразовые пароли HOTP (RFC 4226) и TOTP (RFC 6238) по ключам в формате otpauth:// URI
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Типы ключей
+const (
+	TypeTOTP = "totp"
+	TypeHOTP = "hotp"
+)
+
+// Алгоритмы HMAC
+const (
+	AlgorithmSHA1   = "SHA1"
+	AlgorithmSHA256 = "SHA256"
+	AlgorithmSHA512 = "SHA512"
+)
+
+// Значения по умолчанию из спецификации формата otpauth
+const (
+	DefaultDigits = 6
+	DefaultPeriod = 30
+)
+
+var (
+	ErrBadURI    = errors.New("bad otpauth uri")
+	ErrBadSecret = errors.New("bad otp secret")
+	ErrAlgorithm = errors.New("unsupported otp algorithm")
+	ErrDigits    = errors.New("otp digits must be from 6 to 8")
+	ErrPeriod    = errors.New("bad otp period")
+)
+
+var algorithms = map[string]func() hash.Hash{
+	AlgorithmSHA1:   sha1.New,
+	AlgorithmSHA256: sha256.New,
+	AlgorithmSHA512: sha512.New,
+}
+
+// Key ключ одноразовых паролей
+// Period используется только для TOTP, Counter - только для HOTP
+type Key struct {
+	Type,
+	Issuer,
+	Account,
+	Algorithm string
+	Secret  []byte
+	Digits  int
+	Period  int
+	Counter uint64
+}
+
+// Parse разбор otpauth:// URI
+// вместо URI можно указать только секрет в base32, тогда используется TOTP с параметрами по умолчанию
+func Parse(value string) (*Key, error) {
+	value = strings.TrimSpace(value)
+
+	if !strings.HasPrefix(strings.ToLower(value), "otpauth://") {
+		secret, err := decodeSecret(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Key{Type: TypeTOTP, Secret: secret, Algorithm: AlgorithmSHA1, Digits: DefaultDigits, Period: DefaultPeriod}, nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadURI, err.Error())
+	}
+
+	k := &Key{
+		Type:      strings.ToLower(u.Host),
+		Algorithm: AlgorithmSHA1,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+
+	if k.Type != TypeTOTP && k.Type != TypeHOTP {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrBadURI, u.Host)
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		k.Issuer, k.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+	} else {
+		k.Account = label
+	}
+
+	q := u.Query()
+
+	if k.Secret, err = decodeSecret(q.Get("secret")); err != nil {
+		return nil, err
+	}
+
+	if issuer := q.Get("issuer"); issuer != "" {
+		k.Issuer = issuer
+	}
+
+	if alg := q.Get("algorithm"); alg != "" {
+		k.Algorithm = strings.ToUpper(alg)
+	}
+
+	if digits := q.Get("digits"); digits != "" {
+		if k.Digits, err = strconv.Atoi(digits); err != nil {
+			return nil, ErrDigits
+		}
+	}
+
+	if period := q.Get("period"); period != "" {
+		if k.Period, err = strconv.Atoi(period); err != nil {
+			return nil, ErrPeriod
+		}
+	}
+
+	if k.Type == TypeHOTP {
+		if k.Counter, err = strconv.ParseUint(q.Get("counter"), 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: hotp counter is required", ErrBadURI)
+		}
+	}
+
+	return k, k.validate()
+}
+
+// URI ключ в формате otpauth://
+func (k Key) URI() string {
+	label := k.Account
+	if k.Issuer != "" {
+		label = k.Issuer + ":" + k.Account
+	}
+
+	q := url.Values{}
+	q.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(k.Secret))
+	q.Set("algorithm", k.Algorithm)
+	q.Set("digits", strconv.Itoa(k.Digits))
+
+	if k.Issuer != "" {
+		q.Set("issuer", k.Issuer)
+	}
+
+	if k.Type == TypeHOTP {
+		q.Set("counter", strconv.FormatUint(k.Counter, 10))
+	} else {
+		q.Set("period", strconv.Itoa(k.Period))
+	}
+
+	u := url.URL{Scheme: "otpauth", Host: k.Type, Path: "/" + label, RawQuery: q.Encode()}
+
+	return u.String()
+}
+
+// Code одноразовый пароль: для TOTP - на момент t, для HOTP - для текущего значения счетчика
+func (k Key) Code(t time.Time) (string, error) {
+	if err := k.validate(); err != nil {
+		return "", err
+	}
+
+	counter := k.Counter
+	if k.Type == TypeTOTP {
+		counter = uint64(t.Unix()) / uint64(k.Period)
+	}
+
+	return HOTP(k.Secret, counter, k.Digits, k.Algorithm)
+}
+
+// Remaining время до смены пароля TOTP, для HOTP - 0
+func (k Key) Remaining(t time.Time) time.Duration {
+	if k.Type != TypeTOTP || k.Period <= 0 {
+		return 0
+	}
+
+	period := int64(k.Period) * int64(time.Second)
+
+	return time.Duration(period - t.UnixNano()%period)
+}
+
+// HOTP одноразовый пароль для значения счетчика (RFC 4226)
+func HOTP(secret []byte, counter uint64, digits int, algorithm string) (string, error) {
+	newHash, ok := algorithms[algorithm]
+	if !ok {
+		return "", ErrAlgorithm
+	}
+
+	if digits < 6 || digits > 8 {
+		return "", ErrDigits
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(newHash, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// динамическое усечение
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod), nil
+}
+
+func (k Key) validate() error {
+	if _, ok := algorithms[k.Algorithm]; !ok {
+		return ErrAlgorithm
+	}
+
+	if k.Digits < 6 || k.Digits > 8 {
+		return ErrDigits
+	}
+
+	if k.Type == TypeTOTP && k.Period <= 0 {
+		return ErrPeriod
+	}
+
+	if len(k.Secret) == 0 {
+		return ErrBadSecret
+	}
+
+	return nil
+}
+
+// decodeSecret секрет в base32, регистр, пробелы и дополнение не учитываются
+func decodeSecret(value string) ([]byte, error) {
+	value = strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '=' {
+			return -1
+		}
+
+		return r
+	}, value))
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(value)
+	if err != nil || len(secret) == 0 {
+		return nil, ErrBadSecret
+	}
+
+	return secret, nil
+}
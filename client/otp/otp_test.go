@@ -0,0 +1,114 @@
+package otp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHOTP(t *testing.T) {
+	// тестовые значения из RFC 4226, приложение D
+	secret := []byte("12345678901234567890")
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, code := range want {
+		got, err := HOTP(secret, uint64(counter), 6, AlgorithmSHA1)
+		require.NoError(t, err)
+		assert.Equal(t, code, got)
+	}
+
+	_, err := HOTP(secret, 0, 5, AlgorithmSHA1)
+	assert.ErrorIs(t, err, ErrDigits)
+
+	_, err = HOTP(secret, 0, 6, "MD5")
+	assert.ErrorIs(t, err, ErrAlgorithm)
+}
+
+func TestKey_Code(t *testing.T) {
+	// тестовые значения из RFC 6238, приложение B
+	secrets := map[string]string{
+		AlgorithmSHA1:   "12345678901234567890",
+		AlgorithmSHA256: "12345678901234567890123456789012",
+		AlgorithmSHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+
+	tests := []struct {
+		unix      int64
+		algorithm string
+		want      string
+	}{
+		{unix: 59, algorithm: AlgorithmSHA1, want: "94287082"},
+		{unix: 59, algorithm: AlgorithmSHA256, want: "46119246"},
+		{unix: 59, algorithm: AlgorithmSHA512, want: "90693936"},
+		{unix: 1111111109, algorithm: AlgorithmSHA1, want: "07081804"},
+		{unix: 1111111109, algorithm: AlgorithmSHA256, want: "68084774"},
+		{unix: 1111111109, algorithm: AlgorithmSHA512, want: "25091201"},
+		{unix: 1234567890, algorithm: AlgorithmSHA1, want: "89005924"},
+		{unix: 20000000000, algorithm: AlgorithmSHA1, want: "65353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm+" "+tt.want, func(t *testing.T) {
+			k := Key{Type: TypeTOTP, Secret: []byte(secrets[tt.algorithm]), Algorithm: tt.algorithm, Digits: 8, Period: 30}
+
+			got, err := k.Code(time.Unix(tt.unix, 0))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	k, err := Parse("otpauth://totp/ACME%20Co:john@example.com?secret=" + strings.TrimRight(secret, "=") + "&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60")
+	require.NoError(t, err)
+	assert.Equal(t, &Key{
+		Type:      TypeTOTP,
+		Issuer:    "ACME Co",
+		Account:   "john@example.com",
+		Algorithm: AlgorithmSHA256,
+		Secret:    []byte("12345678901234567890"),
+		Digits:    8,
+		Period:    60,
+	}, k)
+
+	again, err := Parse(k.URI())
+	require.NoError(t, err)
+	assert.Equal(t, k, again)
+
+	k, err = Parse("otpauth://hotp/mail?secret=" + strings.ToLower(secret) + "&counter=3")
+	require.NoError(t, err)
+	assert.Equal(t, TypeHOTP, k.Type)
+	assert.Equal(t, "mail", k.Account)
+	assert.Equal(t, uint64(3), k.Counter)
+	assert.Contains(t, k.URI(), "counter=3")
+	code, err := k.Code(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "969429", code)
+	assert.Zero(t, k.Remaining(time.Now()))
+
+	// только секрет, как его раньше хранили в текстовых полях
+	k, err = Parse(" gezd gnbv gy3t qojq ")
+	require.NoError(t, err)
+	assert.Equal(t, TypeTOTP, k.Type)
+	assert.Equal(t, []byte("1234567890"), k.Secret)
+	assert.Equal(t, 10*time.Second, k.Remaining(time.Unix(50, 0)))
+
+	for _, bad := range []string{
+		"",
+		"otpauth://sms/x?secret=" + secret,
+		"otpauth://totp/x",
+		"otpauth://totp/x?secret=" + secret + "&digits=4",
+		"otpauth://totp/x?secret=" + secret + "&algorithm=MD5",
+		"otpauth://totp/x?secret=" + secret + "&period=0",
+		"otpauth://hotp/x?secret=" + secret,
+		"not base32!",
+	} {
+		_, err = Parse(bad)
+		assert.Error(t, err, bad)
+	}
+}
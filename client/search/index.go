@@ -0,0 +1,357 @@
+// Package search локальный полнотекстовый индекс по расшифрованным данным пользователя
+// Сервер хранит только шифротекст и не может искать по содержимому, поэтому индекс строится на клиенте
+// и хранится на диске зашифрованным ключом пользователя
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"gophkeeper/internal/crypto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// IndexFileName имя файла индекса в каталоге пользователя
+const IndexFileName = "search.idx"
+
+// Поля записи, по которым выполняется поиск
+const (
+	FieldName  = "name"
+	FieldLogin = "login"
+	FieldText  = "text"
+	FieldMeta  = "meta"
+)
+
+// Entry проиндексированная запись, пароли и номера карт в индекс не попадают
+type Entry struct {
+	ID      uint64 `json:"id"`
+	Version uint64 `json:"v"`
+	Name    string `json:"n"`
+	Login   string `json:"l,omitempty"`
+	Text    string `json:"t,omitempty"`
+	Meta    string `json:"m,omitempty"`
+}
+
+// Result найденная запись, Field - поле с лучшим совпадением
+type Result struct {
+	ID    uint64
+	Name  string
+	Field string
+	Score int
+}
+
+// Index индекс записей одного пользователя
+type Index struct {
+	mu      sync.RWMutex
+	entries map[uint64]Entry
+	path    string
+	key     []byte
+}
+
+// NewIndex пустой индекс, который будет сохранен в path с шифрованием ключом key
+func NewIndex(path string, key []byte) *Index {
+	return &Index{
+		entries: make(map[uint64]Entry),
+		path:    path,
+		key:     key,
+	}
+}
+
+// Open загрузка индекса с диска, если файла нет - возвращается пустой индекс
+// индекс, который не удалось расшифровать (например, после смены пароля), также начинается заново
+func Open(path string, key []byte) (*Index, error) {
+	idx := NewIndex(path, key)
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted, err := crypto.Decrypt(key, string(raw))
+	if err != nil {
+		return idx, nil
+	}
+
+	var entries []Entry
+	if err = json.Unmarshal([]byte(decrypted), &entries); err != nil {
+		return idx, nil
+	}
+
+	for _, e := range entries {
+		idx.entries[e.ID] = e
+	}
+
+	return idx, nil
+}
+
+// Save сохранение индекса на диск в зашифрованном виде
+func (i *Index) Save() error {
+	i.mu.RLock()
+	entries := make([]Entry, 0, len(i.entries))
+	for _, e := range i.entries {
+		entries = append(entries, e)
+	}
+	i.mu.RUnlock()
+
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].ID < entries[b].ID
+	})
+
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := crypto.Encrypt(i.key, raw)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(i.path), 0700); err != nil {
+		return err
+	}
+
+	// запись через временный файл, чтобы прерванное сохранение не испортило индекс
+	tmp := i.path + ".tmp"
+	if err = os.WriteFile(tmp, []byte(encrypted), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, i.path)
+}
+
+// Put добавление или обновление записи
+func (i *Index) Put(e Entry) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.entries[e.ID] = e
+}
+
+// Delete удаление записи
+func (i *Index) Delete(id uint64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.entries, id)
+}
+
+// Version версия проиндексированной записи, false - если записи нет в индексе
+func (i *Index) Version(id uint64) (uint64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	e, ok := i.entries[id]
+
+	return e.Version, ok
+}
+
+// IDs ИД всех проиндексированных записей
+func (i *Index) IDs() []uint64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	ids := make([]uint64, 0, len(i.entries))
+	for id := range i.entries {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// Len количество записей в индексе
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.entries)
+}
+
+// Search нечеткий поиск по индексу
+// запрос разбивается на слова, каждое слово должно найтись хотя бы в одном поле записи
+// слово вида field:value ищется только в указанном поле, например login:ci-bot
+func (i *Index) Search(query string, limit int) []Result {
+	terms := parseQuery(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	i.mu.RLock()
+	var res []Result
+	for _, e := range i.entries {
+		if r, ok := matchEntry(e, terms); ok {
+			res = append(res, r)
+		}
+	}
+	i.mu.RUnlock()
+
+	sort.Slice(res, func(a, b int) bool {
+		if res[a].Score != res[b].Score {
+			return res[a].Score > res[b].Score
+		}
+
+		if res[a].Name != res[b].Name {
+			return res[a].Name < res[b].Name
+		}
+
+		return res[a].ID < res[b].ID
+	})
+
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res
+}
+
+type term struct {
+	field string
+	value string
+}
+
+func parseQuery(query string) []term {
+	var terms []term
+
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		t := term{value: word}
+
+		if field, value, ok := strings.Cut(word, ":"); ok && isField(field) {
+			t = term{field: field, value: value}
+		}
+
+		if t.value != "" {
+			terms = append(terms, t)
+		}
+	}
+
+	return terms
+}
+
+func isField(field string) bool {
+	switch field {
+	case FieldName, FieldLogin, FieldText, FieldMeta:
+		return true
+	}
+
+	return false
+}
+
+// fieldWeights совпадение в названии важнее совпадения в заметке
+var fieldWeights = map[string]int{
+	FieldName:  4,
+	FieldLogin: 3,
+	FieldMeta:  2,
+	FieldText:  1,
+}
+
+func matchEntry(e Entry, terms []term) (Result, bool) {
+	fields := map[string]string{
+		FieldName:  e.Name,
+		FieldLogin: e.Login,
+		FieldText:  e.Text,
+		FieldMeta:  e.Meta,
+	}
+
+	res := Result{ID: e.ID, Name: e.Name}
+	bestFieldScore := 0
+
+	for _, t := range terms {
+		termScore := 0
+
+		for field, value := range fields {
+			if t.field != "" && t.field != field {
+				continue
+			}
+
+			score := Score(t.value, strings.ToLower(value)) * fieldWeights[field]
+			if score > termScore {
+				termScore = score
+			}
+
+			if score > bestFieldScore || (score == bestFieldScore && score > 0 && fieldWeights[field] > fieldWeights[res.Field]) {
+				bestFieldScore = score
+				res.Field = field
+			}
+		}
+
+		if termScore == 0 {
+			return Result{}, false
+		}
+
+		res.Score += termScore
+	}
+
+	return res, true
+}
+
+// Score оценка совпадения шаблона с текстом, 0 - совпадения нет
+// точное вхождение подстроки ценится выше, чем совпадение символов по порядку с пропусками,
+// начало слова и идущие подряд символы дают дополнительные очки
+func Score(pattern, text string) int {
+	if pattern == "" || text == "" {
+		return 0
+	}
+
+	if pos := strings.Index(text, pattern); pos >= 0 {
+		score := 100 + len(pattern)*2
+		if pos == 0 || isSeparator(text[pos-1]) {
+			score += 50
+		}
+
+		if len(pattern) == len(text) {
+			score += 50
+		}
+
+		return score
+	}
+
+	score := 0
+	consecutive := 0
+	ti := 0
+
+	for pi := 0; pi < len(pattern); pi++ {
+		found := false
+
+		for ; ti < len(text); ti++ {
+			if text[ti] != pattern[pi] {
+				consecutive = 0
+				continue
+			}
+
+			score++
+			if ti == 0 || isSeparator(text[ti-1]) {
+				score += 3
+			}
+
+			consecutive++
+			score += consecutive - 1
+
+			ti++
+			found = true
+
+			break
+		}
+
+		if !found {
+			return 0
+		}
+	}
+
+	return score
+}
+
+func isSeparator(c byte) bool {
+	switch c {
+	case ' ', '-', '_', '.', '/', '@', ':', ',', '\n', '\t':
+		return true
+	}
+
+	return false
+}
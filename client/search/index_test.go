@@ -0,0 +1,105 @@
+package search
+
+import (
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func testKey(secret string) []byte {
+	return pbkdf2.Key([]byte(secret), []byte("some salt"), 4096, 32, sha1.New)
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := NewIndex("", testKey("key"))
+	idx.Put(Entry{ID: 1, Name: "GitHub", Login: "ci-bot", Meta: "https://github.com"})
+	idx.Put(Entry{ID: 2, Name: "Gitlab runner", Login: "deploy", Text: "token for ci"})
+	idx.Put(Entry{ID: 3, Name: "Bank card", Meta: "visa"})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []uint64
+	}{
+		{name: "substring", query: "git", want: []uint64{1, 2}},
+		{name: "fuzzy", query: "gthb", want: []uint64{1}},
+		{name: "all terms must match", query: "git visa", want: nil},
+		{name: "field prefix", query: "login:ci-bot", want: []uint64{1}},
+		{name: "field prefix excludes other fields", query: "name:ci", want: nil},
+		{name: "note text", query: "token", want: []uint64{2}},
+		{name: "empty query", query: "  ", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []uint64
+			for _, r := range idx.Search(tt.query, 10) {
+				got = append(got, r.ID)
+			}
+
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestIndex_SearchRanking(t *testing.T) {
+	idx := NewIndex("", testKey("key"))
+	idx.Put(Entry{ID: 1, Name: "notes", Text: "mail server password"})
+	idx.Put(Entry{ID: 2, Name: "mail"})
+	idx.Put(Entry{ID: 3, Name: "main log"})
+
+	res := idx.Search("mail", 10)
+	require.Len(t, res, 3)
+	assert.Equal(t, uint64(2), res[0].ID)
+	assert.Equal(t, FieldName, res[0].Field)
+	assert.Equal(t, uint64(3), res[2].ID)
+
+	assert.Len(t, idx.Search("mail", 1), 1)
+}
+
+func TestScore(t *testing.T) {
+	assert.Zero(t, Score("abc", "acb"))
+	assert.Greater(t, Score("git", "github"), Score("git", "legit"))
+	assert.Greater(t, Score("hub", "github"), Score("gthb", "github"))
+	assert.Positive(t, Score("gthb", "github"))
+}
+
+func TestIndex_SaveOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user", IndexFileName)
+	key := testKey("key")
+
+	idx := NewIndex(path, key)
+	idx.Put(Entry{ID: 1, Version: 3, Name: "GitHub", Login: "ci-bot"})
+	idx.Put(Entry{ID: 2, Version: 1, Name: "Bank"})
+	idx.Delete(2)
+	require.NoError(t, idx.Save())
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "GitHub")
+	assert.NotContains(t, string(raw), "ci-bot")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := Open(path, key)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loaded.Len())
+
+	version, ok := loaded.Version(1)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), version)
+
+	other, err := Open(path, testKey("other"))
+	require.NoError(t, err)
+	assert.Zero(t, other.Len())
+
+	missing, err := Open(filepath.Join(t.TempDir(), IndexFileName), key)
+	require.NoError(t, err)
+	assert.Zero(t, missing.Len())
+}
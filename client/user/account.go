@@ -0,0 +1,38 @@
+package user
+
+import (
+	"context"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/client/workers/grpc/interceptors"
+	"time"
+)
+
+// DeleteAccount удаление учетной записи текущего пользователя с подтверждением паролем
+// graceDays 0 - данные удаляются сразу, иначе удаление можно отменить через RestoreAccount в течение graceDays дней;
+// в обоих случаях сессия завершается, возвращается время окончательного удаления, нулевое - учетная запись уже удалена
+func DeleteAccount(pass string, graceDays uint32) (time.Time, error) {
+	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+
+	purgeAt, err := client.AppInstance.UserClient.DeleteAccount(ctx, pass, graceDays)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return purgeAt, Logout()
+}
+
+// RestoreAccount отмена запланированного удаления учетной записи и вход в нее
+func RestoreAccount(login, pass string) error {
+	if err := validateRegisterCredential(login, pass); err != nil {
+		return err
+	}
+
+	token, refreshToken, err := client.AppInstance.UserClient.RestoreAccount(login, pass)
+	if err != nil {
+		return err
+	}
+
+	signIn(login, pass, token, refreshToken)
+
+	return nil
+}
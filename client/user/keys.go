@@ -0,0 +1,36 @@
+package user
+
+import (
+	"context"
+	"encoding/base64"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/client/workers/grpc/interceptors"
+	"gophkeeper/internal/crypto"
+	domain2 "gophkeeper/server/domain"
+)
+
+// ensureKeys создание пары ключей для обмена записями, если у пользователя ее еще нет
+// закрытый ключ хранится на сервере зашифрованным ключом хранилища
+func ensureKeys() error {
+	ctx := context.WithValue(context.Background(), interceptors.ContextUserTokenKey{}, client.AppInstance.User.Token)
+
+	keys, err := client.AppInstance.UserClient.GetKeys(ctx)
+	if err != nil || keys.PublicKey != "" {
+		return err
+	}
+
+	public, private, err := crypto.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := crypto.Encrypt(client.AppInstance.User.StorageKey, private)
+	if err != nil {
+		return err
+	}
+
+	return client.AppInstance.UserClient.SetKeys(ctx, domain2.UserKeys{
+		PublicKey:  base64.StdEncoding.EncodeToString(public),
+		PrivateKey: wrapped,
+	})
+}
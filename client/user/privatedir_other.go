@@ -0,0 +1,13 @@
+//go:build !unix
+
+package user
+
+import "gophkeeper/client/domain"
+
+// noFollow на остальных платформах флага нет
+const noFollow = 0
+
+// checkPrivateDir на остальных платформах владельца каталога проверить нельзя, поэтому сессия не сохраняется
+func checkPrivateDir(string) error {
+	return domain.ErrNoRuntimeDir
+}
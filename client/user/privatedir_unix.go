@@ -0,0 +1,28 @@
+//go:build unix
+
+package user
+
+import (
+	"gophkeeper/client/domain"
+	"os"
+	"syscall"
+)
+
+// noFollow временный файл сессии не открывается через символическую ссылку
+const noFollow = syscall.O_NOFOLLOW
+
+// checkPrivateDir каталог dir - не символическая ссылка, принадлежит текущему пользователю и доступен только ему
+// каталог в общем /tmp мог заранее создать другой пользователь, тогда секрет разблокировки в нем не хранится
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !info.IsDir() || !ok || int(st.Uid) != os.Getuid() || info.Mode().Perm() != 0700 {
+		return domain.ErrNoRuntimeDir
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package user
 
 import (
 	"gophkeeper/client/domain"
+	"gophkeeper/internal"
 	"gophkeeper/internal/client"
 	"unicode/utf8"
 )
@@ -17,7 +18,7 @@ type Service struct{}
 
 // Auth метода для регистрации/авторизации пользователя
 func Auth(login, pass string, isLogin bool) error {
-	var token string
+	var token, refreshToken string
 
 	err := validateRegisterCredential(login, pass)
 	if err != nil {
@@ -25,26 +26,42 @@ func Auth(login, pass string, isLogin bool) error {
 	}
 
 	if isLogin {
-		token, err = client.AppInstance.UserClient.Login(login, pass)
+		token, refreshToken, err = client.AppInstance.UserClient.Login(login, pass)
 	} else {
-		token, err = client.AppInstance.UserClient.Registration(login, pass)
+		token, refreshToken, err = client.AppInstance.UserClient.Registration(login, pass)
 	}
 	if err != nil {
 		return err
 	}
 
+	signIn(login, pass, token, refreshToken)
+
+	return nil
+}
+
+// signIn сохранение авторизации пользователя после успешного входа
+func signIn(login, pass, token, refreshToken string) {
 	client.AppInstance.User.Token = token
+	client.AppInstance.User.RefreshToken = refreshToken
 	client.AppInstance.User.Login = login
+	client.AppInstance.User.PrivateKey = nil
 	client.AppInstance.SetStorageKey(login, pass)
 
-	return nil
+	// без пары ключей пользователю нельзя предоставить доступ к записи, но вход от этого не зависит
+	if err := ensureKeys(); err != nil {
+		internal.Logger.Errorw("error while creating user keys", "error", err)
+	}
 }
 
 // ResetUser сброс данных пользователя после деавторизации
 func ResetUser() {
 	client.AppInstance.User.Login = ""
 	client.AppInstance.User.Token = ""
+	client.AppInstance.User.RefreshToken = ""
 	client.AppInstance.User.StorageKey = nil
+	client.AppInstance.User.PrivateKey = nil
+	client.AppInstance.SearchIndex = nil
+	client.AppInstance.Vault = nil
 }
 
 func validateRegisterCredential(login, pass string) error {
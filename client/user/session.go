@@ -0,0 +1,246 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/crypto"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Файлы сессии: зашифрованная сессия хранится в каталоге настроек,
+// а секрет для ее расшифровки - в каталоге времени выполнения, который очищается при перезагрузке
+const (
+	SessionFileName = "session"
+	UnlockFileName  = "unlock"
+)
+
+// tokenRefreshMargin токен доступа обновляется, если до его истечения осталось меньше этого времени
+const tokenRefreshMargin = 5 * time.Minute
+
+// sessionFile содержимое файла сессии, Data зашифрован секретом разблокировки
+type sessionFile struct {
+	Login string `json:"login"`
+	Data  string `json:"data"`
+}
+
+// sessionData данные авторизации, которые сохраняются между запусками клиента
+type sessionData struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	StorageKey   []byte    `json:"storage_key"`
+	LockAt       time.Time `json:"lock_at"`
+}
+
+// SaveSession сохранение текущей авторизации до истечения времени бездействия
+// при каждом сохранении создается новый секрет разблокировки, старые копии файла сессии становятся бесполезны
+func SaveSession() error {
+	if client.AppInstance.LockAfter <= 0 || client.AppInstance.ConfigDir == "" {
+		return nil
+	}
+
+	raw, err := json.Marshal(sessionData{
+		Token:        client.AppInstance.User.Token,
+		RefreshToken: client.AppInstance.User.RefreshToken,
+		StorageKey:   client.AppInstance.User.StorageKey,
+		LockAt:       time.Now().Add(client.AppInstance.LockAfter),
+	})
+	if err != nil {
+		return err
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return err
+	}
+
+	encrypted, err := crypto.Encrypt(secret, raw)
+	if err != nil {
+		return err
+	}
+
+	file, err := json.Marshal(sessionFile{Login: client.AppInstance.User.Login, Data: encrypted})
+	if err != nil {
+		return err
+	}
+
+	// секрет разблокировки хранится только в каталоге, который не мог подготовить другой пользователь
+	if err = os.MkdirAll(client.AppInstance.RuntimeDir, 0700); err != nil {
+		return err
+	}
+
+	if err = checkPrivateDir(client.AppInstance.RuntimeDir); err != nil {
+		return err
+	}
+
+	if err = writePrivateFile(unlockPath(), secret); err != nil {
+		return err
+	}
+
+	return writePrivateFile(sessionPath(), file)
+}
+
+// LoadSession восстановление сохраненной авторизации
+// истекший по бездействию сеанс блокируется, токен доступа при необходимости обновляется
+func LoadSession() error {
+	raw, err := os.ReadFile(sessionPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return domain.ErrNotLoggedIn
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var file sessionFile
+	if err = json.Unmarshal(raw, &file); err != nil {
+		return lock()
+	}
+
+	if err = checkPrivateDir(client.AppInstance.RuntimeDir); err != nil {
+		return lock()
+	}
+
+	secret, err := os.ReadFile(unlockPath())
+	if err != nil {
+		return lock()
+	}
+
+	decrypted, err := crypto.Decrypt(secret, file.Data)
+	if err != nil {
+		return lock()
+	}
+
+	var data sessionData
+	if err = json.Unmarshal([]byte(decrypted), &data); err != nil || time.Now().After(data.LockAt) {
+		return lock()
+	}
+
+	client.AppInstance.User.Login = file.Login
+	client.AppInstance.User.Token = data.Token
+	client.AppInstance.User.RefreshToken = data.RefreshToken
+	client.AppInstance.User.StorageKey = data.StorageKey
+
+	return KeepSession()
+}
+
+// KeepSession продление сессии после действий пользователя
+func KeepSession() error {
+	if err := RefreshToken(); err != nil {
+		if errors.Is(err, domain.ErrSessionLocked) {
+			return lock()
+		}
+
+		return err
+	}
+
+	return SaveSession()
+}
+
+// RefreshToken обновление токена доступа, если он скоро истекает
+// если сервер не принял токен обновления, авторизация сбрасывается и возвращается ErrSessionLocked
+func RefreshToken() error {
+	if !tokenExpiresSoon(client.AppInstance.User.Token) {
+		return nil
+	}
+
+	token, refreshToken, err := client.AppInstance.UserClient.Refresh(client.AppInstance.User.RefreshToken)
+	if status.Code(err) == codes.Unauthenticated || status.Code(err) == codes.InvalidArgument {
+		ResetUser()
+		return domain.ErrSessionLocked
+	}
+
+	if err != nil {
+		return err
+	}
+
+	client.AppInstance.User.Token = token
+	client.AppInstance.User.RefreshToken = refreshToken
+
+	return nil
+}
+
+// LockSession удаление сохраненной сессии, после блокировки нужно снова ввести пароль
+func LockSession() error {
+	errSecret := os.Remove(unlockPath())
+	if errors.Is(errSecret, os.ErrNotExist) {
+		errSecret = nil
+	}
+
+	errSession := os.Remove(sessionPath())
+	if errors.Is(errSession, os.ErrNotExist) {
+		errSession = nil
+	}
+
+	return errors.Join(errSecret, errSession)
+}
+
+// Logout выход пользователя с удалением сохраненной сессии
+func Logout() error {
+	ResetUser()
+
+	return LockSession()
+}
+
+// lock блокировка сессии, которую нельзя использовать
+func lock() error {
+	if err := LockSession(); err != nil {
+		internal.Logger.Errorw("error locking session", "error", err)
+	}
+
+	return domain.ErrSessionLocked
+}
+
+// tokenExpiresSoon истекает ли токен доступа в ближайшее время
+// подпись не проверяется, это делает сервер, токен без срока действия не обновляется
+func tokenExpiresSoon(token string) bool {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil || claims.ExpiresAt == nil {
+		return false
+	}
+
+	return time.Until(claims.ExpiresAt.Time) < tokenRefreshMargin
+}
+
+// writePrivateFile запись файла, доступного только владельцу
+// временный файл создается заново без перехода по символическим ссылкам, затем атомарно заменяет path
+func writePrivateFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	// оставшийся от прерванной записи файл или подложенная ссылка удаляются, цель ссылки не затрагивается
+	tmp := path + ".tmp"
+	if err := os.Remove(tmp); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL|noFollow, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err = errors.Join(err, f.Close()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func sessionPath() string {
+	return filepath.Join(client.AppInstance.ConfigDir, SessionFileName)
+}
+
+func unlockPath() string {
+	return filepath.Join(client.AppInstance.RuntimeDir, UnlockFileName)
+}
@@ -0,0 +1,192 @@
+package user
+
+import (
+	"gophkeeper/client/domain"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/server/auth"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initSessionApp(t *testing.T) {
+	internal.InitLogger()
+
+	client.AppInstance = &client.App{
+		ConfigDir:  t.TempDir(),
+		RuntimeDir: filepath.Join(t.TempDir(), "runtime"),
+		LockAfter:  time.Minute,
+	}
+
+	token, err := auth.BuildJWTString(1)
+	require.NoError(t, err)
+
+	client.AppInstance.User = client.AppUser{
+		Login:        "test",
+		Token:        token,
+		RefreshToken: "refresh",
+	}
+	client.AppInstance.SetStorageKey("test", "testtest")
+}
+
+func TestSession(t *testing.T) {
+	initSessionApp(t)
+	user := client.AppInstance.User
+
+	require.ErrorIs(t, LoadSession(), domain.ErrNotLoggedIn)
+	require.NoError(t, SaveSession())
+
+	raw, err := os.ReadFile(sessionPath())
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), user.Token)
+	assert.NotContains(t, string(raw), "refresh")
+
+	info, err := os.Stat(unlockPath())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	secret, err := os.ReadFile(unlockPath())
+	require.NoError(t, err)
+
+	ResetUser()
+	require.NoError(t, LoadSession())
+	assert.Equal(t, user, client.AppInstance.User)
+
+	// после использования секрет разблокировки меняется
+	rotated, err := os.ReadFile(unlockPath())
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, rotated)
+
+	require.NoError(t, Logout())
+	assert.Empty(t, client.AppInstance.User.Token)
+	assert.ErrorIs(t, LoadSession(), domain.ErrNotLoggedIn)
+}
+
+func TestSession_Lock(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T)
+	}{
+		{
+			name: "idle timeout",
+			prepare: func(t *testing.T) {
+				client.AppInstance.LockAfter = time.Millisecond
+				require.NoError(t, SaveSession())
+				time.Sleep(5 * time.Millisecond)
+			},
+		},
+		{
+			name: "unlock secret removed after reboot",
+			prepare: func(t *testing.T) {
+				require.NoError(t, SaveSession())
+				require.NoError(t, os.RemoveAll(client.AppInstance.RuntimeDir))
+			},
+		},
+		{
+			name: "session from another unlock secret",
+			prepare: func(t *testing.T) {
+				require.NoError(t, SaveSession())
+				secret, err := os.ReadFile(unlockPath())
+				require.NoError(t, err)
+
+				require.NoError(t, SaveSession())
+				require.NoError(t, os.WriteFile(unlockPath(), secret, 0600))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initSessionApp(t)
+			tt.prepare(t)
+
+			ResetUser()
+			assert.ErrorIs(t, LoadSession(), domain.ErrSessionLocked)
+			assert.Empty(t, client.AppInstance.User.StorageKey)
+
+			_, err := os.Stat(filepath.Join(client.AppInstance.ConfigDir, SessionFileName))
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestSaveSession_Disabled(t *testing.T) {
+	initSessionApp(t)
+	client.AppInstance.LockAfter = 0
+
+	require.NoError(t, SaveSession())
+	assert.ErrorIs(t, LoadSession(), domain.ErrNotLoggedIn)
+}
+
+func TestSaveSession_RuntimeDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T) string
+	}{
+		{
+			name: "shared permissions",
+			prepare: func(t *testing.T) string {
+				dir := filepath.Join(t.TempDir(), "runtime")
+				require.NoError(t, os.Mkdir(dir, 0700))
+				require.NoError(t, os.Chmod(dir, 0755))
+
+				return dir
+			},
+		},
+		{
+			name: "symlink",
+			prepare: func(t *testing.T) string {
+				target := filepath.Join(t.TempDir(), "target")
+				require.NoError(t, os.Mkdir(target, 0700))
+				dir := filepath.Join(t.TempDir(), "runtime")
+				require.NoError(t, os.Symlink(target, dir))
+
+				return dir
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initSessionApp(t)
+			client.AppInstance.RuntimeDir = tt.prepare(t)
+
+			assert.ErrorIs(t, SaveSession(), domain.ErrNoRuntimeDir)
+
+			_, err := os.Stat(sessionPath())
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+
+	t.Run("temp file symlink", func(t *testing.T) {
+		initSessionApp(t)
+
+		// подложенная ссылка на временный файл не должна привести к записи в чужой файл
+		victim := filepath.Join(t.TempDir(), "victim")
+		require.NoError(t, os.WriteFile(victim, []byte("data"), 0600))
+		require.NoError(t, os.Mkdir(client.AppInstance.RuntimeDir, 0700))
+		require.NoError(t, os.Symlink(victim, unlockPath()+".tmp"))
+
+		require.NoError(t, SaveSession())
+
+		raw, err := os.ReadFile(victim)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(raw))
+
+		info, err := os.Lstat(unlockPath())
+		require.NoError(t, err)
+		assert.True(t, info.Mode().IsRegular())
+	})
+}
+
+func Test_tokenExpiresSoon(t *testing.T) {
+	token, err := auth.BuildJWTString(1)
+	require.NoError(t, err)
+
+	assert.False(t, tokenExpiresSoon(token))
+	assert.False(t, tokenExpiresSoon("not a token"))
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"gophkeeper/internal"
 	"gophkeeper/internal/client"
+	"gophkeeper/internal/client/cli"
 	"gophkeeper/internal/client/view"
 	"os"
 
@@ -29,6 +31,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	// с указанной командой клиент работает без интерфейса, например в скриптах
+	if args := flag.Args(); len(args) > 0 {
+		os.Exit(cli.Run(args, os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	if len(os.Getenv("DEBUG")) > 0 {
 		var f *os.File
 		f, err = tea.LogToFile("debug.log", "debug")
@@ -39,7 +46,14 @@ func main() {
 		defer f.Close()
 	}
 
-	if _, err = tea.NewProgram(view.RootModel{BuildDate: buildDate, BuildVersion: buildVersion}).Run(); err != nil {
+	_, err = tea.NewProgram(view.InitStartModel(buildVersion, buildDate)).Run()
+
+	// скопированный секрет не должен остаться в буфере обмена после выхода
+	if clearErr := client.AppInstance.Clipboard.Clear(); clearErr != nil {
+		internal.Logger.Errorw("error clearing clipboard", "error", clearErr)
+	}
+
+	if err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
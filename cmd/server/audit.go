@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gophkeeper/internal/crypto"
+	"gophkeeper/internal/server"
+	"gophkeeper/server/audit"
+	"os"
+	"time"
+)
+
+// runVerifyAudit проверка цепочки хешей журнала аудита и подписей отметок ключом сервера
+// перед проверкой подписывается отметка последнего события, чтобы зафиксировать проверенное состояние
+func runVerifyAudit(ctx context.Context, app *server.App) error {
+	ch, err := crypto.NewCipher(app.CryptoKeysPath)
+	if err != nil {
+		return err
+	}
+
+	repos, err := initRepositories(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	service := audit.NewService(repos.audit, repos.user, ch)
+
+	report, err := service.Verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = service.Checkpoint(ctx); err != nil {
+		return err
+	}
+
+	last := "none"
+	if report.LastCheckpoint != nil {
+		last = report.LastCheckpoint.Format(time.RFC3339)
+	}
+
+	fmt.Fprintf(os.Stderr, "audit log verified: %d events, %d checkpoints, last checkpoint %s\n", report.Events, report.Checkpoints, last)
+
+	if report.Legacy > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d events were recorded before hash chaining and are not verified\n", report.Legacy)
+	}
+
+	return nil
+}
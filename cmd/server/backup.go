@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gophkeeper/internal/server"
+	"gophkeeper/internal/server/backup"
+	"gophkeeper/internal/server/repository/pgsql"
+	"gophkeeper/internal/server/repository/sqlite"
+	"io"
+	"os"
+	"time"
+)
+
+// runCommand выполнение служебной команды вместо запуска сервера, итог и ошибки выводятся в stderr
+// backup <файл> - архив базы и файлов пользователей, restore <файл> - восстановление архива в пустое хранилище, "-" - stdout/stdin
+// verify-audit - проверка, что журнал аудита не изменялся
+func runCommand(ctx context.Context, app *server.App) error {
+	name := app.Command[0]
+	if name == "verify-audit" && len(app.Command) == 1 {
+		return runVerifyAudit(ctx, app)
+	}
+
+	if len(app.Command) != 2 {
+		return errors.New("usage: gophkeeper-server -d=<database uri> -f=<files path> backup|restore <file|->\n" +
+			"       gophkeeper-server -d=<database uri> -c=<keys path> verify-audit")
+	}
+
+	db, err := backupDB(app)
+	if err != nil {
+		return err
+	}
+
+	path := app.Command[1]
+	if name != "backup" && name != "restore" {
+		return fmt.Errorf("unknown command %q, available: backup, restore, verify-audit", name)
+	}
+
+	// миграции применяются, как при запуске сервера: для restore таблицы должны существовать до загрузки данных
+	if _, err = initRepositories(ctx, app); err != nil {
+		return err
+	}
+
+	if name == "backup" {
+		return runBackup(ctx, app, db, path)
+	}
+
+	return runRestore(ctx, app, db, path)
+}
+
+func runBackup(ctx context.Context, app *server.App, db backup.Database, path string) error {
+	var w io.Writer = os.Stdout
+
+	if path != "-" {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	m, err := backup.Backup(ctx, db, app.FilesSavePath, w)
+	if err != nil {
+		if path != "-" {
+			// недописанный архив не оставляется
+			os.Remove(path)
+		}
+
+		return err
+	}
+
+	for _, missing := range m.Missing {
+		fmt.Fprintln(os.Stderr, "warning: file from database not found in files path:", missing)
+	}
+
+	fmt.Fprintf(os.Stderr, "backup of %s storage created: %d files, %d missing\n", m.Storage, len(m.Files), len(m.Missing))
+
+	return nil
+}
+
+func runRestore(ctx context.Context, app *server.App, db backup.Database, path string) error {
+	var r io.Reader = os.Stdin
+
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	m, err := backup.Restore(ctx, db, app.FilesSavePath, r)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "backup from %s restored: %d files in %s\n", m.Created.Format(time.RFC3339), len(m.Files), app.FilesSavePath)
+
+	return nil
+}
+
+func backupDB(app *server.App) (backup.Database, error) {
+	switch app.Storage {
+	case server.StoragePgsql:
+		return pgsql.NewBackupDB(app.DBPool), nil
+	case server.StorageSQLite:
+		return sqlite.NewBackupDB(app.SQLiteDB), nil
+	}
+
+	return nil, errors.New("backup is not supported for storage " + app.Storage)
+}
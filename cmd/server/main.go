@@ -2,20 +2,34 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"gophkeeper/internal"
 	"gophkeeper/internal/crypto"
 	"gophkeeper/internal/server"
 	grpc2 "gophkeeper/internal/server/grpc"
 	interceptors2 "gophkeeper/internal/server/grpc/interceptors"
+	"gophkeeper/internal/server/repository/memory"
 	"gophkeeper/internal/server/repository/pgsql"
+	"gophkeeper/internal/server/repository/sqlite"
+	"gophkeeper/internal/server/web"
 	pb "gophkeeper/proto"
+	"gophkeeper/server/account"
+	"gophkeeper/server/audit"
 	"gophkeeper/server/data"
+	"gophkeeper/server/emergency"
 	"gophkeeper/server/file"
+	"gophkeeper/server/folder"
+	"gophkeeper/server/organization"
+	"gophkeeper/server/ratelimit"
+	"gophkeeper/server/secret"
 	"gophkeeper/server/user"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
 )
@@ -30,12 +44,21 @@ func main() {
 		panic(err)
 	}
 
+	if len(app.Command) > 0 {
+		if err = runCommand(ctx, app); err != nil {
+			fmt.Fprintln(os.Stderr, app.Command[0]+":", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	listen, err = net.Listen("tcp", app.Address)
 	if err != nil {
 		internal.Logger.Fatalw("failed to listen", "err", err)
 	}
 
-	s := initGRPCServer(ctx, app)
+	s, hs := initGRPCServer(ctx, app)
 
 	jobsDone := make(chan struct{})
 	sigint := make(chan os.Signal, 1)
@@ -44,10 +67,21 @@ func main() {
 	go func() {
 		<-sigint
 		s.GracefulStop()
+		if hs != nil {
+			_ = hs.Shutdown(ctx)
+		}
 		close(jobsDone)
 		internal.Logger.Infow("shutdown complete")
 	}()
 
+	if hs != nil {
+		go func() {
+			if err := hs.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				internal.Logger.Fatalw("failed to http serve", "err", err)
+			}
+		}()
+	}
+
 	go func() {
 		if err = s.Serve(listen); err != nil {
 			internal.Logger.Fatalw("failed to grpc serve", "err", err)
@@ -57,7 +91,8 @@ func main() {
 	<-jobsDone
 }
 
-func initGRPCServer(ctx context.Context, app *server.App) *grpc.Server {
+// initGRPCServer gRPC сервер и HTTP сервер ссылок на секреты, nil - если HTTP адрес не задан
+func initGRPCServer(ctx context.Context, app *server.App) (*grpc.Server, *http.Server) {
 	var err error
 	var ch *crypto.Cipher
 	var interceptors []grpc.UnaryServerInterceptor
@@ -67,31 +102,222 @@ func initGRPCServer(ctx context.Context, app *server.App) *grpc.Server {
 		internal.Logger.Fatalw("error initializing cipher", "err", err)
 	}
 
-	interceptors = append(interceptors, interceptors2.Auth)
+	repos, err := initRepositories(ctx, app)
+	if err != nil {
+		panic(err)
+	}
 
-	s := grpc.NewServer(grpc.Creds(ch.GetServerGRPCTransportCreds()), grpc.ChainUnaryInterceptor(interceptors...), grpc.StreamInterceptor(interceptors2.StreamAuth))
+	userService := user.NewService(repos.user)
+	fileService := file.NewService(repos.file)
+	dataService := data.NewService(repos.data, repos.file, repos.user)
+	folderService := folder.NewService(repos.folder, repos.data)
+	orgService := organization.NewService(repos.org, repos.user, repos.data, repos.folder)
+	emergencyService := emergency.NewService(repos.emergency, repos.user)
+	secretService := secret.NewService(repos.secret, app.PublicURL)
+	auditService := audit.NewService(repos.audit, repos.user, ch)
+	rateLimitService := ratelimit.NewService(repos.rateLimit)
+	accountService := account.NewService(repos.account, repos.user, repos.org, fileService)
+
+	userService.Audit = auditService
+	dataService.Audit = auditService
+	accountService.Audit = auditService
+
+	go emergencyService.Run(ctx, time.Minute)
+	go secretService.Run(ctx, time.Minute)
+	go auditService.Run(ctx, time.Minute)
+	go rateLimitService.Run(ctx, time.Hour)
+	go accountService.Run(ctx, time.Hour)
+
+	interceptors = append(interceptors, interceptors2.RateLimit(rateLimitService, repos.user), interceptors2.Auth, interceptors2.Session(userService),
+		interceptors2.Emergency(emergencyService), interceptors2.Vault(orgService))
+
+	s := grpc.NewServer(grpc.Creds(ch.GetServerGRPCTransportCreds()), grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(interceptors2.StreamAuth, interceptors2.StreamSession(userService), interceptors2.StreamEmergency(emergencyService), interceptors2.StreamVault(orgService)))
+
+	userServer := grpc2.NewUserServer(userService)
+	userServer.Account = accountService
+
+	pb.RegisterUserServiceServer(s, userServer)
+	pb.RegisterOrganizationServiceServer(s, grpc2.NewOrganizationServer(orgService))
+	pb.RegisterEmergencyServiceServer(s, grpc2.NewEmergencyServer(emergencyService))
+	pb.RegisterSecretServiceServer(s, grpc2.NewSecretServer(secretService))
+	pb.RegisterAuditServiceServer(s, grpc2.NewAuditServer(auditService))
+	pb.RegisterDataServiceServer(s, grpc2.NewDataServer(dataService, app.FilesSavePath, fileService, folderService))
+
+	if app.HTTPAddress == "" {
+		return s, nil
+	}
+
+	hs := &http.Server{
+		Addr:              app.HTTPAddress,
+		Handler:           web.NewHandler(secretService),
+		TLSConfig:         ch.GetServerTLSConfig(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s, hs
+}
+
+// userRepository хранилище пользователей нужно для авторизации, обмена записями, экстренного доступа, журнала аудита
+// и удаления учетных записей
+type userRepository interface {
+	user.Repository
+	account.UserRepository
+	data.UserRepository
+	emergency.UserRepository
+	audit.UserRepository
+}
+
+type repositories struct {
+	user      userRepository
+	data      data.Repository
+	file      file.FileRepository
+	folder    folder.Repository
+	org       organization.Repository
+	emergency emergency.Repository
+	secret    secret.Repository
+	audit     audit.Repository
+	rateLimit ratelimit.Repository
+	account   account.Repository
+}
+
+func initRepositories(ctx context.Context, app *server.App) (*repositories, error) {
+	switch app.Storage {
+	case server.StorageMemory:
+		userRepo, dataRepo, fileRepo, folderRepo := memory.NewUserRepository(), memory.NewDataRepository(), memory.NewFileRepository(), memory.NewFolderRepository()
+		orgRepo, emergencyRepo, secretRepo := memory.NewOrganizationRepository(userRepo, dataRepo, folderRepo), memory.NewEmergencyRepository(userRepo), memory.NewSecretRepository()
+
+		return &repositories{
+			user:      userRepo,
+			data:      dataRepo,
+			file:      fileRepo,
+			folder:    folderRepo,
+			org:       orgRepo,
+			emergency: emergencyRepo,
+			secret:    secretRepo,
+			audit:     memory.NewAuditRepository(),
+			rateLimit: memory.NewRateLimitRepository(),
+			account:   memory.NewAccountRepository(userRepo, dataRepo, fileRepo, folderRepo, orgRepo, emergencyRepo, secretRepo),
+		}, nil
+	case server.StorageSQLite:
+		return initSQLiteRepositories(ctx, app)
+	}
 
 	userRepo, err := pgsql.NewUserRepository(ctx, app.DBPool, pgsql.UsersTableName)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	fileRepo, err := pgsql.NewFileRepository(ctx, app.DBPool, pgsql.FileTableName)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	dataRepo, err := pgsql.NewDataRepository(ctx, app.DBPool, pgsql.DataTableName, pgsql.FileTableName, pgsql.UsersTableName)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	folderRepo, err := pgsql.NewFolderRepository(ctx, app.DBPool, pgsql.FolderTableName, pgsql.UsersTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	orgRepo, err := pgsql.NewOrganizationRepository(ctx, app.DBPool, pgsql.OrganizationTableName, pgsql.UsersTableName, pgsql.DataTableName, pgsql.FolderTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	emergencyRepo, err := pgsql.NewEmergencyRepository(ctx, app.DBPool, pgsql.EmergencyTableName, pgsql.UsersTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	secretRepo, err := pgsql.NewSecretRepository(ctx, app.DBPool, pgsql.SecretShareTableName, pgsql.UsersTableName)
+	if err != nil {
+		return nil, err
 	}
 
-	userService := user.NewService(userRepo)
-	fileService := file.NewService(fileRepo)
-	dataService := data.NewService(dataRepo, fileRepo)
+	auditRepo, err := pgsql.NewAuditRepository(ctx, app.DBPool, pgsql.AuditTableName)
+	if err != nil {
+		return nil, err
+	}
 
-	pb.RegisterUserServiceServer(s, grpc2.NewUserServer(userService))
-	pb.RegisterDataServiceServer(s, grpc2.NewDataServer(dataService, app.FilesSavePath, fileService))
+	var rateLimitRepo ratelimit.Repository = memory.NewRateLimitRepository()
+	if app.SharedRateLimit {
+		rateLimitRepo, err = pgsql.NewRateLimitRepository(ctx, app.DBPool, pgsql.RateLimitTableName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &repositories{
+		user:      userRepo,
+		data:      dataRepo,
+		file:      fileRepo,
+		folder:    folderRepo,
+		org:       orgRepo,
+		emergency: emergencyRepo,
+		secret:    secretRepo,
+		audit:     auditRepo,
+		rateLimit: rateLimitRepo,
+		account: pgsql.NewAccountRepository(app.DBPool, pgsql.UsersTableName, pgsql.DataTableName, pgsql.FileTableName,
+			pgsql.FolderTableName, pgsql.OrganizationTableName),
+	}, nil
+}
+
+func initSQLiteRepositories(ctx context.Context, app *server.App) (*repositories, error) {
+	userRepo, err := sqlite.NewUserRepository(ctx, app.SQLiteDB, sqlite.UsersTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	fileRepo, err := sqlite.NewFileRepository(ctx, app.SQLiteDB, sqlite.FileTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	dataRepo, err := sqlite.NewDataRepository(ctx, app.SQLiteDB, sqlite.DataTableName, sqlite.FileTableName, sqlite.UsersTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	folderRepo, err := sqlite.NewFolderRepository(ctx, app.SQLiteDB, sqlite.FolderTableName, sqlite.UsersTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	orgRepo, err := sqlite.NewOrganizationRepository(ctx, app.SQLiteDB, sqlite.OrganizationTableName, sqlite.UsersTableName, sqlite.DataTableName, sqlite.FolderTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	emergencyRepo, err := sqlite.NewEmergencyRepository(ctx, app.SQLiteDB, sqlite.EmergencyTableName, sqlite.UsersTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	secretRepo, err := sqlite.NewSecretRepository(ctx, app.SQLiteDB, sqlite.SecretShareTableName, sqlite.UsersTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	auditRepo, err := sqlite.NewAuditRepository(ctx, app.SQLiteDB, sqlite.AuditTableName)
+	if err != nil {
+		return nil, err
+	}
 
-	return s
+	return &repositories{
+		user:      userRepo,
+		data:      dataRepo,
+		file:      fileRepo,
+		folder:    folderRepo,
+		org:       orgRepo,
+		emergency: emergencyRepo,
+		secret:    secretRepo,
+		audit:     auditRepo,
+		rateLimit: memory.NewRateLimitRepository(),
+		account: sqlite.NewAccountRepository(app.SQLiteDB, sqlite.UsersTableName, sqlite.DataTableName, sqlite.FileTableName,
+			sqlite.FolderTableName, sqlite.OrganizationTableName),
+	}, nil
 }
@@ -0,0 +1,249 @@
+// Package agent фоновый процесс клиента, который держит расшифрованный ключ хранилища в памяти
+// и отвечает на запросы консольных команд через unix сокет, доступный только владельцу (аналог ssh-agent)
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/user"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	domain2 "gophkeeper/server/domain"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SocketFileName имя сокета агента в каталоге времени выполнения
+const SocketFileName = "agent.sock"
+
+// connTimeout время на обработку одного подключения
+const connTimeout = time.Minute
+
+// Операции агента
+const (
+	OpStatus  = "status"
+	OpList    = "list"
+	OpGet     = "get"
+	OpFolders = "folders"
+	OpLock    = "lock"
+)
+
+var (
+	ErrLocked         = errors.New("agent is locked")
+	ErrPeerNotAllowed = errors.New("peer is not allowed")
+)
+
+// knownErrors ошибки, которые передаются через сокет с сохранением типа
+var knownErrors = map[string]error{
+	"data_not_found":   domain.ErrDataNotFound,
+	"folder_not_found": domain.ErrFolderNotFound,
+	"locked":           ErrLocked,
+	"session_locked":   domain.ErrSessionLocked,
+}
+
+// Request запрос к агенту
+type Request struct {
+	Op     string                `json:"op"`
+	Ref    string                `json:"ref,omitempty"`
+	Filter domain.DataListFilter `json:"filter"`
+}
+
+// Response ответ агента, при ошибке заполняются Error, ErrorKind и Code
+type Response struct {
+	Error     string             `json:"error,omitempty"`
+	ErrorKind string             `json:"error_kind,omitempty"`
+	Code      codes.Code         `json:"code,omitempty"`
+	Login     string             `json:"login,omitempty"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Data      *domain.Data       `json:"data,omitempty"`
+	List      []domain2.DataName `json:"list,omitempty"`
+	Folders   []domain.Folder    `json:"folders,omitempty"`
+}
+
+// Server агент, использует авторизацию из client.AppInstance до истечения ttl или команды lock
+type Server struct {
+	// mu запросы выполняются последовательно, пакет client/data не рассчитан на конкурентный доступ
+	mu        sync.Mutex
+	ttl       time.Duration
+	expiresAt time.Time
+	locked    bool
+	timer     *time.Timer
+	listener  net.Listener
+	once      sync.Once
+}
+
+func NewServer(ttl time.Duration) *Server {
+	return &Server{ttl: ttl}
+}
+
+// Serve обработка подключений, завершается после блокировки агента
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.listener = l
+	s.expiresAt = time.Now().Add(s.ttl)
+	s.timer = time.AfterFunc(s.ttl, s.Lock)
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isLocked() {
+				return nil
+			}
+
+			return err
+		}
+
+		go s.handleConn(conn)
+	}
+}
+
+// Lock удаление ключей из памяти и остановка агента
+func (s *Server) Lock() {
+	s.once.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.locked = true
+
+		for i := range client.AppInstance.User.StorageKey {
+			client.AppInstance.User.StorageKey[i] = 0
+		}
+
+		for i := range client.AppInstance.User.PrivateKey {
+			client.AppInstance.User.PrivateKey[i] = 0
+		}
+
+		user.ResetUser()
+		client.AppInstance.DecryptedData = make(map[uint64]domain.Data)
+
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+
+		if s.listener != nil {
+			if err := s.listener.Close(); err != nil {
+				internal.Logger.Errorw("error closing agent listener", "error", err)
+			}
+		}
+	})
+}
+
+func (s *Server) isLocked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.locked
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(connTimeout)); err != nil {
+		return
+	}
+
+	enc := json.NewEncoder(conn)
+
+	if err := checkPeer(conn); err != nil {
+		internal.Logger.Infow("agent connection rejected", "error", err)
+		_ = enc.Encode(errorResponse(err))
+
+		return
+	}
+
+	dec := json.NewDecoder(conn)
+
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+
+		if req.Op == OpLock {
+			s.Lock()
+			_ = enc.Encode(Response{})
+
+			return
+		}
+
+		if err := enc.Encode(s.handle(req)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(req Request) Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked {
+		return errorResponse(ErrLocked)
+	}
+
+	if err := user.RefreshToken(); err != nil {
+		return errorResponse(err)
+	}
+
+	resp := Response{Login: client.AppInstance.User.Login, ExpiresAt: s.expiresAt}
+
+	var err error
+
+	switch req.Op {
+	case OpStatus:
+	case OpGet:
+		resp.Data, err = data.FindData(req.Ref)
+	case OpList:
+		resp.List, err = data.ListAll(req.Filter)
+	case OpFolders:
+		resp.Folders, err = data.GetFolders()
+	default:
+		err = errors.New("unknown operation " + req.Op)
+	}
+
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return resp
+}
+
+func errorResponse(err error) Response {
+	resp := Response{Error: err.Error()}
+
+	for kind, e := range knownErrors {
+		if errors.Is(err, e) {
+			resp.ErrorKind = kind
+			return resp
+		}
+	}
+
+	if st, ok := status.FromError(err); ok {
+		resp.Code = st.Code()
+	}
+
+	return resp
+}
+
+// responseError восстановление ошибки из ответа агента
+func responseError(resp Response) error {
+	if resp.Error == "" {
+		return nil
+	}
+
+	if err, ok := knownErrors[resp.ErrorKind]; ok {
+		return err
+	}
+
+	if resp.Code != codes.OK {
+		return status.Error(resp.Code, resp.Error)
+	}
+
+	return errors.New(resp.Error)
+}
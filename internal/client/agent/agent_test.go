@@ -0,0 +1,166 @@
+package agent
+
+import (
+	"context"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/user"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	g "gophkeeper/internal/client/workers/grpc"
+	interceptors2 "gophkeeper/internal/client/workers/grpc/interceptors"
+	grpc2 "gophkeeper/internal/server/grpc"
+	"gophkeeper/internal/server/grpc/interceptors"
+	"gophkeeper/internal/server/repository/memory"
+	pb "gophkeeper/proto"
+	data2 "gophkeeper/server/data"
+	"gophkeeper/server/file"
+	"gophkeeper/server/folder"
+	user2 "gophkeeper/server/user"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func initTestApp(t *testing.T) {
+	internal.InitLogger()
+
+	repo := memory.NewDataRepository()
+	fileRepo := memory.NewFileRepository()
+	userRepo := memory.NewUserRepository()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth), grpc.StreamInterceptor(interceptors.StreamAuth))
+	pb.RegisterUserServiceServer(s, grpc2.NewUserServer(user2.NewService(userRepo)))
+	pb.RegisterDataServiceServer(s, grpc2.NewDataServer(data2.NewService(repo, fileRepo, userRepo), t.TempDir(),
+		file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo)))
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithUnaryInterceptor(interceptors2.Auth),
+		grpc.WithStreamInterceptor(interceptors2.StreamAuth),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	client.AppInstance = &client.App{
+		DecryptedData: make(map[uint64]domain.Data),
+		UserClient:    g.NewUserClient(pb.NewUserServiceClient(conn)),
+		DataClient:    g.NewDataClient(pb.NewDataServiceClient(conn)),
+	}
+}
+
+func TestServer(t *testing.T) {
+	initTestApp(t)
+
+	require.NoError(t, user.Auth("agent", "testtest", false))
+	saved, err := data.SaveData(domain.Data{Name: "deploy", Login: "robot", Pass: "p@ss"})
+	require.NoError(t, err)
+
+	key := client.AppInstance.User.StorageKey
+
+	// короткий путь: длина пути unix сокета ограничена
+	dir, err := os.MkdirTemp("", "agent")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	path := filepath.Join(dir, SocketFileName)
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	server := NewServer(time.Hour)
+	served := make(chan error)
+	go func() {
+		served <- server.Serve(l)
+	}()
+
+	c, err := Dial(path)
+	require.NoError(t, err)
+	defer c.Close()
+
+	status, err := c.Status()
+	require.NoError(t, err)
+	assert.Equal(t, "agent", status.Login)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), status.ExpiresAt, time.Minute)
+
+	got, err := c.Get("deploy")
+	require.NoError(t, err)
+	assert.Equal(t, saved.ID, got.ID)
+	assert.Equal(t, "p@ss", got.Pass)
+
+	_, err = c.Get("absent")
+	assert.ErrorIs(t, err, domain.ErrDataNotFound)
+
+	list, err := c.List(domain.DataListFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "deploy", list[0].Name)
+
+	folders, err := c.Folders()
+	require.NoError(t, err)
+	assert.Empty(t, folders)
+
+	require.NoError(t, c.Lock())
+
+	select {
+	case err = <-served:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("agent was not stopped after lock")
+	}
+
+	// ключ стерт из памяти
+	assert.Equal(t, make([]byte, len(key)), key)
+	assert.Empty(t, client.AppInstance.User.Token)
+
+	_, err = Dial(path)
+	assert.Error(t, err)
+}
+
+func TestServer_TTL(t *testing.T) {
+	initTestApp(t)
+	client.AppInstance.User.StorageKey = []byte("key")
+
+	dir, err := os.MkdirTemp("", "agent")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	l, err := net.Listen("unix", filepath.Join(dir, SocketFileName))
+	require.NoError(t, err)
+
+	served := make(chan error)
+	go func() {
+		served <- NewServer(50 * time.Millisecond).Serve(l)
+	}()
+
+	select {
+	case err = <-served:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("agent was not locked after ttl")
+	}
+
+	assert.Empty(t, client.AppInstance.User.StorageKey)
+}
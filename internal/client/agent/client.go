@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"encoding/json"
+	"gophkeeper/client/domain"
+	domain2 "gophkeeper/server/domain"
+	"net"
+	"time"
+)
+
+// dialTimeout время ожидания подключения к агенту
+const dialTimeout = time.Second
+
+// Client подключение консольной команды к агенту
+type Client struct {
+	conn net.Conn
+	enc  *json.Encoder
+	dec  *json.Decoder
+}
+
+// Dial подключение к агенту, сокет должен принадлежать тому же пользователю
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkPeer(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if err = conn.SetDeadline(time.Now().Add(connTimeout)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return &Client{
+		conn: conn,
+		enc:  json.NewEncoder(conn),
+		dec:  json.NewDecoder(conn),
+	}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Status логин пользователя и время блокировки агента
+func (c *Client) Status() (Response, error) {
+	return c.call(Request{Op: OpStatus})
+}
+
+// Get поиск данных по названию или ИД
+func (c *Client) Get(ref string) (*domain.Data, error) {
+	resp, err := c.call(Request{Op: OpGet, Ref: ref})
+
+	return resp.Data, err
+}
+
+// List полный список данных по фильтру
+func (c *Client) List(filter domain.DataListFilter) ([]domain2.DataName, error) {
+	resp, err := c.call(Request{Op: OpList, Filter: filter})
+
+	return resp.List, err
+}
+
+// Folders папки пользователя
+func (c *Client) Folders() ([]domain.Folder, error) {
+	resp, err := c.call(Request{Op: OpFolders})
+
+	return resp.Folders, err
+}
+
+// Lock удаление ключей из памяти агента и его остановка
+func (c *Client) Lock() error {
+	_, err := c.call(Request{Op: OpLock})
+
+	return err
+}
+
+func (c *Client) call(req Request) (Response, error) {
+	var resp Response
+
+	if err := c.enc.Encode(req); err != nil {
+		return resp, err
+	}
+
+	if err := c.dec.Decode(&resp); err != nil {
+		return resp, err
+	}
+
+	return resp, responseError(resp)
+}
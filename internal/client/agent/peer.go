@@ -0,0 +1,21 @@
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// checkPeer с агентом может работать только процесс того же пользователя
+func checkPeer(conn net.Conn) error {
+	uid, err := peerUID(conn)
+	if err != nil {
+		return err
+	}
+
+	if uid != os.Getuid() {
+		return fmt.Errorf("%w: uid %d", ErrPeerNotAllowed, uid)
+	}
+
+	return nil
+}
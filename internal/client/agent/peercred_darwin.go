@@ -0,0 +1,39 @@
+//go:build darwin
+
+package agent
+
+import (
+	"errors"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerUID uid процесса на другой стороне сокета
+func peerUID(conn net.Conn) (int, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, errors.New("not a unix socket connection")
+	}
+
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var cred *unix.Xucred
+	var credErr error
+
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if credErr != nil {
+		return 0, credErr
+	}
+
+	return int(cred.Uid), nil
+}
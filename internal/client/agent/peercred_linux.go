@@ -0,0 +1,39 @@
+//go:build linux
+
+package agent
+
+import (
+	"errors"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerUID uid процесса на другой стороне сокета
+func peerUID(conn net.Conn) (int, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, errors.New("not a unix socket connection")
+	}
+
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var cred *unix.Ucred
+	var credErr error
+
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if credErr != nil {
+		return 0, credErr
+	}
+
+	return int(cred.Uid), nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package agent
+
+import (
+	"errors"
+	"net"
+)
+
+// peerUID на остальных платформах учетные данные собеседника не проверяются, агент не запускается
+func peerUID(net.Conn) (int, error) {
+	return 0, errors.New("peer credentials are not supported on this platform")
+}
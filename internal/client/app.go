@@ -5,14 +5,19 @@ import (
 	"crypto/sha1"
 	"errors"
 	"flag"
+	"gophkeeper/client/clipboard"
 	"gophkeeper/client/domain"
+	"gophkeeper/client/search"
 	"gophkeeper/internal"
 	g "gophkeeper/internal/client/workers/grpc"
 	"gophkeeper/internal/client/workers/grpc/interceptors"
 	"gophkeeper/internal/crypto"
 	pb "gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"golang.org/x/crypto/pbkdf2"
 	"google.golang.org/grpc"
@@ -21,17 +26,62 @@ import (
 // AppUser структура для хранения данных авторизованного пользователя
 type AppUser struct {
 	Token,
+	RefreshToken,
 	Login string
 	StorageKey []byte
+	// PrivateKey закрытый ключ X25519 для открытия чужих записей, загружается с сервера при первом обращении
+	PrivateKey []byte
+}
+
+// AppVault хранилище организации, с которым работает пользователь
+type AppVault struct {
+	OrgID,
+	KeyVersion uint64
+	Name string
+	Role domain2.OrgRole
+	// Key ключ хранилища, которым шифруются ключи записей, теги и названия папок организации
+	Key []byte
+	// GrantID экстренный доступ, по которому открыто личное хранилище другого пользователя, 0 - хранилище организации
+	GrantID uint64
 }
 
 // App структрура хранящия данные приложения
 type App struct {
 	UserClient    *g.UserClient
 	DataClient    *g.DataClient
+	OrgClient     *g.OrgClient
 	User          AppUser
 	DecryptedData map[uint64]domain.Data
 	DataSavePath  string
+	// EncryptTags хранить теги и названия папок на сервере в зашифрованном виде
+	// фильтр по тегам продолжает работать, но поиск по части тега на сервере невозможен
+	EncryptTags bool
+	// EncryptNames хранить названия данных на сервере в зашифрованном виде
+	// уникальность названия сервер проверяет по слепому индексу, поиск по названию выполняется на клиенте
+	EncryptNames bool
+	// ConfigDir каталог настроек клиента, в нем хранится зашифрованная сессия
+	ConfigDir string
+	// RuntimeDir каталог для секрета разблокировки сессии, очищается при перезагрузке системы
+	RuntimeDir string
+	// LockAfter время бездействия, после которого сессия блокируется, 0 - сессия не сохраняется
+	LockAfter time.Duration
+	// SearchIndex локальный индекс для поиска по расшифрованным данным, открывается после авторизации
+	SearchIndex *search.Index
+	// BreachSource база утечек паролей для проверки хранилища: путь к отсортированному файлу SHA-1 хешей
+	// или URL сервиса с API диапазонов Pwned Passwords, пустая - пароли по утечкам не проверяются
+	BreachSource string
+	// Clipboard буфер обмена для копирования секретов, очищается через заданное время
+	Clipboard *clipboard.Clipboard
+	// Vault хранилище организации, nil - личное хранилище пользователя
+	Vault *AppVault
+	// VaultName название организации, хранилище которой выбирается после авторизации
+	VaultName string
+	// EmergencyClient клиент экстренного доступа доверенных лиц
+	EmergencyClient *g.EmergencyClient
+	// SecretClient клиент одноразовых ссылок на секреты
+	SecretClient *g.SecretClient
+	// ActivityClient клиент журнала аудита сервера
+	ActivityClient *g.ActivityClient
 }
 
 var AppInstance *App
@@ -56,6 +106,14 @@ func InitApp(buildCryptoKeysPath, buildSaveFilePath string) error {
 	AppInstance = &App{
 		DecryptedData: make(map[uint64]domain.Data),
 		DataSavePath:  c.fileSavePath,
+		EncryptTags:   c.encryptTags,
+		EncryptNames:  c.encryptNames,
+		ConfigDir:     c.configDir,
+		RuntimeDir:    runtimeDir(),
+		LockAfter:     c.lockAfter,
+		BreachSource:  c.breachSource,
+		VaultName:     c.vault,
+		Clipboard:     clipboard.New(clipboard.Detect(os.Stdout), c.clipboardClear),
 	}
 
 	err = initGRPCUserClient(c)
@@ -89,6 +147,10 @@ func initGRPCUserClient(cnf *config) error {
 
 	AppInstance.UserClient = g.NewUserClient(pb.NewUserServiceClient(conn))
 	AppInstance.DataClient = g.NewDataClient(pb.NewDataServiceClient(conn))
+	AppInstance.OrgClient = g.NewOrgClient(pb.NewOrganizationServiceClient(conn))
+	AppInstance.EmergencyClient = g.NewEmergencyClient(pb.NewEmergencyServiceClient(conn))
+	AppInstance.SecretClient = g.NewSecretClient(pb.NewSecretServiceClient(conn))
+	AppInstance.ActivityClient = g.NewActivityClient(pb.NewAuditServiceClient(conn))
 
 	return nil
 }
@@ -97,10 +159,27 @@ type config struct {
 	address,
 	fileSavePath,
 	cryptoKeysPath string
+	configDir,
+	breachSource,
+	vault string
+	encryptTags,
+	encryptNames bool
+	lockAfter,
+	clipboardClear time.Duration
 }
 
 const serverAddressVAr = "SERVER_ADDRESS"
 const cryptoKeysPath = "CRYPTO_KEYS_PATH"
+const encryptTagsVar = "ENCRYPT_TAGS"
+const encryptNamesVar = "ENCRYPT_NAMES"
+const configDirVar = "GOPHKEEPER_CONFIG_DIR"
+const lockAfterVar = "LOCK_AFTER"
+const breachSourceVar = "GOPHKEEPER_BREACH_SOURCE"
+const clipboardClearVar = "GOPHKEEPER_CLIPBOARD_CLEAR"
+const vaultVar = "GOPHKEEPER_VAULT"
+
+// defaultLockAfter время бездействия до блокировки сессии по умолчанию
+const defaultLockAfter = 15 * time.Minute
 
 func initConfig() *config {
 	c := new(config)
@@ -108,6 +187,13 @@ func initConfig() *config {
 	flag.StringVar(&c.address, "a", "", "server address")
 	flag.StringVar(&c.cryptoKeysPath, "c", "", "crypto keys path")
 	flag.StringVar(&c.fileSavePath, "f", "", "save files path")
+	flag.BoolVar(&c.encryptTags, "encrypt-tags", false, "store tags and folder names encrypted")
+	flag.BoolVar(&c.encryptNames, "encrypt-names", false, "store data names encrypted")
+	flag.StringVar(&c.configDir, "config-dir", "", "client config directory (by default user config dir)")
+	flag.DurationVar(&c.lockAfter, "lock-after", defaultLockAfter, "lock saved session after this idle time, 0 disables saving")
+	flag.StringVar(&c.breachSource, "breach-source", "", "breached passwords hash file or range API URL for vault audit")
+	flag.StringVar(&c.vault, "vault", "", "organization vault to work with instead of personal one")
+	flag.DurationVar(&c.clipboardClear, "clipboard-clear", clipboard.DefaultClearAfter, "clear copied secret from clipboard after this time, 0 disables clearing")
 
 	flag.Parse()
 
@@ -119,6 +205,44 @@ func initConfig() *config {
 		c.cryptoKeysPath = envVar
 	}
 
+	if envVar := os.Getenv(encryptTagsVar); envVar != "" {
+		c.encryptTags, _ = strconv.ParseBool(envVar)
+	}
+
+	if envVar := os.Getenv(encryptNamesVar); envVar != "" {
+		c.encryptNames, _ = strconv.ParseBool(envVar)
+	}
+
+	if envVar := os.Getenv(configDirVar); envVar != "" {
+		c.configDir = envVar
+	}
+
+	if envVar := os.Getenv(lockAfterVar); envVar != "" {
+		if d, err := time.ParseDuration(envVar); err == nil {
+			c.lockAfter = d
+		}
+	}
+
+	if envVar := os.Getenv(breachSourceVar); envVar != "" {
+		c.breachSource = envVar
+	}
+
+	if envVar := os.Getenv(clipboardClearVar); envVar != "" {
+		if d, err := time.ParseDuration(envVar); err == nil {
+			c.clipboardClear = d
+		}
+	}
+
+	if envVar := os.Getenv(vaultVar); envVar != "" {
+		c.vault = envVar
+	}
+
+	if c.configDir == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			c.configDir = filepath.Join(dir, "gophkeeper")
+		}
+	}
+
 	if c.cryptoKeysPath != "" {
 		c.cryptoKeysPath = filepath.FromSlash(c.cryptoKeysPath)
 	}
@@ -126,6 +250,16 @@ func initConfig() *config {
 	return c
 }
 
+// runtimeDir каталог для короткоживущих секретов клиента
+func runtimeDir() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "gophkeeper-"+strconv.Itoa(os.Getuid()))
+}
+
 func checkConfig(c *config) error {
 	if c.address == "" || c.fileSavePath == "" || c.cryptoKeysPath == "" {
 		return errors.New("please, check configs")
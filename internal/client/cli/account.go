@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"gophkeeper/client/user"
+	"os"
+	"time"
+)
+
+// runAccount управление учетной записью: account delete [--grace дни] | restore <login>
+// пароль читается из stdin или GOPHKEEPER_PASSWORD, удаление всегда требует его повторного ввода
+func runAccount(e env, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "delete":
+		return runAccountDelete(e, args[1:])
+	case "restore":
+		return runAccountRestore(e, args[1:])
+	}
+
+	return errUsage
+}
+
+func runAccountDelete(e env, args []string) error {
+	fs := newFlagSet("account delete", e)
+	grace := fs.Uint("grace", 0, "days during which the deletion can be undone, 0 deletes all data immediately")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 0 || *grace > 30 {
+		return errUsage
+	}
+
+	if err = user.LoadSession(); err != nil {
+		return err
+	}
+
+	pass, err := password(e)
+	if err != nil {
+		return err
+	}
+
+	purgeAt, err := user.DeleteAccount(pass, uint32(*grace))
+	if err != nil {
+		return err
+	}
+
+	if purgeAt.IsZero() {
+		fmt.Fprintln(e.stdout, "account deleted")
+		return nil
+	}
+
+	fmt.Fprintln(e.stdout, "account will be deleted at", purgeAt.Format(time.DateTime)+", undo with: account restore <login>")
+
+	return nil
+}
+
+func runAccountRestore(e env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	pass, err := password(e)
+	if err != nil {
+		return err
+	}
+
+	if err = user.RestoreAccount(args[0], pass); err != nil {
+		return err
+	}
+
+	if err = user.SaveSession(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout, "account restored, logged in as", args[0])
+
+	return nil
+}
+
+// password пароль пользователя из GOPHKEEPER_PASSWORD или первой строки stdin
+func password(e env) (string, error) {
+	if pass := os.Getenv(passwordEnv); pass != "" {
+		return pass, nil
+	}
+
+	return readLine(e.stdin)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	domain2 "gophkeeper/server/domain"
+	"time"
+)
+
+// defaultActivityLimit число событий журнала по умолчанию
+const defaultActivityLimit = 50
+
+// activityJSON событие журнала аудита в выводе --json
+type activityJSON struct {
+	ID      uint64 `json:"id"`
+	Time    string `json:"time"`
+	Action  string `json:"action"`
+	Outcome string `json:"outcome"`
+	Actor   string `json:"actor"`
+	IP      string `json:"ip,omitempty"`
+	Record  string `json:"record,omitempty"`
+}
+
+// runActivity журнал аудита сервера: кто, когда и откуда входил, читал, менял и скачивал записи
+// записи указываются по названию, если они расшифрованы в текущем хранилище, иначе по ИД
+func runActivity(e env, args []string) error {
+	var actions stringsFlag
+
+	fs := newFlagSet("activity", e)
+	outcome := fs.String("outcome", "", "only events with outcome: ok, denied or failed")
+	since := fs.Duration("since", 0, "only events newer than this, e.g. 24h")
+	record := fs.String("record", "", "only events of the record with this name or id")
+	limit := fs.Int("limit", defaultActivityLimit, "max number of events")
+	before := fs.Uint64("before", 0, "only events older than the event with this id, for paging")
+	asJSON := fs.Bool("json", false, "json output")
+	fs.Var(&actions, "action", "action: login, read, save, delete, download, ..., can be repeated")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 0 || *since < 0 || *limit <= 0 || *limit > domain2.AuditMaxPageSize {
+		return errUsage
+	}
+
+	filter := domain2.AuditFilter{Limit: *limit, BeforeID: *before}
+
+	for _, name := range actions {
+		action, ok := domain.ParseAuditAction(name)
+		if !ok {
+			return errUsage
+		}
+
+		filter.Actions = append(filter.Actions, action)
+	}
+
+	if *outcome != "" {
+		var ok bool
+		if filter.Outcome, ok = domain.ParseAuditOutcome(*outcome); !ok {
+			return errUsage
+		}
+	}
+
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+
+	if *record != "" {
+		d, err := data.FindData(*record)
+		if err != nil {
+			return err
+		}
+
+		filter.DataID = d.ID
+	}
+
+	list, err := data.GetActivity(filter)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		res := make([]activityJSON, 0, len(list))
+		for _, ev := range list {
+			res = append(res, activityJSON{
+				ID:      ev.ID,
+				Time:    ev.CreatedAt.UTC().Format(time.RFC3339),
+				Action:  domain.AuditActionName(ev.Action),
+				Outcome: domain.AuditOutcomeName(ev.Outcome),
+				Actor:   ev.Actor,
+				IP:      ev.IP,
+				Record:  data.ActivityRecordName(ev.DataID),
+			})
+		}
+
+		return writeJSON(e.stdout, res)
+	}
+
+	for _, ev := range list {
+		line := fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s", ev.ID, ev.CreatedAt.Format(time.DateTime),
+			domain.AuditActionName(ev.Action), domain.AuditOutcomeName(ev.Outcome), ev.Actor, ev.IP)
+		if name := data.ActivityRecordName(ev.DataID); name != "" {
+			line += "\t" + name
+		}
+
+		fmt.Fprintln(e.stdout, line)
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/user"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/client/agent"
+	domain2 "gophkeeper/server/domain"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// agentSocketEnv переменная окружения с путем к сокету агента
+const agentSocketEnv = "GOPHKEEPER_AGENT_SOCK"
+
+// agentStartTimeout время ожидания запуска фонового агента
+const agentStartTimeout = 5 * time.Second
+
+var errAgentNotRunning = errors.New("agent is not running")
+
+// vault источник данных для команд чтения
+type vault interface {
+	Get(ref string) (*domain.Data, error)
+	List(filter domain.DataListFilter) ([]domain2.DataName, error)
+	Folders() ([]domain.Folder, error)
+}
+
+// localVault данные запрашиваются у сервера текущим процессом
+type localVault struct{}
+
+func (localVault) Get(ref string) (*domain.Data, error) {
+	return data.FindData(ref)
+}
+
+func (localVault) List(filter domain.DataListFilter) ([]domain2.DataName, error) {
+	return data.ListAll(filter)
+}
+
+func (localVault) Folders() ([]domain.Folder, error) {
+	return data.GetFolders()
+}
+
+// agentVault данные запрашиваются у запущенного агента
+type agentVault struct {
+	*agent.Client
+}
+
+// agentKeys ключи, которые процесс запуска передает фоновому агенту через stdin
+type agentKeys struct {
+	Login        string `json:"login"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	StorageKey   []byte `json:"storage_key"`
+}
+
+func agentSocketPath() string {
+	if path := os.Getenv(agentSocketEnv); path != "" {
+		return path
+	}
+
+	return filepath.Join(client.AppInstance.RuntimeDir, agent.SocketFileName)
+}
+
+func runAgent(e env, args []string) error {
+	fs := newFlagSet("agent", e)
+	ttl := fs.Duration("ttl", time.Hour, "lock agent after this time")
+	foreground := fs.Bool("foreground", false, "do not detach from terminal")
+	keysStdin := fs.Bool("keys-stdin", false, "read unlocked keys from stdin (used when agent starts itself in background)")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) > 1 || *ttl <= 0 {
+		return errUsage
+	}
+
+	switch {
+	case *keysStdin:
+		var keys agentKeys
+		if err = json.NewDecoder(e.stdin).Decode(&keys); err != nil {
+			return err
+		}
+
+		client.AppInstance.User = client.AppUser{
+			Login:        keys.Login,
+			Token:        keys.Token,
+			RefreshToken: keys.RefreshToken,
+			StorageKey:   keys.StorageKey,
+		}
+	case len(positional) == 1:
+		pass := os.Getenv(passwordEnv)
+		if pass == "" {
+			if pass, err = readLine(e.stdin); err != nil {
+				return err
+			}
+		}
+
+		if err = user.Auth(positional[0], pass, true); err != nil {
+			return err
+		}
+	default:
+		if err = user.LoadSession(); err != nil {
+			return err
+		}
+	}
+
+	path := agentSocketPath()
+
+	if *foreground || *keysStdin {
+		l, err := listenAgent(path)
+		if err != nil {
+			return err
+		}
+
+		if *foreground {
+			printAgentEnv(e, path)
+		}
+
+		return agent.NewServer(*ttl).Serve(l)
+	}
+
+	if err = startAgent(args, *ttl); err != nil {
+		return err
+	}
+
+	printAgentEnv(e, path)
+
+	return nil
+}
+
+func runLock(e env, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	errSession := user.LockSession()
+
+	c, err := agent.Dial(agentSocketPath())
+	if err != nil {
+		if errSession != nil {
+			return errSession
+		}
+
+		fmt.Fprintln(e.stdout, "session locked,", errAgentNotRunning)
+
+		return nil
+	}
+
+	defer c.Close()
+
+	if err = c.Lock(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout, "agent and session locked")
+
+	return errSession
+}
+
+// listenAgent создание сокета, доступного только владельцу
+// сокет, оставшийся от аварийно завершенного агента, удаляется
+func listenAgent(path string) (net.Listener, error) {
+	if c, err := agent.Dial(path); err == nil {
+		_ = c.Close()
+		return nil, errors.New("agent is already running")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Chmod(path, 0600); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// startAgent запуск агента в фоне с теми же глобальными флагами, ключи передаются через stdin
+func startAgent(args []string, ttl time.Duration) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// глобальные флаги стоят в os.Args перед названием команды
+	global := os.Args[1 : len(os.Args)-len(args)-1]
+	childArgs := append(append([]string{}, global...), "agent", "-keys-stdin", "-ttl", ttl.String())
+
+	cmd := exec.Command(exe, childArgs...)
+	cmd.SysProcAttr = detachAttr()
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	err = json.NewEncoder(stdin).Encode(agentKeys{
+		Login:        client.AppInstance.User.Login,
+		Token:        client.AppInstance.User.Token,
+		RefreshToken: client.AppInstance.User.RefreshToken,
+		StorageKey:   client.AppInstance.User.StorageKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = stdin.Close(); err != nil {
+		return err
+	}
+
+	if err = cmd.Process.Release(); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(agentStartTimeout)
+	for time.Now().Before(deadline) {
+		if c, err := agent.Dial(agentSocketPath()); err == nil {
+			return c.Close()
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return errors.New("agent did not start")
+}
+
+func printAgentEnv(e env, path string) {
+	fmt.Fprintf(e.stdout, "%s=%s; export %s;\n", agentSocketEnv, path, agentSocketEnv)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"gophkeeper/client/audit"
+	"gophkeeper/client/data"
+	"time"
+)
+
+var errAuditIssues = errors.New("vault has issues")
+
+// runAudit отчет о состоянии хранилища в формате JSON
+// с --fail команда завершается с ошибкой, если в отчете есть замечания, что удобно для периодической проверки
+func runAudit(e env, args []string) error {
+	fs := newFlagSet("audit", e)
+	maxAge := fs.Int("max-age-days", int(audit.DefaultMaxPasswordAge.Hours()/24), "passwords not changed for longer are reported, 0 disables the check")
+	cardDays := fs.Int("card-days", int(audit.DefaultCardExpiryWarn.Hours()/24), "cards expiring within this number of days are reported")
+	breachSource := fs.String("breach-source", "", "breached passwords hash file or range API URL, overrides -breach-source")
+	fail := fs.Bool("fail", false, "exit with error if any issue is found")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 0 || *maxAge < 0 || *cardDays < 0 {
+		return errUsage
+	}
+
+	o, err := data.AuditOptions(*breachSource)
+	if err != nil {
+		return err
+	}
+
+	o.MaxPasswordAge = time.Duration(*maxAge) * 24 * time.Hour
+	o.CardExpiryWarn = time.Duration(*cardDays) * 24 * time.Hour
+
+	report, err := data.AuditVault(o)
+	if err != nil {
+		return err
+	}
+
+	if err = writeJSON(e.stdout, report); err != nil {
+		return err
+	}
+
+	if *fail && report.Issues > 0 {
+		return fmt.Errorf("%w: %d of %d records", errAuditIssues, report.Issues, report.Records)
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+// Package cli неинтерактивные команды клиента для использования в скриптах и CI
+// команды используют те же пакеты client/data и client/user, что и TUI, поэтому ведут себя одинаково
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"gophkeeper/client/backup"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/generator"
+	"gophkeeper/client/importer"
+	"gophkeeper/client/importer/kdbx"
+	"gophkeeper/client/otp"
+	"gophkeeper/client/user"
+	"gophkeeper/internal/client"
+	"gophkeeper/internal/client/agent"
+	"io"
+	"io/fs"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Коды завершения команд по классам ошибок
+const (
+	ExitOK = iota
+	ExitError
+	ExitUsage
+	ExitAuth
+	ExitNotFound
+	ExitConflict
+	ExitUnavailable
+)
+
+var (
+	errUsage  = errors.New("usage")
+	errNoFile = errors.New("data has no attached file")
+)
+
+// env окружение выполнения команды
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// vault источник данных для команд чтения: агент, если он запущен, либо сервер
+	vault vault
+}
+
+type command struct {
+	usage string
+	// auth команде нужна сохраненная авторизация
+	auth bool
+	// viaAgent команда только читает данные и может выполняться запущенным агентом без загрузки сессии
+	viaAgent bool
+	run      func(e env, args []string) error
+}
+
+var commands = map[string]command{
+	"login":     {usage: "login [--register] <login>  (password from stdin or GOPHKEEPER_PASSWORD)", run: runLogin},
+	"logout":    {usage: "logout", run: runLogout},
+	"account":   {usage: "account delete [--grace days] | restore <login>  (password from stdin or GOPHKEEPER_PASSWORD; without --grace all data is deleted immediately, grace is up to 30 days)", run: runAccount},
+	"ls":        {usage: "ls [--prefix s] [--contains s] [--tag t]... [--type t] [--folder path] [--shared] [--sort name|-name|id|-id] [--json]", auth: true, viaAgent: true, run: runList},
+	"get":       {usage: "get <name|id> [--field name|login|pass|card|exp|otp|text|meta|file] [--json]", auth: true, viaAgent: true, run: runGet},
+	"set":       {usage: "set <name> [--login s] [--text s] [--card s] [--exp MM/YY] [--meta s] [--folder path] [--tag t]... [--stdin field] [--json]  (otp key: --stdin otp)", auth: true, run: runSet},
+	"rm":        {usage: "rm <name|id>", auth: true, run: runRemove},
+	"attach":    {usage: "attach <name|id> <file>", auth: true, run: runAttach},
+	"download":  {usage: "download <name|id> [-o path]", auth: true, run: runDownload},
+	"sync":      {usage: "sync", auth: true, run: runSync},
+	"share":     {usage: "share <name|id> <login> [--write]  (read-only access unless --write)\n  share --link [--field pass] [--expire 24h] [--views 1] <name|id> | share --link --stdin  (one-off link for people without account)", auth: true, run: runShare},
+	"unshare":   {usage: "unshare <name|id> <login>", auth: true, run: runUnshare},
+	"org":       {usage: "org ls [--json] | create <name> | invite <org> <login> [--role viewer|editor|admin|owner] | accept <org> | members <org> [--json] | role <org> <login> <role> | remove <org> <login>  (data commands work with the vault chosen by -vault or GOPHKEEPER_VAULT)", auth: true, run: runOrg},
+	"emergency": {usage: "emergency ls [--json] | add <login> [--wait 72h] | accept <owner> | request <owner> | approve <login> | reject <login> | revoke <login>  (approved access is opened read-only with -vault emergency:<owner>)", auth: true, run: runEmergency},
+	"agent":     {usage: "agent [--ttl 1h] [--foreground] [<login>]  (without login the saved session is used)", run: runAgent},
+	"lock":      {usage: "lock  (stop agent and lock saved session)", run: runLock},
+	"activity":  {usage: "activity [--action login|read|save|delete|download|...]... [--outcome ok|denied|failed] [--since 24h] [--record <name|id>] [--limit 50] [--before id] [--json]  (server log of your sign-ins and record access)", auth: true, run: runActivity},
+	"audit":     {usage: "audit [--max-age-days 365] [--card-days 60] [--breach-source file|url] [--fail]  (vault health report in JSON)", auth: true, run: runAudit},
+	"otp":       {usage: "otp <name|id> [--json]  (current one-time code, hotp counter is advanced and saved)", auth: true, run: runOTP},
+	"import":    {usage: "import <bitwarden|1password|keepass|csv> <file> [--dry-run] [--on-conflict skip|rename|overwrite] [--map field=Column,...] [--keyfile path] [--json]  (keepass password from stdin or GOPHKEEPER_KEEPASS_PASSWORD)\n  import --from-backup <file> [--dry-run] [--on-conflict skip|rename|overwrite] [--json]  (passphrase from stdin or GOPHKEEPER_BACKUP_PASSPHRASE)", auth: true, run: runImport},
+	"export":    {usage: "export <file> [--force] [--json]  (encrypted archive of all data, passphrase from stdin or GOPHKEEPER_BACKUP_PASSPHRASE)", auth: true, run: runExport},
+	"gen":       {usage: "gen [--mode random|pronounceable|passphrase] [--length n] [--words n] [--separator s] [--no-lower] [--no-upper] [--no-digits] [--no-symbols] [--ambiguous] [--json]", run: runGen},
+}
+
+// Run выполнение команды, возвращается код завершения процесса
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	e := env{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 || args[0] == "help" {
+		printUsage(stdout)
+		return ExitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		printUsage(stderr)
+
+		return ExitUsage
+	}
+
+	err := func() error {
+		// агент хранит только личное хранилище
+		if cmd.viaAgent && client.AppInstance.VaultName == "" {
+			if c, err := agent.Dial(agentSocketPath()); err == nil {
+				defer c.Close()
+
+				e.vault = agentVault{c}
+
+				return cmd.run(e, args[1:])
+			}
+		}
+
+		if cmd.auth {
+			if err := user.LoadSession(); err != nil {
+				return err
+			}
+
+			if err := data.UseVault(client.AppInstance.VaultName); err != nil {
+				return err
+			}
+		}
+
+		e.vault = localVault{}
+
+		return cmd.run(e, args[1:])
+	}()
+
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+
+	if err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "usage: %s\n", cmd.usage)
+		} else {
+			fmt.Fprintln(stderr, "error:", err)
+		}
+	}
+
+	return ExitCode(err)
+}
+
+// ExitCode код завершения для ошибки команды
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, errUsage):
+		return ExitUsage
+	case errors.Is(err, domain.ErrNotLoggedIn),
+		errors.Is(err, domain.ErrSessionLocked),
+		errors.Is(err, agent.ErrLocked),
+		errors.Is(err, agent.ErrPeerNotAllowed),
+		errors.Is(err, domain.ErrRegisterDataLength),
+		errors.Is(err, domain.ErrShareNotOwner),
+		errors.Is(err, domain.ErrSharedReadOnly),
+		errors.Is(err, domain.ErrVaultReadOnly),
+		errors.Is(err, domain.ErrVaultShare),
+		errors.Is(err, domain.ErrEmergencyReadOnly):
+		return ExitAuth
+	case errors.Is(err, domain.ErrDataNotFound),
+		errors.Is(err, domain.ErrFolderNotFound),
+		errors.Is(err, errNoFile),
+		errors.Is(err, domain.ErrNoOTP),
+		errors.Is(err, domain.ErrVaultNotFound),
+		errors.Is(err, domain.ErrGrantNotFound):
+		return ExitNotFound
+	case errors.Is(err, otp.ErrBadURI),
+		errors.Is(err, otp.ErrBadSecret),
+		errors.Is(err, otp.ErrAlgorithm),
+		errors.Is(err, otp.ErrDigits),
+		errors.Is(err, otp.ErrPeriod):
+		return ExitConflict
+	case errors.Is(err, generator.ErrNoClasses),
+		errors.Is(err, generator.ErrLength),
+		errors.Is(err, generator.ErrWords),
+		errors.Is(err, importer.ErrFormat),
+		errors.Is(err, importer.ErrMapping),
+		errors.Is(err, domain.ErrOrgRole),
+		errors.Is(err, backup.ErrWeakPass):
+		return ExitUsage
+	case errors.Is(err, kdbx.ErrCredentials),
+		errors.Is(err, backup.ErrPassphrase):
+		return ExitAuth
+	case errors.Is(err, importer.ErrEncrypted),
+		errors.Is(err, importer.ErrEmpty),
+		errors.Is(err, kdbx.ErrSignature),
+		errors.Is(err, kdbx.ErrVersion),
+		errors.Is(err, kdbx.ErrCorrupted),
+		errors.Is(err, kdbx.ErrHeader),
+		errors.Is(err, kdbx.ErrUnsupported),
+		errors.Is(err, backup.ErrFormat),
+		errors.Is(err, backup.ErrVersion),
+		errors.Is(err, backup.ErrCorrupted),
+		errors.Is(err, domain.ErrNoKeyPair),
+		errors.Is(err, fs.ErrExist):
+		return ExitConflict
+	case errors.Is(err, domain.ErrTooManyAttempts):
+		return ExitUnavailable
+	}
+
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return ExitAuth
+	case codes.NotFound:
+		return ExitNotFound
+	case codes.AlreadyExists, codes.InvalidArgument, codes.FailedPrecondition, codes.Aborted:
+		return ExitConflict
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return ExitUnavailable
+	}
+
+	return ExitError
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: gophkeeper [flags] <command> [args]")
+	fmt.Fprintln(w, "without command the interactive interface is started")
+	fmt.Fprintln(w, "\ncommands:")
+
+	names := []string{"login", "logout", "account", "ls", "get", "set", "rm", "attach", "download", "sync", "share", "unshare", "org", "emergency", "activity", "audit", "agent", "lock", "otp", "import", "export", "gen"}
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+
+	fmt.Fprintf(w, "\nexit codes: %d ok, %d error, %d usage, %d auth, %d not found, %d invalid or conflicting data, %d server unavailable or too many attempts\n",
+		ExitOK, ExitError, ExitUsage, ExitAuth, ExitNotFound, ExitConflict, ExitUnavailable)
+}
+
+// parseArgs разбор флагов команды, флаги могут идти после позиционных аргументов
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("%w: %s", errUsage, err.Error())
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet набор флагов команды, ошибки разбора выводятся в stderr
+func newFlagSet(name string, e env) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+
+	return fs
+}
+
+// stringsFlag флаг, который можно указать несколько раз
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+
+	return nil
+}
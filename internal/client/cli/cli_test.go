@@ -0,0 +1,1011 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"gophkeeper/client/audit"
+	"gophkeeper/client/backup"
+	data2 "gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/user"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	g "gophkeeper/internal/client/workers/grpc"
+	interceptors2 "gophkeeper/internal/client/workers/grpc/interceptors"
+	"gophkeeper/internal/crypto"
+	grpc2 "gophkeeper/internal/server/grpc"
+	"gophkeeper/internal/server/grpc/interceptors"
+	"gophkeeper/internal/server/repository/memory"
+	"gophkeeper/internal/server/web"
+	pb "gophkeeper/proto"
+	"gophkeeper/server/account"
+	audit2 "gophkeeper/server/audit"
+	"gophkeeper/server/data"
+	"gophkeeper/server/emergency"
+	"gophkeeper/server/file"
+	"gophkeeper/server/folder"
+	"gophkeeper/server/organization"
+	"gophkeeper/server/secret"
+	user2 "gophkeeper/server/user"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func initTestApp(t *testing.T) {
+	internal.InitLogger()
+
+	repo := memory.NewDataRepository()
+	fileRepo := memory.NewFileRepository()
+	userRepo := memory.NewUserRepository()
+	folderRepo := memory.NewFolderRepository()
+	orgRepo, emergencyRepo, secretRepo := memory.NewOrganizationRepository(userRepo, repo, folderRepo), memory.NewEmergencyRepository(userRepo), memory.NewSecretRepository()
+	orgService := organization.NewService(orgRepo, userRepo, repo, folderRepo)
+	emergencyService := emergency.NewService(emergencyRepo, userRepo)
+
+	// HTTP сервер ссылок на секреты, адрес известен до запуска, он нужен сервису для ссылок
+	ws := httptest.NewUnstartedServer(nil)
+	secretService := secret.NewService(secretRepo, "http://"+ws.Listener.Addr().String())
+	ws.Config.Handler = web.NewHandler(secretService)
+	ws.Start()
+	t.Cleanup(ws.Close)
+
+	auditService := audit2.NewService(memory.NewAuditRepository(), userRepo, nil)
+	userService := user2.NewService(userRepo)
+	userService.Audit = auditService
+	dataService := data.NewService(repo, fileRepo, userRepo)
+	dataService.Audit = auditService
+	fileService := file.NewService(fileRepo)
+	accountService := account.NewService(memory.NewAccountRepository(userRepo, repo, fileRepo, folderRepo, orgRepo, emergencyRepo, secretRepo),
+		userRepo, orgRepo, fileService)
+	accountService.Audit = auditService
+	userServer := grpc2.NewUserServer(userService)
+	userServer.Account = accountService
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.Auth, interceptors.Session(userService), interceptors.Emergency(emergencyService), interceptors.Vault(orgService)),
+		grpc.ChainStreamInterceptor(interceptors.StreamAuth, interceptors.StreamSession(userService), interceptors.StreamEmergency(emergencyService), interceptors.StreamVault(orgService)))
+	pb.RegisterUserServiceServer(s, userServer)
+	pb.RegisterDataServiceServer(s, grpc2.NewDataServer(dataService, t.TempDir(),
+		fileService, folder.NewService(folderRepo, repo)))
+	pb.RegisterOrganizationServiceServer(s, grpc2.NewOrganizationServer(orgService))
+	pb.RegisterEmergencyServiceServer(s, grpc2.NewEmergencyServer(emergencyService))
+	pb.RegisterSecretServiceServer(s, grpc2.NewSecretServer(secretService))
+	pb.RegisterAuditServiceServer(s, grpc2.NewAuditServer(auditService))
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithUnaryInterceptor(interceptors2.Auth),
+		grpc.WithStreamInterceptor(interceptors2.StreamAuth),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	client.AppInstance = &client.App{
+		DecryptedData:   make(map[uint64]domain.Data),
+		DataSavePath:    t.TempDir(),
+		ConfigDir:       t.TempDir(),
+		RuntimeDir:      filepath.Join(t.TempDir(), "runtime"),
+		LockAfter:       time.Minute,
+		UserClient:      g.NewUserClient(pb.NewUserServiceClient(conn)),
+		DataClient:      g.NewDataClient(pb.NewDataServiceClient(conn)),
+		OrgClient:       g.NewOrgClient(pb.NewOrganizationServiceClient(conn)),
+		EmergencyClient: g.NewEmergencyClient(pb.NewEmergencyServiceClient(conn)),
+		SecretClient:    g.NewSecretClient(pb.NewSecretServiceClient(conn)),
+		ActivityClient:  g.NewActivityClient(pb.NewAuditServiceClient(conn)),
+	}
+}
+
+// run выполнение команды в новом "процессе" клиента, авторизация восстанавливается из файла сессии
+func run(t *testing.T, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	client.AppInstance.User = client.AppUser{}
+	client.AppInstance.DecryptedData = make(map[uint64]domain.Data)
+	client.AppInstance.SearchIndex = nil
+	client.AppInstance.Vault = nil
+
+	code := Run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	initTestApp(t)
+
+	code, _, stderr := run(t, "", "ls")
+	assert.Equal(t, ExitAuth, code)
+	assert.Contains(t, stderr, domain.ErrNotLoggedIn.Error())
+
+	code, _, _ = run(t, "secret-pass\n", "login", "--register", "ci-bot")
+	require.Equal(t, ExitOK, code)
+
+	info, err := os.Stat(filepath.Join(client.AppInstance.ConfigDir, user.SessionFileName))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	code, stdout, _ := run(t, "p@ss\n", "set", "deploy", "--login", "robot", "--tag", "ci", "--stdin", "pass")
+	require.Equal(t, ExitOK, code)
+	assert.NotEmpty(t, strings.TrimSpace(stdout))
+
+	code, stdout, _ = run(t, "", "get", "deploy", "--field", "pass")
+	assert.Equal(t, ExitOK, code)
+	assert.Equal(t, "p@ss\n", stdout)
+
+	// изменение одного поля не затирает остальные
+	code, _, _ = run(t, "", "set", "deploy", "--meta", "https://ci.example.com")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "get", "--json", "deploy")
+	require.Equal(t, ExitOK, code)
+
+	var got dataJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	assert.Equal(t, "robot", got.Login)
+	assert.Equal(t, "p@ss", got.Pass)
+	assert.Equal(t, "https://ci.example.com", got.Meta)
+	assert.Equal(t, []string{"ci"}, got.Tags)
+
+	code, stdout, _ = run(t, "", "get", "deploy")
+	require.Equal(t, ExitOK, code)
+	assert.NotContains(t, stdout, "p@ss")
+
+	code, stdout, _ = run(t, "", "ls", "--json", "--tag", "ci")
+	require.Equal(t, ExitOK, code)
+
+	var list []dataNameJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "deploy", list[0].Name)
+
+	code, stdout, _ = run(t, "", "sync")
+	assert.Equal(t, ExitOK, code)
+	assert.Equal(t, "indexed 1 records\n", stdout)
+
+	code, stdout, _ = run(t, "", "audit", "--fail")
+	assert.Equal(t, ExitError, code)
+	var report audit.Report
+	require.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	assert.Equal(t, 1, report.Passwords)
+	require.Len(t, report.Weak, 1)
+	assert.Equal(t, "deploy", report.Weak[0].Name)
+	assert.NotContains(t, stdout, "p@ss")
+
+	// HOTP: счетчик сохраняется на сервере и переживает перезапуск клиента (RFC 4226, приложение D)
+	code, _, _ = run(t, "otpauth://hotp/CI:robot?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&counter=0\n", "set", "2fa", "--stdin", "otp")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "otp", "2fa")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "755224\n", stdout)
+
+	code, stdout, _ = run(t, "", "otp", "2fa", "--json")
+	require.Equal(t, ExitOK, code)
+	var oneTime otpJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &oneTime))
+	assert.Equal(t, otpJSON{Code: "287082", Type: "hotp", Counter: 2}, oneTime)
+
+	code, stdout, _ = run(t, "", "get", "2fa", "--field", "otp")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "counter=2")
+
+	code, stdout, _ = run(t, "", "get", "2fa")
+	require.Equal(t, ExitOK, code)
+	assert.NotContains(t, stdout, "GEZDGNBV")
+
+	code, _, _ = run(t, "JBSW Y3DP EHPK 3PXP\n", "set", "totp", "--stdin", "otp")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, stderr = run(t, "", "otp", "totp")
+	require.Equal(t, ExitOK, code)
+	assert.Regexp(t, `^\d{6}\n$`, stdout)
+	assert.Contains(t, stderr, "expires in")
+
+	code, _, _ = run(t, "not-base32!\n", "set", "totp", "--stdin", "otp")
+	assert.Equal(t, ExitConflict, code)
+
+	code, _, _ = run(t, "", "otp", "deploy")
+	assert.Equal(t, ExitNotFound, code)
+
+	for _, name := range []string{"2fa", "totp"} {
+		code, _, _ = run(t, "", "rm", name)
+		require.Equal(t, ExitOK, code)
+	}
+
+	code, _, _ = run(t, "", "get", "absent")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, _, stderr = run(t, "", "get")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, "usage: get")
+
+	code, _, _ = run(t, "", "download", "deploy")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, _, _ = run(t, "", "rm", "deploy")
+	assert.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "ls")
+	assert.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+
+	code, _, _ = run(t, "", "logout")
+	assert.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "ls")
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = run(t, "", "unknown")
+	assert.Equal(t, ExitUsage, code)
+
+	code, stdout, stderr = run(t, "", "gen", "--length", "32", "--no-symbols")
+	require.Equal(t, ExitOK, code)
+	assert.Len(t, strings.TrimSpace(stdout), 32)
+	assert.Contains(t, stderr, "entropy:")
+
+	code, stdout, _ = run(t, "", "gen", "--mode", "passphrase", "--words", "5", "--separator", " ", "--json")
+	require.Equal(t, ExitOK, code)
+	var gen genJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &gen))
+	assert.Len(t, strings.Fields(gen.Value), 5)
+	assert.Greater(t, gen.Entropy, 60.0)
+
+	code, _, _ = run(t, "", "gen", "--no-lower", "--no-upper", "--no-digits", "--no-symbols")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Share(t *testing.T) {
+	initTestApp(t)
+
+	code, _, _ := run(t, "bob-pass\n", "login", "--register", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "alice-pass\n", "login", "--register", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "s3cret\n", "set", "db", "--login", "admin", "--tag", "prod", "--stdin", "pass")
+	require.Equal(t, ExitOK, code)
+
+	attachment := filepath.Join(t.TempDir(), "dump.sql")
+	require.NoError(t, os.WriteFile(attachment, []byte("create table t;"), 0600))
+	code, _, _ = run(t, "", "attach", "db", attachment)
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "share", "db", "alice")
+	assert.Equal(t, ExitConflict, code)
+
+	code, _, _ = run(t, "", "share", "db", "nobody")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, stdout, _ := run(t, "", "share", "db", "bob")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "shared db with bob (read)\n", stdout)
+
+	// после перешифровки ключом записи владелец по-прежнему читает запись и файл
+	code, stdout, _ = run(t, "", "get", "db", "--field", "pass")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "s3cret\n", stdout)
+
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "ls", "--shared", "--json")
+	require.Equal(t, ExitOK, code)
+	var list []dataNameJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "db", list[0].Name)
+	assert.Equal(t, "alice", list[0].SharedBy)
+	assert.Empty(t, list[0].Tags)
+
+	code, stdout, _ = run(t, "", "get", "db", "--json")
+	require.Equal(t, ExitOK, code)
+	var got dataJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	assert.Equal(t, "admin", got.Login)
+	assert.Equal(t, "s3cret", got.Pass)
+	assert.Equal(t, "alice", got.SharedBy)
+	assert.True(t, got.ReadOnly)
+
+	out := filepath.Join(t.TempDir(), "out.sql")
+	code, _, _ = run(t, "", "download", "db", "-o", out)
+	require.Equal(t, ExitOK, code)
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "create table t;", string(content))
+
+	code, _, _ = run(t, "", "set", "db", "--meta", "changed")
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = run(t, "", "share", "db", "alice")
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = run(t, "alice-pass\n", "login", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "share", "db", "bob", "--write")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "set", "db", "--meta", "changed by bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "alice-pass\n", "login", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "get", "db", "--json")
+	require.Equal(t, ExitOK, code)
+	var own dataJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &own))
+	assert.Equal(t, "changed by bob", own.Meta)
+	assert.Equal(t, []string{"prod"}, own.Tags)
+	assert.Empty(t, own.SharedBy)
+
+	code, _, _ = run(t, "", "unshare", "db", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "ls")
+	require.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+
+	code, _, _ = run(t, "", "get", "db")
+	assert.Equal(t, ExitNotFound, code)
+}
+
+func TestRun_Org(t *testing.T) {
+	initTestApp(t)
+	client.AppInstance.EncryptTags = true
+	client.AppInstance.EncryptNames = true
+
+	// vault выбирает хранилище организации для команд с данными
+	vault := func(name string, stdin string, args ...string) (int, string, string) {
+		client.AppInstance.VaultName = name
+		defer func() {
+			client.AppInstance.VaultName = ""
+		}()
+
+		return run(t, stdin, args...)
+	}
+
+	for _, login := range []string{"bob", "carol", "alice"} {
+		code, _, _ := run(t, login+"-pass\n", "login", "--register", login)
+		require.Equal(t, ExitOK, code)
+	}
+
+	code, _, _ := run(t, "", "org", "create", "team")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "org", "invite", "team", "bob", "--role", "boss")
+	assert.Equal(t, ExitUsage, code)
+
+	for _, login := range []string{"bob", "carol"} {
+		code, _, _ = run(t, "", "org", "invite", "team", login)
+		require.Equal(t, ExitOK, code)
+	}
+
+	code, _, _ = vault("team", "s3cret\n", "set", "db", "--login", "admin", "--tag", "prod", "--folder", "", "--stdin", "pass")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = vault("team", "", "set", "wiki", "--text", "hello")
+	require.Equal(t, ExitOK, code)
+
+	// запись организации не видна в личном хранилище
+	code, stdout, _ := run(t, "", "ls")
+	require.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = vault("team", "", "ls")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, stdout, _ = run(t, "", "org", "ls", "--json")
+	require.Equal(t, ExitOK, code)
+	var orgs []orgJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &orgs))
+	require.Len(t, orgs, 1)
+	assert.Equal(t, "viewer", orgs[0].Role)
+	assert.False(t, orgs[0].Accepted)
+
+	code, _, _ = run(t, "", "org", "accept", "team")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = vault("team", "", "get", "db", "--field", "pass")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "s3cret\n", stdout)
+
+	code, stdout, _ = vault("team", "", "ls", "--tag", "prod")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "db #prod")
+
+	code, _, _ = vault("team", "", "set", "db", "--meta", "changed")
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = vault("team", "", "share", "db", "alice")
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = run(t, "", "org", "remove", "team", "alice")
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = run(t, "alice-pass\n", "login", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "org", "members", "team", "--json")
+	require.Equal(t, ExitOK, code)
+	var members []memberJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &members))
+	assert.Equal(t, []memberJSON{{Login: "alice", Role: "owner", Accepted: true}, {Login: "bob", Role: "viewer", Accepted: true},
+		{Login: "carol", Role: "viewer"}}, members)
+
+	code, _, _ = run(t, "", "org", "role", "team", "alice", "admin")
+	assert.Equal(t, ExitConflict, code)
+
+	// исключение участника меняет ключ хранилища, данные остаются доступны оставшимся участникам
+	code, _, _ = run(t, "", "org", "remove", "team", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = vault("team", "", "ls", "--tag", "prod", "--contains", "d")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "db #prod")
+
+	code, stdout, _ = vault("team", "", "get", "wiki", "--field", "text")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "hello\n", stdout)
+
+	code, _, _ = vault("team", "", "set", "db", "--meta", "after rotation")
+	require.Equal(t, ExitOK, code)
+
+	// приглашенная участница получила новый ключ вместе с остальными
+	code, _, _ = run(t, "carol-pass\n", "login", "carol")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "org", "accept", "team")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = vault("team", "", "get", "db", "--field", "meta")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "after rotation\n", stdout)
+
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = vault("team", "", "get", "db")
+	assert.Equal(t, ExitNotFound, code)
+}
+
+func TestRun_Emergency(t *testing.T) {
+	initTestApp(t)
+	client.AppInstance.EncryptNames = true
+
+	// vault открывает хранилище по экстренному доступу для команд с данными
+	vault := func(owner string, args ...string) (int, string, string) {
+		client.AppInstance.VaultName = data2.EmergencyVaultPrefix + owner
+		defer func() {
+			client.AppInstance.VaultName = ""
+		}()
+
+		return run(t, "", args...)
+	}
+
+	for _, login := range []string{"bob", "alice"} {
+		code, _, _ := run(t, login+"-pass\n", "login", "--register", login)
+		require.Equal(t, ExitOK, code)
+	}
+
+	code, _, _ := run(t, "s3cret\n", "set", "db", "--login", "admin", "--stdin", "pass")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "emergency", "add", "alice")
+	assert.Equal(t, ExitConflict, code)
+
+	code, _, _ = run(t, "", "emergency", "add", "bob", "--wait", "1h")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "emergency", "add", "bob")
+	assert.Equal(t, ExitConflict, code)
+
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "emergency", "request", "alice")
+	assert.Equal(t, ExitConflict, code)
+
+	for _, cmd := range []string{"accept", "request"} {
+		code, _, _ = run(t, "", "emergency", cmd, "alice")
+		require.Equal(t, ExitOK, code)
+	}
+
+	code, stdout, _ := run(t, "", "emergency", "ls", "--json")
+	require.Equal(t, ExitOK, code)
+	var grants []grantJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &grants))
+	require.Len(t, grants, 1)
+	assert.Equal(t, "requested", grants[0].State)
+	assert.Equal(t, "1h0m0s", grants[0].Wait)
+	assert.NotEmpty(t, grants[0].RequestedAt)
+
+	// до одобрения ключ хранилища не выдается
+	code, _, _ = vault("alice", "ls")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, _, _ = run(t, "", "emergency", "approve", "alice")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, _, _ = run(t, "alice-pass\n", "login", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "emergency", "ls")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "to bob\trequested")
+
+	code, _, _ = run(t, "", "emergency", "approve", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = vault("alice", "get", "db", "--field", "pass")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "s3cret\n", stdout)
+
+	code, _, _ = vault("alice", "set", "db", "--meta", "changed")
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = vault("alice", "rm", "db")
+	assert.Equal(t, ExitAuth, code)
+
+	// личное хранилище доверенного лица не изменилось
+	code, stdout, _ = run(t, "", "ls")
+	require.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+
+	code, _, _ = run(t, "alice-pass\n", "login", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "", "emergency", "reject", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = vault("alice", "ls")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, _, _ = run(t, "", "emergency", "revoke", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ = run(t, "", "emergency", "ls")
+	require.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+}
+
+func TestRun_Import(t *testing.T) {
+	initTestApp(t)
+
+	keepass := filepath.Join("..", "..", "..", "client", "importer", "testdata", "keepass.kdbx")
+	bitwarden := filepath.Join("..", "..", "..", "client", "importer", "testdata", "bitwarden.json")
+
+	code, _, _ := run(t, "secret-pass\n", "login", "--register", "importer")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "wrong\n", "import", "keepass", keepass)
+	assert.Equal(t, ExitAuth, code)
+
+	// предпросмотр ничего не сохраняет и не выводит секреты
+	code, stdout, _ := run(t, "import-test\n", "import", "--dry-run", "keepass", keepass)
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "create     GitHub  (login, pass, otp, text, meta, tags, file recovery-codes.txt)")
+	assert.Contains(t, stdout, "create     db-primary [Work/Servers]")
+	assert.Contains(t, stdout, "2 to create, 0 to rename, 0 to overwrite, 0 to skip")
+	assert.NotContains(t, stdout, "gh-pass")
+
+	code, stdout, _ = run(t, "", "ls")
+	require.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+
+	code, stdout, stderr := run(t, "import-test\n", "import", "keepass", keepass)
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "created 2, overwritten 0, skipped 0, failed 0\n", stdout)
+	assert.Contains(t, stderr, `"avatar.png" skipped`)
+
+	code, stdout, _ = run(t, "", "get", "--json", "GitHub")
+	require.Equal(t, ExitOK, code)
+	var got dataJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	assert.Equal(t, "gh-pass", got.Pass)
+	assert.Equal(t, "recovery-codes.txt", got.FileName)
+	assert.Equal(t, []string{"dev", "oss"}, got.Tags)
+
+	code, stdout, _ = run(t, "", "ls", "--folder", "Work/Servers")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "db-primary")
+
+	// совпадающие названия по умолчанию пропускаются
+	code, stdout, _ = run(t, "import-test\n", "import", "keepass", keepass)
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "created 0, overwritten 0, skipped 2, failed 0\n", stdout)
+
+	code, stdout, _ = run(t, "import-test\n", "import", "--dry-run", "--json", "--on-conflict", "rename", "keepass", keepass)
+	require.Equal(t, ExitOK, code)
+	var preview importJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &preview))
+	require.Len(t, preview.Plan, 2)
+	assert.Equal(t, "rename", preview.Plan[0].Action)
+	assert.Equal(t, "GitHub (2)", preview.Plan[0].Name)
+	assert.Equal(t, "GitHub", preview.Plan[0].Source)
+
+	code, stdout, _ = run(t, "", "import", "--json", "bitwarden", bitwarden)
+	require.Equal(t, ExitOK, code)
+	var imported importJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &imported))
+	require.NotNil(t, imported.Result)
+	assert.Equal(t, 4, imported.Result.Created)
+	assert.Len(t, imported.Warnings, 1)
+
+	code, stdout, _ = run(t, "", "get", "AWS", "--field", "otp")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP\n", stdout)
+
+	code, _, _ = run(t, "", "import", "lastpass", bitwarden)
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Backup(t *testing.T) {
+	initTestApp(t)
+
+	kdf := backup.DefaultKDF
+	backup.DefaultKDF = backup.KDF{Time: 1, Memory: 64, Threads: 1}
+	t.Cleanup(func() {
+		backup.DefaultKDF = kdf
+	})
+
+	const passphrase = "backup-passphrase\n"
+	archive := filepath.Join(t.TempDir(), "vault.gkb")
+	attachment := filepath.Join(t.TempDir(), "id_rsa")
+	require.NoError(t, os.WriteFile(attachment, []byte("private key"), 0600))
+
+	code, _, _ := run(t, "secret-pass\n", "login", "--register", "alice")
+	require.Equal(t, ExitOK, code)
+
+	_, err := data2.SaveFolder(domain.Folder{Name: "Empty"})
+	require.NoError(t, err)
+	work, err := data2.SaveFolder(domain.Folder{Name: "Work"})
+	require.NoError(t, err)
+	_, err = data2.SaveFolder(domain.Folder{Name: "Servers", ParentID: work.ID})
+	require.NoError(t, err)
+
+	code, _, _ = run(t, "p@ss\n", "set", "db", "--login", "admin", "--folder", "Work/Servers", "--tag", "prod", "--stdin", "pass")
+	require.Equal(t, ExitOK, code)
+	code, _, _ = run(t, "JBSWY3DPEHPK3PXP", "set", "keys", "--text", "note", "--card", "4111", "--exp", "08/27", "--meta", "m", "--stdin", "otp")
+	require.Equal(t, ExitOK, code)
+	code, _, _ = run(t, "", "attach", "keys", attachment)
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "short\n", "export", archive)
+	assert.Equal(t, ExitUsage, code)
+
+	code, stdout, _ := run(t, passphrase, "export", archive)
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "exported 2 records, 1 files, 3 folders to "+archive+"\n", stdout)
+
+	// выгрузка не оставляет расшифрованных копий файлов
+	entries, err := os.ReadDir(filepath.Join(client.AppInstance.DataSavePath, "alice"))
+	if err == nil {
+		for _, e := range entries {
+			files, _ := os.ReadDir(filepath.Join(client.AppInstance.DataSavePath, "alice", e.Name()))
+			assert.Empty(t, files)
+		}
+	}
+
+	code, _, _ = run(t, passphrase, "export", archive)
+	assert.Equal(t, ExitConflict, code)
+
+	exported := func() map[string]dataJSON {
+		res := make(map[string]dataJSON)
+		for _, name := range []string{"db", "keys"} {
+			code, stdout, _ := run(t, "", "get", "--json", name)
+			require.Equal(t, ExitOK, code)
+
+			var d dataJSON
+			require.NoError(t, json.Unmarshal([]byte(stdout), &d))
+			d.ID, d.Version = 0, 0
+			res[name] = d
+		}
+
+		return res
+	}
+	want := exported()
+
+	code, _, _ = run(t, "secret-pass\n", "login", "--register", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "wrong-passphrase\n", "import", "--from-backup", archive)
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = run(t, passphrase, "import", "--from-backup", archive, "keepass")
+	assert.Equal(t, ExitUsage, code)
+
+	code, stdout, _ = run(t, passphrase, "import", "--from-backup", archive)
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "created 2, overwritten 0, skipped 0, failed 0\n", stdout)
+
+	assert.Equal(t, want, exported())
+
+	// повторная выгрузка восстановленного хранилища совпадает с исходной во всем, кроме версий записей
+	restored := filepath.Join(t.TempDir(), "restored.gkb")
+	code, _, _ = run(t, passphrase, "export", restored)
+	require.Equal(t, ExitOK, code)
+
+	readArchive := func(path string) *backup.Backup {
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		b, err := backup.Read(bytes.NewReader(raw), int64(len(raw)), strings.TrimSpace(passphrase))
+		require.NoError(t, err)
+
+		for i := range b.Records {
+			assert.NotZero(t, b.Records[i].Version)
+			b.Records[i].Version = 0
+		}
+
+		return b
+	}
+	source, copied := readArchive(archive), readArchive(restored)
+	assert.Equal(t, source.Folders, copied.Folders)
+	assert.Equal(t, source.Records, copied.Records)
+
+	code, stdout, _ = run(t, "", "download", "keys")
+	require.Equal(t, ExitOK, code)
+	raw, err := os.ReadFile(strings.TrimSpace(stdout))
+	require.NoError(t, err)
+	assert.Equal(t, "private key", string(raw))
+
+	folders, err := data2.GetFolders()
+	require.NoError(t, err)
+	_, err = data2.FindFolder(folders, "Empty")
+	assert.NoError(t, err)
+
+	// повторное восстановление пропускает существующие записи
+	code, stdout, _ = run(t, passphrase, "import", "--from-backup", archive)
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "created 0, overwritten 0, skipped 2, failed 0\n", stdout)
+
+	require.NoError(t, os.WriteFile(archive, []byte("not a backup"), 0600))
+	code, _, _ = run(t, passphrase, "import", "--from-backup", archive)
+	assert.Equal(t, ExitConflict, code)
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "ok", err: nil, want: ExitOK},
+		{name: "not logged in", err: domain.ErrNotLoggedIn, want: ExitAuth},
+		{name: "unauthenticated", err: status.Error(codes.Unauthenticated, "token"), want: ExitAuth},
+		{name: "not found", err: status.Error(codes.NotFound, "data"), want: ExitNotFound},
+		{name: "name exists", err: status.Error(codes.AlreadyExists, "name"), want: ExitConflict},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "server"), want: ExitUnavailable},
+		{name: "too many attempts", err: domain.ErrTooManyAttempts, want: ExitUnavailable},
+		{name: "rate limited", err: status.Error(codes.ResourceExhausted, "too many attempts"), want: ExitUnavailable},
+		{name: "shared read-only", err: domain.ErrSharedReadOnly, want: ExitAuth},
+		{name: "no key pair", err: domain.ErrNoKeyPair, want: ExitConflict},
+		{name: "vault read-only", err: domain.ErrVaultReadOnly, want: ExitAuth},
+		{name: "vault not found", err: domain.ErrVaultNotFound, want: ExitNotFound},
+		{name: "key rotated", err: status.Error(codes.Aborted, "key version"), want: ExitConflict},
+		{name: "other", err: domain.ErrEncryptData, want: ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestRun_ShareLink(t *testing.T) {
+	initTestApp(t)
+
+	code, _, _ := run(t, "alice-pass\n", "login", "--register", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "s3cret\n", "set", "db", "--login", "admin", "--stdin", "pass")
+	require.Equal(t, ExitOK, code)
+
+	// open получение секрета по ссылке так, как это делает страница в браузере
+	open := func(link string) (int, string) {
+		page, key, ok := strings.Cut(link, "#")
+		require.True(t, ok)
+
+		resp, err := http.Post(page, "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var blob bytes.Buffer
+		_, err = blob.ReadFrom(resp.Body)
+		require.NoError(t, err)
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, ""
+		}
+
+		raw, err := base64.RawURLEncoding.DecodeString(key)
+		require.NoError(t, err)
+
+		secret, err := crypto.Decrypt(raw, blob.String())
+		require.NoError(t, err)
+
+		return resp.StatusCode, secret
+	}
+
+	code, stdout, _ := run(t, "", "share", "--link", "db")
+	require.Equal(t, ExitOK, code)
+	link := strings.TrimSpace(stdout)
+	assert.Contains(t, link, "/s/")
+
+	status, secret := open(link)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "s3cret", secret)
+
+	status, _ = open(link)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	code, stdout, _ = run(t, "token\n", "share", "--link", "--stdin", "--views", "2", "--expire", "1h")
+	require.Equal(t, ExitOK, code)
+	link = strings.TrimSpace(stdout)
+
+	for i := 0; i < 2; i++ {
+		status, secret = open(link)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "token", secret)
+	}
+
+	status, _ = open(link)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	code, _, _ = run(t, "", "share", "--link", "db", "--field", "owner")
+	assert.Equal(t, ExitUsage, code)
+
+	code, _, _ = run(t, "", "share", "--link", "--stdin", "db")
+	assert.Equal(t, ExitUsage, code)
+
+	code, _, _ = run(t, "", "share", "--link", "db", "--expire", "1000h")
+	assert.Equal(t, ExitConflict, code)
+}
+
+func TestRun_Activity(t *testing.T) {
+	initTestApp(t)
+
+	code, _, _ := run(t, "alice-pass\n", "login", "--register", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "s3cret\n", "set", "db", "--login", "admin", "--stdin", "pass")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "wrong-pass\n", "login", "alice")
+	require.Equal(t, ExitNotFound, code)
+
+	code, _, _ = run(t, "alice-pass\n", "login", "alice")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ := run(t, "", "activity", "--json")
+	require.Equal(t, ExitOK, code)
+
+	var events []activityJSON
+	require.NoError(t, json.Unmarshal([]byte(stdout), &events))
+
+	var actions []string
+	for _, ev := range events {
+		assert.Equal(t, "alice", ev.Actor)
+		actions = append(actions, ev.Action+":"+ev.Outcome)
+	}
+	assert.Equal(t, []string{"login:ok", "login:denied", "save:ok", "register:ok"}, actions)
+
+	code, stdout, _ = run(t, "", "activity", "--outcome", "denied", "--json")
+	require.Equal(t, ExitOK, code)
+	require.NoError(t, json.Unmarshal([]byte(stdout), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "login", events[0].Action)
+
+	code, stdout, _ = run(t, "", "activity", "--record", "db", "--action", "save", "--json")
+	require.Equal(t, ExitOK, code)
+	require.NoError(t, json.Unmarshal([]byte(stdout), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "db", events[0].Record)
+
+	code, stdout, _ = run(t, "", "activity", "--limit", "1")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, 1, strings.Count(stdout, "\n"))
+
+	code, _, _ = run(t, "", "activity", "--action", "hack")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Account(t *testing.T) {
+	initTestApp(t)
+
+	for _, login := range []string{"bob", "alice"} {
+		code, _, _ := run(t, login+"-pass\n", "login", "--register", login)
+		require.Equal(t, ExitOK, code)
+	}
+
+	code, _, _ := run(t, "s3cret\n", "set", "db", "--login", "admin", "--stdin", "pass")
+	require.Equal(t, ExitOK, code)
+	attachment := filepath.Join(t.TempDir(), "id_rsa")
+	require.NoError(t, os.WriteFile(attachment, []byte("private key"), 0600))
+	code, _, _ = run(t, "", "attach", "db", attachment)
+	require.Equal(t, ExitOK, code)
+	code, _, _ = run(t, "", "share", "db", "bob")
+	require.Equal(t, ExitOK, code)
+
+	code, _, _ = run(t, "wrong-pass\n", "account", "delete", "--grace", "7")
+	assert.Equal(t, ExitAuth, code)
+
+	code, _, _ = run(t, "alice-pass\n", "account", "delete", "--grace", "31")
+	assert.Equal(t, ExitUsage, code)
+
+	code, stdout, _ := run(t, "alice-pass\n", "account", "delete", "--grace", "7")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "account will be deleted at")
+
+	// сессия завершена, вход запрещен до отмены удаления
+	code, _, _ = run(t, "", "ls")
+	assert.Equal(t, ExitAuth, code)
+	code, _, stderr := run(t, "alice-pass\n", "login", "alice")
+	assert.Equal(t, ExitConflict, code)
+	assert.Contains(t, stderr, "account is scheduled for deletion")
+
+	code, _, _ = run(t, "alice-pass\n", "account", "restore", "alice")
+	require.Equal(t, ExitOK, code)
+	code, _, _ = run(t, "alice-pass\n", "account", "restore", "alice")
+	assert.Equal(t, ExitConflict, code)
+
+	code, stdout, _ = run(t, "", "get", "db", "--field", "pass")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "s3cret\n", stdout)
+
+	code, stdout, _ = run(t, "alice-pass\n", "account", "delete")
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "account deleted\n", stdout)
+
+	code, _, _ = run(t, "alice-pass\n", "login", "alice")
+	assert.Equal(t, ExitNotFound, code)
+
+	// запись, к которой у bob был доступ, удалена вместе с учетной записью
+	code, _, _ = run(t, "bob-pass\n", "login", "bob")
+	require.Equal(t, ExitOK, code)
+	code, stdout, _ = run(t, "", "ls", "--shared")
+	require.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+}
@@ -0,0 +1,517 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/otp"
+	"gophkeeper/client/user"
+	"gophkeeper/internal/client"
+	domain2 "gophkeeper/server/domain"
+	"io"
+	"os"
+	"strings"
+)
+
+// passwordEnv переменная окружения с паролем для команды login
+const passwordEnv = "GOPHKEEPER_PASSWORD"
+
+// dataJSON представление данных в выводе --json
+type dataJSON struct {
+	ID       uint64   `json:"id"`
+	Version  uint64   `json:"version"`
+	Name     string   `json:"name"`
+	Login    string   `json:"login,omitempty"`
+	Pass     string   `json:"pass,omitempty"`
+	CardNum  string   `json:"card,omitempty"`
+	CardExp  string   `json:"exp,omitempty"`
+	Otp      string   `json:"otp,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	Meta     string   `json:"meta,omitempty"`
+	FileName string   `json:"file,omitempty"`
+	Folder   string   `json:"folder,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	SharedBy string   `json:"shared_by,omitempty"`
+	ReadOnly bool     `json:"read_only,omitempty"`
+}
+
+// dataNameJSON элемент списка в выводе --json
+type dataNameJSON struct {
+	ID       uint64   `json:"id"`
+	Name     string   `json:"name"`
+	Folder   string   `json:"folder,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	SharedBy string   `json:"shared_by,omitempty"`
+}
+
+var dataTypes = map[string]domain2.DataType{
+	"":            domain2.DataTypeAny,
+	"credentials": domain2.DataTypeCredentials,
+	"card":        domain2.DataTypeCard,
+	"text":        domain2.DataTypeText,
+	"file":        domain2.DataTypeFile,
+	"otp":         domain2.DataTypeOTP,
+}
+
+var dataSorts = map[string]domain2.DataSort{
+	"name":  domain2.DataSortNameAsc,
+	"-name": domain2.DataSortNameDesc,
+	"id":    domain2.DataSortIDAsc,
+	"-id":   domain2.DataSortIDDesc,
+}
+
+func runLogin(e env, args []string) error {
+	fs := newFlagSet("login", e)
+	register := fs.Bool("register", false, "register a new user")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	pass, err := password(e)
+	if err != nil {
+		return err
+	}
+
+	if err = user.Auth(positional[0], pass, !*register); err != nil {
+		return err
+	}
+
+	if err = user.SaveSession(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout, "logged in as", positional[0])
+
+	return nil
+}
+
+func runLogout(e env, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	return user.Logout()
+}
+
+func runList(e env, args []string) error {
+	var tags stringsFlag
+
+	fs := newFlagSet("ls", e)
+	prefix := fs.String("prefix", "", "name prefix")
+	contains := fs.String("contains", "", "name substring")
+	dataType := fs.String("type", "", "data type: credentials, card, text or file")
+	folder := fs.String("folder", "", "folder path")
+	sort := fs.String("sort", "name", "sort order: name, -name, id or -id")
+	shared := fs.Bool("shared", false, "only data shared with you by other users")
+	asJSON := fs.Bool("json", false, "json output")
+	fs.Var(&tags, "tag", "tag, can be repeated")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	t, okType := dataTypes[*dataType]
+	s, okSort := dataSorts[*sort]
+	if len(positional) != 0 || !okType || !okSort {
+		return errUsage
+	}
+
+	folders, err := e.vault.Folders()
+	if err != nil {
+		return err
+	}
+
+	folderID, err := data.FindFolder(folders, *folder)
+	if err != nil {
+		return err
+	}
+
+	list, err := e.vault.List(domain.DataListFilter{
+		NamePrefix:   *prefix,
+		NameContains: *contains,
+		Type:         t,
+		Sort:         s,
+		FolderID:     folderID,
+		Tags:         tags,
+		Shared:       *shared,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		res := make([]dataNameJSON, 0, len(list))
+		for _, d := range list {
+			item := dataNameJSON{ID: d.ID, Name: d.Name, Folder: folderPath(folders, d.FolderID), Tags: d.Tags}
+			if d.Share != nil {
+				item.SharedBy = d.Share.Owner
+			}
+
+			res = append(res, item)
+		}
+
+		return writeJSON(e.stdout, res)
+	}
+
+	for _, d := range list {
+		line := fmt.Sprintf("%d\t%s", d.ID, d.Name)
+		for _, tag := range d.Tags {
+			line += " #" + tag
+		}
+
+		if d.Share != nil {
+			line += " (shared by " + d.Share.Owner + ")"
+		}
+
+		fmt.Fprintln(e.stdout, line)
+	}
+
+	return nil
+}
+
+func runGet(e env, args []string) error {
+	fs := newFlagSet("get", e)
+	field := fs.String("field", "", "print only this field")
+	asJSON := fs.Bool("json", false, "json output")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	d, err := e.vault.Get(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if *field != "" {
+		value, ok := fieldValue(*d, *field)
+		if !ok {
+			return errUsage
+		}
+
+		fmt.Fprintln(e.stdout, value)
+
+		return nil
+	}
+
+	folders, err := e.vault.Folders()
+	if err != nil {
+		return err
+	}
+
+	var folderID *uint64
+	if d.FolderID != 0 {
+		folderID = &d.FolderID
+	}
+
+	res := dataJSON{
+		ID:       d.ID,
+		Version:  d.Version,
+		Name:     d.Name,
+		Login:    d.Login,
+		Pass:     d.Pass,
+		CardNum:  d.CardNum,
+		CardExp:  d.CardExp,
+		Otp:      d.Otp,
+		Text:     d.Text,
+		Meta:     d.Meta,
+		FileName: d.FileName,
+		Folder:   folderPath(folders, folderID),
+		Tags:     d.Tags,
+		SharedBy: d.SharedBy,
+		ReadOnly: d.SharedBy != "" && d.Permission != domain2.SharePermissionWrite,
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, res)
+	}
+
+	// пароль и ключ одноразовых паролей выводятся только явным запросом --field или в json
+	res.Pass = strings.Repeat("*", len([]rune(res.Pass)))
+	res.Otp = strings.Repeat("*", min(len(res.Otp), 8))
+
+	fmt.Fprintf(e.stdout, "id: %d\nname: %s\nlogin: %s\npass: %s\ncard: %s\nexp: %s\notp: %s\ntext: %s\nmeta: %s\nfile: %s\nfolder: %s\ntags: %s\n",
+		res.ID, res.Name, res.Login, res.Pass, res.CardNum, res.CardExp, res.Otp, res.Text, res.Meta, res.FileName, res.Folder, strings.Join(res.Tags, ","))
+
+	if res.SharedBy != "" {
+		access := "write"
+		if res.ReadOnly {
+			access = "read"
+		}
+
+		fmt.Fprintf(e.stdout, "shared by: %s (%s)\n", res.SharedBy, access)
+	}
+
+	return nil
+}
+
+func runSet(e env, args []string) error {
+	var tags stringsFlag
+
+	fs := newFlagSet("set", e)
+	fs.String("login", "", "login")
+	fs.String("text", "", "text")
+	fs.String("card", "", "card number")
+	fs.String("exp", "", "card expiry date MM/YY")
+	fs.String("meta", "", "meta")
+	folder := fs.String("folder", "", "folder path")
+	stdinField := fs.String("stdin", "", "read value of this field from stdin, e.g. pass")
+	asJSON := fs.Bool("json", false, "json output")
+	fs.Var(&tags, "tag", "tag, can be repeated, replaces existing tags")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	d, err := data.FindData(positional[0])
+	if errors.Is(err, domain.ErrDataNotFound) {
+		d, err = &domain.Data{Name: positional[0]}, nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// изменяются только явно указанные поля
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "login", "text", "card", "exp", "meta":
+			setField(d, f.Name, f.Value.String())
+		case "tag":
+			d.Tags = tags
+		}
+	})
+
+	if *folder != "" {
+		folders, err := data.GetFolders()
+		if err != nil {
+			return err
+		}
+
+		if d.FolderID, err = data.FindFolder(folders, *folder); err != nil {
+			return err
+		}
+	}
+
+	if *stdinField != "" {
+		raw, err := io.ReadAll(e.stdin)
+		if err != nil {
+			return err
+		}
+
+		if !setField(d, *stdinField, strings.TrimRight(string(raw), "\r\n")) {
+			return errUsage
+		}
+	}
+
+	// ключ проверяется до сохранения, чтобы ошибка в URI не обнаружилась только при получении пароля
+	if d.Otp != "" {
+		if _, err = otp.Parse(d.Otp); err != nil {
+			return err
+		}
+	}
+
+	saved, err := data.SaveData(*d)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, map[string]uint64{"id": saved.ID, "version": saved.Version})
+	}
+
+	fmt.Fprintln(e.stdout, saved.ID)
+
+	return nil
+}
+
+func runRemove(e env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	d, err := data.FindData(args[0])
+	if err != nil {
+		return err
+	}
+
+	return data.DeleteData(d.ID)
+}
+
+func runAttach(e env, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	d, err := data.FindData(args[0])
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(args[1]); err != nil {
+		return err
+	}
+
+	d.FilePath = args[1]
+
+	_, err = data.SaveData(*d)
+
+	return err
+}
+
+func runDownload(e env, args []string) error {
+	fs := newFlagSet("download", e)
+	output := fs.String("o", "", "copy decrypted file to this path")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	d, err := data.FindData(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if d.FileName == "" {
+		return errNoFile
+	}
+
+	path, err := data.DownloadFile(*d)
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if err = os.WriteFile(*output, raw, 0600); err != nil {
+			return err
+		}
+
+		path = *output
+	}
+
+	fmt.Fprintln(e.stdout, path)
+
+	return nil
+}
+
+func runSync(e env, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	if err := data.SyncSearchIndex(); err != nil {
+		return err
+	}
+
+	if idx := client.AppInstance.SearchIndex; idx != nil {
+		fmt.Fprintf(e.stdout, "indexed %d records\n", idx.Len())
+	}
+
+	return nil
+}
+
+// fieldValue значение поля данных по его названию в командах
+func fieldValue(d domain.Data, field string) (string, bool) {
+	switch field {
+	case "name":
+		return d.Name, true
+	case "login":
+		return d.Login, true
+	case "pass":
+		return d.Pass, true
+	case "card":
+		return d.CardNum, true
+	case "exp":
+		return d.CardExp, true
+	case "otp":
+		return d.Otp, true
+	case "text":
+		return d.Text, true
+	case "meta":
+		return d.Meta, true
+	case "file":
+		return d.FileName, true
+	}
+
+	return "", false
+}
+
+// setField изменение поля данных по его названию в командах
+func setField(d *domain.Data, field, value string) bool {
+	switch field {
+	case "login":
+		d.Login = value
+	case "pass":
+		d.Pass = value
+	case "card":
+		d.CardNum = value
+	case "exp":
+		d.CardExp = value
+	case "otp":
+		d.Otp = value
+	case "text":
+		d.Text = value
+	case "meta":
+		d.Meta = value
+	default:
+		return false
+	}
+
+	return true
+}
+
+func folderPath(folders []domain.Folder, id *uint64) string {
+	if id == nil {
+		return ""
+	}
+
+	return data.FolderPath(folders, *id)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// readLine чтение первой строки, например пароля из stdin
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
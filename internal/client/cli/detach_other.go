@@ -0,0 +1,10 @@
+//go:build !unix
+
+package cli
+
+import "syscall"
+
+// detachAttr на остальных платформах агент запускается без отделения от терминала
+func detachAttr() *syscall.SysProcAttr {
+	return nil
+}
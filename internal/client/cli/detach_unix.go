@@ -0,0 +1,10 @@
+//go:build unix
+
+package cli
+
+import "syscall"
+
+// detachAttr фоновый агент запускается в новой сессии, чтобы не завершаться вместе с терминалом
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
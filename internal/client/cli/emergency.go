@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	"time"
+)
+
+// defaultEmergencyWait время ожидания по умолчанию, после которого запрос доступа одобряется автоматически
+const defaultEmergencyWait = 72 * time.Hour
+
+// grantJSON элемент списка экстренных доступов в выводе --json
+type grantJSON struct {
+	Grantor     string `json:"grantor"`
+	Grantee     string `json:"grantee"`
+	State       string `json:"state"`
+	Wait        string `json:"wait"`
+	RequestedAt string `json:"requested_at,omitempty"`
+}
+
+// runEmergency управление экстренным доступом: emergency <подкоманда> [args]
+// доверенное лицо и владелец хранилища указываются логином
+func runEmergency(e env, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	var change func(login string) error
+
+	switch args[0] {
+	case "ls":
+		return runEmergencyList(e, args[1:])
+	case "add":
+		return runEmergencyAdd(e, args[1:])
+	case "accept":
+		change = data.AcceptGrant
+	case "request":
+		change = data.RequestAccess
+	case "approve":
+		change = data.ApproveAccess
+	case "reject":
+		change = data.RejectAccess
+	case "revoke":
+		change = data.RevokeGrant
+	default:
+		return errUsage
+	}
+
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	return change(args[1])
+}
+
+func runEmergencyList(e env, args []string) error {
+	fs := newFlagSet("emergency ls", e)
+	asJSON := fs.Bool("json", false, "json output")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 0 {
+		return errUsage
+	}
+
+	list, err := data.GetGrants()
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		res := make([]grantJSON, 0, len(list))
+		for _, g := range list {
+			item := grantJSON{Grantor: g.Grantor, Grantee: g.Grantee, State: domain.EmergencyStateName(g.State), Wait: g.Wait.String()}
+			if g.RequestedAt != nil {
+				item.RequestedAt = g.RequestedAt.UTC().Format(time.RFC3339)
+			}
+
+			res = append(res, item)
+		}
+
+		return writeJSON(e.stdout, res)
+	}
+
+	for _, g := range list {
+		// для владельца показывается доверенное лицо, для доверенного лица - владелец хранилища
+		line := "to " + g.Grantee
+		if g.Grantee == client.AppInstance.User.Login {
+			line = "from " + g.Grantor
+		}
+
+		line += "\t" + domain.EmergencyStateName(g.State) + "\twait " + g.Wait.String()
+		if g.RequestedAt != nil {
+			line += "\trequested " + g.RequestedAt.Format(time.DateTime)
+		}
+
+		fmt.Fprintln(e.stdout, line)
+	}
+
+	return nil
+}
+
+func runEmergencyAdd(e env, args []string) error {
+	fs := newFlagSet("emergency add", e)
+	wait := fs.Duration("wait", defaultEmergencyWait, "waiting period before the request is approved automatically")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 || *wait < time.Second {
+		return errUsage
+	}
+
+	return data.CreateGrant(positional[0], *wait)
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"gophkeeper/client/backup"
+	"gophkeeper/client/data"
+	"os"
+)
+
+// backupPassphraseEnv переменная окружения с паролем архива для команд export и import --from-backup
+const backupPassphraseEnv = "GOPHKEEPER_BACKUP_PASSPHRASE"
+
+// runExport выгрузка всего хранилища в зашифрованный архив
+// существующий файл перезаписывается только с --force
+func runExport(e env, args []string) error {
+	fs := newFlagSet("export", e)
+	force := fs.Bool("force", false, "overwrite existing file")
+	asJSON := fs.Bool("json", false, "json output")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	passphrase, err := backupPassphrase(e)
+	if err != nil {
+		return err
+	}
+
+	b, err := data.ExportBackup()
+	if err != nil {
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(positional[0], flags, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err = backup.Write(f, b, passphrase, backup.DefaultKDF); err != nil {
+		f.Close()
+		// недописанный архив не оставляется
+		os.Remove(positional[0])
+
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	files := 0
+	for _, r := range b.Records {
+		if r.File != nil {
+			files++
+		}
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, map[string]int{"records": len(b.Records), "files": files, "folders": len(b.Folders)})
+	}
+
+	fmt.Fprintf(e.stdout, "exported %d records, %d files, %d folders to %s\n", len(b.Records), files, len(b.Folders), positional[0])
+
+	return nil
+}
+
+// readBackup чтение архива выгрузки для import --from-backup
+func readBackup(e env, path string) (*backup.Backup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// манифест проверяется до запроса пароля, чтобы не вводить его для чужого или поврежденного файла
+	if _, err = backup.ReadManifest(f, info.Size()); err != nil {
+		return nil, err
+	}
+
+	passphrase, err := backupPassphrase(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return backup.Read(f, info.Size(), passphrase)
+}
+
+func backupPassphrase(e env) (string, error) {
+	if pass := os.Getenv(backupPassphraseEnv); pass != "" {
+		return pass, nil
+	}
+
+	pass, err := readLine(e.stdin)
+	if err == nil && pass == "" {
+		err = errors.New("backup passphrase is required")
+	}
+
+	return pass, err
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"gophkeeper/client/generator"
+	"slices"
+)
+
+// genJSON результат команды gen в выводе --json
+type genJSON struct {
+	Value    string  `json:"value"`
+	Entropy  float64 `json:"entropy"`
+	Strength string  `json:"strength"`
+}
+
+// runGen генерация пароля, пароль выводится в stdout, оценка стойкости - в stderr
+func runGen(e env, args []string) error {
+	o := generator.DefaultOptions()
+
+	fs := newFlagSet("gen", e)
+	mode := fs.String("mode", generator.ModeNames[o.Mode], "random, pronounceable or passphrase")
+	fs.IntVar(&o.Length, "length", o.Length, "password length")
+	fs.IntVar(&o.Words, "words", o.Words, "number of passphrase words")
+	fs.StringVar(&o.Separator, "separator", o.Separator, "passphrase words separator")
+	noLower := fs.Bool("no-lower", false, "without lowercase letters")
+	noUpper := fs.Bool("no-upper", false, "without uppercase letters")
+	noDigits := fs.Bool("no-digits", false, "without digits")
+	noSymbols := fs.Bool("no-symbols", false, "without symbols")
+	ambiguous := fs.Bool("ambiguous", false, "allow ambiguous characters like l, 1, O, 0")
+	asJSON := fs.Bool("json", false, "print result and entropy as JSON")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	m := slices.Index(generator.ModeNames, *mode)
+	if len(positional) != 0 || m < 0 {
+		return errUsage
+	}
+
+	o.Mode = generator.Mode(m)
+	o.Lower = !*noLower
+	o.Upper = !*noUpper
+	o.Digits = !*noDigits
+	o.Symbols = !*noSymbols
+	o.ExcludeAmbiguous = !*ambiguous
+
+	res, err := generator.Generate(o)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, genJSON{Value: res.Value, Entropy: res.Entropy, Strength: res.Strength()})
+	}
+
+	fmt.Fprintln(e.stdout, res.Value)
+	fmt.Fprintf(e.stderr, "entropy: %.1f bits (%s)\n", res.Entropy, res.Strength())
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/importer"
+	"os"
+	"strings"
+)
+
+// keePassPasswordEnv переменная окружения с паролем базы KeePass для команды import
+const keePassPasswordEnv = "GOPHKEEPER_KEEPASS_PASSWORD"
+
+var errImportFailed = errors.New("some items were not imported")
+
+// importStepJSON запись плана импорта в выводе, секреты не выводятся
+type importStepJSON struct {
+	Action string   `json:"action"`
+	Name   string   `json:"name"`
+	Source string   `json:"source,omitempty"`
+	Folder string   `json:"folder,omitempty"`
+	Fields []string `json:"fields"`
+}
+
+type importJSON struct {
+	Plan     []importStepJSON   `json:"plan"`
+	Result   *data.ImportResult `json:"result,omitempty"`
+	Warnings []string           `json:"warnings"`
+}
+
+// runImport импорт экспорта другого менеджера паролей или восстановление архива команды export (--from-backup)
+// с --dry-run выводится только план: какие записи будут созданы, пропущены, переименованы или перезаписаны
+func runImport(e env, args []string) error {
+	fs := newFlagSet("import", e)
+	dryRun := fs.Bool("dry-run", false, "show import plan without saving")
+	onConflict := fs.String("on-conflict", data.ImportSkip, "existing name: skip, rename or overwrite")
+	mapping := fs.String("map", "", "csv column mapping, e.g. name=Title,login=Username,pass=Password")
+	keyFile := fs.String("keyfile", "", "keepass key file")
+	fromBackup := fs.String("from-backup", "", "restore archive created by export")
+	asJSON := fs.Bool("json", false, "json output")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	switch *onConflict {
+	case data.ImportSkip, data.ImportRename, data.ImportOverwrite:
+	default:
+		return errUsage
+	}
+
+	var (
+		parsed  importer.Result
+		folders []string
+	)
+
+	switch {
+	case *fromBackup != "" && len(positional) == 0:
+		b, err := readBackup(e, *fromBackup)
+		if err != nil {
+			return err
+		}
+
+		parsed.Items, folders = b.Items(), b.Folders
+	case *fromBackup == "" && len(positional) == 2:
+		if parsed, err = parseExport(e, positional[0], positional[1], *mapping, *keyFile); err != nil {
+			return err
+		}
+	default:
+		return errUsage
+	}
+
+	plan, err := data.PlanImport(parsed.Items, *onConflict)
+	if err != nil {
+		return err
+	}
+
+	out := importJSON{Plan: make([]importStepJSON, 0, len(plan)), Warnings: parsed.Warnings}
+	if out.Warnings == nil {
+		out.Warnings = []string{}
+	}
+
+	for _, step := range plan {
+		out.Plan = append(out.Plan, planStepJSON(step))
+	}
+
+	if !*asJSON {
+		for _, w := range out.Warnings {
+			fmt.Fprintln(e.stderr, "warning:", w)
+		}
+	}
+
+	if *dryRun {
+		if *asJSON {
+			return writeJSON(e.stdout, out)
+		}
+
+		printImportPlan(e, out.Plan)
+
+		return nil
+	}
+
+	// пустые папки из архива создаются отдельно, папки записей создаются при сохранении
+	if err = data.RestoreFolders(folders); err != nil {
+		return err
+	}
+
+	res, err := data.ApplyImport(plan)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		out.Result = &res
+		if err = writeJSON(e.stdout, out); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range res.Failed {
+			fmt.Fprintf(e.stderr, "failed: %s: %s\n", f.Name, f.Error)
+		}
+
+		fmt.Fprintf(e.stdout, "created %d, overwritten %d, skipped %d, failed %d\n", res.Created, res.Overwritten, res.Skipped, len(res.Failed))
+	}
+
+	if len(res.Failed) > 0 {
+		return fmt.Errorf("%w: %d of %d", errImportFailed, len(res.Failed), len(plan))
+	}
+
+	return nil
+}
+
+// parseExport разбор экспорта другого менеджера паролей
+func parseExport(e env, format, path, mapping, keyFile string) (importer.Result, error) {
+	var err error
+
+	o := importer.Options{}
+	if o.Mapping, err = importer.ParseMapping(mapping); err != nil {
+		return importer.Result{}, err
+	}
+
+	if keyFile != "" {
+		if o.KeyFile, err = os.ReadFile(keyFile); err != nil {
+			return importer.Result{}, err
+		}
+	}
+
+	if format == importer.FormatKeePass {
+		if o.Password = os.Getenv(keePassPasswordEnv); o.Password == "" {
+			if o.Password, err = readLine(e.stdin); err != nil {
+				return importer.Result{}, err
+			}
+		}
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return importer.Result{}, err
+	}
+
+	return importer.Parse(format, raw, o)
+}
+
+func planStepJSON(step data.ImportStep) importStepJSON {
+	d := step.Item.Data
+	res := importStepJSON{Action: step.Action, Name: step.Name, Folder: step.Item.Folder, Fields: []string{}}
+
+	if step.Name != d.Name {
+		res.Source = d.Name
+	}
+
+	for _, f := range []struct {
+		name, value string
+	}{
+		{"login", d.Login}, {"pass", d.Pass}, {"card", d.CardNum}, {"exp", d.CardExp},
+		{"otp", d.Otp}, {"text", d.Text}, {"meta", d.Meta}, {"tags", strings.Join(d.Tags, ",")},
+	} {
+		if f.value != "" {
+			res.Fields = append(res.Fields, f.name)
+		}
+	}
+
+	if step.Item.Attachment != nil {
+		res.Fields = append(res.Fields, "file "+step.Item.Attachment.Name)
+	}
+
+	return res
+}
+
+func printImportPlan(e env, plan []importStepJSON) {
+	counts := make(map[string]int)
+
+	for _, s := range plan {
+		counts[s.Action]++
+
+		name := s.Name
+		if s.Source != "" {
+			name = s.Source + " -> " + s.Name
+		}
+
+		if s.Folder != "" {
+			name += " [" + s.Folder + "]"
+		}
+
+		fmt.Fprintf(e.stdout, "%-10s %s  (%s)\n", s.Action, name, strings.Join(s.Fields, ", "))
+	}
+
+	fmt.Fprintf(e.stdout, "\n%d to create, %d to rename, %d to overwrite, %d to skip\n",
+		counts[data.ImportActionCreate], counts[data.ImportActionRename], counts[data.ImportActionOverwrite], counts[data.ImportActionSkip])
+}
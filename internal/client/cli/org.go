@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+)
+
+// orgJSON элемент списка организаций в выводе --json
+type orgJSON struct {
+	ID       uint64 `json:"id"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Accepted bool   `json:"accepted"`
+}
+
+// memberJSON участник организации в выводе --json
+type memberJSON struct {
+	Login    string `json:"login"`
+	Role     string `json:"role"`
+	Accepted bool   `json:"accepted"`
+}
+
+// runOrg управление организациями: org <подкоманда> [args]
+func runOrg(e env, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "ls":
+		return runOrgList(e, args[1:])
+	case "create":
+		return runOrgCreate(e, args[1:])
+	case "invite":
+		return runOrgInvite(e, args[1:])
+	case "accept":
+		if len(args) != 2 {
+			return errUsage
+		}
+
+		return data.AcceptInvite(args[1])
+	case "members":
+		return runOrgMembers(e, args[1:])
+	case "role":
+		if len(args) != 4 {
+			return errUsage
+		}
+
+		role, err := domain.ParseOrgRole(args[3])
+		if err != nil {
+			return fmt.Errorf("%w: %s", errUsage, err.Error())
+		}
+
+		return data.SetMemberRole(args[1], args[2], role)
+	case "remove":
+		if len(args) != 3 {
+			return errUsage
+		}
+
+		return data.RemoveMember(args[1], args[2])
+	}
+
+	return errUsage
+}
+
+func runOrgList(e env, args []string) error {
+	fs := newFlagSet("org ls", e)
+	asJSON := fs.Bool("json", false, "json output")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 0 {
+		return errUsage
+	}
+
+	list, err := data.GetOrganizations()
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		res := make([]orgJSON, 0, len(list))
+		for _, o := range list {
+			res = append(res, orgJSON{ID: o.ID, Name: o.Name, Role: domain.OrgRoleName(o.Role), Accepted: o.Accepted})
+		}
+
+		return writeJSON(e.stdout, res)
+	}
+
+	for _, o := range list {
+		line := fmt.Sprintf("%d\t%s\t%s", o.ID, o.Name, domain.OrgRoleName(o.Role))
+		if !o.Accepted {
+			line += " (invited)"
+		}
+
+		fmt.Fprintln(e.stdout, line)
+	}
+
+	return nil
+}
+
+func runOrgCreate(e env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	id, err := data.CreateOrganization(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout, id)
+
+	return nil
+}
+
+func runOrgInvite(e env, args []string) error {
+	fs := newFlagSet("org invite", e)
+	roleName := fs.String("role", "viewer", "member role: viewer, editor, admin or owner")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	role, err := domain.ParseOrgRole(*roleName)
+	if len(positional) != 2 || err != nil {
+		return errUsage
+	}
+
+	return data.InviteMember(positional[0], positional[1], role)
+}
+
+func runOrgMembers(e env, args []string) error {
+	fs := newFlagSet("org members", e)
+	asJSON := fs.Bool("json", false, "json output")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	members, err := data.GetMembers(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		res := make([]memberJSON, 0, len(members))
+		for _, m := range members {
+			res = append(res, memberJSON{Login: m.Login, Role: domain.OrgRoleName(m.Role), Accepted: m.Accepted})
+		}
+
+		return writeJSON(e.stdout, res)
+	}
+
+	for _, m := range members {
+		line := m.Login + "\t" + domain.OrgRoleName(m.Role)
+		if !m.Accepted {
+			line += " (invited)"
+		}
+
+		fmt.Fprintln(e.stdout, line)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/otp"
+)
+
+// otpJSON одноразовый пароль в выводе --json
+type otpJSON struct {
+	Code      string `json:"code"`
+	Type      string `json:"type"`
+	Remaining int    `json:"remaining,omitempty"`
+	Counter   uint64 `json:"counter,omitempty"`
+}
+
+// runOTP текущий одноразовый пароль записи
+// для HOTP счетчик сохраняется на сервере до вывода пароля, оставшееся время TOTP выводится в stderr
+func runOTP(e env, args []string) error {
+	fs := newFlagSet("otp", e)
+	asJSON := fs.Bool("json", false, "json output")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	d, err := data.FindData(positional[0])
+	if err != nil {
+		return err
+	}
+
+	res, err := data.GenerateOTP(d.ID)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		out := otpJSON{Code: res.Code, Type: res.Key.Type, Remaining: int(res.Remaining.Seconds())}
+		if res.Key.Type == otp.TypeHOTP {
+			out.Counter = res.Key.Counter
+		}
+
+		return writeJSON(e.stdout, out)
+	}
+
+	fmt.Fprintln(e.stdout, res.Code)
+
+	if res.Key.Type == otp.TypeTOTP {
+		fmt.Fprintf(e.stderr, "expires in %ds\n", int(res.Remaining.Seconds()))
+	}
+
+	return nil
+}
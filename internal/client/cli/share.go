@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"gophkeeper/client/data"
+	"io"
+	"strings"
+	"time"
+)
+
+// defaultLinkExpire время жизни одноразовой ссылки по умолчанию
+const defaultLinkExpire = 24 * time.Hour
+
+// runShare предоставление другому пользователю доступа к своей записи
+// с --link вместо этого создается одноразовая ссылка на поле записи или на секрет из stdin для человека без учетной записи
+func runShare(e env, args []string) error {
+	fs := newFlagSet("share", e)
+	write := fs.Bool("write", false, "allow changing the data")
+	link := fs.Bool("link", false, "create one-off secret link instead of sharing with user")
+	field := fs.String("field", "pass", "field of data shared by link")
+	fromStdin := fs.Bool("stdin", false, "share secret read from stdin by link")
+	expire := fs.Duration("expire", defaultLinkExpire, "link lifetime")
+	views := fs.Int("views", 1, "how many times the secret can be viewed by link")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *link {
+		return runShareLink(e, positional, *field, *fromStdin, *expire, *views)
+	}
+
+	if len(positional) != 2 {
+		return errUsage
+	}
+
+	d, err := data.FindData(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if err = data.ShareData(*d, positional[1], *write); err != nil {
+		return err
+	}
+
+	access := "read"
+	if *write {
+		access = "write"
+	}
+
+	fmt.Fprintf(e.stdout, "shared %s with %s (%s)\n", d.Name, positional[1], access)
+
+	return nil
+}
+
+// runShareLink создание одноразовой ссылки, секрет шифруется на клиенте, ключ есть только во фрагменте ссылки
+func runShareLink(e env, positional []string, field string, fromStdin bool, expire time.Duration, views int) error {
+	if expire < time.Second || views < 1 || fromStdin != (len(positional) == 0) || len(positional) > 1 {
+		return errUsage
+	}
+
+	var secret string
+
+	if fromStdin {
+		raw, err := io.ReadAll(e.stdin)
+		if err != nil {
+			return err
+		}
+
+		secret = strings.TrimSuffix(string(raw), "\n")
+	} else {
+		d, err := data.FindData(positional[0])
+		if err != nil {
+			return err
+		}
+
+		var ok bool
+		if secret, ok = fieldValue(*d, field); !ok {
+			return errUsage
+		}
+	}
+
+	if secret == "" {
+		return errUsage
+	}
+
+	url, err := data.CreateSecretLink([]byte(secret), expire, views)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout, url)
+
+	return nil
+}
+
+// runUnshare отзыв доступа пользователя к своей записи
+func runUnshare(e env, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	d, err := data.FindData(args[0])
+	if err != nil {
+		return err
+	}
+
+	return data.UnshareData(*d, args[1])
+}
@@ -0,0 +1,162 @@
+package view
+
+import (
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	domain2 "gophkeeper/server/domain"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// activityPageSize число событий журнала на странице
+const activityPageSize = 20
+
+// activityLoadedMsg страница журнала загружена
+type activityLoadedMsg struct {
+	events []domain2.AuditEvent
+	err    error
+}
+
+// ActivityModel журнал аудита сервера: входы, чтение, изменение и скачивание записей
+// отказы и действия других пользователей выделяются, чтобы было проще заметить чужой доступ
+type ActivityModel struct {
+	events []domain2.AuditEvent
+	filter domain2.AuditFilter
+	// pages ИД, с которых начинались предыдущие страницы
+	pages   []uint64
+	loading bool
+	errMsg  string
+}
+
+func InitActivityModel() ActivityModel {
+	return ActivityModel{
+		filter:  domain2.AuditFilter{Limit: activityPageSize},
+		loading: true,
+	}
+}
+
+func (m ActivityModel) Init() tea.Cmd {
+	return loadActivity(m.filter)
+}
+
+func loadActivity(filter domain2.AuditFilter) tea.Cmd {
+	return func() tea.Msg {
+		events, err := data.GetActivity(filter)
+
+		return activityLoadedMsg{events: events, err: err}
+	}
+}
+
+func (m ActivityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case activityLoadedMsg:
+		m.loading = false
+		m.errMsg = ""
+		if msg.err != nil {
+			m.errMsg = msg.err.Error()
+			return m, nil
+		}
+
+		m.events = msg.events
+
+		return m, nil
+	case tea.KeyMsg:
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			return UserModel{}, nil
+		case "r":
+			m.loading = true
+			return m, loadActivity(m.filter)
+		case "f":
+			// только отказы: неверный пароль и попытки открыть чужие записи
+			if m.filter.Outcome == 0 {
+				m.filter.Outcome = domain2.AuditDenied
+			} else {
+				m.filter.Outcome = 0
+			}
+
+			m.filter.BeforeID, m.pages, m.loading = 0, nil, true
+
+			return m, loadActivity(m.filter)
+		case "n":
+			if len(m.events) < activityPageSize {
+				return m, nil
+			}
+
+			m.pages = append(m.pages, m.filter.BeforeID)
+			m.filter.BeforeID = m.events[len(m.events)-1].ID
+			m.loading = true
+
+			return m, loadActivity(m.filter)
+		case "p":
+			if len(m.pages) == 0 {
+				return m, nil
+			}
+
+			m.filter.BeforeID = m.pages[len(m.pages)-1]
+			m.pages = m.pages[:len(m.pages)-1]
+			m.loading = true
+
+			return m, loadActivity(m.filter)
+		}
+	}
+
+	return m, nil
+}
+
+func (m ActivityModel) View() string {
+	s := strings.Builder{}
+
+	if len(m.errMsg) > 0 {
+		s.WriteString(errorStyle.Render(m.errMsg) + "\n\n")
+	}
+
+	title := "Activity"
+	if m.filter.Outcome == domain2.AuditDenied {
+		title += " (denied only)"
+	}
+
+	s.WriteString(actionsStyle.Render(title))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.loading:
+		s.WriteString(blueStyle.Render("loading...") + "\n")
+	case len(m.events) == 0 && m.errMsg == "":
+		s.WriteString(infoStyle.Render("no events") + "\n")
+	}
+
+	if !m.loading {
+		for _, ev := range m.events {
+			line := fmt.Sprintf("%s  %-8s %-6s %-15s %s", ev.CreatedAt.Format(time.DateTime),
+				domain.AuditActionName(ev.Action), domain.AuditOutcomeName(ev.Outcome), ev.IP, ev.Actor)
+			if name := data.ActivityRecordName(ev.DataID); name != "" {
+				line += "  " + name
+			}
+
+			switch {
+			case ev.Outcome != domain2.AuditSuccess:
+				line = errorStyle.Render(line)
+			case ev.Actor != client.AppInstance.User.Login:
+				line = blueStyle.Render(line)
+			}
+
+			s.WriteString(line + "\n")
+		}
+	}
+
+	s.WriteString(helpStyle.Render("\n'f' denied only/all, 'n' older, 'p' newer, 'r' refresh, 'esc' back"))
+	s.WriteString("\n(press q to quit)\n")
+
+	return s.String()
+}
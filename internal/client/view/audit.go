@@ -0,0 +1,218 @@
+package view
+
+import (
+	"fmt"
+	"gophkeeper/client/audit"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// auditBuiltMsg отчет построен
+type auditBuiltMsg struct {
+	report audit.Report
+	err    error
+}
+
+// auditLine строка отчета с записью, к которой можно перейти
+type auditLine struct {
+	id   uint64
+	text string
+}
+
+// AuditModel модель отчета о состоянии хранилища
+// отчет строится в фоне, все записи расшифровываются и проверяются локально
+type AuditModel struct {
+	report  audit.Report
+	lines   []auditLine
+	cursor  int
+	loading bool
+	errMsg  string
+}
+
+func InitAuditModel() AuditModel {
+	return AuditModel{loading: true}
+}
+
+func (m AuditModel) Init() tea.Cmd {
+	return buildAudit
+}
+
+func buildAudit() tea.Msg {
+	o, err := data.AuditOptions("")
+	if err != nil {
+		return auditBuiltMsg{err: err}
+	}
+
+	report, err := data.AuditVault(o)
+
+	return auditBuiltMsg{report: report, err: err}
+}
+
+func (m AuditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case auditBuiltMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.errMsg = msg.err.Error()
+			return m, nil
+		}
+
+		m.report = msg.report
+		m.lines = auditLines(msg.report)
+
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			dl := InitDataListModel()
+			return dl, dl.Init()
+		case "r":
+			m.loading = true
+			return m, buildAudit
+		case "enter":
+			if len(m.lines) == 0 {
+				return m, nil
+			}
+
+			return m.Do()
+		case "down", "j":
+			m.cursor++
+			if m.cursor >= len(m.lines) {
+				m.cursor = 0
+			}
+		case "up", "k":
+			m.cursor--
+			if m.cursor < 0 {
+				m.cursor = max(len(m.lines)-1, 0)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Do переход к редактированию выбранной записи
+func (m AuditModel) Do() (tea.Model, tea.Cmd) {
+	d, err := data.GetData(m.lines[m.cursor].id)
+	if err != nil {
+		m.errMsg = err.Error()
+		return m, nil
+	}
+
+	if d == nil {
+		m.errMsg = domain.ErrDataNotFound.Error()
+		return m, nil
+	}
+
+	dt := InitDataFieldsModel(*d)
+
+	return dt, dt.Init()
+}
+
+// auditLines замечания отчета в порядке важности
+func auditLines(r audit.Report) []auditLine {
+	var lines []auditLine
+
+	for _, b := range r.Breached {
+		lines = append(lines, auditLine{
+			id:   b.ID,
+			text: fmt.Sprintf("breached password: %s (seen %d times in data breaches)", b.Name, b.Count),
+		})
+	}
+
+	for _, w := range r.Weak {
+		text := fmt.Sprintf("weak password: %s (score %d/4", w.Name, w.Score)
+		if w.Warning != "" {
+			text += ", " + w.Warning
+		}
+
+		lines = append(lines, auditLine{id: w.ID, text: text + ")"})
+	}
+
+	for i, group := range r.Reused {
+		names := make([]string, len(group.Records))
+		for j, rec := range group.Records {
+			names[j] = rec.Name
+		}
+
+		for _, rec := range group.Records {
+			lines = append(lines, auditLine{
+				id:   rec.ID,
+				text: fmt.Sprintf("reused password #%d: %s (same as %s)", i+1, rec.Name, strings.Join(names, ", ")),
+			})
+		}
+	}
+
+	for _, o := range r.Old {
+		lines = append(lines, auditLine{
+			id:   o.ID,
+			text: fmt.Sprintf("old password: %s (not changed for %d days)", o.Name, o.AgeDays),
+		})
+	}
+
+	for _, c := range r.Cards {
+		text := fmt.Sprintf("card expires: %s (%s)", c.Name, c.Expiry)
+
+		switch {
+		case c.Invalid:
+			text = fmt.Sprintf("card expiry is invalid: %s (%s)", c.Name, c.Expiry)
+		case c.Expired:
+			text = fmt.Sprintf("card expired: %s (%s)", c.Name, c.Expiry)
+		}
+
+		lines = append(lines, auditLine{id: c.ID, text: text})
+	}
+
+	return lines
+}
+
+func (m AuditModel) View() string {
+	s := strings.Builder{}
+
+	if len(m.errMsg) > 0 {
+		s.WriteString(errorStyle.Render(m.errMsg) + "\n\n")
+	}
+
+	s.WriteString(actionsStyle.Render("Vault health"))
+	s.WriteString("\n\n")
+
+	if m.loading {
+		s.WriteString(blueStyle.Render("checking records...") + "\n")
+	} else {
+		s.WriteString(fmt.Sprintf("records: %d, with password: %d, with issues: %d\n",
+			m.report.Records, m.report.Passwords, m.report.Issues))
+		s.WriteString(fmt.Sprintf("weak: %d, reused groups: %d, old: %d, cards: %d\n",
+			len(m.report.Weak), len(m.report.Reused), len(m.report.Old), len(m.report.Cards)))
+
+		if m.report.BreachChecked {
+			s.WriteString(fmt.Sprintf("breached: %d\n\n", len(m.report.Breached)))
+		} else {
+			s.WriteString(blurredStyle.Render("breached passwords not checked, set -breach-source") + "\n\n")
+		}
+
+		if len(m.lines) == 0 && m.errMsg == "" {
+			s.WriteString(infoStyle.Render("no issues found") + "\n")
+		}
+	}
+
+	for i, l := range m.lines {
+		if m.cursor == i {
+			s.WriteString("(•) ")
+		} else {
+			s.WriteString("( ) ")
+		}
+
+		s.WriteString(l.text + "\n")
+	}
+
+	s.WriteString(helpStyle.Render("\n'up'/'down' choose record, 'enter' go to view/edit, 'r' check again"))
+	s.WriteString(helpStyle.Render("\n'esc' back to data list"))
+	s.WriteString("\n(press ctrl+c to quit)\n")
+
+	return s.String()
+}
@@ -133,6 +133,11 @@ func (m AuthModel) registrateUser(teaMsg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.updateInputs(teaMsg)
 	}
 
+	// сессия сохраняется, чтобы после перезапуска не вводить пароль до автоблокировки
+	if err = user.SaveSession(); err != nil {
+		userModel.msg = fmt.Sprintf("session not saved: %s", err)
+	}
+
 	var cmd tea.Cmd
 
 	return userModel, cmd
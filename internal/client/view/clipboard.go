@@ -0,0 +1,85 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"gophkeeper/client/otp"
+	"gophkeeper/internal/client"
+	"strings"
+	"time"
+)
+
+// copyKeys поля, которые копируются в буфер обмена по сочетаниям клавиш
+var copyKeys = map[string]string{
+	"alt+l": loginFieldKey,
+	"alt+p": passFieldKey,
+	"alt+c": cardNumFieldKey,
+	"alt+o": otpFieldKey,
+}
+
+// copyField копирование значения поля в буфер обмена, для ключа OTP копируется текущий одноразовый пароль
+func (m *DataFieldsModel) copyField(keys string) {
+	cb := client.AppInstance.Clipboard
+	if cb == nil {
+		m.errMsg = "clipboard is not available"
+		return
+	}
+
+	d := m.getData()
+	name, value := "", ""
+
+	switch copyKeys[keys] {
+	case loginFieldKey:
+		name, value = "login", d.Login
+	case passFieldKey:
+		name, value = "password", d.Pass
+	case cardNumFieldKey:
+		name, value = "card number", strings.ReplaceAll(d.CardNum, " ", "")
+	case otpFieldKey:
+		var err error
+		if value, err = m.otpCode(time.Now()); err != nil {
+			m.errMsg = err.Error()
+			return
+		}
+
+		name = "OTP code"
+	}
+
+	if value == "" {
+		m.errMsg = name + " is empty"
+		return
+	}
+
+	if err := cb.Copy(value); err != nil {
+		m.errMsg = err.Error()
+		return
+	}
+
+	m.msg = name + " copied"
+	if after := cb.ClearAfter(); after > 0 {
+		m.msg += fmt.Sprintf(", clipboard will be cleared in %s", after)
+	}
+}
+
+// otpCode пароль для копирования: текущий TOTP или последний выданный HOTP
+func (m DataFieldsModel) otpCode(now time.Time) (string, error) {
+	value := m.otpInput()
+	if value == "" {
+		return "", nil
+	}
+
+	key, err := otp.Parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	if key.Type == otp.TypeHOTP {
+		if m.hotpCode == "" {
+			return "", errors.New("press 'ctrl+n' to get the next hotp code first")
+		}
+
+		return m.hotpCode, nil
+	}
+
+	return key.Code(now)
+}
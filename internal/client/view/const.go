@@ -10,8 +10,12 @@ const (
 	loginFieldName    = "Login"
 	passFieldName     = "Password"
 	cardNumFieldName  = "Card Number"
+	cardExpFieldName  = "Card Expiry"
+	otpFieldName      = "OTP Key"
 	fileFieldName     = "File path"
 	fileNameFieldName = "File name"
 	textFieldName     = "Text"
 	metaFieldName     = "Meta"
+	folderFieldName   = "Folder path"
+	tagsFieldName     = "Tags (comma sep)"
 )
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"gophkeeper/client/data"
 	"gophkeeper/client/domain"
+	"gophkeeper/client/generator"
+	"gophkeeper/client/otp"
 	"gophkeeper/internal"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,7 +25,11 @@ const (
 	loginFieldKey   = "login"
 	passFieldKey    = "pass"
 	cardNumFieldKey = "card_num"
+	cardExpFieldKey = "card_exp"
+	otpFieldKey     = "otp"
 	fileFieldKey    = "file"
+	folderFieldKey  = "folder"
+	tagsFieldKey    = "tags"
 )
 
 var dataFields = []field{
@@ -42,10 +49,26 @@ var dataFields = []field{
 		key:  cardNumFieldKey,
 		name: cardNumFieldName,
 	},
+	{
+		key:  cardExpFieldKey,
+		name: cardExpFieldName,
+	},
+	{
+		key:  otpFieldKey,
+		name: otpFieldName,
+	},
 	{
 		key:  fileFieldKey,
 		name: fileFieldName,
 	},
+	{
+		key:  folderFieldKey,
+		name: folderFieldName,
+	},
+	{
+		key:  tagsFieldKey,
+		name: tagsFieldName,
+	},
 }
 
 // DataFieldsModel структура описывающая модель редактирование текстовых полей
@@ -56,6 +79,11 @@ type DataFieldsModel struct {
 	errMsg     string
 	msg        string
 	data       domain.Data
+	folders    []domain.Folder
+	// genMode режим генератора паролей, переключается по ctrl+o
+	genMode generator.Mode
+	// hotpCode последний выданный пароль HOTP, TOTP вычисляется при каждой отрисовке
+	hotpCode string
 }
 
 // InitDataFieldsModel инициализация модели
@@ -66,6 +94,13 @@ func InitDataFieldsModel(data domain.Data) DataFieldsModel {
 		data:   data,
 	}
 
+	folders, err := getFolders()
+	if err != nil {
+		m.errMsg = err.Error()
+	}
+
+	m.folders = folders
+
 	var t textinput.Model
 
 	for i, n := range dataFields {
@@ -82,6 +117,7 @@ func InitDataFieldsModel(data domain.Data) DataFieldsModel {
 			t.Placeholder = PasswordPlaceholder
 			t.EchoMode = textinput.EchoPassword
 			t.EchoCharacter = '•'
+			t.CharLimit = 128
 			t.SetValue(data.Pass)
 		case cardNumFieldKey:
 			t.CharLimit = 20
@@ -89,12 +125,30 @@ func InitDataFieldsModel(data domain.Data) DataFieldsModel {
 			t.Width = 30
 			t.Validate = ccnValidator
 			t.SetValue(data.CardNum)
+		case cardExpFieldKey:
+			t.CharLimit = 7
+			t.Placeholder = "MM/YY"
+			t.SetValue(data.CardExp)
+		case otpFieldKey:
+			t.CharLimit = 1024
+			t.Placeholder = "otpauth://totp/... or base32 secret"
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '•'
+			t.SetValue(data.Otp)
 		case fileFieldKey:
 			t.CharLimit = 200
 			t.Placeholder = data.FileName
 			t.SetValue(data.FilePath)
 		case loginFieldKey:
 			t.SetValue(data.Login)
+		case folderFieldKey:
+			t.CharLimit = 200
+			t.Placeholder = "work/bank"
+			t.SetValue(folderPath(folders, data.FolderID))
+		case tagsFieldKey:
+			t.CharLimit = 200
+			t.Placeholder = "personal, important"
+			t.SetValue(strings.Join(data.Tags, ", "))
 		}
 
 		m.inputs[i] = t
@@ -105,11 +159,14 @@ func InitDataFieldsModel(data domain.Data) DataFieldsModel {
 
 // Init инициализация
 func (m DataFieldsModel) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, otpTick())
 }
 
 func (m DataFieldsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msgType := msg.(type) {
+	// перерисовка пароля TOTP и обратного отсчета
+	case otpTickMsg:
+		return m, otpTick()
 	case tea.KeyMsg:
 		switch msgType.String() {
 		case "ctrl+c", "esc":
@@ -141,6 +198,18 @@ func (m DataFieldsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.dowloadFile()
 		case "ctrl+e":
 			return m.deleteData()
+		// generate password
+		case "ctrl+g":
+			m.generatePass()
+		case "ctrl+o":
+			m.genMode = (m.genMode + 1) % generator.Mode(len(generator.ModeNames))
+			m.msg = "generator mode: " + generator.ModeNames[m.genMode]
+		// next hotp code
+		case "ctrl+n":
+			m.nextHOTP()
+		// copy to clipboard
+		case "alt+l", "alt+p", "alt+c", "alt+o":
+			m.copyField(msgType.String())
 		// Set focus to next input
 		case "tab", "shift+tab", "enter", "up", "down":
 			s := msgType.String()
@@ -214,6 +283,10 @@ func (m DataFieldsModel) View() string {
 		b.WriteRune('\n')
 	}
 
+	if line := m.otpLine(time.Now()); line != "" {
+		b.WriteString("\n" + line + "\n")
+	}
+
 	b.WriteRune('\n')
 
 	button := &blurredButton
@@ -226,6 +299,12 @@ func (m DataFieldsModel) View() string {
 	b.WriteRune('\n')
 	b.WriteString(actionsStyle.Render("'ctrl+a' to edit meta window"))
 	b.WriteRune('\n')
+	b.WriteString(actionsStyle.Render(fmt.Sprintf("'ctrl+g' generate password, 'ctrl+o' change mode (%s)", generator.ModeNames[m.genMode])))
+	b.WriteRune('\n')
+	b.WriteString(actionsStyle.Render("'ctrl+n' next HOTP code (counter is saved)"))
+	b.WriteRune('\n')
+	b.WriteString(actionsStyle.Render("'alt+l' copy login, 'alt+p' password, 'alt+c' card number, 'alt+o' OTP code"))
+	b.WriteRune('\n')
 	b.WriteString(actionsStyle.Render("'ctrl+d' for download file"))
 	b.WriteRune('\n')
 	b.WriteString(actionsStyle.Render("'ctrl+s' save data"))
@@ -274,26 +353,101 @@ func (m DataFieldsModel) getData() domain.Data {
 			m.data.Pass = v.Value()
 		case cardNumFieldKey:
 			m.data.CardNum = v.Value()
+		case cardExpFieldKey:
+			m.data.CardExp = strings.TrimSpace(v.Value())
+		case otpFieldKey:
+			m.data.Otp = strings.TrimSpace(v.Value())
 		case fileFieldKey:
 			m.data.FilePath = strings.TrimSpace(v.Value())
+		case tagsFieldKey:
+			m.data.Tags = parseTags(v.Value())
 		}
 	}
 
 	return m.data
 }
 
+// folderPath введенный путь папки
+func (m DataFieldsModel) folderPath() string {
+	for i, v := range m.inputs {
+		if dataFields[i].key == folderFieldKey {
+			return v.Value()
+		}
+	}
+
+	return ""
+}
+
+func getFolders() ([]domain.Folder, error) {
+	return data.GetFolders()
+}
+
+func folderPath(folders []domain.Folder, id uint64) string {
+	return data.FolderPath(folders, id)
+}
+
+// parseTags разбор списка тегов, разделенных запятыми
+func parseTags(value string) []string {
+	var tags []string
+
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
 func (m *DataFieldsModel) saveData() {
-	gotData, err := data.SaveData(m.getData())
+	folderID, err := data.FindFolder(m.folders, m.folderPath())
+	if err != nil {
+		m.errMsg = err.Error()
+		return
+	}
+
+	m.data.FolderID = folderID
+
+	d := m.getData()
+	if d.Otp != "" {
+		if _, err = otp.Parse(d.Otp); err != nil {
+			m.errMsg = err.Error()
+			return
+		}
+	}
+
+	gotData, err := data.SaveData(d)
 	if err != nil {
 		m.errMsg = err.Error()
 	} else {
 		m.data.ID = gotData.ID
 		m.data.Version = gotData.Version
 		m.data.FileID = gotData.FileID
+		m.data.Otp = gotData.Otp
 		m.msg = "data saved"
 	}
 }
 
+// generatePass генерация пароля в поле пароля с оценкой стойкости
+func (m *DataFieldsModel) generatePass() {
+	o := generator.DefaultOptions()
+	o.Mode = m.genMode
+
+	res, err := generator.Generate(o)
+	if err != nil {
+		m.errMsg = err.Error()
+		return
+	}
+
+	for i := range m.inputs {
+		if dataFields[i].key == passFieldKey {
+			m.inputs[i].SetValue(res.Value)
+		}
+	}
+
+	m.msg = fmt.Sprintf("%s password generated, entropy %.1f bits (%s)", generator.ModeNames[m.genMode], res.Entropy, res.Strength())
+}
+
 func (m *DataFieldsModel) dowloadFile() {
 	if m.data.FileName == "" {
 		m.errMsg = "file is absent or data not saved"
@@ -8,57 +8,179 @@ import (
 	"gophkeeper/client/user"
 	"gophkeeper/internal/client"
 	domain2 "gophkeeper/server/domain"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dataListPageSize размер страницы списка данных
+const dataListPageSize = 20
+
+// названия типов данных и сортировок для отображения
+var (
+	dataTypeNames = []string{"all", "credentials", "card", "text", "file", "otp"}
+	dataSortNames = []string{"name ↑", "name ↓", "id ↑", "id ↓"}
 )
 
 // DataListModel модель для отображения списка данных пользователя
 // позволяет выбрать данные и перейти к редактированию
+// список запрашивается у сервера постранично, с фильтром по имени, тегам, папке и типу
 type DataListModel struct {
 	cursor   int
 	choice   string
 	msg      string
 	dataList []domain2.DataName
 	errMsg   string
+	filter   domain.DataListFilter
+	// tokens токены уже пройденных страниц, для возврата назад
+	tokens    []string
+	nextToken string
+	search    textinput.Model
+	searching bool
+	folders   folderTree
+	// sidebarFocused клавиши управляют деревом папок, а не списком
+	sidebarFocused bool
 }
 
 func InitDataListModel() DataListModel {
-	var errmsg string
+	search := textinput.New()
+	search.Placeholder = "name contains, #tag"
+	search.Cursor.Style = cursorStyle
+	search.CharLimit = 256
 
-	dataList, err := data.GetDataList()
-	if err != nil {
-		errmsg = err.Error()
+	m := DataListModel{
+		search:  search,
+		filter:  domain.DataListFilter{PageSize: dataListPageSize},
+		folders: newFolderTree(),
 	}
+	m.load()
 
-	m := DataListModel{
-		dataList: dataList,
-		errMsg:   errmsg,
+	if err := m.folders.load(); err != nil && m.errMsg == "" {
+		m.errMsg = err.Error()
 	}
 
 	return m
 }
 
+// load загрузка текущей страницы списка
+func (m *DataListModel) load() {
+	m.errMsg = ""
+	m.cursor = 0
+
+	dataList, next, err := data.GetDataList(m.filter)
+	if err != nil {
+		m.errMsg = err.Error()
+	}
+
+	m.dataList = dataList
+	m.nextToken = next
+}
+
+// reload загрузка первой страницы после изменения фильтра
+func (m *DataListModel) reload() {
+	m.tokens = nil
+	m.filter.PageToken = ""
+	m.load()
+}
+
 func (m DataListModel) Init() tea.Cmd {
 	return nil
 }
 
 func (m DataListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.searching {
+		return m.updateSearch(msg)
+	}
+
+	if m.folders.adding {
+		return m.updateFolderInput(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && m.sidebarFocused {
+		switch msg.String() {
+		case "tab", "esc":
+			m.sidebarFocused = false
+		case "down", "j":
+			m.folders.move(1)
+		case "up", "k":
+			m.folders.move(-1)
+		case "enter":
+			m.folders.selected = m.folders.current()
+			m.filter.FolderID = m.folders.selected
+			m.reload()
+		case "a":
+			return m, m.folders.startAdding()
+		case "x":
+			if err := m.folders.remove(); err != nil {
+				m.errMsg = err.Error()
+				return m, nil
+			}
+
+			m.filter.FolderID = m.folders.selected
+			m.reload()
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		}
+
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "tab":
+			m.sidebarFocused = true
+		case "/":
+			m.searching = true
+			m.search.PromptStyle = focusedStyle
+			m.search.TextStyle = focusedStyle
+
+			return m, m.search.Focus()
+		case "ctrl+f":
+			sm := InitSearchModel()
+
+			return sm, sm.Init()
+		case "h":
+			am := InitAuditModel()
+
+			return am, am.Init()
+		case "n":
+			if m.nextToken != "" {
+				m.tokens = append(m.tokens, m.filter.PageToken)
+				m.filter.PageToken = m.nextToken
+				m.load()
+			}
+		case "p":
+			if len(m.tokens) > 0 {
+				m.filter.PageToken = m.tokens[len(m.tokens)-1]
+				m.tokens = m.tokens[:len(m.tokens)-1]
+				m.load()
+			}
+		case "t":
+			m.filter.Type = (m.filter.Type + 1) % domain2.DataType(len(dataTypeNames))
+			m.reload()
+		case "s":
+			m.filter.Sort = (m.filter.Sort + 1) % domain2.DataSort(len(dataSortNames))
+			m.reload()
 		case "ctrl+c", "q", "esc":
 			return m, tea.Quit
 		case "ctrl+w":
 			var cmd tea.Cmd
 			rm := RootModel{}
 
-			user.ResetUser()
+			if err := user.Logout(); err != nil {
+				rm.msg = err.Error()
+			}
 
 			return rm, tea.Batch(cmd, rm.Init())
 		case "enter":
-			//var cmd tea.Cmd
-			// Send the choice on the channel and exit.
+			if len(m.dataList) == 0 {
+				return m, nil
+			}
+
 			return m.Do()
 		case "down", "j":
 			m.cursor++
@@ -76,6 +198,98 @@ func (m DataListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateSearch ввод строки поиска, 'enter' применяет фильтр, 'esc' отменяет ввод
+func (m DataListModel) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "enter", "esc":
+			if msg.String() == "esc" {
+				m.search.SetValue(searchValue(m.filter.NameContains, m.filter.Tags))
+			}
+
+			m.searching = false
+			m.search.Blur()
+			m.search.PromptStyle = noStyle
+			m.search.TextStyle = noStyle
+
+			contains, tags := parseSearch(m.search.Value())
+			if contains != m.filter.NameContains || !slices.Equal(tags, m.filter.Tags) {
+				m.filter.NameContains = contains
+				m.filter.Tags = tags
+				m.reload()
+			}
+
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+
+	return m, cmd
+}
+
+// updateFolderInput ввод названия новой папки, 'enter' сохраняет папку, 'esc' отменяет ввод
+func (m DataListModel) updateFolderInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.folders.stopAdding()
+			return m, nil
+		case "enter":
+			if err := m.folders.add(); err != nil {
+				m.errMsg = err.Error()
+			}
+
+			m.folders.stopAdding()
+
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.folders.input, cmd = m.folders.input.Update(msg)
+
+	return m, cmd
+}
+
+// parseSearch разбор строки поиска: слова, начинающиеся с #, считаются тегами, остальное - частью названия
+func parseSearch(value string) (string, []string) {
+	var words, tags []string
+
+	for _, word := range strings.Fields(value) {
+		if tag, ok := strings.CutPrefix(word, "#"); ok {
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+
+			continue
+		}
+
+		words = append(words, word)
+	}
+
+	return strings.Join(words, " "), tags
+}
+
+// searchValue обратное к parseSearch преобразование для отображения текущего фильтра
+func searchValue(contains string, tags []string) string {
+	parts := []string{}
+	if contains != "" {
+		parts = append(parts, contains)
+	}
+
+	for _, tag := range tags {
+		parts = append(parts, "#"+tag)
+	}
+
+	return strings.Join(parts, " ")
+}
+
 // Do переход к редактирование данных
 func (m DataListModel) Do() (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -108,18 +322,39 @@ func (m DataListModel) View() string {
 		s.WriteString(infoStyle.Render(m.msg) + "\n\n")
 	}
 
+	if len(m.errMsg) > 0 {
+		s.WriteString(errorStyle.Render(m.errMsg) + "\n\n")
+	}
+
 	s.WriteString(strings.TrimSpace(actionsStyle.Render("Choose data and press 'enter' for go to view/edit\n\n")))
+	s.WriteString("\n")
+	s.WriteString(m.search.View() + "\n")
+	s.WriteString(blueStyle.Render(fmt.Sprintf("type: %s, sort: %s, page: %d",
+		dataTypeNames[m.filter.Type], dataSortNames[m.filter.Sort], len(m.tokens)+1)) + "\n\n")
 
+	list := strings.Builder{}
 	for i := 0; i < len(m.dataList); i++ {
-		if m.cursor == i {
-			s.WriteString("(•) ")
+		if m.cursor == i && !m.sidebarFocused {
+			list.WriteString("(•) ")
 		} else {
-			s.WriteString("( ) ")
+			list.WriteString("( ) ")
+		}
+		list.WriteString(fmt.Sprintf("dataID: %d, dataName: %s", m.dataList[i].ID, m.dataList[i].Name))
+
+		for _, tag := range m.dataList[i].Tags {
+			list.WriteString(" " + infoStyle.Render("#"+tag))
 		}
-		s.WriteString(fmt.Sprintf("dataID: %d, dataName: %s\n", m.dataList[i].ID, m.dataList[i].Name))
+
+		list.WriteString("\n")
 	}
 
-	s.WriteString(helpStyle.Render("\n\n'ctrl+w' to main window"))
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, m.folders.view(m.sidebarFocused), list.String()))
+
+	s.WriteString(helpStyle.Render("\n\n'/' search by name and #tags, 't' data type, 's' sort, 'n'/'p' next/previous page"))
+	s.WriteString(helpStyle.Render("\n'ctrl+f' full-text search in names, logins, urls and notes"))
+	s.WriteString(helpStyle.Render("\n'h' vault health: weak, reused and old passwords, expiring cards"))
+	s.WriteString(helpStyle.Render("\n'tab' folders: 'enter' show folder, 'a' add subfolder, 'x' delete folder"))
+	s.WriteString(helpStyle.Render("\n'ctrl+w' to main window"))
 	s.WriteString("\n(press q to quit)\n")
 
 	return s.String()
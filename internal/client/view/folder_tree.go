@@ -0,0 +1,189 @@
+package view
+
+import (
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// folderItem строка дерева папок, id = 0 - все данные
+type folderItem struct {
+	id    uint64
+	depth int
+	name  string
+}
+
+// folderTree боковая панель с деревом папок в списке данных
+type folderTree struct {
+	folders []domain.Folder
+	items   []folderItem
+	cursor  int
+	// selected ИД папки, по которой отфильтрован список
+	selected uint64
+	input    textinput.Model
+	adding   bool
+}
+
+func newFolderTree() folderTree {
+	input := textinput.New()
+	input.Placeholder = "new folder"
+	input.Cursor.Style = cursorStyle
+	input.CharLimit = 64
+	input.Width = 20
+
+	return folderTree{input: input}
+}
+
+// load загрузка папок с сервера и построение дерева
+func (t *folderTree) load() error {
+	folders, err := data.GetFolders()
+	if err != nil {
+		return err
+	}
+
+	t.folders = folders
+	t.items = buildFolderItems(folders)
+
+	if t.cursor >= len(t.items) {
+		t.cursor = len(t.items) - 1
+	}
+
+	return nil
+}
+
+// current ИД папки под курсором
+func (t *folderTree) current() uint64 {
+	if t.cursor < 0 || t.cursor >= len(t.items) {
+		return 0
+	}
+
+	return t.items[t.cursor].id
+}
+
+func (t *folderTree) move(delta int) {
+	t.cursor += delta
+	if t.cursor >= len(t.items) {
+		t.cursor = 0
+	}
+
+	if t.cursor < 0 {
+		t.cursor = len(t.items) - 1
+	}
+}
+
+// startAdding начать ввод названия новой папки внутри папки под курсором
+func (t *folderTree) startAdding() tea.Cmd {
+	t.adding = true
+	t.input.SetValue("")
+	t.input.PromptStyle = focusedStyle
+	t.input.TextStyle = focusedStyle
+
+	return t.input.Focus()
+}
+
+func (t *folderTree) stopAdding() {
+	t.adding = false
+	t.input.Blur()
+	t.input.PromptStyle = noStyle
+	t.input.TextStyle = noStyle
+}
+
+// add сохранение новой папки
+func (t *folderTree) add() error {
+	name := strings.TrimSpace(t.input.Value())
+	if name == "" {
+		return nil
+	}
+
+	_, err := data.SaveFolder(domain.Folder{Name: name, ParentID: t.current()})
+	if err != nil {
+		return err
+	}
+
+	return t.load()
+}
+
+// remove удаление папки под курсором
+func (t *folderTree) remove() error {
+	id := t.current()
+	if id == 0 {
+		return nil
+	}
+
+	if err := data.DeleteFolder(id); err != nil {
+		return err
+	}
+
+	if t.selected == id {
+		t.selected = 0
+	}
+
+	return t.load()
+}
+
+func (t *folderTree) view(focused bool) string {
+	s := strings.Builder{}
+
+	title := "Folders"
+	if focused {
+		title = focusedStyle.Render(title)
+	}
+
+	s.WriteString(title + "\n\n")
+
+	for i, item := range t.items {
+		line := strings.Repeat("  ", item.depth) + item.name
+		if item.id == t.selected {
+			line = blueStyle.Render(line)
+		}
+
+		if focused && i == t.cursor {
+			s.WriteString("> ")
+		} else {
+			s.WriteString("  ")
+		}
+
+		s.WriteString(line + "\n")
+	}
+
+	if t.adding {
+		s.WriteString("\n" + t.input.View() + "\n")
+	}
+
+	return sidebarStyle.Render(s.String())
+}
+
+// buildFolderItems обход дерева папок в глубину, первым идет пункт со всеми данными
+func buildFolderItems(folders []domain.Folder) []folderItem {
+	children := make(map[uint64][]domain.Folder)
+	known := make(map[uint64]bool, len(folders))
+
+	for _, f := range folders {
+		known[f.ID] = true
+	}
+
+	for _, f := range folders {
+		parentID := f.ParentID
+		if !known[parentID] {
+			parentID = 0
+		}
+
+		children[parentID] = append(children[parentID], f)
+	}
+
+	items := []folderItem{{name: "All"}}
+
+	var walk func(parentID uint64, depth int)
+	walk = func(parentID uint64, depth int) {
+		for _, f := range children[parentID] {
+			items = append(items, folderItem{id: f.ID, depth: depth, name: f.Name})
+			walk(f.ID, depth+1)
+		}
+	}
+	walk(0, 1)
+
+	return items
+}
@@ -17,6 +17,8 @@ func showData(d domain.Data) string {
 	res += fmt.Sprintf("%-17s:  %s\n", loginFieldName, d.Login)
 	res += fmt.Sprintf("%-17s:  %s\n", passFieldName, strings.Repeat("*", utf8.RuneCountInString(d.Pass)))
 	res += fmt.Sprintf("%-17s:  %s\n", cardNumFieldName, d.CardNum)
+	res += fmt.Sprintf("%-17s:  %s\n", cardExpFieldName, d.CardExp)
+	res += fmt.Sprintf("%-17s:  %s\n", otpFieldName, strings.Repeat("*", min(len(d.Otp), 8)))
 	res += fmt.Sprintf("%-17s:  %s\n", fileNameFieldName, d.FileName)
 
 	return res
@@ -0,0 +1,127 @@
+package view
+
+import (
+	"errors"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/user"
+	"gophkeeper/internal"
+	"gophkeeper/internal/client"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lockCheckInterval период проверки бездействия пользователя
+const lockCheckInterval = 15 * time.Second
+
+// lockTickMsg сообщение для периодической проверки бездействия
+type lockTickMsg time.Time
+
+// AutoLockModel обертка над текущей моделью, которая блокирует сессию после бездействия пользователя
+// пока пользователь активен, сохраненная сессия продлевается, чтобы ее могли использовать консольные команды
+type AutoLockModel struct {
+	inner        tea.Model
+	lastActivity time.Time
+	lastKeep     time.Time
+}
+
+// InitStartModel стартовая модель программы
+// если сохраненная сессия не заблокирована, пользователь сразу попадает в свое меню
+func InitStartModel(buildVersion, buildDate string) AutoLockModel {
+	var start tea.Model
+
+	rm := RootModel{BuildVersion: buildVersion, BuildDate: buildDate}
+	start = rm
+
+	switch err := user.LoadSession(); {
+	case err == nil:
+		um := UserModel{}
+		if err = data.UseVault(client.AppInstance.VaultName); err != nil {
+			um.msg = err.Error()
+		}
+
+		start = um
+	case !errors.Is(err, domain.ErrNotLoggedIn):
+		rm.msg = err.Error()
+		start = rm
+	}
+
+	return NewAutoLockModel(start)
+}
+
+func NewAutoLockModel(inner tea.Model) AutoLockModel {
+	now := time.Now()
+
+	return AutoLockModel{
+		inner:        inner,
+		lastActivity: now,
+		lastKeep:     now,
+	}
+}
+
+func lockTick() tea.Cmd {
+	return tea.Tick(lockCheckInterval, func(t time.Time) tea.Msg {
+		return lockTickMsg(t)
+	})
+}
+
+func (m AutoLockModel) Init() tea.Cmd {
+	return tea.Batch(m.inner.Init(), lockTick())
+}
+
+func (m AutoLockModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		m.lastActivity = time.Now()
+	case lockTickMsg:
+		return m.checkLock(time.Time(msg))
+	}
+
+	var cmd tea.Cmd
+	m.inner, cmd = m.inner.Update(msg)
+
+	return m, cmd
+}
+
+// checkLock блокировка после бездействия, либо продление сессии, если пользователь был активен
+func (m AutoLockModel) checkLock(now time.Time) (tea.Model, tea.Cmd) {
+	if client.AppInstance.User.Token == "" || client.AppInstance.LockAfter <= 0 {
+		return m, lockTick()
+	}
+
+	if now.Sub(m.lastActivity) >= client.AppInstance.LockAfter {
+		if err := user.Logout(); err != nil {
+			internal.Logger.Errorw("error locking session", "error", err)
+		}
+
+		return m.lock()
+	}
+
+	if m.lastActivity.After(m.lastKeep) {
+		m.lastKeep = now
+
+		err := user.KeepSession()
+		if errors.Is(err, domain.ErrSessionLocked) {
+			return m.lock()
+		}
+
+		if err != nil {
+			internal.Logger.Errorw("error keeping session", "error", err)
+		}
+	}
+
+	return m, lockTick()
+}
+
+// lock переход на стартовый экран после блокировки сессии
+func (m AutoLockModel) lock() (tea.Model, tea.Cmd) {
+	rm := RootModel{msg: domain.ErrSessionLocked.Error()}
+	m.inner = rm
+
+	return m, tea.Batch(rm.Init(), lockTick())
+}
+
+func (m AutoLockModel) View() string {
+	return m.inner.View()
+}
@@ -0,0 +1,86 @@
+package view
+
+import (
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/otp"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// otpTickInterval период перерисовки пароля TOTP
+const otpTickInterval = time.Second
+
+// otpTickMsg сообщение для перерисовки пароля TOTP и обратного отсчета
+type otpTickMsg time.Time
+
+func otpTick() tea.Cmd {
+	return tea.Tick(otpTickInterval, func(t time.Time) tea.Msg {
+		return otpTickMsg(t)
+	})
+}
+
+// otpInput значение поля ключа одноразовых паролей
+func (m DataFieldsModel) otpInput() string {
+	return m.getData().Otp
+}
+
+// otpLine строка с текущим паролем TOTP и временем до его смены, для HOTP - последний выданный пароль и счетчик
+// пустая строка - ключ не указан
+func (m DataFieldsModel) otpLine(now time.Time) string {
+	value := m.otpInput()
+	if value == "" {
+		return ""
+	}
+
+	key, err := otp.Parse(value)
+	if err != nil {
+		return errorStyle.Render("OTP: " + err.Error())
+	}
+
+	if key.Type == otp.TypeHOTP {
+		if m.hotpCode == "" {
+			return fmt.Sprintf("%-17s:  %s", "OTP code", blurredStyle.Render(fmt.Sprintf("counter %d, press 'ctrl+n' for next code", key.Counter)))
+		}
+
+		return fmt.Sprintf("%-17s:  %s (counter %d)", "OTP code", blueStyle.Render(m.hotpCode), key.Counter)
+	}
+
+	code, err := key.Code(now)
+	if err != nil {
+		return errorStyle.Render("OTP: " + err.Error())
+	}
+
+	return fmt.Sprintf("%-17s:  %s (%ds)", "OTP code", blueStyle.Render(code), int(key.Remaining(now).Seconds()))
+}
+
+// nextHOTP следующий пароль HOTP, увеличенный счетчик сохраняется на сервере до показа пароля
+// используется сохраненный ключ, поэтому измененный в поле ключ нужно сначала сохранить
+func (m *DataFieldsModel) nextHOTP() {
+	if m.data.ID == 0 || m.otpInput() != m.data.Otp {
+		m.errMsg = "save the otp key first"
+		return
+	}
+
+	res, err := data.GenerateOTP(m.data.ID)
+	if err != nil {
+		m.errMsg = err.Error()
+		return
+	}
+
+	if res.Key.Type != otp.TypeHOTP {
+		m.msg = "totp code changes every period, ctrl+n is for hotp keys"
+		return
+	}
+
+	m.data.Otp = res.Key.URI()
+	m.data.Version = res.Version
+	m.hotpCode = res.Code
+
+	for i := range m.inputs {
+		if dataFields[i].key == otpFieldKey {
+			m.inputs[i].SetValue(m.data.Otp)
+		}
+	}
+}
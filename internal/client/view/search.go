@@ -0,0 +1,175 @@
+package view
+
+import (
+	"fmt"
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/client/search"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchResultsLimit количество отображаемых результатов поиска
+const searchResultsLimit = 20
+
+// searchSyncedMsg завершение синхронизации локального индекса
+type searchSyncedMsg struct {
+	err error
+}
+
+// SearchModel модель нечеткого поиска по локальному индексу расшифрованных данных
+// результаты обновляются при вводе, индекс синхронизируется с сервером в фоне
+type SearchModel struct {
+	input   textinput.Model
+	results []search.Result
+	cursor  int
+	syncing bool
+	errMsg  string
+}
+
+func InitSearchModel() SearchModel {
+	input := textinput.New()
+	input.Placeholder = "fuzzy search, login:value for field"
+	input.Cursor.Style = cursorStyle
+	input.PromptStyle = focusedStyle
+	input.TextStyle = focusedStyle
+	input.CharLimit = 256
+	input.Focus()
+
+	return SearchModel{
+		input:   input,
+		syncing: true,
+	}
+}
+
+func (m SearchModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, syncSearchIndex)
+}
+
+func syncSearchIndex() tea.Msg {
+	return searchSyncedMsg{err: data.SyncSearchIndex()}
+}
+
+func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case searchSyncedMsg:
+		m.syncing = false
+		if msg.err != nil {
+			m.errMsg = msg.err.Error()
+		}
+
+		m.find()
+
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			dl := InitDataListModel()
+			return dl, dl.Init()
+		case "enter":
+			if len(m.results) == 0 {
+				return m, nil
+			}
+
+			return m.Do()
+		case "down", "ctrl+n":
+			m.cursor++
+			if m.cursor >= len(m.results) {
+				m.cursor = 0
+			}
+
+			return m, nil
+		case "up", "ctrl+p":
+			m.cursor--
+			if m.cursor < 0 {
+				m.cursor = max(len(m.results)-1, 0)
+			}
+
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	prev := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+
+	if m.input.Value() != prev {
+		m.find()
+	}
+
+	return m, cmd
+}
+
+// find поиск по текущему значению строки ввода
+func (m *SearchModel) find() {
+	m.cursor = 0
+
+	results, err := data.SearchData(m.input.Value(), searchResultsLimit)
+	if err != nil {
+		m.errMsg = err.Error()
+	}
+
+	m.results = results
+}
+
+// Do переход к редактированию найденных данных
+func (m SearchModel) Do() (tea.Model, tea.Cmd) {
+	d, err := data.GetData(m.results[m.cursor].ID)
+	if err != nil {
+		m.errMsg = err.Error()
+		return m, nil
+	}
+
+	if d == nil {
+		m.errMsg = domain.ErrDataNotFound.Error()
+		return m, nil
+	}
+
+	dt := InitDataFieldsModel(*d)
+
+	return dt, dt.Init()
+}
+
+func (m SearchModel) View() string {
+	s := strings.Builder{}
+
+	if len(m.errMsg) > 0 {
+		s.WriteString(errorStyle.Render(m.errMsg) + "\n\n")
+	}
+
+	s.WriteString(actionsStyle.Render("Type to search and press 'enter' for go to view/edit"))
+	s.WriteString("\n")
+	s.WriteString(m.input.View() + "\n")
+
+	if m.syncing {
+		s.WriteString(blueStyle.Render("syncing search index...") + "\n")
+	}
+
+	s.WriteString("\n")
+
+	for i, r := range m.results {
+		if m.cursor == i {
+			s.WriteString("(•) ")
+		} else {
+			s.WriteString("( ) ")
+		}
+
+		s.WriteString(fmt.Sprintf("dataID: %d, dataName: %s", r.ID, r.Name))
+
+		if r.Field != search.FieldName {
+			s.WriteString(" " + infoStyle.Render("in "+r.Field))
+		}
+
+		s.WriteString("\n")
+	}
+
+	s.WriteString(helpStyle.Render("\n'up'/'down' choose result, fields: name:, login:, text:, meta:"))
+	s.WriteString(helpStyle.Render("\n'esc' back to data list"))
+	s.WriteString("\n(press ctrl+c to quit)\n")
+
+	return s.String()
+}
@@ -16,6 +16,7 @@ var (
 	cursorStyle  = focusedStyle
 	noStyle      = lipgloss.NewStyle()
 	helpStyle    = blurredStyle
+	sidebarStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, true, false, false).PaddingRight(2).MarginRight(1)
 
 	focusedButton = focusedStyle.Render("[ Submit ]")
 	blurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Submit"))
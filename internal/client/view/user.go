@@ -14,15 +14,19 @@ import (
 const (
 	DataListChoice = 0
 	AddDataChoice  = 1
+	VaultChoice    = 2
+	ActivityChoice = 3
 )
 
 var userModelChoices = map[int]string{
 	DataListChoice: "Get data list",
 	AddDataChoice:  "Add data",
+	VaultChoice:    "Switch vault",
+	ActivityChoice: "Account activity",
 }
 
 // UserModel модель для авторизованного пользователя
-// позволяет перейти к списку данных, либо в форму добавления
+// позволяет перейти к списку данных, в форму добавления, к выбору хранилища или журналу активности
 type UserModel struct {
 	cursor int
 	choice string
@@ -43,7 +47,9 @@ func (m UserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var cmd tea.Cmd
 			rm := RootModel{}
 
-			user.ResetUser()
+			if err := user.Logout(); err != nil {
+				rm.msg = err.Error()
+			}
 
 			return rm, tea.Batch(cmd, rm.Init())
 		case "enter":
@@ -75,6 +81,11 @@ func (m UserModel) Do() (tea.Model, tea.Cmd) {
 	case AddDataChoice:
 		var data domain.Data
 		return InitDataFieldsModel(data), cmd
+	case VaultChoice:
+		return InitVaultModel(), cmd
+	case ActivityChoice:
+		am := InitActivityModel()
+		return am, am.Init()
 	}
 
 	return m, tea.Batch(cmd, m.Init())
@@ -86,7 +97,14 @@ func (m UserModel) View() string {
 	if len(client.AppInstance.User.Token) == 0 {
 		getError(errors.New("sorry( auth data is empty"))
 	} else {
-		s.WriteString(infoStyle.Render(fmt.Sprintf("Hi!, %s", client.AppInstance.User.Login)) + "\n\n")
+		s.WriteString(infoStyle.Render(fmt.Sprintf("Hi!, %s", client.AppInstance.User.Login)) + "\n")
+
+		vault := "personal"
+		if client.AppInstance.Vault != nil {
+			vault = client.AppInstance.Vault.Name
+		}
+
+		s.WriteString(helpStyle.Render("vault: "+vault) + "\n\n")
 	}
 
 	if len(m.msg) > 0 {
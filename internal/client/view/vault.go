@@ -0,0 +1,184 @@
+package view
+
+import (
+	"gophkeeper/client/data"
+	"gophkeeper/client/domain"
+	"gophkeeper/internal/client"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// VaultModel выбор хранилища: личное, хранилище организации или открытое по экстренному доступу
+// на непринятом приглашении enter принимает приглашение
+type VaultModel struct {
+	orgs []domain.Organization
+	// grants экстренные доступы, по которым пользователю предоставлен доступ к чужому хранилищу
+	grants []domain.EmergencyGrant
+	cursor int
+	msg    string
+}
+
+func InitVaultModel() VaultModel {
+	m := VaultModel{}
+	m.load()
+
+	return m
+}
+
+func (m *VaultModel) load() {
+	orgs, err := data.GetOrganizations()
+	if err != nil {
+		m.msg = getError(err)
+		return
+	}
+
+	m.orgs = orgs
+
+	grants, err := data.GetGrants()
+	if err != nil {
+		m.msg = getError(err)
+		return
+	}
+
+	m.grants = m.grants[:0]
+	for _, g := range grants {
+		if g.VaultKey != "" {
+			m.grants = append(m.grants, g)
+		}
+	}
+}
+
+func (m VaultModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m VaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			return UserModel{}, nil
+		case "enter":
+			return m.Do()
+		case "down", "j":
+			m.cursor++
+			if m.cursor > len(m.orgs)+len(m.grants) {
+				m.cursor = 0
+			}
+		case "up", "k":
+			m.cursor--
+			if m.cursor < 0 {
+				m.cursor = len(m.orgs) + len(m.grants)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Do переключение на хранилище под курсором, первая строка - личное хранилище, после организаций - экстренные доступы
+func (m VaultModel) Do() (tea.Model, tea.Cmd) {
+	if m.cursor == 0 {
+		if err := data.UseVault(""); err != nil {
+			m.msg = getError(err)
+			return m, nil
+		}
+
+		return UserModel{msg: "Personal vault"}, nil
+	}
+
+	if m.cursor > len(m.orgs) {
+		ref := data.EmergencyVaultPrefix + m.grants[m.cursor-len(m.orgs)-1].Grantor
+
+		if err := data.UseVault(ref); err != nil {
+			m.msg = getError(err)
+			return m, nil
+		}
+
+		return UserModel{msg: "Vault " + ref + " (read-only)"}, nil
+	}
+
+	org := m.orgs[m.cursor-1]
+	ref := strconv.FormatUint(org.ID, 10)
+
+	if !org.Accepted {
+		if err := data.AcceptInvite(ref); err != nil {
+			m.msg = getError(err)
+			return m, nil
+		}
+
+		m.load()
+		m.msg = infoStyle.Render("Invite to " + org.Name + " accepted")
+
+		return m, nil
+	}
+
+	if err := data.UseVault(ref); err != nil {
+		m.msg = getError(err)
+		return m, nil
+	}
+
+	return UserModel{msg: "Vault " + org.Name}, nil
+}
+
+func (m VaultModel) View() string {
+	s := strings.Builder{}
+
+	s.WriteString("Choose vault\n\n")
+
+	items := []string{"Personal"}
+	for _, org := range m.orgs {
+		item := org.Name + " (" + domain.OrgRoleName(org.Role) + ")"
+		if !org.Accepted {
+			item += " - invite, enter to accept"
+		}
+
+		items = append(items, item)
+	}
+
+	for _, g := range m.grants {
+		items = append(items, g.Grantor+" (emergency, read-only)")
+	}
+
+	current := 0
+	if vault := client.AppInstance.Vault; vault != nil {
+		for i, org := range m.orgs {
+			if org.ID == vault.OrgID {
+				current = i + 1
+			}
+		}
+
+		for i, g := range m.grants {
+			if g.ID == vault.GrantID {
+				current = len(m.orgs) + i + 1
+			}
+		}
+	}
+
+	for i, item := range items {
+		if m.cursor == i {
+			s.WriteString("(•) ")
+		} else {
+			s.WriteString("( ) ")
+		}
+
+		if i == current {
+			item = blueStyle.Render(item)
+		}
+
+		s.WriteString(item + "\n")
+	}
+
+	if len(m.msg) > 0 {
+		s.WriteString("\n" + m.msg + "\n")
+	}
+
+	s.WriteString(helpStyle.Render("\n\n'esc' back"))
+	s.WriteString("\n(press q to quit)\n")
+
+	return s.String()
+}
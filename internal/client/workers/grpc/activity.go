@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	pb "gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+	"time"
+)
+
+// ActivityClient клиент журнала аудита сервера: входы, чтение и изменение записей
+type ActivityClient struct {
+	client pb.AuditServiceClient
+}
+
+func NewActivityClient(client pb.AuditServiceClient) *ActivityClient {
+	return &ActivityClient{
+		client: client,
+	}
+}
+
+// List страница журнала пользователя по фильтру, от новых событий к старым
+func (c *ActivityClient) List(ctx context.Context, filter domain2.AuditFilter) ([]domain2.AuditEvent, error) {
+	req := &pb.ListAuditEventsRequest{
+		DataId:   filter.DataID,
+		Outcome:  pb.AuditOutcome(filter.Outcome),
+		Limit:    uint32(filter.Limit),
+		BeforeId: filter.BeforeID,
+	}
+
+	for _, a := range filter.Actions {
+		req.Actions = append(req.Actions, pb.AuditAction(a))
+	}
+
+	if !filter.Since.IsZero() {
+		req.Since = filter.Since.Unix()
+	}
+
+	if !filter.Until.IsZero() {
+		req.Until = filter.Until.Unix()
+	}
+
+	resp, err := c.client.ListAuditEvents(ctx, req)
+	if err != nil {
+		return nil, orgError("get activity", err)
+	}
+
+	res := make([]domain2.AuditEvent, 0, len(resp.GetEvents()))
+	for _, e := range resp.GetEvents() {
+		res = append(res, domain2.AuditEvent{
+			ID:        e.GetId(),
+			UID:       e.GetUid(),
+			ActorUID:  e.GetActorUid(),
+			Actor:     e.GetActor(),
+			IP:        e.GetIp(),
+			Action:    domain2.AuditAction(e.GetAction()),
+			DataID:    e.GetDataId(),
+			Outcome:   domain2.AuditOutcome(e.GetOutcome()),
+			CreatedAt: time.Unix(e.GetCreatedAt(), 0),
+		})
+	}
+
+	return res, nil
+}
@@ -14,7 +14,6 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type DataClient struct {
@@ -49,54 +48,99 @@ func (c *DataClient) Get(ctx context.Context, id uint64) (*clientDomain.Data, er
 		Name:     respData.GetName(),
 		Pass:     respData.GetPass(),
 		CardNum:  respData.GetCardNum(),
+		CardExp:  respData.GetCardExp(),
+		Otp:      respData.GetOtp(),
 		Text:     respData.GetText(),
 		FileName: respData.GetFileName(),
 		Login:    respData.GetLogin(),
 		Meta:     respData.GetMeta(),
 		FileID:   respData.GetFileID(),
+		FolderID: respData.GetFolderId(),
+		Tags:     respData.GetTags(),
+
+		RecordKey:  respData.GetRecordKey(),
+		SharedBy:   respData.GetSharedBy(),
+		Permission: domain2.SharePermission(respData.GetPermission()),
 	}
 
 	return data, nil
 }
 
-// GetList получения списка данных пользователя
-func (c *DataClient) GetList(ctx context.Context) ([]domain2.DataName, error) {
-	resp, err := c.client.GetDataList(ctx, &emptypb.Empty{})
+// GetList получения страницы списка данных пользователя
+// вторым значением возвращается токен следующей страницы, пустой - если страница последняя
+func (c *DataClient) GetList(ctx context.Context, filter clientDomain.DataListFilter) ([]domain2.DataName, string, error) {
+	resp, err := c.client.GetDataList(ctx, &pb.GetDataListRequest{
+		NamePrefix:   filter.NamePrefix,
+		NameContains: filter.NameContains,
+		Type:         pb.DataType(filter.Type),
+		Sort:         pb.SortOrder(filter.Sort),
+		PageSize:     filter.PageSize,
+		PageToken:    filter.PageToken,
+		FolderId:     filter.FolderID,
+		Tags:         filter.Tags,
+		Shared:       filter.Shared,
+	})
 
 	if err != nil {
 		if status.Code(err) == codes.Internal {
 			internal.Logger.Errorw("error while get data list", "error", err)
-			return nil, clientDomain.ErrGetDataList
+			return nil, "", clientDomain.ErrGetDataList
 		}
 
-		return nil, err
+		return nil, "", err
 	}
 
 	var dataList []domain2.DataName
 
 	for _, data := range resp.GetDataList() {
 		dd := domain2.DataName{
-			Name: data.GetName(),
-			ID:   data.GetId(),
+			Name:    data.GetName(),
+			ID:      data.GetId(),
+			Version: data.GetVersion(),
+			Tags:    data.GetTags(),
+		}
+
+		if folderID := data.GetFolderId(); folderID != 0 {
+			dd.FolderID = &folderID
+		}
+
+		if recordKey := data.GetRecordKey(); recordKey != "" {
+			dd.RecordKey = &recordKey
+		}
+
+		if owner := data.GetSharedBy(); owner != "" {
+			dd.Share = &domain2.DataShare{
+				DataID:     dd.ID,
+				Owner:      owner,
+				RecordKey:  data.GetRecordKey(),
+				Permission: domain2.SharePermission(data.GetPermission()),
+			}
 		}
 
 		dataList = append(dataList, dd)
 	}
 
-	return dataList, nil
+	return dataList, resp.GetNextPageToken(), nil
 }
 
 // SaveData сохранение данных
 func (c *DataClient) SaveData(ctx context.Context, data *clientDomain.Data) error {
 	pbData := &pb.Data{
-		Id:      data.ID,
-		Name:    data.Name,
-		Version: data.Version,
-		Login:   data.Login,
-		Pass:    data.Pass,
-		Text:    data.Text,
-		CardNum: data.CardNum,
-		Meta:    data.Meta,
+		Id:       data.ID,
+		Name:     data.Name,
+		Version:  data.Version,
+		Login:    data.Login,
+		Pass:     data.Pass,
+		Text:     data.Text,
+		CardNum:  data.CardNum,
+		CardExp:  data.CardExp,
+		Otp:      data.Otp,
+		Meta:     data.Meta,
+		FolderId: data.FolderID,
+		Tags:     data.Tags,
+		NameHash: data.NameHash,
+
+		RecordKey: data.RecordKey,
 	}
 
 	resp, err := c.client.SaveData(ctx, &pb.SaveDataRequest{
@@ -253,3 +297,32 @@ func (c *DataClient) DeleteData(ctx context.Context, id uint64) error {
 
 	return nil
 }
+
+// ShareData предоставить пользователю login доступ к записи, recordKey зашифрован его открытым ключом
+func (c *DataClient) ShareData(ctx context.Context, id uint64, login, recordKey string, permission domain2.SharePermission) error {
+	_, err := c.client.ShareData(ctx, &pb.ShareDataRequest{
+		DataId:     id,
+		Login:      login,
+		RecordKey:  recordKey,
+		Permission: pb.SharePermission(permission),
+	})
+
+	if err != nil && status.Code(err) == codes.Internal {
+		internal.Logger.Errorw("error while share data", "error", err)
+		return clientDomain.ErrSaveDataRequest
+	}
+
+	return err
+}
+
+// UnshareData отозвать доступ пользователя login к записи
+func (c *DataClient) UnshareData(ctx context.Context, id uint64, login string) error {
+	_, err := c.client.UnshareData(ctx, &pb.UnshareDataRequest{DataId: id, Login: login})
+
+	if err != nil && status.Code(err) == codes.Internal {
+		internal.Logger.Errorw("error while unshare data", "error", err)
+		return clientDomain.ErrSaveDataRequest
+	}
+
+	return err
+}
@@ -7,12 +7,14 @@ import (
 	"gophkeeper/internal/server/auth"
 	g "gophkeeper/internal/server/grpc"
 	"gophkeeper/internal/server/grpc/interceptors"
+	"gophkeeper/internal/server/repository/memory"
 	"gophkeeper/internal/server/repository/pgsql"
 	"gophkeeper/internal/test"
 	pb "gophkeeper/proto"
 	"gophkeeper/server/data"
 	domain2 "gophkeeper/server/domain"
 	"gophkeeper/server/file"
+	"gophkeeper/server/folder"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -26,6 +28,8 @@ import (
 )
 
 func TestDataClient_SaveData(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	internal.InitLogger()
 	ctx := context.Background()
 	existingUserLogin := "kaka"
@@ -68,7 +72,7 @@ func TestDataClient_SaveData(t *testing.T) {
 	err = dataRepo.Insert(ctx, testData)
 	assert.NoError(t, err)
 
-	server := g.NewDataServer(data.NewService(dataRepo, fileRepo), "/tmp", file.NewService(fileRepo))
+	server := g.NewDataServer(data.NewService(dataRepo, fileRepo, memory.NewUserRepository()), "/tmp", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), dataRepo))
 
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth))
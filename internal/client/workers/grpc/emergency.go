@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	clientDomain "gophkeeper/client/domain"
+	pb "gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type EmergencyClient struct {
+	client pb.EmergencyServiceClient
+}
+
+func NewEmergencyClient(client pb.EmergencyServiceClient) *EmergencyClient {
+	return &EmergencyClient{
+		client: client,
+	}
+}
+
+// Create назначение доверенного лица login, vaultKey - ключ хранилища, зашифрованный его открытым ключом
+func (c *EmergencyClient) Create(ctx context.Context, login string, wait time.Duration, vaultKey string) (uint64, error) {
+	resp, err := c.client.CreateGrant(ctx, &pb.CreateGrantRequest{Login: login, WaitSeconds: int64(wait / time.Second), VaultKey: vaultKey})
+	if err != nil {
+		return 0, orgError("create emergency access", err)
+	}
+
+	return resp.GetId(), nil
+}
+
+// GetList экстренные доступы пользователя как владельца и как доверенного лица
+func (c *EmergencyClient) GetList(ctx context.Context) ([]clientDomain.EmergencyGrant, error) {
+	resp, err := c.client.GetGrants(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, orgError("get emergency access", err)
+	}
+
+	res := make([]clientDomain.EmergencyGrant, 0, len(resp.GetGrants()))
+	for _, g := range resp.GetGrants() {
+		grant := clientDomain.EmergencyGrant{
+			ID:       g.GetId(),
+			Grantor:  g.GetGrantor(),
+			Grantee:  g.GetGrantee(),
+			State:    domain2.EmergencyState(g.GetState()),
+			Wait:     time.Duration(g.GetWaitSeconds()) * time.Second,
+			VaultKey: g.GetVaultKey(),
+		}
+
+		if g.GetRequestedAt() != 0 {
+			requested := time.Unix(g.GetRequestedAt(), 0)
+			grant.RequestedAt = &requested
+		}
+
+		res = append(res, grant)
+	}
+
+	return res, nil
+}
+
+// Accept согласие стать доверенным лицом
+func (c *EmergencyClient) Accept(ctx context.Context, id uint64) error {
+	_, err := c.client.AcceptGrant(ctx, &pb.GrantRequest{Id: id})
+
+	return orgError("accept emergency access", err)
+}
+
+// Request запрос доступа к хранилищу владельца
+func (c *EmergencyClient) Request(ctx context.Context, id uint64) error {
+	_, err := c.client.RequestAccess(ctx, &pb.GrantRequest{Id: id})
+
+	return orgError("request emergency access", err)
+}
+
+// Approve одобрение запроса доступа
+func (c *EmergencyClient) Approve(ctx context.Context, id uint64) error {
+	_, err := c.client.ApproveAccess(ctx, &pb.GrantRequest{Id: id})
+
+	return orgError("approve emergency access", err)
+}
+
+// Reject отказ в доступе
+func (c *EmergencyClient) Reject(ctx context.Context, id uint64) error {
+	_, err := c.client.RejectAccess(ctx, &pb.GrantRequest{Id: id})
+
+	return orgError("reject emergency access", err)
+}
+
+// Revoke удаление экстренного доступа
+func (c *EmergencyClient) Revoke(ctx context.Context, id uint64) error {
+	_, err := c.client.RevokeGrant(ctx, &pb.GrantRequest{Id: id})
+
+	return orgError("revoke emergency access", err)
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	clientDomain "gophkeeper/client/domain"
+	"gophkeeper/internal"
+	pb "gophkeeper/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GetFolders получение всех папок пользователя
+func (c *DataClient) GetFolders(ctx context.Context) ([]clientDomain.Folder, error) {
+	resp, err := c.client.GetFolders(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, folderError(err)
+	}
+
+	folders := make([]clientDomain.Folder, 0, len(resp.GetFolders()))
+	for _, f := range resp.GetFolders() {
+		folders = append(folders, clientDomain.Folder{
+			ID:       f.GetId(),
+			ParentID: f.GetParentId(),
+			Name:     f.GetName(),
+		})
+	}
+
+	return folders, nil
+}
+
+// SaveFolder добавление или сохранение папки
+func (c *DataClient) SaveFolder(ctx context.Context, folder *clientDomain.Folder) error {
+	resp, err := c.client.SaveFolder(ctx, &pb.SaveFolderRequest{
+		Folder: &pb.Folder{
+			Id:       folder.ID,
+			ParentId: folder.ParentID,
+			Name:     folder.Name,
+		},
+	})
+	if err != nil {
+		return folderError(err)
+	}
+
+	folder.ID = resp.GetFolderId()
+
+	return nil
+}
+
+// DeleteFolder удаление папки вместе с вложенными папками
+func (c *DataClient) DeleteFolder(ctx context.Context, id uint64) error {
+	_, err := c.client.DeleteFolder(ctx, &pb.DeleteFolderRequest{Id: id})
+	if err != nil {
+		return folderError(err)
+	}
+
+	return nil
+}
+
+func folderError(err error) error {
+	switch status.Code(err) {
+	case codes.Internal:
+		internal.Logger.Errorw("error in folder request", "error", err)
+		return clientDomain.ErrFolderRequest
+	case codes.NotFound:
+		return clientDomain.ErrFolderNotFound
+	}
+
+	return err
+}
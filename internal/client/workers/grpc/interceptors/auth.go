@@ -6,6 +6,7 @@ import (
 	"errors"
 	"gophkeeper/proto"
 	"gophkeeper/server/domain"
+	"strconv"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -13,6 +14,17 @@ import (
 
 type ContextUserTokenKey struct{}
 
+// ContextVaultKey ключ контекста для хранилища организации, в котором выполняется запрос
+type ContextVaultKey struct{}
+
+// Vault хранилище организации и версия ключа, которым клиент шифрует данные
+// GrantID - экстренный доступ к хранилищу другого пользователя, вместо организации
+type Vault struct {
+	OrgID,
+	KeyVersion,
+	GrantID uint64
+}
+
 func Auth(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 	var err error
 
@@ -34,7 +46,8 @@ func StreamAuth(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
 }
 
 func setAuthMeta(ctx context.Context, method string) (context.Context, error) {
-	if method == proto.UserService_Register_FullMethodName || method == proto.UserService_Login_FullMethodName {
+	if method == proto.UserService_Register_FullMethodName || method == proto.UserService_Login_FullMethodName ||
+		method == proto.UserService_Refresh_FullMethodName || method == proto.UserService_RestoreAccount_FullMethodName {
 		return ctx, nil
 	}
 
@@ -44,6 +57,14 @@ func setAuthMeta(ctx context.Context, method string) (context.Context, error) {
 	}
 
 	md := metadata.Pairs(domain.AuthorizationMetaKey, domain.TokenSubstr+" "+token)
+
+	if vault, ok := ctx.Value(ContextVaultKey{}).(Vault); ok && vault.GrantID != 0 {
+		md.Append(domain.EmergencyMetaKey, strconv.FormatUint(vault.GrantID, 10))
+	} else if ok {
+		md.Append(domain.VaultMetaKey, strconv.FormatUint(vault.OrgID, 10))
+		md.Append(domain.VaultKeyVersionMetaKey, strconv.FormatUint(vault.KeyVersion, 10))
+	}
+
 	ctx = metadata.NewOutgoingContext(ctx, md)
 	return ctx, nil
 }
@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	clientDomain "gophkeeper/client/domain"
+	"gophkeeper/internal"
+	pb "gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type OrgClient struct {
+	client pb.OrganizationServiceClient
+}
+
+func NewOrgClient(client pb.OrganizationServiceClient) *OrgClient {
+	return &OrgClient{
+		client: client,
+	}
+}
+
+// Create создание организации, collectionKey - ключ хранилища, зашифрованный открытым ключом пользователя
+func (c *OrgClient) Create(ctx context.Context, name, collectionKey string) (uint64, error) {
+	resp, err := c.client.CreateOrganization(ctx, &pb.CreateOrganizationRequest{Name: name, CollectionKey: collectionKey})
+	if err != nil {
+		return 0, orgError("create organization", err)
+	}
+
+	return resp.GetId(), nil
+}
+
+// GetList организации пользователя и приглашения в них
+func (c *OrgClient) GetList(ctx context.Context) ([]clientDomain.Organization, error) {
+	resp, err := c.client.GetOrganizations(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, orgError("get organizations", err)
+	}
+
+	res := make([]clientDomain.Organization, 0, len(resp.GetOrganizations()))
+	for _, o := range resp.GetOrganizations() {
+		res = append(res, clientDomain.Organization{
+			ID:            o.GetId(),
+			KeyVersion:    o.GetKeyVersion(),
+			Name:          o.GetName(),
+			CollectionKey: o.GetCollectionKey(),
+			Role:          domain2.OrgRole(o.GetRole()),
+			Accepted:      o.GetAccepted(),
+		})
+	}
+
+	return res, nil
+}
+
+// GetMembers участники организации и текущая версия ключа хранилища
+func (c *OrgClient) GetMembers(ctx context.Context, orgID uint64) ([]clientDomain.OrgMember, uint64, error) {
+	resp, err := c.client.GetMembers(ctx, &pb.OrgRequest{OrgId: orgID})
+	if err != nil {
+		return nil, 0, orgError("get organization members", err)
+	}
+
+	res := make([]clientDomain.OrgMember, 0, len(resp.GetMembers()))
+	for _, m := range resp.GetMembers() {
+		res = append(res, clientDomain.OrgMember{Login: m.GetLogin(), Role: domain2.OrgRole(m.GetRole()), Accepted: m.GetAccepted()})
+	}
+
+	return res, resp.GetKeyVersion(), nil
+}
+
+// Invite приглашение пользователя login, collectionKey - ключ хранилища, зашифрованный его открытым ключом
+func (c *OrgClient) Invite(ctx context.Context, orgID uint64, login string, role domain2.OrgRole, collectionKey string, keyVersion uint64) error {
+	_, err := c.client.InviteMember(ctx, &pb.InviteMemberRequest{
+		OrgId:         orgID,
+		Login:         login,
+		Role:          pb.OrgRole(role),
+		CollectionKey: collectionKey,
+		KeyVersion:    keyVersion,
+	})
+
+	return orgError("invite member", err)
+}
+
+// Accept принятие приглашения
+func (c *OrgClient) Accept(ctx context.Context, orgID uint64) error {
+	_, err := c.client.AcceptInvite(ctx, &pb.OrgRequest{OrgId: orgID})
+
+	return orgError("accept invite", err)
+}
+
+// SetRole изменение роли участника
+func (c *OrgClient) SetRole(ctx context.Context, orgID uint64, login string, role domain2.OrgRole) error {
+	_, err := c.client.SetMemberRole(ctx, &pb.SetMemberRoleRequest{OrgId: orgID, Login: login, Role: pb.OrgRole(role)})
+
+	return orgError("set member role", err)
+}
+
+// Remove исключение участника вместе со сменой ключа хранилища
+func (c *OrgClient) Remove(ctx context.Context, req clientDomain.RemoveMember) error {
+	pbReq := &pb.RemoveMemberRequest{OrgId: req.OrgID, Login: req.Login, KeyVersion: req.KeyVersion}
+
+	for login, key := range req.Keys {
+		pbReq.Keys = append(pbReq.Keys, &pb.MemberKey{Login: login, CollectionKey: key})
+	}
+
+	for _, r := range req.Records {
+		pbReq.Records = append(pbReq.Records, &pb.RotatedRecord{DataId: r.ID, RecordKey: r.RecordKey, NameHash: r.NameHash, Tags: r.Tags})
+	}
+
+	for _, f := range req.Folders {
+		pbReq.Folders = append(pbReq.Folders, &pb.RotatedFolder{FolderId: f.ID, Name: f.Name})
+	}
+
+	_, err := c.client.RemoveMember(ctx, pbReq)
+
+	return orgError("remove member", err)
+}
+
+// orgError внутренние ошибки сервера логируются и заменяются общей ошибкой
+func orgError(action string, err error) error {
+	if err != nil && status.Code(err) == codes.Internal {
+		internal.Logger.Errorw("error while "+action, "error", err)
+		return clientDomain.ErrSomethingWrong
+	}
+
+	return err
+}
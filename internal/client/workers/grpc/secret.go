@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+	pb "gophkeeper/proto"
+	"time"
+)
+
+type SecretClient struct {
+	client pb.SecretServiceClient
+}
+
+func NewSecretClient(client pb.SecretServiceClient) *SecretClient {
+	return &SecretClient{
+		client: client,
+	}
+}
+
+// Create сохранение зашифрованного секрета, возвращается ссылка на него без ключа
+func (c *SecretClient) Create(ctx context.Context, blob string, expire time.Duration, maxViews int) (string, error) {
+	resp, err := c.client.CreateSecretShare(ctx, &pb.CreateSecretShareRequest{
+		Blob:          blob,
+		ExpireSeconds: int64(expire / time.Second),
+		MaxViews:      int32(maxViews),
+	})
+	if err != nil {
+		return "", orgError("create secret link", err)
+	}
+
+	return resp.GetUrl(), nil
+}
@@ -2,11 +2,18 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"gophkeeper/client/domain"
 	pb "gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+	"strconv"
+	"time"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type UserClient struct {
@@ -20,53 +27,150 @@ func NewUserClient(client pb.UserServiceClient) *UserClient {
 }
 
 // Registration регистрация пользователя
-func (c *UserClient) Registration(login, password string) (token string, err error) {
+func (c *UserClient) Registration(login, password string) (token, refreshToken string, err error) {
 	var response *pb.RegisterResponse
 
+	var header metadata.MD
+
 	response, err = c.client.Register(context.Background(), &pb.RegisterRequest{
 		User: &pb.User{
 			Login:    login,
 			Password: password,
 		},
-	})
+	}, grpc.Header(&header))
 
 	if err != nil {
-		if status.Code(err) == codes.Internal {
-			return "", domain.ErrRegisterRequest
-		}
-
-		return "", err
+		return "", "", authError(err, header)
 	}
 
 	if len(response.Token) == 0 {
-		return "", domain.ErrRegisterRequest
+		return "", "", domain.ErrRegisterRequest
 	}
 
-	return response.Token, nil
+	return response.Token, response.RefreshToken, nil
 }
 
 // Login авторизация пользователя
-func (c *UserClient) Login(login, password string) (token string, err error) {
+func (c *UserClient) Login(login, password string) (token, refreshToken string, err error) {
 	var response *pb.RegisterResponse
 
+	var header metadata.MD
+
 	response, err = c.client.Login(context.Background(), &pb.RegisterRequest{
 		User: &pb.User{
 			Login:    login,
 			Password: password,
 		},
-	})
+	}, grpc.Header(&header))
+
+	if err != nil {
+		return "", "", authError(err, header)
+	}
+
+	if len(response.Token) == 0 {
+		return "", "", domain.ErrRegisterRequest
+	}
+
+	return response.Token, response.RefreshToken, nil
+}
 
+// Refresh получение нового токена доступа по токену обновления
+func (c *UserClient) Refresh(refreshToken string) (token, newRefreshToken string, err error) {
+	var response *pb.RegisterResponse
+
+	response, err = c.client.Refresh(context.Background(), &pb.RefreshRequest{RefreshToken: refreshToken})
 	if err != nil {
 		if status.Code(err) == codes.Internal {
-			return "", domain.ErrRegisterRequest
+			return "", "", domain.ErrRegisterRequest
 		}
 
+		return "", "", err
+	}
+
+	if len(response.Token) == 0 {
+		return "", "", domain.ErrRegisterRequest
+	}
+
+	return response.Token, response.RefreshToken, nil
+}
+
+// GetKeys получение пары ключей пользователя, пустая - если ключи еще не заданы
+func (c *UserClient) GetKeys(ctx context.Context) (domain2.UserKeys, error) {
+	resp, err := c.client.GetKeys(ctx, &emptypb.Empty{})
+	if err != nil {
+		return domain2.UserKeys{}, err
+	}
+
+	return domain2.UserKeys{PublicKey: resp.GetPublicKey(), PrivateKey: resp.GetPrivateKey()}, nil
+}
+
+// SetKeys сохранение пары ключей пользователя, закрытый ключ должен быть зашифрован
+func (c *UserClient) SetKeys(ctx context.Context, keys domain2.UserKeys) error {
+	_, err := c.client.SetKeys(ctx, &pb.KeyPair{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey})
+
+	return err
+}
+
+// GetPublicKey получение открытого ключа другого пользователя
+func (c *UserClient) GetPublicKey(ctx context.Context, login string) (string, error) {
+	resp, err := c.client.GetPublicKey(ctx, &pb.PublicKeyRequest{Login: login})
+	if err != nil {
 		return "", err
 	}
 
+	return resp.GetPublicKey(), nil
+}
+
+// DeleteAccount удаление учетной записи с подтверждением паролем, сразу или через graceDays дней
+// возвращается время окончательного удаления, нулевое - учетная запись уже удалена
+func (c *UserClient) DeleteAccount(ctx context.Context, password string, graceDays uint32) (time.Time, error) {
+	resp, err := c.client.DeleteAccount(ctx, &pb.DeleteAccountRequest{Password: password, GraceDays: graceDays})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if resp.GetPurgeAt() == 0 {
+		return time.Time{}, nil
+	}
+
+	return time.Unix(resp.GetPurgeAt(), 0), nil
+}
+
+// RestoreAccount отмена запланированного удаления учетной записи, возвращаются новые токены
+func (c *UserClient) RestoreAccount(login, password string) (token, refreshToken string, err error) {
+	var header metadata.MD
+
+	response, err := c.client.RestoreAccount(context.Background(), &pb.RegisterRequest{
+		User: &pb.User{
+			Login:    login,
+			Password: password,
+		},
+	}, grpc.Header(&header))
+	if err != nil {
+		return "", "", authError(err, header)
+	}
+
 	if len(response.Token) == 0 {
-		return "", domain.ErrRegisterRequest
+		return "", "", domain.ErrRegisterRequest
+	}
+
+	return response.Token, response.RefreshToken, nil
+}
+
+// authError ошибка регистрации или входа, при превышении числа попыток к ошибке добавляется время ожидания из retry-after
+func authError(err error, header metadata.MD) error {
+	switch status.Code(err) {
+	case codes.Internal:
+		return domain.ErrRegisterRequest
+	case codes.ResourceExhausted:
+		if vals := header.Get(domain2.RetryAfterMetaKey); len(vals) > 0 {
+			if seconds, convErr := strconv.Atoi(vals[0]); convErr == nil {
+				return fmt.Errorf("%w, retry in %s", domain.ErrTooManyAttempts, time.Duration(seconds)*time.Second)
+			}
+		}
+
+		return domain.ErrTooManyAttempts
 	}
 
-	return response.Token, nil
+	return err
 }
@@ -30,6 +30,8 @@ func bufDialer(context.Context, string) (net.Conn, error) {
 }
 
 func TestUserClient_Registration(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	internal.InitLogger()
 	ctx := context.Background()
 	existingUserLogin := "kaka"
@@ -122,7 +124,7 @@ func TestUserClient_Registration(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var token string
-			token, err = client.Registration(tt.args.login, tt.args.password)
+			token, _, err = client.Registration(tt.args.login, tt.args.password)
 			if tt.wantErr {
 				assert.Equal(t, tt.wantErrorCode, status.Code(err))
 			} else {
@@ -134,6 +136,8 @@ func TestUserClient_Registration(t *testing.T) {
 }
 
 func TestUserClient_Login(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	internal.InitLogger()
 	ctx := context.Background()
 	existingUserLogin := "kaka"
@@ -221,7 +225,7 @@ func TestUserClient_Login(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var token string
-			token, err = client.Login(tt.args.login, tt.args.password)
+			token, _, err = client.Login(tt.args.login, tt.args.password)
 			if tt.wantErr {
 				assert.Equal(t, tt.wantErrorCode, status.Code(err))
 			} else {
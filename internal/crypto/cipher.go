@@ -3,10 +3,14 @@
 package crypto
 
 import (
+	stdcrypto "crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"gophkeeper/internal"
 	"os"
 	"path/filepath"
@@ -120,6 +124,31 @@ func (c *Cipher) Decrypt(encrypted []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
+// ErrNoSigningKey для подписи нужен закрытый ключ сервера, для проверки - открытый
+var ErrNoSigningKey = errors.New("server key for signing is not loaded")
+
+// Sign подпись данных закрытым ключом сервера (RSA-PSS, SHA-256)
+func (c *Cipher) Sign(data []byte) ([]byte, error) {
+	if c == nil || c.privateKey == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	digest := sha256.Sum256(data)
+
+	return rsa.SignPSS(rand.Reader, c.privateKey, stdcrypto.SHA256, digest[:], nil)
+}
+
+// Verify проверка подписи данных открытым ключом сервера
+func (c *Cipher) Verify(data, signature []byte) error {
+	if c == nil || c.publicKey == nil {
+		return ErrNoSigningKey
+	}
+
+	digest := sha256.Sum256(data)
+
+	return rsa.VerifyPSS(c.publicKey, stdcrypto.SHA256, digest[:], signature, nil)
+}
+
 func (c *Cipher) IsPrivateKeyExist() bool {
 	if c == nil || c.privateKey == nil {
 		return false
@@ -163,3 +192,17 @@ func (c *Cipher) GetServerGRPCTransportCreds() credentials.TransportCredentials
 
 	return creds
 }
+
+// GetServerTLSConfig настройки TLS для HTTP сервера с тем же сертификатом, что и у gRPC, nil - соединение без TLS
+func (c *Cipher) GetServerTLSConfig() *tls.Config {
+	if c == nil || c.certPath == "" || c.privateKeyPath == "" {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certPath, c.privateKeyPath)
+	if err != nil {
+		internal.Logger.Fatalw("Failed to load server TLS certificate", "err", err)
+	}
+
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+}
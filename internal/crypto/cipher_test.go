@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const cryptKeysPath = "/Users/fanishadeev/GolandProjects/gophkeeper/internal/crypto"
@@ -99,3 +100,18 @@ func TestCipher_GetServerGRPCTransportCreds(t *testing.T) {
 		})
 	}
 }
+
+func TestCipher_Sign(t *testing.T) {
+	c, err := NewCipher(".")
+	require.NoError(t, err)
+
+	signature, err := c.Sign([]byte("checkpoint"))
+	require.NoError(t, err)
+
+	assert.NoError(t, c.Verify([]byte("checkpoint"), signature))
+	assert.Error(t, c.Verify([]byte("checkpoint!"), signature))
+
+	var empty *Cipher
+	_, err = empty.Sign([]byte("checkpoint"))
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+}
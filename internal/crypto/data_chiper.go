@@ -3,7 +3,9 @@ package crypto
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 )
 
@@ -29,6 +31,45 @@ func Encrypt(key, data []byte) (string, error) {
 	return base64.StdEncoding.EncodeToString(ciphertext), nil
 }
 
+// EncryptDeterministic шифрование, при котором одинаковые данные дают одинаковый результат
+// nonce вычисляется как HMAC от данных, поэтому сервер может сравнивать значения на равенство, не зная их
+// подходит только для коротких меток (теги, названия папок), расшифровывается через Decrypt
+func EncryptDeterministic(key, data []byte) (string, error) {
+	blockCipher, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(blockCipher)
+	if err != nil {
+		return "", err
+	}
+
+	// ключ для nonce выводится из ключа пользователя, чтобы не использовать один ключ в AES и HMAC
+	nonceKey := hmac.New(sha256.New, key)
+	nonceKey.Write([]byte("deterministic nonce"))
+
+	mac := hmac.New(sha256.New, nonceKey.Sum(nil))
+	mac.Write(data)
+	nonce := mac.Sum(nil)[:gcm.NonceSize()]
+
+	ciphertext := gcm.Seal(nonce, nonce, data, nil)
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// BlindIndex слепой индекс значения для проверки на равенство без раскрытия самого значения
+// HMAC-SHA256 на ключе, выведенном из ключа пользователя, без ключа индекс нельзя подобрать по словарю
+func BlindIndex(key, data []byte) string {
+	indexKey := hmac.New(sha256.New, key)
+	indexKey.Write([]byte("blind index"))
+
+	mac := hmac.New(sha256.New, indexKey.Sum(nil))
+	mac.Write(data)
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
 // Decrypt расшифровка данных, с помощью ключа пользователя
 func Decrypt(key []byte, dataB64 string) (string, error) {
 	data, err := base64.StdEncoding.DecodeString(dataB64)
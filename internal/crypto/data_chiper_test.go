@@ -41,3 +41,35 @@ func TestEncrypt(t *testing.T) {
 		})
 	}
 }
+
+func TestEncryptDeterministic(t *testing.T) {
+	key := pbkdf2.Key([]byte("some_key"), []byte("some salt"), 4096, 32, sha1.New)
+
+	first, err := EncryptDeterministic(key, []byte("work"))
+	assert.NoError(t, err)
+
+	second, err := EncryptDeterministic(key, []byte("work"))
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	other, err := EncryptDeterministic(key, []byte("home"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, other)
+
+	decrypted, err := Decrypt(key, first)
+	assert.NoError(t, err)
+	assert.Equal(t, "work", decrypted)
+}
+
+func TestBlindIndex(t *testing.T) {
+	key := pbkdf2.Key([]byte("some_key"), []byte("some salt"), 4096, 32, sha1.New)
+	otherKey := pbkdf2.Key([]byte("other_key"), []byte("some salt"), 4096, 32, sha1.New)
+
+	index := BlindIndex(key, []byte("prod-db-root"))
+
+	assert.Equal(t, index, BlindIndex(key, []byte("prod-db-root")))
+	assert.NotEqual(t, index, BlindIndex(key, []byte("prod-db-user")))
+	assert.NotEqual(t, index, BlindIndex(otherKey, []byte("prod-db-root")))
+	assert.NotContains(t, index, "prod")
+	assert.LessOrEqual(t, len(index), 128)
+}
@@ -0,0 +1,130 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// RecordKeySize размер ключа записи (AES-256)
+const RecordKeySize = 32
+
+// shareInfo контекст вывода ключа шифрования ключа записи
+const shareInfo = "gophkeeper record key"
+
+var ErrSealedKey = errors.New("bad sealed record key")
+
+// GenerateKeyPair пара ключей X25519 для обмена записями
+func GenerateKeyPair() (public, private []byte, err error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key.PublicKey().Bytes(), key.Bytes(), nil
+}
+
+// NewRecordKey случайный ключ записи
+func NewRecordKey() ([]byte, error) {
+	key := make([]byte, RecordKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// SealKey шифрование ключа записи открытым ключом получателя
+// для каждого вызова создается одноразовая пара ключей, общий секрет X25519 через HKDF дает ключ AES-GCM
+// результат: одноразовый открытый ключ, nonce и шифротекст в base64
+func SealKey(public, key []byte) (string, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(public)
+	if err != nil {
+		return "", err
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := shareCipher(ephemeral, recipient, append(ephemeral.PublicKey().Bytes(), public...))
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := append(ephemeral.PublicKey().Bytes(), nonce...)
+	sealed = gcm.Seal(sealed, nonce, key, nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenKey расшифровка ключа записи закрытым ключом получателя
+func OpenKey(private []byte, sealedB64 string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(sealedB64)
+	if err != nil {
+		return nil, ErrSealedKey
+	}
+
+	key, err := ecdh.X25519().NewPrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	// 32 байта открытого ключа X25519, nonce GCM и тег GCM
+	if len(sealed) < 32+12+16 {
+		return nil, ErrSealedKey
+	}
+
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed[:32])
+	if err != nil {
+		return nil, ErrSealedKey
+	}
+
+	gcm, err := shareCipher(key, ephemeral, append(sealed[:32:32], key.PublicKey().Bytes()...))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, ciphertext := sealed[32:32+gcm.NonceSize()], sealed[32+gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrSealedKey
+	}
+
+	return plaintext, nil
+}
+
+// shareCipher AES-GCM на ключе, выведенном из общего секрета
+// солью служат одноразовый открытый ключ и открытый ключ получателя, чтобы шифротекст нельзя было переадресовать
+func shareCipher(private *ecdh.PrivateKey, public *ecdh.PublicKey, salt []byte) (cipher.AEAD, error) {
+	secret, err := private.ECDH(public)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, RecordKeySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(shareInfo)), key); err != nil {
+		return nil, err
+	}
+
+	blockCipher, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(blockCipher)
+}
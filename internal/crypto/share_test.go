@@ -0,0 +1,37 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealKey(t *testing.T) {
+	public, private, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	key, err := NewRecordKey()
+	require.NoError(t, err)
+
+	sealed, err := SealKey(public, key)
+	require.NoError(t, err)
+
+	// одноразовая пара ключей делает результат разным при каждом вызове
+	again, err := SealKey(public, key)
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+
+	opened, err := OpenKey(private, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, key, opened)
+
+	_, otherPrivate, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	_, err = OpenKey(otherPrivate, sealed)
+	assert.ErrorIs(t, err, ErrSealedKey)
+
+	_, err = OpenKey(private, "c2hvcnQ=")
+	assert.ErrorIs(t, err, ErrSealedKey)
+}
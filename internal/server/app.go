@@ -3,11 +3,14 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"flag"
 	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/sqlite"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -17,21 +20,47 @@ const (
 	databaseURIVar = "DATABASE_URI"
 	saveFilesPath  = "FILES_SAVE_PATH"
 	cryptoKeysPath = "CRYPTO_KEYS_PATH"
+	storageVar     = "STORAGE"
+	httpAddressVar = "HTTP_ADDRESS"
+	publicURLVar   = "PUBLIC_URL"
+	sharedLimitVar = "SHARED_RATE_LIMIT"
+)
+
+// Типы хранилищ сервера
+const (
+	StoragePgsql  = "pgsql"
+	StorageSQLite = "sqlite"
+	StorageMemory = "memory"
 )
 
 // App структура для хранения данных приложения
+// Command - служебная команда с аргументами (backup, restore, verify-audit), пустая при запуске сервера
 type App struct {
 	Address,
 	FilesSavePath,
-	CryptoKeysPath string
-	DBPool *pgxpool.Pool
+	CryptoKeysPath,
+	Storage string
+	Command  []string
+	DBPool   *pgxpool.Pool
+	SQLiteDB *sql.DB
+	// HTTPAddress адрес HTTP сервера для одноразовых ссылок на секреты, пустой - ссылки отключены
+	HTTPAddress string
+	// PublicURL адрес HTTP сервера в ссылках, если сервер доступен снаружи по другому адресу (например, за прокси)
+	PublicURL string
+	// SharedRateLimit ограничения попыток входа хранятся в Postgres и общие для всех серверов, иначе - в памяти сервера
+	SharedRateLimit bool
 }
 
 type config struct {
 	runAddress,
 	databaseURI,
 	cryptoKeysPath,
-	saveFilePath string
+	saveFilePath,
+	storage,
+	httpAddress,
+	publicURL string
+	command         []string
+	sharedRateLimit bool
 }
 
 func InitApp(ctx context.Context) (*App, error) {
@@ -41,17 +70,37 @@ func InitApp(ctx context.Context) (*App, error) {
 		return nil, err
 	}
 
-	dbPool, err := initDB(ctx, c.databaseURI)
-	if err != nil {
-		return nil, err
+	app := &App{
+		Address:         c.runAddress,
+		FilesSavePath:   c.saveFilePath,
+		CryptoKeysPath:  c.cryptoKeysPath,
+		Storage:         c.storage,
+		Command:         c.command,
+		HTTPAddress:     c.httpAddress,
+		PublicURL:       c.publicURL,
+		SharedRateLimit: c.sharedRateLimit,
+	}
+
+	switch c.storage {
+	case StorageMemory:
+		internal.Logger.Infow("server started with in-memory storage, data will be lost on shutdown")
+	case StorageSQLite:
+		db, err := sqlite.Open(ctx, c.databaseURI)
+		if err != nil {
+			return nil, err
+		}
+
+		app.SQLiteDB = db
+	default:
+		dbPool, err := initDB(ctx, c.databaseURI)
+		if err != nil {
+			return nil, err
+		}
+
+		app.DBPool = dbPool
 	}
 
-	return &App{
-		Address:        c.runAddress,
-		DBPool:         dbPool,
-		FilesSavePath:  c.saveFilePath,
-		CryptoKeysPath: c.cryptoKeysPath,
-	}, nil
+	return app, nil
 }
 
 func initConfig() *config {
@@ -61,9 +110,15 @@ func initConfig() *config {
 	flag.StringVar(&c.databaseURI, "d", "", "database uri")
 	flag.StringVar(&c.saveFilePath, "f", "", "save files path")
 	flag.StringVar(&c.cryptoKeysPath, "c", "", "crypto keys path")
+	flag.StringVar(&c.storage, "storage", "", "storage type: pgsql, sqlite or memory (by default detected from database uri)")
+	flag.StringVar(&c.httpAddress, "http", "", "http address for secret links (disabled if empty)")
+	flag.StringVar(&c.publicURL, "public-url", "", "base url of secret links (by default https://<http address>)")
+	flag.BoolVar(&c.sharedRateLimit, "shared-rate-limit", false, "keep login rate limits in postgres, shared by all servers")
 
 	flag.Parse()
 
+	c.command = flag.Args()
+
 	if envVar := os.Getenv(runAddressVar); envVar != "" {
 		c.runAddress = envVar
 	}
@@ -80,6 +135,33 @@ func initConfig() *config {
 		c.cryptoKeysPath = envVar
 	}
 
+	if envVar := os.Getenv(storageVar); envVar != "" {
+		c.storage = envVar
+	}
+
+	if envVar := os.Getenv(httpAddressVar); envVar != "" {
+		c.httpAddress = envVar
+	}
+
+	if envVar := os.Getenv(publicURLVar); envVar != "" {
+		c.publicURL = envVar
+	}
+
+	if envVar := os.Getenv(sharedLimitVar); envVar != "" {
+		c.sharedRateLimit, _ = strconv.ParseBool(envVar)
+	}
+
+	if c.publicURL == "" && c.httpAddress != "" {
+		c.publicURL = "https://" + c.httpAddress
+	}
+
+	if c.storage == "" {
+		c.storage = StoragePgsql
+		if sqlite.IsSQLiteURI(c.databaseURI) {
+			c.storage = StorageSQLite
+		}
+	}
+
 	if c.cryptoKeysPath != "" {
 		c.cryptoKeysPath = filepath.FromSlash(c.cryptoKeysPath)
 	}
@@ -111,9 +193,28 @@ func initDB(ctx context.Context, DSN string) (*pgxpool.Pool, error) {
 }
 
 func checkConfig(c *config) error {
-	if c.runAddress == "" || c.databaseURI == "" || c.saveFilePath == "" || c.cryptoKeysPath == "" {
+	if c.saveFilePath == "" {
 		return errors.New("please, check configs")
 	}
 
+	// адрес и ключи нужны только для запуска сервера
+	if len(c.command) == 0 && (c.runAddress == "" || c.cryptoKeysPath == "") {
+		return errors.New("please, check configs")
+	}
+
+	switch c.storage {
+	case StoragePgsql, StorageSQLite:
+		if c.databaseURI == "" {
+			return errors.New("please, check configs")
+		}
+	case StorageMemory:
+	default:
+		return errors.New("unknown storage type: " + c.storage)
+	}
+
+	if c.sharedRateLimit && c.storage != StoragePgsql {
+		return errors.New("shared rate limit requires pgsql storage")
+	}
+
 	return nil
 }
@@ -13,21 +13,39 @@ import (
 type claims struct {
 	jwt.RegisteredClaims
 	UserID uint64
+	// Type тип токена, пустой у токена доступа
+	Type string `json:",omitempty"`
 }
 
 const tokenExp = time.Hour * 3
+const refreshTokenExp = time.Hour * 24 * 30
 const secretKey = "someSecretSuperKey"
 
+// refreshTokenType тип токена обновления, таким токеном нельзя авторизовать запрос к данным
+const refreshTokenType = "refresh"
+
 // BuildJWTString получить токен пользвателя, содержащий его ИД
 func BuildJWTString(userID uint64) (string, error) {
+	return buildToken(userID, "", tokenExp)
+}
+
+// BuildRefreshToken получить долгоживущий токен, по которому выдается новый токен доступа
+func BuildRefreshToken(userID uint64) (string, error) {
+	return buildToken(userID, refreshTokenType, refreshTokenExp)
+}
+
+func buildToken(userID uint64, tokenType string, exp time.Duration) (string, error) {
 	// создаём новый токен с алгоритмом подписи HS256 и утверждениями — Claims
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			// когда создан токен
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenExp)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(exp)),
+			// время выдачи, по нему отзываются сессии удаляемой учетной записи
+			IssuedAt: jwt.NewNumericDate(time.Now()),
 		},
 		// собственное утверждение
 		UserID: userID,
+		Type:   tokenType,
 	})
 
 	// создаём строку токена
@@ -42,6 +60,43 @@ func BuildJWTString(userID uint64) (string, error) {
 
 // GetUserID получение ИД пользвателя из токена
 func GetUserID(tokenString string) (uint64, error) {
+	userID, _, err := GetSession(tokenString)
+
+	return userID, err
+}
+
+// GetRefreshUserID получение ИД пользвателя из токена обновления
+func GetRefreshUserID(tokenString string) (uint64, error) {
+	userID, _, err := GetRefreshSession(tokenString)
+
+	return userID, err
+}
+
+// GetSession получение ИД пользователя и времени выдачи из токена доступа, у токенов без времени выдачи оно нулевое
+func GetSession(tokenString string) (uint64, time.Time, error) {
+	return getSession(tokenString, "")
+}
+
+// GetRefreshSession получение ИД пользователя и времени выдачи из токена обновления
+func GetRefreshSession(tokenString string) (uint64, time.Time, error) {
+	return getSession(tokenString, refreshTokenType)
+}
+
+func getSession(tokenString, tokenType string) (uint64, time.Time, error) {
+	c, err := parseToken(tokenString)
+	if err != nil || c == nil || c.Type != tokenType {
+		return 0, time.Time{}, err
+	}
+
+	if c.IssuedAt == nil {
+		return c.UserID, time.Time{}, nil
+	}
+
+	return c.UserID, c.IssuedAt.Time, nil
+}
+
+// parseToken разбор и проверка подписи токена, nil - если токен недействителен
+func parseToken(tokenString string) (*claims, error) {
 	claims := &claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims,
 		func(t *jwt.Token) (interface{}, error) {
@@ -52,15 +107,15 @@ func GetUserID(tokenString string) (uint64, error) {
 		})
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenInvalidClaims) {
-			return 0, nil
+			return nil, nil
 		}
 		internal.Logger.Infow("error in parse token", "err", err)
-		return 0, err
+		return nil, err
 	}
 
 	if !token.Valid {
-		return 0, nil
+		return nil, nil
 	}
 
-	return claims.UserID, nil
+	return claims, nil
 }
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"gophkeeper/internal"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuildJWTString(t *testing.T) {
@@ -58,3 +60,56 @@ func TestGetUserID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRefreshUserID(t *testing.T) {
+	var id uint64 = 3333
+	internal.InitLogger()
+
+	refresh, err := BuildRefreshToken(id)
+	assert.NoError(t, err)
+
+	access, err := BuildJWTString(id)
+	assert.NoError(t, err)
+
+	got, err := GetRefreshUserID(refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, id, got)
+
+	// токены не взаимозаменяемы
+	got, err = GetRefreshUserID(access)
+	assert.NoError(t, err)
+	assert.Zero(t, got)
+
+	got, err = GetUserID(refresh)
+	assert.NoError(t, err)
+	assert.Zero(t, got)
+}
+
+func TestGetSession(t *testing.T) {
+	var id uint64 = 3333
+	internal.InitLogger()
+
+	before := time.Now().Truncate(time.Second)
+
+	access, err := BuildJWTString(id)
+	require.NoError(t, err)
+
+	refresh, err := BuildRefreshToken(id)
+	require.NoError(t, err)
+
+	got, issuedAt, err := GetSession(access)
+	require.NoError(t, err)
+	assert.Equal(t, id, got)
+	assert.False(t, issuedAt.Before(before))
+	assert.False(t, issuedAt.After(time.Now()))
+
+	got, issuedAt, err = GetRefreshSession(refresh)
+	require.NoError(t, err)
+	assert.Equal(t, id, got)
+	assert.False(t, issuedAt.Before(before))
+
+	got, issuedAt, err = GetSession(refresh)
+	assert.NoError(t, err)
+	assert.Zero(t, got)
+	assert.True(t, issuedAt.IsZero())
+}
@@ -0,0 +1,388 @@
+// Package backup резервное копирование сервера: согласованный снимок базы данных и файлы пользователей в одном архиве tar.gz
+// в архиве пути файлов хранятся относительно каталога файлов сервера, при восстановлении они переписываются на новый каталог
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format идентификатор формата в манифесте
+const Format = "gophkeeper-server-backup"
+
+// Version версия формата, архивы более новой версии не восстанавливаются
+const Version = 1
+
+// Имена в архиве
+const (
+	ManifestName = "manifest.json"
+	DatabaseDir  = "db"
+	FilesDir     = "files"
+)
+
+var (
+	ErrFormat   = errors.New("not a gophkeeper server backup")
+	ErrVersion  = errors.New("unsupported server backup version")
+	ErrChecksum = errors.New("backup checksum mismatch")
+	ErrStorage  = errors.New("backup was made from other storage type")
+	ErrNotEmpty = errors.New("target storage is not empty")
+	ErrSchema   = errors.New("backup schema is newer than server schema")
+	ErrPath     = errors.New("file path is outside of files directory")
+	ErrChanged  = errors.New("file changed during backup")
+)
+
+// Snapshot результат снимка базы данных
+// Entries - имена созданных в каталоге снимка файлов, Files - пути файлов пользователей относительно каталога файлов,
+// Schema - версии схемы таблиц
+type Snapshot struct {
+	Entries []string
+	Files   []string
+	Schema  map[string]int
+}
+
+// Database база данных сервера
+type Database interface {
+	// Storage тип хранилища, архив восстанавливается только в хранилище того же типа
+	Storage() string
+	// Dump согласованный снимок базы в каталог dir, пути файлов в снимке заменяются на relPath(path)
+	Dump(ctx context.Context, dir string, relPath func(string) (string, error)) (Snapshot, error)
+	// Restore загрузка снимка из dir в пустую базу, пути файлов заменяются на absPath(rel)
+	Restore(ctx context.Context, dir string, schema map[string]int, absPath func(string) string) error
+}
+
+// Entry элемент архива
+type Entry struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest описание архива, первый элемент архива
+// Missing - пути файлов из базы, которых не было в каталоге файлов на момент копирования
+type Manifest struct {
+	Format   string         `json:"format"`
+	Version  int            `json:"version"`
+	Created  time.Time      `json:"created"`
+	Storage  string         `json:"storage"`
+	Schema   map[string]int `json:"schema"`
+	Database []Entry        `json:"database"`
+	Files    []Entry        `json:"files"`
+	Missing  []string       `json:"missing,omitempty"`
+}
+
+// Backup запись архива: снимок базы и файлы пользователей из каталога filesRoot
+func Backup(ctx context.Context, db Database, filesRoot string, w io.Writer) (*Manifest, error) {
+	tmp, err := os.MkdirTemp("", "gophkeeper-backup")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	root := filepath.Clean(filesRoot)
+
+	snap, err := db.Dump(ctx, tmp, func(p string) (string, error) {
+		rel, err := filepath.Rel(root, filepath.Clean(p))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("%w: %s", ErrPath, p)
+		}
+
+		return filepath.ToSlash(rel), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		Format:   Format,
+		Version:  Version,
+		Created:  time.Now().UTC(),
+		Storage:  db.Storage(),
+		Schema:   snap.Schema,
+		Database: []Entry{},
+		Files:    []Entry{},
+	}
+
+	sources := make(map[string]string)
+
+	for _, name := range snap.Entries {
+		e, err := describe(filepath.Join(tmp, name), path.Join(DatabaseDir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m.Database = append(m.Database, e)
+		sources[e.Name] = filepath.Join(tmp, name)
+	}
+
+	for _, rel := range snap.Files {
+		src := filepath.Join(root, filepath.FromSlash(rel))
+
+		e, err := describe(src, path.Join(FilesDir, rel))
+		if errors.Is(err, fs.ErrNotExist) {
+			m.Missing = append(m.Missing, rel)
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		m.Files = append(m.Files, e)
+		sources[e.Name] = src
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	err = tw.WriteHeader(&tar.Header{Name: ManifestName, Mode: 0600, Size: int64(len(manifest)), ModTime: m.Created, Typeflag: tar.TypeReg})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = tw.Write(manifest); err != nil {
+		return nil, err
+	}
+
+	for _, e := range append(m.Database, m.Files...) {
+		if err = writeEntry(tw, e, sources[e.Name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return nil, err
+	}
+
+	return m, gz.Close()
+}
+
+// Restore восстановление архива в пустую базу и каталог filesRoot
+// контрольные суммы всех элементов проверяются до загрузки базы, при ошибке записанные файлы удаляются
+func Restore(ctx context.Context, db Database, filesRoot string, r io.Reader) (m *Manifest, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrFormat
+	}
+
+	tr := tar.NewReader(gz)
+
+	if m, err = readManifest(tr); err != nil {
+		return nil, err
+	}
+
+	if m.Storage != db.Storage() {
+		return nil, fmt.Errorf("%w: %s, server uses %s", ErrStorage, m.Storage, db.Storage())
+	}
+
+	tmp, err := os.MkdirTemp("", "gophkeeper-restore")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	root := filepath.Clean(filesRoot)
+
+	// назначение каждого элемента архива
+	targets := make(map[string]string, len(m.Database)+len(m.Files))
+	expected := make(map[string]Entry, len(m.Database)+len(m.Files))
+
+	for _, e := range m.Database {
+		name, ok := strings.CutPrefix(e.Name, DatabaseDir+"/")
+		if !ok || !safePath(name) {
+			return nil, fmt.Errorf("%w: bad entry %s", ErrFormat, e.Name)
+		}
+
+		targets[e.Name], expected[e.Name] = filepath.Join(tmp, filepath.FromSlash(name)), e
+	}
+
+	for _, e := range m.Files {
+		rel, ok := strings.CutPrefix(e.Name, FilesDir+"/")
+		if !ok || !safePath(rel) {
+			return nil, fmt.Errorf("%w: bad entry %s", ErrFormat, e.Name)
+		}
+
+		targets[e.Name], expected[e.Name] = filepath.Join(root, filepath.FromSlash(rel)), e
+	}
+
+	var written []string
+
+	defer func() {
+		if err != nil {
+			for _, p := range written {
+				os.Remove(p)
+			}
+		}
+	}()
+
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrFormat, err.Error())
+		}
+
+		e, ok := expected[h.Name]
+		if !ok || h.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%w: unexpected entry %s", ErrFormat, h.Name)
+		}
+
+		delete(expected, h.Name)
+
+		// существующие файлы не перезаписываются
+		if err = extract(tr, e, targets[h.Name]); err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(h.Name, FilesDir+"/") {
+			written = append(written, targets[h.Name])
+		}
+	}
+
+	for name := range expected {
+		return nil, fmt.Errorf("%w: %s is missing", ErrChecksum, name)
+	}
+
+	err = db.Restore(ctx, tmp, m.Schema, func(rel string) string {
+		return filepath.Join(root, filepath.FromSlash(rel))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// CheckSchema проверка, что версии схемы таблиц архива не новее текущих
+func CheckSchema(schema, current map[string]int) error {
+	for table, v := range schema {
+		latest, ok := current[table]
+		if !ok || v > latest {
+			return fmt.Errorf("%w: table %s version %d, server %d", ErrSchema, table, v, latest)
+		}
+	}
+
+	return nil
+}
+
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	h, err := tr.Next()
+	if err != nil || h.Name != ManifestName {
+		return nil, ErrFormat
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(tr, 64<<20))
+	if err != nil {
+		return nil, ErrFormat
+	}
+
+	var m Manifest
+	if err = json.Unmarshal(raw, &m); err != nil || m.Format != Format {
+		return nil, ErrFormat
+	}
+
+	if m.Version < 1 || m.Version > Version {
+		return nil, fmt.Errorf("%w %d, supported %d", ErrVersion, m.Version, Version)
+	}
+
+	return &m, nil
+}
+
+// describe размер и контрольная сумма файла
+func describe(src, name string) (Entry, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return Entry{Name: name, Size: size, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// writeEntry копирование файла в архив, файл должен совпадать с описанием в манифесте
+func writeEntry(tw *tar.Writer, e Entry, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = tw.WriteHeader(&tar.Header{Name: e.Name, Mode: 0600, Size: e.Size, ModTime: time.Now().UTC(), Typeflag: tar.TypeReg})
+	if err != nil {
+		return err
+	}
+
+	h := sha256.New()
+
+	n, err := io.Copy(io.MultiWriter(tw, h), io.LimitReader(f, e.Size))
+	if err != nil {
+		return err
+	}
+
+	if n != e.Size || hex.EncodeToString(h.Sum(nil)) != e.SHA256 {
+		return fmt.Errorf("%w: %s", ErrChanged, src)
+	}
+
+	return nil
+}
+
+// extract запись элемента архива с проверкой размера и контрольной суммы
+func extract(r io.Reader, e Entry, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	h := sha256.New()
+
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(r, e.Size+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil && (n != e.Size || hex.EncodeToString(h.Sum(nil)) != e.SHA256) {
+		err = fmt.Errorf("%w: %s", ErrChecksum, e.Name)
+	}
+
+	if err != nil {
+		os.Remove(target)
+	}
+
+	return err
+}
+
+// safePath относительный путь без выхода за пределы каталога
+func safePath(p string) bool {
+	return p != "" && !path.IsAbs(p) && path.Clean(p) == p && p != ".." && !strings.HasPrefix(p, "../")
+}
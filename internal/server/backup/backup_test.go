@@ -0,0 +1,242 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDB база из одного файла dump.txt, в котором через перевод строки перечислены пути файлов
+type testDB struct {
+	storage string
+	paths   []string
+	// restored пути после восстановления
+	restored []string
+}
+
+func (d *testDB) Storage() string {
+	return d.storage
+}
+
+func (d *testDB) Dump(_ context.Context, dir string, relPath func(string) (string, error)) (Snapshot, error) {
+	snap := Snapshot{Entries: []string{"dump.txt"}, Schema: map[string]int{"data": 1}}
+
+	for _, p := range d.paths {
+		rel, err := relPath(p)
+		if err != nil {
+			return snap, err
+		}
+
+		snap.Files = append(snap.Files, rel)
+	}
+
+	return snap, os.WriteFile(filepath.Join(dir, "dump.txt"), []byte(strings.Join(snap.Files, "\n")), 0600)
+}
+
+func (d *testDB) Restore(_ context.Context, dir string, schema map[string]int, absPath func(string) string) error {
+	if err := CheckSchema(schema, map[string]int{"data": 1}); err != nil {
+		return err
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "dump.txt"))
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range strings.Split(string(raw), "\n") {
+		d.restored = append(d.restored, absPath(rel))
+	}
+
+	return nil
+}
+
+func testArchive(t *testing.T) (*testDB, []byte) {
+	root := t.TempDir()
+	for name, content := range map[string]string{"1/1/a": "first", "1/2/b": "second"} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0600))
+	}
+
+	db := &testDB{storage: "test", paths: []string{filepath.Join(root, "1", "1", "a"), filepath.Join(root, "1", "2", "b")}}
+
+	var buf bytes.Buffer
+	_, err := Backup(context.Background(), db, root, &buf)
+	require.NoError(t, err)
+
+	return db, buf.Bytes()
+}
+
+func TestBackupRestore(t *testing.T) {
+	_, archive := testArchive(t)
+
+	root := t.TempDir()
+	db := &testDB{storage: "test"}
+
+	m, err := Restore(context.Background(), db, root, bytes.NewReader(archive))
+	require.NoError(t, err)
+	assert.Equal(t, Format, m.Format)
+	assert.Equal(t, []string{filepath.Join(root, "1", "1", "a"), filepath.Join(root, "1", "2", "b")}, db.restored)
+
+	content, err := os.ReadFile(filepath.Join(root, "1", "2", "b"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+}
+
+func TestBackup_PathOutsideRoot(t *testing.T) {
+	db := &testDB{storage: "test", paths: []string{"/etc/passwd"}}
+
+	_, err := Backup(context.Background(), db, t.TempDir(), io.Discard)
+	assert.ErrorIs(t, err, ErrPath)
+}
+
+func TestRestore_Damaged(t *testing.T) {
+	_, archive := testArchive(t)
+
+	tests := []struct {
+		name    string
+		storage string
+		change  func(name string, content []byte) (string, []byte)
+		wantErr error
+	}{
+		{
+			name:    "other storage",
+			storage: "other",
+			wantErr: ErrStorage,
+		},
+		{
+			name: "changed file",
+			change: func(name string, content []byte) (string, []byte) {
+				if name == "files/1/2/b" {
+					return name, []byte("SECOND")
+				}
+
+				return name, content
+			},
+			wantErr: ErrChecksum,
+		},
+		{
+			name: "missing file",
+			change: func(name string, content []byte) (string, []byte) {
+				if name == "files/1/1/a" {
+					return "", nil
+				}
+
+				return name, content
+			},
+			wantErr: ErrChecksum,
+		},
+		{
+			name: "extra entry",
+			change: func(name string, content []byte) (string, []byte) {
+				if name == "files/1/1/a" {
+					return "files/1/1/c", content
+				}
+
+				return name, content
+			},
+			wantErr: ErrFormat,
+		},
+		{
+			name:    "newer version",
+			change:  changeManifest(t, func(m *Manifest) { m.Version = Version + 1 }),
+			wantErr: ErrVersion,
+		},
+		{
+			name:    "newer schema",
+			change:  changeManifest(t, func(m *Manifest) { m.Schema["data"] = 2 }),
+			wantErr: ErrSchema,
+		},
+		{
+			name:    "path traversal",
+			change:  changeManifest(t, func(m *Manifest) { m.Files[0].Name = "files/../../a" }),
+			wantErr: ErrFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			damaged := archive
+			if tt.change != nil {
+				damaged = rewrite(t, archive, tt.change)
+			}
+
+			storage := "test"
+			if tt.storage != "" {
+				storage = tt.storage
+			}
+
+			root := t.TempDir()
+
+			_, err := Restore(context.Background(), &testDB{storage: storage}, root, bytes.NewReader(damaged))
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			// после ошибки в каталоге файлов ничего не остается
+			files, err := filepath.Glob(filepath.Join(root, "*", "*", "*"))
+			require.NoError(t, err)
+			assert.Empty(t, files)
+		})
+	}
+}
+
+func changeManifest(t *testing.T, change func(m *Manifest)) func(string, []byte) (string, []byte) {
+	return func(name string, content []byte) (string, []byte) {
+		if name != ManifestName {
+			return name, content
+		}
+
+		var m Manifest
+		require.NoError(t, json.Unmarshal(content, &m))
+		change(&m)
+
+		content, err := json.Marshal(m)
+		require.NoError(t, err)
+
+		return name, content
+	}
+}
+
+// rewrite копия архива с измененными элементами, пустое имя удаляет элемент
+func rewrite(t *testing.T, archive []byte, change func(name string, content []byte) (string, []byte)) []byte {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+
+		name, content := change(h.Name, content)
+		if name == "" {
+			continue
+		}
+
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err = tw.Write(content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
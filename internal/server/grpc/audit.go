@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	pb "gophkeeper/proto"
+	"gophkeeper/server/audit"
+	domain2 "gophkeeper/server/domain"
+	"time"
+)
+
+// AuditServer просмотр журнала аудита пользователем
+type AuditServer struct {
+	pb.UnimplementedAuditServiceServer
+	Service *audit.Service
+}
+
+func NewAuditServer(s *audit.Service) *AuditServer {
+	return &AuditServer{
+		Service: s,
+	}
+}
+
+// ListAuditEvents страница журнала аудита текущего пользователя по фильтру
+func (s *AuditServer) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	filter := domain2.AuditFilter{
+		DataID:   req.GetDataId(),
+		Outcome:  domain2.AuditOutcome(req.GetOutcome()),
+		BeforeID: req.GetBeforeId(),
+		Limit:    int(req.GetLimit()),
+	}
+
+	for _, a := range req.GetActions() {
+		filter.Actions = append(filter.Actions, domain2.AuditAction(a))
+	}
+
+	if req.GetSince() > 0 {
+		filter.Since = time.Unix(req.GetSince(), 0)
+	}
+
+	if req.GetUntil() > 0 {
+		filter.Until = time.Unix(req.GetUntil(), 0)
+	}
+
+	list, err := s.Service.List(ctx, uid, filter)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	res := &pb.ListAuditEventsResponse{Events: make([]*pb.AuditEvent, 0, len(list))}
+	for _, e := range list {
+		res.Events = append(res.Events, &pb.AuditEvent{
+			Id:        e.ID,
+			Uid:       e.UID,
+			ActorUid:  e.ActorUID,
+			Actor:     e.Actor,
+			Ip:        e.IP,
+			Action:    pb.AuditAction(e.Action),
+			DataId:    e.DataID,
+			Outcome:   pb.AuditOutcome(e.Outcome),
+			CreatedAt: e.CreatedAt.Unix(),
+		})
+	}
+
+	return res, nil
+}
@@ -8,6 +8,7 @@ import (
 	"gophkeeper/server/data"
 	domain2 "gophkeeper/server/domain"
 	file3 "gophkeeper/server/file"
+	"gophkeeper/server/folder"
 	"gophkeeper/server/user"
 	"io"
 	"os"
@@ -22,14 +23,16 @@ type DataServer struct {
 	pb.UnimplementedDataServiceServer
 	Service       data.Service
 	FileService   file3.Service
+	FolderService folder.Service
 	filesSavePath string
 }
 
-func NewDataServer(s *data.Service, filesSavePath string, f *file3.Service) *DataServer {
+func NewDataServer(s *data.Service, filesSavePath string, f *file3.Service, fo *folder.Service) *DataServer {
 	return &DataServer{
 		Service:       *s,
 		filesSavePath: filesSavePath,
 		FileService:   *f,
+		FolderService: *fo,
 	}
 }
 
@@ -40,6 +43,12 @@ func (s *DataServer) SaveData(ctx context.Context, req *pb.SaveDataRequest) (*pb
 		return nil, getError(err)
 	}
 
+	if ur.FolderID != nil {
+		if _, err := s.FolderService.Get(ctx, *ur.FolderID, ur.UID); err != nil {
+			return nil, getError(err)
+		}
+	}
+
 	err := s.Service.UpsertData(ctx, ur.Data)
 	if err != nil {
 		return nil, getError(err)
@@ -110,23 +119,88 @@ func (s *DataServer) DeleteData(ctx context.Context, req *pb.DeleteDataRequest)
 	return nil, nil
 }
 
-// GetDataList получение списка данных
-func (s *DataServer) GetDataList(ctx context.Context, empty *emptypb.Empty) (*pb.DataListResponse, error) {
+// ShareData предоставление доступа к записи другому пользователю
+func (s *DataServer) ShareData(ctx context.Context, req *pb.ShareDataRequest) (*emptypb.Empty, error) {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return nil, getError(domain2.ErrUserIDAbsent)
+	}
+
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		internal.Logger.Errorw("share request validation error", "err", err)
+		return nil, getError(domain2.ErrBadData)
+	}
+
+	share := domain2.DataShare{
+		DataID:     req.GetDataId(),
+		RecordKey:  req.GetRecordKey(),
+		Permission: domain2.SharePermission(req.GetPermission()),
+	}
+
+	if err = s.Service.Share(ctx, ctxUID, req.GetLogin(), share); err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// UnshareData отзыв доступа к записи у другого пользователя
+func (s *DataServer) UnshareData(ctx context.Context, req *pb.UnshareDataRequest) (*emptypb.Empty, error) {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return nil, getError(domain2.ErrUserIDAbsent)
+	}
+
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		internal.Logger.Errorw("unshare request validation error", "err", err)
+		return nil, getError(domain2.ErrBadData)
+	}
+
+	if err = s.Service.Unshare(ctx, ctxUID, req.GetLogin(), req.GetDataId()); err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetDataList получение страницы списка данных с учетом фильтра
+func (s *DataServer) GetDataList(ctx context.Context, req *pb.GetDataListRequest) (*pb.DataListResponse, error) {
 	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
 	if ctxUID == 0 {
 		return nil, domain2.ErrUserIDAbsent
 	}
 
-	list, err := s.Service.GetList(ctx, ctxUID)
+	lr := &dataListRequest{}
+	if err := lr.Bind(req); err != nil {
+		return nil, getError(err)
+	}
+
+	list, next, err := s.Service.GetList(ctx, ctxUID, lr.DataListFilter)
 	if err != nil {
 		return nil, getError(err)
 	}
 
-	if len(list) == 0 {
-		return &pb.DataListResponse{}, nil
+	resp := getDataListResponse(list)
+
+	if next != nil {
+		resp.NextPageToken, err = data.EncodePageToken(lr.Sort, *next)
+		if err != nil {
+			internal.Logger.Errorw("error while encoding page token", "err", err)
+			return nil, getError(domain2.ErrInternalServerError)
+		}
 	}
 
-	return getDataListResponse(list), nil
+	return resp, nil
 }
 
 // UploadFile загрузка файла
@@ -203,7 +277,7 @@ func (s *DataServer) DownloadFile(req *pb.DownloadFileRequest, stream pb.DataSer
 		return getError(err)
 	}
 
-	dbData, err := s.Service.Get(stream.Context(), dr.DataID, dr.UID)
+	dbData, err := s.Service.Download(stream.Context(), dr.DataID, dr.UID)
 	if err != nil {
 		return getError(err)
 	}
@@ -315,6 +389,8 @@ func (d *dataRequest) Bind(ctx context.Context, req *pb.SaveDataRequest) error {
 	text := reqData.GetText()
 	meta := reqData.GetMeta()
 	cardNum := reqData.GetCardNum()
+	cardExp := reqData.GetCardExp()
+	otp := reqData.GetOtp()
 
 	d.Data.ID = reqData.GetId()
 	d.Version = reqData.GetVersion()
@@ -324,8 +400,23 @@ func (d *dataRequest) Bind(ctx context.Context, req *pb.SaveDataRequest) error {
 	d.Text = &text
 	d.Meta = &meta
 	d.CardNum = &cardNum
+	d.CardExp = &cardExp
+	d.Otp = &otp
+	d.Tags = reqData.GetTags()
 	d.UID = ctxUID
 
+	if nameHash := reqData.GetNameHash(); nameHash != "" {
+		d.NameHash = &nameHash
+	}
+
+	if recordKey := reqData.GetRecordKey(); recordKey != "" {
+		d.RecordKey = &recordKey
+	}
+
+	if folderID := reqData.GetFolderId(); folderID != 0 {
+		d.FolderID = &folderID
+	}
+
 	return nil
 }
 
@@ -341,6 +432,7 @@ func getDataResponse(data domain2.Data, file *domain2.File) *pb.GetDataResponse
 		Name:     data.Name,
 		FileName: fileName,
 		Version:  data.Version,
+		Tags:     data.Tags,
 	}
 
 	if data.Login != nil {
@@ -359,6 +451,14 @@ func getDataResponse(data domain2.Data, file *domain2.File) *pb.GetDataResponse
 		respData.CardNum = *data.CardNum
 	}
 
+	if data.CardExp != nil {
+		respData.CardExp = *data.CardExp
+	}
+
+	if data.Otp != nil {
+		respData.Otp = *data.Otp
+	}
+
 	if data.Meta != nil {
 		respData.Meta = *data.Meta
 	}
@@ -367,17 +467,44 @@ func getDataResponse(data domain2.Data, file *domain2.File) *pb.GetDataResponse
 		respData.FileID = *data.FileID
 	}
 
+	if data.FolderID != nil {
+		respData.FolderId = *data.FolderID
+	}
+
+	respData.RecordKey, respData.SharedBy, respData.Permission = recordKey(data.RecordKey, data.Share)
+
 	return &pb.GetDataResponse{Data: respData}
 }
 
+// recordKey ключ записи для пользователя: свой для владельца или зашифрованный для получателя доступа
+func recordKey(ownerKey *string, share *domain2.DataShare) (string, string, pb.SharePermission) {
+	if share != nil {
+		return share.RecordKey, share.Owner, pb.SharePermission(share.Permission)
+	}
+
+	if ownerKey != nil {
+		return *ownerKey, "", pb.SharePermission_SHARE_PERMISSION_NONE
+	}
+
+	return "", "", pb.SharePermission_SHARE_PERMISSION_NONE
+}
+
 func getDataListResponse(data []domain2.DataName) *pb.DataListResponse {
 	dataList := make([]*pb.DataList, len(data))
 
 	for i, d := range data {
 		dataList[i] = &pb.DataList{
-			Name: d.Name,
-			Id:   d.ID,
+			Name:    d.Name,
+			Id:      d.ID,
+			Tags:    d.Tags,
+			Version: d.Version,
 		}
+
+		if d.FolderID != nil {
+			dataList[i].FolderId = *d.FolderID
+		}
+
+		dataList[i].RecordKey, dataList[i].SharedBy, dataList[i].Permission = recordKey(d.RecordKey, d.Share)
 	}
 
 	return &pb.DataListResponse{
@@ -385,6 +512,39 @@ func getDataListResponse(data []domain2.DataName) *pb.DataListResponse {
 	}
 }
 
+type dataListRequest struct {
+	domain2.DataListFilter
+}
+
+// Bind отображение параметров запроса списка данных в фильтр сервера
+func (l *dataListRequest) Bind(req *pb.GetDataListRequest) error {
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		internal.Logger.Errorw("data list request validation error", "err", err)
+		return domain2.ErrBadData
+	}
+
+	l.NamePrefix = req.GetNamePrefix()
+	l.NameContains = req.GetNameContains()
+	l.Type = domain2.DataType(req.GetType())
+	l.Sort = domain2.DataSort(req.GetSort())
+	l.Limit = int(req.GetPageSize())
+	l.FolderID = req.GetFolderId()
+	l.Tags = data.NormalizeTags(req.GetTags())
+	l.Shared = req.GetShared()
+
+	l.After, err = data.DecodePageToken(req.GetPageToken(), l.Sort)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 type DownloadFileRequest struct {
 	DataID uint64
 	UID    uint64
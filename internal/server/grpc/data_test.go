@@ -6,12 +6,14 @@ import (
 	"gophkeeper/internal"
 	"gophkeeper/internal/server/auth"
 	"gophkeeper/internal/server/grpc/interceptors"
+	"gophkeeper/internal/server/repository/memory"
 	"gophkeeper/internal/server/repository/pgsql"
 	"gophkeeper/internal/test"
 	pb "gophkeeper/proto"
 	"gophkeeper/server/data"
 	domain2 "gophkeeper/server/domain"
 	"gophkeeper/server/file"
+	"gophkeeper/server/folder"
 	user2 "gophkeeper/server/user"
 	"io"
 	"net"
@@ -27,7 +29,6 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var lis *bufconn.Listener
@@ -41,6 +42,8 @@ const (
 )
 
 func TestDataServer_SaveData(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	var fileRepo *pgsql.FileRepository
 	ctx := context.Background()
 	internal.InitLogger()
@@ -69,7 +72,7 @@ func TestDataServer_SaveData(t *testing.T) {
 	repo, err := pgsql.NewDataRepository(ctx, pool, testDataTable, testFileTable, testUsersTable)
 	assert.NoError(t, err)
 
-	service := data.NewService(repo, fileRepo)
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
 
 	var versionFirst uint64 = 1
 	login := "test"
@@ -85,7 +88,7 @@ func TestDataServer_SaveData(t *testing.T) {
 	err = repo.Insert(ctx, testData)
 	assert.NoError(t, err)
 
-	server := NewDataServer(service, "/", file.NewService(fileRepo))
+	server := NewDataServer(service, "/", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	tests := []struct {
 		name    string
@@ -148,6 +151,8 @@ func TestDataServer_SaveData(t *testing.T) {
 }
 
 func TestDataServer_UploadFile(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	userTable := "p_users"
 	fileTable := "p_files"
 	dataTable := "p_data"
@@ -185,8 +190,8 @@ func TestDataServer_UploadFile(t *testing.T) {
 	err = repo.Insert(ctx, &dData)
 	assert.NoError(t, err)
 
-	service := data.NewService(repo, fileRepo)
-	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo))
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth), grpc.StreamInterceptor(interceptors.StreamAuth))
@@ -265,6 +270,8 @@ func bufDialer(context.Context, string) (net.Conn, error) {
 }
 
 func TestDataServer_GetDataList(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	userTable := "p_users"
 	fileTable := "p_files"
 	dataTable := "p_data"
@@ -334,8 +341,8 @@ func TestDataServer_GetDataList(t *testing.T) {
 	err = repo.Insert(ctx, &dData3)
 	assert.NoError(t, err)
 
-	service := data.NewService(repo, fileRepo)
-	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo))
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	tests := []struct {
 		name      string
@@ -357,7 +364,7 @@ func TestDataServer_GetDataList(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var got *pb.DataListResponse
 			respCtx := context.WithValue(ctx, user2.ContextUserIDKey{}, tt.uid)
-			got, err = server.GetDataList(respCtx, &emptypb.Empty{})
+			got, err = server.GetDataList(respCtx, &pb.GetDataListRequest{})
 			assert.NoError(t, err)
 			assert.Len(t, got.DataList, tt.wantCount)
 		})
@@ -365,6 +372,8 @@ func TestDataServer_GetDataList(t *testing.T) {
 }
 
 func TestDataServer_GetData(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	userTable := "p_users"
 	fileTable := "p_files"
 	dataTable := "p_data"
@@ -412,8 +421,8 @@ func TestDataServer_GetData(t *testing.T) {
 	err = repo.Insert(ctx, &dData)
 	assert.NoError(t, err)
 
-	service := data.NewService(repo, fileRepo)
-	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo))
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	tests := []struct {
 		name        string
@@ -453,6 +462,8 @@ func TestDataServer_GetData(t *testing.T) {
 }
 
 func TestDataServer_DeleteData(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	userTable := "p_users"
 	fileTable := "p_files"
 	dataTable := "p_data"
@@ -504,8 +515,8 @@ func TestDataServer_DeleteData(t *testing.T) {
 	err = repo.Insert(ctx, &dData)
 	assert.NoError(t, err)
 
-	service := data.NewService(repo, fileRepo)
-	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo))
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	respCtx := context.WithValue(ctx, user2.ContextUserIDKey{}, userID)
 	_, err = server.DeleteData(respCtx, &pb.DeleteDataRequest{Id: dData.ID})
@@ -520,6 +531,8 @@ func TestDataServer_DeleteData(t *testing.T) {
 }
 
 func TestDataServer_DownloadFile(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	userTable := "k_users"
 	fileTable := "k_files"
 	dataTable := "k_data"
@@ -592,8 +605,8 @@ func TestDataServer_DownloadFile(t *testing.T) {
 	err = repo.Insert(ctx, &dDataWithWrongFilePath)
 	assert.NoError(t, err)
 
-	service := data.NewService(repo, fileRepo)
-	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo))
+	service := data.NewService(repo, fileRepo, memory.NewUserRepository())
+	server := NewDataServer(service, "/tmp/uploaded", file.NewService(fileRepo), folder.NewService(memory.NewFolderRepository(), repo))
 
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer(grpc.UnaryInterceptor(interceptors.Auth), grpc.StreamInterceptor(interceptors.StreamAuth))
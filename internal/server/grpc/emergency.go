@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	pb "gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/emergency"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// EmergencyServer управление экстренным доступом доверенных лиц
+// чтение хранилища владельца идет через DataServer с метаданными emergency
+type EmergencyServer struct {
+	pb.UnimplementedEmergencyServiceServer
+	Service *emergency.Service
+}
+
+func NewEmergencyServer(s *emergency.Service) *EmergencyServer {
+	return &EmergencyServer{
+		Service: s,
+	}
+}
+
+// CreateGrant назначение доверенного лица
+func (e *EmergencyServer) CreateGrant(ctx context.Context, req *pb.CreateGrantRequest) (*pb.CreateGrantResponse, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	grant, err := e.Service.Create(ctx, uid, req.GetLogin(), time.Duration(req.GetWaitSeconds())*time.Second, req.GetVaultKey())
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &pb.CreateGrantResponse{Id: grant.ID}, nil
+}
+
+// GetGrants экстренные доступы пользователя как владельца и как доверенного лица
+func (e *EmergencyServer) GetGrants(ctx context.Context, req *emptypb.Empty) (*pb.GrantListResponse, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	list, err := e.Service.GetList(ctx, uid)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	res := &pb.GrantListResponse{Grants: make([]*pb.EmergencyGrant, 0, len(list))}
+	for _, g := range list {
+		res.Grants = append(res.Grants, grantToProto(g))
+	}
+
+	return res, nil
+}
+
+// AcceptGrant согласие доверенного лица
+func (e *EmergencyServer) AcceptGrant(ctx context.Context, req *pb.GrantRequest) (*emptypb.Empty, error) {
+	return e.change(ctx, req, e.Service.Accept)
+}
+
+// RequestAccess запрос доступа к хранилищу владельца
+func (e *EmergencyServer) RequestAccess(ctx context.Context, req *pb.GrantRequest) (*emptypb.Empty, error) {
+	return e.change(ctx, req, e.Service.Request)
+}
+
+// ApproveAccess одобрение запроса владельцем
+func (e *EmergencyServer) ApproveAccess(ctx context.Context, req *pb.GrantRequest) (*emptypb.Empty, error) {
+	return e.change(ctx, req, e.Service.Approve)
+}
+
+// RejectAccess отказ владельца в доступе
+func (e *EmergencyServer) RejectAccess(ctx context.Context, req *pb.GrantRequest) (*emptypb.Empty, error) {
+	return e.change(ctx, req, e.Service.Reject)
+}
+
+// RevokeGrant удаление экстренного доступа
+func (e *EmergencyServer) RevokeGrant(ctx context.Context, req *pb.GrantRequest) (*emptypb.Empty, error) {
+	return e.change(ctx, req, e.Service.Revoke)
+}
+
+func (e *EmergencyServer) change(ctx context.Context, req *pb.GrantRequest, f func(ctx context.Context, uid, id uint64) error) (*emptypb.Empty, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	if err = f(ctx, uid, req.GetId()); err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func grantToProto(g domain2.EmergencyGrant) *pb.EmergencyGrant {
+	res := &pb.EmergencyGrant{
+		Id:          g.ID,
+		Grantor:     g.Grantor,
+		Grantee:     g.Grantee,
+		State:       pb.EmergencyState(g.State),
+		WaitSeconds: int64(g.Wait / time.Second),
+		VaultKey:    g.VaultKey,
+	}
+
+	if g.RequestedAt != nil {
+		res.RequestedAt = g.RequestedAt.Unix()
+	}
+
+	return res
+}
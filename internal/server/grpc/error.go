@@ -12,16 +12,30 @@ func getError(err error) error {
 	switch {
 	case errors.Is(err, domain.ErrUserIDAbsent):
 		return status.Error(codes.Unauthenticated, "user id absent")
+	case errors.Is(err, domain.ErrBadRefreshToken), errors.Is(err, domain.ErrAccountDeleted), errors.Is(err, domain.ErrSessionRevoked):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, domain.ErrWrongPassword):
+		return status.Error(codes.PermissionDenied, err.Error())
 	case
 		errors.Is(err, domain.ErrBadData),
 		errors.Is(err, domain.ErrDataVersionAbsent),
 		errors.Is(err, domain.ErrDataNameNotUniq),
-		errors.Is(err, domain.ErrBadFileID):
+		errors.Is(err, domain.ErrBadFileID),
+		errors.Is(err, domain.ErrBadPageToken),
+		errors.Is(err, domain.ErrFolderCycle),
+		errors.Is(err, domain.ErrShareSelf),
+		errors.Is(err, domain.ErrNoRecordKey),
+		errors.Is(err, domain.ErrOrgRotation),
+		errors.Is(err, domain.ErrGrantSelf):
 		return status.Error(codes.InvalidArgument, err.Error())
 	case
 		errors.Is(err, domain.ErrUserNotFound),
 		errors.Is(err, domain.ErrDataNotFound),
-		errors.Is(err, domain.ErrFileNotFound):
+		errors.Is(err, domain.ErrFileNotFound),
+		errors.Is(err, domain.ErrFolderNotFound),
+		errors.Is(err, domain.ErrOrgNotFound),
+		errors.Is(err, domain.ErrMemberNotFound),
+		errors.Is(err, domain.ErrGrantNotFound):
 		return status.Error(codes.NotFound, err.Error())
 	case
 		errors.Is(err, domain.ErrInternalServerError),
@@ -29,10 +43,18 @@ func getError(err error) error {
 		errors.Is(err, domain.ErrDataUpdate),
 		errors.Is(err, domain.ErrCheckDataName):
 		return status.Error(codes.Internal, err.Error())
-	case errors.Is(err, domain.ErrLoginExist):
+	case errors.Is(err, domain.ErrLoginExist), errors.Is(err, domain.ErrKeysExist), errors.Is(err, domain.ErrMemberExists),
+		errors.Is(err, domain.ErrGrantExists):
 		return status.Error(codes.AlreadyExists, err.Error())
-	case errors.Is(err, domain.ErrDataOutdated):
+	case errors.Is(err, domain.ErrDataOutdated), errors.Is(err, domain.ErrNoPublicKey), errors.Is(err, domain.ErrLastOwner),
+		errors.Is(err, domain.ErrOrgMember), errors.Is(err, domain.ErrGrantState), errors.Is(err, domain.ErrSecretLinksDisabled), errors.Is(err, domain.ErrAccountDeleting),
+		errors.Is(err, domain.ErrAccountActive):
 		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrDataReadOnly), errors.Is(err, domain.ErrOrgForbidden), errors.Is(err, domain.ErrVaultMethod),
+		errors.Is(err, domain.ErrGrantForbidden), errors.Is(err, domain.ErrEmergencyMethod):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrOrgKeyVersion):
+		return status.Error(codes.Aborted, err.Error())
 	default:
 		return status.Error(codes.Internal, err.Error())
 	}
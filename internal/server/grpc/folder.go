@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"gophkeeper/internal"
+	pb "gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/user"
+
+	"github.com/bufbuild/protovalidate-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// SaveFolder добавление или сохранение папки
+func (s *DataServer) SaveFolder(ctx context.Context, req *pb.SaveFolderRequest) (*pb.SaveFolderResponse, error) {
+	fr := &folderRequest{&domain2.Folder{}}
+	if err := fr.Bind(ctx, req); err != nil {
+		return nil, getError(err)
+	}
+
+	if err := s.FolderService.Save(ctx, fr.Folder); err != nil {
+		return nil, getError(err)
+	}
+
+	return &pb.SaveFolderResponse{FolderId: fr.ID}, nil
+}
+
+// GetFolders получение всех папок пользователя
+func (s *DataServer) GetFolders(ctx context.Context, _ *emptypb.Empty) (*pb.GetFoldersResponse, error) {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return nil, getError(domain2.ErrUserIDAbsent)
+	}
+
+	list, err := s.FolderService.GetList(ctx, ctxUID)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	folders := make([]*pb.Folder, len(list))
+	for i, f := range list {
+		folders[i] = &pb.Folder{
+			Id:   f.ID,
+			Name: f.Name,
+		}
+
+		if f.ParentID != nil {
+			folders[i].ParentId = *f.ParentID
+		}
+	}
+
+	return &pb.GetFoldersResponse{Folders: folders}, nil
+}
+
+// DeleteFolder удаление папки вместе с вложенными папками
+func (s *DataServer) DeleteFolder(ctx context.Context, req *pb.DeleteFolderRequest) (*emptypb.Empty, error) {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return nil, getError(domain2.ErrUserIDAbsent)
+	}
+
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		internal.Logger.Errorw("delete folder request validation error", "err", err)
+		return nil, getError(domain2.ErrBadData)
+	}
+
+	if err = s.FolderService.Delete(ctx, req.GetId(), ctxUID); err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+type folderRequest struct {
+	*domain2.Folder
+}
+
+// Bind отображение данных запроса в модель папки сервера
+func (f *folderRequest) Bind(ctx context.Context, req *pb.SaveFolderRequest) error {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return domain2.ErrUserIDAbsent
+	}
+
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		internal.Logger.Errorw("folder validation error", "err", err)
+		return domain2.ErrBadData
+	}
+
+	reqFolder := req.GetFolder()
+
+	f.ID = reqFolder.GetId()
+	f.Name = reqFolder.GetName()
+	f.UID = ctxUID
+
+	if parentID := reqFolder.GetParentId(); parentID != 0 {
+		f.ParentID = &parentID
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	domain2 "gophkeeper/server/domain"
 	"gophkeeper/server/user"
 	"strings"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -59,7 +60,8 @@ func newStreamContextWrapper(ss grpc.ServerStream) StreamContextWrapper {
 
 // Auth получение из запроса авторизационных данных и запись их в контекст
 func Auth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-	if info.FullMethod == proto.UserService_Register_FullMethodName || info.FullMethod == proto.UserService_Login_FullMethodName {
+	if info.FullMethod == proto.UserService_Register_FullMethodName || info.FullMethod == proto.UserService_Login_FullMethodName ||
+		info.FullMethod == proto.UserService_Refresh_FullMethodName || info.FullMethod == proto.UserService_RestoreAccount_FullMethodName {
 		return handler(ctx, req)
 	}
 
@@ -72,9 +74,12 @@ func Auth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, hand
 				return nil, status.Errorf(codes.Unauthenticated, wrongAuthenticatedMeta)
 			}
 
-			var userID uint64
+			var (
+				userID   uint64
+				issuedAt time.Time
+			)
 			token := strings.TrimSpace(strings.Replace(val, domain2.TokenSubstr, "", -1))
-			userID, err = auth.GetUserID(token)
+			userID, issuedAt, err = auth.GetSession(token)
 			if err != nil {
 				return nil, status.Errorf(codes.Internal, domain2.ErrInternalServerError.Error())
 			}
@@ -83,7 +88,7 @@ func Auth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, hand
 				return nil, status.Errorf(codes.Unauthenticated, wrongAuthenticatedMeta)
 			}
 
-			respCtx := context.WithValue(ctx, user.ContextUserIDKey{}, userID)
+			respCtx := context.WithValue(context.WithValue(ctx, user.ContextUserIDKey{}, userID), user.ContextIssuedAtKey{}, issuedAt)
 
 			return handler(respCtx, req)
 		}
@@ -104,9 +109,8 @@ func StreamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerIn
 				return status.Errorf(codes.Unauthenticated, wrongAuthenticatedMeta)
 			}
 
-			var userID uint64
 			token := strings.TrimSpace(strings.Replace(val, domain2.TokenSubstr, "", -1))
-			userID, err := auth.GetUserID(token)
+			userID, issuedAt, err := auth.GetSession(token)
 			if err != nil {
 				return status.Errorf(codes.Internal, domain2.ErrInternalServerError.Error())
 			}
@@ -115,7 +119,7 @@ func StreamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerIn
 				return status.Errorf(codes.Unauthenticated, wrongAuthenticatedMeta)
 			}
 
-			respCtx := context.WithValue(ss.Context(), user.ContextUserIDKey{}, userID)
+			respCtx := context.WithValue(context.WithValue(ss.Context(), user.ContextUserIDKey{}, userID), user.ContextIssuedAtKey{}, issuedAt)
 			sw := newStreamContextWrapper(ss)
 			sw.SetContext(respCtx)
 
@@ -0,0 +1,86 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/user"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const wrongEmergencyMeta = "wrong emergency meta"
+
+// EmergencyResolver определение владельца хранилища по экстренному доступу
+type EmergencyResolver interface {
+	Vault(ctx context.Context, id, uid uint64) (uint64, error)
+}
+
+// Emergency подмена пользователя на владельца хранилища, если в запросе передана метаинформация emergency
+// по экстренному доступу разрешено только чтение, должен вызываться после Auth
+func Emergency(resolver EmergencyResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		emergencyCtx, err := emergencyContext(ctx, resolver, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(emergencyCtx, req)
+	}
+}
+
+// StreamEmergency подмена пользователя на владельца хранилища в потоковых запросах
+func StreamEmergency(resolver EmergencyResolver) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		emergencyCtx, err := emergencyContext(ss.Context(), resolver, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		if emergencyCtx == ss.Context() {
+			return handler(srv, ss)
+		}
+
+		sw := newStreamContextWrapper(ss)
+		sw.SetContext(emergencyCtx)
+
+		return handler(srv, sw)
+	}
+}
+
+func emergencyContext(ctx context.Context, resolver EmergencyResolver, method string) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[domain2.EmergencyMetaKey]) == 0 {
+		return ctx, nil
+	}
+
+	if write, ok := vaultMethods[method]; !ok || write || len(md[domain2.VaultMetaKey]) > 0 {
+		return nil, status.Error(codes.PermissionDenied, domain2.ErrEmergencyMethod.Error())
+	}
+
+	id, err := strconv.ParseUint(md[domain2.EmergencyMetaKey][0], 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, wrongEmergencyMeta)
+	}
+
+	uid, _ := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if uid == 0 {
+		return nil, status.Error(codes.Unauthenticated, authenticatedMetaNotFound)
+	}
+
+	grantorUID, err := resolver.Vault(ctx, id, uid)
+	switch {
+	case err == nil:
+		return context.WithValue(context.WithValue(ctx, user.ContextActorIDKey{}, uid), user.ContextUserIDKey{}, grantorUID), nil
+	case errors.Is(err, domain2.ErrGrantNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain2.ErrGrantForbidden):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return nil, status.Error(codes.Internal, domain2.ErrInternalServerError.Error())
+}
@@ -0,0 +1,131 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/auth"
+	"gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimiter ограничение частоты попыток входа и регистрации
+type RateLimiter interface {
+	Login(ctx context.Context, login, ip string) (time.Duration, error)
+	LoginResult(ctx context.Context, login, ip string, success bool)
+	Register(ctx context.Context, ip string) (time.Duration, error)
+}
+
+// UserLookup поиск логина владельца токена, чтобы попытки подтвердить пароль при удалении учетной записи
+// учитывались вместе с попытками входа в нее
+type UserLookup interface {
+	GetByID(ctx context.Context, id uint64) (domain2.User, error)
+}
+
+// RateLimit ограничение попыток входа по логину и IP клиента и регистраций по IP клиента, должен вызываться до Auth
+// при превышении возвращается codes.ResourceExhausted, число секунд до следующей попытки передается
+// в метаинформации retry-after; неверный логин или пароль учитывается как неудачная попытка.
+// восстановление и удаление учетной записи проверяют пароль и ограничиваются так же, как вход в нее:
+// при удалении логин определяется по токену доступа, иначе украденным токеном можно было бы подбирать пароль
+func RateLimit(limiter RateLimiter, users UserLookup) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		switch info.FullMethod {
+		case proto.UserService_Login_FullMethodName, proto.UserService_RestoreAccount_FullMethodName:
+			r, _ := req.(*proto.RegisterRequest)
+
+			return limitLogin(ctx, limiter, r.GetUser().GetLogin(), req, handler)
+		case proto.UserService_DeleteAccount_FullMethodName:
+			// без действительного токена запрос отклонит Auth
+			login := tokenLogin(ctx, users)
+			if login == "" {
+				return handler(ctx, req)
+			}
+
+			return limitLogin(ctx, limiter, login, req, handler)
+		case proto.UserService_Register_FullMethodName:
+			if wait, err := limiter.Register(ctx, clientIP(ctx)); err != nil {
+				return nil, rateLimited(ctx, wait)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// limitLogin проверка пароля учетной записи login с учетом ограничения попыток
+// неверный логин (NotFound) или пароль при подтверждении (PermissionDenied) считаются неудачной попыткой
+func limitLogin(ctx context.Context, limiter RateLimiter, login string, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+	ip := clientIP(ctx)
+
+	if wait, err := limiter.Login(ctx, login, ip); err != nil {
+		return nil, rateLimited(ctx, wait)
+	}
+
+	resp, err := handler(ctx, req)
+	switch status.Code(err) {
+	case codes.OK:
+		limiter.LoginResult(ctx, login, ip, true)
+	case codes.NotFound, codes.PermissionDenied:
+		limiter.LoginResult(ctx, login, ip, false)
+	}
+
+	return resp, err
+}
+
+// tokenLogin логин владельца токена доступа из метаинформации запроса, пустой - токена нет или он недействителен
+func tokenLogin(ctx context.Context, users UserLookup) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	vals := md.Get(domain2.AuthorizationMetaKey)
+	if len(vals) == 0 || !strings.Contains(vals[0], domain2.TokenSubstr) {
+		return ""
+	}
+
+	uid, err := auth.GetUserID(strings.TrimSpace(strings.Replace(vals[0], domain2.TokenSubstr, "", -1)))
+	if err != nil || uid == 0 {
+		return ""
+	}
+
+	u, err := users.GetByID(ctx, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching user for rate limit", "uid", uid, "err", err)
+		return ""
+	}
+
+	return u.Login
+}
+
+// rateLimited ошибка превышения числа попыток с временем ожидания в метаинформации ответа
+func rateLimited(ctx context.Context, wait time.Duration) error {
+	seconds := int64(math.Ceil(wait.Seconds()))
+	if err := grpc.SetHeader(ctx, metadata.Pairs(domain2.RetryAfterMetaKey, strconv.FormatInt(seconds, 10))); err != nil {
+		internal.Logger.Errorw("error while setting retry-after header", "err", err)
+	}
+
+	return status.Error(codes.ResourceExhausted, fmt.Sprintf("%s, retry in %s", domain2.ErrRateLimited, time.Duration(seconds)*time.Second))
+}
+
+// clientIP адрес клиента gRPC без порта
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
@@ -0,0 +1,140 @@
+package interceptors
+
+import (
+	"context"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/auth"
+	"gophkeeper/internal/server/repository/memory"
+	pb "gophkeeper/proto"
+	"gophkeeper/server/domain"
+	"gophkeeper/server/ratelimit"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testUserServer struct {
+	pb.UnimplementedUserServiceServer
+}
+
+func (u *testUserServer) Login(_ context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	if req.GetUser().GetPassword() != "secret" {
+		return nil, status.Error(codes.NotFound, domain.ErrUserNotFound.Error())
+	}
+
+	return &pb.RegisterResponse{Token: "token"}, nil
+}
+
+func (u *testUserServer) DeleteAccount(_ context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	if req.GetPassword() != "secret" {
+		return nil, status.Error(codes.PermissionDenied, domain.ErrWrongPassword.Error())
+	}
+
+	return &pb.DeleteAccountResponse{}, nil
+}
+
+func (u *testUserServer) Register(context.Context, *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	return &pb.RegisterResponse{Token: "token"}, nil
+}
+
+func TestRateLimit(t *testing.T) {
+	internal.InitLogger()
+
+	lis = bufconn.Listen(bufSize)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(RateLimit(ratelimit.NewService(memory.NewRateLimitRepository()), memory.NewUserRepository()), Auth))
+	pb.RegisterUserServiceServer(s, &testUserServer{})
+	go func() {
+		assert.NoError(t, s.Serve(lis))
+	}()
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough://bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewUserServiceClient(conn)
+	ctx := context.Background()
+
+	login := func(password string) (metadata.MD, error) {
+		var header metadata.MD
+		_, err := client.Login(ctx, &pb.RegisterRequest{User: &pb.User{Login: "alice", Password: password}}, grpc.Header(&header))
+
+		return header, err
+	}
+
+	for i := 0; i < ratelimit.LoginLimit.MaxFailures; i++ {
+		_, err = login("wrong")
+		require.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	// после серии неудач логин заблокирован даже для верного пароля
+	header, err := login("secret")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{strconv.Itoa(int(ratelimit.LoginLimit.LockoutBase.Seconds()))}, header.Get(domain.RetryAfterMetaKey))
+
+	// другой логин с того же адреса не заблокирован
+	_, err = client.Login(ctx, &pb.RegisterRequest{User: &pb.User{Login: "bob", Password: "secret"}})
+	assert.NoError(t, err)
+
+	for i := 0; i < ratelimit.RegisterLimit.Burst; i++ {
+		_, err = client.Register(ctx, &pb.RegisterRequest{User: &pb.User{Login: "carol", Password: "secret"}})
+		require.NoError(t, err)
+	}
+
+	var header2 metadata.MD
+	_, err = client.Register(ctx, &pb.RegisterRequest{User: &pb.User{Login: "carol", Password: "secret"}}, grpc.Header(&header2))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{strconv.Itoa(int(ratelimit.RegisterLimit.Every.Seconds()))}, header2.Get(domain.RetryAfterMetaKey))
+	assert.ErrorContains(t, err, "too many attempts, retry in 20m0s")
+}
+
+func TestRateLimit_DeleteAccount(t *testing.T) {
+	internal.InitLogger()
+	ctx := context.Background()
+
+	users := memory.NewUserRepository()
+	alice, err := users.Store(ctx, domain.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+
+	lis = bufconn.Listen(bufSize)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(RateLimit(ratelimit.NewService(memory.NewRateLimitRepository()), users), Auth))
+	pb.RegisterUserServiceServer(s, &testUserServer{})
+	go func() {
+		assert.NoError(t, s.Serve(lis))
+	}()
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough://bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewUserServiceClient(conn)
+
+	token, err := auth.BuildJWTString(alice)
+	require.NoError(t, err)
+	authCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs(domain.AuthorizationMetaKey, domain.TokenSubstr+" "+token))
+
+	for i := 0; i < ratelimit.LoginLimit.MaxFailures; i++ {
+		_, err = client.DeleteAccount(authCtx, &pb.DeleteAccountRequest{Password: "wrong"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+
+	// подбор пароля по украденному токену блокирует и удаление, и вход в учетную запись
+	_, err = client.DeleteAccount(authCtx, &pb.DeleteAccountRequest{Password: "secret"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = client.Login(ctx, &pb.RegisterRequest{User: &pb.User{Login: "alice", Password: "secret"}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// без токена запрос отклоняется Auth, попытка не учитывается
+	_, err = client.DeleteAccount(ctx, &pb.DeleteAccountRequest{Password: "secret"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
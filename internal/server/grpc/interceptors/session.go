@@ -0,0 +1,62 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/user"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SessionChecker проверка, что учетная запись существует и сессия с токеном, выданным в issuedAt, не отозвана
+type SessionChecker interface {
+	CheckSession(ctx context.Context, uid uint64, issuedAt time.Time) error
+}
+
+// Session отклонение запросов от удаленных и удаляемых учетных записей, должен вызываться после Auth
+// токен подписан сервером и действует до истечения срока, поэтому без проверки им можно было бы пользоваться
+// и после удаления учетной записи
+func Session(checker SessionChecker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkSession(ctx, checker); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamSession отклонение потоковых запросов от удаленных и удаляемых учетных записей
+func StreamSession(checker SessionChecker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkSession(ss.Context(), checker); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func checkSession(ctx context.Context, checker SessionChecker) error {
+	// методы входа и регистрации вызываются без токена
+	uid, _ := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if uid == 0 {
+		return nil
+	}
+
+	issuedAt, _ := ctx.Value(user.ContextIssuedAtKey{}).(time.Time)
+
+	err := checker.CheckSession(ctx, uid, issuedAt)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, domain2.ErrAccountDeleted), errors.Is(err, domain2.ErrAccountDeleting), errors.Is(err, domain2.ErrSessionRevoked):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return status.Error(codes.Internal, domain2.ErrInternalServerError.Error())
+	}
+}
@@ -0,0 +1,69 @@
+package interceptors
+
+import (
+	"context"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/auth"
+	"gophkeeper/internal/server/repository/memory"
+	pb "gophkeeper/proto"
+	"gophkeeper/server/domain"
+	"gophkeeper/server/user"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestSession(t *testing.T) {
+	internal.InitLogger()
+	ctx := context.Background()
+
+	users := memory.NewUserRepository()
+	alice, err := users.Store(ctx, domain.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+
+	lis = bufconn.Listen(bufSize)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(Auth, Session(user.NewService(users))))
+	pb.RegisterTestServiceServer(s, &TestServer{})
+	go func() {
+		assert.NoError(t, s.Serve(lis))
+	}()
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough://bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewTestServiceClient(conn)
+	call := func(uid uint64) error {
+		token, err := auth.BuildJWTString(uid)
+		require.NoError(t, err)
+
+		_, err = client.Test(metadata.NewOutgoingContext(ctx, metadata.Pairs(domain.AuthorizationMetaKey, domain.TokenSubstr+" "+token)), nil)
+
+		return err
+	}
+
+	assert.NoError(t, call(alice))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(alice+1)))
+
+	// токены, выданные до запроса на удаление, отозваны, новые отклоняются до отмены удаления
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, users.SetState(ctx, alice, domain.AccountState{PurgeAt: now.Add(time.Hour), SessionsAfter: now.Add(time.Hour)}))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(alice)))
+
+	require.NoError(t, users.SetState(ctx, alice, domain.AccountState{SessionsAfter: now.Add(time.Hour)}))
+	err = call(alice)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.ErrorContains(t, err, domain.ErrSessionRevoked.Error())
+
+	require.NoError(t, users.SetState(ctx, alice, domain.AccountState{SessionsAfter: now}))
+	assert.NoError(t, call(alice))
+}
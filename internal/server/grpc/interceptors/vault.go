@@ -0,0 +1,110 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/user"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const wrongVaultMeta = "wrong vault meta"
+
+// VaultResolver определение учетной записи хранилища организации
+type VaultResolver interface {
+	Vault(ctx context.Context, orgID, uid uint64, write bool, keyVersion uint64) (uint64, error)
+}
+
+// vaultMethods методы, доступные в хранилище организации, true - метод изменяет данные
+var vaultMethods = map[string]bool{
+	proto.DataService_GetDataList_FullMethodName:  false,
+	proto.DataService_GetData_FullMethodName:      false,
+	proto.DataService_DownloadFile_FullMethodName: false,
+	proto.DataService_GetFolders_FullMethodName:   false,
+	proto.DataService_SaveData_FullMethodName:     true,
+	proto.DataService_DeleteData_FullMethodName:   true,
+	proto.DataService_UploadFile_FullMethodName:   true,
+	proto.DataService_SaveFolder_FullMethodName:   true,
+	proto.DataService_DeleteFolder_FullMethodName: true,
+}
+
+// Vault подмена пользователя на учетную запись хранилища организации, если в запросе передана метаинформация vault
+// должен вызываться после Auth
+func Vault(resolver VaultResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		vaultCtx, err := vaultContext(ctx, resolver, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(vaultCtx, req)
+	}
+}
+
+// StreamVault подмена пользователя на учетную запись хранилища организации в потоковых запросах
+func StreamVault(resolver VaultResolver) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		vaultCtx, err := vaultContext(ss.Context(), resolver, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		if vaultCtx == ss.Context() {
+			return handler(srv, ss)
+		}
+
+		sw := newStreamContextWrapper(ss)
+		sw.SetContext(vaultCtx)
+
+		return handler(srv, sw)
+	}
+}
+
+func vaultContext(ctx context.Context, resolver VaultResolver, method string) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[domain2.VaultMetaKey]) == 0 {
+		return ctx, nil
+	}
+
+	write, ok := vaultMethods[method]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, domain2.ErrVaultMethod.Error())
+	}
+
+	orgID, err := strconv.ParseUint(md[domain2.VaultMetaKey][0], 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, wrongVaultMeta)
+	}
+
+	var keyVersion uint64
+	if vals := md[domain2.VaultKeyVersionMetaKey]; len(vals) > 0 {
+		if keyVersion, err = strconv.ParseUint(vals[0], 10, 64); err != nil {
+			return nil, status.Error(codes.InvalidArgument, wrongVaultMeta)
+		}
+	}
+
+	uid, _ := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if uid == 0 {
+		return nil, status.Error(codes.Unauthenticated, authenticatedMetaNotFound)
+	}
+
+	vaultUID, err := resolver.Vault(ctx, orgID, uid, write, keyVersion)
+	switch {
+	case err == nil:
+		return context.WithValue(context.WithValue(ctx, user.ContextActorIDKey{}, uid), user.ContextUserIDKey{}, vaultUID), nil
+	case errors.Is(err, domain2.ErrOrgNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain2.ErrOrgForbidden):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain2.ErrOrgKeyVersion):
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	return nil, status.Error(codes.Internal, domain2.ErrInternalServerError.Error())
+}
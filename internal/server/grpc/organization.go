@@ -0,0 +1,186 @@
+package grpc
+
+import (
+	"context"
+	"gophkeeper/internal"
+	pb "gophkeeper/proto"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/organization"
+	"gophkeeper/server/user"
+
+	"github.com/bufbuild/protovalidate-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// OrganizationServer управление организациями и их участниками
+// работа с данными хранилища организации идет через DataServer с метаданными vault
+type OrganizationServer struct {
+	pb.UnimplementedOrganizationServiceServer
+	Service *organization.Service
+}
+
+func NewOrganizationServer(s *organization.Service) *OrganizationServer {
+	return &OrganizationServer{
+		Service: s,
+	}
+}
+
+// CreateOrganization создание организации
+func (o *OrganizationServer) CreateOrganization(ctx context.Context, req *pb.CreateOrganizationRequest) (*pb.CreateOrganizationResponse, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	org, err := o.Service.Create(ctx, uid, req.GetName(), req.GetCollectionKey())
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &pb.CreateOrganizationResponse{Id: org.ID}, nil
+}
+
+// GetOrganizations организации пользователя и приглашения в них
+func (o *OrganizationServer) GetOrganizations(ctx context.Context, req *emptypb.Empty) (*pb.OrganizationListResponse, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	list, err := o.Service.GetList(ctx, uid)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	res := &pb.OrganizationListResponse{Organizations: make([]*pb.Organization, 0, len(list))}
+	for _, m := range list {
+		res.Organizations = append(res.Organizations, &pb.Organization{
+			Id:            m.Org.ID,
+			Name:          m.Org.Name,
+			Role:          pb.OrgRole(m.Member.Role),
+			CollectionKey: m.Member.CollectionKey,
+			KeyVersion:    m.Member.KeyVersion,
+			Accepted:      m.Member.Accepted,
+		})
+	}
+
+	return res, nil
+}
+
+// GetMembers участники организации
+func (o *OrganizationServer) GetMembers(ctx context.Context, req *pb.OrgRequest) (*pb.OrgMembersResponse, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	org, list, err := o.Service.GetMembers(ctx, req.GetOrgId(), uid)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	res := &pb.OrgMembersResponse{KeyVersion: org.KeyVersion, Members: make([]*pb.OrgMember, 0, len(list))}
+	for _, m := range list {
+		res.Members = append(res.Members, &pb.OrgMember{Login: m.Login, Role: pb.OrgRole(m.Role), Accepted: m.Accepted})
+	}
+
+	return res, nil
+}
+
+// InviteMember приглашение пользователя в организацию
+func (o *OrganizationServer) InviteMember(ctx context.Context, req *pb.InviteMemberRequest) (*emptypb.Empty, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	err = o.Service.Invite(ctx, uid, req.GetOrgId(), req.GetLogin(), domain2.OrgRole(req.GetRole()), req.GetCollectionKey(), req.GetKeyVersion())
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// AcceptInvite принятие приглашения
+func (o *OrganizationServer) AcceptInvite(ctx context.Context, req *pb.OrgRequest) (*emptypb.Empty, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	if err = o.Service.Accept(ctx, uid, req.GetOrgId()); err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// SetMemberRole изменение роли участника
+func (o *OrganizationServer) SetMemberRole(ctx context.Context, req *pb.SetMemberRoleRequest) (*emptypb.Empty, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	if err = o.Service.SetRole(ctx, uid, req.GetOrgId(), req.GetLogin(), domain2.OrgRole(req.GetRole())); err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RemoveMember исключение участника со сменой ключа хранилища
+func (o *OrganizationServer) RemoveMember(ctx context.Context, req *pb.RemoveMemberRequest) (*emptypb.Empty, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	keys := make(map[string]string, len(req.GetKeys()))
+	for _, k := range req.GetKeys() {
+		keys[k.GetLogin()] = k.GetCollectionKey()
+	}
+
+	rotation := domain2.OrgRotation{KeyVersion: req.GetKeyVersion()}
+
+	for _, r := range req.GetRecords() {
+		rotated := domain2.RotatedRecord{ID: r.GetDataId(), RecordKey: r.GetRecordKey(), Tags: r.GetTags()}
+		if nameHash := r.GetNameHash(); nameHash != "" {
+			rotated.NameHash = &nameHash
+		}
+
+		rotation.Records = append(rotation.Records, rotated)
+	}
+
+	for _, f := range req.GetFolders() {
+		rotation.Folders = append(rotation.Folders, domain2.Folder{ID: f.GetFolderId(), Name: f.GetName()})
+	}
+
+	if err = o.Service.Remove(ctx, uid, req.GetOrgId(), req.GetLogin(), keys, rotation); err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// orgRequest ИД пользователя и проверка запроса
+func orgRequest(ctx context.Context, req proto.Message) (uint64, error) {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return 0, domain2.ErrUserIDAbsent
+	}
+
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		internal.Logger.Errorw("organization request validation error", "err", err)
+		return 0, domain2.ErrBadData
+	}
+
+	return ctxUID, nil
+}
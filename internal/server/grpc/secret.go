@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"context"
+	pb "gophkeeper/proto"
+	"gophkeeper/server/secret"
+	"time"
+)
+
+// SecretServer создание одноразовых ссылок на секреты, секреты по ссылкам выдает HTTP сервер
+type SecretServer struct {
+	pb.UnimplementedSecretServiceServer
+	Service *secret.Service
+}
+
+func NewSecretServer(s *secret.Service) *SecretServer {
+	return &SecretServer{
+		Service: s,
+	}
+}
+
+// CreateSecretShare сохранение зашифрованного секрета и выдача ссылки на него
+func (s *SecretServer) CreateSecretShare(ctx context.Context, req *pb.CreateSecretShareRequest) (*pb.CreateSecretShareResponse, error) {
+	uid, err := orgRequest(ctx, req)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	share, url, err := s.Service.Create(ctx, uid, req.GetBlob(), time.Duration(req.GetExpireSeconds())*time.Second, int(req.GetMaxViews()))
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &pb.CreateSecretShareResponse{Id: share.ID, Url: url}, nil
+}
@@ -2,12 +2,15 @@ package grpc
 
 import (
 	"context"
+	"gophkeeper/server/account"
 	"gophkeeper/server/domain"
 	"gophkeeper/server/user"
+	"time"
 
 	"github.com/bufbuild/protovalidate-go"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	"gophkeeper/internal"
 )
@@ -17,6 +20,8 @@ import pb "gophkeeper/proto"
 type UserServer struct {
 	pb.UnimplementedUserServiceServer
 	Service *user.Service
+	// Account удаление учетных записей, nil - удаление недоступно
+	Account *account.Service
 }
 
 func NewUserServer(s *user.Service) *UserServer {
@@ -32,14 +37,15 @@ func (u *UserServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	token, err := u.Service.Register(ctx, ur.User)
+	tokens, err := u.Service.Register(ctx, ur.User)
 	if err != nil {
 		return nil, getError(err)
 	}
 
 	return &pb.RegisterResponse{
-		Token: token,
-		Error: "",
+		Token:        tokens.Access,
+		RefreshToken: tokens.Refresh,
+		Error:        "",
 	}, nil
 }
 
@@ -50,14 +56,147 @@ func (u *UserServer) Login(ctx context.Context, req *pb.RegisterRequest) (*pb.Re
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	token, err := u.Service.Login(ctx, ur.User)
+	tokens, err := u.Service.Login(ctx, ur.User)
 	if err != nil {
 		return nil, getError(err)
 	}
 
 	return &pb.RegisterResponse{
-		Token: token,
-		Error: "",
+		Token:        tokens.Access,
+		RefreshToken: tokens.Refresh,
+		Error:        "",
+	}, nil
+}
+
+// Refresh выдача нового токена доступа по токену обновления
+func (u *UserServer) Refresh(ctx context.Context, req *pb.RefreshRequest) (*pb.RegisterResponse, error) {
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	tokens, err := u.Service.Refresh(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &pb.RegisterResponse{
+		Token:        tokens.Access,
+		RefreshToken: tokens.Refresh,
+	}, nil
+}
+
+// SetKeys сохранение пары ключей пользователя
+func (u *UserServer) SetKeys(ctx context.Context, req *pb.KeyPair) (*emptypb.Empty, error) {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return nil, getError(domain.ErrUserIDAbsent)
+	}
+
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = u.Service.SetKeys(ctx, ctxUID, domain.UserKeys{PublicKey: req.GetPublicKey(), PrivateKey: req.GetPrivateKey()})
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetKeys получение пары ключей пользователя, пустой ответ - если ключи еще не заданы
+func (u *UserServer) GetKeys(ctx context.Context, _ *emptypb.Empty) (*pb.KeyPair, error) {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return nil, getError(domain.ErrUserIDAbsent)
+	}
+
+	keys, err := u.Service.GetKeys(ctx, ctxUID)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &pb.KeyPair{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey}, nil
+}
+
+// GetPublicKey получение открытого ключа другого пользователя по логину
+func (u *UserServer) GetPublicKey(ctx context.Context, req *pb.PublicKeyRequest) (*pb.PublicKeyResponse, error) {
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	key, err := u.Service.GetPublicKey(ctx, req.GetLogin())
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &pb.PublicKeyResponse{PublicKey: key}, nil
+}
+
+// DeleteAccount удаление учетной записи после повторного ввода пароля, сразу или по истечении grace_days дней
+func (u *UserServer) DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	ctxUID := ctx.Value(user.ContextUserIDKey{}).(uint64)
+	if ctxUID == 0 {
+		return nil, getError(domain.ErrUserIDAbsent)
+	}
+
+	if u.Account == nil {
+		return nil, status.Error(codes.Unimplemented, "account deletion is not available")
+	}
+
+	v, err := protovalidate.New()
+	if err != nil {
+		internal.Logger.Fatalw("failed to initialize validator", "err", err)
+	}
+
+	if err = v.Validate(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	grace := time.Duration(req.GetGraceDays()) * 24 * time.Hour
+	purgeAt, err := u.Account.Delete(ctx, ctxUID, req.GetPassword(), grace)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	resp := &pb.DeleteAccountResponse{}
+	if !purgeAt.IsZero() {
+		resp.PurgeAt = purgeAt.Unix()
+	}
+
+	return resp, nil
+}
+
+// RestoreAccount отмена запланированного удаления учетной записи по логину и паролю
+func (u *UserServer) RestoreAccount(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	ur := &userRequest{}
+	if err := ur.Bind(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	tokens, err := u.Service.Restore(ctx, ur.User)
+	if err != nil {
+		return nil, getError(err)
+	}
+
+	return &pb.RegisterResponse{
+		Token:        tokens.Access,
+		RefreshToken: tokens.Refresh,
 	}, nil
 }
 
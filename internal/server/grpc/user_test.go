@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
 	"gophkeeper/internal/server/repository/pgsql"
 	"gophkeeper/internal/test"
 	"gophkeeper/proto"
@@ -17,6 +18,8 @@ import (
 )
 
 func TestUserServer_Register(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	internal.InitLogger()
 	ctx := context.Background()
 
@@ -119,6 +122,8 @@ func TestUserServer_Register(t *testing.T) {
 }
 
 func TestUserServer_Login(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	internal.InitLogger()
 	ctx := context.Background()
 
@@ -211,3 +216,26 @@ func TestUserServer_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestUserServer_Refresh(t *testing.T) {
+	internal.InitLogger()
+	ctx := context.Background()
+
+	server := NewUserServer(user.NewService(memory.NewUserRepository()))
+
+	registered, err := server.Register(ctx, &proto.RegisterRequest{User: &proto.User{Login: "refresh", Password: "testtest"}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, registered.GetRefreshToken())
+
+	got, err := server.Refresh(ctx, &proto.RefreshRequest{RefreshToken: registered.GetRefreshToken()})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, got.GetToken())
+	assert.NotEmpty(t, got.GetRefreshToken())
+
+	// токен доступа не подходит для обновления
+	_, err = server.Refresh(ctx, &proto.RefreshRequest{RefreshToken: registered.GetToken()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = server.Refresh(ctx, &proto.RefreshRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,55 @@
+package dataquery
+
+import (
+	"gophkeeper/server/domain"
+	"strconv"
+	"strings"
+)
+
+// AuditColumns колонки события журнала аудита в порядке полей domain.AuditEvent
+// события, записанные до появления цепочки хешей, хранятся без хешей
+const AuditColumns = `id, uid, actor_uid, actor, ip, action, data_id, outcome, created_at, coalesce(prev_hash, ''), coalesce(hash, '')`
+
+// AuditList построение запроса событий журнала аудита пользователя по фильтру
+// в список попадают события учетной записи пользователя и действия самого пользователя в других хранилищах
+// возвращает запрос с подстановкой #T# вместо имени таблицы и его параметры
+func AuditList(param func(n int) string, uid uint64, filter domain.AuditFilter) (string, []any) {
+	b := NewBuilder(param)
+	b.Where("(uid = ? or actor_uid = ?)", uid, uid)
+
+	if len(filter.Actions) > 0 {
+		args := make([]any, len(filter.Actions))
+		for i, a := range filter.Actions {
+			args[i] = a
+		}
+
+		b.Where("action in ("+strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")+")", args...)
+	}
+
+	if filter.DataID != 0 {
+		b.Where("data_id = ?", filter.DataID)
+	}
+
+	if filter.Outcome != 0 {
+		b.Where("outcome = ?", filter.Outcome)
+	}
+
+	if !filter.Since.IsZero() {
+		b.Where("created_at >= ?", filter.Since.Unix())
+	}
+
+	if !filter.Until.IsZero() {
+		b.Where("created_at < ?", filter.Until.Unix())
+	}
+
+	if filter.BeforeID != 0 {
+		b.Where("id < ?", filter.BeforeID)
+	}
+
+	query := `select ` + AuditColumns + ` from #T#` + b.WhereSQL() + ` order by id desc`
+	if filter.Limit > 0 {
+		query += ` limit ` + strconv.Itoa(filter.Limit)
+	}
+
+	return query, b.Args()
+}
@@ -0,0 +1,166 @@
+// Package dataquery общие для SQL хранилищ части запроса списка данных пользователя
+package dataquery
+
+import (
+	"gophkeeper/server/domain"
+	"strconv"
+	"strings"
+)
+
+// Builder накапливает условия и параметры запроса
+type Builder struct {
+	param func(n int) string
+	where []string
+	args  []any
+}
+
+// NewBuilder создание построителя запроса, param - формат параметра запроса в диалекте базы данных
+func NewBuilder(param func(n int) string) *Builder {
+	return &Builder{param: param}
+}
+
+// Where добавить условие, вместо ? в условие подставляются параметры в формате диалекта
+func (b *Builder) Where(cond string, args ...any) {
+	for _, arg := range args {
+		b.args = append(b.args, arg)
+		cond = strings.Replace(cond, "?", b.param(len(b.args)), 1)
+	}
+
+	b.where = append(b.where, cond)
+}
+
+// Args параметры запроса
+func (b *Builder) Args() []any {
+	return b.args
+}
+
+// WhereSQL условия запроса, объединенные через and
+func (b *Builder) WhereSQL() string {
+	if len(b.where) == 0 {
+		return ""
+	}
+
+	return " where " + strings.Join(b.where, " and ")
+}
+
+// List построение запроса списка данных пользователя по фильтру
+// в список попадают собственные записи и чужие, к которым пользователю предоставлен доступ
+// возвращает запрос с подстановкой #T# вместо имени таблицы и его параметры
+func List(param func(n int) string, uid uint64, filter domain.DataListFilter) (string, []any) {
+	b := NewBuilder(param)
+
+	switch {
+	case filter.Shared:
+		b.Where(sharedCondition, uid)
+	case filter.FolderID != 0 || len(filter.Tags) > 0:
+		b.Where("uid = ?", uid)
+	default:
+		b.Where("(uid = ? or "+sharedCondition+")", uid, uid)
+	}
+
+	if filter.NamePrefix != "" {
+		b.Where(`name like ? escape '\'`, escapeLike(filter.NamePrefix)+"%")
+	}
+
+	if filter.NameContains != "" {
+		b.Where(`lower(name) like lower(?) escape '\'`, "%"+escapeLike(filter.NameContains)+"%")
+	}
+
+	if filter.FolderID != 0 {
+		b.Where("folder_id = ?", filter.FolderID)
+	}
+
+	for _, tag := range filter.Tags {
+		b.Where("exists (select 1 from #T#_tag t where t.data_id = #T#.id and t.tag = ?)", tag)
+	}
+
+	if cond := typeCondition(filter.Type); cond != "" {
+		b.Where(cond)
+	}
+
+	if filter.After != nil {
+		switch filter.Sort {
+		case domain.DataSortNameDesc:
+			b.Where("(name, id) < (?, ?)", filter.After.Name, filter.After.ID)
+		case domain.DataSortIDAsc:
+			b.Where("id > ?", filter.After.ID)
+		case domain.DataSortIDDesc:
+			b.Where("id < ?", filter.After.ID)
+		default:
+			b.Where("(name, id) > (?, ?)", filter.After.Name, filter.After.ID)
+		}
+	}
+
+	query := `select id, name, version, folder_id, uid, record_key from #T#` + b.WhereSQL() + ` order by ` + orderBy(filter.Sort)
+	if filter.Limit > 0 {
+		query += ` limit ` + strconv.Itoa(filter.Limit)
+	}
+
+	return query, b.Args()
+}
+
+// Tags построение запроса тегов для набора записей
+// возвращает запрос с подстановкой #T# вместо имени таблицы данных и его параметры
+func Tags(param func(n int) string, ids []uint64) (string, []any) {
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+
+	for i, id := range ids {
+		placeholders[i] = param(i + 1)
+		args[i] = id
+	}
+
+	return `select data_id, tag from #T#_tag where data_id in (` + strings.Join(placeholders, ", ") + `) order by data_id, tag`, args
+}
+
+// Shares построение запроса доступов пользователя к набору записей
+// возвращает запрос с подстановкой #T# вместо имени таблицы данных и его параметры
+func Shares(param func(n int) string, uid uint64, ids []uint64) (string, []any) {
+	placeholders := make([]string, len(ids))
+	args := []any{uid}
+
+	for i, id := range ids {
+		placeholders[i] = param(i + 2)
+		args = append(args, id)
+	}
+
+	return `select data_id, uid, record_key, permission from #T#_share where uid = ` + param(1) +
+		` and data_id in (` + strings.Join(placeholders, ", ") + `)`, args
+}
+
+// sharedCondition условие на записи, к которым пользователю предоставлен доступ
+const sharedCondition = "exists (select 1 from #T#_share s where s.data_id = #T#.id and s.uid = ?)"
+
+func typeCondition(t domain.DataType) string {
+	switch t {
+	case domain.DataTypeCredentials:
+		return "(coalesce(login, '') <> '' or coalesce(pass, '') <> '')"
+	case domain.DataTypeCard:
+		return "coalesce(card_num, '') <> ''"
+	case domain.DataTypeText:
+		return "coalesce(text, '') <> ''"
+	case domain.DataTypeFile:
+		return "file_id is not null"
+	case domain.DataTypeOTP:
+		return "coalesce(otp, '') <> ''"
+	default:
+		return ""
+	}
+}
+
+func orderBy(sort domain.DataSort) string {
+	switch sort {
+	case domain.DataSortNameDesc:
+		return "name desc, id desc"
+	case domain.DataSortIDAsc:
+		return "id"
+	case domain.DataSortIDDesc:
+		return "id desc"
+	default:
+		return "name, id"
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+)
+
+// AccountRepository удаление учетных записей вместе со всеми данными из хранилищ в памяти
+type AccountRepository struct {
+	users     *UserRepository
+	data      *DataRepository
+	files     *FileRepository
+	folders   *FolderRepository
+	orgs      *OrganizationRepository
+	emergency *EmergencyRepository
+	secrets   *SecretRepository
+}
+
+func NewAccountRepository(users *UserRepository, data *DataRepository, files *FileRepository, folders *FolderRepository,
+	orgs *OrganizationRepository, emergency *EmergencyRepository, secrets *SecretRepository) *AccountRepository {
+	return &AccountRepository{
+		users:     users,
+		data:      data,
+		files:     files,
+		folders:   folders,
+		orgs:      orgs,
+		emergency: emergency,
+		secrets:   secrets,
+	}
+}
+
+// Purge удаление организаций purge.OrgIDs и учетных записей purge.UIDs со всеми их данными
+// возвращаются пути файлов, которые нужно удалить с диска
+func (a *AccountRepository) Purge(_ context.Context, purge domain.AccountPurge) ([]string, error) {
+	a.orgs.mu.Lock()
+	for _, id := range purge.OrgIDs {
+		delete(a.orgs.orgs, id)
+		delete(a.orgs.members, id)
+	}
+
+	for _, members := range a.orgs.members {
+		for _, uid := range purge.UIDs {
+			delete(members, uid)
+		}
+	}
+	a.orgs.mu.Unlock()
+
+	var paths []string
+	for _, uid := range purge.UIDs {
+		for _, fileID := range a.purgeData(uid) {
+			a.files.mu.Lock()
+			if f, ok := a.files.files[fileID]; ok {
+				paths = append(paths, f.Path)
+				delete(a.files.files, fileID)
+			}
+			a.files.mu.Unlock()
+		}
+
+		a.folders.mu.Lock()
+		for id, f := range a.folders.folders {
+			if f.UID == uid {
+				delete(a.folders.folders, id)
+			}
+		}
+		a.folders.mu.Unlock()
+
+		a.emergency.mu.Lock()
+		for id, g := range a.emergency.grants {
+			if g.GrantorUID == uid || g.GranteeUID == uid {
+				delete(a.emergency.grants, id)
+			}
+		}
+		a.emergency.mu.Unlock()
+
+		a.secrets.mu.Lock()
+		for id, s := range a.secrets.secrets {
+			if s.UID == uid {
+				delete(a.secrets.secrets, id)
+			}
+		}
+		a.secrets.mu.Unlock()
+
+		a.users.delete(uid)
+	}
+
+	return paths, nil
+}
+
+// purgeData удаление записей пользователя uid и доступа к записям, выданного ему и им, возвращаются ИД файлов записей
+func (a *AccountRepository) purgeData(uid uint64) []uint64 {
+	a.data.mu.Lock()
+	defer a.data.mu.Unlock()
+
+	var files []uint64
+	for id, d := range a.data.data {
+		if d.UID != uid {
+			continue
+		}
+
+		if d.FileID != nil {
+			files = append(files, *d.FileID)
+		}
+
+		delete(a.data.data, id)
+		delete(a.data.shares, id)
+	}
+
+	for _, shares := range a.data.shares {
+		delete(shares, uid)
+	}
+
+	return files
+}
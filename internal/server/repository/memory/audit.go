@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"slices"
+	"sync"
+)
+
+// AuditRepository журнал аудита в памяти
+type AuditRepository struct {
+	mu          sync.Mutex
+	events      []domain.AuditEvent
+	checkpoints []domain.AuditCheckpoint
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+// Insert добавление события в конец цепочки, в event заполняются ИД и хеши
+func (a *AuditRepository) Insert(_ context.Context, event *domain.AuditEvent) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	event.PrevHash = ""
+	if len(a.events) > 0 {
+		event.PrevHash = a.events[len(a.events)-1].Hash
+	}
+
+	event.ID = uint64(len(a.events) + 1)
+	event.Hash = event.ChainHash()
+	a.events = append(a.events, *event)
+
+	return nil
+}
+
+// GetList события журнала пользователя uid по фильтру, от новых к старым
+func (a *AuditRepository) GetList(_ context.Context, uid uint64, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var res []domain.AuditEvent
+	for i := len(a.events) - 1; i >= 0; i-- {
+		e := a.events[i]
+
+		switch {
+		case e.UID != uid && e.ActorUID != uid,
+			len(filter.Actions) > 0 && !slices.Contains(filter.Actions, e.Action),
+			filter.DataID != 0 && e.DataID != filter.DataID,
+			filter.Outcome != 0 && e.Outcome != filter.Outcome,
+			!filter.Since.IsZero() && e.CreatedAt.Before(filter.Since),
+			!filter.Until.IsZero() && !e.CreatedAt.Before(filter.Until),
+			filter.BeforeID != 0 && e.ID >= filter.BeforeID:
+			continue
+		}
+
+		res = append(res, e)
+		if filter.Limit > 0 && len(res) == filter.Limit {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// Scan страница всех событий журнала после события afterID по возрастанию ИД
+func (a *AuditRepository) Scan(_ context.Context, afterID uint64, limit int) ([]domain.AuditEvent, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var res []domain.AuditEvent
+	for _, e := range a.events {
+		if e.ID > afterID && len(res) < limit {
+			res = append(res, e)
+		}
+	}
+
+	return res, nil
+}
+
+// Head неподписанная отметка последнего события журнала, EventID = 0 - журнал пуст
+func (a *AuditRepository) Head(_ context.Context) (domain.AuditCheckpoint, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.events) == 0 {
+		return domain.AuditCheckpoint{}, nil
+	}
+
+	last := a.events[len(a.events)-1]
+
+	return domain.AuditCheckpoint{EventID: last.ID, Hash: last.Hash, Events: int64(len(a.events))}, nil
+}
+
+// InsertCheckpoint сохранение подписанной отметки, в checkpoint заполняется ИД
+func (a *AuditRepository) InsertCheckpoint(_ context.Context, checkpoint *domain.AuditCheckpoint) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	checkpoint.ID = uint64(len(a.checkpoints) + 1)
+	a.checkpoints = append(a.checkpoints, *checkpoint)
+
+	return nil
+}
+
+// GetCheckpoints все отметки журнала по возрастанию ИД
+func (a *AuditRepository) GetCheckpoints(_ context.Context) ([]domain.AuditCheckpoint, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return slices.Clone(a.checkpoints), nil
+}
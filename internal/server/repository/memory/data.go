@@ -0,0 +1,392 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/server/domain"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUniqueViolation нарушение уникальности пары (name, uid), аналог ограничения в базе данных
+var ErrUniqueViolation = errors.New("data name and user id must be unique")
+
+// DataRepository хранилище данных пользователей в памяти
+type DataRepository struct {
+	mu   sync.RWMutex
+	data map[uint64]domain.Data
+	// shares доступы к записям по ИД записи и ИД пользователя
+	shares map[uint64]map[uint64]domain.DataShare
+	lastID uint64
+}
+
+func NewDataRepository() *DataRepository {
+	return &DataRepository{
+		data:   make(map[uint64]domain.Data),
+		shares: make(map[uint64]map[uint64]domain.DataShare),
+	}
+}
+
+// Insert добавление новой записи
+func (d *DataRepository) Insert(_ context.Context, data *domain.Data) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.findByName(data.UID, data.Name) != 0 || d.findByNameHash(data.UID, data.NameHash) != 0 {
+		return ErrUniqueViolation
+	}
+
+	d.lastID++
+	data.ID = d.lastID
+	d.data[data.ID] = copyData(*data)
+
+	return nil
+}
+
+// Update обновление записи
+func (d *DataRepository) Update(_ context.Context, data domain.Data) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	row, ok := d.data[data.ID]
+	if !ok {
+		return nil
+	}
+
+	if id := d.findByName(row.UID, data.Name); id != 0 && id != data.ID {
+		return ErrUniqueViolation
+	}
+
+	if id := d.findByNameHash(row.UID, data.NameHash); id != 0 && id != data.ID {
+		return ErrUniqueViolation
+	}
+
+	row.Name = data.Name
+	row.NameHash = copyString(data.NameHash)
+	row.Login = copyString(data.Login)
+	row.Pass = copyString(data.Pass)
+	row.Text = copyString(data.Text)
+	row.CardNum = copyString(data.CardNum)
+	row.CardExp = copyString(data.CardExp)
+	row.Otp = copyString(data.Otp)
+	row.Meta = copyString(data.Meta)
+	row.Version = data.Version
+	row.FolderID = copyUint(data.FolderID)
+	row.RecordKey = copyString(data.RecordKey)
+	row.Tags = copyTags(data.Tags)
+	d.data[data.ID] = row
+
+	return nil
+}
+
+// UnsetFolder убрать записи пользователя из перечисленных папок
+func (d *DataRepository) UnsetFolder(_ context.Context, uid uint64, folderIDs []uint64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, row := range d.data {
+		if row.UID != uid || row.FolderID == nil || !slices.Contains(folderIDs, *row.FolderID) {
+			continue
+		}
+
+		row.FolderID = nil
+		d.data[id] = row
+	}
+
+	return nil
+}
+
+// SetFile добавление файла
+func (d *DataRepository) SetFile(_ context.Context, data domain.Data) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	row, ok := d.data[data.ID]
+	if !ok {
+		return nil
+	}
+
+	row.FileID = copyUint(data.FileID)
+	row.Version = data.Version
+	d.data[data.ID] = row
+
+	return nil
+}
+
+// GetByNameAndUserID получить ИД записи по названию и ИД пользователя, 0 - если запись не найдена
+func (d *DataRepository) GetByNameAndUserID(_ context.Context, uid uint64, name string) (uint64, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.findByName(uid, name), nil
+}
+
+// GetByNameHashAndUserID получить ИД записи по слепому индексу названия и ИД пользователя, 0 - если запись не найдена
+func (d *DataRepository) GetByNameHashAndUserID(_ context.Context, uid uint64, nameHash string) (uint64, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.findByNameHash(uid, &nameHash), nil
+}
+
+// Get получить запись по ИД
+func (d *DataRepository) Get(_ context.Context, id uint64) (*domain.Data, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	row, ok := d.data[id]
+	if !ok {
+		return nil, nil
+	}
+
+	row = copyData(row)
+
+	return &row, nil
+}
+
+// GetByUser получить запись по ИД для владельца или пользователя, которому предоставлен доступ
+// для чужой записи заполняется доступ, а папка и теги владельца не возвращаются
+func (d *DataRepository) GetByUser(_ context.Context, id, uid uint64) (*domain.Data, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	row, ok := d.data[id]
+	if !ok {
+		return nil, nil
+	}
+
+	row = copyData(row)
+	if row.UID == uid {
+		return &row, nil
+	}
+
+	share, ok := d.shares[id][uid]
+	if !ok {
+		return nil, nil
+	}
+
+	row.FolderID, row.Tags, row.Share = nil, nil, &share
+
+	return &row, nil
+}
+
+// GetShare получить доступ пользователя к записи, nil - если доступа нет
+func (d *DataRepository) GetShare(_ context.Context, dataID, uid uint64) (*domain.DataShare, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	share, ok := d.shares[dataID][uid]
+	if !ok {
+		return nil, nil
+	}
+
+	return &share, nil
+}
+
+// Share предоставить доступ к записи или изменить его
+func (d *DataRepository) Share(_ context.Context, share domain.DataShare) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.data[share.DataID]; !ok {
+		return nil
+	}
+
+	if d.shares[share.DataID] == nil {
+		d.shares[share.DataID] = make(map[uint64]domain.DataShare)
+	}
+
+	d.shares[share.DataID][share.UID] = share
+
+	return nil
+}
+
+// Unshare отозвать доступ пользователя к записи
+func (d *DataRepository) Unshare(_ context.Context, dataID, uid uint64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.shares[dataID], uid)
+
+	return nil
+}
+
+// GetList получить список для пользователя с учетом фильтра
+func (d *DataRepository) GetList(_ context.Context, uid uint64, filter domain.DataListFilter) ([]domain.DataName, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var res []domain.DataName
+
+	for _, row := range d.data {
+		dn := domain.DataName{ID: row.ID, Name: row.Name, Version: row.Version, UID: row.UID, RecordKey: copyString(row.RecordKey)}
+
+		if row.UID == uid {
+			// папки и теги есть только у собственных записей
+			if filter.Shared || !matchFilter(row, filter) {
+				continue
+			}
+
+			dn.FolderID, dn.Tags = copyUint(row.FolderID), copyTags(row.Tags)
+		} else {
+			share, ok := d.shares[row.ID][uid]
+			if !ok || filter.FolderID != 0 || len(filter.Tags) > 0 || !matchFilter(row, filter) {
+				continue
+			}
+
+			dn.Share = &share
+		}
+
+		res = append(res, dn)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return less(res[i], res[j], filter.Sort)
+	})
+
+	if filter.After != nil {
+		after := domain.DataName{ID: filter.After.ID, Name: filter.After.Name}
+		pos := sort.Search(len(res), func(i int) bool {
+			return less(after, res[i], filter.Sort)
+		})
+		res = res[pos:]
+	}
+
+	if filter.Limit > 0 && len(res) > filter.Limit {
+		res = res[:filter.Limit]
+	}
+
+	return res, nil
+}
+
+// Delete удалить запись
+func (d *DataRepository) Delete(_ context.Context, id uint64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.data, id)
+	delete(d.shares, id)
+
+	return nil
+}
+
+func matchFilter(row domain.Data, filter domain.DataListFilter) bool {
+	if filter.NamePrefix != "" && !strings.HasPrefix(row.Name, filter.NamePrefix) {
+		return false
+	}
+
+	if filter.NameContains != "" && !strings.Contains(strings.ToLower(row.Name), strings.ToLower(filter.NameContains)) {
+		return false
+	}
+
+	if filter.FolderID != 0 && (row.FolderID == nil || *row.FolderID != filter.FolderID) {
+		return false
+	}
+
+	for _, tag := range filter.Tags {
+		if !slices.Contains(row.Tags, tag) {
+			return false
+		}
+	}
+
+	switch filter.Type {
+	case domain.DataTypeCredentials:
+		return notEmpty(row.Login) || notEmpty(row.Pass)
+	case domain.DataTypeCard:
+		return notEmpty(row.CardNum)
+	case domain.DataTypeText:
+		return notEmpty(row.Text)
+	case domain.DataTypeFile:
+		return row.FileID != nil
+	case domain.DataTypeOTP:
+		return notEmpty(row.Otp)
+	}
+
+	return true
+}
+
+func less(a, b domain.DataName, order domain.DataSort) bool {
+	switch order {
+	case domain.DataSortNameDesc:
+		return a.Name > b.Name || (a.Name == b.Name && a.ID > b.ID)
+	case domain.DataSortIDAsc:
+		return a.ID < b.ID
+	case domain.DataSortIDDesc:
+		return a.ID > b.ID
+	default:
+		return a.Name < b.Name || (a.Name == b.Name && a.ID < b.ID)
+	}
+}
+
+func notEmpty(s *string) bool {
+	return s != nil && *s != ""
+}
+
+func (d *DataRepository) findByName(uid uint64, name string) uint64 {
+	for id, row := range d.data {
+		if row.UID == uid && row.Name == name {
+			return id
+		}
+	}
+
+	return 0
+}
+
+func (d *DataRepository) findByNameHash(uid uint64, nameHash *string) uint64 {
+	if nameHash == nil {
+		return 0
+	}
+
+	for id, row := range d.data {
+		if row.UID == uid && row.NameHash != nil && *row.NameHash == *nameHash {
+			return id
+		}
+	}
+
+	return 0
+}
+
+func copyData(data domain.Data) domain.Data {
+	data.Login = copyString(data.Login)
+	data.Pass = copyString(data.Pass)
+	data.Text = copyString(data.Text)
+	data.CardNum = copyString(data.CardNum)
+	data.CardExp = copyString(data.CardExp)
+	data.Otp = copyString(data.Otp)
+	data.Meta = copyString(data.Meta)
+	data.NameHash = copyString(data.NameHash)
+	data.RecordKey = copyString(data.RecordKey)
+	data.FileID = copyUint(data.FileID)
+	data.FolderID = copyUint(data.FolderID)
+	data.Tags = copyTags(data.Tags)
+
+	return data
+}
+
+func copyTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	return slices.Clone(tags)
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	v := *s
+	return &v
+}
+
+func copyUint(u *uint64) *uint64 {
+	if u == nil {
+		return nil
+	}
+
+	v := *u
+	return &v
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataRepository_Insert(t *testing.T) {
+	ctx := context.Background()
+	repo := NewDataRepository()
+
+	first := &domain.Data{Name: "test", UID: 1, Version: 1}
+	err := repo.Insert(ctx, first)
+	assert.NoError(t, err)
+	assert.NotZero(t, first.ID)
+
+	tests := []struct {
+		name    string
+		data    *domain.Data
+		wantErr error
+	}{
+		{
+			name:    "name exist",
+			data:    &domain.Data{Name: "test", UID: 1, Version: 1},
+			wantErr: ErrUniqueViolation,
+		},
+		{
+			name:    "same name other user",
+			data:    &domain.Data{Name: "test", UID: 2, Version: 1},
+			wantErr: nil,
+		},
+		{
+			name:    "other name",
+			data:    &domain.Data{Name: "test2", UID: 1, Version: 1},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err = repo.Insert(ctx, tt.data)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDataRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	repo := NewDataRepository()
+	login := "login"
+
+	first := &domain.Data{Name: "first", UID: 1, Version: 1, Login: &login}
+	second := &domain.Data{Name: "second", UID: 1, Version: 1}
+	assert.NoError(t, repo.Insert(ctx, first))
+	assert.NoError(t, repo.Insert(ctx, second))
+
+	err := repo.Update(ctx, domain.Data{ID: second.ID, Name: first.Name, Version: 2})
+	assert.ErrorIs(t, err, ErrUniqueViolation)
+
+	newLogin := "new login"
+	err = repo.Update(ctx, domain.Data{ID: first.ID, Name: "renamed", Version: 2, Login: &newLogin})
+	assert.NoError(t, err)
+
+	got, err := repo.GetByUser(ctx, first.ID, first.UID)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", got.Name)
+	assert.Equal(t, newLogin, *got.Login)
+	assert.Equal(t, uint64(2), got.Version)
+
+	// изменение полученной записи не должно менять хранилище
+	*got.Login = "changed"
+	got, err = repo.Get(ctx, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, newLogin, *got.Login)
+}
+
+func TestDataRepository_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := NewDataRepository()
+
+	data := &domain.Data{Name: "test", UID: 1, Version: 1}
+	assert.NoError(t, repo.Insert(ctx, data))
+
+	got, err := repo.Get(ctx, data.ID+1)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = repo.GetByUser(ctx, data.ID, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	id, err := repo.GetByNameAndUserID(ctx, 2, data.Name)
+	assert.NoError(t, err)
+	assert.Zero(t, id)
+
+	id, err = repo.GetByNameAndUserID(ctx, 1, data.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, data.ID, id)
+
+	assert.NoError(t, repo.Delete(ctx, data.ID))
+	list, err := repo.GetList(ctx, 1, domain.DataListFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestDataRepository_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewDataRepository()
+	count := 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := repo.Insert(ctx, &domain.Data{Name: strconv.Itoa(i), UID: 1, Version: 1})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	list, err := repo.GetList(ctx, 1, domain.DataListFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, list, count)
+}
@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EmergencyRepository хранилище экстренных доступов в памяти, логины берутся из хранилища пользователей
+type EmergencyRepository struct {
+	mu     sync.RWMutex
+	grants map[uint64]domain.EmergencyGrant
+	lastID uint64
+	users  *UserRepository
+}
+
+func NewEmergencyRepository(users *UserRepository) *EmergencyRepository {
+	return &EmergencyRepository{
+		grants: make(map[uint64]domain.EmergencyGrant),
+		users:  users,
+	}
+}
+
+// Insert сохранение нового экстренного доступа
+func (e *EmergencyRepository) Insert(_ context.Context, grant *domain.EmergencyGrant) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastID++
+	grant.ID = e.lastID
+	e.grants[grant.ID] = copyGrant(*grant)
+
+	return nil
+}
+
+// Get получить экстренный доступ по ИД, если доступ не найден - возвращается nil
+func (e *EmergencyRepository) Get(ctx context.Context, id uint64) (*domain.EmergencyGrant, error) {
+	return e.find(ctx, func(g domain.EmergencyGrant) bool {
+		return g.ID == id
+	})
+}
+
+// GetByPair получить экстренный доступ доверенного лица к хранилищу владельца, если доступа нет - возвращается nil
+func (e *EmergencyRepository) GetByPair(ctx context.Context, grantorUID, granteeUID uint64) (*domain.EmergencyGrant, error) {
+	return e.find(ctx, func(g domain.EmergencyGrant) bool {
+		return g.GrantorUID == grantorUID && g.GranteeUID == granteeUID
+	})
+}
+
+// GetByUser экстренные доступы, в которых пользователь владелец или доверенное лицо
+func (e *EmergencyRepository) GetByUser(ctx context.Context, uid uint64) ([]domain.EmergencyGrant, error) {
+	e.mu.RLock()
+	var res []domain.EmergencyGrant
+	for _, g := range e.grants {
+		if g.GrantorUID == uid || g.GranteeUID == uid {
+			res = append(res, copyGrant(g))
+		}
+	}
+	e.mu.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	for i := range res {
+		if err := e.setLogins(ctx, &res[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// Update изменение состояния экстренного доступа, который все еще находится в состоянии state,
+// иначе возвращается domain.ErrGrantState
+func (e *EmergencyRepository) Update(_ context.Context, grant domain.EmergencyGrant, state domain.EmergencyState) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	g, ok := e.grants[grant.ID]
+	if !ok || g.State != state {
+		return domain.ErrGrantState
+	}
+
+	g.State, g.RequestedAt = grant.State, copyTime(grant.RequestedAt)
+	e.grants[grant.ID] = g
+
+	return nil
+}
+
+// Delete удаление экстренного доступа
+func (e *EmergencyRepository) Delete(_ context.Context, id uint64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.grants, id)
+
+	return nil
+}
+
+// AutoApprove одобрение запросов, время ожидания которых истекло к моменту now, возвращается число одобренных запросов
+func (e *EmergencyRepository) AutoApprove(_ context.Context, now time.Time) (int64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var n int64
+	for id, g := range e.grants {
+		if g.State == domain.EmergencyRequested && g.RequestedAt != nil && !g.RequestedAt.Add(g.Wait).After(now) {
+			g.State = domain.EmergencyAutoApproved
+			e.grants[id] = g
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (e *EmergencyRepository) find(ctx context.Context, match func(g domain.EmergencyGrant) bool) (*domain.EmergencyGrant, error) {
+	e.mu.RLock()
+	var found *domain.EmergencyGrant
+	for _, g := range e.grants {
+		if match(g) {
+			g = copyGrant(g)
+			found = &g
+			break
+		}
+	}
+	e.mu.RUnlock()
+
+	if found == nil {
+		return nil, nil
+	}
+
+	return found, e.setLogins(ctx, found)
+}
+
+func (e *EmergencyRepository) setLogins(ctx context.Context, g *domain.EmergencyGrant) error {
+	grantor, err := e.users.GetByID(ctx, g.GrantorUID)
+	if err != nil {
+		return err
+	}
+
+	grantee, err := e.users.GetByID(ctx, g.GranteeUID)
+	if err != nil {
+		return err
+	}
+
+	g.Grantor, g.Grantee = grantor.Login, grantee.Login
+
+	return nil
+}
+
+func copyGrant(g domain.EmergencyGrant) domain.EmergencyGrant {
+	g.RequestedAt = copyTime(g.RequestedAt)
+	return g
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+
+	return &c
+}
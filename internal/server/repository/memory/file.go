@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"sync"
+)
+
+// FileRepository хранилище записей о файлах в памяти
+type FileRepository struct {
+	mu     sync.RWMutex
+	files  map[uint64]domain.File
+	lastID uint64
+}
+
+func NewFileRepository() *FileRepository {
+	return &FileRepository{
+		files: make(map[uint64]domain.File),
+	}
+}
+
+// Get получить файл по ИД, если файл не найден - возвращается nil
+func (f *FileRepository) Get(_ context.Context, id uint64) (*domain.File, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	file, ok := f.files[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &file, nil
+}
+
+// Insert добавить запись
+func (f *FileRepository) Insert(_ context.Context, file *domain.File) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastID++
+	file.ID = f.lastID
+	f.files[file.ID] = *file
+
+	return nil
+}
+
+// Update обновить запись
+func (f *FileRepository) Update(_ context.Context, file *domain.File) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.files[file.ID]; !ok {
+		return nil
+	}
+
+	f.files[file.ID] = *file
+
+	return nil
+}
+
+// Delete удалить запись
+func (f *FileRepository) Delete(_ context.Context, id uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.files, id)
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"sort"
+	"sync"
+)
+
+// FolderRepository хранилище папок пользователей в памяти
+type FolderRepository struct {
+	mu      sync.RWMutex
+	folders map[uint64]domain.Folder
+	lastID  uint64
+}
+
+func NewFolderRepository() *FolderRepository {
+	return &FolderRepository{
+		folders: make(map[uint64]domain.Folder),
+	}
+}
+
+// Insert добавление папки
+func (f *FolderRepository) Insert(_ context.Context, folder *domain.Folder) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastID++
+	folder.ID = f.lastID
+	f.folders[folder.ID] = copyFolder(*folder)
+
+	return nil
+}
+
+// Update переименование или перемещение папки
+func (f *FolderRepository) Update(_ context.Context, folder domain.Folder) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.folders[folder.ID]
+	if !ok {
+		return nil
+	}
+
+	row.Name = folder.Name
+	row.ParentID = copyUint(folder.ParentID)
+	f.folders[folder.ID] = row
+
+	return nil
+}
+
+// GetByUser получить папку по ИД для пользователя, если папка не найдена - возвращается nil
+func (f *FolderRepository) GetByUser(_ context.Context, id, uid uint64) (*domain.Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	row, ok := f.folders[id]
+	if !ok || row.UID != uid {
+		return nil, nil
+	}
+
+	row = copyFolder(row)
+
+	return &row, nil
+}
+
+// GetList получить все папки пользователя
+func (f *FolderRepository) GetList(_ context.Context, uid uint64) ([]domain.Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var res []domain.Folder
+	for _, row := range f.folders {
+		if row.UID == uid {
+			res = append(res, copyFolder(row))
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name || (res[i].Name == res[j].Name && res[i].ID < res[j].ID)
+	})
+
+	return res, nil
+}
+
+// Delete удалить папки
+func (f *FolderRepository) Delete(_ context.Context, ids []uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, id := range ids {
+		delete(f.folders, id)
+	}
+
+	return nil
+}
+
+func copyFolder(folder domain.Folder) domain.Folder {
+	folder.ParentID = copyUint(folder.ParentID)
+
+	return folder
+}
@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"sort"
+	"sync"
+)
+
+// OrganizationRepository хранилище организаций и их участников в памяти
+// учетные записи хранилищ создаются в хранилище пользователей, смена ключа изменяет записи и папки хранилища
+type OrganizationRepository struct {
+	mu      sync.RWMutex
+	orgs    map[uint64]domain.Organization
+	members map[uint64]map[uint64]domain.OrgMember
+	lastID  uint64
+	users   *UserRepository
+	data    *DataRepository
+	folders *FolderRepository
+}
+
+func NewOrganizationRepository(users *UserRepository, data *DataRepository, folders *FolderRepository) *OrganizationRepository {
+	return &OrganizationRepository{
+		orgs:    make(map[uint64]domain.Organization),
+		members: make(map[uint64]map[uint64]domain.OrgMember),
+		users:   users,
+		data:    data,
+		folders: folders,
+	}
+}
+
+// Insert создание организации вместе с учетной записью хранилища и владельцем
+func (o *OrganizationRepository) Insert(ctx context.Context, org *domain.Organization, owner domain.OrgMember) error {
+	vaultUID, err := o.users.Store(ctx, domain.User{})
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.lastID++
+	org.ID, org.VaultUID = o.lastID, vaultUID
+	o.orgs[org.ID] = *org
+
+	owner.OrgID = org.ID
+	o.members[org.ID] = map[uint64]domain.OrgMember{owner.UID: owner}
+
+	return nil
+}
+
+// Get получить организацию по ИД, если организация не найдена - возвращается nil
+func (o *OrganizationRepository) Get(_ context.Context, id uint64) (*domain.Organization, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	org, ok := o.orgs[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &org, nil
+}
+
+// GetByUser получить организации, в которых состоит или приглашен пользователь
+func (o *OrganizationRepository) GetByUser(ctx context.Context, uid uint64) ([]domain.OrgMembership, error) {
+	o.mu.RLock()
+	var res []domain.OrgMembership
+	for id, members := range o.members {
+		if m, ok := members[uid]; ok {
+			res = append(res, domain.OrgMembership{Org: o.orgs[id], Member: m})
+		}
+	}
+	o.mu.RUnlock()
+
+	for i := range res {
+		if err := o.setLogin(ctx, &res[i].Member); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Org.Name < res[j].Org.Name || (res[i].Org.Name == res[j].Org.Name && res[i].Org.ID < res[j].Org.ID)
+	})
+
+	return res, nil
+}
+
+// GetMember получить участника организации, если пользователь не состоит в организации - возвращается nil
+func (o *OrganizationRepository) GetMember(ctx context.Context, orgID, uid uint64) (*domain.OrgMember, error) {
+	o.mu.RLock()
+	m, ok := o.members[orgID][uid]
+	o.mu.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	if err := o.setLogin(ctx, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// GetMembers получить всех участников организации, включая приглашенных
+func (o *OrganizationRepository) GetMembers(ctx context.Context, orgID uint64) ([]domain.OrgMember, error) {
+	o.mu.RLock()
+	res := make([]domain.OrgMember, 0, len(o.members[orgID]))
+	for _, m := range o.members[orgID] {
+		res = append(res, m)
+	}
+	o.mu.RUnlock()
+
+	for i := range res {
+		if err := o.setLogin(ctx, &res[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Role > res[j].Role || (res[i].Role == res[j].Role && res[i].Login < res[j].Login)
+	})
+
+	return res, nil
+}
+
+// SaveMember добавление или изменение участника организации
+func (o *OrganizationRepository) SaveMember(_ context.Context, member domain.OrgMember) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.orgs[member.OrgID]; !ok {
+		return nil
+	}
+
+	member.Login = ""
+	o.members[member.OrgID][member.UID] = member
+
+	return nil
+}
+
+// DeleteMember исключение участника из организации
+func (o *OrganizationRepository) DeleteMember(_ context.Context, orgID, uid uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.members[orgID], uid)
+
+	return nil
+}
+
+// Rotate исключение участника и смена ключа хранилища
+// блокируются все три хранилища, чтобы изменения были видны только целиком
+func (o *OrganizationRepository) Rotate(_ context.Context, rotation domain.OrgRotation) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.data.mu.Lock()
+	defer o.data.mu.Unlock()
+	o.folders.mu.Lock()
+	defer o.folders.mu.Unlock()
+
+	org, ok := o.orgs[rotation.OrgID]
+	if !ok || org.KeyVersion != rotation.KeyVersion-1 {
+		return domain.ErrOrgKeyVersion
+	}
+
+	org.KeyVersion = rotation.KeyVersion
+	o.orgs[org.ID] = org
+
+	members := o.members[org.ID]
+	delete(members, rotation.RemoveUID)
+
+	for uid, key := range rotation.Keys {
+		if m, ok := members[uid]; ok {
+			m.CollectionKey, m.KeyVersion = key, rotation.KeyVersion
+			members[uid] = m
+		}
+	}
+
+	for _, r := range rotation.Records {
+		row, ok := o.data.data[r.ID]
+		if !ok || row.UID != rotation.VaultUID {
+			continue
+		}
+
+		recordKey := r.RecordKey
+		row.RecordKey, row.NameHash, row.Tags = &recordKey, copyString(r.NameHash), copyTags(r.Tags)
+		o.data.data[r.ID] = row
+	}
+
+	for _, f := range rotation.Folders {
+		row, ok := o.folders.folders[f.ID]
+		if !ok || row.UID != rotation.VaultUID {
+			continue
+		}
+
+		row.Name = f.Name
+		o.folders.folders[f.ID] = row
+	}
+
+	return nil
+}
+
+// setLogin логин участника берется из хранилища пользователей, как при соединении таблиц в базе данных
+func (o *OrganizationRepository) setLogin(ctx context.Context, m *domain.OrgMember) error {
+	u, err := o.users.GetByID(ctx, m.UID)
+	m.Login = u.Login
+
+	return err
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"sync"
+	"time"
+)
+
+// RateLimitRepository хранилище ограничений попыток входа в памяти, состояние видно только одному серверу
+type RateLimitRepository struct {
+	mu      sync.Mutex
+	buckets map[string]domain.RateBucket
+}
+
+func NewRateLimitRepository() *RateLimitRepository {
+	return &RateLimitRepository{
+		buckets: make(map[string]domain.RateBucket),
+	}
+}
+
+// Update изменение состояния ключа функцией update
+func (r *RateLimitRepository) Update(_ context.Context, key string, update func(bucket *domain.RateBucket)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket.Key = key
+	}
+
+	update(&bucket)
+	r.buckets[key] = bucket
+
+	return nil
+}
+
+// DeleteIdle удаление ключей без попыток и блокировок после before, возвращается число удаленных ключей
+func (r *RateLimitRepository) DeleteIdle(_ context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for key, bucket := range r.buckets {
+		if bucket.UpdatedAt.Before(before) && bucket.LockedUntil.Before(before) {
+			delete(r.buckets, key)
+			n++
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"sync"
+	"time"
+)
+
+// SecretRepository хранилище одноразовых ссылок на секреты в памяти
+type SecretRepository struct {
+	mu      sync.Mutex
+	secrets map[string]domain.SecretShare
+}
+
+func NewSecretRepository() *SecretRepository {
+	return &SecretRepository{
+		secrets: make(map[string]domain.SecretShare),
+	}
+}
+
+// Insert сохранение секрета
+func (s *SecretRepository) Insert(_ context.Context, share domain.SecretShare) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.secrets[share.ID] = share
+
+	return nil
+}
+
+// Take просмотр секрета, секрет, просмотренный последний раз, удаляется
+// если секрет не найден, истек или просмотрен - возвращается nil
+func (s *SecretRepository) Take(_ context.Context, id string, now time.Time) (*domain.SecretShare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	share, ok := s.secrets[id]
+	if !ok || !share.ExpiresAt.After(now) || share.Views >= share.MaxViews {
+		return nil, nil
+	}
+
+	share.Views++
+	s.secrets[id] = share
+
+	if share.Views >= share.MaxViews {
+		delete(s.secrets, id)
+	}
+
+	return &share, nil
+}
+
+// DeleteExpired удаление секретов, истекших к моменту now, возвращается число удаленных секретов
+func (s *SecretRepository) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for id, share := range s.secrets {
+		if !share.ExpiresAt.After(now) {
+			delete(s.secrets, id)
+			n++
+		}
+	}
+
+	return n, nil
+}
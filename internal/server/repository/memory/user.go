@@ -0,0 +1,137 @@
+// Package memory пакет с потокобезопасными реализациями хранилищ сервера в оперативной памяти.
+// Используется для демонстрационного запуска сервера и для тестов, которым не нужна база данных
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// UserRepository хранилище пользователей в памяти
+type UserRepository struct {
+	mu     sync.RWMutex
+	users  map[uint64]domain.User
+	keys   map[uint64]domain.UserKeys
+	states map[uint64]domain.AccountState
+	lastID uint64
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:  make(map[uint64]domain.User),
+		keys:   make(map[uint64]domain.UserKeys),
+		states: make(map[uint64]domain.AccountState),
+	}
+}
+
+// GetByLogin получить пользователя по логину
+// если пользователь не найден, возвращается пустая структура без ошибки
+func (u *UserRepository) GetByLogin(_ context.Context, login string) (domain.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	for _, user := range u.users {
+		if user.Login == login {
+			return user, nil
+		}
+	}
+
+	return domain.User{}, nil
+}
+
+// GetByID получить пользователя по ИД
+// если пользователь не найден, возвращается пустая структура без ошибки
+func (u *UserRepository) GetByID(_ context.Context, id uint64) (domain.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.users[id], nil
+}
+
+// GetKeys получить ключи пользователя, пустые - если ключи не заданы
+func (u *UserRepository) GetKeys(_ context.Context, id uint64) (domain.UserKeys, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.keys[id], nil
+}
+
+// SetKeys сохранить ключи пользователя
+func (u *UserRepository) SetKeys(_ context.Context, id uint64, keys domain.UserKeys) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.users[id]; ok {
+		u.keys[id] = keys
+	}
+
+	return nil
+}
+
+// Store добавить нового пользователя
+func (u *UserRepository) Store(_ context.Context, user domain.User) (uint64, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.lastID++
+	user.ID = u.lastID
+	u.users[user.ID] = user
+
+	return user.ID, nil
+}
+
+// GetState состояние учетной записи, nil - пользователь не найден
+func (u *UserRepository) GetState(_ context.Context, id uint64) (*domain.AccountState, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if _, ok := u.users[id]; !ok {
+		return nil, nil
+	}
+
+	state := u.states[id]
+
+	return &state, nil
+}
+
+// SetState сохранение состояния учетной записи
+func (u *UserRepository) SetState(_ context.Context, id uint64, state domain.AccountState) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.users[id]; ok {
+		u.states[id] = state
+	}
+
+	return nil
+}
+
+// GetPurgeDue ИД учетных записей, время окончательного удаления которых наступило к now
+func (u *UserRepository) GetPurgeDue(_ context.Context, now time.Time) ([]uint64, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	var ids []uint64
+	for id, state := range u.states {
+		if !state.PurgeAt.IsZero() && !state.PurgeAt.After(now) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids, nil
+}
+
+// delete удаление пользователя вместе с ключами и состоянием, вызывается хранилищем учетных записей
+func (u *UserRepository) delete(id uint64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delete(u.users, id)
+	delete(u.keys, id)
+	delete(u.states, id)
+}
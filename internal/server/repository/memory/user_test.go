@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRepository_GetByLogin(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository()
+
+	id, err := repo.Store(ctx, domain.User{Login: "test", Password: "pass"})
+	assert.NoError(t, err)
+	assert.NotZero(t, id)
+
+	got, err := repo.GetByLogin(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, id, got.ID)
+
+	got, err = repo.GetByLogin(ctx, "absent")
+	assert.NoError(t, err)
+	assert.Zero(t, got.ID)
+}
@@ -0,0 +1,154 @@
+// Package migrations общие миграции схемы базы данных для всех SQL хранилищ сервера.
+// Запросы описываются один раз с подстановками, зависящие от диалекта части заменяются при применении
+package migrations
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Подстановки, которые можно использовать в тексте миграций
+const (
+	TableVar      = "#T#"
+	UsersTableVar = "#UT#"
+	FileTableVar  = "#FT#"
+	serialVar     = "#SERIAL#"
+)
+
+// VersionsTableName таблица с версиями схемы таблиц
+const VersionsTableName = "schema_migrations"
+
+// Migration одна миграция таблицы
+// Dialect - если указан, миграция выполняется только для этого диалекта, для остальных только повышается версия
+type Migration struct {
+	Version int
+	Query   string
+	Dialect string
+}
+
+// Dialect особенности SQL конкретной базы данных
+type Dialect struct {
+	Name string
+	// Serial описание автоинкрементного первичного ключа
+	Serial string
+	// TableExists запрос, проверяющий наличие таблицы, возвращает количество найденных таблиц
+	TableExists string
+	// Param формат параметра запроса по его номеру (начиная с 1)
+	Param func(n int) string
+}
+
+// Диалекты поддерживаемых баз данных
+var (
+	Postgres = Dialect{
+		Name:        "postgres",
+		Serial:      "serial primary key",
+		TableExists: `select count(*) from information_schema.tables where table_schema = current_schema() and table_name = $1`,
+		Param: func(n int) string {
+			return "$" + strconv.Itoa(n)
+		},
+	}
+
+	SQLite = Dialect{
+		Name:        "sqlite",
+		Serial:      "integer primary key autoincrement",
+		TableExists: `select count(*) from sqlite_master where type = 'table' and name = ?`,
+		Param: func(int) string {
+			return "?"
+		},
+	}
+)
+
+// DB минимальный набор методов соединения, необходимый для применения миграций
+// Tx выполняет f в транзакции, которая не идет одновременно с другими транзакциями миграций,
+// в том числе с миграциями других серверов, работающих с той же базой
+type DB interface {
+	Exec(ctx context.Context, query string, args ...any) error
+	QueryInt(ctx context.Context, query string, args ...any) (int, error)
+	Tx(ctx context.Context, f func(tx DB) error) error
+}
+
+// Apply применение миграций к таблице
+// vars - значения подстановок, значение TableVar обязательно
+// Версия схемы хранится для каждой таблицы отдельно, если таблица была удалена - миграции применяются заново.
+// Каждая миграция выполняется в своей транзакции вместе с записью новой версии, а версия перечитывается
+// после получения блокировки: несколько серверов, запущенных одновременно, не применят миграцию дважды,
+// а после ошибки применение продолжится с первой непримененной миграции
+func Apply(ctx context.Context, db DB, d Dialect, list []Migration, vars map[string]string) error {
+	table := vars[TableVar]
+
+	err := db.Tx(ctx, func(tx DB) error {
+		return tx.Exec(ctx, `create table if not exists `+VersionsTableName+`
+		(
+			table_name varchar(255) primary key,
+			version integer not null
+		);`)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range list {
+		err = db.Tx(ctx, func(tx DB) error {
+			version, err := currentVersion(ctx, tx, d, table)
+			if err != nil || m.Version <= version {
+				return err
+			}
+
+			if m.Dialect == "" || m.Dialect == d.Name {
+				if err = tx.Exec(ctx, Render(m.Query, d, vars)); err != nil {
+					return err
+				}
+			}
+
+			return setVersion(ctx, tx, d, table, m.Version)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Latest версия схемы после применения всех миграций списка
+func Latest(list []Migration) int {
+	res := 0
+	for _, m := range list {
+		res = max(res, m.Version)
+	}
+
+	return res
+}
+
+// Render подстановка имен таблиц и особенностей диалекта в текст запроса
+func Render(query string, d Dialect, vars map[string]string) string {
+	query = strings.ReplaceAll(query, serialVar, d.Serial)
+	for k, v := range vars {
+		query = strings.ReplaceAll(query, k, v)
+	}
+
+	return query
+}
+
+func currentVersion(ctx context.Context, db DB, d Dialect, table string) (int, error) {
+	exists, err := db.QueryInt(ctx, d.TableExists, table)
+	if err != nil {
+		return 0, err
+	}
+
+	if exists == 0 {
+		return 0, nil
+	}
+
+	return db.QueryInt(ctx, `select coalesce(max(version), 0) from `+VersionsTableName+` where table_name = `+d.Param(1), table)
+}
+
+func setVersion(ctx context.Context, db DB, d Dialect, table string, version int) error {
+	err := db.Exec(ctx, `delete from `+VersionsTableName+` where table_name = `+d.Param(1), table)
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(ctx, `insert into `+VersionsTableName+` (table_name, version) values (`+d.Param(1)+`, `+d.Param(2)+`)`, table, version)
+}
@@ -0,0 +1,307 @@
+package migrations
+
+// Users миграции таблицы пользователей
+var Users = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id    #SERIAL#,
+			login  varchar not null,
+			password varchar not null
+		);`,
+	},
+	{
+		// ключи для обмена записями, закрытый ключ зашифрован клиентом
+		Version: 2,
+		Query: `alter table #T# add column public_key varchar;
+		alter table #T# add column private_key varchar;`,
+	},
+	{
+		// удаление учетной записи с отсрочкой: время окончательного удаления и отзыв выданных раньше токенов (unix)
+		Version: 3,
+		Query: `alter table #T# add column purge_at bigint;
+		alter table #T# add column sessions_after bigint;`,
+	},
+}
+
+// File миграции таблицы файлов
+var File = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id    #SERIAL#,
+			name varchar(255) not null,
+			path varchar(255) not null
+		);`,
+	},
+}
+
+// Data миграции таблицы данных пользователей
+var Data = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id    #SERIAL#,
+			name varchar(255) not null,
+			uid      integer not null
+        		constraint user___fk
+            		references #UT#,
+			file_id   integer
+			    constraint data___fk_file
+			    references #FT#,
+    		login    varchar,
+    		pass     varchar,
+    		text     text,
+    		card_num varchar,
+    		meta     varchar,
+    		version integer not null,
+    		constraint #T#_name_unique UNIQUE (name, uid)
+		);`,
+	},
+	{
+		Version: 2,
+		Query: `create index if not exists #T#_uid_name_idx on #T# (uid, name, id);
+		create index if not exists #T#_uid_id_idx on #T# (uid, id);`,
+	},
+	{
+		Version: 3,
+		Query: `alter table #T# add column folder_id integer;
+		create table if not exists #T#_tag
+		(
+			data_id integer not null
+				constraint #T#_tag___fk_data
+				references #T# on delete cascade,
+			tag varchar(1024) not null,
+			primary key (data_id, tag)
+		);
+		create index if not exists #T#_tag_tag_idx on #T#_tag (tag);`,
+	},
+	{
+		// зашифрованное название длиннее исходного, в SQLite длина varchar не ограничивается
+		Version: 4,
+		Dialect: Postgres.Name,
+		Query:   `alter table #T# alter column name type varchar(1024);`,
+	},
+	{
+		Version: 5,
+		Query: `alter table #T# add column name_hash varchar(128);
+		create unique index if not exists #T#_name_hash_unique on #T# (uid, name_hash) where name_hash is not null;`,
+	},
+	{
+		Version: 6,
+		Query:   `alter table #T# add column card_exp varchar;`,
+	},
+	{
+		Version: 7,
+		Query:   `alter table #T# add column otp varchar;`,
+	},
+	{
+		Version: 8,
+		Query: `alter table #T# add column record_key varchar;
+		create table if not exists #T#_share
+		(
+			data_id integer not null
+				constraint #T#_share___fk_data
+				references #T# on delete cascade,
+			uid integer not null
+				constraint #T#_share___fk_user
+				references #UT#,
+			record_key varchar not null,
+			permission integer not null,
+			primary key (data_id, uid)
+		);
+		create index if not exists #T#_share_uid_idx on #T#_share (uid);`,
+	},
+}
+
+// Folder миграции таблицы папок пользователей
+var Folder = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id    #SERIAL#,
+			uid   integer not null
+				constraint #T#___fk_user
+				references #UT#,
+			parent_id integer
+				constraint #T#___fk_parent
+				references #T# on delete cascade,
+			name  varchar(1024) not null
+		);
+		create index if not exists #T#_uid_idx on #T# (uid);`,
+	},
+}
+
+// Organization миграции таблиц организаций и их участников
+var Organization = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id    #SERIAL#,
+			vault_uid integer not null
+				constraint #T#___fk_vault
+				references #UT#,
+			name  varchar(1024) not null,
+			key_version integer not null
+		);
+		create table if not exists #T#_member
+		(
+			org_id integer not null
+				constraint #T#_member___fk_org
+				references #T# on delete cascade,
+			uid integer not null
+				constraint #T#_member___fk_user
+				references #UT#,
+			role integer not null,
+			collection_key varchar not null,
+			key_version integer not null,
+			accepted boolean not null,
+			primary key (org_id, uid)
+		);
+		create index if not exists #T#_member_uid_idx on #T#_member (uid);`,
+	},
+}
+
+// Emergency миграции таблицы экстренного доступа
+var Emergency = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id    #SERIAL#,
+			grantor_uid integer not null
+				constraint #T#___fk_grantor
+				references #UT# on delete cascade,
+			grantee_uid integer not null
+				constraint #T#___fk_grantee
+				references #UT# on delete cascade,
+			state integer not null,
+			wait_seconds bigint not null,
+			vault_key varchar not null,
+			requested_at bigint,
+			unique (grantor_uid, grantee_uid)
+		);
+		create index if not exists #T#_grantee_idx on #T# (grantee_uid);`,
+	},
+}
+
+// SecretShare миграции таблицы одноразовых ссылок на секреты
+var SecretShare = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id varchar primary key,
+			uid integer not null
+				constraint #T#___fk_user
+				references #UT# on delete cascade,
+			blob text not null,
+			expires_at bigint not null,
+			max_views integer not null,
+			views integer not null
+		);
+		create index if not exists #T#_expires_idx on #T# (expires_at);`,
+	},
+}
+
+// Audit миграции журнала аудита
+// журнал только дополняется: изменение и удаление событий запрещено триггерами,
+// внешних ключей нет, чтобы события оставались после удаления записей и пользователей
+var Audit = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id    #SERIAL#,
+			uid integer not null,
+			actor_uid integer not null,
+			actor varchar not null,
+			ip varchar not null,
+			action integer not null,
+			data_id integer not null,
+			outcome integer not null,
+			created_at bigint not null
+		);
+		create index if not exists #T#_uid_idx on #T# (uid, id);
+		create index if not exists #T#_actor_idx on #T# (actor_uid, id);`,
+	},
+	{
+		Version: 2,
+		Dialect: Postgres.Name,
+		Query: `create or replace function #T#_append_only() returns trigger as $$
+		begin
+			raise exception 'audit events are append-only';
+		end;
+		$$ language plpgsql;
+		drop trigger if exists #T#_append_only on #T#;
+		create trigger #T#_append_only before update or delete on #T# for each row execute procedure #T#_append_only();`,
+	},
+	{
+		Version: 3,
+		Dialect: SQLite.Name,
+		Query: `create trigger if not exists #T#_no_update before update on #T#
+		begin
+			select raise(abort, 'audit events are append-only');
+		end;
+		create trigger if not exists #T#_no_delete before delete on #T#
+		begin
+			select raise(abort, 'audit events are append-only');
+		end;`,
+	},
+	{
+		// цепочка хешей событий и подписанные ключом сервера отметки состояния журнала
+		Version: 4,
+		Query: `alter table #T# add column prev_hash varchar;
+		alter table #T# add column hash varchar;
+		create table if not exists #T#_checkpoint
+		(
+			id    #SERIAL#,
+			event_id integer not null,
+			hash varchar not null,
+			events bigint not null,
+			created_at bigint not null,
+			signature varchar not null
+		);`,
+	},
+	{
+		Version: 5,
+		Dialect: Postgres.Name,
+		Query: `drop trigger if exists #T#_checkpoint_append_only on #T#_checkpoint;
+		create trigger #T#_checkpoint_append_only before update or delete on #T#_checkpoint for each row execute procedure #T#_append_only();`,
+	},
+	{
+		Version: 6,
+		Dialect: SQLite.Name,
+		Query: `create trigger if not exists #T#_checkpoint_no_update before update on #T#_checkpoint
+		begin
+			select raise(abort, 'audit events are append-only');
+		end;
+		create trigger if not exists #T#_checkpoint_no_delete before delete on #T#_checkpoint
+		begin
+			select raise(abort, 'audit events are append-only');
+		end;`,
+	},
+}
+
+// RateLimit миграции общего для серверов состояния ограничения попыток входа и регистрации
+// время хранится в миллисекундах, 0 - не задано
+var RateLimit = []Migration{
+	{
+		Version: 1,
+		Query: `create table if not exists #T#
+		(
+			id varchar primary key,
+			tokens double precision not null default 0,
+			updated_at bigint not null default 0,
+			failures integer not null default 0,
+			failed_at bigint not null default 0,
+			locked_until bigint not null default 0
+		);
+		create index if not exists #T#_updated_idx on #T# (updated_at);`,
+	},
+}
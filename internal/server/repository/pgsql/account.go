@@ -0,0 +1,105 @@
+package pgsql
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AccountRepository структура для удаления учетных записей вместе со всеми данными
+// своих таблиц у нее нет, удаление затрагивает таблицы пользователей, данных, файлов, папок и организаций
+type AccountRepository struct {
+	DBPoll *pgxpool.Pool
+	usersTableName,
+	dataTableName,
+	fileTableName,
+	folderTableName,
+	orgTableName string
+}
+
+func NewAccountRepository(pool *pgxpool.Pool, usersTableName, dataTableName, fileTableName, folderTableName, orgTableName string) *AccountRepository {
+	return &AccountRepository{
+		DBPoll:          pool,
+		usersTableName:  usersTableName,
+		dataTableName:   dataTableName,
+		fileTableName:   fileTableName,
+		folderTableName: folderTableName,
+		orgTableName:    orgTableName,
+	}
+}
+
+// Purge удаление в одной транзакции организаций purge.OrgIDs и учетных записей purge.UIDs: записей с тегами,
+// выданного и полученного доступа к записям, файлов, папок и участия в организациях; экстренный доступ и ссылки
+// на секреты удаляются каскадно. возвращаются пути файлов, которые нужно удалить с диска после фиксации транзакции
+func (a *AccountRepository) Purge(ctx context.Context, purge domain.AccountPurge) ([]string, error) {
+	var paths []string
+
+	err := pgx.BeginFunc(ctx, a.DBPoll, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, a.setTableName(`delete from #OT# where id = any($1)`), purge.OrgIDs); err != nil {
+			return err
+		}
+
+		for _, uid := range purge.UIDs {
+			var ids []uint64
+
+			rows, err := tx.Query(ctx, a.setTableName(`select f.id, f.path from #FT# f join #DT# d on d.file_id = f.id where d.uid = $1`), uid)
+			if err != nil {
+				return err
+			}
+
+			for rows.Next() {
+				var (
+					id   uint64
+					path string
+				)
+
+				if err = rows.Scan(&id, &path); err != nil {
+					rows.Close()
+					return err
+				}
+
+				ids = append(ids, id)
+				paths = append(paths, path)
+			}
+
+			rows.Close()
+			if err = rows.Err(); err != nil {
+				return err
+			}
+
+			for _, query := range []string{
+				`delete from #OT#_member where uid = $1`,
+				`delete from #DT#_share where uid = $1`,
+				`delete from #DT# where uid = $1`,
+				`delete from #FOT# where uid = $1`,
+			} {
+				if _, err = tx.Exec(ctx, a.setTableName(query), uid); err != nil {
+					return err
+				}
+			}
+
+			if _, err = tx.Exec(ctx, a.setTableName(`delete from #FT# where id = any($1)`), ids); err != nil {
+				return err
+			}
+
+			if _, err = tx.Exec(ctx, a.setTableName(`delete from #UT# where id = $1`), uid); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+func (a *AccountRepository) setTableName(query string) string {
+	return strings.NewReplacer("#UT#", a.usersTableName, "#DT#", a.dataTableName, "#FT#", a.fileTableName,
+		"#FOT#", a.folderTableName, "#OT#", a.orgTableName).Replace(query)
+}
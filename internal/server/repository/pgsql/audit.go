@@ -0,0 +1,145 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/internal/server/repository/dataquery"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const AuditTableName = "audit_events"
+
+// AuditRepository структура для взаимодействия с журналом аудита
+// журнал только дополняется, изменение и удаление событий и отметок запрещено триггерами
+type AuditRepository struct {
+	DBPoll    *pgxpool.Pool
+	tableName string
+}
+
+func NewAuditRepository(ctx context.Context, pool *pgxpool.Pool, tableName string) (*AuditRepository, error) {
+	err := migrate(ctx, pool, migrations.Audit, map[string]string{
+		migrations.TableVar: tableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditRepository{
+		DBPoll:    pool,
+		tableName: tableName,
+	}, nil
+}
+
+// Insert добавление события в конец цепочки, в event заполняются ИД и хеши
+// добавления в журнал упорядочиваются транзакционной advisory-блокировкой по имени таблицы, чтобы два события
+// не получили один предыдущий хеш; сама таблица не блокируется, поэтому чтение и остальные запросы не ждут
+func (a *AuditRepository) Insert(ctx context.Context, event *domain.AuditEvent) error {
+	return pgx.BeginFunc(ctx, a.DBPoll, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `select pg_advisory_xact_lock(hashtext($1))`, a.tableName); err != nil {
+			return err
+		}
+
+		err := tx.QueryRow(ctx, a.setTableName(`select coalesce(hash, '') from #T# order by id desc limit 1`)).Scan(&event.PrevHash)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		event.Hash = event.ChainHash()
+
+		return tx.QueryRow(ctx, a.setTableName(`insert into #T# (uid, actor_uid, actor, ip, action, data_id, outcome, created_at, prev_hash, hash) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) returning id`),
+			event.UID, event.ActorUID, event.Actor, event.IP, event.Action, event.DataID, event.Outcome, event.CreatedAt.Unix(), event.PrevHash, event.Hash).Scan(&event.ID)
+	})
+}
+
+// GetList события журнала пользователя uid по фильтру, от новых к старым
+func (a *AuditRepository) GetList(ctx context.Context, uid uint64, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	query, args := dataquery.AuditList(migrations.Postgres.Param, uid, filter)
+
+	return a.query(ctx, query, args...)
+}
+
+// Scan страница всех событий журнала после события afterID по возрастанию ИД, для проверки цепочки
+func (a *AuditRepository) Scan(ctx context.Context, afterID uint64, limit int) ([]domain.AuditEvent, error) {
+	return a.query(ctx, `select `+dataquery.AuditColumns+` from #T# where id > $1 order by id limit $2`, afterID, limit)
+}
+
+// Head неподписанная отметка последнего события журнала, EventID = 0 - журнал пуст
+func (a *AuditRepository) Head(ctx context.Context) (domain.AuditCheckpoint, error) {
+	var head domain.AuditCheckpoint
+
+	err := a.DBPoll.QueryRow(ctx, a.setTableName(`select id, coalesce(hash, ''), (select count(*) from #T#) from #T# order by id desc limit 1`)).
+		Scan(&head.EventID, &head.Hash, &head.Events)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return head, nil
+	}
+
+	return head, err
+}
+
+// InsertCheckpoint сохранение подписанной отметки, в checkpoint заполняется ИД
+func (a *AuditRepository) InsertCheckpoint(ctx context.Context, checkpoint *domain.AuditCheckpoint) error {
+	return a.DBPoll.QueryRow(ctx, a.setTableName(`insert into #T#_checkpoint (event_id, hash, events, created_at, signature) values ($1, $2, $3, $4, $5) returning id`),
+		checkpoint.EventID, checkpoint.Hash, checkpoint.Events, checkpoint.CreatedAt.Unix(), checkpoint.Signature).Scan(&checkpoint.ID)
+}
+
+// GetCheckpoints все отметки журнала по возрастанию ИД
+func (a *AuditRepository) GetCheckpoints(ctx context.Context) ([]domain.AuditCheckpoint, error) {
+	rows, err := a.DBPoll.Query(ctx, a.setTableName(`select id, event_id, hash, events, created_at, signature from #T#_checkpoint order by id`))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.AuditCheckpoint
+	for rows.Next() {
+		var (
+			c         domain.AuditCheckpoint
+			createdAt int64
+		)
+
+		if err = rows.Scan(&c.ID, &c.EventID, &c.Hash, &c.Events, &createdAt, &c.Signature); err != nil {
+			return nil, err
+		}
+
+		c.CreatedAt = time.Unix(createdAt, 0)
+		res = append(res, c)
+	}
+
+	return res, rows.Err()
+}
+
+func (a *AuditRepository) query(ctx context.Context, query string, args ...any) ([]domain.AuditEvent, error) {
+	rows, err := a.DBPoll.Query(ctx, a.setTableName(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.AuditEvent
+	for rows.Next() {
+		var (
+			event     domain.AuditEvent
+			createdAt int64
+		)
+
+		err = rows.Scan(&event.ID, &event.UID, &event.ActorUID, &event.Actor, &event.IP, &event.Action, &event.DataID, &event.Outcome, &createdAt, &event.PrevHash, &event.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		event.CreatedAt = time.Unix(createdAt, 0)
+		res = append(res, event)
+	}
+
+	return res, rows.Err()
+}
+
+func (a *AuditRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", a.tableName)
+}
@@ -0,0 +1,354 @@
+package pgsql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gophkeeper/internal/server/backup"
+	"gophkeeper/internal/server/repository/migrations"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// backupTable таблица в порядке восстановления (сначала те, на которые ссылаются внешние ключи)
+// order - порядок строк в выгрузке, serial - есть ли автоинкрементный ИД
+type backupTable struct {
+	name   string
+	order  string
+	serial bool
+}
+
+var backupTables = []backupTable{
+	{name: UsersTableName, order: "id", serial: true},
+	{name: OrganizationTableName, order: "id", serial: true},
+	{name: OrganizationTableName + "_member", order: "org_id, uid"},
+	{name: EmergencyTableName, order: "id", serial: true},
+	{name: FileTableName, order: "id", serial: true},
+	{name: FolderTableName, serial: true},
+	{name: DataTableName, order: "id", serial: true},
+	{name: DataTableName + "_tag", order: "data_id, tag"},
+	{name: DataTableName + "_share", order: "data_id, uid"},
+	{name: AuditTableName, order: "id", serial: true},
+	{name: AuditTableName + "_checkpoint", order: "id", serial: true},
+}
+
+// backupSchema миграции таблиц для проверки версии схемы архива
+var backupSchema = map[string][]migrations.Migration{
+	UsersTableName:        migrations.Users,
+	OrganizationTableName: migrations.Organization,
+	EmergencyTableName:    migrations.Emergency,
+	FileTableName:         migrations.File,
+	FolderTableName:       migrations.Folder,
+	DataTableName:         migrations.Data,
+	AuditTableName:        migrations.Audit,
+}
+
+// BackupDB снимок и восстановление базы Postgres для резервного копирования сервера
+// таблицы выгружаются командой COPY в формате CSV внутри одной транзакции repeatable read
+type BackupDB struct {
+	DBPoll *pgxpool.Pool
+}
+
+func NewBackupDB(pool *pgxpool.Pool) *BackupDB {
+	return &BackupDB{DBPoll: pool}
+}
+
+// Storage тип хранилища
+func (b *BackupDB) Storage() string {
+	return migrations.Postgres.Name
+}
+
+// Dump выгрузка всех таблиц в файлы <таблица>.csv
+func (b *BackupDB) Dump(ctx context.Context, dir string, relPath func(string) (string, error)) (backup.Snapshot, error) {
+	snap := backup.Snapshot{Schema: make(map[string]int)}
+
+	tx, err := b.DBPoll.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return snap, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `select table_name, version from `+migrations.VersionsTableName)
+	if err != nil {
+		return snap, err
+	}
+
+	for rows.Next() {
+		var (
+			table   string
+			version int
+		)
+
+		if err = rows.Scan(&table, &version); err != nil {
+			return snap, err
+		}
+
+		if _, ok := backupSchema[table]; ok {
+			snap.Schema[table] = version
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return snap, err
+	}
+
+	for _, t := range backupTables {
+		var buf bytes.Buffer
+
+		if err = copyTable(ctx, tx, t, &buf); err != nil {
+			return snap, err
+		}
+
+		raw := buf.Bytes()
+
+		if t.name == FileTableName {
+			if raw, snap.Files, err = relativePaths(raw, relPath); err != nil {
+				return snap, err
+			}
+		}
+
+		name := t.name + ".csv"
+		if err = os.WriteFile(filepath.Join(dir, name), raw, 0600); err != nil {
+			return snap, err
+		}
+
+		snap.Entries = append(snap.Entries, name)
+	}
+
+	return snap, nil
+}
+
+// Restore загрузка выгрузки в пустые таблицы
+// таблицы должны быть созданы миграциями, колонки берутся из заголовка CSV, поэтому выгрузка старой схемы тоже загружается
+func (b *BackupDB) Restore(ctx context.Context, dir string, schema map[string]int, absPath func(string) string) error {
+	current := make(map[string]int, len(backupSchema))
+	for table, list := range backupSchema {
+		current[table] = migrations.Latest(list)
+	}
+
+	if err := backup.CheckSchema(schema, current); err != nil {
+		return err
+	}
+
+	return pgx.BeginFunc(ctx, b.DBPoll, func(tx pgx.Tx) error {
+		for _, t := range backupTables {
+			var exists bool
+			if err := tx.QueryRow(ctx, `select exists (select 1 from `+t.name+`)`).Scan(&exists); err != nil {
+				return err
+			}
+
+			if exists {
+				return fmt.Errorf("%w: table %s has rows", backup.ErrNotEmpty, t.name)
+			}
+		}
+
+		for _, t := range backupTables {
+			if !inArchive(schema, t.name) {
+				continue
+			}
+
+			if err := loadTable(ctx, tx, t, filepath.Join(dir, t.name+".csv")); err != nil {
+				return err
+			}
+
+			if t.serial {
+				query := `select setval(pg_get_serial_sequence('` + t.name + `', 'id'), coalesce(max(id), 0) + 1, false) from ` + t.name
+				if _, err := tx.Exec(ctx, query); err != nil {
+					return err
+				}
+			}
+		}
+
+		return absolutePaths(ctx, tx, absPath)
+	})
+}
+
+// lateTable таблица схемы, по которой версионируется таблица, появившаяся позже первой версии архива,
+// и версия схемы, в которой она появилась
+type lateTable struct {
+	schema  string
+	version int
+}
+
+// lateTables таблицы, появившиеся позже первой версии архива
+var lateTables = map[string]lateTable{
+	OrganizationTableName:             {OrganizationTableName, 1},
+	OrganizationTableName + "_member": {OrganizationTableName, 1},
+	EmergencyTableName:                {EmergencyTableName, 1},
+	AuditTableName:                    {AuditTableName, 1},
+	AuditTableName + "_checkpoint":    {AuditTableName, 4},
+}
+
+// inArchive есть ли таблица в архиве: в старых архивах таблиц, добавленных позже, нет
+func inArchive(schema map[string]int, table string) bool {
+	late, ok := lateTables[table]
+	if !ok {
+		return true
+	}
+
+	return schema[late.schema] >= late.version
+}
+
+func copyTable(ctx context.Context, tx pgx.Tx, t backupTable, w io.Writer) error {
+	cols, err := tableColumns(ctx, tx, t.name)
+	if err != nil {
+		return err
+	}
+
+	list := quoteColumns(cols)
+	query := `select ` + list + ` from ` + t.name + ` order by ` + t.order
+
+	// папку можно перенести в другую, поэтому родитель может иметь больший ИД, чем вложенная папка
+	if t.name == FolderTableName {
+		query = `with recursive tree as (
+			select *, 0 as depth from ` + t.name + ` where parent_id is null
+			union all
+			select f.*, tree.depth + 1 from ` + t.name + ` f join tree on f.parent_id = tree.id
+		)
+		select ` + list + ` from tree order by depth, id`
+	}
+
+	_, err = tx.Conn().PgConn().CopyTo(ctx, w, `copy (`+query+`) to stdout with (format csv, header true)`)
+
+	return err
+}
+
+func loadTable(ctx context.Context, tx pgx.Tx, t backupTable, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// имена колонок не содержат переводов строки, поэтому заголовок - первая строка файла
+	header, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("%w: %s has no header", backup.ErrFormat, filepath.Base(path))
+	}
+
+	cols, err := csv.NewReader(strings.NewReader(header)).Read()
+	if err != nil {
+		return fmt.Errorf("%w: %s", backup.ErrFormat, err.Error())
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	query := `copy ` + t.name + ` (` + quoteColumns(cols) + `) from stdin with (format csv, header true)`
+	_, err = tx.Conn().PgConn().CopyFrom(ctx, f, query)
+
+	return err
+}
+
+func tableColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(ctx, `select column_name from information_schema.columns
+		where table_schema = current_schema() and table_name = $1 order by ordinal_position`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	cols, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cols) == 0 {
+		return nil, errors.New("table not found: " + table)
+	}
+
+	return cols, nil
+}
+
+func quoteColumns(cols []string) string {
+	res := make([]string, 0, len(cols))
+	for _, c := range cols {
+		res = append(res, pgx.Identifier{c}.Sanitize())
+	}
+
+	return strings.Join(res, ", ")
+}
+
+// relativePaths замена путей в выгрузке таблицы файлов на относительные
+// все значения записываются в кавычках: в CSV формате COPY пустое значение без кавычек означает NULL
+func relativePaths(raw []byte, relPath func(string) (string, error)) ([]byte, []string, error) {
+	records, err := csv.NewReader(bytes.NewReader(raw)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, nil, fmt.Errorf("bad %s table dump", FileTableName)
+	}
+
+	pathCol := -1
+	for i, c := range records[0] {
+		if c == "path" {
+			pathCol = i
+		}
+	}
+
+	if pathCol < 0 {
+		return nil, nil, fmt.Errorf("no path column in %s table", FileTableName)
+	}
+
+	var (
+		buf   bytes.Buffer
+		files []string
+	)
+
+	for i, rec := range records {
+		if i > 0 {
+			if rec[pathCol], err = relPath(rec[pathCol]); err != nil {
+				return nil, nil, err
+			}
+
+			files = append(files, rec[pathCol])
+		}
+
+		for j, v := range rec {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+
+			buf.WriteString(`"` + strings.ReplaceAll(v, `"`, `""`) + `"`)
+		}
+
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), files, nil
+}
+
+func absolutePaths(ctx context.Context, tx pgx.Tx, absPath func(string) string) error {
+	rows, err := tx.Query(ctx, `select id, path from `+FileTableName)
+	if err != nil {
+		return err
+	}
+
+	type file struct {
+		id   uint64
+		path string
+	}
+
+	files, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (file, error) {
+		var f file
+		err := row.Scan(&f.id, &f.path)
+		return f, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if _, err = tx.Exec(ctx, `update `+FileTableName+` set path = $1 where id = $2`, absPath(f.path), f.id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
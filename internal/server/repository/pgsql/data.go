@@ -3,6 +3,8 @@ package pgsql
 
 import (
 	"context"
+	"gophkeeper/internal/server/repository/dataquery"
+	"gophkeeper/internal/server/repository/migrations"
 	"gophkeeper/server/domain"
 	"strings"
 
@@ -32,38 +34,53 @@ func NewDataRepository(ctx context.Context, pool *pgxpool.Pool, tableName, fileT
 	}, nil
 }
 
-// Insert добавление новой записи
+// Insert добавление новой записи вместе с тегами
 func (d *DataRepository) Insert(ctx context.Context, data *domain.Data) error {
-	query := d.setTableName(`insert into #T# (name, uid, login, pass, text, card_num, meta, version, file_id) values ($1, $2, $3, $4, $5, $6, $7, $8, $9) returning id`)
+	query := d.setTableName(`insert into #T# (name, name_hash, uid, login, pass, text, card_num, card_exp, otp, meta, version, file_id, folder_id, record_key) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) returning id`)
 
-	err := d.DBPoll.QueryRow(ctx, query, data.Name, data.UID, data.Login, data.Pass, data.Text, data.CardNum, data.Meta, data.Version, data.FileID).Scan(&data.ID)
-	if err != nil {
-		return err
-	}
+	return pgx.BeginFunc(ctx, d.DBPoll, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, data.Name, data.NameHash, data.UID, data.Login, data.Pass, data.Text, data.CardNum, data.CardExp, data.Otp, data.Meta, data.Version, data.FileID, data.FolderID, data.RecordKey).Scan(&data.ID)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		return d.setTags(ctx, tx, data.ID, data.Tags)
+	})
 }
 
-// Update обновление записи
+// Update обновление записи, теги записи заменяются целиком
 func (d *DataRepository) Update(ctx context.Context, data domain.Data) error {
 	query := d.setTableName(`update #T# set
 		name = $1, 
-		login = $2,
-		pass = $3,
-		text = $4,
-		card_num = $5,
-		meta = $6,
-		version = $7
-		where id = $8
+		name_hash = $2,
+		login = $3,
+		pass = $4,
+		text = $5,
+		card_num = $6,
+		card_exp = $7,
+		otp = $8,
+		meta = $9,
+		version = $10,
+		folder_id = $11,
+		record_key = $12
+		where id = $13
 	`)
 
-	_, err := d.DBPoll.Exec(ctx, query, data.Name, data.Login, data.Pass, data.Text, data.CardNum, data.Meta, data.Version, data.ID)
+	return pgx.BeginFunc(ctx, d.DBPoll, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query, data.Name, data.NameHash, data.Login, data.Pass, data.Text, data.CardNum, data.CardExp, data.Otp, data.Meta, data.Version, data.FolderID, data.RecordKey, data.ID)
+		if err != nil {
+			return err
+		}
 
-	if err != nil {
-		return err
-	}
+		return d.setTags(ctx, tx, data.ID, data.Tags)
+	})
+}
 
-	return nil
+// UnsetFolder убрать записи пользователя из перечисленных папок
+func (d *DataRepository) UnsetFolder(ctx context.Context, uid uint64, folderIDs []uint64) error {
+	query := d.setTableName(`update #T# set folder_id = null where uid = $1 and folder_id = any($2)`)
+	_, err := d.DBPoll.Exec(ctx, query, uid, folderIDs)
+	return err
 }
 
 // SetFile добавление файла
@@ -95,6 +112,18 @@ func (d *DataRepository) GetByNameAndUserID(ctx context.Context, uid uint64, nam
 	return data.ID, nil
 }
 
+// GetByNameHashAndUserID получить ИД записи по слепому индексу названия и ИД пользователя
+func (d *DataRepository) GetByNameHashAndUserID(ctx context.Context, uid uint64, nameHash string) (uint64, error) {
+	query := d.setTableName(`select * from #T# where uid = $1 and name_hash = $2`)
+
+	data, err := d.getOne(ctx, query, uid, nameHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return data.ID, nil
+}
+
 // Get получить запись из БД по ИД
 func (d *DataRepository) Get(ctx context.Context, id uint64) (*domain.Data, error) {
 	query := d.setTableName(`select * from #T# where id = $1`)
@@ -111,29 +140,63 @@ func (d *DataRepository) Get(ctx context.Context, id uint64) (*domain.Data, erro
 	return &row, nil
 }
 
-// GetByUser получить запись по ИД для пользователя
+// GetByUser получить запись по ИД для владельца или пользователя, которому предоставлен доступ
+// для чужой записи заполняется доступ, а папка и теги владельца не возвращаются
 func (d *DataRepository) GetByUser(ctx context.Context, id, uid uint64) (*domain.Data, error) {
-	query := d.setTableName(`select * from #T# where id = $1 and uid = $2`)
+	row, err := d.Get(ctx, id)
+	if err != nil || row == nil || row.UID == uid {
+		return row, err
+	}
+
+	share, err := d.GetShare(ctx, id, uid)
+	if err != nil || share == nil {
+		return nil, err
+	}
+
+	row.FolderID, row.Tags, row.Share = nil, nil, share
+
+	return row, nil
+}
 
-	row, err := d.getOne(ctx, query, id, uid)
+// GetShare получить доступ пользователя к записи, nil - если доступа нет
+func (d *DataRepository) GetShare(ctx context.Context, dataID, uid uint64) (*domain.DataShare, error) {
+	shares, err := d.getShares(ctx, uid, dataID)
 	if err != nil {
 		return nil, err
 	}
 
-	if row.ID == 0 {
+	share, ok := shares[dataID]
+	if !ok {
 		return nil, nil
 	}
 
-	return &row, nil
+	return &share, nil
+}
+
+// Share предоставить доступ к записи или изменить его
+func (d *DataRepository) Share(ctx context.Context, share domain.DataShare) error {
+	query := d.setTableName(`insert into #T#_share (data_id, uid, record_key, permission) values ($1, $2, $3, $4)
+		on conflict (data_id, uid) do update set record_key = excluded.record_key, permission = excluded.permission`)
+
+	_, err := d.DBPoll.Exec(ctx, query, share.DataID, share.UID, share.RecordKey, share.Permission)
+
+	return err
 }
 
-// GetList получить список для пользователя
-func (d *DataRepository) GetList(ctx context.Context, uid uint64) ([]domain.DataName, error) {
+// Unshare отозвать доступ пользователя к записи
+func (d *DataRepository) Unshare(ctx context.Context, dataID, uid uint64) error {
+	_, err := d.DBPoll.Exec(ctx, d.setTableName(`delete from #T#_share where data_id = $1 and uid = $2`), dataID, uid)
+	return err
+}
+
+// GetList получить список для пользователя с учетом фильтра
+func (d *DataRepository) GetList(ctx context.Context, uid uint64, filter domain.DataListFilter) ([]domain.DataName, error) {
 	var res []domain.DataName
 
-	query := d.setTableName(`select id, name from #T# where uid = $1`)
+	query, args := dataquery.List(migrations.Postgres.Param, uid, filter)
+	query = d.setTableName(query)
 
-	rows, err := d.DBPoll.Query(ctx, query, uid)
+	rows, err := d.DBPoll.Query(ctx, query, args...)
 	if err != nil {
 		return res, err
 	}
@@ -143,6 +206,33 @@ func (d *DataRepository) GetList(ctx context.Context, uid uint64) ([]domain.Data
 		return res, err
 	}
 
+	var own, shared []uint64
+	for _, dn := range res {
+		if dn.UID == uid {
+			own = append(own, dn.ID)
+		} else {
+			shared = append(shared, dn.ID)
+		}
+	}
+
+	tags, err := d.getTags(ctx, own...)
+	if err != nil {
+		return res, err
+	}
+
+	shares, err := d.getShares(ctx, uid, shared...)
+	if err != nil {
+		return res, err
+	}
+
+	for i := range res {
+		res[i].Tags = tags[res[i].ID]
+
+		if share, ok := shares[res[i].ID]; ok {
+			res[i].FolderID, res[i].Share = nil, &share
+		}
+	}
+
 	return res, nil
 }
 
@@ -165,43 +255,97 @@ func (d *DataRepository) getOne(ctx context.Context, query string, args ...inter
 	}
 
 	for _, data = range datas {
+		tags, err := d.getTags(ctx, data.ID)
+		if err != nil {
+			return data, err
+		}
+
+		data.Tags = tags[data.ID]
+
 		return data, nil
 	}
 
 	return
 }
 
-func (d *DataRepository) setTableName(query string) string {
-	return strings.ReplaceAll(query, "#T#", d.tableName)
+// getTags получить теги записей, сгруппированные по ИД записи
+func (d *DataRepository) getTags(ctx context.Context, ids ...uint64) (map[uint64][]string, error) {
+	res := make(map[uint64][]string, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	query, args := dataquery.Tags(migrations.Postgres.Param, ids)
+
+	rows, err := d.DBPoll.Query(ctx, d.setTableName(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint64
+		var tag string
+		if err = rows.Scan(&id, &tag); err != nil {
+			return nil, err
+		}
+
+		res[id] = append(res[id], tag)
+	}
+
+	return res, rows.Err()
 }
 
-func createDataTable(ctx context.Context, pool *pgxpool.Pool, tableName, usersTableName, fileTableName string) error {
-	query := strings.ReplaceAll(`create table if not exists #T#
-		(
-			id    serial primary key,
-			name varchar(255) not null,
-			uid      integer not null
-        		constraint user___fk
-            		references #UT#,
-			file_id   integer
-			    constraint data___fk_file
-			    references #FT#,
-    		login    varchar,
-    		pass     varchar,
-    		text     text,
-    		card_num varchar,
-    		meta     varchar,
-    		version integer not null,
-    		constraint #T#_name_unique UNIQUE (name, uid)
-		);`, "#T#", tableName)
-
-	query = strings.ReplaceAll(query, "#FT#", fileTableName)
-	query = strings.ReplaceAll(query, "#UT#", usersTableName)
-
-	_, err := pool.Exec(ctx, query)
+// getShares получить доступы пользователя к записям, сгруппированные по ИД записи
+func (d *DataRepository) getShares(ctx context.Context, uid uint64, ids ...uint64) (map[uint64]domain.DataShare, error) {
+	res := make(map[uint64]domain.DataShare, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	query, args := dataquery.Shares(migrations.Postgres.Param, uid, ids)
 
+	rows, err := d.DBPoll.Query(ctx, d.setTableName(query), args...)
 	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var share domain.DataShare
+		if err = rows.Scan(&share.DataID, &share.UID, &share.RecordKey, &share.Permission); err != nil {
+			return nil, err
+		}
+
+		res[share.DataID] = share
+	}
+
+	return res, rows.Err()
+}
+
+// setTags замена тегов записи
+func (d *DataRepository) setTags(ctx context.Context, tx pgx.Tx, id uint64, tags []string) error {
+	if _, err := tx.Exec(ctx, d.setTableName(`delete from #T#_tag where data_id = $1`), id); err != nil {
 		return err
 	}
+
+	for _, tag := range tags {
+		if _, err := tx.Exec(ctx, d.setTableName(`insert into #T#_tag (data_id, tag) values ($1, $2) on conflict do nothing`), id, tag); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+func (d *DataRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", d.tableName)
+}
+
+func createDataTable(ctx context.Context, pool *pgxpool.Pool, tableName, usersTableName, fileTableName string) error {
+	return migrate(ctx, pool, migrations.Data, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+		migrations.FileTableVar:  fileTableName,
+	})
+}
@@ -0,0 +1,154 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const EmergencyTableName = "emergency"
+
+const grantColumns = `e.id, e.grantor_uid, e.grantee_uid, gr.login, ge.login, e.state, e.wait_seconds, e.vault_key, e.requested_at`
+
+// EmergencyRepository структура для взаимодействия с таблицей экстренного доступа
+type EmergencyRepository struct {
+	DBPoll *pgxpool.Pool
+	tableName,
+	usersTableName string
+}
+
+func NewEmergencyRepository(ctx context.Context, pool *pgxpool.Pool, tableName, usersTableName string) (*EmergencyRepository, error) {
+	err := migrate(ctx, pool, migrations.Emergency, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmergencyRepository{
+		DBPoll:         pool,
+		tableName:      tableName,
+		usersTableName: usersTableName,
+	}, nil
+}
+
+// Insert сохранение нового экстренного доступа
+func (e *EmergencyRepository) Insert(ctx context.Context, grant *domain.EmergencyGrant) error {
+	query := e.setTableName(`insert into #T# (grantor_uid, grantee_uid, state, wait_seconds, vault_key, requested_at)
+		values ($1, $2, $3, $4, $5, $6) returning id`)
+
+	return e.DBPoll.QueryRow(ctx, query, grant.GrantorUID, grant.GranteeUID, grant.State, int64(grant.Wait/time.Second),
+		grant.VaultKey, unixTime(grant.RequestedAt)).Scan(&grant.ID)
+}
+
+// Get получить экстренный доступ по ИД, если доступ не найден - возвращается nil
+func (e *EmergencyRepository) Get(ctx context.Context, id uint64) (*domain.EmergencyGrant, error) {
+	return e.getOne(ctx, `e.id = $1`, id)
+}
+
+// GetByPair получить экстренный доступ доверенного лица к хранилищу владельца, если доступа нет - возвращается nil
+func (e *EmergencyRepository) GetByPair(ctx context.Context, grantorUID, granteeUID uint64) (*domain.EmergencyGrant, error) {
+	return e.getOne(ctx, `e.grantor_uid = $1 and e.grantee_uid = $2`, grantorUID, granteeUID)
+}
+
+// GetByUser экстренные доступы, в которых пользователь владелец или доверенное лицо
+func (e *EmergencyRepository) GetByUser(ctx context.Context, uid uint64) ([]domain.EmergencyGrant, error) {
+	return e.getList(ctx, `e.grantor_uid = $1 or e.grantee_uid = $1`, uid)
+}
+
+// Update изменение состояния экстренного доступа, который все еще находится в состоянии state,
+// иначе возвращается domain.ErrGrantState
+func (e *EmergencyRepository) Update(ctx context.Context, grant domain.EmergencyGrant, state domain.EmergencyState) error {
+	tag, err := e.DBPoll.Exec(ctx, e.setTableName(`update #T# set state = $1, requested_at = $2 where id = $3 and state = $4`),
+		grant.State, unixTime(grant.RequestedAt), grant.ID, state)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrGrantState
+	}
+
+	return nil
+}
+
+// Delete удаление экстренного доступа
+func (e *EmergencyRepository) Delete(ctx context.Context, id uint64) error {
+	_, err := e.DBPoll.Exec(ctx, e.setTableName(`delete from #T# where id = $1`), id)
+	return err
+}
+
+// AutoApprove одобрение запросов, время ожидания которых истекло к моменту now, возвращается число одобренных запросов
+func (e *EmergencyRepository) AutoApprove(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := e.DBPoll.Exec(ctx, e.setTableName(`update #T# set state = $1 where state = $2 and requested_at + wait_seconds <= $3`),
+		domain.EmergencyAutoApproved, domain.EmergencyRequested, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (e *EmergencyRepository) getOne(ctx context.Context, where string, args ...any) (*domain.EmergencyGrant, error) {
+	list, err := e.getList(ctx, where, args...)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+func (e *EmergencyRepository) getList(ctx context.Context, where string, args ...any) ([]domain.EmergencyGrant, error) {
+	query := e.setTableName(`select ` + grantColumns + ` from #T# e join #UT# gr on gr.id = e.grantor_uid
+		join #UT# ge on ge.id = e.grantee_uid where ` + where + ` order by e.id`)
+
+	rows, err := e.DBPoll.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.EmergencyGrant, error) {
+		var (
+			g           domain.EmergencyGrant
+			wait        int64
+			requestedAt *int64
+		)
+
+		err := row.Scan(&g.ID, &g.GrantorUID, &g.GranteeUID, &g.Grantor, &g.Grantee, &g.State, &wait, &g.VaultKey, &requestedAt)
+
+		g.Wait = time.Duration(wait) * time.Second
+		if requestedAt != nil {
+			t := time.Unix(*requestedAt, 0)
+			g.RequestedAt = &t
+		}
+
+		return g, err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return res, err
+}
+
+func (e *EmergencyRepository) setTableName(query string) string {
+	return strings.NewReplacer("#T#", e.tableName, "#UT#", e.usersTableName).Replace(query)
+}
+
+// unixTime время в секундах unix для хранения в базе, nil - NULL
+func unixTime(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+
+	sec := t.Unix()
+
+	return &sec
+}
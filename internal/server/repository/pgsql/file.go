@@ -2,6 +2,7 @@ package pgsql
 
 import (
 	"context"
+	"gophkeeper/internal/server/repository/migrations"
 	"gophkeeper/server/domain"
 	"strings"
 
@@ -100,17 +101,7 @@ func (f *FileRepository) getOne(ctx context.Context, query string, args ...inter
 }
 
 func createFileTable(ctx context.Context, pool *pgxpool.Pool, tableName string) error {
-	query := strings.ReplaceAll(`create table if not exists #T#
-		(
-			id    serial primary key,
-			name varchar(255) not null,
-			path varchar(255) not null
-		);`, "#T#", tableName)
-
-	_, err := pool.Exec(ctx, query)
-
-	if err != nil {
-		return err
-	}
-	return nil
+	return migrate(ctx, pool, migrations.File, map[string]string{
+		migrations.TableVar: tableName,
+	})
 }
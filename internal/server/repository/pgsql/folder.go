@@ -0,0 +1,92 @@
+package pgsql
+
+import (
+	"context"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const FolderTableName = "folder"
+
+// FolderRepository структура для взаимодействия с таблицей папок
+type FolderRepository struct {
+	DBPoll    *pgxpool.Pool
+	tableName string
+}
+
+func NewFolderRepository(ctx context.Context, pool *pgxpool.Pool, tableName, usersTableName string) (*FolderRepository, error) {
+	err := migrate(ctx, pool, migrations.Folder, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FolderRepository{
+		DBPoll:    pool,
+		tableName: tableName,
+	}, nil
+}
+
+// Insert добавление папки
+func (f *FolderRepository) Insert(ctx context.Context, folder *domain.Folder) error {
+	query := f.setTableName(`insert into #T# (uid, parent_id, name) values ($1, $2, $3) returning id`)
+
+	return f.DBPoll.QueryRow(ctx, query, folder.UID, folder.ParentID, folder.Name).Scan(&folder.ID)
+}
+
+// Update переименование или перемещение папки
+func (f *FolderRepository) Update(ctx context.Context, folder domain.Folder) error {
+	query := f.setTableName(`update #T# set
+		parent_id = $1,
+		name = $2
+		where id = $3
+	`)
+
+	_, err := f.DBPoll.Exec(ctx, query, folder.ParentID, folder.Name, folder.ID)
+
+	return err
+}
+
+// GetByUser получить папку по ИД для пользователя, если папка не найдена - возвращается nil
+func (f *FolderRepository) GetByUser(ctx context.Context, id, uid uint64) (*domain.Folder, error) {
+	list, err := f.getList(ctx, f.setTableName(`select * from #T# where id = $1 and uid = $2`), id, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, folder := range list {
+		return &folder, nil
+	}
+
+	return nil, nil
+}
+
+// GetList получить все папки пользователя
+func (f *FolderRepository) GetList(ctx context.Context, uid uint64) ([]domain.Folder, error) {
+	return f.getList(ctx, f.setTableName(`select * from #T# where uid = $1 order by name, id`), uid)
+}
+
+// Delete удалить папки
+func (f *FolderRepository) Delete(ctx context.Context, ids []uint64) error {
+	_, err := f.DBPoll.Exec(ctx, f.setTableName(`delete from #T# where id = any($1)`), ids)
+	return err
+}
+
+func (f *FolderRepository) getList(ctx context.Context, query string, args ...interface{}) ([]domain.Folder, error) {
+	rows, err := f.DBPoll.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[domain.Folder])
+}
+
+func (f *FolderRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", f.tableName)
+}
@@ -0,0 +1,52 @@
+package pgsql
+
+import (
+	"context"
+	"gophkeeper/internal/server/repository/migrations"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationConn запросы, общие для пула соединений и транзакции
+type migrationConn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// migrationDB адаптер пула соединений или транзакции для применения общих миграций
+type migrationDB struct {
+	pool *pgxpool.Pool
+	conn migrationConn
+}
+
+func (m migrationDB) Exec(ctx context.Context, query string, args ...any) error {
+	_, err := m.conn.Exec(ctx, query, args...)
+	return err
+}
+
+func (m migrationDB) QueryInt(ctx context.Context, query string, args ...any) (int, error) {
+	var res int
+	err := m.conn.QueryRow(ctx, query, args...).Scan(&res)
+	return res, err
+}
+
+// Tx транзакция миграций: все серверы, работающие с базой, ждут одну advisory-блокировку до конца транзакции
+func (m migrationDB) Tx(ctx context.Context, f func(tx migrations.DB) error) error {
+	if m.pool == nil {
+		return f(m)
+	}
+
+	return pgx.BeginFunc(ctx, m.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `select pg_advisory_xact_lock(hashtext($1))`, migrations.VersionsTableName); err != nil {
+			return err
+		}
+
+		return f(migrationDB{conn: tx})
+	})
+}
+
+func migrate(ctx context.Context, pool *pgxpool.Pool, list []migrations.Migration, vars map[string]string) error {
+	return migrations.Apply(ctx, migrationDB{pool: pool, conn: pool}, migrations.Postgres, list, vars)
+}
@@ -0,0 +1,213 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const OrganizationTableName = "organization"
+
+const memberColumns = `m.org_id, m.uid, u.login, m.role, m.collection_key, m.key_version, m.accepted`
+
+// OrganizationRepository структура для взаимодействия с таблицами организаций и их участников
+// смена ключа хранилища изменяет записи и папки хранилища, поэтому нужны и их таблицы
+type OrganizationRepository struct {
+	DBPoll *pgxpool.Pool
+	tableName,
+	usersTableName,
+	dataTableName,
+	folderTableName string
+}
+
+func NewOrganizationRepository(ctx context.Context, pool *pgxpool.Pool, tableName, usersTableName, dataTableName, folderTableName string) (*OrganizationRepository, error) {
+	err := migrate(ctx, pool, migrations.Organization, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrganizationRepository{
+		DBPoll:          pool,
+		tableName:       tableName,
+		usersTableName:  usersTableName,
+		dataTableName:   dataTableName,
+		folderTableName: folderTableName,
+	}, nil
+}
+
+// Insert создание организации вместе с учетной записью хранилища и владельцем
+func (o *OrganizationRepository) Insert(ctx context.Context, org *domain.Organization, owner domain.OrgMember) error {
+	return pgx.BeginFunc(ctx, o.DBPoll, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, o.setTableName(`insert into #UT# (login, password) values ('', '') returning id`)).Scan(&org.VaultUID)
+		if err != nil {
+			return err
+		}
+
+		query := o.setTableName(`insert into #T# (vault_uid, name, key_version) values ($1, $2, $3) returning id`)
+		if err = tx.QueryRow(ctx, query, org.VaultUID, org.Name, org.KeyVersion).Scan(&org.ID); err != nil {
+			return err
+		}
+
+		owner.OrgID = org.ID
+
+		return o.saveMember(ctx, tx, owner)
+	})
+}
+
+// Get получить организацию по ИД, если организация не найдена - возвращается nil
+func (o *OrganizationRepository) Get(ctx context.Context, id uint64) (*domain.Organization, error) {
+	var org domain.Organization
+	query := o.setTableName(`select id, vault_uid, name, key_version from #T# where id = $1`)
+
+	err := o.DBPoll.QueryRow(ctx, query, id).Scan(&org.ID, &org.VaultUID, &org.Name, &org.KeyVersion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+// GetByUser получить организации, в которых состоит или приглашен пользователь
+func (o *OrganizationRepository) GetByUser(ctx context.Context, uid uint64) ([]domain.OrgMembership, error) {
+	query := o.setTableName(`select o.id, o.vault_uid, o.name, o.key_version, ` + memberColumns + `
+		from #T#_member m join #T# o on o.id = m.org_id join #UT# u on u.id = m.uid
+		where m.uid = $1 order by o.name, o.id`)
+
+	rows, err := o.DBPoll.Query(ctx, query, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.OrgMembership, error) {
+		var m domain.OrgMembership
+		err := row.Scan(&m.Org.ID, &m.Org.VaultUID, &m.Org.Name, &m.Org.KeyVersion, &m.Member.OrgID, &m.Member.UID, &m.Member.Login,
+			&m.Member.Role, &m.Member.CollectionKey, &m.Member.KeyVersion, &m.Member.Accepted)
+
+		return m, err
+	})
+}
+
+// GetMember получить участника организации, если пользователь не состоит в организации - возвращается nil
+func (o *OrganizationRepository) GetMember(ctx context.Context, orgID, uid uint64) (*domain.OrgMember, error) {
+	list, err := o.getMembers(ctx, `m.org_id = $1 and m.uid = $2`, orgID, uid)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+// GetMembers получить всех участников организации, включая приглашенных
+func (o *OrganizationRepository) GetMembers(ctx context.Context, orgID uint64) ([]domain.OrgMember, error) {
+	return o.getMembers(ctx, `m.org_id = $1`, orgID)
+}
+
+// SaveMember добавление или изменение участника организации
+func (o *OrganizationRepository) SaveMember(ctx context.Context, member domain.OrgMember) error {
+	return pgx.BeginFunc(ctx, o.DBPoll, func(tx pgx.Tx) error {
+		return o.saveMember(ctx, tx, member)
+	})
+}
+
+// DeleteMember исключение участника из организации
+func (o *OrganizationRepository) DeleteMember(ctx context.Context, orgID, uid uint64) error {
+	_, err := o.DBPoll.Exec(ctx, o.setTableName(`delete from #T#_member where org_id = $1 and uid = $2`), orgID, uid)
+	return err
+}
+
+// Rotate исключение участника и смена ключа хранилища в одной транзакции
+// если ключ уже сменили параллельно, возвращается ErrOrgKeyVersion
+func (o *OrganizationRepository) Rotate(ctx context.Context, rotation domain.OrgRotation) error {
+	return pgx.BeginFunc(ctx, o.DBPoll, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, o.setTableName(`update #T# set key_version = $1 where id = $2 and key_version = $3`),
+			rotation.KeyVersion, rotation.OrgID, rotation.KeyVersion-1)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return domain.ErrOrgKeyVersion
+		}
+
+		if _, err = tx.Exec(ctx, o.setTableName(`delete from #T#_member where org_id = $1 and uid = $2`), rotation.OrgID, rotation.RemoveUID); err != nil {
+			return err
+		}
+
+		for uid, key := range rotation.Keys {
+			query := o.setTableName(`update #T#_member set collection_key = $1, key_version = $2 where org_id = $3 and uid = $4`)
+			if _, err = tx.Exec(ctx, query, key, rotation.KeyVersion, rotation.OrgID, uid); err != nil {
+				return err
+			}
+		}
+
+		for _, r := range rotation.Records {
+			query := o.setTableName(`update #DT# set record_key = $1, name_hash = $2 where id = $3 and uid = $4`)
+			if _, err = tx.Exec(ctx, query, r.RecordKey, r.NameHash, r.ID, rotation.VaultUID); err != nil {
+				return err
+			}
+
+			if _, err = tx.Exec(ctx, o.setTableName(`delete from #DT#_tag where data_id = $1`), r.ID); err != nil {
+				return err
+			}
+
+			for _, t := range r.Tags {
+				query = o.setTableName(`insert into #DT#_tag (data_id, tag) values ($1, $2) on conflict do nothing`)
+				if _, err = tx.Exec(ctx, query, r.ID, t); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, f := range rotation.Folders {
+			query := o.setTableName(`update #FOT# set name = $1 where id = $2 and uid = $3`)
+			if _, err = tx.Exec(ctx, query, f.Name, f.ID, rotation.VaultUID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (o *OrganizationRepository) getMembers(ctx context.Context, where string, args ...any) ([]domain.OrgMember, error) {
+	query := o.setTableName(`select ` + memberColumns + ` from #T#_member m join #UT# u on u.id = m.uid
+		where ` + where + ` order by m.role desc, u.login`)
+
+	rows, err := o.DBPoll.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.OrgMember, error) {
+		var m domain.OrgMember
+		err := row.Scan(&m.OrgID, &m.UID, &m.Login, &m.Role, &m.CollectionKey, &m.KeyVersion, &m.Accepted)
+
+		return m, err
+	})
+}
+
+func (o *OrganizationRepository) saveMember(ctx context.Context, tx pgx.Tx, member domain.OrgMember) error {
+	query := o.setTableName(`insert into #T#_member (org_id, uid, role, collection_key, key_version, accepted) values ($1, $2, $3, $4, $5, $6)
+		on conflict (org_id, uid) do update set role = excluded.role, collection_key = excluded.collection_key,
+		key_version = excluded.key_version, accepted = excluded.accepted`)
+
+	_, err := tx.Exec(ctx, query, member.OrgID, member.UID, member.Role, member.CollectionKey, member.KeyVersion, member.Accepted)
+
+	return err
+}
+
+func (o *OrganizationRepository) setTableName(query string) string {
+	return strings.NewReplacer("#T#", o.tableName, "#UT#", o.usersTableName, "#DT#", o.dataTableName, "#FOT#", o.folderTableName).Replace(query)
+}
@@ -0,0 +1,96 @@
+package pgsql
+
+import (
+	"context"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const RateLimitTableName = "rate_limit"
+
+// RateLimitRepository структура для хранения ограничений попыток входа в базе, общих для всех серверов
+type RateLimitRepository struct {
+	DBPoll    *pgxpool.Pool
+	tableName string
+}
+
+func NewRateLimitRepository(ctx context.Context, pool *pgxpool.Pool, tableName string) (*RateLimitRepository, error) {
+	err := migrate(ctx, pool, migrations.RateLimit, map[string]string{
+		migrations.TableVar: tableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RateLimitRepository{
+		DBPoll:    pool,
+		tableName: tableName,
+	}, nil
+}
+
+// Update изменение состояния ключа функцией update, строка ключа блокируется до конца транзакции,
+// поэтому попытки с разных серверов учитываются по очереди
+func (r *RateLimitRepository) Update(ctx context.Context, key string, update func(bucket *domain.RateBucket)) error {
+	return pgx.BeginFunc(ctx, r.DBPoll, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, r.setTableName(`insert into #T# (id) values ($1) on conflict do nothing`), key); err != nil {
+			return err
+		}
+
+		var (
+			bucket = domain.RateBucket{Key: key}
+			updatedAt,
+			failedAt,
+			lockedUntil int64
+		)
+
+		err := tx.QueryRow(ctx, r.setTableName(`select tokens, updated_at, failures, failed_at, locked_until from #T# where id = $1 for update`), key).
+			Scan(&bucket.Tokens, &updatedAt, &bucket.Failures, &failedAt, &lockedUntil)
+		if err != nil {
+			return err
+		}
+
+		bucket.UpdatedAt, bucket.FailedAt, bucket.LockedUntil = fromMilli(updatedAt), fromMilli(failedAt), fromMilli(lockedUntil)
+		update(&bucket)
+
+		_, err = tx.Exec(ctx, r.setTableName(`update #T# set tokens = $2, updated_at = $3, failures = $4, failed_at = $5, locked_until = $6 where id = $1`),
+			key, bucket.Tokens, toMilli(bucket.UpdatedAt), bucket.Failures, toMilli(bucket.FailedAt), toMilli(bucket.LockedUntil))
+
+		return err
+	})
+}
+
+// DeleteIdle удаление ключей без попыток и блокировок после before, возвращается число удаленных ключей
+func (r *RateLimitRepository) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.DBPoll.Exec(ctx, r.setTableName(`delete from #T# where updated_at < $1 and locked_until < $1`), toMilli(before))
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *RateLimitRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", r.tableName)
+}
+
+// toMilli время в миллисекундах, нулевое время - 0
+func toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+func fromMilli(v int64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(v)
+}
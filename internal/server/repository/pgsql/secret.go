@@ -0,0 +1,89 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const SecretShareTableName = "secret_share"
+
+// SecretRepository структура для взаимодействия с таблицей одноразовых ссылок на секреты
+type SecretRepository struct {
+	DBPoll *pgxpool.Pool
+	tableName,
+	usersTableName string
+}
+
+func NewSecretRepository(ctx context.Context, pool *pgxpool.Pool, tableName, usersTableName string) (*SecretRepository, error) {
+	err := migrate(ctx, pool, migrations.SecretShare, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretRepository{
+		DBPoll:         pool,
+		tableName:      tableName,
+		usersTableName: usersTableName,
+	}, nil
+}
+
+// Insert сохранение секрета
+func (s *SecretRepository) Insert(ctx context.Context, share domain.SecretShare) error {
+	_, err := s.DBPoll.Exec(ctx, s.setTableName(`insert into #T# (id, uid, blob, expires_at, max_views, views) values ($1, $2, $3, $4, $5, $6)`),
+		share.ID, share.UID, share.Blob, share.ExpiresAt.Unix(), share.MaxViews, share.Views)
+
+	return err
+}
+
+// Take просмотр секрета: счетчик просмотров увеличивается одним запросом, поэтому секрет не выдается больше MaxViews раз
+// секрет, просмотренный последний раз, удаляется; если секрет не найден, истек или просмотрен - возвращается nil
+func (s *SecretRepository) Take(ctx context.Context, id string, now time.Time) (*domain.SecretShare, error) {
+	var (
+		share     = domain.SecretShare{ID: id}
+		expiresAt int64
+	)
+
+	err := s.DBPoll.QueryRow(ctx, s.setTableName(`update #T# set views = views + 1 where id = $1 and expires_at > $2 and views < max_views
+		returning uid, blob, expires_at, max_views, views`), id, now.Unix()).Scan(&share.UID, &share.Blob, &expiresAt, &share.MaxViews, &share.Views)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	share.ExpiresAt = time.Unix(expiresAt, 0)
+
+	if share.Views >= share.MaxViews {
+		if _, err = s.DBPoll.Exec(ctx, s.setTableName(`delete from #T# where id = $1`), id); err != nil {
+			return nil, err
+		}
+	}
+
+	return &share, nil
+}
+
+// DeleteExpired удаление секретов, истекших к моменту now, возвращается число удаленных секретов
+func (s *SecretRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := s.DBPoll.Exec(ctx, s.setTableName(`delete from #T# where expires_at <= $1`), now.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (s *SecretRepository) setTableName(query string) string {
+	return strings.NewReplacer("#T#", s.tableName, "#UT#", s.usersTableName).Replace(query)
+}
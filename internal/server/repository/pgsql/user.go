@@ -2,8 +2,11 @@ package pgsql
 
 import (
 	"context"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
 	"gophkeeper/server/domain"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	_ "github.com/jackc/pgx/v5"
@@ -37,6 +40,33 @@ func (u *UserRepository) GetByLogin(ctx context.Context, login string) (domain.U
 	return u.getOne(ctx, query, login)
 }
 
+// GetByID получить пользователя по ИД
+func (u *UserRepository) GetByID(ctx context.Context, id uint64) (domain.User, error) {
+	query := u.setUserTableName(`select id, login, password from #T# where id = $1`)
+
+	return u.getOne(ctx, query, id)
+}
+
+// GetKeys получить ключи пользователя, пустые - если ключи не заданы
+func (u *UserRepository) GetKeys(ctx context.Context, id uint64) (keys domain.UserKeys, err error) {
+	query := u.setUserTableName(`select coalesce(public_key, ''), coalesce(private_key, '') from #T# where id = $1`)
+
+	err = u.DBPoll.QueryRow(ctx, query, id).Scan(&keys.PublicKey, &keys.PrivateKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.UserKeys{}, nil
+	}
+
+	return keys, err
+}
+
+// SetKeys сохранить ключи пользователя
+func (u *UserRepository) SetKeys(ctx context.Context, id uint64, keys domain.UserKeys) error {
+	query := u.setUserTableName(`update #T# set public_key = $1, private_key = $2 where id = $3`)
+	_, err := u.DBPoll.Exec(ctx, query, keys.PublicKey, keys.PrivateKey, id)
+
+	return err
+}
+
 // Store добавить нового пользователя
 func (u *UserRepository) Store(ctx context.Context, user domain.User) (uint64, error) {
 	var id uint64
@@ -50,6 +80,51 @@ func (u *UserRepository) Store(ctx context.Context, user domain.User) (uint64, e
 	return id, nil
 }
 
+// GetState состояние учетной записи, nil - пользователь не найден
+func (u *UserRepository) GetState(ctx context.Context, id uint64) (*domain.AccountState, error) {
+	var purgeAt, sessionsAfter *int64
+
+	err := u.DBPoll.QueryRow(ctx, u.setUserTableName(`select purge_at, sessions_after from #T# where id = $1`), id).Scan(&purgeAt, &sessionsAfter)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AccountState{PurgeAt: fromNullUnix(purgeAt), SessionsAfter: fromNullUnix(sessionsAfter)}, nil
+}
+
+// SetState сохранение состояния учетной записи
+func (u *UserRepository) SetState(ctx context.Context, id uint64, state domain.AccountState) error {
+	_, err := u.DBPoll.Exec(ctx, u.setUserTableName(`update #T# set purge_at = $1, sessions_after = $2 where id = $3`),
+		nullUnix(state.PurgeAt), nullUnix(state.SessionsAfter), id)
+
+	return err
+}
+
+// GetPurgeDue ИД учетных записей, время окончательного удаления которых наступило к now
+func (u *UserRepository) GetPurgeDue(ctx context.Context, now time.Time) ([]uint64, error) {
+	rows, err := u.DBPoll.Query(ctx, u.setUserTableName(`select id from #T# where purge_at <= $1 order by id`), now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
 func (u *UserRepository) getOne(ctx context.Context, query string, args ...interface{}) (user domain.User, err error) {
 	rows, err := u.DBPoll.Query(ctx, query, args...)
 	if err != nil {
@@ -69,21 +144,30 @@ func (u *UserRepository) getOne(ctx context.Context, query string, args ...inter
 }
 
 func createUsersTable(ctx context.Context, pool *pgxpool.Pool, tableName string) error {
-	query := strings.ReplaceAll(`create table if not exists #T#
-		(
-			id    serial primary key,
-			login  varchar not null,
-			password varchar not null
-		);`, "#T#", tableName)
+	return migrate(ctx, pool, migrations.Users, map[string]string{
+		migrations.TableVar: tableName,
+	})
+}
 
-	_, err := pool.Exec(ctx, query)
+func (u *UserRepository) setUserTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", u.tableName)
+}
 
-	if err != nil {
-		return err
+// nullUnix время в секундах, нулевое время - NULL
+func nullUnix(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
 	}
-	return nil
+
+	sec := t.Unix()
+
+	return &sec
 }
 
-func (u *UserRepository) setUserTableName(query string) string {
-	return strings.ReplaceAll(query, "#T#", u.tableName)
+func fromNullUnix(sec *int64) time.Time {
+	if sec == nil {
+		return time.Time{}
+	}
+
+	return time.Unix(*sec, 0)
 }
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"gophkeeper/server/domain"
+	"strings"
+)
+
+// AccountRepository структура для удаления учетных записей вместе со всеми данными
+// своих таблиц у нее нет, удаление затрагивает таблицы пользователей, данных, файлов, папок и организаций
+type AccountRepository struct {
+	DB *sql.DB
+	usersTableName,
+	dataTableName,
+	fileTableName,
+	folderTableName,
+	orgTableName string
+}
+
+func NewAccountRepository(db *sql.DB, usersTableName, dataTableName, fileTableName, folderTableName, orgTableName string) *AccountRepository {
+	return &AccountRepository{
+		DB:              db,
+		usersTableName:  usersTableName,
+		dataTableName:   dataTableName,
+		fileTableName:   fileTableName,
+		folderTableName: folderTableName,
+		orgTableName:    orgTableName,
+	}
+}
+
+// Purge удаление в одной транзакции организаций purge.OrgIDs и учетных записей purge.UIDs: записей с тегами,
+// выданного и полученного доступа к записям, файлов, папок и участия в организациях; экстренный доступ и ссылки
+// на секреты удаляются каскадно. возвращаются пути файлов, которые нужно удалить с диска после фиксации транзакции
+func (a *AccountRepository) Purge(ctx context.Context, purge domain.AccountPurge) ([]string, error) {
+	tx, err := a.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := a.purge(ctx, tx, purge)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	return paths, tx.Commit()
+}
+
+func (a *AccountRepository) purge(ctx context.Context, tx *sql.Tx, purge domain.AccountPurge) ([]string, error) {
+	for _, id := range purge.OrgIDs {
+		if _, err := tx.ExecContext(ctx, a.setTableName(`delete from #OT# where id = ?`), id); err != nil {
+			return nil, err
+		}
+	}
+
+	var paths []string
+	for _, uid := range purge.UIDs {
+		files, err := a.files(ctx, tx, uid)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, query := range []string{
+			`delete from #OT#_member where uid = ?`,
+			`delete from #DT#_share where uid = ?`,
+			`delete from #DT# where uid = ?`,
+			`delete from #FOT# where uid = ?`,
+		} {
+			if _, err = tx.ExecContext(ctx, a.setTableName(query), uid); err != nil {
+				return nil, err
+			}
+		}
+
+		for id, path := range files {
+			if _, err = tx.ExecContext(ctx, a.setTableName(`delete from #FT# where id = ?`), id); err != nil {
+				return nil, err
+			}
+
+			paths = append(paths, path)
+		}
+
+		if _, err = tx.ExecContext(ctx, a.setTableName(`delete from #UT# where id = ?`), uid); err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
+
+// files пути файлов записей пользователя по ИД файла
+func (a *AccountRepository) files(ctx context.Context, tx *sql.Tx, uid uint64) (map[uint64]string, error) {
+	rows, err := tx.QueryContext(ctx, a.setTableName(`select f.id, f.path from #FT# f join #DT# d on d.file_id = f.id where d.uid = ?`), uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[uint64]string)
+	for rows.Next() {
+		var (
+			id   uint64
+			path string
+		)
+
+		if err = rows.Scan(&id, &path); err != nil {
+			return nil, err
+		}
+
+		files[id] = path
+	}
+
+	return files, rows.Err()
+}
+
+func (a *AccountRepository) setTableName(query string) string {
+	return strings.NewReplacer("#UT#", a.usersTableName, "#DT#", a.dataTableName, "#FT#", a.fileTableName,
+		"#FOT#", a.folderTableName, "#OT#", a.orgTableName).Replace(query)
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	r := newTestRepos(t, db)
+	accounts := NewAccountRepository(db, UsersTableName, DataTableName, FileTableName, FolderTableName, OrganizationTableName)
+
+	secrets, err := NewSecretRepository(ctx, db, SecretShareTableName, UsersTableName)
+	require.NoError(t, err)
+
+	alice, err := r.user.Store(ctx, domain.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+	bob, err := r.user.Store(ctx, domain.User{Login: "bob", Password: "hash"})
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	require.NoError(t, r.user.SetState(ctx, alice, domain.AccountState{PurgeAt: now, SessionsAfter: now}))
+	due, err := r.user.GetPurgeDue(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{alice}, due)
+
+	folder := &domain.Folder{Name: "work", UID: alice}
+	require.NoError(t, r.folder.Insert(ctx, folder))
+	file := &domain.File{Name: "id_rsa", Path: "/files/1/1/id_rsa"}
+	require.NoError(t, r.file.Insert(ctx, file))
+	record := &domain.Data{Name: "ssh", UID: alice, Version: 1, FileID: &file.ID, FolderID: &folder.ID, Tags: []string{"work"}}
+	require.NoError(t, r.data.Insert(ctx, record))
+	require.NoError(t, r.data.Share(ctx, domain.DataShare{DataID: record.ID, UID: bob, RecordKey: "key"}))
+
+	// запись bob, к которой у alice есть доступ, остается
+	other := &domain.Data{Name: "db", UID: bob, Version: 1}
+	require.NoError(t, r.data.Insert(ctx, other))
+	require.NoError(t, r.data.Share(ctx, domain.DataShare{DataID: other.ID, UID: alice, RecordKey: "key"}))
+
+	solo := &domain.Organization{Name: "solo", KeyVersion: 1}
+	require.NoError(t, r.org.Insert(ctx, solo, domain.OrgMember{UID: alice, Role: domain.OrgRoleOwner, CollectionKey: "key", KeyVersion: 1, Accepted: true}))
+	vaultRecord := &domain.Data{Name: "vault", UID: solo.VaultUID, Version: 1}
+	require.NoError(t, r.data.Insert(ctx, vaultRecord))
+	team := &domain.Organization{Name: "team", KeyVersion: 1}
+	require.NoError(t, r.org.Insert(ctx, team, domain.OrgMember{UID: bob, Role: domain.OrgRoleOwner, CollectionKey: "key", KeyVersion: 1, Accepted: true}))
+	require.NoError(t, r.org.SaveMember(ctx, domain.OrgMember{OrgID: team.ID, UID: alice, Role: domain.OrgRoleViewer, CollectionKey: "key", KeyVersion: 1, Accepted: true}))
+
+	require.NoError(t, r.emergency.Insert(ctx, &domain.EmergencyGrant{GrantorUID: alice, GranteeUID: bob, VaultKey: "key", State: domain.EmergencyInvited, Wait: time.Hour}))
+	require.NoError(t, secrets.Insert(ctx, domain.SecretShare{ID: "once", UID: alice, Blob: "blob", ExpiresAt: now.Add(time.Hour), MaxViews: 1}))
+
+	paths, err := accounts.Purge(ctx, domain.AccountPurge{UIDs: []uint64{alice, solo.VaultUID}, OrgIDs: []uint64{solo.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{file.Path}, paths)
+
+	for _, uid := range []uint64{alice, solo.VaultUID} {
+		state, err := r.user.GetState(ctx, uid)
+		require.NoError(t, err)
+		assert.Nil(t, state)
+	}
+
+	for _, id := range []uint64{record.ID, vaultRecord.ID} {
+		row, err := r.data.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Nil(t, row)
+	}
+
+	f, err := r.file.Get(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	folders, err := r.folder.GetList(ctx, alice)
+	require.NoError(t, err)
+	assert.Empty(t, folders)
+
+	org, err := r.org.Get(ctx, solo.ID)
+	require.NoError(t, err)
+	assert.Nil(t, org)
+
+	members, err := r.org.GetMembers(ctx, team.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, bob, members[0].UID)
+
+	share, err := r.data.GetShare(ctx, other.ID, alice)
+	require.NoError(t, err)
+	assert.Nil(t, share)
+	row, err := r.data.Get(ctx, other.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, row)
+
+	grants, err := r.emergency.GetByUser(ctx, bob)
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+	secret, err := secrets.Take(ctx, "once", now)
+	require.NoError(t, err)
+	assert.Nil(t, secret)
+}
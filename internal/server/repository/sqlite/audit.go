@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gophkeeper/internal/server/repository/dataquery"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+	"time"
+)
+
+const AuditTableName = "audit_events"
+
+// AuditRepository структура для взаимодействия с журналом аудита
+// журнал только дополняется, изменение и удаление событий и отметок запрещено триггерами
+type AuditRepository struct {
+	DB        *sql.DB
+	tableName string
+}
+
+func NewAuditRepository(ctx context.Context, db *sql.DB, tableName string) (*AuditRepository, error) {
+	err := migrate(ctx, db, migrations.Audit, map[string]string{
+		migrations.TableVar: tableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditRepository{
+		DB:        db,
+		tableName: tableName,
+	}, nil
+}
+
+// Insert добавление события в конец цепочки, в event заполняются ИД и хеши
+// хеш предыдущего события читается в той же транзакции, запись в SQLite идет через одно соединение
+func (a *AuditRepository) Insert(ctx context.Context, event *domain.AuditEvent) error {
+	tx, err := a.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, a.setTableName(`select coalesce(hash, '') from #T# order by id desc limit 1`)).Scan(&event.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	event.Hash = event.ChainHash()
+
+	err = tx.QueryRowContext(ctx, a.setTableName(`insert into #T# (uid, actor_uid, actor, ip, action, data_id, outcome, created_at, prev_hash, hash) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) returning id`),
+		event.UID, event.ActorUID, event.Actor, event.IP, event.Action, event.DataID, event.Outcome, event.CreatedAt.Unix(), event.PrevHash, event.Hash).Scan(&event.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetList события журнала пользователя uid по фильтру, от новых к старым
+func (a *AuditRepository) GetList(ctx context.Context, uid uint64, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	query, args := dataquery.AuditList(migrations.SQLite.Param, uid, filter)
+
+	return a.query(ctx, query, args...)
+}
+
+// Scan страница всех событий журнала после события afterID по возрастанию ИД, для проверки цепочки
+func (a *AuditRepository) Scan(ctx context.Context, afterID uint64, limit int) ([]domain.AuditEvent, error) {
+	return a.query(ctx, `select `+dataquery.AuditColumns+` from #T# where id > ? order by id limit ?`, afterID, limit)
+}
+
+// Head неподписанная отметка последнего события журнала, EventID = 0 - журнал пуст
+func (a *AuditRepository) Head(ctx context.Context) (domain.AuditCheckpoint, error) {
+	var head domain.AuditCheckpoint
+
+	err := a.DB.QueryRowContext(ctx, a.setTableName(`select id, coalesce(hash, ''), (select count(*) from #T#) from #T# order by id desc limit 1`)).
+		Scan(&head.EventID, &head.Hash, &head.Events)
+	if errors.Is(err, sql.ErrNoRows) {
+		return head, nil
+	}
+
+	return head, err
+}
+
+// InsertCheckpoint сохранение подписанной отметки, в checkpoint заполняется ИД
+func (a *AuditRepository) InsertCheckpoint(ctx context.Context, checkpoint *domain.AuditCheckpoint) error {
+	return a.DB.QueryRowContext(ctx, a.setTableName(`insert into #T#_checkpoint (event_id, hash, events, created_at, signature) values (?, ?, ?, ?, ?) returning id`),
+		checkpoint.EventID, checkpoint.Hash, checkpoint.Events, checkpoint.CreatedAt.Unix(), checkpoint.Signature).Scan(&checkpoint.ID)
+}
+
+// GetCheckpoints все отметки журнала по возрастанию ИД
+func (a *AuditRepository) GetCheckpoints(ctx context.Context) ([]domain.AuditCheckpoint, error) {
+	rows, err := a.DB.QueryContext(ctx, a.setTableName(`select id, event_id, hash, events, created_at, signature from #T#_checkpoint order by id`))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.AuditCheckpoint
+	for rows.Next() {
+		var (
+			c         domain.AuditCheckpoint
+			createdAt int64
+		)
+
+		if err = rows.Scan(&c.ID, &c.EventID, &c.Hash, &c.Events, &createdAt, &c.Signature); err != nil {
+			return nil, err
+		}
+
+		c.CreatedAt = time.Unix(createdAt, 0)
+		res = append(res, c)
+	}
+
+	return res, rows.Err()
+}
+
+func (a *AuditRepository) query(ctx context.Context, query string, args ...any) ([]domain.AuditEvent, error) {
+	rows, err := a.DB.QueryContext(ctx, a.setTableName(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.AuditEvent
+	for rows.Next() {
+		var (
+			event     domain.AuditEvent
+			createdAt int64
+		)
+
+		err = rows.Scan(&event.ID, &event.UID, &event.ActorUID, &event.Actor, &event.IP, &event.Action, &event.DataID, &event.Outcome, &createdAt, &event.PrevHash, &event.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		event.CreatedAt = time.Unix(createdAt, 0)
+		res = append(res, event)
+	}
+
+	return res, rows.Err()
+}
+
+func (a *AuditRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", a.tableName)
+}
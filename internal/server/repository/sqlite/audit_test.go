@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(t, openTestDB(t))
+
+	now := time.Unix(1700000000, 0)
+	events := []*domain.AuditEvent{
+		{UID: 1, ActorUID: 1, Actor: "alice", IP: "10.0.0.1", Action: domain.AuditLogin, Outcome: domain.AuditSuccess, CreatedAt: now},
+		{UID: 1, ActorUID: 1, Actor: "alice", IP: "10.0.0.1", Action: domain.AuditRead, DataID: 7, Outcome: domain.AuditSuccess, CreatedAt: now.Add(time.Minute)},
+		{UID: 1, ActorUID: 1, Actor: "alice", IP: "192.0.2.1", Action: domain.AuditLogin, Outcome: domain.AuditDenied, CreatedAt: now.Add(2 * time.Minute)},
+		// действие alice в хранилище организации
+		{UID: 5, ActorUID: 1, Actor: "alice", IP: "10.0.0.1", Action: domain.AuditSave, DataID: 9, Outcome: domain.AuditSuccess, CreatedAt: now.Add(3 * time.Minute)},
+		{UID: 2, ActorUID: 2, Actor: "bob", IP: "10.0.0.2", Action: domain.AuditLogin, Outcome: domain.AuditSuccess, CreatedAt: now},
+	}
+
+	for i, e := range events {
+		require.NoError(t, r.audit.Insert(ctx, e))
+		assert.NotZero(t, e.ID)
+		assert.Equal(t, e.ChainHash(), e.Hash)
+
+		// каждое событие ссылается на хеш предыдущего, цепочка общая для всех пользователей
+		if i > 0 {
+			assert.Equal(t, events[i-1].Hash, e.PrevHash)
+		} else {
+			assert.Empty(t, e.PrevHash)
+		}
+	}
+
+	ids := func(list []domain.AuditEvent) []uint64 {
+		var res []uint64
+		for _, e := range list {
+			res = append(res, e.ID)
+		}
+
+		return res
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.AuditFilter
+		want   []*domain.AuditEvent
+	}{
+		{"all", domain.AuditFilter{}, []*domain.AuditEvent{events[3], events[2], events[1], events[0]}},
+		{"actions", domain.AuditFilter{Actions: []domain.AuditAction{domain.AuditLogin}}, []*domain.AuditEvent{events[2], events[0]}},
+		{"data", domain.AuditFilter{DataID: 7}, []*domain.AuditEvent{events[1]}},
+		{"outcome", domain.AuditFilter{Outcome: domain.AuditDenied}, []*domain.AuditEvent{events[2]}},
+		{"period", domain.AuditFilter{Since: now.Add(time.Minute), Until: now.Add(3 * time.Minute)}, []*domain.AuditEvent{events[2], events[1]}},
+		{"page", domain.AuditFilter{BeforeID: events[2].ID, Limit: 1}, []*domain.AuditEvent{events[1]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := r.audit.GetList(ctx, 1, tt.filter)
+			require.NoError(t, err)
+
+			var want []uint64
+			for _, e := range tt.want {
+				want = append(want, e.ID)
+			}
+			assert.Equal(t, want, ids(list))
+		})
+	}
+
+	list, err := r.audit.GetList(ctx, 1, domain.AuditFilter{DataID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AuditEvent{*events[1]}, list)
+
+	page, err := r.audit.Scan(ctx, events[1].ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AuditEvent{*events[2], *events[3]}, page)
+
+	head, err := r.audit.Head(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuditCheckpoint{EventID: events[4].ID, Hash: events[4].Hash, Events: 5}, head)
+
+	head.CreatedAt, head.Signature = now, "sig"
+	require.NoError(t, r.audit.InsertCheckpoint(ctx, &head))
+
+	checkpoints, err := r.audit.GetCheckpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AuditCheckpoint{head}, checkpoints)
+
+	t.Run("append only", func(t *testing.T) {
+		for _, table := range []string{AuditTableName, AuditTableName + "_checkpoint"} {
+			_, err := r.audit.DB.ExecContext(ctx, `update `+table+` set hash = ?`, "forged")
+			assert.ErrorContains(t, err, "append-only")
+
+			_, err = r.audit.DB.ExecContext(ctx, `delete from `+table)
+			assert.ErrorContains(t, err, "append-only")
+		}
+	})
+}
@@ -0,0 +1,270 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gophkeeper/internal/server/backup"
+	"gophkeeper/internal/server/repository/migrations"
+	"path/filepath"
+	"strings"
+)
+
+// snapshotName имя файла снимка базы в архиве
+const snapshotName = "gophkeeper.db"
+
+// backupTables таблицы в порядке восстановления (сначала те, на которые ссылаются внешние ключи)
+var backupTables = []string{UsersTableName, OrganizationTableName, OrganizationTableName + "_member", EmergencyTableName, FileTableName, FolderTableName, DataTableName, DataTableName + "_tag", DataTableName + "_share", AuditTableName, AuditTableName + "_checkpoint"}
+
+// backupSchema миграции таблиц для проверки версии схемы архива
+var backupSchema = map[string][]migrations.Migration{
+	UsersTableName:        migrations.Users,
+	OrganizationTableName: migrations.Organization,
+	EmergencyTableName:    migrations.Emergency,
+	FileTableName:         migrations.File,
+	FolderTableName:       migrations.Folder,
+	DataTableName:         migrations.Data,
+	AuditTableName:        migrations.Audit,
+}
+
+// BackupDB снимок и восстановление базы SQLite для резервного копирования сервера
+// снимок делается командой VACUUM INTO, которая читает базу в одной транзакции
+type BackupDB struct {
+	DB *sql.DB
+}
+
+func NewBackupDB(db *sql.DB) *BackupDB {
+	return &BackupDB{DB: db}
+}
+
+// Storage тип хранилища
+func (b *BackupDB) Storage() string {
+	return migrations.SQLite.Name
+}
+
+// Dump снимок базы в файл, пути файлов в снимке заменяются на относительные
+func (b *BackupDB) Dump(ctx context.Context, dir string, relPath func(string) (string, error)) (backup.Snapshot, error) {
+	snap := backup.Snapshot{Entries: []string{snapshotName}, Schema: make(map[string]int)}
+	path := filepath.Join(dir, snapshotName)
+
+	if _, err := b.DB.ExecContext(ctx, `vacuum into ?`, path); err != nil {
+		return snap, err
+	}
+
+	db, err := sql.Open(driverName, "file:"+path)
+	if err != nil {
+		return snap, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `select table_name, version from `+migrations.VersionsTableName)
+	if err != nil {
+		return snap, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			table   string
+			version int
+		)
+
+		if err = rows.Scan(&table, &version); err != nil {
+			return snap, err
+		}
+
+		if _, ok := backupSchema[table]; ok {
+			snap.Schema[table] = version
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return snap, err
+	}
+
+	err = rewritePaths(ctx, db, func(p string) (string, error) {
+		rel, err := relPath(p)
+		snap.Files = append(snap.Files, rel)
+
+		return rel, err
+	})
+
+	return snap, err
+}
+
+// Restore перенос данных из снимка в пустые таблицы
+// таблицы должны быть созданы миграциями, переносятся только колонки, которые есть в снимке
+func (b *BackupDB) Restore(ctx context.Context, dir string, schema map[string]int, absPath func(string) string) error {
+	current := make(map[string]int, len(backupSchema))
+	for table, list := range backupSchema {
+		current[table] = migrations.Latest(list)
+	}
+
+	if err := backup.CheckSchema(schema, current); err != nil {
+		return err
+	}
+
+	// ATTACH действует только в рамках соединения
+	conn, err := b.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, `attach database ? as backup`, filepath.Join(dir, snapshotName)); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), `detach database backup`)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// папку можно перенести в другую, поэтому внешние ключи проверяются при фиксации транзакции
+	if _, err = tx.ExecContext(ctx, `pragma defer_foreign_keys = on`); err != nil {
+		return err
+	}
+
+	for _, table := range backupTables {
+		var exists bool
+		if err = tx.QueryRowContext(ctx, `select exists (select 1 from main.`+table+`)`).Scan(&exists); err != nil {
+			return err
+		}
+
+		if exists {
+			return fmt.Errorf("%w: table %s has rows", backup.ErrNotEmpty, table)
+		}
+	}
+
+	for _, table := range backupTables {
+		if !inArchive(schema, table) {
+			continue
+		}
+
+		cols, err := snapshotColumns(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+
+		list := strings.Join(cols, ", ")
+		if _, err = tx.ExecContext(ctx, `insert into main.`+table+` (`+list+`) select `+list+` from backup.`+table); err != nil {
+			return err
+		}
+	}
+
+	err = rewritePaths(ctx, tx, func(p string) (string, error) {
+		return absPath(p), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lateTable таблица схемы, по которой версионируется таблица, появившаяся позже первой версии архива,
+// и версия схемы, в которой она появилась
+type lateTable struct {
+	schema  string
+	version int
+}
+
+// lateTables таблицы, появившиеся позже первой версии архива
+var lateTables = map[string]lateTable{
+	OrganizationTableName:             {OrganizationTableName, 1},
+	OrganizationTableName + "_member": {OrganizationTableName, 1},
+	EmergencyTableName:                {EmergencyTableName, 1},
+	AuditTableName:                    {AuditTableName, 1},
+	AuditTableName + "_checkpoint":    {AuditTableName, 4},
+}
+
+// inArchive есть ли таблица в архиве: в старых архивах таблиц, добавленных позже, нет
+func inArchive(schema map[string]int, table string) bool {
+	late, ok := lateTables[table]
+	if !ok {
+		return true
+	}
+
+	return schema[late.schema] >= late.version
+}
+
+// execQuerier общие методы *sql.DB, *sql.Tx и *sql.Conn
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func rewritePaths(ctx context.Context, db execQuerier, rewrite func(string) (string, error)) error {
+	rows, err := db.QueryContext(ctx, `select id, path from `+FileTableName+` order by id`)
+	if err != nil {
+		return err
+	}
+
+	type file struct {
+		id   uint64
+		path string
+	}
+
+	var files []file
+
+	for rows.Next() {
+		var f file
+		if err = rows.Scan(&f.id, &f.path); err != nil {
+			rows.Close()
+			return err
+		}
+
+		files = append(files, f)
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.path, err = rewrite(f.path); err != nil {
+			return err
+		}
+
+		if _, err = db.ExecContext(ctx, `update `+FileTableName+` set path = ? where id = ?`, f.path, f.id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// snapshotColumns колонки таблицы снимка
+func snapshotColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `select name from pragma_table_info(?, 'backup')`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []string
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		cols = append(cols, `"`+strings.ReplaceAll(name, `"`, `""`)+`"`)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("%w: no table %s in snapshot", backup.ErrFormat, table)
+	}
+
+	return cols, nil
+}
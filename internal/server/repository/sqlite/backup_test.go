@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"gophkeeper/internal/server/backup"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRepos struct {
+	user      *UserRepository
+	file      *FileRepository
+	data      *DataRepository
+	folder    *FolderRepository
+	org       *OrganizationRepository
+	emergency *EmergencyRepository
+	audit     *AuditRepository
+}
+
+func newTestRepos(t *testing.T, db *sql.DB) testRepos {
+	ctx := context.Background()
+
+	var (
+		r   testRepos
+		err error
+	)
+
+	r.user, err = NewUserRepository(ctx, db, UsersTableName)
+	require.NoError(t, err)
+	r.file, err = NewFileRepository(ctx, db, FileTableName)
+	require.NoError(t, err)
+	r.data, err = NewDataRepository(ctx, db, DataTableName, FileTableName, UsersTableName)
+	require.NoError(t, err)
+	r.folder, err = NewFolderRepository(ctx, db, FolderTableName, UsersTableName)
+	require.NoError(t, err)
+	r.org, err = NewOrganizationRepository(ctx, db, OrganizationTableName, UsersTableName, DataTableName, FolderTableName)
+	require.NoError(t, err)
+	r.emergency, err = NewEmergencyRepository(ctx, db, EmergencyTableName, UsersTableName)
+	require.NoError(t, err)
+	r.audit, err = NewAuditRepository(ctx, db, AuditTableName)
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestBackupDB(t *testing.T) {
+	ctx := context.Background()
+	src := newTestRepos(t, openTestDB(t))
+	srcRoot := t.TempDir()
+
+	uid, err := src.user.Store(ctx, domain.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+
+	// вложенная папка создана раньше родителя
+	child := &domain.Folder{Name: "servers", UID: uid}
+	require.NoError(t, src.folder.Insert(ctx, child))
+	parent := &domain.Folder{Name: "work", UID: uid}
+	require.NoError(t, src.folder.Insert(ctx, parent))
+	child.ParentID = &parent.ID
+	require.NoError(t, src.folder.Update(ctx, *child))
+
+	blob := filepath.Join(srcRoot, "1", "1", "id_rsa")
+	require.NoError(t, os.MkdirAll(filepath.Dir(blob), 0700))
+	require.NoError(t, os.WriteFile(blob, []byte("encrypted key"), 0600))
+
+	file := &domain.File{Name: "id_rsa", Path: blob}
+	require.NoError(t, src.file.Insert(ctx, file))
+	missing := &domain.File{Name: "lost", Path: filepath.Join(srcRoot, "1", "2", "lost")}
+	require.NoError(t, src.file.Insert(ctx, missing))
+
+	otp := "encrypted-otp"
+	data := &domain.Data{Name: "db", UID: uid, Version: 1700000000, FolderID: &child.ID, FileID: &file.ID, Otp: &otp, Tags: []string{"prod", "ssh"}}
+	require.NoError(t, src.data.Insert(ctx, data))
+
+	org := &domain.Organization{Name: "team", KeyVersion: 1}
+	require.NoError(t, src.org.Insert(ctx, org, domain.OrgMember{UID: uid, Role: domain.OrgRoleOwner, CollectionKey: "sealed", KeyVersion: 1, Accepted: true}))
+
+	bob, err := src.user.Store(ctx, domain.User{Login: "bob", Password: "hash"})
+	require.NoError(t, err)
+	grant := &domain.EmergencyGrant{GrantorUID: uid, GranteeUID: bob, State: domain.EmergencyInvited, Wait: time.Hour, VaultKey: "sealed"}
+	require.NoError(t, src.emergency.Insert(ctx, grant))
+
+	event := &domain.AuditEvent{UID: uid, ActorUID: uid, Actor: "alice", IP: "10.0.0.1", Action: domain.AuditLogin, Outcome: domain.AuditSuccess, CreatedAt: time.Unix(1700000000, 0)}
+	require.NoError(t, src.audit.Insert(ctx, event))
+	checkpoint := &domain.AuditCheckpoint{EventID: event.ID, Hash: event.Hash, Events: 1, CreatedAt: time.Unix(1700000000, 0), Signature: "sig"}
+	require.NoError(t, src.audit.InsertCheckpoint(ctx, checkpoint))
+
+	var archive bytes.Buffer
+	m, err := backup.Backup(ctx, NewBackupDB(src.data.DB), srcRoot, &archive)
+	require.NoError(t, err)
+	assert.Equal(t, migrations.SQLite.Name, m.Storage)
+	assert.Equal(t, []string{"1/2/lost"}, m.Missing)
+	require.Len(t, m.Files, 1)
+	assert.Equal(t, "files/1/1/id_rsa", m.Files[0].Name)
+	assert.Equal(t, 8, m.Schema[DataTableName])
+
+	// пути в исходной базе не изменились
+	got, err := src.file.Get(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blob, got.Path)
+
+	dstDB := openTestDB(t)
+	dst := newTestRepos(t, dstDB)
+	dstRoot := filepath.Join(t.TempDir(), "files")
+
+	_, err = backup.Restore(ctx, NewBackupDB(dstDB), dstRoot, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+
+	got, err = dst.file.Get(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dstRoot, "1", "1", "id_rsa"), got.Path)
+
+	content, err := os.ReadFile(got.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "encrypted key", string(content))
+
+	restored, err := dst.data.Get(ctx, data.ID)
+	require.NoError(t, err)
+	assert.Equal(t, data.Version, restored.Version)
+	assert.Equal(t, otp, *restored.Otp)
+	assert.Equal(t, []string{"prod", "ssh"}, restored.Tags)
+	assert.Equal(t, child.ID, *restored.FolderID)
+
+	folders, err := dst.folder.GetList(ctx, uid)
+	require.NoError(t, err)
+	assert.Len(t, folders, 2)
+
+	user, err := dst.user.GetByLogin(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, uid, user.ID)
+
+	members, err := dst.org.GetMembers(ctx, org.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "alice", members[0].Login)
+	assert.Equal(t, "sealed", members[0].CollectionKey)
+
+	restoredGrant, err := dst.emergency.Get(ctx, grant.ID)
+	require.NoError(t, err)
+	require.NotNil(t, restoredGrant)
+	assert.Equal(t, "bob", restoredGrant.Grantee)
+	assert.Equal(t, time.Hour, restoredGrant.Wait)
+
+	events, err := dst.audit.GetList(ctx, uid, domain.AuditFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AuditEvent{*event}, events)
+
+	checkpoints, err := dst.audit.GetCheckpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AuditCheckpoint{*checkpoint}, checkpoints)
+
+	// автоинкремент продолжается после восстановленных ИД
+	next := &domain.Data{Name: "next", UID: uid, Version: 1}
+	require.NoError(t, dst.data.Insert(ctx, next))
+	assert.Greater(t, next.ID, data.ID)
+
+	t.Run("not empty", func(t *testing.T) {
+		_, err := backup.Restore(ctx, NewBackupDB(dstDB), t.TempDir(), bytes.NewReader(archive.Bytes()))
+		assert.ErrorIs(t, err, backup.ErrNotEmpty)
+	})
+
+	t.Run("existing file is kept", func(t *testing.T) {
+		db := openTestDB(t)
+		newTestRepos(t, db)
+
+		_, err := backup.Restore(ctx, NewBackupDB(db), dstRoot, bytes.NewReader(archive.Bytes()))
+		assert.ErrorIs(t, err, os.ErrExist)
+
+		content, err := os.ReadFile(filepath.Join(dstRoot, "1", "1", "id_rsa"))
+		require.NoError(t, err)
+		assert.Equal(t, "encrypted key", string(content))
+	})
+}
@@ -0,0 +1,370 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gophkeeper/internal/server/repository/dataquery"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+)
+
+const DataTableName = "data"
+
+const dataColumns = `id, name, name_hash, uid, file_id, folder_id, login, pass, text, card_num, card_exp, otp, meta, version, record_key`
+
+// DataRepository структура для взаимодействия с таблицей данных пользователей
+type DataRepository struct {
+	DB        *sql.DB
+	tableName string
+}
+
+func NewDataRepository(ctx context.Context, db *sql.DB, tableName, fileTableName, usersTableName string) (*DataRepository, error) {
+	err := migrate(ctx, db, migrations.Data, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+		migrations.FileTableVar:  fileTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataRepository{
+		DB:        db,
+		tableName: tableName,
+	}, nil
+}
+
+// Insert добавление новой записи вместе с тегами
+func (d *DataRepository) Insert(ctx context.Context, data *domain.Data) error {
+	query := d.setTableName(`insert into #T# (name, name_hash, uid, login, pass, text, card_num, card_exp, otp, meta, version, file_id, folder_id, record_key) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) returning id`)
+
+	return d.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, data.Name, data.NameHash, data.UID, data.Login, data.Pass, data.Text, data.CardNum, data.CardExp, data.Otp, data.Meta, data.Version, data.FileID, data.FolderID, data.RecordKey).Scan(&data.ID)
+		if err != nil {
+			return err
+		}
+
+		return d.setTags(ctx, tx, data.ID, data.Tags)
+	})
+}
+
+// Update обновление записи, теги записи заменяются целиком
+func (d *DataRepository) Update(ctx context.Context, data domain.Data) error {
+	query := d.setTableName(`update #T# set
+		name = ?,
+		name_hash = ?,
+		login = ?,
+		pass = ?,
+		text = ?,
+		card_num = ?,
+		card_exp = ?,
+		otp = ?,
+		meta = ?,
+		version = ?,
+		folder_id = ?,
+		record_key = ?
+		where id = ?
+	`)
+
+	return d.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, data.Name, data.NameHash, data.Login, data.Pass, data.Text, data.CardNum, data.CardExp, data.Otp, data.Meta, data.Version, data.FolderID, data.RecordKey, data.ID)
+		if err != nil {
+			return err
+		}
+
+		return d.setTags(ctx, tx, data.ID, data.Tags)
+	})
+}
+
+// UnsetFolder убрать записи пользователя из перечисленных папок
+func (d *DataRepository) UnsetFolder(ctx context.Context, uid uint64, folderIDs []uint64) error {
+	for _, id := range folderIDs {
+		query := d.setTableName(`update #T# set folder_id = null where uid = ? and folder_id = ?`)
+		if _, err := d.DB.ExecContext(ctx, query, uid, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetFile добавление файла
+func (d *DataRepository) SetFile(ctx context.Context, data domain.Data) error {
+	query := d.setTableName(`update #T# set
+		file_id = ?,
+		version = ?
+		where id = ?
+	`)
+
+	_, err := d.DB.ExecContext(ctx, query, data.FileID, data.Version, data.ID)
+
+	return err
+}
+
+// GetByNameAndUserID получить ИД записи по названию и ИД пользователя
+func (d *DataRepository) GetByNameAndUserID(ctx context.Context, uid uint64, name string) (uint64, error) {
+	query := d.setTableName(`select ` + dataColumns + ` from #T# where uid = ? and name = ?`)
+
+	data, err := d.getOne(ctx, query, uid, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return data.ID, nil
+}
+
+// GetByNameHashAndUserID получить ИД записи по слепому индексу названия и ИД пользователя
+func (d *DataRepository) GetByNameHashAndUserID(ctx context.Context, uid uint64, nameHash string) (uint64, error) {
+	query := d.setTableName(`select ` + dataColumns + ` from #T# where uid = ? and name_hash = ?`)
+
+	data, err := d.getOne(ctx, query, uid, nameHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return data.ID, nil
+}
+
+// Get получить запись по ИД
+func (d *DataRepository) Get(ctx context.Context, id uint64) (*domain.Data, error) {
+	query := d.setTableName(`select ` + dataColumns + ` from #T# where id = ?`)
+
+	row, err := d.getOne(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if row.ID == 0 {
+		return nil, nil
+	}
+
+	return &row, nil
+}
+
+// GetByUser получить запись по ИД для владельца или пользователя, которому предоставлен доступ
+// для чужой записи заполняется доступ, а папка и теги владельца не возвращаются
+func (d *DataRepository) GetByUser(ctx context.Context, id, uid uint64) (*domain.Data, error) {
+	row, err := d.Get(ctx, id)
+	if err != nil || row == nil || row.UID == uid {
+		return row, err
+	}
+
+	share, err := d.GetShare(ctx, id, uid)
+	if err != nil || share == nil {
+		return nil, err
+	}
+
+	row.FolderID, row.Tags, row.Share = nil, nil, share
+
+	return row, nil
+}
+
+// GetShare получить доступ пользователя к записи, nil - если доступа нет
+func (d *DataRepository) GetShare(ctx context.Context, dataID, uid uint64) (*domain.DataShare, error) {
+	shares, err := d.getShares(ctx, uid, dataID)
+	if err != nil {
+		return nil, err
+	}
+
+	share, ok := shares[dataID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &share, nil
+}
+
+// Share предоставить доступ к записи или изменить его
+func (d *DataRepository) Share(ctx context.Context, share domain.DataShare) error {
+	query := d.setTableName(`insert into #T#_share (data_id, uid, record_key, permission) values (?, ?, ?, ?)
+		on conflict (data_id, uid) do update set record_key = excluded.record_key, permission = excluded.permission`)
+
+	_, err := d.DB.ExecContext(ctx, query, share.DataID, share.UID, share.RecordKey, share.Permission)
+
+	return err
+}
+
+// Unshare отозвать доступ пользователя к записи
+func (d *DataRepository) Unshare(ctx context.Context, dataID, uid uint64) error {
+	_, err := d.DB.ExecContext(ctx, d.setTableName(`delete from #T#_share where data_id = ? and uid = ?`), dataID, uid)
+	return err
+}
+
+// GetList получить список для пользователя с учетом фильтра
+func (d *DataRepository) GetList(ctx context.Context, uid uint64, filter domain.DataListFilter) ([]domain.DataName, error) {
+	var res []domain.DataName
+
+	query, args := dataquery.List(migrations.SQLite.Param, uid, filter)
+	query = d.setTableName(query)
+
+	rows, err := d.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return res, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dn domain.DataName
+		if err = rows.Scan(&dn.ID, &dn.Name, &dn.Version, &dn.FolderID, &dn.UID, &dn.RecordKey); err != nil {
+			return res, err
+		}
+
+		res = append(res, dn)
+	}
+
+	if err = rows.Err(); err != nil {
+		return res, err
+	}
+
+	if err = rows.Close(); err != nil {
+		return res, err
+	}
+
+	var own, shared []uint64
+	for _, dn := range res {
+		if dn.UID == uid {
+			own = append(own, dn.ID)
+		} else {
+			shared = append(shared, dn.ID)
+		}
+	}
+
+	tags, err := d.getTags(ctx, own...)
+	if err != nil {
+		return res, err
+	}
+
+	shares, err := d.getShares(ctx, uid, shared...)
+	if err != nil {
+		return res, err
+	}
+
+	for i := range res {
+		res[i].Tags = tags[res[i].ID]
+
+		if share, ok := shares[res[i].ID]; ok {
+			res[i].FolderID, res[i].Share = nil, &share
+		}
+	}
+
+	return res, nil
+}
+
+// Delete удалить запись
+func (d *DataRepository) Delete(ctx context.Context, id uint64) error {
+	query := d.setTableName(`delete from #T# where id = ?`)
+	_, err := d.DB.ExecContext(ctx, query, id)
+	return err
+}
+
+func (d *DataRepository) getOne(ctx context.Context, query string, args ...interface{}) (data domain.Data, err error) {
+	err = d.DB.QueryRowContext(ctx, query, args...).Scan(
+		&data.ID, &data.Name, &data.NameHash, &data.UID, &data.FileID, &data.FolderID, &data.Login, &data.Pass, &data.Text, &data.CardNum, &data.CardExp, &data.Otp, &data.Meta, &data.Version, &data.RecordKey,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Data{}, nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	tags, err := d.getTags(ctx, data.ID)
+	if err != nil {
+		return
+	}
+
+	data.Tags = tags[data.ID]
+
+	return
+}
+
+// getTags получить теги записей, сгруппированные по ИД записи
+func (d *DataRepository) getTags(ctx context.Context, ids ...uint64) (map[uint64][]string, error) {
+	res := make(map[uint64][]string, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	query, args := dataquery.Tags(migrations.SQLite.Param, ids)
+
+	rows, err := d.DB.QueryContext(ctx, d.setTableName(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint64
+		var tag string
+		if err = rows.Scan(&id, &tag); err != nil {
+			return nil, err
+		}
+
+		res[id] = append(res[id], tag)
+	}
+
+	return res, rows.Err()
+}
+
+// getShares получить доступы пользователя к записям, сгруппированные по ИД записи
+func (d *DataRepository) getShares(ctx context.Context, uid uint64, ids ...uint64) (map[uint64]domain.DataShare, error) {
+	res := make(map[uint64]domain.DataShare, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	query, args := dataquery.Shares(migrations.SQLite.Param, uid, ids)
+
+	rows, err := d.DB.QueryContext(ctx, d.setTableName(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var share domain.DataShare
+		if err = rows.Scan(&share.DataID, &share.UID, &share.RecordKey, &share.Permission); err != nil {
+			return nil, err
+		}
+
+		res[share.DataID] = share
+	}
+
+	return res, rows.Err()
+}
+
+// setTags замена тегов записи
+func (d *DataRepository) setTags(ctx context.Context, tx *sql.Tx, id uint64, tags []string) error {
+	if _, err := tx.ExecContext(ctx, d.setTableName(`delete from #T#_tag where data_id = ?`), id); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, d.setTableName(`insert into #T#_tag (data_id, tag) values (?, ?) on conflict do nothing`), id, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DataRepository) inTx(ctx context.Context, f func(tx *sql.Tx) error) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = f(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (d *DataRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", d.tableName)
+}
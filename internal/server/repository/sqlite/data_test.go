@@ -0,0 +1,250 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"gophkeeper/server/domain"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := Open(context.Background(), "sqlite://"+filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, db.Close())
+	})
+
+	return db
+}
+
+func TestDataRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	userRepo, err := NewUserRepository(ctx, db, UsersTableName)
+	assert.NoError(t, err)
+	fileRepo, err := NewFileRepository(ctx, db, FileTableName)
+	assert.NoError(t, err)
+	repo, err := NewDataRepository(ctx, db, DataTableName, FileTableName, UsersTableName)
+	assert.NoError(t, err)
+
+	// повторное применение миграций не должно приводить к ошибке
+	_, err = NewDataRepository(ctx, db, DataTableName, FileTableName, UsersTableName)
+	assert.NoError(t, err)
+
+	uid, err := userRepo.Store(ctx, domain.User{Login: "test", Password: "test"})
+	assert.NoError(t, err)
+
+	login := "login"
+	data := &domain.Data{Name: "test", UID: uid, Version: 1, Login: &login}
+	assert.NoError(t, repo.Insert(ctx, data))
+	assert.NotZero(t, data.ID)
+
+	t.Run("name not uniq", func(t *testing.T) {
+		err = repo.Insert(ctx, &domain.Data{Name: "test", UID: uid, Version: 1})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		err = repo.Insert(ctx, &domain.Data{Name: "other", UID: uid + 100, Version: 1})
+		assert.Error(t, err)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		var got *domain.Data
+		got, err = repo.GetByUser(ctx, data.ID, uid)
+		assert.NoError(t, err)
+		assert.Equal(t, login, *got.Login)
+		assert.Nil(t, got.Pass)
+		assert.Nil(t, got.FileID)
+
+		got, err = repo.GetByUser(ctx, data.ID, uid+1)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		var id uint64
+		id, err = repo.GetByNameAndUserID(ctx, uid, "absent")
+		assert.NoError(t, err)
+		assert.Zero(t, id)
+	})
+
+	t.Run("name hash", func(t *testing.T) {
+		hash := "hash"
+		withHash := &domain.Data{Name: "encrypted", NameHash: &hash, UID: uid, Version: 1}
+		assert.NoError(t, repo.Insert(ctx, withHash))
+
+		var id uint64
+		id, err = repo.GetByNameHashAndUserID(ctx, uid, hash)
+		assert.NoError(t, err)
+		assert.Equal(t, withHash.ID, id)
+
+		err = repo.Insert(ctx, &domain.Data{Name: "encrypted again", NameHash: &hash, UID: uid, Version: 1})
+		assert.Error(t, err)
+
+		assert.NoError(t, repo.Delete(ctx, withHash.ID))
+	})
+
+	t.Run("card expiry", func(t *testing.T) {
+		exp := "08/27"
+		data.CardExp = &exp
+		assert.NoError(t, repo.Update(ctx, *data))
+
+		var got *domain.Data
+		got, err = repo.Get(ctx, data.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, exp, *got.CardExp)
+	})
+
+	t.Run("otp", func(t *testing.T) {
+		key := "encrypted-otp-key"
+		data.Otp = &key
+		assert.NoError(t, repo.Update(ctx, *data))
+
+		var list []domain.DataName
+		list, err = repo.GetList(ctx, uid, domain.DataListFilter{Type: domain.DataTypeOTP})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("set file", func(t *testing.T) {
+		file := &domain.File{Name: "file", Path: "/tmp/file"}
+		assert.NoError(t, fileRepo.Insert(ctx, file))
+
+		data.FileID = &file.ID
+		data.Version = 2
+		assert.NoError(t, repo.SetFile(ctx, *data))
+
+		var got *domain.Data
+		got, err = repo.Get(ctx, data.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, file.ID, *got.FileID)
+		assert.Equal(t, uint64(2), got.Version)
+	})
+
+	t.Run("list and delete", func(t *testing.T) {
+		var list []domain.DataName
+		list, err = repo.GetList(ctx, uid, domain.DataListFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+
+		assert.NoError(t, repo.Delete(ctx, data.ID))
+
+		list, err = repo.GetList(ctx, uid, domain.DataListFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+	})
+}
+
+func TestPathFromURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{name: "absolute", uri: "sqlite:///var/lib/gophkeeper.db", want: "/var/lib/gophkeeper.db"},
+		{name: "relative", uri: "sqlite://gophkeeper.db", want: "gophkeeper.db"},
+		{name: "postgres", uri: "postgres://localhost/db", wantErr: true},
+		{name: "empty path", uri: "sqlite://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PathFromURI(tt.uri)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDataRepository_GetListFilter(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	userRepo, err := NewUserRepository(ctx, db, UsersTableName)
+	assert.NoError(t, err)
+	_, err = NewFileRepository(ctx, db, FileTableName)
+	assert.NoError(t, err)
+	repo, err := NewDataRepository(ctx, db, DataTableName, FileTableName, UsersTableName)
+	assert.NoError(t, err)
+
+	uid, err := userRepo.Store(ctx, domain.User{Login: "test", Password: "test"})
+	assert.NoError(t, err)
+
+	text, card := "text", "4111"
+	for _, d := range []domain.Data{
+		{Name: "bank card", CardNum: &card},
+		{Name: "Bank_note", Text: &text},
+		{Name: "mail", Text: &text},
+		{Name: "bankomat"},
+	} {
+		d.UID, d.Version = uid, 1
+		assert.NoError(t, repo.Insert(ctx, &d))
+	}
+
+	names := func(list []domain.DataName) []string {
+		res := make([]string, 0, len(list))
+		for _, d := range list {
+			res = append(res, d.Name)
+		}
+
+		return res
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.DataListFilter
+		want   []string
+	}{
+		{
+			name:   "prefix case sensitive",
+			filter: domain.DataListFilter{NamePrefix: "bank"},
+			want:   []string{"bank card", "bankomat"},
+		},
+		{
+			name:   "prefix with like wildcard",
+			filter: domain.DataListFilter{NamePrefix: "Bank_"},
+			want:   []string{"Bank_note"},
+		},
+		{
+			name:   "contains ignore case",
+			filter: domain.DataListFilter{NameContains: "BANK", Sort: domain.DataSortNameDesc},
+			want:   []string{"bankomat", "bank card", "Bank_note"},
+		},
+		{
+			name:   "type text",
+			filter: domain.DataListFilter{Type: domain.DataTypeText},
+			want:   []string{"Bank_note", "mail"},
+		},
+		{
+			name:   "type card",
+			filter: domain.DataListFilter{Type: domain.DataTypeCard},
+			want:   []string{"bank card"},
+		},
+		{
+			name:   "page after cursor",
+			filter: domain.DataListFilter{Limit: 2, After: &domain.DataListCursor{Name: "bank card", ID: 1}},
+			want:   []string{"bankomat", "mail"},
+		},
+		{
+			name:   "id desc",
+			filter: domain.DataListFilter{Sort: domain.DataSortIDDesc, Limit: 1},
+			want:   []string{"bankomat"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetList(ctx, uid, tt.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, names(got))
+		})
+	}
+}
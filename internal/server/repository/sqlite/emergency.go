@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+	"time"
+)
+
+const EmergencyTableName = "emergency"
+
+const grantColumns = `e.id, e.grantor_uid, e.grantee_uid, gr.login, ge.login, e.state, e.wait_seconds, e.vault_key, e.requested_at`
+
+// EmergencyRepository структура для взаимодействия с таблицей экстренного доступа
+type EmergencyRepository struct {
+	DB *sql.DB
+	tableName,
+	usersTableName string
+}
+
+func NewEmergencyRepository(ctx context.Context, db *sql.DB, tableName, usersTableName string) (*EmergencyRepository, error) {
+	err := migrate(ctx, db, migrations.Emergency, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmergencyRepository{
+		DB:             db,
+		tableName:      tableName,
+		usersTableName: usersTableName,
+	}, nil
+}
+
+// Insert сохранение нового экстренного доступа
+func (e *EmergencyRepository) Insert(ctx context.Context, grant *domain.EmergencyGrant) error {
+	query := e.setTableName(`insert into #T# (grantor_uid, grantee_uid, state, wait_seconds, vault_key, requested_at)
+		values (?, ?, ?, ?, ?, ?) returning id`)
+
+	return e.DB.QueryRowContext(ctx, query, grant.GrantorUID, grant.GranteeUID, grant.State, int64(grant.Wait/time.Second),
+		grant.VaultKey, unixTime(grant.RequestedAt)).Scan(&grant.ID)
+}
+
+// Get получить экстренный доступ по ИД, если доступ не найден - возвращается nil
+func (e *EmergencyRepository) Get(ctx context.Context, id uint64) (*domain.EmergencyGrant, error) {
+	return e.getOne(ctx, `e.id = ?`, id)
+}
+
+// GetByPair получить экстренный доступ доверенного лица к хранилищу владельца, если доступа нет - возвращается nil
+func (e *EmergencyRepository) GetByPair(ctx context.Context, grantorUID, granteeUID uint64) (*domain.EmergencyGrant, error) {
+	return e.getOne(ctx, `e.grantor_uid = ? and e.grantee_uid = ?`, grantorUID, granteeUID)
+}
+
+// GetByUser экстренные доступы, в которых пользователь владелец или доверенное лицо
+func (e *EmergencyRepository) GetByUser(ctx context.Context, uid uint64) ([]domain.EmergencyGrant, error) {
+	return e.getList(ctx, `e.grantor_uid = ? or e.grantee_uid = ?`, uid, uid)
+}
+
+// Update изменение состояния экстренного доступа, который все еще находится в состоянии state,
+// иначе возвращается domain.ErrGrantState
+func (e *EmergencyRepository) Update(ctx context.Context, grant domain.EmergencyGrant, state domain.EmergencyState) error {
+	res, err := e.DB.ExecContext(ctx, e.setTableName(`update #T# set state = ?, requested_at = ? where id = ? and state = ?`),
+		grant.State, unixTime(grant.RequestedAt), grant.ID, state)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return domain.ErrGrantState
+	}
+
+	return nil
+}
+
+// Delete удаление экстренного доступа
+func (e *EmergencyRepository) Delete(ctx context.Context, id uint64) error {
+	_, err := e.DB.ExecContext(ctx, e.setTableName(`delete from #T# where id = ?`), id)
+	return err
+}
+
+// AutoApprove одобрение запросов, время ожидания которых истекло к моменту now, возвращается число одобренных запросов
+func (e *EmergencyRepository) AutoApprove(ctx context.Context, now time.Time) (int64, error) {
+	res, err := e.DB.ExecContext(ctx, e.setTableName(`update #T# set state = ? where state = ? and requested_at + wait_seconds <= ?`),
+		domain.EmergencyAutoApproved, domain.EmergencyRequested, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (e *EmergencyRepository) getOne(ctx context.Context, where string, args ...any) (*domain.EmergencyGrant, error) {
+	list, err := e.getList(ctx, where, args...)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+func (e *EmergencyRepository) getList(ctx context.Context, where string, args ...any) ([]domain.EmergencyGrant, error) {
+	var res []domain.EmergencyGrant
+	query := e.setTableName(`select ` + grantColumns + ` from #T# e join #UT# gr on gr.id = e.grantor_uid
+		join #UT# ge on ge.id = e.grantee_uid where ` + where + ` order by e.id`)
+
+	rows, err := e.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			g           domain.EmergencyGrant
+			wait        int64
+			requestedAt sql.NullInt64
+		)
+
+		err = rows.Scan(&g.ID, &g.GrantorUID, &g.GranteeUID, &g.Grantor, &g.Grantee, &g.State, &wait, &g.VaultKey, &requestedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		g.Wait = time.Duration(wait) * time.Second
+		if requestedAt.Valid {
+			t := time.Unix(requestedAt.Int64, 0)
+			g.RequestedAt = &t
+		}
+
+		res = append(res, g)
+	}
+
+	return res, rows.Err()
+}
+
+func (e *EmergencyRepository) setTableName(query string) string {
+	return strings.NewReplacer("#T#", e.tableName, "#UT#", e.usersTableName).Replace(query)
+}
+
+// unixTime время в секундах unix для хранения в базе, nil - NULL
+func unixTime(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+
+	sec := t.Unix()
+
+	return &sec
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmergencyRepository(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(t, openTestDB(t))
+
+	alice, err := r.user.Store(ctx, domain.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+	bob, err := r.user.Store(ctx, domain.User{Login: "bob", Password: "hash"})
+	require.NoError(t, err)
+
+	grant := &domain.EmergencyGrant{GrantorUID: alice, GranteeUID: bob, State: domain.EmergencyInvited, Wait: time.Hour, VaultKey: "sealed"}
+	require.NoError(t, r.emergency.Insert(ctx, grant))
+	assert.Error(t, r.emergency.Insert(ctx, &domain.EmergencyGrant{GrantorUID: alice, GranteeUID: bob, State: domain.EmergencyInvited, Wait: time.Hour}))
+
+	got, err := r.emergency.GetByPair(ctx, alice, bob)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "alice", got.Grantor)
+	assert.Equal(t, "bob", got.Grantee)
+	assert.Nil(t, got.RequestedAt)
+
+	missing, err := r.emergency.GetByPair(ctx, bob, alice)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	requested := time.Unix(1700000000, 0)
+	got.State, got.RequestedAt = domain.EmergencyRequested, &requested
+	assert.ErrorIs(t, r.emergency.Update(ctx, *got, domain.EmergencyAccepted), domain.ErrGrantState)
+	require.NoError(t, r.emergency.Update(ctx, *got, domain.EmergencyInvited))
+
+	n, err := r.emergency.AutoApprove(ctx, requested.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = r.emergency.AutoApprove(ctx, requested.Add(time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	list, err := r.emergency.GetByUser(ctx, bob)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, domain.EmergencyAutoApproved, list[0].State)
+	assert.True(t, requested.Equal(*list[0].RequestedAt))
+
+	// запрос, прочитанный до автоматического одобрения, уже не изменить
+	got.State = domain.EmergencyApproved
+	assert.ErrorIs(t, r.emergency.Update(ctx, *got, domain.EmergencyRequested), domain.ErrGrantState)
+
+	require.NoError(t, r.emergency.Delete(ctx, grant.ID))
+	got, err = r.emergency.Get(ctx, grant.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
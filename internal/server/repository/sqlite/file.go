@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+)
+
+const FileTableName = "file"
+
+// FileRepository структура для взаимодействия с таблицей файлов
+type FileRepository struct {
+	DB        *sql.DB
+	tableName string
+}
+
+func NewFileRepository(ctx context.Context, db *sql.DB, tableName string) (*FileRepository, error) {
+	err := migrate(ctx, db, migrations.File, map[string]string{
+		migrations.TableVar: tableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileRepository{
+		DB:        db,
+		tableName: tableName,
+	}, nil
+}
+
+// Get получить файл по ИД, если файл не найден - возвращается nil
+func (f *FileRepository) Get(ctx context.Context, id uint64) (*domain.File, error) {
+	var file domain.File
+	query := f.setTableName(`select id, name, path from #T# where id = ?`)
+
+	err := f.DB.QueryRowContext(ctx, query, id).Scan(&file.ID, &file.Name, &file.Path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Insert добавить запись
+func (f *FileRepository) Insert(ctx context.Context, file *domain.File) error {
+	query := f.setTableName(`insert into #T# (name, path) values (?, ?) returning id`)
+
+	return f.DB.QueryRowContext(ctx, query, file.Name, file.Path).Scan(&file.ID)
+}
+
+// Update обновить запись
+func (f *FileRepository) Update(ctx context.Context, file *domain.File) error {
+	query := f.setTableName(`update #T# set
+		name = ?,
+		path = ?
+		where id = ?
+	`)
+
+	_, err := f.DB.ExecContext(ctx, query, file.Name, file.Path, file.ID)
+
+	return err
+}
+
+// Delete удалить запись
+func (f *FileRepository) Delete(ctx context.Context, id uint64) error {
+	query := f.setTableName(`delete from #T# where id = ?`)
+	_, err := f.DB.ExecContext(ctx, query, id)
+	return err
+}
+
+func (f *FileRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", f.tableName)
+}
@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+)
+
+const FolderTableName = "folder"
+
+// FolderRepository структура для взаимодействия с таблицей папок
+type FolderRepository struct {
+	DB        *sql.DB
+	tableName string
+}
+
+func NewFolderRepository(ctx context.Context, db *sql.DB, tableName, usersTableName string) (*FolderRepository, error) {
+	err := migrate(ctx, db, migrations.Folder, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FolderRepository{
+		DB:        db,
+		tableName: tableName,
+	}, nil
+}
+
+// Insert добавление папки
+func (f *FolderRepository) Insert(ctx context.Context, folder *domain.Folder) error {
+	query := f.setTableName(`insert into #T# (uid, parent_id, name) values (?, ?, ?) returning id`)
+
+	return f.DB.QueryRowContext(ctx, query, folder.UID, folder.ParentID, folder.Name).Scan(&folder.ID)
+}
+
+// Update переименование или перемещение папки
+func (f *FolderRepository) Update(ctx context.Context, folder domain.Folder) error {
+	query := f.setTableName(`update #T# set
+		parent_id = ?,
+		name = ?
+		where id = ?
+	`)
+
+	_, err := f.DB.ExecContext(ctx, query, folder.ParentID, folder.Name, folder.ID)
+
+	return err
+}
+
+// GetByUser получить папку по ИД для пользователя, если папка не найдена - возвращается nil
+func (f *FolderRepository) GetByUser(ctx context.Context, id, uid uint64) (*domain.Folder, error) {
+	var folder domain.Folder
+	query := f.setTableName(`select id, uid, parent_id, name from #T# where id = ? and uid = ?`)
+
+	err := f.DB.QueryRowContext(ctx, query, id, uid).Scan(&folder.ID, &folder.UID, &folder.ParentID, &folder.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &folder, nil
+}
+
+// GetList получить все папки пользователя
+func (f *FolderRepository) GetList(ctx context.Context, uid uint64) ([]domain.Folder, error) {
+	var res []domain.Folder
+	query := f.setTableName(`select id, uid, parent_id, name from #T# where uid = ? order by name, id`)
+
+	rows, err := f.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var folder domain.Folder
+		if err = rows.Scan(&folder.ID, &folder.UID, &folder.ParentID, &folder.Name); err != nil {
+			return nil, err
+		}
+
+		res = append(res, folder)
+	}
+
+	return res, rows.Err()
+}
+
+// Delete удалить папки
+func (f *FolderRepository) Delete(ctx context.Context, ids []uint64) error {
+	for _, id := range ids {
+		if _, err := f.DB.ExecContext(ctx, f.setTableName(`delete from #T# where id = ?`), id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *FolderRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", f.tableName)
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFolderRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	userRepo, err := NewUserRepository(ctx, db, UsersTableName)
+	assert.NoError(t, err)
+	_, err = NewFileRepository(ctx, db, FileTableName)
+	assert.NoError(t, err)
+	dataRepo, err := NewDataRepository(ctx, db, DataTableName, FileTableName, UsersTableName)
+	assert.NoError(t, err)
+	repo, err := NewFolderRepository(ctx, db, FolderTableName, UsersTableName)
+	assert.NoError(t, err)
+
+	uid, err := userRepo.Store(ctx, domain.User{Login: "test", Password: "test"})
+	assert.NoError(t, err)
+
+	root := &domain.Folder{Name: "work", UID: uid}
+	assert.NoError(t, repo.Insert(ctx, root))
+	child := &domain.Folder{Name: "bank", UID: uid, ParentID: &root.ID}
+	assert.NoError(t, repo.Insert(ctx, child))
+
+	t.Run("get", func(t *testing.T) {
+		got, err := repo.GetByUser(ctx, child.ID, uid)
+		assert.NoError(t, err)
+		assert.Equal(t, child, got)
+
+		got, err = repo.GetByUser(ctx, child.ID, uid+1)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	data := &domain.Data{Name: "card", UID: uid, Version: 1, FolderID: &child.ID, Tags: []string{"bank", "personal"}}
+	assert.NoError(t, dataRepo.Insert(ctx, data))
+
+	t.Run("data tags and folder", func(t *testing.T) {
+		got, err := dataRepo.Get(ctx, data.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bank", "personal"}, got.Tags)
+		assert.Equal(t, child.ID, *got.FolderID)
+
+		data.Tags = []string{"personal"}
+		assert.NoError(t, dataRepo.Update(ctx, *data))
+
+		list, err := dataRepo.GetList(ctx, uid, domain.DataListFilter{FolderID: child.ID, Tags: []string{"personal"}})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, []string{"personal"}, list[0].Tags)
+
+		list, err = dataRepo.GetList(ctx, uid, domain.DataListFilter{Tags: []string{"bank"}})
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, dataRepo.UnsetFolder(ctx, uid, []uint64{root.ID, child.ID}))
+		assert.NoError(t, repo.Delete(ctx, []uint64{root.ID, child.ID}))
+
+		list, err := repo.GetList(ctx, uid)
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+
+		got, err := dataRepo.Get(ctx, data.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, got.FolderID)
+
+		// теги удаляются вместе с записью
+		assert.NoError(t, dataRepo.Delete(ctx, data.ID))
+
+		var count int
+		assert.NoError(t, db.QueryRow(`select count(*) from data_tag`).Scan(&count))
+		assert.Zero(t, count)
+	})
+}
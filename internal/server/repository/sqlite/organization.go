@@ -0,0 +1,242 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+)
+
+const OrganizationTableName = "organization"
+
+const memberColumns = `m.org_id, m.uid, u.login, m.role, m.collection_key, m.key_version, m.accepted`
+
+// OrganizationRepository структура для взаимодействия с таблицами организаций и их участников
+// смена ключа хранилища изменяет записи и папки хранилища, поэтому нужны и их таблицы
+type OrganizationRepository struct {
+	DB *sql.DB
+	tableName,
+	usersTableName,
+	dataTableName,
+	folderTableName string
+}
+
+func NewOrganizationRepository(ctx context.Context, db *sql.DB, tableName, usersTableName, dataTableName, folderTableName string) (*OrganizationRepository, error) {
+	err := migrate(ctx, db, migrations.Organization, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrganizationRepository{
+		DB:              db,
+		tableName:       tableName,
+		usersTableName:  usersTableName,
+		dataTableName:   dataTableName,
+		folderTableName: folderTableName,
+	}, nil
+}
+
+// Insert создание организации вместе с учетной записью хранилища и владельцем
+func (o *OrganizationRepository) Insert(ctx context.Context, org *domain.Organization, owner domain.OrgMember) error {
+	return o.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, o.setTableName(`insert into #UT# (login, password) values ('', '') returning id`)).Scan(&org.VaultUID)
+		if err != nil {
+			return err
+		}
+
+		query := o.setTableName(`insert into #T# (vault_uid, name, key_version) values (?, ?, ?) returning id`)
+		if err = tx.QueryRowContext(ctx, query, org.VaultUID, org.Name, org.KeyVersion).Scan(&org.ID); err != nil {
+			return err
+		}
+
+		owner.OrgID = org.ID
+
+		return o.saveMember(ctx, tx, owner)
+	})
+}
+
+// Get получить организацию по ИД, если организация не найдена - возвращается nil
+func (o *OrganizationRepository) Get(ctx context.Context, id uint64) (*domain.Organization, error) {
+	var org domain.Organization
+	query := o.setTableName(`select id, vault_uid, name, key_version from #T# where id = ?`)
+
+	err := o.DB.QueryRowContext(ctx, query, id).Scan(&org.ID, &org.VaultUID, &org.Name, &org.KeyVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+// GetByUser получить организации, в которых состоит или приглашен пользователь
+func (o *OrganizationRepository) GetByUser(ctx context.Context, uid uint64) ([]domain.OrgMembership, error) {
+	var res []domain.OrgMembership
+	query := o.setTableName(`select o.id, o.vault_uid, o.name, o.key_version, ` + memberColumns + `
+		from #T#_member m join #T# o on o.id = m.org_id join #UT# u on u.id = m.uid
+		where m.uid = ? order by o.name, o.id`)
+
+	rows, err := o.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m domain.OrgMembership
+
+		err = rows.Scan(&m.Org.ID, &m.Org.VaultUID, &m.Org.Name, &m.Org.KeyVersion, &m.Member.OrgID, &m.Member.UID, &m.Member.Login,
+			&m.Member.Role, &m.Member.CollectionKey, &m.Member.KeyVersion, &m.Member.Accepted)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, m)
+	}
+
+	return res, rows.Err()
+}
+
+// GetMember получить участника организации, если пользователь не состоит в организации - возвращается nil
+func (o *OrganizationRepository) GetMember(ctx context.Context, orgID, uid uint64) (*domain.OrgMember, error) {
+	list, err := o.getMembers(ctx, `m.org_id = ? and m.uid = ?`, orgID, uid)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+// GetMembers получить всех участников организации, включая приглашенных
+func (o *OrganizationRepository) GetMembers(ctx context.Context, orgID uint64) ([]domain.OrgMember, error) {
+	return o.getMembers(ctx, `m.org_id = ?`, orgID)
+}
+
+// SaveMember добавление или изменение участника организации
+func (o *OrganizationRepository) SaveMember(ctx context.Context, member domain.OrgMember) error {
+	return o.saveMember(ctx, o.DB, member)
+}
+
+// DeleteMember исключение участника из организации
+func (o *OrganizationRepository) DeleteMember(ctx context.Context, orgID, uid uint64) error {
+	_, err := o.DB.ExecContext(ctx, o.setTableName(`delete from #T#_member where org_id = ? and uid = ?`), orgID, uid)
+	return err
+}
+
+// Rotate исключение участника и смена ключа хранилища в одной транзакции
+// если ключ уже сменили параллельно, возвращается ErrOrgKeyVersion
+func (o *OrganizationRepository) Rotate(ctx context.Context, rotation domain.OrgRotation) error {
+	return o.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, o.setTableName(`update #T# set key_version = ? where id = ? and key_version = ?`),
+			rotation.KeyVersion, rotation.OrgID, rotation.KeyVersion-1)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return domain.ErrOrgKeyVersion
+		}
+
+		if _, err = tx.ExecContext(ctx, o.setTableName(`delete from #T#_member where org_id = ? and uid = ?`), rotation.OrgID, rotation.RemoveUID); err != nil {
+			return err
+		}
+
+		for uid, key := range rotation.Keys {
+			query := o.setTableName(`update #T#_member set collection_key = ?, key_version = ? where org_id = ? and uid = ?`)
+			if _, err = tx.ExecContext(ctx, query, key, rotation.KeyVersion, rotation.OrgID, uid); err != nil {
+				return err
+			}
+		}
+
+		for _, r := range rotation.Records {
+			query := o.setTableName(`update #DT# set record_key = ?, name_hash = ? where id = ? and uid = ?`)
+			if _, err = tx.ExecContext(ctx, query, r.RecordKey, r.NameHash, r.ID, rotation.VaultUID); err != nil {
+				return err
+			}
+
+			if _, err = tx.ExecContext(ctx, o.setTableName(`delete from #DT#_tag where data_id = ?`), r.ID); err != nil {
+				return err
+			}
+
+			for _, tag := range r.Tags {
+				query = o.setTableName(`insert into #DT#_tag (data_id, tag) values (?, ?) on conflict do nothing`)
+				if _, err = tx.ExecContext(ctx, query, r.ID, tag); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, f := range rotation.Folders {
+			query := o.setTableName(`update #FOT# set name = ? where id = ? and uid = ?`)
+			if _, err = tx.ExecContext(ctx, query, f.Name, f.ID, rotation.VaultUID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (o *OrganizationRepository) getMembers(ctx context.Context, where string, args ...any) ([]domain.OrgMember, error) {
+	var res []domain.OrgMember
+	query := o.setTableName(`select ` + memberColumns + ` from #T#_member m join #UT# u on u.id = m.uid
+		where ` + where + ` order by m.role desc, u.login`)
+
+	rows, err := o.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m domain.OrgMember
+		if err = rows.Scan(&m.OrgID, &m.UID, &m.Login, &m.Role, &m.CollectionKey, &m.KeyVersion, &m.Accepted); err != nil {
+			return nil, err
+		}
+
+		res = append(res, m)
+	}
+
+	return res, rows.Err()
+}
+
+func (o *OrganizationRepository) saveMember(ctx context.Context, db execQuerier, member domain.OrgMember) error {
+	query := o.setTableName(`insert into #T#_member (org_id, uid, role, collection_key, key_version, accepted) values (?, ?, ?, ?, ?, ?)
+		on conflict (org_id, uid) do update set role = excluded.role, collection_key = excluded.collection_key,
+		key_version = excluded.key_version, accepted = excluded.accepted`)
+
+	_, err := db.ExecContext(ctx, query, member.OrgID, member.UID, member.Role, member.CollectionKey, member.KeyVersion, member.Accepted)
+
+	return err
+}
+
+func (o *OrganizationRepository) inTx(ctx context.Context, f func(tx *sql.Tx) error) error {
+	tx, err := o.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = f(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (o *OrganizationRepository) setTableName(query string) string {
+	return strings.NewReplacer("#T#", o.tableName, "#UT#", o.usersTableName, "#DT#", o.dataTableName, "#FOT#", o.folderTableName).Replace(query)
+}
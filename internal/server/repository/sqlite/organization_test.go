@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationRepository(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(t, openTestDB(t))
+
+	alice, err := r.user.Store(ctx, domain.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+	bob, err := r.user.Store(ctx, domain.User{Login: "bob", Password: "hash"})
+	require.NoError(t, err)
+
+	org := &domain.Organization{Name: "team", KeyVersion: 1}
+	require.NoError(t, r.org.Insert(ctx, org, domain.OrgMember{UID: alice, Role: domain.OrgRoleOwner, CollectionKey: "key-alice", KeyVersion: 1, Accepted: true}))
+	require.NoError(t, r.org.SaveMember(ctx, domain.OrgMember{OrgID: org.ID, UID: bob, Role: domain.OrgRoleViewer, CollectionKey: "key-bob", KeyVersion: 1}))
+
+	got, err := r.org.Get(ctx, org.ID)
+	require.NoError(t, err)
+	assert.Equal(t, org, got)
+
+	list, err := r.org.GetByUser(ctx, bob)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "team", list[0].Org.Name)
+	assert.Equal(t, "bob", list[0].Member.Login)
+	assert.False(t, list[0].Member.Accepted)
+
+	members, err := r.org.GetMembers(ctx, org.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "alice", members[0].Login)
+
+	recordKey, nameHash := "key", "hash"
+	record := &domain.Data{Name: "db", UID: org.VaultUID, Version: 1, RecordKey: &recordKey, NameHash: &nameHash, Tags: []string{"old"}}
+	require.NoError(t, r.data.Insert(ctx, record))
+	folder := &domain.Folder{Name: "old", UID: org.VaultUID}
+	require.NoError(t, r.folder.Insert(ctx, folder))
+
+	newHash := "hash2"
+	rotation := domain.OrgRotation{
+		OrgID:      org.ID,
+		VaultUID:   org.VaultUID,
+		RemoveUID:  bob,
+		KeyVersion: 2,
+		Keys:       map[uint64]string{alice: "key2"},
+		Records:    []domain.RotatedRecord{{ID: record.ID, RecordKey: "key2", NameHash: &newHash, Tags: []string{"new"}}},
+		Folders:    []domain.Folder{{ID: folder.ID, Name: "new"}},
+	}
+	require.NoError(t, r.org.Rotate(ctx, rotation))
+	assert.ErrorIs(t, r.org.Rotate(ctx, rotation), domain.ErrOrgKeyVersion)
+
+	m, err := r.org.GetMember(ctx, org.ID, bob)
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	m, err = r.org.GetMember(ctx, org.ID, alice)
+	require.NoError(t, err)
+	assert.Equal(t, "key2", m.CollectionKey)
+	assert.Equal(t, uint64(2), m.KeyVersion)
+
+	saved, err := r.data.Get(ctx, record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "key2", *saved.RecordKey)
+	assert.Equal(t, newHash, *saved.NameHash)
+	assert.Equal(t, []string{"new"}, saved.Tags)
+
+	folders, err := r.folder.GetList(ctx, org.VaultUID)
+	require.NoError(t, err)
+	assert.Equal(t, "new", folders[0].Name)
+}
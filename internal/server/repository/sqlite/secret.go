@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+	"time"
+)
+
+const SecretShareTableName = "secret_share"
+
+// SecretRepository структура для взаимодействия с таблицей одноразовых ссылок на секреты
+type SecretRepository struct {
+	DB *sql.DB
+	tableName,
+	usersTableName string
+}
+
+func NewSecretRepository(ctx context.Context, db *sql.DB, tableName, usersTableName string) (*SecretRepository, error) {
+	err := migrate(ctx, db, migrations.SecretShare, map[string]string{
+		migrations.TableVar:      tableName,
+		migrations.UsersTableVar: usersTableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretRepository{
+		DB:             db,
+		tableName:      tableName,
+		usersTableName: usersTableName,
+	}, nil
+}
+
+// Insert сохранение секрета
+func (s *SecretRepository) Insert(ctx context.Context, share domain.SecretShare) error {
+	_, err := s.DB.ExecContext(ctx, s.setTableName(`insert into #T# (id, uid, blob, expires_at, max_views, views) values (?, ?, ?, ?, ?, ?)`),
+		share.ID, share.UID, share.Blob, share.ExpiresAt.Unix(), share.MaxViews, share.Views)
+
+	return err
+}
+
+// Take просмотр секрета: счетчик просмотров увеличивается одним запросом, поэтому секрет не выдается больше MaxViews раз
+// секрет, просмотренный последний раз, удаляется; если секрет не найден, истек или просмотрен - возвращается nil
+func (s *SecretRepository) Take(ctx context.Context, id string, now time.Time) (*domain.SecretShare, error) {
+	var (
+		share     = domain.SecretShare{ID: id}
+		expiresAt int64
+	)
+
+	err := s.DB.QueryRowContext(ctx, s.setTableName(`update #T# set views = views + 1 where id = ? and expires_at > ? and views < max_views
+		returning uid, blob, expires_at, max_views, views`), id, now.Unix()).Scan(&share.UID, &share.Blob, &expiresAt, &share.MaxViews, &share.Views)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	share.ExpiresAt = time.Unix(expiresAt, 0)
+
+	if share.Views >= share.MaxViews {
+		if _, err = s.DB.ExecContext(ctx, s.setTableName(`delete from #T# where id = ?`), id); err != nil {
+			return nil, err
+		}
+	}
+
+	return &share, nil
+}
+
+// DeleteExpired удаление секретов, истекших к моменту now, возвращается число удаленных секретов
+func (s *SecretRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, s.setTableName(`delete from #T# where expires_at <= ?`), now.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (s *SecretRepository) setTableName(query string) string {
+	return strings.NewReplacer("#T#", s.tableName, "#UT#", s.usersTableName).Replace(query)
+}
@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"context"
+	"gophkeeper/server/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	r := newTestRepos(t, db)
+
+	secrets, err := NewSecretRepository(ctx, db, SecretShareTableName, UsersTableName)
+	require.NoError(t, err)
+
+	uid, err := r.user.Store(ctx, domain.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	require.NoError(t, secrets.Insert(ctx, domain.SecretShare{ID: "once", UID: uid, Blob: "blob", ExpiresAt: now.Add(time.Hour), MaxViews: 2}))
+	require.NoError(t, secrets.Insert(ctx, domain.SecretShare{ID: "old", UID: uid, Blob: "blob", ExpiresAt: now.Add(time.Minute), MaxViews: 1}))
+
+	for views := 1; views <= 2; views++ {
+		share, err := secrets.Take(ctx, "once", now)
+		require.NoError(t, err)
+		require.NotNil(t, share)
+		assert.Equal(t, "blob", share.Blob)
+		assert.Equal(t, views, share.Views)
+	}
+
+	share, err := secrets.Take(ctx, "once", now)
+	require.NoError(t, err)
+	assert.Nil(t, share)
+
+	share, err = secrets.Take(ctx, "old", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, share)
+
+	n, err := secrets.DeleteExpired(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+}
@@ -0,0 +1,118 @@
+// Package sqlite пакет для взаимодействия сервера со встроенной базой данных SQLite
+// Предназначен для запуска сервера одним пользователем или небольшой командой без отдельного сервера баз данных
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"net/url"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// Scheme схема DATABASE_URI, по которой выбирается хранилище SQLite
+const Scheme = "sqlite"
+
+const driverName = "sqlite"
+
+// Open открытие базы данных по адресу вида sqlite:///var/lib/gophkeeper.db
+func Open(ctx context.Context, uri string) (*sql.DB, error) {
+	path, err := PathFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(driverName, "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=case_sensitive_like(1)")
+	if err != nil {
+		return nil, err
+	}
+
+	// case_sensitive_like нужен для совпадения поведения поиска по префиксу с Postgres и использования индекса
+	// SQLite допускает только одного писателя, поэтому все запросы идут через одно соединение
+	db.SetMaxOpenConns(1)
+
+	if err = db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// PathFromURI получение пути к файлу базы данных из DATABASE_URI
+func PathFromURI(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Scheme != Scheme {
+		return "", errors.New("unexpected database uri scheme: " + u.Scheme)
+	}
+
+	path := u.Host + u.Path
+	if path == "" {
+		path = u.Opaque
+	}
+
+	if path == "" {
+		return "", errors.New("empty sqlite database path")
+	}
+
+	return path, nil
+}
+
+// IsSQLiteURI проверка, что DATABASE_URI указывает на базу SQLite
+func IsSQLiteURI(uri string) bool {
+	return strings.HasPrefix(uri, Scheme+":")
+}
+
+// migrationDB адаптер базы данных или соединения с открытой транзакцией для применения общих миграций
+type migrationDB struct {
+	db   *sql.DB
+	conn execQuerier
+}
+
+func (m migrationDB) Exec(ctx context.Context, query string, args ...any) error {
+	_, err := m.conn.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (m migrationDB) QueryInt(ctx context.Context, query string, args ...any) (int, error) {
+	var res int
+	err := m.conn.QueryRowContext(ctx, query, args...).Scan(&res)
+	return res, err
+}
+
+// Tx транзакция миграций, begin immediate сразу берет блокировку записи, поэтому процессы,
+// открывшие тот же файл базы, применяют миграции по очереди
+func (m migrationDB) Tx(ctx context.Context, f func(tx migrations.DB) error) error {
+	if m.db == nil {
+		return f(m)
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, `begin immediate`); err != nil {
+		return err
+	}
+
+	if err = f(migrationDB{conn: conn}); err != nil {
+		_, rollbackErr := conn.ExecContext(context.WithoutCancel(ctx), `rollback`)
+		return errors.Join(err, rollbackErr)
+	}
+
+	_, err = conn.ExecContext(ctx, `commit`)
+
+	return err
+}
+
+func migrate(ctx context.Context, db *sql.DB, list []migrations.Migration, vars map[string]string) error {
+	return migrations.Apply(ctx, migrationDB{db: db, conn: db}, migrations.SQLite, list, vars)
+}
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"gophkeeper/internal/server/repository/migrations"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	vars := map[string]string{migrations.TableVar: "items"}
+
+	version := func(t *testing.T, db migrationDB) int {
+		v, err := db.QueryInt(ctx, `select version from `+migrations.VersionsTableName+` where table_name = ?`, "items")
+		require.NoError(t, err)
+
+		return v
+	}
+
+	t.Run("failed migration", func(t *testing.T) {
+		db := openTestDB(t)
+		list := []migrations.Migration{
+			{Version: 1, Query: `create table #T# (id #SERIAL#)`},
+			{Version: 2, Query: `alter table #T# add column name varchar`},
+			{Version: 3, Query: `alter table missing add column broken varchar`},
+		}
+
+		require.Error(t, migrate(ctx, db, list, vars))
+		assert.Equal(t, 2, version(t, migrationDB{conn: db}))
+
+		// примененные миграции не повторяются, применение продолжается с неудавшейся
+		list[2].Query = `alter table #T# add column note varchar`
+		require.NoError(t, migrate(ctx, db, list, vars))
+		assert.Equal(t, 3, version(t, migrationDB{conn: db}))
+	})
+
+	t.Run("concurrent servers", func(t *testing.T) {
+		path := "sqlite://" + filepath.Join(t.TempDir(), "shared.db")
+		list := []migrations.Migration{
+			{Version: 1, Query: `create table #T# (id #SERIAL#)`},
+			{Version: 2, Query: `alter table #T# add column name varchar`},
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, 4)
+		for i := range errs {
+			db, err := Open(ctx, path)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = migrate(ctx, db, list, vars)
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gophkeeper/internal/server/repository/migrations"
+	"gophkeeper/server/domain"
+	"strings"
+	"time"
+)
+
+const UsersTableName = "users"
+
+// UserRepository структура для взаимодействия с таблицей пользователей
+type UserRepository struct {
+	DB        *sql.DB
+	tableName string
+}
+
+func NewUserRepository(ctx context.Context, db *sql.DB, tableName string) (*UserRepository, error) {
+	err := migrate(ctx, db, migrations.Users, map[string]string{
+		migrations.TableVar: tableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserRepository{
+		DB:        db,
+		tableName: tableName,
+	}, nil
+}
+
+// GetByLogin получить пользователя по логину
+func (u *UserRepository) GetByLogin(ctx context.Context, login string) (user domain.User, err error) {
+	query := u.setTableName(`select id, login, password from #T# where login = ?`)
+
+	err = u.DB.QueryRowContext(ctx, query, login).Scan(&user.ID, &user.Login, &user.Password)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, nil
+	}
+
+	return user, err
+}
+
+// GetByID получить пользователя по ИД
+func (u *UserRepository) GetByID(ctx context.Context, id uint64) (user domain.User, err error) {
+	query := u.setTableName(`select id, login, password from #T# where id = ?`)
+
+	err = u.DB.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Login, &user.Password)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, nil
+	}
+
+	return user, err
+}
+
+// GetKeys получить ключи пользователя, пустые - если ключи не заданы
+func (u *UserRepository) GetKeys(ctx context.Context, id uint64) (keys domain.UserKeys, err error) {
+	query := u.setTableName(`select coalesce(public_key, ''), coalesce(private_key, '') from #T# where id = ?`)
+
+	err = u.DB.QueryRowContext(ctx, query, id).Scan(&keys.PublicKey, &keys.PrivateKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.UserKeys{}, nil
+	}
+
+	return keys, err
+}
+
+// SetKeys сохранить ключи пользователя
+func (u *UserRepository) SetKeys(ctx context.Context, id uint64, keys domain.UserKeys) error {
+	query := u.setTableName(`update #T# set public_key = ?, private_key = ? where id = ?`)
+	_, err := u.DB.ExecContext(ctx, query, keys.PublicKey, keys.PrivateKey, id)
+
+	return err
+}
+
+// Store добавить нового пользователя
+func (u *UserRepository) Store(ctx context.Context, user domain.User) (uint64, error) {
+	var id uint64
+	query := u.setTableName(`insert into #T# (login, password) values (?, ?) returning id`)
+
+	err := u.DB.QueryRowContext(ctx, query, user.Login, user.Password).Scan(&id)
+	if err != nil {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// GetState состояние учетной записи, nil - пользователь не найден
+func (u *UserRepository) GetState(ctx context.Context, id uint64) (*domain.AccountState, error) {
+	var purgeAt, sessionsAfter *int64
+
+	err := u.DB.QueryRowContext(ctx, u.setTableName(`select purge_at, sessions_after from #T# where id = ?`), id).Scan(&purgeAt, &sessionsAfter)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AccountState{PurgeAt: fromNullUnix(purgeAt), SessionsAfter: fromNullUnix(sessionsAfter)}, nil
+}
+
+// SetState сохранение состояния учетной записи
+func (u *UserRepository) SetState(ctx context.Context, id uint64, state domain.AccountState) error {
+	_, err := u.DB.ExecContext(ctx, u.setTableName(`update #T# set purge_at = ?, sessions_after = ? where id = ?`),
+		nullUnix(state.PurgeAt), nullUnix(state.SessionsAfter), id)
+
+	return err
+}
+
+// GetPurgeDue ИД учетных записей, время окончательного удаления которых наступило к now
+func (u *UserRepository) GetPurgeDue(ctx context.Context, now time.Time) ([]uint64, error) {
+	rows, err := u.DB.QueryContext(ctx, u.setTableName(`select id from #T# where purge_at <= ? order by id`), now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (u *UserRepository) setTableName(query string) string {
+	return strings.ReplaceAll(query, "#T#", u.tableName)
+}
+
+// nullUnix время в секундах, нулевое время - NULL
+func nullUnix(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+
+	sec := t.Unix()
+
+	return &sec
+}
+
+func fromNullUnix(sec *int64) time.Time {
+	if sec == nil {
+		return time.Time{}
+	}
+
+	return time.Unix(*sec, 0)
+}
@@ -0,0 +1,78 @@
+// Package web HTTP сервер для получателей одноразовых ссылок на секреты
+package web
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/secret"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// SecretTaker выдача зашифрованного секрета по ИД
+type SecretTaker interface {
+	Take(ctx context.Context, id string) (string, error)
+}
+
+// NewHandler обработчик ссылок на секреты
+// GET возвращает страницу, которая расшифровывает секрет в браузере, секрет выдается только на POST,
+// поэтому переход по ссылке роботами предпросмотра не тратит просмотры
+func NewHandler(taker SecretTaker) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET "+secret.LinkPath+"{id}", func(w http.ResponseWriter, r *http.Request) {
+		serveStatic(w, "static/secret.html", "text/html; charset=utf-8")
+	})
+
+	mux.HandleFunc("GET /secret.js", func(w http.ResponseWriter, r *http.Request) {
+		serveStatic(w, "static/secret.js", "text/javascript; charset=utf-8")
+	})
+
+	mux.HandleFunc("POST "+secret.LinkPath+"{id}", func(w http.ResponseWriter, r *http.Request) {
+		setSecurityHeaders(w)
+
+		blob, err := taker.Take(r.Context(), r.PathValue("id"))
+		switch {
+		case errors.Is(err, domain2.ErrSecretNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, domain2.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(blob))
+	})
+
+	return mux
+}
+
+func serveStatic(w http.ResponseWriter, name, contentType string) {
+	setSecurityHeaders(w)
+
+	content, err := static.ReadFile(name)
+	if err != nil {
+		internal.Logger.Errorw("error while reading static file", "name", name, "err", err)
+		http.Error(w, domain2.ErrInternalServerError.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(content)
+}
+
+// setSecurityHeaders ответы не кешируются, ссылка с ключом не уходит в Referer, сторонние скрипты запрещены
+func setSecurityHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("Cache-Control", "no-store")
+	h.Set("Referrer-Policy", "no-referrer")
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Content-Security-Policy", "default-src 'none'; script-src 'self'; connect-src 'self'; style-src 'unsafe-inline'")
+}
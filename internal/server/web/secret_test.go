@@ -0,0 +1,85 @@
+package web
+
+import (
+	"context"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onceTaker секрет, который выдается один раз
+type onceTaker struct {
+	blob string
+}
+
+func (o *onceTaker) Take(_ context.Context, id string) (string, error) {
+	if id != "abc" || o.blob == "" {
+		return "", domain2.ErrSecretNotFound
+	}
+
+	blob := o.blob
+	o.blob = ""
+
+	return blob, nil
+}
+
+func TestNewHandler(t *testing.T) {
+	internal.InitLogger()
+
+	srv := httptest.NewServer(NewHandler(&onceTaker{blob: "encrypted"}))
+	t.Cleanup(srv.Close)
+
+	get := func(path string) (*http.Response, string) {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, string(body)
+	}
+
+	post := func(path string) (*http.Response, string) {
+		resp, err := http.Post(srv.URL+path, "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, string(body)
+	}
+
+	// страница не тратит просмотры
+	for i := 0; i < 2; i++ {
+		resp, body := get("/s/abc")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, `<script src="/secret.js">`)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+		assert.Equal(t, "no-referrer", resp.Header.Get("Referrer-Policy"))
+	}
+
+	resp, body := get("/secret.js")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "crypto.subtle")
+
+	resp, body = post("/s/abc")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "encrypted", body)
+
+	resp, _ = post("/s/abc")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = post("/s/other")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = get("/")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
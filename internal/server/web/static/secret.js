@@ -0,0 +1,47 @@
+// расшифровка секрета в браузере: ключ берется из фрагмента ссылки и на сервер не отправляется
+// секрет запрашивается только по кнопке, чтобы предпросмотр ссылки в мессенджерах не тратил просмотры
+(function () {
+  const button = document.getElementById('show');
+  const status = document.getElementById('status');
+  const output = document.getElementById('secret');
+  const key = location.hash.slice(1);
+
+  if (!key) {
+    status.textContent = 'The link has no key.';
+    button.disabled = true;
+    return;
+  }
+
+  // base64 и base64url в байты
+  function decode(s) {
+    s = s.replace(/-/g, '+').replace(/_/g, '/');
+    while (s.length % 4) {
+      s += '=';
+    }
+
+    return Uint8Array.from(atob(s), (c) => c.charCodeAt(0));
+  }
+
+  button.addEventListener('click', async () => {
+    button.disabled = true;
+
+    const resp = await fetch(location.pathname, { method: 'POST', cache: 'no-store' });
+    if (!resp.ok) {
+      status.textContent = resp.status === 404 ? 'The secret was not found, expired or was already viewed.' : 'Server error.';
+      return;
+    }
+
+    try {
+      // nonce AES-GCM в начале шифротекста, как в crypto.Encrypt
+      const data = decode(await resp.text());
+      const cryptoKey = await crypto.subtle.importKey('raw', decode(key), 'AES-GCM', false, ['decrypt']);
+      const plain = await crypto.subtle.decrypt({ name: 'AES-GCM', iv: data.slice(0, 12) }, cryptoKey, data.slice(12));
+
+      output.value = new TextDecoder().decode(plain);
+      output.hidden = false;
+      status.textContent = 'Copy the secret now, it may not be available again.';
+    } catch (e) {
+      status.textContent = 'The secret can not be decrypted, check the link.';
+    }
+  });
+})();
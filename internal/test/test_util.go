@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"testing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -14,8 +15,20 @@ const UsersTestTable = "test_users"
 const DataTestTable = "test_data"
 const FileTestTable = "test_file"
 
+// DSNEnv переменная окружения со строкой подключения к тестовой базе
+const DSNEnv = "TEST_DATABASE_DSN"
+
+// SkipWithoutDB пропуск теста, которому нужна база, если строка подключения не задана
+func SkipWithoutDB(t testing.TB) {
+	t.Helper()
+
+	if os.Getenv(DSNEnv) == "" {
+		t.Skip(DSNEnv + " is not set")
+	}
+}
+
 func InitConnection(ctx context.Context) (*pgxpool.Pool, error) {
-	dns := os.Getenv("TEST_DATABASE_DSN")
+	dns := os.Getenv(DSNEnv)
 	if dns == "" {
 		return nil, nil
 	}
@@ -0,0 +1,605 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: audit.proto
+
+package proto
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AuditAction int32
+
+const (
+	AuditAction_AUDIT_ACTION_NONE            AuditAction = 0
+	AuditAction_AUDIT_ACTION_REGISTER        AuditAction = 1
+	AuditAction_AUDIT_ACTION_LOGIN           AuditAction = 2
+	AuditAction_AUDIT_ACTION_REFRESH         AuditAction = 3
+	AuditAction_AUDIT_ACTION_READ            AuditAction = 4
+	AuditAction_AUDIT_ACTION_SAVE            AuditAction = 5
+	AuditAction_AUDIT_ACTION_DELETE          AuditAction = 6
+	AuditAction_AUDIT_ACTION_DOWNLOAD        AuditAction = 7
+	AuditAction_AUDIT_ACTION_UPLOAD          AuditAction = 8
+	AuditAction_AUDIT_ACTION_SHARE           AuditAction = 9
+	AuditAction_AUDIT_ACTION_UNSHARE         AuditAction = 10
+	AuditAction_AUDIT_ACTION_DELETE_ACCOUNT  AuditAction = 11
+	AuditAction_AUDIT_ACTION_RESTORE_ACCOUNT AuditAction = 12
+	AuditAction_AUDIT_ACTION_PURGE_ACCOUNT   AuditAction = 13
+)
+
+// Enum value maps for AuditAction.
+var (
+	AuditAction_name = map[int32]string{
+		0:  "AUDIT_ACTION_NONE",
+		1:  "AUDIT_ACTION_REGISTER",
+		2:  "AUDIT_ACTION_LOGIN",
+		3:  "AUDIT_ACTION_REFRESH",
+		4:  "AUDIT_ACTION_READ",
+		5:  "AUDIT_ACTION_SAVE",
+		6:  "AUDIT_ACTION_DELETE",
+		7:  "AUDIT_ACTION_DOWNLOAD",
+		8:  "AUDIT_ACTION_UPLOAD",
+		9:  "AUDIT_ACTION_SHARE",
+		10: "AUDIT_ACTION_UNSHARE",
+		11: "AUDIT_ACTION_DELETE_ACCOUNT",
+		12: "AUDIT_ACTION_RESTORE_ACCOUNT",
+		13: "AUDIT_ACTION_PURGE_ACCOUNT",
+	}
+	AuditAction_value = map[string]int32{
+		"AUDIT_ACTION_NONE":            0,
+		"AUDIT_ACTION_REGISTER":        1,
+		"AUDIT_ACTION_LOGIN":           2,
+		"AUDIT_ACTION_REFRESH":         3,
+		"AUDIT_ACTION_READ":            4,
+		"AUDIT_ACTION_SAVE":            5,
+		"AUDIT_ACTION_DELETE":          6,
+		"AUDIT_ACTION_DOWNLOAD":        7,
+		"AUDIT_ACTION_UPLOAD":          8,
+		"AUDIT_ACTION_SHARE":           9,
+		"AUDIT_ACTION_UNSHARE":         10,
+		"AUDIT_ACTION_DELETE_ACCOUNT":  11,
+		"AUDIT_ACTION_RESTORE_ACCOUNT": 12,
+		"AUDIT_ACTION_PURGE_ACCOUNT":   13,
+	}
+)
+
+func (x AuditAction) Enum() *AuditAction {
+	p := new(AuditAction)
+	*p = x
+	return p
+}
+
+func (x AuditAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuditAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_audit_proto_enumTypes[0].Descriptor()
+}
+
+func (AuditAction) Type() protoreflect.EnumType {
+	return &file_audit_proto_enumTypes[0]
+}
+
+func (x AuditAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuditAction.Descriptor instead.
+func (AuditAction) EnumDescriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{0}
+}
+
+// AuditOutcome результат действия, DENIED - неверные учетные данные или запрет доступа
+type AuditOutcome int32
+
+const (
+	AuditOutcome_AUDIT_OUTCOME_ANY     AuditOutcome = 0
+	AuditOutcome_AUDIT_OUTCOME_SUCCESS AuditOutcome = 1
+	AuditOutcome_AUDIT_OUTCOME_DENIED  AuditOutcome = 2
+	AuditOutcome_AUDIT_OUTCOME_FAILURE AuditOutcome = 3
+)
+
+// Enum value maps for AuditOutcome.
+var (
+	AuditOutcome_name = map[int32]string{
+		0: "AUDIT_OUTCOME_ANY",
+		1: "AUDIT_OUTCOME_SUCCESS",
+		2: "AUDIT_OUTCOME_DENIED",
+		3: "AUDIT_OUTCOME_FAILURE",
+	}
+	AuditOutcome_value = map[string]int32{
+		"AUDIT_OUTCOME_ANY":     0,
+		"AUDIT_OUTCOME_SUCCESS": 1,
+		"AUDIT_OUTCOME_DENIED":  2,
+		"AUDIT_OUTCOME_FAILURE": 3,
+	}
+)
+
+func (x AuditOutcome) Enum() *AuditOutcome {
+	p := new(AuditOutcome)
+	*p = x
+	return p
+}
+
+func (x AuditOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuditOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_audit_proto_enumTypes[1].Descriptor()
+}
+
+func (AuditOutcome) Type() protoreflect.EnumType {
+	return &file_audit_proto_enumTypes[1]
+}
+
+func (x AuditOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuditOutcome.Descriptor instead.
+func (AuditOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{1}
+}
+
+// AuditEvent событие журнала аудита
+// Uid - учетная запись, к данным которой относится событие, Actor - логин выполнившего действие, CreatedAt - время (unix)
+type AuditEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        uint64       `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Uid       uint64       `protobuf:"varint,2,opt,name=Uid,proto3" json:"Uid,omitempty"`
+	ActorUid  uint64       `protobuf:"varint,3,opt,name=ActorUid,proto3" json:"ActorUid,omitempty"`
+	Actor     string       `protobuf:"bytes,4,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	Ip        string       `protobuf:"bytes,5,opt,name=Ip,proto3" json:"Ip,omitempty"`
+	Action    AuditAction  `protobuf:"varint,6,opt,name=Action,proto3,enum=gophkeeper.AuditAction" json:"Action,omitempty"`
+	DataId    uint64       `protobuf:"varint,7,opt,name=DataId,proto3" json:"DataId,omitempty"`
+	Outcome   AuditOutcome `protobuf:"varint,8,opt,name=Outcome,proto3,enum=gophkeeper.AuditOutcome" json:"Outcome,omitempty"`
+	CreatedAt int64        `protobuf:"varint,9,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_audit_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuditEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetUid() uint64 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetActorUid() uint64 {
+	if x != nil {
+		return x.ActorUid
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAction() AuditAction {
+	if x != nil {
+		return x.Action
+	}
+	return AuditAction_AUDIT_ACTION_NONE
+}
+
+func (x *AuditEvent) GetDataId() uint64 {
+	if x != nil {
+		return x.DataId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetOutcome() AuditOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return AuditOutcome_AUDIT_OUTCOME_ANY
+}
+
+func (x *AuditEvent) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// ListAuditEventsRequest фильтр журнала, пустые поля не ограничивают выборку
+// Since, Until - границы времени (unix), BeforeId - ИД последнего события предыдущей страницы
+type ListAuditEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Actions  []AuditAction `protobuf:"varint,1,rep,packed,name=Actions,proto3,enum=gophkeeper.AuditAction" json:"Actions,omitempty"`
+	DataId   uint64        `protobuf:"varint,2,opt,name=DataId,proto3" json:"DataId,omitempty"`
+	Outcome  AuditOutcome  `protobuf:"varint,3,opt,name=Outcome,proto3,enum=gophkeeper.AuditOutcome" json:"Outcome,omitempty"`
+	Since    int64         `protobuf:"varint,4,opt,name=Since,proto3" json:"Since,omitempty"`
+	Until    int64         `protobuf:"varint,5,opt,name=Until,proto3" json:"Until,omitempty"`
+	Limit    uint32        `protobuf:"varint,6,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	BeforeId uint64        `protobuf:"varint,7,opt,name=BeforeId,proto3" json:"BeforeId,omitempty"`
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_audit_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListAuditEventsRequest) GetActions() []AuditAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *ListAuditEventsRequest) GetDataId() uint64 {
+	if x != nil {
+		return x.DataId
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetOutcome() AuditOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return AuditOutcome_AUDIT_OUTCOME_ANY
+}
+
+func (x *ListAuditEventsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetBeforeId() uint64 {
+	if x != nil {
+		return x.BeforeId
+	}
+	return 0
+}
+
+// ListAuditEventsResponse события от новых к старым
+type ListAuditEventsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*AuditEvent `protobuf:"bytes,1,rep,name=Events,proto3" json:"Events,omitempty"`
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_audit_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+var File_audit_proto protoreflect.FileDescriptor
+
+var file_audit_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x61, 0x75, 0x64, 0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x67,
+	0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x1a, 0x1b, 0x62, 0x75, 0x66, 0x2f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8b, 0x02, 0x0a, 0x0a, 0x41, 0x75, 0x64, 0x69, 0x74,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x02, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x55, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x03, 0x55, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x41, 0x63, 0x74, 0x6f, 0x72,
+	0x55, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x41, 0x63, 0x74, 0x6f, 0x72,
+	0x55, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x41, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x41, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x70, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x70, 0x12, 0x2f, 0x0a, 0x06, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x70, 0x68,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x44, 0x61,
+	0x74, 0x61, 0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x44, 0x61, 0x74, 0x61,
+	0x49, 0x64, 0x12, 0x32, 0x0a, 0x07, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x07, 0x4f,
+	0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x22, 0xaf, 0x02, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x45, 0x0a, 0x07, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e,
+	0x32, 0x17, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x12, 0xba, 0x48, 0x0f, 0x92, 0x01,
+	0x0c, 0x10, 0x10, 0x22, 0x08, 0x82, 0x01, 0x05, 0x10, 0x01, 0x22, 0x01, 0x00, 0x52, 0x07, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x12, 0x3c,
+	0x0a, 0x07, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x18, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x42, 0x08, 0xba, 0x48, 0x05, 0x82, 0x01,
+	0x02, 0x10, 0x01, 0x52, 0x07, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x05,
+	0x53, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xba, 0x48, 0x04,
+	0x22, 0x02, 0x28, 0x00, 0x52, 0x05, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x05, 0x55,
+	0x6e, 0x74, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xba, 0x48, 0x04, 0x22,
+	0x02, 0x28, 0x00, 0x52, 0x05, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1e, 0x0a, 0x05, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x08, 0xba, 0x48, 0x05, 0x2a, 0x03,
+	0x18, 0xe8, 0x07, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x42, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x49, 0x64, 0x22, 0x49, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2e, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x41,
+	0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x2a, 0x81, 0x03, 0x0a, 0x0b, 0x41, 0x75, 0x64, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x41, 0x55, 0x44, 0x49,
+	0x54, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x45,
+	0x52, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x4c, 0x4f, 0x47, 0x49, 0x4e, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x41,
+	0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x46, 0x52,
+	0x45, 0x53, 0x48, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41,
+	0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x10, 0x04, 0x12, 0x15, 0x0a, 0x11,
+	0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x41, 0x56,
+	0x45, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x06, 0x12, 0x19, 0x0a, 0x15,
+	0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x4f, 0x57,
+	0x4e, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x07, 0x12, 0x17, 0x0a, 0x13, 0x41, 0x55, 0x44, 0x49, 0x54,
+	0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x08,
+	0x12, 0x16, 0x0a, 0x12, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x53, 0x48, 0x41, 0x52, 0x45, 0x10, 0x09, 0x12, 0x18, 0x0a, 0x14, 0x41, 0x55, 0x44, 0x49,
+	0x54, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x48, 0x41, 0x52, 0x45,
+	0x10, 0x0a, 0x12, 0x1f, 0x0a, 0x1b, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x5f, 0x41, 0x43, 0x43, 0x4f, 0x55, 0x4e,
+	0x54, 0x10, 0x0b, 0x12, 0x20, 0x0a, 0x1c, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x5f, 0x41, 0x43, 0x43, 0x4f,
+	0x55, 0x4e, 0x54, 0x10, 0x0c, 0x12, 0x1e, 0x0a, 0x1a, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41,
+	0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x50, 0x55, 0x52, 0x47, 0x45, 0x5f, 0x41, 0x43, 0x43, 0x4f,
+	0x55, 0x4e, 0x54, 0x10, 0x0d, 0x2a, 0x75, 0x0a, 0x0c, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4f, 0x75,
+	0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x4f,
+	0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x41, 0x4e, 0x59, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15,
+	0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x53, 0x55,
+	0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x41, 0x55, 0x44, 0x49, 0x54,
+	0x5f, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x44, 0x45, 0x4e, 0x49, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x19, 0x0a, 0x15, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x4f, 0x55, 0x54, 0x43, 0x4f,
+	0x4d, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x10, 0x03, 0x32, 0x6a, 0x0a, 0x0c,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5a, 0x0a, 0x0f,
+	0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x22, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x12, 0x5a, 0x10, 0x67, 0x6f, 0x70, 0x68,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_audit_proto_rawDescOnce sync.Once
+	file_audit_proto_rawDescData = file_audit_proto_rawDesc
+)
+
+func file_audit_proto_rawDescGZIP() []byte {
+	file_audit_proto_rawDescOnce.Do(func() {
+		file_audit_proto_rawDescData = protoimpl.X.CompressGZIP(file_audit_proto_rawDescData)
+	})
+	return file_audit_proto_rawDescData
+}
+
+var file_audit_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_audit_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_audit_proto_goTypes = []any{
+	(AuditAction)(0),                // 0: gophkeeper.AuditAction
+	(AuditOutcome)(0),               // 1: gophkeeper.AuditOutcome
+	(*AuditEvent)(nil),              // 2: gophkeeper.AuditEvent
+	(*ListAuditEventsRequest)(nil),  // 3: gophkeeper.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil), // 4: gophkeeper.ListAuditEventsResponse
+}
+var file_audit_proto_depIdxs = []int32{
+	0, // 0: gophkeeper.AuditEvent.Action:type_name -> gophkeeper.AuditAction
+	1, // 1: gophkeeper.AuditEvent.Outcome:type_name -> gophkeeper.AuditOutcome
+	0, // 2: gophkeeper.ListAuditEventsRequest.Actions:type_name -> gophkeeper.AuditAction
+	1, // 3: gophkeeper.ListAuditEventsRequest.Outcome:type_name -> gophkeeper.AuditOutcome
+	2, // 4: gophkeeper.ListAuditEventsResponse.Events:type_name -> gophkeeper.AuditEvent
+	3, // 5: gophkeeper.AuditService.ListAuditEvents:input_type -> gophkeeper.ListAuditEventsRequest
+	4, // 6: gophkeeper.AuditService.ListAuditEvents:output_type -> gophkeeper.ListAuditEventsResponse
+	6, // [6:7] is the sub-list for method output_type
+	5, // [5:6] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_audit_proto_init() }
+func file_audit_proto_init() {
+	if File_audit_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_audit_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*AuditEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_audit_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ListAuditEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_audit_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ListAuditEventsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_audit_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_audit_proto_goTypes,
+		DependencyIndexes: file_audit_proto_depIdxs,
+		EnumInfos:         file_audit_proto_enumTypes,
+		MessageInfos:      file_audit_proto_msgTypes,
+	}.Build()
+	File_audit_proto = out.File
+	file_audit_proto_rawDesc = nil
+	file_audit_proto_goTypes = nil
+	file_audit_proto_depIdxs = nil
+}
@@ -22,21 +22,197 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// DataType тип записи, определяется сервером по заполненным полям
+type DataType int32
+
+const (
+	DataType_DATA_TYPE_ANY         DataType = 0
+	DataType_DATA_TYPE_CREDENTIALS DataType = 1
+	DataType_DATA_TYPE_CARD        DataType = 2
+	DataType_DATA_TYPE_TEXT        DataType = 3
+	DataType_DATA_TYPE_FILE        DataType = 4
+	DataType_DATA_TYPE_OTP         DataType = 5
+)
+
+// Enum value maps for DataType.
+var (
+	DataType_name = map[int32]string{
+		0: "DATA_TYPE_ANY",
+		1: "DATA_TYPE_CREDENTIALS",
+		2: "DATA_TYPE_CARD",
+		3: "DATA_TYPE_TEXT",
+		4: "DATA_TYPE_FILE",
+		5: "DATA_TYPE_OTP",
+	}
+	DataType_value = map[string]int32{
+		"DATA_TYPE_ANY":         0,
+		"DATA_TYPE_CREDENTIALS": 1,
+		"DATA_TYPE_CARD":        2,
+		"DATA_TYPE_TEXT":        3,
+		"DATA_TYPE_FILE":        4,
+		"DATA_TYPE_OTP":         5,
+	}
+)
+
+func (x DataType) Enum() *DataType {
+	p := new(DataType)
+	*p = x
+	return p
+}
+
+func (x DataType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DataType) Descriptor() protoreflect.EnumDescriptor {
+	return file_data_proto_enumTypes[0].Descriptor()
+}
+
+func (DataType) Type() protoreflect.EnumType {
+	return &file_data_proto_enumTypes[0]
+}
+
+func (x DataType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DataType.Descriptor instead.
+func (DataType) EnumDescriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{0}
+}
+
+// SharePermission права пользователя на чужую запись
+type SharePermission int32
+
+const (
+	SharePermission_SHARE_PERMISSION_NONE  SharePermission = 0
+	SharePermission_SHARE_PERMISSION_READ  SharePermission = 1
+	SharePermission_SHARE_PERMISSION_WRITE SharePermission = 2
+)
+
+// Enum value maps for SharePermission.
+var (
+	SharePermission_name = map[int32]string{
+		0: "SHARE_PERMISSION_NONE",
+		1: "SHARE_PERMISSION_READ",
+		2: "SHARE_PERMISSION_WRITE",
+	}
+	SharePermission_value = map[string]int32{
+		"SHARE_PERMISSION_NONE":  0,
+		"SHARE_PERMISSION_READ":  1,
+		"SHARE_PERMISSION_WRITE": 2,
+	}
+)
+
+func (x SharePermission) Enum() *SharePermission {
+	p := new(SharePermission)
+	*p = x
+	return p
+}
+
+func (x SharePermission) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SharePermission) Descriptor() protoreflect.EnumDescriptor {
+	return file_data_proto_enumTypes[1].Descriptor()
+}
+
+func (SharePermission) Type() protoreflect.EnumType {
+	return &file_data_proto_enumTypes[1]
+}
+
+func (x SharePermission) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SharePermission.Descriptor instead.
+func (SharePermission) EnumDescriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{1}
+}
+
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_NAME_ASC  SortOrder = 0
+	SortOrder_SORT_ORDER_NAME_DESC SortOrder = 1
+	SortOrder_SORT_ORDER_ID_ASC    SortOrder = 2
+	SortOrder_SORT_ORDER_ID_DESC   SortOrder = 3
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_NAME_ASC",
+		1: "SORT_ORDER_NAME_DESC",
+		2: "SORT_ORDER_ID_ASC",
+		3: "SORT_ORDER_ID_DESC",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_NAME_ASC":  0,
+		"SORT_ORDER_NAME_DESC": 1,
+		"SORT_ORDER_ID_ASC":    2,
+		"SORT_ORDER_ID_DESC":   3,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_data_proto_enumTypes[2].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_data_proto_enumTypes[2]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{2}
+}
+
 type Data struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       uint64 `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
-	Name     string `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
-	Login    string `protobuf:"bytes,3,opt,name=Login,proto3" json:"Login,omitempty"`
-	Pass     string `protobuf:"bytes,4,opt,name=Pass,proto3" json:"Pass,omitempty"`
-	Text     string `protobuf:"bytes,5,opt,name=Text,proto3" json:"Text,omitempty"`
-	CardNum  string `protobuf:"bytes,6,opt,name=CardNum,proto3" json:"CardNum,omitempty"`
-	Meta     string `protobuf:"bytes,7,opt,name=Meta,proto3" json:"Meta,omitempty"`
-	FileName string `protobuf:"bytes,8,opt,name=FileName,proto3" json:"FileName,omitempty"`
-	Version  uint64 `protobuf:"varint,10,opt,name=Version,proto3" json:"Version,omitempty"`
-	FileID   uint64 `protobuf:"varint,11,opt,name=FileID,proto3" json:"FileID,omitempty"`
+	Id uint64 `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	// Name может быть зашифрован клиентом, тогда уникальность проверяется по NameHash
+	Name     string   `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Login    string   `protobuf:"bytes,3,opt,name=Login,proto3" json:"Login,omitempty"`
+	Pass     string   `protobuf:"bytes,4,opt,name=Pass,proto3" json:"Pass,omitempty"`
+	Text     string   `protobuf:"bytes,5,opt,name=Text,proto3" json:"Text,omitempty"`
+	CardNum  string   `protobuf:"bytes,6,opt,name=CardNum,proto3" json:"CardNum,omitempty"`
+	Meta     string   `protobuf:"bytes,7,opt,name=Meta,proto3" json:"Meta,omitempty"`
+	FileName string   `protobuf:"bytes,8,opt,name=FileName,proto3" json:"FileName,omitempty"`
+	Version  uint64   `protobuf:"varint,10,opt,name=Version,proto3" json:"Version,omitempty"`
+	FileID   uint64   `protobuf:"varint,11,opt,name=FileID,proto3" json:"FileID,omitempty"`
+	FolderId uint64   `protobuf:"varint,12,opt,name=FolderId,proto3" json:"FolderId,omitempty"`
+	Tags     []string `protobuf:"bytes,13,rep,name=Tags,proto3" json:"Tags,omitempty"`
+	// NameHash слепой индекс названия (HMAC с ключом пользователя)
+	NameHash string `protobuf:"bytes,14,opt,name=NameHash,proto3" json:"NameHash,omitempty"`
+	// CardExp срок действия карты в виде MM/YY, зашифрован клиентом
+	CardExp string `protobuf:"bytes,15,opt,name=CardExp,proto3" json:"CardExp,omitempty"`
+	// Otp ключ одноразовых паролей в виде otpauth:// URI, зашифрован клиентом
+	Otp string `protobuf:"bytes,16,opt,name=Otp,proto3" json:"Otp,omitempty"`
+	// RecordKey ключ записи: для владельца зашифрован ключом хранилища, для получателя - его открытым ключом
+	// пустой, если запись зашифрована ключом хранилища владельца
+	RecordKey string `protobuf:"bytes,17,opt,name=RecordKey,proto3" json:"RecordKey,omitempty"`
+	// SharedBy логин владельца записи, к которой пользователю предоставлен доступ, заполняется сервером
+	SharedBy   string          `protobuf:"bytes,18,opt,name=SharedBy,proto3" json:"SharedBy,omitempty"`
+	Permission SharePermission `protobuf:"varint,19,opt,name=Permission,proto3,enum=gophkeeper.SharePermission" json:"Permission,omitempty"`
 }
 
 func (x *Data) Reset() {
@@ -141,32 +317,507 @@ func (x *Data) GetFileID() uint64 {
 	return 0
 }
 
+func (x *Data) GetFolderId() uint64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *Data) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Data) GetNameHash() string {
+	if x != nil {
+		return x.NameHash
+	}
+	return ""
+}
+
+func (x *Data) GetCardExp() string {
+	if x != nil {
+		return x.CardExp
+	}
+	return ""
+}
+
+func (x *Data) GetOtp() string {
+	if x != nil {
+		return x.Otp
+	}
+	return ""
+}
+
+func (x *Data) GetRecordKey() string {
+	if x != nil {
+		return x.RecordKey
+	}
+	return ""
+}
+
+func (x *Data) GetSharedBy() string {
+	if x != nil {
+		return x.SharedBy
+	}
+	return ""
+}
+
+func (x *Data) GetPermission() SharePermission {
+	if x != nil {
+		return x.Permission
+	}
+	return SharePermission_SHARE_PERMISSION_NONE
+}
+
 type DataList struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id   uint64 `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Id         uint64          `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Name       string          `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	FolderId   uint64          `protobuf:"varint,3,opt,name=FolderId,proto3" json:"FolderId,omitempty"`
+	Tags       []string        `protobuf:"bytes,4,rep,name=Tags,proto3" json:"Tags,omitempty"`
+	Version    uint64          `protobuf:"varint,5,opt,name=Version,proto3" json:"Version,omitempty"`
+	RecordKey  string          `protobuf:"bytes,6,opt,name=RecordKey,proto3" json:"RecordKey,omitempty"`
+	SharedBy   string          `protobuf:"bytes,7,opt,name=SharedBy,proto3" json:"SharedBy,omitempty"`
+	Permission SharePermission `protobuf:"varint,8,opt,name=Permission,proto3,enum=gophkeeper.SharePermission" json:"Permission,omitempty"`
+}
+
+func (x *DataList) Reset() {
+	*x = DataList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_data_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DataList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataList) ProtoMessage() {}
+
+func (x *DataList) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataList.ProtoReflect.Descriptor instead.
+func (*DataList) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DataList) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DataList) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DataList) GetFolderId() uint64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *DataList) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *DataList) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DataList) GetRecordKey() string {
+	if x != nil {
+		return x.RecordKey
+	}
+	return ""
+}
+
+func (x *DataList) GetSharedBy() string {
+	if x != nil {
+		return x.SharedBy
+	}
+	return ""
+}
+
+func (x *DataList) GetPermission() SharePermission {
+	if x != nil {
+		return x.Permission
+	}
+	return SharePermission_SHARE_PERMISSION_NONE
+}
+
+// Folder папка пользователя, ParentId = 0 для папок верхнего уровня
+type Folder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       uint64 `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	ParentId uint64 `protobuf:"varint,2,opt,name=ParentId,proto3" json:"ParentId,omitempty"`
+	Name     string `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
+}
+
+func (x *Folder) Reset() {
+	*x = Folder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_data_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Folder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Folder) ProtoMessage() {}
+
+func (x *Folder) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Folder.ProtoReflect.Descriptor instead.
+func (*Folder) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Folder) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Folder) GetParentId() uint64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *Folder) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetDataListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NamePrefix   string    `protobuf:"bytes,1,opt,name=NamePrefix,proto3" json:"NamePrefix,omitempty"`
+	NameContains string    `protobuf:"bytes,2,opt,name=NameContains,proto3" json:"NameContains,omitempty"`
+	Type         DataType  `protobuf:"varint,3,opt,name=Type,proto3,enum=gophkeeper.DataType" json:"Type,omitempty"`
+	Sort         SortOrder `protobuf:"varint,4,opt,name=Sort,proto3,enum=gophkeeper.SortOrder" json:"Sort,omitempty"`
+	PageSize     uint32    `protobuf:"varint,5,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	PageToken    string    `protobuf:"bytes,6,opt,name=PageToken,proto3" json:"PageToken,omitempty"`
+	FolderId     uint64    `protobuf:"varint,7,opt,name=FolderId,proto3" json:"FolderId,omitempty"`
+	Tags         []string  `protobuf:"bytes,8,rep,name=Tags,proto3" json:"Tags,omitempty"`
+	// Shared только записи других пользователей, к которым предоставлен доступ
+	Shared bool `protobuf:"varint,9,opt,name=Shared,proto3" json:"Shared,omitempty"`
+}
+
+func (x *GetDataListRequest) Reset() {
+	*x = GetDataListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_data_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDataListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDataListRequest) ProtoMessage() {}
+
+func (x *GetDataListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDataListRequest.ProtoReflect.Descriptor instead.
+func (*GetDataListRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetDataListRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *GetDataListRequest) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+func (x *GetDataListRequest) GetType() DataType {
+	if x != nil {
+		return x.Type
+	}
+	return DataType_DATA_TYPE_ANY
+}
+
+func (x *GetDataListRequest) GetSort() SortOrder {
+	if x != nil {
+		return x.Sort
+	}
+	return SortOrder_SORT_ORDER_NAME_ASC
+}
+
+func (x *GetDataListRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetDataListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetDataListRequest) GetFolderId() uint64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *GetDataListRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetDataListRequest) GetShared() bool {
+	if x != nil {
+		return x.Shared
+	}
+	return false
+}
+
+type SaveFolderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Folder *Folder `protobuf:"bytes,1,opt,name=Folder,proto3" json:"Folder,omitempty"`
+}
+
+func (x *SaveFolderRequest) Reset() {
+	*x = SaveFolderRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_data_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SaveFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveFolderRequest) ProtoMessage() {}
+
+func (x *SaveFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveFolderRequest.ProtoReflect.Descriptor instead.
+func (*SaveFolderRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SaveFolderRequest) GetFolder() *Folder {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+type SaveFolderResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FolderId uint64 `protobuf:"varint,1,opt,name=FolderId,proto3" json:"FolderId,omitempty"`
+}
+
+func (x *SaveFolderResponse) Reset() {
+	*x = SaveFolderResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_data_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SaveFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveFolderResponse) ProtoMessage() {}
+
+func (x *SaveFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveFolderResponse.ProtoReflect.Descriptor instead.
+func (*SaveFolderResponse) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SaveFolderResponse) GetFolderId() uint64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+type GetFoldersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Folders []*Folder `protobuf:"bytes,1,rep,name=Folders,proto3" json:"Folders,omitempty"`
+}
+
+func (x *GetFoldersResponse) Reset() {
+	*x = GetFoldersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_data_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFoldersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFoldersResponse) ProtoMessage() {}
+
+func (x *GetFoldersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFoldersResponse.ProtoReflect.Descriptor instead.
+func (*GetFoldersResponse) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetFoldersResponse) GetFolders() []*Folder {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+type DeleteFolderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id uint64 `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
 }
 
-func (x *DataList) Reset() {
-	*x = DataList{}
+func (x *DeleteFolderRequest) Reset() {
+	*x = DeleteFolderRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[1]
+		mi := &file_data_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DataList) String() string {
+func (x *DeleteFolderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DataList) ProtoMessage() {}
+func (*DeleteFolderRequest) ProtoMessage() {}
 
-func (x *DataList) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[1]
+func (x *DeleteFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -177,25 +828,18 @@ func (x *DataList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DataList.ProtoReflect.Descriptor instead.
-func (*DataList) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use DeleteFolderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFolderRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *DataList) GetId() uint64 {
+func (x *DeleteFolderRequest) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *DataList) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
 type SaveDataRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -207,7 +851,7 @@ type SaveDataRequest struct {
 func (x *SaveDataRequest) Reset() {
 	*x = SaveDataRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[2]
+		mi := &file_data_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -220,7 +864,7 @@ func (x *SaveDataRequest) String() string {
 func (*SaveDataRequest) ProtoMessage() {}
 
 func (x *SaveDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[2]
+	mi := &file_data_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -233,7 +877,7 @@ func (x *SaveDataRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SaveDataRequest.ProtoReflect.Descriptor instead.
 func (*SaveDataRequest) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{2}
+	return file_data_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SaveDataRequest) GetData() *Data {
@@ -254,7 +898,7 @@ type GetDataRequest struct {
 func (x *GetDataRequest) Reset() {
 	*x = GetDataRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[3]
+		mi := &file_data_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -267,7 +911,7 @@ func (x *GetDataRequest) String() string {
 func (*GetDataRequest) ProtoMessage() {}
 
 func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[3]
+	mi := &file_data_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -280,7 +924,7 @@ func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDataRequest.ProtoReflect.Descriptor instead.
 func (*GetDataRequest) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{3}
+	return file_data_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetDataRequest) GetId() uint64 {
@@ -301,7 +945,7 @@ type DeleteDataRequest struct {
 func (x *DeleteDataRequest) Reset() {
 	*x = DeleteDataRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[4]
+		mi := &file_data_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -314,7 +958,7 @@ func (x *DeleteDataRequest) String() string {
 func (*DeleteDataRequest) ProtoMessage() {}
 
 func (x *DeleteDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[4]
+	mi := &file_data_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -327,7 +971,7 @@ func (x *DeleteDataRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteDataRequest.ProtoReflect.Descriptor instead.
 func (*DeleteDataRequest) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{4}
+	return file_data_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DeleteDataRequest) GetId() uint64 {
@@ -352,7 +996,7 @@ type UploadFileRequest struct {
 func (x *UploadFileRequest) Reset() {
 	*x = UploadFileRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[5]
+		mi := &file_data_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -365,7 +1009,7 @@ func (x *UploadFileRequest) String() string {
 func (*UploadFileRequest) ProtoMessage() {}
 
 func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[5]
+	mi := &file_data_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,7 +1022,7 @@ func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadFileRequest.ProtoReflect.Descriptor instead.
 func (*UploadFileRequest) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{5}
+	return file_data_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *UploadFileRequest) GetDataId() uint64 {
@@ -428,7 +1072,7 @@ type DownloadFileRequest struct {
 func (x *DownloadFileRequest) Reset() {
 	*x = DownloadFileRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[6]
+		mi := &file_data_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -441,7 +1085,7 @@ func (x *DownloadFileRequest) String() string {
 func (*DownloadFileRequest) ProtoMessage() {}
 
 func (x *DownloadFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[6]
+	mi := &file_data_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -454,7 +1098,7 @@ func (x *DownloadFileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownloadFileRequest.ProtoReflect.Descriptor instead.
 func (*DownloadFileRequest) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{6}
+	return file_data_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DownloadFileRequest) GetFileID() uint64 {
@@ -471,6 +1115,134 @@ func (x *DownloadFileRequest) GetDataID() uint64 {
 	return 0
 }
 
+// ShareDataRequest предоставление доступа к записи пользователю с логином Login
+// RecordKey - ключ записи, зашифрованный открытым ключом получателя
+type ShareDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataId     uint64          `protobuf:"varint,1,opt,name=DataId,proto3" json:"DataId,omitempty"`
+	Login      string          `protobuf:"bytes,2,opt,name=Login,proto3" json:"Login,omitempty"`
+	RecordKey  string          `protobuf:"bytes,3,opt,name=RecordKey,proto3" json:"RecordKey,omitempty"`
+	Permission SharePermission `protobuf:"varint,4,opt,name=Permission,proto3,enum=gophkeeper.SharePermission" json:"Permission,omitempty"`
+}
+
+func (x *ShareDataRequest) Reset() {
+	*x = ShareDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_data_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShareDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareDataRequest) ProtoMessage() {}
+
+func (x *ShareDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareDataRequest.ProtoReflect.Descriptor instead.
+func (*ShareDataRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ShareDataRequest) GetDataId() uint64 {
+	if x != nil {
+		return x.DataId
+	}
+	return 0
+}
+
+func (x *ShareDataRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *ShareDataRequest) GetRecordKey() string {
+	if x != nil {
+		return x.RecordKey
+	}
+	return ""
+}
+
+func (x *ShareDataRequest) GetPermission() SharePermission {
+	if x != nil {
+		return x.Permission
+	}
+	return SharePermission_SHARE_PERMISSION_NONE
+}
+
+type UnshareDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataId uint64 `protobuf:"varint,1,opt,name=DataId,proto3" json:"DataId,omitempty"`
+	Login  string `protobuf:"bytes,2,opt,name=Login,proto3" json:"Login,omitempty"`
+}
+
+func (x *UnshareDataRequest) Reset() {
+	*x = UnshareDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_data_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnshareDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareDataRequest) ProtoMessage() {}
+
+func (x *UnshareDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareDataRequest.ProtoReflect.Descriptor instead.
+func (*UnshareDataRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UnshareDataRequest) GetDataId() uint64 {
+	if x != nil {
+		return x.DataId
+	}
+	return 0
+}
+
+func (x *UnshareDataRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
 type GetDataResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -483,7 +1255,7 @@ type GetDataResponse struct {
 func (x *GetDataResponse) Reset() {
 	*x = GetDataResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[7]
+		mi := &file_data_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -496,7 +1268,7 @@ func (x *GetDataResponse) String() string {
 func (*GetDataResponse) ProtoMessage() {}
 
 func (x *GetDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[7]
+	mi := &file_data_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,7 +1281,7 @@ func (x *GetDataResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDataResponse.ProtoReflect.Descriptor instead.
 func (*GetDataResponse) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{7}
+	return file_data_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetDataResponse) GetData() *Data {
@@ -539,7 +1311,7 @@ type SaveDataResponse struct {
 func (x *SaveDataResponse) Reset() {
 	*x = SaveDataResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[8]
+		mi := &file_data_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -552,7 +1324,7 @@ func (x *SaveDataResponse) String() string {
 func (*SaveDataResponse) ProtoMessage() {}
 
 func (x *SaveDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[8]
+	mi := &file_data_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -565,7 +1337,7 @@ func (x *SaveDataResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SaveDataResponse.ProtoReflect.Descriptor instead.
 func (*SaveDataResponse) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{8}
+	return file_data_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *SaveDataResponse) GetDataId() uint64 {
@@ -594,13 +1366,14 @@ type DataListResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DataList []*DataList `protobuf:"bytes,1,rep,name=DataList,proto3" json:"DataList,omitempty"`
+	DataList      []*DataList `protobuf:"bytes,1,rep,name=DataList,proto3" json:"DataList,omitempty"`
+	NextPageToken string      `protobuf:"bytes,2,opt,name=NextPageToken,proto3" json:"NextPageToken,omitempty"`
 }
 
 func (x *DataListResponse) Reset() {
 	*x = DataListResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[9]
+		mi := &file_data_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -613,7 +1386,7 @@ func (x *DataListResponse) String() string {
 func (*DataListResponse) ProtoMessage() {}
 
 func (x *DataListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[9]
+	mi := &file_data_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -626,7 +1399,7 @@ func (x *DataListResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DataListResponse.ProtoReflect.Descriptor instead.
 func (*DataListResponse) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{9}
+	return file_data_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *DataListResponse) GetDataList() []*DataList {
@@ -636,6 +1409,13 @@ func (x *DataListResponse) GetDataList() []*DataList {
 	return nil
 }
 
+func (x *DataListResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type FileUploadResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -649,7 +1429,7 @@ type FileUploadResponse struct {
 func (x *FileUploadResponse) Reset() {
 	*x = FileUploadResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[10]
+		mi := &file_data_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -662,7 +1442,7 @@ func (x *FileUploadResponse) String() string {
 func (*FileUploadResponse) ProtoMessage() {}
 
 func (x *FileUploadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[10]
+	mi := &file_data_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -675,7 +1455,7 @@ func (x *FileUploadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileUploadResponse.ProtoReflect.Descriptor instead.
 func (*FileUploadResponse) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{10}
+	return file_data_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *FileUploadResponse) GetFileId() uint64 {
@@ -710,7 +1490,7 @@ type DownloadFileResponse struct {
 func (x *DownloadFileResponse) Reset() {
 	*x = DownloadFileResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_data_proto_msgTypes[11]
+		mi := &file_data_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -723,7 +1503,7 @@ func (x *DownloadFileResponse) String() string {
 func (*DownloadFileResponse) ProtoMessage() {}
 
 func (x *DownloadFileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_data_proto_msgTypes[11]
+	mi := &file_data_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -736,7 +1516,7 @@ func (x *DownloadFileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownloadFileResponse.ProtoReflect.Descriptor instead.
 func (*DownloadFileResponse) Descriptor() ([]byte, []int) {
-	return file_data_proto_rawDescGZIP(), []int{11}
+	return file_data_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *DownloadFileResponse) GetFileChunk() []byte {
@@ -754,10 +1534,10 @@ var file_data_proto_rawDesc = []byte{
 	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x22, 0xf0, 0x01, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x0e, 0x0a, 0x02, 0x49,
+	0x74, 0x6f, 0x22, 0x98, 0x04, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x0e, 0x0a, 0x02, 0x49,
 	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x04, 0x4e,
 	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0a, 0xba, 0x48, 0x07, 0x72, 0x05,
-	0x10, 0x01, 0x18, 0xff, 0x01, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x4c,
+	0x10, 0x01, 0x18, 0x80, 0x08, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x4c,
 	0x6f, 0x67, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x6f, 0x67, 0x69,
 	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x50, 0x61, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x04, 0x50, 0x61, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20,
@@ -769,92 +1549,232 @@ var file_data_proto_rawDesc = []byte{
 	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a,
 	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
 	0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x46,
-	0x69, 0x6c, 0x65, 0x49, 0x44, 0x22, 0x2e, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73,
-	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49,
-	0x64, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x37, 0x0a, 0x0f, 0x53, 0x61, 0x76, 0x65, 0x44, 0x61, 0x74,
-	0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65,
-	0x70, 0x65, 0x72, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x22, 0x29,
-	0x0a, 0x0e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x17, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x07, 0xba, 0x48,
-	0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x02, 0x49, 0x64, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
-	0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x64, 0x22, 0xc6,
-	0x01, 0x0a, 0x11, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x42, 0x07, 0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x06, 0x44,
-	0x61, 0x74, 0x61, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x29, 0x0a,
-	0x0b, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x04, 0x42, 0x07, 0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x0b, 0x44, 0x61, 0x74,
-	0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x08, 0x46, 0x69, 0x6c, 0x65,
-	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0a, 0xba, 0x48, 0x07, 0x72,
-	0x05, 0x10, 0x01, 0x18, 0xff, 0x01, 0x52, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65,
-	0x12, 0x25, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x0c, 0x42, 0x07, 0xba, 0x48, 0x04, 0x7a, 0x02, 0x10, 0x01, 0x52, 0x09, 0x46, 0x69,
-	0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x57, 0x0a, 0x13, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
-	0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f,
-	0x0a, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x07,
-	0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x12,
-	0x1f, 0x0a, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x42,
-	0x07, 0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x44,
-	0x22, 0x4d, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x10, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44,
-	0x61, 0x74, 0x61, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
-	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
-	0x62, 0x0a, 0x10, 0x53, 0x61, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x44,
-	0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a,
-	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
-	0x72, 0x6f, 0x72, 0x22, 0x44, 0x0a, 0x10, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x4c,
-	0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x70, 0x68,
-	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52,
-	0x08, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x62, 0x0a, 0x12, 0x46, 0x69, 0x6c,
-	0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x16, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x56,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x44, 0x61,
-	0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x34, 0x0a,
-	0x14, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75,
-	0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x43, 0x68,
-	0x75, 0x6e, 0x6b, 0x32, 0xc6, 0x03, 0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x08, 0x53, 0x61, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12,
-	0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x53, 0x61, 0x76,
-	0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x67,
-	0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x44, 0x61,
-	0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0b, 0x47, 0x65,
-	0x74, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x1a, 0x1c, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44,
-	0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x42, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x2e, 0x67, 0x6f, 0x70,
-	0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65,
-	0x70, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74,
-	0x61, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x69, 0x6c, 0x65, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x49,
+	0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x23, 0x0a, 0x04, 0x54, 0x61, 0x67, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x42,
+	0x0f, 0xba, 0x48, 0x0c, 0x92, 0x01, 0x09, 0x10, 0x20, 0x22, 0x05, 0x72, 0x03, 0x18, 0x80, 0x08,
+	0x52, 0x04, 0x54, 0x61, 0x67, 0x73, 0x12, 0x24, 0x0a, 0x08, 0x4e, 0x61, 0x6d, 0x65, 0x48, 0x61,
+	0x73, 0x68, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0xba, 0x48, 0x05, 0x72, 0x03, 0x18,
+	0x80, 0x01, 0x52, 0x08, 0x4e, 0x61, 0x6d, 0x65, 0x48, 0x61, 0x73, 0x68, 0x12, 0x22, 0x0a, 0x07,
+	0x43, 0x61, 0x72, 0x64, 0x45, 0x78, 0x70, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0xba,
+	0x48, 0x05, 0x72, 0x03, 0x18, 0x80, 0x08, 0x52, 0x07, 0x43, 0x61, 0x72, 0x64, 0x45, 0x78, 0x70,
+	0x12, 0x1a, 0x0a, 0x03, 0x4f, 0x74, 0x70, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0xba,
+	0x48, 0x05, 0x72, 0x03, 0x18, 0x80, 0x20, 0x52, 0x03, 0x4f, 0x74, 0x70, 0x12, 0x26, 0x0a, 0x09,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x08, 0xba, 0x48, 0x05, 0x72, 0x03, 0x18, 0x80, 0x08, 0x52, 0x09, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x4b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x42, 0x79,
+	0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x42, 0x79,
+	0x12, 0x3b, 0x0a, 0x0a, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x13,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x0a, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xef, 0x01,
+	0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x61,
+	0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x54, 0x61, 0x67, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64,
+	0x42, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64,
+	0x42, 0x79, 0x12, 0x3b, 0x0a, 0x0a, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22,
+	0x54, 0x0a, 0x06, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x61, 0x72,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x50, 0x61, 0x72,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x0a, 0xba, 0x48, 0x07, 0x72, 0x05, 0x10, 0x01, 0x18, 0x80, 0x08, 0x52,
+	0x04, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xfc, 0x02, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74,
+	0x61, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x0a,
+	0x4e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x08, 0xba, 0x48, 0x05, 0x72, 0x03, 0x18, 0xff, 0x01, 0x52, 0x0a, 0x4e, 0x61, 0x6d, 0x65,
+	0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x2c, 0x0a, 0x0c, 0x4e, 0x61, 0x6d, 0x65, 0x43, 0x6f,
+	0x6e, 0x74, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0xba, 0x48,
+	0x05, 0x72, 0x03, 0x18, 0xff, 0x01, 0x52, 0x0c, 0x4e, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x73, 0x12, 0x32, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e,
+	0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x42, 0x08, 0xba, 0x48, 0x05, 0x82, 0x01, 0x02,
+	0x10, 0x01, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x33, 0x0a, 0x04, 0x53, 0x6f, 0x72, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x2e, 0x53, 0x6f, 0x72, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x08, 0xba,
+	0x48, 0x05, 0x82, 0x01, 0x02, 0x10, 0x01, 0x52, 0x04, 0x53, 0x6f, 0x72, 0x74, 0x12, 0x24, 0x0a,
+	0x08, 0x50, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x42,
+	0x08, 0xba, 0x48, 0x05, 0x2a, 0x03, 0x18, 0xf4, 0x03, 0x52, 0x08, 0x50, 0x61, 0x67, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x26, 0x0a, 0x09, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0xba, 0x48, 0x05, 0x72, 0x03, 0x18, 0x80, 0x08,
+	0x52, 0x09, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x46,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x46,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x04, 0x54, 0x61, 0x67, 0x73, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0f, 0xba, 0x48, 0x0c, 0x92, 0x01, 0x09, 0x10, 0x20, 0x22,
+	0x05, 0x72, 0x03, 0x18, 0x80, 0x08, 0x52, 0x04, 0x54, 0x61, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x64, 0x22, 0x47, 0x0a, 0x11, 0x53, 0x61, 0x76, 0x65, 0x46, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x46, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x70, 0x68,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x42, 0x06, 0xba,
+	0x48, 0x03, 0xc8, 0x01, 0x01, 0x52, 0x06, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x22, 0x30, 0x0a,
+	0x12, 0x53, 0x61, 0x76, 0x65, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x49, 0x64, 0x22,
+	0x42, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x2e, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x52, 0x07, 0x46, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x73, 0x22, 0x2e, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x02, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x07, 0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52,
+	0x02, 0x49, 0x64, 0x22, 0x37, 0x0a, 0x0f, 0x53, 0x61, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x22, 0x29, 0x0a, 0x0e,
+	0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17,
+	0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x07, 0xba, 0x48, 0x04, 0x32,
+	0x02, 0x20, 0x00, 0x52, 0x02, 0x49, 0x64, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x64, 0x22, 0xc6, 0x01, 0x0a,
+	0x11, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1f, 0x0a, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x42, 0x07, 0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x06, 0x44, 0x61, 0x74,
+	0x61, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x0b, 0x44,
+	0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x42, 0x07, 0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0a, 0xba, 0x48, 0x07, 0x72, 0x05, 0x10,
+	0x01, 0x18, 0xff, 0x01, 0x52, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x25,
+	0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0c, 0x42, 0x07, 0xba, 0x48, 0x04, 0x7a, 0x02, 0x10, 0x01, 0x52, 0x09, 0x46, 0x69, 0x6c, 0x65,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x57, 0x0a, 0x13, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61,
+	0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x06,
+	0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x07, 0xba, 0x48,
+	0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x44, 0x12, 0x1f, 0x0a,
+	0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x42, 0x07, 0xba,
+	0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x44, 0x22, 0xc8,
+	0x01, 0x0a, 0x10, 0x53, 0x68, 0x61, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x42, 0x07, 0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x06, 0x44, 0x61,
+	0x74, 0x61, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x09, 0xba, 0x48, 0x06, 0x72, 0x04, 0x10, 0x02, 0x18, 0x64, 0x52, 0x05,
+	0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x28, 0x0a, 0x09, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0a, 0xba, 0x48, 0x07, 0x72, 0x05, 0x10,
+	0x01, 0x18, 0x80, 0x08, 0x52, 0x09, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x12,
+	0x48, 0x0a, 0x0a, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x42, 0x0b, 0xba, 0x48, 0x08, 0x82, 0x01, 0x05, 0x10, 0x01, 0x22, 0x01, 0x00, 0x52, 0x0a, 0x50,
+	0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x56, 0x0a, 0x12, 0x55, 0x6e, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1f, 0x0a, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42,
+	0x07, 0xba, 0x48, 0x04, 0x32, 0x02, 0x20, 0x00, 0x52, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64,
+	0x12, 0x1f, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x09, 0xba, 0x48, 0x06, 0x72, 0x04, 0x10, 0x02, 0x18, 0x64, 0x52, 0x05, 0x4c, 0x6f, 0x67, 0x69,
+	0x6e, 0x22, 0x4d, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e,
+	0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x22, 0x62, 0x0a, 0x10, 0x53, 0x61, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x44, 0x61, 0x74, 0x61, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b,
+	0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x22, 0x6a, 0x0a, 0x10, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61,
+	0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x70,
+	0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x08, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x4e, 0x65,
+	0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x4e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x22, 0x62, 0x0a, 0x12, 0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x20,
+	0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
+	0x53, 0x69, 0x7a, 0x65, 0x22, 0x34, 0x0a, 0x14, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x66, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x66, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x2a, 0x87, 0x01, 0x0a, 0x08, 0x44,
+	0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x44, 0x41, 0x54, 0x41, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x4e, 0x59, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x44, 0x41,
+	0x54, 0x41, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x52, 0x45, 0x44, 0x45, 0x4e, 0x54, 0x49,
+	0x41, 0x4c, 0x53, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x44, 0x41, 0x54,
+	0x41, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x54, 0x45, 0x58, 0x54, 0x10, 0x03, 0x12, 0x12, 0x0a,
+	0x0e, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x10,
+	0x04, 0x12, 0x11, 0x0a, 0x0d, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4f,
+	0x54, 0x50, 0x10, 0x05, 0x2a, 0x63, 0x0a, 0x0f, 0x53, 0x68, 0x61, 0x72, 0x65, 0x50, 0x65, 0x72,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x48, 0x41, 0x52, 0x45,
+	0x5f, 0x50, 0x45, 0x52, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x4e, 0x45,
+	0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x48, 0x41, 0x52, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x4d,
+	0x49, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x10, 0x01, 0x12, 0x1a, 0x0a,
+	0x16, 0x53, 0x48, 0x41, 0x52, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4f,
+	0x4e, 0x5f, 0x57, 0x52, 0x49, 0x54, 0x45, 0x10, 0x02, 0x2a, 0x6d, 0x0a, 0x09, 0x53, 0x6f, 0x72,
+	0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x4f,
+	0x52, 0x44, 0x45, 0x52, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x5f, 0x41, 0x53, 0x43, 0x10, 0x00, 0x12,
+	0x18, 0x0a, 0x14, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x4e, 0x41,
+	0x4d, 0x45, 0x5f, 0x44, 0x45, 0x53, 0x43, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x4f, 0x52,
+	0x54, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x49, 0x44, 0x5f, 0x41, 0x53, 0x43, 0x10, 0x02,
+	0x12, 0x16, 0x0a, 0x12, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x49,
+	0x44, 0x5f, 0x44, 0x45, 0x53, 0x43, 0x10, 0x03, 0x32, 0xb4, 0x06, 0x0a, 0x0b, 0x44, 0x61, 0x74,
+	0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x08, 0x53, 0x61, 0x76, 0x65,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1c, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x53,
+	0x61, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1e,
+	0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x44,
+	0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44, 0x61, 0x74, 0x61,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x07,
+	0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65,
+	0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x43, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1d,
+	0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4d, 0x0a, 0x0a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46,
+	0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e,
+	0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x28, 0x01, 0x12, 0x53, 0x0a, 0x0c, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70,
+	0x65, 0x72, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x4b, 0x0a, 0x0a, 0x53, 0x61, 0x76,
+	0x65, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65,
+	0x65, 0x70, 0x65, 0x72, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x46, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1e, 0x2e, 0x67,
+	0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0c,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x1f, 0x2e, 0x67,
+	0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x41, 0x0a, 0x09, 0x53, 0x68, 0x61, 0x72, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4d, 0x0a, 0x0a, 0x55, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65,
-	0x70, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70,
-	0x65, 0x72, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x53, 0x0a, 0x0c, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
-	0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65,
-	0x65, 0x70, 0x65, 0x72, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b,
-	0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69,
-	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x12, 0x5a, 0x10,
-	0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x45, 0x0a, 0x0b, 0x55, 0x6e, 0x73, 0x68,
+	0x61, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65,
+	0x65, 0x70, 0x65, 0x72, 0x2e, 0x55, 0x6e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42,
+	0x12, 0x5a, 0x10, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -869,43 +1789,72 @@ func file_data_proto_rawDescGZIP() []byte {
 	return file_data_proto_rawDescData
 }
 
-var file_data_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_data_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_data_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_data_proto_goTypes = []any{
-	(*Data)(nil),                 // 0: gophkeeper.Data
-	(*DataList)(nil),             // 1: gophkeeper.DataList
-	(*SaveDataRequest)(nil),      // 2: gophkeeper.SaveDataRequest
-	(*GetDataRequest)(nil),       // 3: gophkeeper.GetDataRequest
-	(*DeleteDataRequest)(nil),    // 4: gophkeeper.DeleteDataRequest
-	(*UploadFileRequest)(nil),    // 5: gophkeeper.UploadFileRequest
-	(*DownloadFileRequest)(nil),  // 6: gophkeeper.DownloadFileRequest
-	(*GetDataResponse)(nil),      // 7: gophkeeper.GetDataResponse
-	(*SaveDataResponse)(nil),     // 8: gophkeeper.SaveDataResponse
-	(*DataListResponse)(nil),     // 9: gophkeeper.DataListResponse
-	(*FileUploadResponse)(nil),   // 10: gophkeeper.FileUploadResponse
-	(*DownloadFileResponse)(nil), // 11: gophkeeper.DownloadFileResponse
-	(*emptypb.Empty)(nil),        // 12: google.protobuf.Empty
+	(DataType)(0),                // 0: gophkeeper.DataType
+	(SharePermission)(0),         // 1: gophkeeper.SharePermission
+	(SortOrder)(0),               // 2: gophkeeper.SortOrder
+	(*Data)(nil),                 // 3: gophkeeper.Data
+	(*DataList)(nil),             // 4: gophkeeper.DataList
+	(*Folder)(nil),               // 5: gophkeeper.Folder
+	(*GetDataListRequest)(nil),   // 6: gophkeeper.GetDataListRequest
+	(*SaveFolderRequest)(nil),    // 7: gophkeeper.SaveFolderRequest
+	(*SaveFolderResponse)(nil),   // 8: gophkeeper.SaveFolderResponse
+	(*GetFoldersResponse)(nil),   // 9: gophkeeper.GetFoldersResponse
+	(*DeleteFolderRequest)(nil),  // 10: gophkeeper.DeleteFolderRequest
+	(*SaveDataRequest)(nil),      // 11: gophkeeper.SaveDataRequest
+	(*GetDataRequest)(nil),       // 12: gophkeeper.GetDataRequest
+	(*DeleteDataRequest)(nil),    // 13: gophkeeper.DeleteDataRequest
+	(*UploadFileRequest)(nil),    // 14: gophkeeper.UploadFileRequest
+	(*DownloadFileRequest)(nil),  // 15: gophkeeper.DownloadFileRequest
+	(*ShareDataRequest)(nil),     // 16: gophkeeper.ShareDataRequest
+	(*UnshareDataRequest)(nil),   // 17: gophkeeper.UnshareDataRequest
+	(*GetDataResponse)(nil),      // 18: gophkeeper.GetDataResponse
+	(*SaveDataResponse)(nil),     // 19: gophkeeper.SaveDataResponse
+	(*DataListResponse)(nil),     // 20: gophkeeper.DataListResponse
+	(*FileUploadResponse)(nil),   // 21: gophkeeper.FileUploadResponse
+	(*DownloadFileResponse)(nil), // 22: gophkeeper.DownloadFileResponse
+	(*emptypb.Empty)(nil),        // 23: google.protobuf.Empty
 }
 var file_data_proto_depIdxs = []int32{
-	0,  // 0: gophkeeper.SaveDataRequest.Data:type_name -> gophkeeper.Data
-	0,  // 1: gophkeeper.GetDataResponse.Data:type_name -> gophkeeper.Data
-	1,  // 2: gophkeeper.DataListResponse.DataList:type_name -> gophkeeper.DataList
-	2,  // 3: gophkeeper.DataService.SaveData:input_type -> gophkeeper.SaveDataRequest
-	12, // 4: gophkeeper.DataService.GetDataList:input_type -> google.protobuf.Empty
-	3,  // 5: gophkeeper.DataService.GetData:input_type -> gophkeeper.GetDataRequest
-	4,  // 6: gophkeeper.DataService.DeleteData:input_type -> gophkeeper.DeleteDataRequest
-	5,  // 7: gophkeeper.DataService.UploadFile:input_type -> gophkeeper.UploadFileRequest
-	6,  // 8: gophkeeper.DataService.DownloadFile:input_type -> gophkeeper.DownloadFileRequest
-	8,  // 9: gophkeeper.DataService.SaveData:output_type -> gophkeeper.SaveDataResponse
-	9,  // 10: gophkeeper.DataService.GetDataList:output_type -> gophkeeper.DataListResponse
-	7,  // 11: gophkeeper.DataService.GetData:output_type -> gophkeeper.GetDataResponse
-	12, // 12: gophkeeper.DataService.DeleteData:output_type -> google.protobuf.Empty
-	10, // 13: gophkeeper.DataService.UploadFile:output_type -> gophkeeper.FileUploadResponse
-	11, // 14: gophkeeper.DataService.DownloadFile:output_type -> gophkeeper.DownloadFileResponse
-	9,  // [9:15] is the sub-list for method output_type
-	3,  // [3:9] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	1,  // 0: gophkeeper.Data.Permission:type_name -> gophkeeper.SharePermission
+	1,  // 1: gophkeeper.DataList.Permission:type_name -> gophkeeper.SharePermission
+	0,  // 2: gophkeeper.GetDataListRequest.Type:type_name -> gophkeeper.DataType
+	2,  // 3: gophkeeper.GetDataListRequest.Sort:type_name -> gophkeeper.SortOrder
+	5,  // 4: gophkeeper.SaveFolderRequest.Folder:type_name -> gophkeeper.Folder
+	5,  // 5: gophkeeper.GetFoldersResponse.Folders:type_name -> gophkeeper.Folder
+	3,  // 6: gophkeeper.SaveDataRequest.Data:type_name -> gophkeeper.Data
+	1,  // 7: gophkeeper.ShareDataRequest.Permission:type_name -> gophkeeper.SharePermission
+	3,  // 8: gophkeeper.GetDataResponse.Data:type_name -> gophkeeper.Data
+	4,  // 9: gophkeeper.DataListResponse.DataList:type_name -> gophkeeper.DataList
+	11, // 10: gophkeeper.DataService.SaveData:input_type -> gophkeeper.SaveDataRequest
+	6,  // 11: gophkeeper.DataService.GetDataList:input_type -> gophkeeper.GetDataListRequest
+	12, // 12: gophkeeper.DataService.GetData:input_type -> gophkeeper.GetDataRequest
+	13, // 13: gophkeeper.DataService.DeleteData:input_type -> gophkeeper.DeleteDataRequest
+	14, // 14: gophkeeper.DataService.UploadFile:input_type -> gophkeeper.UploadFileRequest
+	15, // 15: gophkeeper.DataService.DownloadFile:input_type -> gophkeeper.DownloadFileRequest
+	7,  // 16: gophkeeper.DataService.SaveFolder:input_type -> gophkeeper.SaveFolderRequest
+	23, // 17: gophkeeper.DataService.GetFolders:input_type -> google.protobuf.Empty
+	10, // 18: gophkeeper.DataService.DeleteFolder:input_type -> gophkeeper.DeleteFolderRequest
+	16, // 19: gophkeeper.DataService.ShareData:input_type -> gophkeeper.ShareDataRequest
+	17, // 20: gophkeeper.DataService.UnshareData:input_type -> gophkeeper.UnshareDataRequest
+	19, // 21: gophkeeper.DataService.SaveData:output_type -> gophkeeper.SaveDataResponse
+	20, // 22: gophkeeper.DataService.GetDataList:output_type -> gophkeeper.DataListResponse
+	18, // 23: gophkeeper.DataService.GetData:output_type -> gophkeeper.GetDataResponse
+	23, // 24: gophkeeper.DataService.DeleteData:output_type -> google.protobuf.Empty
+	21, // 25: gophkeeper.DataService.UploadFile:output_type -> gophkeeper.FileUploadResponse
+	22, // 26: gophkeeper.DataService.DownloadFile:output_type -> gophkeeper.DownloadFileResponse
+	8,  // 27: gophkeeper.DataService.SaveFolder:output_type -> gophkeeper.SaveFolderResponse
+	9,  // 28: gophkeeper.DataService.GetFolders:output_type -> gophkeeper.GetFoldersResponse
+	23, // 29: gophkeeper.DataService.DeleteFolder:output_type -> google.protobuf.Empty
+	23, // 30: gophkeeper.DataService.ShareData:output_type -> google.protobuf.Empty
+	23, // 31: gophkeeper.DataService.UnshareData:output_type -> google.protobuf.Empty
+	21, // [21:32] is the sub-list for method output_type
+	10, // [10:21] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_data_proto_init() }
@@ -939,7 +1888,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*SaveDataRequest); i {
+			switch v := v.(*Folder); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -951,7 +1900,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*GetDataRequest); i {
+			switch v := v.(*GetDataListRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -963,7 +1912,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*DeleteDataRequest); i {
+			switch v := v.(*SaveFolderRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -975,7 +1924,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*UploadFileRequest); i {
+			switch v := v.(*SaveFolderResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -987,7 +1936,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*DownloadFileRequest); i {
+			switch v := v.(*GetFoldersResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -999,7 +1948,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[7].Exporter = func(v any, i int) any {
-			switch v := v.(*GetDataResponse); i {
+			switch v := v.(*DeleteFolderRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1011,7 +1960,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[8].Exporter = func(v any, i int) any {
-			switch v := v.(*SaveDataResponse); i {
+			switch v := v.(*SaveDataRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1023,7 +1972,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[9].Exporter = func(v any, i int) any {
-			switch v := v.(*DataListResponse); i {
+			switch v := v.(*GetDataRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1035,7 +1984,7 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[10].Exporter = func(v any, i int) any {
-			switch v := v.(*FileUploadResponse); i {
+			switch v := v.(*DeleteDataRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1047,6 +1996,102 @@ func file_data_proto_init() {
 			}
 		}
 		file_data_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*UploadFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_data_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*DownloadFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_data_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*ShareDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_data_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*UnshareDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_data_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*GetDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_data_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*SaveDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_data_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*DataListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_data_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*FileUploadResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_data_proto_msgTypes[19].Exporter = func(v any, i int) any {
 			switch v := v.(*DownloadFileResponse); i {
 			case 0:
 				return &v.state
@@ -1064,13 +2109,14 @@ func file_data_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_data_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   12,
+			NumEnums:      3,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_data_proto_goTypes,
 		DependencyIndexes: file_data_proto_depIdxs,
+		EnumInfos:         file_data_proto_enumTypes,
 		MessageInfos:      file_data_proto_msgTypes,
 	}.Build()
 	File_data_proto = out.File
@@ -26,6 +26,11 @@ const (
 	DataService_DeleteData_FullMethodName   = "/gophkeeper.DataService/DeleteData"
 	DataService_UploadFile_FullMethodName   = "/gophkeeper.DataService/UploadFile"
 	DataService_DownloadFile_FullMethodName = "/gophkeeper.DataService/DownloadFile"
+	DataService_SaveFolder_FullMethodName   = "/gophkeeper.DataService/SaveFolder"
+	DataService_GetFolders_FullMethodName   = "/gophkeeper.DataService/GetFolders"
+	DataService_DeleteFolder_FullMethodName = "/gophkeeper.DataService/DeleteFolder"
+	DataService_ShareData_FullMethodName    = "/gophkeeper.DataService/ShareData"
+	DataService_UnshareData_FullMethodName  = "/gophkeeper.DataService/UnshareData"
 )
 
 // DataServiceClient is the client API for DataService service.
@@ -33,11 +38,16 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type DataServiceClient interface {
 	SaveData(ctx context.Context, in *SaveDataRequest, opts ...grpc.CallOption) (*SaveDataResponse, error)
-	GetDataList(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DataListResponse, error)
+	GetDataList(ctx context.Context, in *GetDataListRequest, opts ...grpc.CallOption) (*DataListResponse, error)
 	GetData(ctx context.Context, in *GetDataRequest, opts ...grpc.CallOption) (*GetDataResponse, error)
 	DeleteData(ctx context.Context, in *DeleteDataRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UploadFile(ctx context.Context, opts ...grpc.CallOption) (DataService_UploadFileClient, error)
 	DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (DataService_DownloadFileClient, error)
+	SaveFolder(ctx context.Context, in *SaveFolderRequest, opts ...grpc.CallOption) (*SaveFolderResponse, error)
+	GetFolders(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetFoldersResponse, error)
+	DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ShareData(ctx context.Context, in *ShareDataRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UnshareData(ctx context.Context, in *UnshareDataRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type dataServiceClient struct {
@@ -58,7 +68,7 @@ func (c *dataServiceClient) SaveData(ctx context.Context, in *SaveDataRequest, o
 	return out, nil
 }
 
-func (c *dataServiceClient) GetDataList(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DataListResponse, error) {
+func (c *dataServiceClient) GetDataList(ctx context.Context, in *GetDataListRequest, opts ...grpc.CallOption) (*DataListResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DataListResponse)
 	err := c.cc.Invoke(ctx, DataService_GetDataList_FullMethodName, in, out, cOpts...)
@@ -156,16 +166,71 @@ func (x *dataServiceDownloadFileClient) Recv() (*DownloadFileResponse, error) {
 	return m, nil
 }
 
+func (c *dataServiceClient) SaveFolder(ctx context.Context, in *SaveFolderRequest, opts ...grpc.CallOption) (*SaveFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SaveFolderResponse)
+	err := c.cc.Invoke(ctx, DataService_SaveFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) GetFolders(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetFoldersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFoldersResponse)
+	err := c.cc.Invoke(ctx, DataService_GetFolders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, DataService_DeleteFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) ShareData(ctx context.Context, in *ShareDataRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, DataService_ShareData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) UnshareData(ctx context.Context, in *UnshareDataRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, DataService_UnshareData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DataServiceServer is the server API for DataService service.
 // All implementations must embed UnimplementedDataServiceServer
 // for forward compatibility
 type DataServiceServer interface {
 	SaveData(context.Context, *SaveDataRequest) (*SaveDataResponse, error)
-	GetDataList(context.Context, *emptypb.Empty) (*DataListResponse, error)
+	GetDataList(context.Context, *GetDataListRequest) (*DataListResponse, error)
 	GetData(context.Context, *GetDataRequest) (*GetDataResponse, error)
 	DeleteData(context.Context, *DeleteDataRequest) (*emptypb.Empty, error)
 	UploadFile(DataService_UploadFileServer) error
 	DownloadFile(*DownloadFileRequest, DataService_DownloadFileServer) error
+	SaveFolder(context.Context, *SaveFolderRequest) (*SaveFolderResponse, error)
+	GetFolders(context.Context, *emptypb.Empty) (*GetFoldersResponse, error)
+	DeleteFolder(context.Context, *DeleteFolderRequest) (*emptypb.Empty, error)
+	ShareData(context.Context, *ShareDataRequest) (*emptypb.Empty, error)
+	UnshareData(context.Context, *UnshareDataRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedDataServiceServer()
 }
 
@@ -176,7 +241,7 @@ type UnimplementedDataServiceServer struct {
 func (UnimplementedDataServiceServer) SaveData(context.Context, *SaveDataRequest) (*SaveDataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SaveData not implemented")
 }
-func (UnimplementedDataServiceServer) GetDataList(context.Context, *emptypb.Empty) (*DataListResponse, error) {
+func (UnimplementedDataServiceServer) GetDataList(context.Context, *GetDataListRequest) (*DataListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDataList not implemented")
 }
 func (UnimplementedDataServiceServer) GetData(context.Context, *GetDataRequest) (*GetDataResponse, error) {
@@ -191,6 +256,21 @@ func (UnimplementedDataServiceServer) UploadFile(DataService_UploadFileServer) e
 func (UnimplementedDataServiceServer) DownloadFile(*DownloadFileRequest, DataService_DownloadFileServer) error {
 	return status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
 }
+func (UnimplementedDataServiceServer) SaveFolder(context.Context, *SaveFolderRequest) (*SaveFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveFolder not implemented")
+}
+func (UnimplementedDataServiceServer) GetFolders(context.Context, *emptypb.Empty) (*GetFoldersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFolders not implemented")
+}
+func (UnimplementedDataServiceServer) DeleteFolder(context.Context, *DeleteFolderRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolder not implemented")
+}
+func (UnimplementedDataServiceServer) ShareData(context.Context, *ShareDataRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareData not implemented")
+}
+func (UnimplementedDataServiceServer) UnshareData(context.Context, *UnshareDataRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnshareData not implemented")
+}
 func (UnimplementedDataServiceServer) mustEmbedUnimplementedDataServiceServer() {}
 
 // UnsafeDataServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -223,7 +303,7 @@ func _DataService_SaveData_Handler(srv interface{}, ctx context.Context, dec fun
 }
 
 func _DataService_GetDataList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(GetDataListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -235,7 +315,7 @@ func _DataService_GetDataList_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: DataService_GetDataList_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DataServiceServer).GetDataList(ctx, req.(*emptypb.Empty))
+		return srv.(DataServiceServer).GetDataList(ctx, req.(*GetDataListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -323,6 +403,96 @@ func (x *dataServiceDownloadFileServer) Send(m *DownloadFileResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _DataService_SaveFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).SaveFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_SaveFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).SaveFolder(ctx, req.(*SaveFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_GetFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).GetFolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_GetFolders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).GetFolders(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_DeleteFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).DeleteFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_DeleteFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).DeleteFolder(ctx, req.(*DeleteFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_ShareData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).ShareData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_ShareData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).ShareData(ctx, req.(*ShareDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_UnshareData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).UnshareData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_UnshareData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).UnshareData(ctx, req.(*UnshareDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DataService_ServiceDesc is the grpc.ServiceDesc for DataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -346,6 +516,26 @@ var DataService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteData",
 			Handler:    _DataService_DeleteData_Handler,
 		},
+		{
+			MethodName: "SaveFolder",
+			Handler:    _DataService_SaveFolder_Handler,
+		},
+		{
+			MethodName: "GetFolders",
+			Handler:    _DataService_GetFolders_Handler,
+		},
+		{
+			MethodName: "DeleteFolder",
+			Handler:    _DataService_DeleteFolder_Handler,
+		},
+		{
+			MethodName: "ShareData",
+			Handler:    _DataService_ShareData_Handler,
+		},
+		{
+			MethodName: "UnshareData",
+			Handler:    _DataService_UnshareData_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
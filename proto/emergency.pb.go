@@ -0,0 +1,615 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: emergency.proto
+
+package proto
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EmergencyState состояние экстренного доступа
+// INVITED -> ACCEPTED -> REQUESTED -> APPROVED | REJECTED | AUTO_APPROVED, после отказа доступ можно запросить снова
+type EmergencyState int32
+
+const (
+	EmergencyState_EMERGENCY_STATE_NONE          EmergencyState = 0
+	EmergencyState_EMERGENCY_STATE_INVITED       EmergencyState = 1
+	EmergencyState_EMERGENCY_STATE_ACCEPTED      EmergencyState = 2
+	EmergencyState_EMERGENCY_STATE_REQUESTED     EmergencyState = 3
+	EmergencyState_EMERGENCY_STATE_APPROVED      EmergencyState = 4
+	EmergencyState_EMERGENCY_STATE_REJECTED      EmergencyState = 5
+	EmergencyState_EMERGENCY_STATE_AUTO_APPROVED EmergencyState = 6
+)
+
+// Enum value maps for EmergencyState.
+var (
+	EmergencyState_name = map[int32]string{
+		0: "EMERGENCY_STATE_NONE",
+		1: "EMERGENCY_STATE_INVITED",
+		2: "EMERGENCY_STATE_ACCEPTED",
+		3: "EMERGENCY_STATE_REQUESTED",
+		4: "EMERGENCY_STATE_APPROVED",
+		5: "EMERGENCY_STATE_REJECTED",
+		6: "EMERGENCY_STATE_AUTO_APPROVED",
+	}
+	EmergencyState_value = map[string]int32{
+		"EMERGENCY_STATE_NONE":          0,
+		"EMERGENCY_STATE_INVITED":       1,
+		"EMERGENCY_STATE_ACCEPTED":      2,
+		"EMERGENCY_STATE_REQUESTED":     3,
+		"EMERGENCY_STATE_APPROVED":      4,
+		"EMERGENCY_STATE_REJECTED":      5,
+		"EMERGENCY_STATE_AUTO_APPROVED": 6,
+	}
+)
+
+func (x EmergencyState) Enum() *EmergencyState {
+	p := new(EmergencyState)
+	*p = x
+	return p
+}
+
+func (x EmergencyState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EmergencyState) Descriptor() protoreflect.EnumDescriptor {
+	return file_emergency_proto_enumTypes[0].Descriptor()
+}
+
+func (EmergencyState) Type() protoreflect.EnumType {
+	return &file_emergency_proto_enumTypes[0]
+}
+
+func (x EmergencyState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EmergencyState.Descriptor instead.
+func (EmergencyState) EnumDescriptor() ([]byte, []int) {
+	return file_emergency_proto_rawDescGZIP(), []int{0}
+}
+
+// EmergencyGrant доверенное лицо Grantee, которое может получить доступ к хранилищу Grantor
+// WaitSeconds - время ожидания, после которого запрос одобряется автоматически, RequestedAt - время запроса (unix)
+// VaultKey - ключ хранилища, зашифрованный открытым ключом доверенного лица, передается только ему после одобрения
+type EmergencyGrant struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          uint64         `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Grantor     string         `protobuf:"bytes,2,opt,name=Grantor,proto3" json:"Grantor,omitempty"`
+	Grantee     string         `protobuf:"bytes,3,opt,name=Grantee,proto3" json:"Grantee,omitempty"`
+	State       EmergencyState `protobuf:"varint,4,opt,name=State,proto3,enum=gophkeeper.EmergencyState" json:"State,omitempty"`
+	WaitSeconds int64          `protobuf:"varint,5,opt,name=WaitSeconds,proto3" json:"WaitSeconds,omitempty"`
+	RequestedAt int64          `protobuf:"varint,6,opt,name=RequestedAt,proto3" json:"RequestedAt,omitempty"`
+	VaultKey    string         `protobuf:"bytes,7,opt,name=VaultKey,proto3" json:"VaultKey,omitempty"`
+}
+
+func (x *EmergencyGrant) Reset() {
+	*x = EmergencyGrant{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_emergency_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EmergencyGrant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmergencyGrant) ProtoMessage() {}
+
+func (x *EmergencyGrant) ProtoReflect() protoreflect.Message {
+	mi := &file_emergency_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmergencyGrant.ProtoReflect.Descriptor instead.
+func (*EmergencyGrant) Descriptor() ([]byte, []int) {
+	return file_emergency_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EmergencyGrant) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EmergencyGrant) GetGrantor() string {
+	if x != nil {
+		return x.Grantor
+	}
+	return ""
+}
+
+func (x *EmergencyGrant) GetGrantee() string {
+	if x != nil {
+		return x.Grantee
+	}
+	return ""
+}
+
+func (x *EmergencyGrant) GetState() EmergencyState {
+	if x != nil {
+		return x.State
+	}
+	return EmergencyState_EMERGENCY_STATE_NONE
+}
+
+func (x *EmergencyGrant) GetWaitSeconds() int64 {
+	if x != nil {
+		return x.WaitSeconds
+	}
+	return 0
+}
+
+func (x *EmergencyGrant) GetRequestedAt() int64 {
+	if x != nil {
+		return x.RequestedAt
+	}
+	return 0
+}
+
+func (x *EmergencyGrant) GetVaultKey() string {
+	if x != nil {
+		return x.VaultKey
+	}
+	return ""
+}
+
+// CreateGrantRequest назначение доверенного лица с логином Login
+type CreateGrantRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Login       string `protobuf:"bytes,1,opt,name=Login,proto3" json:"Login,omitempty"`
+	WaitSeconds int64  `protobuf:"varint,2,opt,name=WaitSeconds,proto3" json:"WaitSeconds,omitempty"`
+	VaultKey    string `protobuf:"bytes,3,opt,name=VaultKey,proto3" json:"VaultKey,omitempty"`
+}
+
+func (x *CreateGrantRequest) Reset() {
+	*x = CreateGrantRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_emergency_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateGrantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGrantRequest) ProtoMessage() {}
+
+func (x *CreateGrantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_emergency_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGrantRequest.ProtoReflect.Descriptor instead.
+func (*CreateGrantRequest) Descriptor() ([]byte, []int) {
+	return file_emergency_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateGrantRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *CreateGrantRequest) GetWaitSeconds() int64 {
+	if x != nil {
+		return x.WaitSeconds
+	}
+	return 0
+}
+
+func (x *CreateGrantRequest) GetVaultKey() string {
+	if x != nil {
+		return x.VaultKey
+	}
+	return ""
+}
+
+type CreateGrantResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id uint64 `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
+}
+
+func (x *CreateGrantResponse) Reset() {
+	*x = CreateGrantResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_emergency_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateGrantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGrantResponse) ProtoMessage() {}
+
+func (x *CreateGrantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_emergency_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGrantResponse.ProtoReflect.Descriptor instead.
+func (*CreateGrantResponse) Descriptor() ([]byte, []int) {
+	return file_emergency_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateGrantResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GrantListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Grants []*EmergencyGrant `protobuf:"bytes,1,rep,name=Grants,proto3" json:"Grants,omitempty"`
+}
+
+func (x *GrantListResponse) Reset() {
+	*x = GrantListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_emergency_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrantListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantListResponse) ProtoMessage() {}
+
+func (x *GrantListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_emergency_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantListResponse.ProtoReflect.Descriptor instead.
+func (*GrantListResponse) Descriptor() ([]byte, []int) {
+	return file_emergency_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GrantListResponse) GetGrants() []*EmergencyGrant {
+	if x != nil {
+		return x.Grants
+	}
+	return nil
+}
+
+type GrantRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id uint64 `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
+}
+
+func (x *GrantRequest) Reset() {
+	*x = GrantRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_emergency_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantRequest) ProtoMessage() {}
+
+func (x *GrantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_emergency_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantRequest.ProtoReflect.Descriptor instead.
+func (*GrantRequest) Descriptor() ([]byte, []int) {
+	return file_emergency_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GrantRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+var File_emergency_proto protoreflect.FileDescriptor
+
+var file_emergency_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x65, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0a, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x1a, 0x1b, 0x62,
+	0x75, 0x66, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74,
+	0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe6, 0x01, 0x0a, 0x0e, 0x45, 0x6d, 0x65, 0x72,
+	0x67, 0x65, 0x6e, 0x63, 0x79, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72,
+	0x61, 0x6e, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x61,
+	0x6e, 0x74, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x65, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x65, 0x65, 0x12, 0x30,
+	0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x45, 0x6d, 0x65, 0x72, 0x67,
+	0x65, 0x6e, 0x63, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x57, 0x61, 0x69, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x57, 0x61, 0x69, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x56, 0x61, 0x75, 0x6c, 0x74, 0x4b, 0x65, 0x79,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x56, 0x61, 0x75, 0x6c, 0x74, 0x4b, 0x65, 0x79,
+	0x22, 0x8b, 0x01, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xba, 0x48, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52,
+	0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x2e, 0x0a, 0x0b, 0x57, 0x61, 0x69, 0x74, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x42, 0x0c, 0xba, 0x48, 0x09,
+	0x22, 0x07, 0x18, 0x80, 0xe7, 0x84, 0x0f, 0x20, 0x00, 0x52, 0x0b, 0x57, 0x61, 0x69, 0x74, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x08, 0x56, 0x61, 0x75, 0x6c, 0x74, 0x4b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0a, 0xba, 0x48, 0x07, 0x72, 0x05, 0x10,
+	0x01, 0x18, 0x80, 0x08, 0x52, 0x08, 0x56, 0x61, 0x75, 0x6c, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x25,
+	0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x02, 0x49, 0x64, 0x22, 0x47, 0x0a, 0x11, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x47, 0x72,
+	0x61, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x70,
+	0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63,
+	0x79, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x06, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x73, 0x22, 0x27,
+	0x0a, 0x0c, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17,
+	0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x07, 0xba, 0x48, 0x04, 0x32,
+	0x02, 0x20, 0x00, 0x52, 0x02, 0x49, 0x64, 0x2a, 0xe3, 0x01, 0x0a, 0x0e, 0x45, 0x6d, 0x65, 0x72,
+	0x67, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x14, 0x45, 0x4d,
+	0x45, 0x52, 0x47, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x4e, 0x4f,
+	0x4e, 0x45, 0x10, 0x00, 0x12, 0x1b, 0x0a, 0x17, 0x45, 0x4d, 0x45, 0x52, 0x47, 0x45, 0x4e, 0x43,
+	0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x49, 0x54, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x1c, 0x0a, 0x18, 0x45, 0x4d, 0x45, 0x52, 0x47, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x45, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12,
+	0x1d, 0x0a, 0x19, 0x45, 0x4d, 0x45, 0x52, 0x47, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x45, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1c,
+	0x0a, 0x18, 0x45, 0x4d, 0x45, 0x52, 0x47, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x45, 0x5f, 0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x04, 0x12, 0x1c, 0x0a, 0x18,
+	0x45, 0x4d, 0x45, 0x52, 0x47, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
+	0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x05, 0x12, 0x21, 0x0a, 0x1d, 0x45, 0x4d,
+	0x45, 0x52, 0x47, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x41, 0x55,
+	0x54, 0x4f, 0x5f, 0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x06, 0x32, 0xf0, 0x03,
+	0x0a, 0x10, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x4e, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x61, 0x6e,
+	0x74, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1f, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x42, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x73, 0x12,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65,
+	0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x47, 0x72, 0x61, 0x6e, 0x74, 0x12, 0x18, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70,
+	0x65, 0x72, 0x2e, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x41, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x18, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b,
+	0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x41, 0x0a, 0x0d, 0x41, 0x70,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x18, 0x2e, 0x67, 0x6f,
+	0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a,
+	0x0c, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x18, 0x2e,
+	0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x72, 0x61, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x3f, 0x0a, 0x0b, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x12, 0x18,
+	0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x47, 0x72, 0x61, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x42, 0x12, 0x5a, 0x10, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_emergency_proto_rawDescOnce sync.Once
+	file_emergency_proto_rawDescData = file_emergency_proto_rawDesc
+)
+
+func file_emergency_proto_rawDescGZIP() []byte {
+	file_emergency_proto_rawDescOnce.Do(func() {
+		file_emergency_proto_rawDescData = protoimpl.X.CompressGZIP(file_emergency_proto_rawDescData)
+	})
+	return file_emergency_proto_rawDescData
+}
+
+var file_emergency_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_emergency_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_emergency_proto_goTypes = []any{
+	(EmergencyState)(0),         // 0: gophkeeper.EmergencyState
+	(*EmergencyGrant)(nil),      // 1: gophkeeper.EmergencyGrant
+	(*CreateGrantRequest)(nil),  // 2: gophkeeper.CreateGrantRequest
+	(*CreateGrantResponse)(nil), // 3: gophkeeper.CreateGrantResponse
+	(*GrantListResponse)(nil),   // 4: gophkeeper.GrantListResponse
+	(*GrantRequest)(nil),        // 5: gophkeeper.GrantRequest
+	(*emptypb.Empty)(nil),       // 6: google.protobuf.Empty
+}
+var file_emergency_proto_depIdxs = []int32{
+	0, // 0: gophkeeper.EmergencyGrant.State:type_name -> gophkeeper.EmergencyState
+	1, // 1: gophkeeper.GrantListResponse.Grants:type_name -> gophkeeper.EmergencyGrant
+	2, // 2: gophkeeper.EmergencyService.CreateGrant:input_type -> gophkeeper.CreateGrantRequest
+	6, // 3: gophkeeper.EmergencyService.GetGrants:input_type -> google.protobuf.Empty
+	5, // 4: gophkeeper.EmergencyService.AcceptGrant:input_type -> gophkeeper.GrantRequest
+	5, // 5: gophkeeper.EmergencyService.RequestAccess:input_type -> gophkeeper.GrantRequest
+	5, // 6: gophkeeper.EmergencyService.ApproveAccess:input_type -> gophkeeper.GrantRequest
+	5, // 7: gophkeeper.EmergencyService.RejectAccess:input_type -> gophkeeper.GrantRequest
+	5, // 8: gophkeeper.EmergencyService.RevokeGrant:input_type -> gophkeeper.GrantRequest
+	3, // 9: gophkeeper.EmergencyService.CreateGrant:output_type -> gophkeeper.CreateGrantResponse
+	4, // 10: gophkeeper.EmergencyService.GetGrants:output_type -> gophkeeper.GrantListResponse
+	6, // 11: gophkeeper.EmergencyService.AcceptGrant:output_type -> google.protobuf.Empty
+	6, // 12: gophkeeper.EmergencyService.RequestAccess:output_type -> google.protobuf.Empty
+	6, // 13: gophkeeper.EmergencyService.ApproveAccess:output_type -> google.protobuf.Empty
+	6, // 14: gophkeeper.EmergencyService.RejectAccess:output_type -> google.protobuf.Empty
+	6, // 15: gophkeeper.EmergencyService.RevokeGrant:output_type -> google.protobuf.Empty
+	9, // [9:16] is the sub-list for method output_type
+	2, // [2:9] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_emergency_proto_init() }
+func file_emergency_proto_init() {
+	if File_emergency_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_emergency_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*EmergencyGrant); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_emergency_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateGrantRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_emergency_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateGrantResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_emergency_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GrantListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_emergency_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GrantRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_emergency_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_emergency_proto_goTypes,
+		DependencyIndexes: file_emergency_proto_depIdxs,
+		EnumInfos:         file_emergency_proto_enumTypes,
+		MessageInfos:      file_emergency_proto_msgTypes,
+	}.Build()
+	File_emergency_proto = out.File
+	file_emergency_proto_rawDesc = nil
+	file_emergency_proto_goTypes = nil
+	file_emergency_proto_depIdxs = nil
+}
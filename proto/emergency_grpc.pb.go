@@ -0,0 +1,339 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.27.1
+// source: emergency.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	EmergencyService_CreateGrant_FullMethodName   = "/gophkeeper.EmergencyService/CreateGrant"
+	EmergencyService_GetGrants_FullMethodName     = "/gophkeeper.EmergencyService/GetGrants"
+	EmergencyService_AcceptGrant_FullMethodName   = "/gophkeeper.EmergencyService/AcceptGrant"
+	EmergencyService_RequestAccess_FullMethodName = "/gophkeeper.EmergencyService/RequestAccess"
+	EmergencyService_ApproveAccess_FullMethodName = "/gophkeeper.EmergencyService/ApproveAccess"
+	EmergencyService_RejectAccess_FullMethodName  = "/gophkeeper.EmergencyService/RejectAccess"
+	EmergencyService_RevokeGrant_FullMethodName   = "/gophkeeper.EmergencyService/RevokeGrant"
+)
+
+// EmergencyServiceClient is the client API for EmergencyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EmergencyServiceClient interface {
+	CreateGrant(ctx context.Context, in *CreateGrantRequest, opts ...grpc.CallOption) (*CreateGrantResponse, error)
+	GetGrants(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GrantListResponse, error)
+	AcceptGrant(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RequestAccess(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ApproveAccess(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RejectAccess(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RevokeGrant(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type emergencyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEmergencyServiceClient(cc grpc.ClientConnInterface) EmergencyServiceClient {
+	return &emergencyServiceClient{cc}
+}
+
+func (c *emergencyServiceClient) CreateGrant(ctx context.Context, in *CreateGrantRequest, opts ...grpc.CallOption) (*CreateGrantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateGrantResponse)
+	err := c.cc.Invoke(ctx, EmergencyService_CreateGrant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyServiceClient) GetGrants(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GrantListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GrantListResponse)
+	err := c.cc.Invoke(ctx, EmergencyService_GetGrants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyServiceClient) AcceptGrant(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EmergencyService_AcceptGrant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyServiceClient) RequestAccess(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EmergencyService_RequestAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyServiceClient) ApproveAccess(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EmergencyService_ApproveAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyServiceClient) RejectAccess(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EmergencyService_RejectAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyServiceClient) RevokeGrant(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EmergencyService_RevokeGrant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EmergencyServiceServer is the server API for EmergencyService service.
+// All implementations must embed UnimplementedEmergencyServiceServer
+// for forward compatibility
+type EmergencyServiceServer interface {
+	CreateGrant(context.Context, *CreateGrantRequest) (*CreateGrantResponse, error)
+	GetGrants(context.Context, *emptypb.Empty) (*GrantListResponse, error)
+	AcceptGrant(context.Context, *GrantRequest) (*emptypb.Empty, error)
+	RequestAccess(context.Context, *GrantRequest) (*emptypb.Empty, error)
+	ApproveAccess(context.Context, *GrantRequest) (*emptypb.Empty, error)
+	RejectAccess(context.Context, *GrantRequest) (*emptypb.Empty, error)
+	RevokeGrant(context.Context, *GrantRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedEmergencyServiceServer()
+}
+
+// UnimplementedEmergencyServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEmergencyServiceServer struct {
+}
+
+func (UnimplementedEmergencyServiceServer) CreateGrant(context.Context, *CreateGrantRequest) (*CreateGrantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGrant not implemented")
+}
+func (UnimplementedEmergencyServiceServer) GetGrants(context.Context, *emptypb.Empty) (*GrantListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGrants not implemented")
+}
+func (UnimplementedEmergencyServiceServer) AcceptGrant(context.Context, *GrantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptGrant not implemented")
+}
+func (UnimplementedEmergencyServiceServer) RequestAccess(context.Context, *GrantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestAccess not implemented")
+}
+func (UnimplementedEmergencyServiceServer) ApproveAccess(context.Context, *GrantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveAccess not implemented")
+}
+func (UnimplementedEmergencyServiceServer) RejectAccess(context.Context, *GrantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectAccess not implemented")
+}
+func (UnimplementedEmergencyServiceServer) RevokeGrant(context.Context, *GrantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeGrant not implemented")
+}
+func (UnimplementedEmergencyServiceServer) mustEmbedUnimplementedEmergencyServiceServer() {}
+
+// UnsafeEmergencyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EmergencyServiceServer will
+// result in compilation errors.
+type UnsafeEmergencyServiceServer interface {
+	mustEmbedUnimplementedEmergencyServiceServer()
+}
+
+func RegisterEmergencyServiceServer(s grpc.ServiceRegistrar, srv EmergencyServiceServer) {
+	s.RegisterService(&EmergencyService_ServiceDesc, srv)
+}
+
+func _EmergencyService_CreateGrant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServiceServer).CreateGrant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyService_CreateGrant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServiceServer).CreateGrant(ctx, req.(*CreateGrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyService_GetGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServiceServer).GetGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyService_GetGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServiceServer).GetGrants(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyService_AcceptGrant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServiceServer).AcceptGrant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyService_AcceptGrant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServiceServer).AcceptGrant(ctx, req.(*GrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyService_RequestAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServiceServer).RequestAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyService_RequestAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServiceServer).RequestAccess(ctx, req.(*GrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyService_ApproveAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServiceServer).ApproveAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyService_ApproveAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServiceServer).ApproveAccess(ctx, req.(*GrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyService_RejectAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServiceServer).RejectAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyService_RejectAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServiceServer).RejectAccess(ctx, req.(*GrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyService_RevokeGrant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServiceServer).RevokeGrant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyService_RevokeGrant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServiceServer).RevokeGrant(ctx, req.(*GrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EmergencyService_ServiceDesc is the grpc.ServiceDesc for EmergencyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EmergencyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gophkeeper.EmergencyService",
+	HandlerType: (*EmergencyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGrant",
+			Handler:    _EmergencyService_CreateGrant_Handler,
+		},
+		{
+			MethodName: "GetGrants",
+			Handler:    _EmergencyService_GetGrants_Handler,
+		},
+		{
+			MethodName: "AcceptGrant",
+			Handler:    _EmergencyService_AcceptGrant_Handler,
+		},
+		{
+			MethodName: "RequestAccess",
+			Handler:    _EmergencyService_RequestAccess_Handler,
+		},
+		{
+			MethodName: "ApproveAccess",
+			Handler:    _EmergencyService_ApproveAccess_Handler,
+		},
+		{
+			MethodName: "RejectAccess",
+			Handler:    _EmergencyService_RejectAccess_Handler,
+		},
+		{
+			MethodName: "RevokeGrant",
+			Handler:    _EmergencyService_RevokeGrant_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "emergency.proto",
+}
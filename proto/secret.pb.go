@@ -0,0 +1,253 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: secret.proto
+
+package proto
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CreateSecretShareRequest секрет для передачи по ссылке человеку без учетной записи
+// Blob - секрет, зашифрованный на клиенте, ключ передается только во фрагменте ссылки
+// ExpireSeconds - время жизни ссылки, MaxViews - сколько раз секрет можно открыть
+type CreateSecretShareRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blob          string `protobuf:"bytes,1,opt,name=Blob,proto3" json:"Blob,omitempty"`
+	ExpireSeconds int64  `protobuf:"varint,2,opt,name=ExpireSeconds,proto3" json:"ExpireSeconds,omitempty"`
+	MaxViews      int32  `protobuf:"varint,3,opt,name=MaxViews,proto3" json:"MaxViews,omitempty"`
+}
+
+func (x *CreateSecretShareRequest) Reset() {
+	*x = CreateSecretShareRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secret_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSecretShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSecretShareRequest) ProtoMessage() {}
+
+func (x *CreateSecretShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSecretShareRequest.ProtoReflect.Descriptor instead.
+func (*CreateSecretShareRequest) Descriptor() ([]byte, []int) {
+	return file_secret_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateSecretShareRequest) GetBlob() string {
+	if x != nil {
+		return x.Blob
+	}
+	return ""
+}
+
+func (x *CreateSecretShareRequest) GetExpireSeconds() int64 {
+	if x != nil {
+		return x.ExpireSeconds
+	}
+	return 0
+}
+
+func (x *CreateSecretShareRequest) GetMaxViews() int32 {
+	if x != nil {
+		return x.MaxViews
+	}
+	return 0
+}
+
+// CreateSecretShareResponse Url - ссылка без фрагмента с ключом, его добавляет клиент
+type CreateSecretShareResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id  string `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Url string `protobuf:"bytes,2,opt,name=Url,proto3" json:"Url,omitempty"`
+}
+
+func (x *CreateSecretShareResponse) Reset() {
+	*x = CreateSecretShareResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secret_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSecretShareResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSecretShareResponse) ProtoMessage() {}
+
+func (x *CreateSecretShareResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSecretShareResponse.ProtoReflect.Descriptor instead.
+func (*CreateSecretShareResponse) Descriptor() ([]byte, []int) {
+	return file_secret_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateSecretShareResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateSecretShareResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+var File_secret_proto protoreflect.FileDescriptor
+
+var file_secret_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a,
+	0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x1a, 0x1b, 0x62, 0x75, 0x66, 0x2f,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x96, 0x01, 0x0a, 0x18, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x04, 0x42, 0x6c, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x0b, 0xba, 0x48, 0x08, 0x72, 0x06, 0x10, 0x01, 0x18, 0x80, 0x80, 0x40, 0x52,
+	0x04, 0x42, 0x6c, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x0d, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x42, 0x0c, 0xba, 0x48,
+	0x09, 0x22, 0x07, 0x18, 0x80, 0x9a, 0x9e, 0x01, 0x20, 0x00, 0x52, 0x0d, 0x45, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x25, 0x0a, 0x08, 0x4d, 0x61, 0x78,
+	0x56, 0x69, 0x65, 0x77, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42, 0x09, 0xba, 0x48, 0x06,
+	0x1a, 0x04, 0x18, 0x64, 0x20, 0x00, 0x52, 0x08, 0x4d, 0x61, 0x78, 0x56, 0x69, 0x65, 0x77, 0x73,
+	0x22, 0x3d, 0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a,
+	0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x10, 0x0a,
+	0x03, 0x55, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x55, 0x72, 0x6c, 0x32,
+	0x71, 0x0a, 0x0d, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x60, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x24, 0x2e, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70,
+	0x65, 0x72, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x67, 0x6f,
+	0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x12, 0x5a, 0x10, 0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_secret_proto_rawDescOnce sync.Once
+	file_secret_proto_rawDescData = file_secret_proto_rawDesc
+)
+
+func file_secret_proto_rawDescGZIP() []byte {
+	file_secret_proto_rawDescOnce.Do(func() {
+		file_secret_proto_rawDescData = protoimpl.X.CompressGZIP(file_secret_proto_rawDescData)
+	})
+	return file_secret_proto_rawDescData
+}
+
+var file_secret_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_secret_proto_goTypes = []any{
+	(*CreateSecretShareRequest)(nil),  // 0: gophkeeper.CreateSecretShareRequest
+	(*CreateSecretShareResponse)(nil), // 1: gophkeeper.CreateSecretShareResponse
+}
+var file_secret_proto_depIdxs = []int32{
+	0, // 0: gophkeeper.SecretService.CreateSecretShare:input_type -> gophkeeper.CreateSecretShareRequest
+	1, // 1: gophkeeper.SecretService.CreateSecretShare:output_type -> gophkeeper.CreateSecretShareResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_secret_proto_init() }
+func file_secret_proto_init() {
+	if File_secret_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_secret_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateSecretShareRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secret_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateSecretShareResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_secret_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_secret_proto_goTypes,
+		DependencyIndexes: file_secret_proto_depIdxs,
+		MessageInfos:      file_secret_proto_msgTypes,
+	}.Build()
+	File_secret_proto = out.File
+	file_secret_proto_rawDesc = nil
+	file_secret_proto_goTypes = nil
+	file_secret_proto_depIdxs = nil
+}
@@ -0,0 +1,215 @@
+// Package account пакет для удаления учетной записи пользователя вместе со всеми его данными
+// удаление требует повторного ввода пароля и может быть отложено на срок, в течение которого его можно отменить
+package account
+
+import (
+	"context"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/user"
+	"time"
+)
+
+type Service struct {
+	repo     Repository
+	userRepo UserRepository
+	orgRepo  OrganizationRepository
+	blobs    BlobDeleter
+	// Audit журнал аудита, nil - события не записываются
+	Audit user.Auditor
+	// now текущее время, подменяется в тестах
+	now func() time.Time
+}
+
+// Repository интерфейс удаления учетных записей со всеми данными в одной транзакции
+type Repository interface {
+	Purge(ctx context.Context, purge domain2.AccountPurge) ([]string, error)
+}
+
+// UserRepository интерфейс хранилища пользователей, необходимый для удаления учетной записи
+type UserRepository interface {
+	GetByID(ctx context.Context, id uint64) (domain2.User, error)
+	GetState(ctx context.Context, id uint64) (*domain2.AccountState, error)
+	SetState(ctx context.Context, id uint64, state domain2.AccountState) error
+	GetPurgeDue(ctx context.Context, now time.Time) ([]uint64, error)
+}
+
+// OrganizationRepository интерфейс хранилища организаций, необходимый для удаления вместе с пользователем
+// организаций, где он единственный участник
+type OrganizationRepository interface {
+	GetByUser(ctx context.Context, uid uint64) ([]domain2.OrgMembership, error)
+	GetMembers(ctx context.Context, orgID uint64) ([]domain2.OrgMember, error)
+}
+
+// BlobDeleter удаление файлов с диска после удаления записей о них
+type BlobDeleter interface {
+	DeleteBlobs(ctx context.Context, paths []string)
+}
+
+func NewService(repo Repository, userRepo UserRepository, orgRepo OrganizationRepository, blobs BlobDeleter) *Service {
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		orgRepo:  orgRepo,
+		blobs:    blobs,
+		now:      time.Now,
+	}
+}
+
+// Delete удаление учетной записи uid после проверки пароля
+// из организаций с другими участниками пользователя должен сначала исключить администратор со сменой ключа хранилища
+// grace 0 - записи, файлы, папки, доступы и участие в организациях удаляются сразу, иначе удаление планируется
+// через grace: все выданные токены отзываются, вход запрещен, а удаление можно отменить через user.Service.Restore;
+// возвращается время окончательного удаления, нулевое - учетная запись уже удалена
+func (s *Service) Delete(ctx context.Context, uid uint64, password string, grace time.Duration) (time.Time, error) {
+	u, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching user", "uid", uid, "err", err)
+		return time.Time{}, domain2.ErrInternalServerError
+	}
+
+	if u.ID == 0 {
+		return time.Time{}, domain2.ErrUserNotFound
+	}
+
+	passwordCorrect, err := user.CheckPassword(password, u.Password)
+	if err != nil {
+		internal.Logger.Errorw("error while checking password", "uid", uid, "err", err)
+		return time.Time{}, domain2.ErrInternalServerError
+	}
+
+	if !passwordCorrect {
+		s.record(ctx, uid, domain2.AuditDeleteAccount, domain2.ErrWrongPassword)
+		return time.Time{}, domain2.ErrWrongPassword
+	}
+
+	// организация не должна остаться без владельца или со старым ключом, это проверяется до планирования удаления
+	purge, err := s.plan(ctx, uid)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if grace <= 0 {
+		s.record(ctx, uid, domain2.AuditDeleteAccount, nil)
+		return time.Time{}, s.purge(ctx, uid, purge)
+	}
+
+	// время выдачи токена хранится с точностью до секунды
+	now := s.now().Truncate(time.Second)
+	state := domain2.AccountState{PurgeAt: now.Add(grace), SessionsAfter: now}
+	if err = s.userRepo.SetState(ctx, uid, state); err != nil {
+		internal.Logger.Errorw("error while scheduling account deletion", "uid", uid, "err", err)
+		return time.Time{}, domain2.ErrInternalServerError
+	}
+
+	s.record(ctx, uid, domain2.AuditDeleteAccount, nil)
+
+	return state.PurgeAt, nil
+}
+
+// PurgeDue окончательное удаление учетных записей, срок отмены удаления которых истек
+// учетная запись, которую удалить нельзя, например, единственный владелец организации, пропускается
+func (s *Service) PurgeDue(ctx context.Context) error {
+	uids, err := s.userRepo.GetPurgeDue(ctx, s.now())
+	if err != nil {
+		internal.Logger.Errorw("error while fetching accounts to purge", "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	for _, uid := range uids {
+		purge, err := s.plan(ctx, uid)
+		if err != nil {
+			internal.Logger.Errorw("account can not be purged", "uid", uid, "err", err)
+			continue
+		}
+
+		_ = s.purge(ctx, uid, purge)
+	}
+
+	return nil
+}
+
+// Run периодическое удаление учетных записей с истекшим сроком отмены до отмены контекста
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.PurgeDue(ctx)
+		}
+	}
+}
+
+// plan что удаляется вместе с пользователем uid: организации без других принявших приглашение участников удаляются
+// вместе с хранилищем, а из остальных он исключается только через organization.Service.Remove, который меняет ключ
+// хранилища: пользователь знает текущий ключ, и без его смены хранилище оставалось бы открыто для него
+func (s *Service) plan(ctx context.Context, uid uint64) (domain2.AccountPurge, error) {
+	purge := domain2.AccountPurge{UIDs: []uint64{uid}}
+
+	memberships, err := s.orgRepo.GetByUser(ctx, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching organizations", "uid", uid, "err", err)
+		return domain2.AccountPurge{}, domain2.ErrInternalServerError
+	}
+
+	for _, m := range memberships {
+		if !m.Member.Accepted {
+			continue
+		}
+
+		members, err := s.orgRepo.GetMembers(ctx, m.Org.ID)
+		if err != nil {
+			internal.Logger.Errorw("error while fetching organization members", "org", m.Org.ID, "err", err)
+			return domain2.AccountPurge{}, domain2.ErrInternalServerError
+		}
+
+		others, owners := 0, 0
+		for _, member := range members {
+			if member.UID == uid || !member.Accepted {
+				continue
+			}
+
+			others++
+			if member.Role == domain2.OrgRoleOwner {
+				owners++
+			}
+		}
+
+		switch {
+		case others == 0:
+			purge.OrgIDs = append(purge.OrgIDs, m.Org.ID)
+			purge.UIDs = append(purge.UIDs, m.Org.VaultUID)
+		case m.Member.Role == domain2.OrgRoleOwner && owners == 0:
+			return domain2.AccountPurge{}, domain2.ErrLastOwner
+		default:
+			return domain2.AccountPurge{}, domain2.ErrOrgMember
+		}
+	}
+
+	return purge, nil
+}
+
+// purge удаление учетной записи uid в одной транзакции, файлы удаляются с диска только после ее фиксации
+func (s *Service) purge(ctx context.Context, uid uint64, purge domain2.AccountPurge) error {
+	paths, err := s.repo.Purge(ctx, purge)
+	if err != nil {
+		internal.Logger.Errorw("error while purging account", "uid", uid, "err", err)
+		s.record(ctx, uid, domain2.AuditPurgeAccount, domain2.ErrInternalServerError)
+		return domain2.ErrInternalServerError
+	}
+
+	s.blobs.DeleteBlobs(ctx, paths)
+	s.record(ctx, uid, domain2.AuditPurgeAccount, nil)
+
+	return nil
+}
+
+func (s *Service) record(ctx context.Context, uid uint64, action domain2.AuditAction, err error) {
+	if s.Audit != nil {
+		s.Audit.Record(ctx, uid, action, 0, err)
+	}
+}
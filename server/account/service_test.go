@@ -0,0 +1,140 @@
+package account
+
+import (
+	"context"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/user"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBlobs struct {
+	paths []string
+}
+
+func (b *testBlobs) DeleteBlobs(_ context.Context, paths []string) {
+	b.paths = append(b.paths, paths...)
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	users := memory.NewUserRepository()
+	data := memory.NewDataRepository()
+	files := memory.NewFileRepository()
+	folders := memory.NewFolderRepository()
+	orgs := memory.NewOrganizationRepository(users, data, folders)
+	repo := memory.NewAccountRepository(users, data, files, folders, orgs, memory.NewEmergencyRepository(users), memory.NewSecretRepository())
+	blobs := &testBlobs{}
+	service := NewService(repo, users, orgs, blobs)
+
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	hash, err := user.HashPassword("secret")
+	require.NoError(t, err)
+
+	uids := make(map[string]uint64)
+	for _, login := range []string{"alice", "bob"} {
+		uid, err := users.Store(ctx, domain2.User{Login: login, Password: hash})
+		require.NoError(t, err)
+		uids[login] = uid
+	}
+
+	file := &domain2.File{Name: "photo", Path: "/tmp/photo"}
+	require.NoError(t, files.Insert(ctx, file))
+	record := &domain2.Data{Name: "photo", UID: uids["alice"], FileID: &file.ID}
+	require.NoError(t, data.Insert(ctx, record))
+	require.NoError(t, data.Share(ctx, domain2.DataShare{DataID: record.ID, UID: uids["bob"]}))
+
+	// в первой организации alice единственный участник, во второй bob принял приглашение
+	solo := &domain2.Organization{Name: "solo"}
+	require.NoError(t, orgs.Insert(ctx, solo, domain2.OrgMember{UID: uids["alice"], Role: domain2.OrgRoleOwner, Accepted: true}))
+	team := &domain2.Organization{Name: "team"}
+	require.NoError(t, orgs.Insert(ctx, team, domain2.OrgMember{UID: uids["alice"], Role: domain2.OrgRoleOwner, Accepted: true}))
+	require.NoError(t, orgs.SaveMember(ctx, domain2.OrgMember{OrgID: team.ID, UID: uids["bob"], Role: domain2.OrgRoleAdmin, Accepted: true}))
+
+	_, err = service.Delete(ctx, uids["alice"], "wrong", 0)
+	assert.ErrorIs(t, err, domain2.ErrWrongPassword)
+
+	_, err = service.Delete(ctx, uids["alice"], "secret", 0)
+	assert.ErrorIs(t, err, domain2.ErrLastOwner)
+
+	require.NoError(t, orgs.SaveMember(ctx, domain2.OrgMember{OrgID: team.ID, UID: uids["bob"], Role: domain2.OrgRoleOwner, Accepted: true}))
+
+	// alice знает ключ хранилища team, поэтому сначала bob исключает ее со сменой ключа
+	_, err = service.Delete(ctx, uids["alice"], "secret", 7*24*time.Hour)
+	assert.ErrorIs(t, err, domain2.ErrOrgMember)
+	state, err := users.GetState(ctx, uids["alice"])
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.True(t, state.PurgeAt.IsZero())
+
+	require.NoError(t, orgs.Rotate(ctx, domain2.OrgRotation{OrgID: team.ID, VaultUID: team.VaultUID, RemoveUID: uids["alice"], KeyVersion: team.KeyVersion + 1, Keys: map[uint64]string{uids["bob"]: "key"}}))
+
+	t.Run("grace", func(t *testing.T) {
+		purgeAt, err := service.Delete(ctx, uids["alice"], "secret", 7*24*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(7*24*time.Hour), purgeAt)
+
+		state, err := users.GetState(ctx, uids["alice"])
+		require.NoError(t, err)
+		assert.Equal(t, domain2.AccountState{PurgeAt: purgeAt, SessionsAfter: now}, *state)
+
+		// до истечения срока ничего не удаляется
+		require.NoError(t, service.PurgeDue(ctx))
+		u, err := users.GetByID(ctx, uids["alice"])
+		require.NoError(t, err)
+		assert.Equal(t, uids["alice"], u.ID)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		now = now.Add(8 * 24 * time.Hour)
+		require.NoError(t, service.PurgeDue(ctx))
+
+		state, err := users.GetState(ctx, uids["alice"])
+		require.NoError(t, err)
+		assert.Nil(t, state)
+
+		row, err := data.Get(ctx, record.ID)
+		require.NoError(t, err)
+		assert.Nil(t, row)
+
+		f, err := files.Get(ctx, file.ID)
+		require.NoError(t, err)
+		assert.Nil(t, f)
+		assert.Equal(t, []string{"/tmp/photo"}, blobs.paths)
+
+		// организация без других участников удалена вместе с хранилищем, вторая остается
+		org, err := orgs.Get(ctx, solo.ID)
+		require.NoError(t, err)
+		assert.Nil(t, org)
+		vault, err := users.GetByID(ctx, solo.VaultUID)
+		require.NoError(t, err)
+		assert.Zero(t, vault.ID)
+
+		members, err := orgs.GetMembers(ctx, team.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.Equal(t, uids["bob"], members[0].UID)
+	})
+
+	t.Run("immediately", func(t *testing.T) {
+		purgeAt, err := service.Delete(ctx, uids["bob"], "secret", 0)
+		require.NoError(t, err)
+		assert.True(t, purgeAt.IsZero())
+
+		org, err := orgs.Get(ctx, team.ID)
+		require.NoError(t, err)
+		assert.Nil(t, org)
+
+		_, err = service.Delete(ctx, uids["bob"], "secret", 0)
+		assert.ErrorIs(t, err, domain2.ErrUserNotFound)
+	})
+}
@@ -0,0 +1,302 @@
+// Package audit пакет журнала аудита: кто, откуда и что делал с учетной записью и записями
+package audit
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/user"
+	"net"
+	"time"
+
+	"google.golang.org/grpc/peer"
+)
+
+// errNoSigner без ключа сервера отметки журнала нельзя подписать и проверить
+var errNoSigner = errors.New("server key is required to sign and verify audit checkpoints")
+
+// verifyPageSize число событий, читаемых за один запрос при проверке журнала
+const verifyPageSize = 1000
+
+type Service struct {
+	repo     Repository
+	userRepo UserRepository
+	// signer ключ сервера для подписи отметок журнала, nil - отметки не создаются
+	signer Signer
+	// now текущее время, подменяется в тестах
+	now func() time.Time
+}
+
+// Repository интерфейс для описания методов журнала аудита
+type Repository interface {
+	Insert(ctx context.Context, event *domain2.AuditEvent) error
+	GetList(ctx context.Context, uid uint64, filter domain2.AuditFilter) ([]domain2.AuditEvent, error)
+	Scan(ctx context.Context, afterID uint64, limit int) ([]domain2.AuditEvent, error)
+	Head(ctx context.Context) (domain2.AuditCheckpoint, error)
+	InsertCheckpoint(ctx context.Context, checkpoint *domain2.AuditCheckpoint) error
+	GetCheckpoints(ctx context.Context) ([]domain2.AuditCheckpoint, error)
+}
+
+// Signer подпись отметок журнала ключом сервера и ее проверка
+type Signer interface {
+	Sign(data []byte) ([]byte, error)
+	Verify(data, signature []byte) error
+}
+
+// UserRepository интерфейс для описания методов хранилища пользователей, нужных для журнала
+type UserRepository interface {
+	GetByID(ctx context.Context, id uint64) (domain2.User, error)
+}
+
+func NewService(repo Repository, userRepo UserRepository, signer Signer) *Service {
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		signer:   signer,
+		now:      time.Now,
+	}
+}
+
+// Record запись события с данными учетной записи uid в журнал
+// исполнитель и его IP берутся из контекста запроса; ошибка записи только логируется и не прерывает действие
+func (s *Service) Record(ctx context.Context, uid uint64, action domain2.AuditAction, dataID uint64, err error) {
+	if uid == 0 {
+		return
+	}
+
+	event := domain2.AuditEvent{
+		UID:       uid,
+		ActorUID:  actorUID(ctx, uid),
+		IP:        peerIP(ctx),
+		Action:    action,
+		DataID:    dataID,
+		Outcome:   outcome(err),
+		CreatedAt: s.now(),
+	}
+
+	// логин сохраняется в событии, чтобы журнал оставался читаемым после удаления исполнителя
+	actor, uerr := s.userRepo.GetByID(ctx, event.ActorUID)
+	if uerr != nil {
+		internal.Logger.Errorw("error while fetching audit actor", "uid", event.ActorUID, "err", uerr)
+	}
+	event.Actor = actor.Login
+
+	if ierr := s.repo.Insert(ctx, &event); ierr != nil {
+		internal.Logger.Errorw("error while inserting audit event", "uid", uid, "action", action, "err", ierr)
+	}
+}
+
+// List страница журнала пользователя uid: события его учетной записи и его действия в других хранилищах
+func (s *Service) List(ctx context.Context, uid uint64, filter domain2.AuditFilter) ([]domain2.AuditEvent, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = domain2.AuditDefaultPageSize
+	}
+
+	if filter.Limit > domain2.AuditMaxPageSize {
+		filter.Limit = domain2.AuditMaxPageSize
+	}
+
+	list, err := s.repo.GetList(ctx, uid, filter)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching audit events", "uid", uid, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	return list, nil
+}
+
+// Checkpoint подпись отметки последнего события журнала, если после предыдущей отметки были новые события
+// отметка фиксирует хеш цепочки: изменить или удалить события до нее, не зная закрытого ключа сервера, незаметно нельзя
+func (s *Service) Checkpoint(ctx context.Context) error {
+	if s.signer == nil {
+		return errNoSigner
+	}
+
+	head, err := s.repo.Head(ctx)
+	if err != nil {
+		return err
+	}
+
+	if head.EventID == 0 || head.Hash == "" {
+		return nil
+	}
+
+	list, err := s.repo.GetCheckpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(list) > 0 && list[len(list)-1].EventID == head.EventID {
+		return nil
+	}
+
+	head.CreatedAt = s.now()
+
+	signature, err := s.signer.Sign(head.SignedData())
+	if err != nil {
+		return err
+	}
+
+	head.Signature = base64.StdEncoding.EncodeToString(signature)
+
+	return s.repo.InsertCheckpoint(ctx, &head)
+}
+
+// Run периодическая подпись отметок журнала до отмены ctx
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	if s.signer == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Checkpoint(ctx); err != nil {
+				internal.Logger.Errorw("error while signing audit checkpoint", "err", err)
+			}
+		}
+	}
+}
+
+// Verify проверка целостности журнала: цепочки хешей и подписанных отметок
+// измененное событие не совпадает со своим хешем, удаленное или переставленное - рвет цепочку,
+// пересчитанная цепочка или удаленный конец журнала не совпадают с отметками
+// при нарушении возвращается ErrAuditTampered с описанием первого найденного нарушения
+func (s *Service) Verify(ctx context.Context) (domain2.AuditVerifyReport, error) {
+	var report domain2.AuditVerifyReport
+
+	checkpoints, err := s.repo.GetCheckpoints(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	if len(checkpoints) > 0 && s.signer == nil {
+		return report, errNoSigner
+	}
+
+	for _, c := range checkpoints {
+		signature, err := base64.StdEncoding.DecodeString(c.Signature)
+		if err == nil {
+			err = s.signer.Verify(c.SignedData(), signature)
+		}
+
+		if err != nil {
+			return report, fmt.Errorf("%w: checkpoint %d has invalid signature", domain2.ErrAuditTampered, c.ID)
+		}
+
+		report.Checkpoints++
+		report.LastCheckpoint = &c.CreatedAt
+	}
+
+	// отметки подписываются по мере роста журнала, поэтому упорядочены по событию
+	var (
+		next    int
+		lastID  uint64
+		prev    string
+		chained bool
+	)
+
+	for {
+		page, err := s.repo.Scan(ctx, lastID, verifyPageSize)
+		if err != nil {
+			return report, err
+		}
+
+		for _, e := range page {
+			if next < len(checkpoints) && checkpoints[next].EventID < e.ID {
+				return report, fmt.Errorf("%w: event %d from checkpoint %d was removed", domain2.ErrAuditTampered, checkpoints[next].EventID, checkpoints[next].ID)
+			}
+
+			report.Events++
+
+			switch {
+			case !chained && e.Hash == "" && e.PrevHash == "":
+				report.Legacy++
+			case e.PrevHash != prev:
+				return report, fmt.Errorf("%w: event %d does not follow the previous one, events were removed or reordered", domain2.ErrAuditTampered, e.ID)
+			case e.ChainHash() != e.Hash:
+				return report, fmt.Errorf("%w: event %d was modified", domain2.ErrAuditTampered, e.ID)
+			default:
+				chained = true
+			}
+
+			prev = e.Hash
+
+			if next < len(checkpoints) && checkpoints[next].EventID == e.ID {
+				if c := checkpoints[next]; c.Hash != e.Hash || c.Events != report.Events {
+					return report, fmt.Errorf("%w: events up to %d do not match checkpoint %d", domain2.ErrAuditTampered, e.ID, c.ID)
+				}
+
+				next++
+			}
+
+			lastID = e.ID
+		}
+
+		if len(page) < verifyPageSize {
+			break
+		}
+	}
+
+	if next < len(checkpoints) {
+		return report, fmt.Errorf("%w: events after %d were removed, checkpoint %d covers event %d", domain2.ErrAuditTampered, lastID, checkpoints[next].ID, checkpoints[next].EventID)
+	}
+
+	return report, nil
+}
+
+// actorUID исполнитель запроса: в хранилище организации и при экстренном доступе
+// ИД пользователя в контексте заменен на ИД хранилища, исходный сохраняется отдельно
+func actorUID(ctx context.Context, uid uint64) uint64 {
+	if actor, ok := ctx.Value(user.ContextActorIDKey{}).(uint64); ok && actor != 0 {
+		return actor
+	}
+
+	if actor, ok := ctx.Value(user.ContextUserIDKey{}).(uint64); ok && actor != 0 {
+		return actor
+	}
+
+	return uid
+}
+
+// peerIP адрес клиента gRPC без порта
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+// outcome результат действия по ошибке сервиса: неверные учетные данные и запрет доступа - отказ
+func outcome(err error) domain2.AuditOutcome {
+	switch {
+	case err == nil:
+		return domain2.AuditSuccess
+	case errors.Is(err, domain2.ErrUserNotFound),
+		errors.Is(err, domain2.ErrDataNotFound),
+		errors.Is(err, domain2.ErrDataReadOnly),
+		errors.Is(err, domain2.ErrBadFileID),
+		errors.Is(err, domain2.ErrWrongPassword),
+		errors.Is(err, domain2.ErrAccountDeleting),
+		errors.Is(err, domain2.ErrAccountDeleted),
+		errors.Is(err, domain2.ErrSessionRevoked):
+		return domain2.AuditDenied
+	default:
+		return domain2.AuditFailure
+	}
+}
@@ -0,0 +1,243 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/internal"
+	"gophkeeper/internal/crypto"
+	"gophkeeper/internal/server/repository/memory"
+	"gophkeeper/server/data"
+	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/user"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+)
+
+func TestService(t *testing.T) {
+	internal.InitLogger()
+
+	users := memory.NewUserRepository()
+	ctx := context.Background()
+
+	alice, err := users.Store(ctx, domain2.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+	bob, err := users.Store(ctx, domain2.User{Login: "bob", Password: "hash"})
+	require.NoError(t, err)
+
+	service := NewService(memory.NewAuditRepository(), users, nil)
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	peerCtx := peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 53211}})
+
+	service.Record(peerCtx, alice, domain2.AuditLogin, 0, domain2.ErrUserNotFound)
+	service.Record(context.WithValue(peerCtx, user.ContextUserIDKey{}, alice), alice, domain2.AuditRead, 3, nil)
+	service.Record(ctx, alice, domain2.AuditSave, 3, errors.New("db is down"))
+
+	// bob работает в хранилище alice по экстренному доступу
+	vaultCtx := context.WithValue(context.WithValue(ctx, user.ContextActorIDKey{}, bob), user.ContextUserIDKey{}, alice)
+	service.Record(vaultCtx, alice, domain2.AuditRead, 4, nil)
+
+	list, err := service.List(ctx, alice, domain2.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+
+	want := domain2.AuditEvent{ID: 4, UID: alice, ActorUID: bob, Actor: "bob", Action: domain2.AuditRead, DataID: 4, Outcome: domain2.AuditSuccess, CreatedAt: now, PrevHash: list[1].Hash}
+	want.Hash = want.ChainHash()
+	assert.Equal(t, want, list[0])
+	assert.Equal(t, domain2.AuditFailure, list[1].Outcome)
+	assert.Equal(t, "192.0.2.10", list[2].IP)
+	want = domain2.AuditEvent{ID: 1, UID: alice, ActorUID: alice, Actor: "alice", IP: "192.0.2.10", Action: domain2.AuditLogin, Outcome: domain2.AuditDenied, CreatedAt: now}
+	want.Hash = want.ChainHash()
+	assert.Equal(t, want, list[3])
+
+	// в журнал bob попадают его действия в чужом хранилище
+	list, err = service.List(ctx, bob, domain2.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, alice, list[0].UID)
+
+	list, err = service.List(ctx, alice, domain2.AuditFilter{Outcome: domain2.AuditDenied, Limit: domain2.AuditMaxPageSize + 1})
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
+
+func TestService_DataEvents(t *testing.T) {
+	internal.InitLogger()
+
+	users := memory.NewUserRepository()
+	ctx := context.Background()
+
+	alice, err := users.Store(ctx, domain2.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+	bob, err := users.Store(ctx, domain2.User{Login: "bob", Password: "hash"})
+	require.NoError(t, err)
+
+	service := NewService(memory.NewAuditRepository(), users, nil)
+	dataService := data.NewService(memory.NewDataRepository(), memory.NewFileRepository(), users)
+	dataService.Audit = service
+
+	aliceCtx := context.WithValue(ctx, user.ContextUserIDKey{}, alice)
+	record := &domain2.Data{Name: "mail", UID: alice}
+	require.NoError(t, dataService.UpsertData(aliceCtx, record))
+
+	_, err = dataService.Get(aliceCtx, record.ID, alice)
+	require.NoError(t, err)
+
+	// чужая запись не найдена - отказ в журнале bob
+	bobCtx := context.WithValue(ctx, user.ContextUserIDKey{}, bob)
+	_, err = dataService.Get(bobCtx, record.ID, bob)
+	assert.ErrorIs(t, err, domain2.ErrDataNotFound)
+
+	list, err := service.List(ctx, alice, domain2.AuditFilter{DataID: record.ID})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, domain2.AuditRead, list[0].Action)
+	assert.Equal(t, domain2.AuditSave, list[1].Action)
+
+	list, err = service.List(ctx, bob, domain2.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, domain2.AuditDenied, list[0].Outcome)
+}
+
+// tamperedRepo журнал, события и отметки которого изменены в обход репозитория, как это может сделать администратор базы
+type tamperedRepo struct {
+	*memory.AuditRepository
+	events      func([]domain2.AuditEvent) []domain2.AuditEvent
+	checkpoints func([]domain2.AuditCheckpoint) []domain2.AuditCheckpoint
+}
+
+func (r tamperedRepo) Scan(ctx context.Context, afterID uint64, limit int) ([]domain2.AuditEvent, error) {
+	page, err := r.AuditRepository.Scan(ctx, afterID, limit)
+	if err != nil || r.events == nil {
+		return page, err
+	}
+
+	return r.events(page), nil
+}
+
+func (r tamperedRepo) GetCheckpoints(ctx context.Context) ([]domain2.AuditCheckpoint, error) {
+	list, err := r.AuditRepository.GetCheckpoints(ctx)
+	if err != nil || r.checkpoints == nil {
+		return list, err
+	}
+
+	return r.checkpoints(list), nil
+}
+
+func TestService_Verify(t *testing.T) {
+	internal.InitLogger()
+
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	repo := memory.NewAuditRepository()
+
+	signer, err := crypto.NewCipher("../../internal/crypto")
+	require.NoError(t, err)
+
+	service := NewService(repo, users, signer)
+
+	record := func(n int) {
+		for i := 0; i < n; i++ {
+			service.Record(ctx, 1, domain2.AuditRead, uint64(i+1), nil)
+		}
+	}
+
+	record(5)
+	require.NoError(t, service.Checkpoint(ctx))
+	record(2)
+	require.NoError(t, service.Checkpoint(ctx))
+	// без новых событий отметка не добавляется
+	require.NoError(t, service.Checkpoint(ctx))
+
+	report, err := service.Verify(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), report.Events)
+	assert.Equal(t, int64(2), report.Checkpoints)
+	assert.NotNil(t, report.LastCheckpoint)
+
+	// rechain пересчет цепочки после события с индексом from, как сделал бы злоумышленник без ключа сервера
+	rechain := func(page []domain2.AuditEvent, from int) []domain2.AuditEvent {
+		for i := from; i < len(page); i++ {
+			if i > 0 {
+				page[i].PrevHash = page[i-1].Hash
+			}
+			page[i].Hash = page[i].ChainHash()
+		}
+
+		return page
+	}
+
+	tests := []struct {
+		name        string
+		events      func([]domain2.AuditEvent) []domain2.AuditEvent
+		checkpoints func([]domain2.AuditCheckpoint) []domain2.AuditCheckpoint
+		want        string
+	}{
+		{
+			name: "modified",
+			events: func(page []domain2.AuditEvent) []domain2.AuditEvent {
+				page[2].IP = "198.51.100.7"
+				return page
+			},
+			want: "event 3 was modified",
+		},
+		{
+			name: "removed",
+			events: func(page []domain2.AuditEvent) []domain2.AuditEvent {
+				return append(page[:2], page[3:]...)
+			},
+			want: "event 4 does not follow the previous one",
+		},
+		{
+			name: "rechained",
+			events: func(page []domain2.AuditEvent) []domain2.AuditEvent {
+				page[2].Outcome = domain2.AuditSuccess
+				page[2].DataID = 42
+				return rechain(page, 2)
+			},
+			want: "events up to 5 do not match checkpoint 1",
+		},
+		{
+			name: "removed checkpoint event",
+			events: func(page []domain2.AuditEvent) []domain2.AuditEvent {
+				return rechain(append(page[:4], page[5:]...), 4)
+			},
+			want: "event 5 from checkpoint 1 was removed",
+		},
+		{
+			name: "truncated",
+			events: func(page []domain2.AuditEvent) []domain2.AuditEvent {
+				return page[:5]
+			},
+			want: "events after 5 were removed",
+		},
+		{
+			name: "forged checkpoint",
+			checkpoints: func(list []domain2.AuditCheckpoint) []domain2.AuditCheckpoint {
+				list[1].Events = 6
+				return list
+			},
+			want: "checkpoint 2 has invalid signature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := NewService(tamperedRepo{AuditRepository: repo, events: tt.events, checkpoints: tt.checkpoints}, users, signer)
+
+			_, err := tampered.Verify(ctx)
+			assert.ErrorIs(t, err, domain2.ErrAuditTampered)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	_, err = NewService(repo, users, nil).Verify(ctx)
+	assert.ErrorIs(t, err, errNoSigner)
+}
@@ -0,0 +1,46 @@
+package data
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	domain2 "gophkeeper/server/domain"
+)
+
+type pageToken struct {
+	Sort   domain2.DataSort       `json:"s"`
+	Cursor domain2.DataListCursor `json:"c"`
+}
+
+// EncodePageToken получить непрозрачный для клиента токен следующей страницы
+func EncodePageToken(sort domain2.DataSort, cursor domain2.DataListCursor) (string, error) {
+	raw, err := json.Marshal(pageToken{Sort: sort, Cursor: cursor})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodePageToken получить курсор из токена страницы
+// токен, полученный при другой сортировке, считается некорректным
+func DecodePageToken(token string, sort domain2.DataSort) (*domain2.DataListCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, domain2.ErrBadPageToken
+	}
+
+	var pt pageToken
+	if err = json.Unmarshal(raw, &pt); err != nil {
+		return nil, domain2.ErrBadPageToken
+	}
+
+	if pt.Sort != sort {
+		return nil, domain2.ErrBadPageToken
+	}
+
+	return &pt.Cursor, nil
+}
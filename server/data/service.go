@@ -3,15 +3,21 @@ package data
 
 import (
 	"context"
+	"errors"
 	"gophkeeper/internal"
 	domain2 "gophkeeper/server/domain"
 	"gophkeeper/server/file"
 	"path/filepath"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type Service struct {
 	DataRepo Repository
 	FileRepo FileRepository
+	UserRepo UserRepository
+	// Audit журнал аудита, nil - события не записываются
+	Audit Auditor
 }
 
 // Repository интерфейс для описания методов хранилища данных
@@ -21,9 +27,14 @@ type Repository interface {
 	Get(ctx context.Context, id uint64) (*domain2.Data, error)
 	GetByUser(ctx context.Context, id uint64, uid uint64) (*domain2.Data, error)
 	GetByNameAndUserID(ctx context.Context, uid uint64, name string) (uint64, error)
+	GetByNameHashAndUserID(ctx context.Context, uid uint64, nameHash string) (uint64, error)
 	SetFile(ctx context.Context, data domain2.Data) error
-	GetList(ctx context.Context, uid uint64) ([]domain2.DataName, error)
+	GetList(ctx context.Context, uid uint64, filter domain2.DataListFilter) ([]domain2.DataName, error)
+	UnsetFolder(ctx context.Context, uid uint64, folderIDs []uint64) error
 	Delete(ctx context.Context, id uint64) error
+	GetShare(ctx context.Context, dataID, uid uint64) (*domain2.DataShare, error)
+	Share(ctx context.Context, share domain2.DataShare) error
+	Unshare(ctx context.Context, dataID, uid uint64) error
 }
 
 // FileRepository интерфейс для описания методов файлового хранилища
@@ -31,17 +42,38 @@ type FileRepository interface {
 	Get(ctx context.Context, id uint64) (*domain2.File, error)
 }
 
-func NewService(d Repository, fileRepo FileRepository) *Service {
+// UserRepository интерфейс для описания методов хранилища пользователей, нужных для обмена записями
+type UserRepository interface {
+	GetByLogin(ctx context.Context, login string) (domain2.User, error)
+	GetByID(ctx context.Context, id uint64) (domain2.User, error)
+	GetKeys(ctx context.Context, id uint64) (domain2.UserKeys, error)
+}
+
+// Auditor интерфейс записи событий в журнал аудита
+type Auditor interface {
+	Record(ctx context.Context, uid uint64, action domain2.AuditAction, dataID uint64, err error)
+}
+
+func NewService(d Repository, fileRepo FileRepository, userRepo UserRepository) *Service {
 	return &Service{
 		DataRepo: d,
 		FileRepo: fileRepo,
+		UserRepo: userRepo,
 	}
 }
 
 // UpsertData добавление или сохраненение (если есть ИД) данных
-func (s Service) UpsertData(ctx context.Context, data *domain2.Data) error {
+// событие записывается в журнал владельца записи, для чужой записи - после проверки доступа
+func (s Service) UpsertData(ctx context.Context, data *domain2.Data) (err error) {
+	defer func() {
+		s.record(ctx, data.UID, domain2.AuditSave, data.ID, err)
+	}()
+
+	data.Tags = NormalizeTags(data.Tags)
+
 	if data.ID == 0 {
-		uniq, err := s.checkName(ctx, data, nil)
+		var uniq bool
+		uniq, err = s.checkName(ctx, data, nil)
 		if err != nil {
 			internal.Logger.Errorw("error while checking name", "err", err)
 			return domain2.ErrCheckDataName
@@ -59,18 +91,34 @@ func (s Service) UpsertData(ctx context.Context, data *domain2.Data) error {
 			return domain2.ErrDataInsert
 		}
 	} else {
-		oldRow, err := s.DataRepo.Get(ctx, data.ID)
+		var oldRow *domain2.Data
+		oldRow, err = s.DataRepo.Get(ctx, data.ID)
 		if err != nil {
 			internal.Logger.Errorw("error while fetching data", "id", data.ID, "err", err)
 			return domain2.ErrInternalServerError
 		}
 
+		if oldRow == nil {
+			return domain2.ErrDataNotFound
+		}
+
+		if oldRow.UID != data.UID {
+			if err = s.checkWrite(ctx, data.ID, data.UID); err != nil {
+				return err
+			}
+
+			// название, папка, теги и ключ записи принадлежат владельцу, получатель меняет только содержимое
+			data.UID, data.Name, data.NameHash = oldRow.UID, oldRow.Name, oldRow.NameHash
+			data.FolderID, data.Tags, data.RecordKey = oldRow.FolderID, oldRow.Tags, oldRow.RecordKey
+		}
+
 		err = s.updateVersion(oldRow, data)
 		if err != nil {
 			return err
 		}
 
-		uniq, err := s.checkName(ctx, data, oldRow)
+		var uniq bool
+		uniq, err = s.checkName(ctx, data, oldRow)
 		if err != nil {
 			internal.Logger.Errorw("error while checking name", "err", err)
 			return domain2.ErrCheckDataName
@@ -90,18 +138,31 @@ func (s Service) UpsertData(ctx context.Context, data *domain2.Data) error {
 	return nil
 }
 
-// CheckUploadFileData проверка что файл принадлежит данному пользователя
-func (s Service) CheckUploadFileData(ctx context.Context, data domain2.Data) error {
+// CheckUploadFileData проверка что файл принадлежит данному пользователя или пользователь может изменять запись
+// отказ записывается в журнал, успешная загрузка - после сохранения файла
+func (s Service) CheckUploadFileData(ctx context.Context, data domain2.Data) (err error) {
+	defer func() {
+		if err != nil {
+			s.record(ctx, data.UID, domain2.AuditUpload, data.ID, err)
+		}
+	}()
+
 	d, err := s.DataRepo.Get(ctx, data.ID)
 	if err != nil {
 		internal.Logger.Errorw("error while fetching data", "id", data.ID, "err", err)
 		return domain2.ErrInternalServerError
 	}
 
-	if d == nil || d.UID != data.UID {
+	if d == nil {
 		return domain2.ErrDataNotFound
 	}
 
+	if d.UID != data.UID {
+		if err = s.checkWrite(ctx, data.ID, data.UID); err != nil {
+			return err
+		}
+	}
+
 	if data.FileID != nil && *data.FileID != 0 {
 		if d.FileID == nil || *d.FileID != *data.FileID {
 			return domain2.ErrBadFileID
@@ -117,14 +178,19 @@ func (s Service) CheckUploadFileData(ctx context.Context, data domain2.Data) err
 }
 
 // SaveDataFile сохранить файл в базу данных
-func (s Service) SaveDataFile(ctx context.Context, data *domain2.Data, filePath string, f file.Service) error {
+func (s Service) SaveDataFile(ctx context.Context, data *domain2.Data, filePath string, f file.Service) (err error) {
+	owner := data.UID
+	defer func() {
+		s.record(ctx, owner, domain2.AuditUpload, data.ID, err)
+	}()
+
 	dFile := domain2.File{
 		Name: filepath.Base(filePath),
 		Path: filePath,
 		ID:   *data.FileID,
 	}
 
-	if err := f.Save(ctx, &dFile); err != nil {
+	if err = f.Save(ctx, &dFile); err != nil {
 		return err
 	}
 
@@ -136,6 +202,8 @@ func (s Service) SaveDataFile(ctx context.Context, data *domain2.Data, filePath
 		return domain2.ErrInternalServerError
 	}
 
+	owner = ownerUID(oldRow, owner)
+
 	err = s.updateVersion(oldRow, data)
 	if err != nil {
 		return err
@@ -150,19 +218,69 @@ func (s Service) SaveDataFile(ctx context.Context, data *domain2.Data, filePath
 	return nil
 }
 
-// GetList получить список данных из базы данных
-func (s Service) GetList(ctx context.Context, uid uint64) (list []domain2.DataName, err error) {
-	list, err = s.DataRepo.GetList(ctx, uid)
+// GetList получить страницу списка данных из базы данных
+// Если после страницы есть еще данные, возвращается курсор для получения следующей страницы
+func (s Service) GetList(ctx context.Context, uid uint64, filter domain2.DataListFilter) (list []domain2.DataName, next *domain2.DataListCursor, err error) {
+	pageSize := filter.Limit
+	if pageSize <= 0 {
+		pageSize = domain2.DataListDefaultPageSize
+	}
+
+	if pageSize > domain2.DataListMaxPageSize {
+		pageSize = domain2.DataListMaxPageSize
+	}
+
+	// запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	filter.Limit = pageSize + 1
+
+	list, err = s.DataRepo.GetList(ctx, uid, filter)
 	if err != nil {
 		internal.Logger.Errorw("error while fetching data", "uid", uid, "err", err)
-		return list, domain2.ErrInternalServerError
+		return list, nil, domain2.ErrInternalServerError
+	}
+
+	if len(list) > pageSize {
+		list = list[:pageSize]
+		last := list[len(list)-1]
+		next = &domain2.DataListCursor{Name: last.Name, ID: last.ID}
+	}
+
+	owners := make(map[uint64]string)
+
+	for _, d := range list {
+		if d.Share == nil {
+			continue
+		}
+
+		if _, ok := owners[d.UID]; !ok {
+			if owners[d.UID], err = s.ownerLogin(ctx, d.UID); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		d.Share.Owner = owners[d.UID]
 	}
 
 	return
 }
 
-// Get получить запись из базы данных
-func (s Service) Get(ctx context.Context, dataID uint64, uid uint64) (data *domain2.Data, err error) {
+// Get получить запись из базы данных, чтение записывается в журнал аудита
+func (s Service) Get(ctx context.Context, dataID uint64, uid uint64) (*domain2.Data, error) {
+	data, err := s.get(ctx, dataID, uid)
+	s.record(ctx, ownerUID(data, uid), domain2.AuditRead, dataID, err)
+
+	return data, err
+}
+
+// Download получить запись для скачивания ее файла, скачивание записывается в журнал аудита
+func (s Service) Download(ctx context.Context, dataID uint64, uid uint64) (*domain2.Data, error) {
+	data, err := s.get(ctx, dataID, uid)
+	s.record(ctx, ownerUID(data, uid), domain2.AuditDownload, dataID, err)
+
+	return data, err
+}
+
+func (s Service) get(ctx context.Context, dataID uint64, uid uint64) (data *domain2.Data, err error) {
 	data, err = s.DataRepo.GetByUser(ctx, dataID, uid)
 	if err != nil {
 		internal.Logger.Errorw("error while fetching data", "id", dataID, "err", err)
@@ -173,11 +291,19 @@ func (s Service) Get(ctx context.Context, dataID uint64, uid uint64) (data *doma
 		return data, domain2.ErrDataNotFound
 	}
 
+	if data.Share != nil {
+		data.Share.Owner, err = s.ownerLogin(ctx, data.UID)
+	}
+
 	return
 }
 
 // Delete удалить запись из базы данных
-func (s Service) Delete(ctx context.Context, dataID, uid uint64, fs file.Service) error {
+func (s Service) Delete(ctx context.Context, dataID, uid uint64, fs file.Service) (err error) {
+	defer func() {
+		s.record(ctx, uid, domain2.AuditDelete, dataID, err)
+	}()
+
 	data, err := s.DataRepo.GetByUser(ctx, dataID, uid)
 	if err != nil {
 		internal.Logger.Errorw("error while fetching data", "id", dataID, "err", err)
@@ -188,6 +314,16 @@ func (s Service) Delete(ctx context.Context, dataID, uid uint64, fs file.Service
 		return domain2.ErrDataNotFound
 	}
 
+	// получатель удаляет из своего списка только доступ к записи
+	if data.Share != nil {
+		if err = s.DataRepo.Unshare(ctx, dataID, uid); err != nil {
+			internal.Logger.Errorw("error while deleting share", "id", dataID, "err", err)
+			return domain2.ErrInternalServerError
+		}
+
+		return nil
+	}
+
 	err = s.DataRepo.Delete(ctx, dataID)
 	if err != nil {
 		internal.Logger.Errorw("error while deleting data", "id", dataID, "err", err)
@@ -205,6 +341,143 @@ func (s Service) Delete(ctx context.Context, dataID, uid uint64, fs file.Service
 	return nil
 }
 
+// Share предоставить пользователю с логином login доступ к записи владельца uid
+// ключ записи в share должен быть зашифрован открытым ключом получателя
+func (s Service) Share(ctx context.Context, uid uint64, login string, share domain2.DataShare) (err error) {
+	defer func() {
+		s.record(ctx, uid, domain2.AuditShare, share.DataID, err)
+	}()
+
+	data, err := s.get(ctx, share.DataID, uid)
+	if err != nil {
+		return err
+	}
+
+	if data.Share != nil {
+		return domain2.ErrDataNotFound
+	}
+
+	if data.RecordKey == nil || *data.RecordKey == "" {
+		return domain2.ErrNoRecordKey
+	}
+
+	recipient, err := s.recipient(ctx, uid, login)
+	if err != nil {
+		return err
+	}
+
+	keys, err := s.UserRepo.GetKeys(ctx, recipient)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching user keys", "uid", recipient, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if keys.PublicKey == "" {
+		return domain2.ErrNoPublicKey
+	}
+
+	share.UID = recipient
+
+	if err = s.DataRepo.Share(ctx, share); err != nil {
+		internal.Logger.Errorw("error while saving share", "id", share.DataID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Unshare отозвать доступ пользователя с логином login к записи владельца uid
+func (s Service) Unshare(ctx context.Context, uid uint64, login string, dataID uint64) (err error) {
+	defer func() {
+		s.record(ctx, uid, domain2.AuditUnshare, dataID, err)
+	}()
+
+	data, err := s.get(ctx, dataID, uid)
+	if err != nil {
+		return err
+	}
+
+	if data.Share != nil {
+		return domain2.ErrDataNotFound
+	}
+
+	recipient, err := s.recipient(ctx, uid, login)
+	if err != nil {
+		return err
+	}
+
+	if err = s.DataRepo.Unshare(ctx, dataID, recipient); err != nil {
+		internal.Logger.Errorw("error while deleting share", "id", dataID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// recipient ИД получателя доступа по логину
+func (s Service) recipient(ctx context.Context, uid uint64, login string) (uint64, error) {
+	u, err := s.UserRepo.GetByLogin(ctx, login)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		internal.Logger.Errorw("error while fetching user", "login", login, "err", err)
+		return 0, domain2.ErrInternalServerError
+	}
+
+	if u.ID == 0 {
+		return 0, domain2.ErrUserNotFound
+	}
+
+	if u.ID == uid {
+		return 0, domain2.ErrShareSelf
+	}
+
+	return u.ID, nil
+}
+
+// checkWrite проверка, что пользователю предоставлен доступ к чужой записи на изменение
+func (s Service) checkWrite(ctx context.Context, dataID, uid uint64) error {
+	share, err := s.DataRepo.GetShare(ctx, dataID, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching share", "id", dataID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if share == nil {
+		return domain2.ErrDataNotFound
+	}
+
+	if share.Permission != domain2.SharePermissionWrite {
+		return domain2.ErrDataReadOnly
+	}
+
+	return nil
+}
+
+// ownerLogin логин владельца чужой записи
+func (s Service) ownerLogin(ctx context.Context, uid uint64) (string, error) {
+	u, err := s.UserRepo.GetByID(ctx, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching user", "uid", uid, "err", err)
+		return "", domain2.ErrInternalServerError
+	}
+
+	return u.Login, nil
+}
+
+func (s Service) record(ctx context.Context, uid uint64, action domain2.AuditAction, dataID uint64, err error) {
+	if s.Audit != nil {
+		s.Audit.Record(ctx, uid, action, dataID, err)
+	}
+}
+
+// ownerUID владелец записи, событие с чужой записью попадает в журнал ее владельца
+func ownerUID(data *domain2.Data, uid uint64) uint64 {
+	if data != nil && data.UID != 0 {
+		return data.UID
+	}
+
+	return uid
+}
+
 func (s Service) updateVersion(oldRow *domain2.Data, newRow *domain2.Data) error {
 	if newRow.Version == 0 {
 		return domain2.ErrDataVersionAbsent
@@ -221,20 +494,31 @@ func (s Service) updateVersion(oldRow *domain2.Data, newRow *domain2.Data) error
 
 // new: check by name and userId
 // update: get old row, if name changed find row with same name and userId
+// if client sent name blind index (encrypted name), check by it: encrypted name differs on every save
 func (s Service) checkName(ctx context.Context, data *domain2.Data, oldData *domain2.Data) (uniq bool, err error) {
 	var id uint64
 
-	if data.ID != 0 && data.Name == oldData.Name {
-		uniq = true
-		return
+	if data.NameHash != nil {
+		if data.ID != 0 && oldData.NameHash != nil && *data.NameHash == *oldData.NameHash {
+			uniq = true
+			return
+		}
+
+		id, err = s.DataRepo.GetByNameHashAndUserID(ctx, data.UID, *data.NameHash)
+	} else {
+		if data.ID != 0 && data.Name == oldData.Name {
+			uniq = true
+			return
+		}
+
+		id, err = s.DataRepo.GetByNameAndUserID(ctx, data.UID, data.Name)
 	}
 
-	id, err = s.DataRepo.GetByNameAndUserID(ctx, data.UID, data.Name)
 	if err != nil {
 		return
 	}
 
-	if id != 0 {
+	if id != 0 && id != data.ID {
 		uniq = false
 		return
 	}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"errors"
 	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
 	"gophkeeper/internal/server/repository/pgsql"
 	"gophkeeper/internal/test"
 	domain2 "gophkeeper/server/domain"
+	"gophkeeper/server/file"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestService_UpsertData(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	ctx := context.Background()
 	internal.InitLogger()
 	pool, err := test.InitConnection(ctx)
@@ -178,10 +185,14 @@ func GetTestService(ctx context.Context, t *testing.T, pool *pgxpool.Pool) *Serv
 	fileRepo, err := pgsql.NewFileRepository(ctx, pool, test.FileTestTable)
 	repo, err := pgsql.NewDataRepository(ctx, pool, test.DataTestTable, test.FileTestTable, test.UsersTestTable)
 	assert.NoError(t, err)
-	return NewService(repo, fileRepo)
+	userRepo, err := pgsql.NewUserRepository(ctx, pool, test.UsersTestTable)
+	assert.NoError(t, err)
+	return NewService(repo, fileRepo, userRepo)
 }
 
 func TestService_CheckUploadFileData(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	ctx := context.Background()
 	internal.InitLogger()
 	pool, err := test.InitConnection(ctx)
@@ -224,7 +235,7 @@ func TestService_CheckUploadFileData(t *testing.T) {
 
 	repo, err := pgsql.NewDataRepository(ctx, pool, "c_data", "c_files", "c_users")
 	assert.NoError(t, err)
-	service := NewService(repo, fileRepo)
+	service := NewService(repo, fileRepo, userRepo)
 
 	var fileId uint64 = 1
 	var fileId1 uint64 = 2
@@ -336,3 +347,261 @@ func TestService_CheckUploadFileData(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	fileRepo := memory.NewFileRepository()
+	service := NewService(memory.NewDataRepository(), fileRepo, memory.NewUserRepository())
+	fileService := file.NewService(fileRepo)
+
+	tmpFile, err := os.CreateTemp("", "test_delete")
+	assert.NoError(t, err)
+	assert.NoError(t, tmpFile.Close())
+
+	dFile := &domain2.File{
+		Name: filepath.Base(tmpFile.Name()),
+		Path: tmpFile.Name(),
+	}
+	err = fileRepo.Insert(ctx, dFile)
+	assert.NoError(t, err)
+
+	withFile := &domain2.Data{Name: "with file", UID: 1, Version: 1, FileID: &dFile.ID}
+	withoutFile := &domain2.Data{Name: "without file", UID: 1, Version: 1}
+	assert.NoError(t, service.DataRepo.Insert(ctx, withFile))
+	assert.NoError(t, service.DataRepo.Insert(ctx, withoutFile))
+
+	tests := []struct {
+		name    string
+		dataID  uint64
+		uid     uint64
+		wantErr error
+	}{
+		{
+			name:    "foreign data",
+			dataID:  withoutFile.ID,
+			uid:     2,
+			wantErr: domain2.ErrDataNotFound,
+		},
+		{
+			name:    "without file",
+			dataID:  withoutFile.ID,
+			uid:     1,
+			wantErr: nil,
+		},
+		{
+			name:    "with file",
+			dataID:  withFile.ID,
+			uid:     1,
+			wantErr: nil,
+		},
+		{
+			name:    "already deleted",
+			dataID:  withFile.ID,
+			uid:     1,
+			wantErr: domain2.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err = service.Delete(ctx, tt.dataID, tt.uid, *fileService)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	_, err = os.Stat(tmpFile.Name())
+	assert.True(t, os.IsNotExist(err))
+
+	got, err := fileRepo.Get(ctx, dFile.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestService_GetList(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	service := NewService(memory.NewDataRepository(), memory.NewFileRepository(), memory.NewUserRepository())
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		assert.NoError(t, service.DataRepo.Insert(ctx, &domain2.Data{Name: name, UID: 1, Version: 1}))
+	}
+
+	// постраничный обход должен вернуть все записи без повторов
+	var names []string
+	filter := domain2.DataListFilter{Limit: 2, Sort: domain2.DataSortNameDesc}
+	for page := 0; page < 5; page++ {
+		list, next, err := service.GetList(ctx, 1, filter)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(list), 2)
+
+		for _, d := range list {
+			names = append(names, d.Name)
+		}
+
+		if next == nil {
+			break
+		}
+
+		token, err := EncodePageToken(filter.Sort, *next)
+		assert.NoError(t, err)
+
+		filter.After, err = DecodePageToken(token, filter.Sort)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"e", "d", "c", "b", "a"}, names)
+
+	list, next, err := service.GetList(ctx, 2, domain2.DataListFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+	assert.Nil(t, next)
+}
+
+func TestDecodePageToken(t *testing.T) {
+	token, err := EncodePageToken(domain2.DataSortIDAsc, domain2.DataListCursor{Name: "name", ID: 10})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		sort    domain2.DataSort
+		want    *domain2.DataListCursor
+		wantErr error
+	}{
+		{
+			name: "empty token",
+		},
+		{
+			name:  "ok",
+			token: token,
+			sort:  domain2.DataSortIDAsc,
+			want:  &domain2.DataListCursor{Name: "name", ID: 10},
+		},
+		{
+			name:    "other sort",
+			token:   token,
+			sort:    domain2.DataSortNameAsc,
+			wantErr: domain2.ErrBadPageToken,
+		},
+		{
+			name:    "garbage",
+			token:   "!!!",
+			wantErr: domain2.ErrBadPageToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePageToken(tt.token, tt.sort)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_UpsertDataNameHash(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	service := NewService(memory.NewDataRepository(), memory.NewFileRepository(), memory.NewUserRepository())
+	hash, otherHash := "hash", "other"
+
+	first := &domain2.Data{Name: "encrypted 1", NameHash: &hash, UID: 1}
+	assert.NoError(t, service.UpsertData(ctx, first))
+
+	// зашифрованные названия различаются, дубликат определяется по слепому индексу
+	duplicate := &domain2.Data{Name: "encrypted 2", NameHash: &hash, UID: 1}
+	assert.ErrorIs(t, service.UpsertData(ctx, duplicate), domain2.ErrDataNameNotUniq)
+
+	otherUser := &domain2.Data{Name: "encrypted 3", NameHash: &hash, UID: 2}
+	assert.NoError(t, service.UpsertData(ctx, otherUser))
+
+	second := &domain2.Data{Name: "encrypted 4", NameHash: &otherHash, UID: 1}
+	assert.NoError(t, service.UpsertData(ctx, second))
+
+	// повторное сохранение с тем же индексом и новым шифротекстом названия
+	first.Name = "encrypted 5"
+	assert.NoError(t, service.UpsertData(ctx, first))
+
+	second.NameHash = &hash
+	assert.ErrorIs(t, service.UpsertData(ctx, second), domain2.ErrDataNameNotUniq)
+}
+
+func TestService_Share(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	userRepo := memory.NewUserRepository()
+	service := NewService(memory.NewDataRepository(), memory.NewFileRepository(), userRepo)
+
+	owner, err := userRepo.Store(ctx, domain2.User{Login: "alice", Password: "hash"})
+	require.NoError(t, err)
+	reader, err := userRepo.Store(ctx, domain2.User{Login: "bob", Password: "hash"})
+	require.NoError(t, err)
+	noKeys, err := userRepo.Store(ctx, domain2.User{Login: "carol", Password: "hash"})
+	require.NoError(t, err)
+	require.NoError(t, userRepo.SetKeys(ctx, reader, domain2.UserKeys{PublicKey: "public", PrivateKey: "private"}))
+
+	folderID := uint64(10)
+	data := &domain2.Data{Name: "db", UID: owner, FolderID: &folderID, Tags: []string{"prod"}}
+	require.NoError(t, service.UpsertData(ctx, data))
+
+	share := domain2.DataShare{DataID: data.ID, RecordKey: "sealed", Permission: domain2.SharePermissionRead}
+	assert.ErrorIs(t, service.Share(ctx, owner, "bob", share), domain2.ErrNoRecordKey)
+
+	recordKey := "wrapped"
+	data.RecordKey = &recordKey
+	require.NoError(t, service.UpsertData(ctx, data))
+
+	assert.ErrorIs(t, service.Share(ctx, owner, "alice", share), domain2.ErrShareSelf)
+	assert.ErrorIs(t, service.Share(ctx, owner, "nobody", share), domain2.ErrUserNotFound)
+	assert.ErrorIs(t, service.Share(ctx, owner, "carol", share), domain2.ErrNoPublicKey)
+	assert.ErrorIs(t, service.Share(ctx, noKeys, "bob", share), domain2.ErrDataNotFound)
+	require.NoError(t, service.Share(ctx, owner, "bob", share))
+
+	got, err := service.Get(ctx, data.ID, reader)
+	require.NoError(t, err)
+	require.NotNil(t, got.Share)
+	assert.Equal(t, "alice", got.Share.Owner)
+	assert.Equal(t, "sealed", got.Share.RecordKey)
+	assert.Nil(t, got.FolderID)
+	assert.Empty(t, got.Tags)
+
+	list, _, err := service.GetList(ctx, reader, domain2.DataListFilter{Shared: true})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "alice", list[0].Share.Owner)
+
+	// получатель может поделиться только своей записью
+	assert.ErrorIs(t, service.Share(ctx, reader, "carol", share), domain2.ErrDataNotFound)
+
+	text := "changed"
+	update := &domain2.Data{ID: data.ID, Name: "renamed", UID: reader, Text: &text, Version: data.Version}
+	assert.ErrorIs(t, service.UpsertData(ctx, update), domain2.ErrDataReadOnly)
+
+	share.Permission = domain2.SharePermissionWrite
+	require.NoError(t, service.Share(ctx, owner, "bob", share))
+	require.NoError(t, service.UpsertData(ctx, update))
+
+	got, err = service.Get(ctx, data.ID, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "db", got.Name)
+	assert.Equal(t, text, *got.Text)
+	assert.Equal(t, folderID, *got.FolderID)
+	assert.Equal(t, []string{"prod"}, got.Tags)
+	assert.Equal(t, recordKey, *got.RecordKey)
+	assert.Nil(t, got.Share)
+
+	// удаление получателем убирает только его доступ
+	require.NoError(t, service.Delete(ctx, data.ID, reader, file.Service{}))
+	_, err = service.Get(ctx, data.ID, reader)
+	assert.ErrorIs(t, err, domain2.ErrDataNotFound)
+	_, err = service.Get(ctx, data.ID, owner)
+	assert.NoError(t, err)
+
+	require.NoError(t, service.Share(ctx, owner, "bob", share))
+	require.NoError(t, service.Unshare(ctx, owner, "bob", data.ID))
+	list, _, err = service.GetList(ctx, reader, domain2.DataListFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
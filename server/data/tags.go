@@ -0,0 +1,23 @@
+package data
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeTags подготовка тегов к сохранению: удаление пробелов по краям, пустых тегов и повторов
+// теги возвращаются отсортированными, в том же порядке их отдает хранилище
+func NormalizeTags(tags []string) []string {
+	res := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			res = append(res, tag)
+		}
+	}
+
+	slices.Sort(res)
+
+	return slices.Compact(res)
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditAction действие пользователя в журнале аудита
+type AuditAction int
+
+const (
+	AuditRegister AuditAction = iota + 1
+	AuditLogin
+	AuditRefresh
+	AuditRead
+	AuditSave
+	AuditDelete
+	AuditDownload
+	AuditUpload
+	AuditShare
+	AuditUnshare
+	AuditDeleteAccount
+	AuditRestoreAccount
+	AuditPurgeAccount
+)
+
+// AuditOutcome результат действия: успех, отказ в доступе или ошибка
+type AuditOutcome int
+
+const (
+	AuditSuccess AuditOutcome = iota + 1
+	AuditDenied
+	AuditFailure
+)
+
+// AuditEvent событие журнала аудита, журнал только дополняется
+// UID - учетная запись, к данным которой относится событие (пользователь или хранилище организации),
+// ActorUID и Actor - кто выполнил действие: отличается от UID при работе в хранилище организации или по экстренному доступу
+type AuditEvent struct {
+	ID,
+	UID,
+	ActorUID uint64
+	Actor,
+	IP string
+	Action AuditAction
+	// DataID запись, с которой выполнялось действие, 0 - действие не относится к записи
+	DataID    uint64
+	Outcome   AuditOutcome
+	CreatedAt time.Time
+	// PrevHash хеш предыдущего события журнала, Hash - хеш этого события вместе с PrevHash
+	// цепочка хешей общая для всех пользователей, события до ее появления хранятся с пустыми хешами
+	PrevHash,
+	Hash string
+}
+
+// ChainHash хеш события в цепочке журнала: SHA-256 от хеша предыдущего события и всех полей, кроме ИД
+// строки экранируются, поэтому разные наборы полей не дают одинаковую последовательность байт
+func (e AuditEvent) ChainHash() string {
+	fields := []string{
+		strconv.Quote(e.PrevHash),
+		strconv.FormatUint(e.UID, 10),
+		strconv.FormatUint(e.ActorUID, 10),
+		strconv.Quote(e.Actor),
+		strconv.Quote(e.IP),
+		strconv.Itoa(int(e.Action)),
+		strconv.FormatUint(e.DataID, 10),
+		strconv.Itoa(int(e.Outcome)),
+		strconv.FormatInt(e.CreatedAt.Unix(), 10),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "|")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditCheckpoint подписанная ключом сервера отметка состояния журнала: последнее событие, его хеш и число событий
+// без закрытого ключа нельзя пересчитать цепочку после изменения событий так, чтобы отметки остались верными
+type AuditCheckpoint struct {
+	ID,
+	EventID uint64
+	Hash      string
+	Events    int64
+	CreatedAt time.Time
+	// Signature подпись SignedData, base64
+	Signature string
+}
+
+// SignedData данные отметки, которые подписываются ключом сервера
+func (c AuditCheckpoint) SignedData() []byte {
+	return []byte(strings.Join([]string{
+		"gophkeeper-audit-checkpoint",
+		strconv.FormatUint(c.EventID, 10),
+		c.Hash,
+		strconv.FormatInt(c.Events, 10),
+		strconv.FormatInt(c.CreatedAt.Unix(), 10),
+	}, "|"))
+}
+
+// AuditVerifyReport итог проверки журнала
+// Legacy - события, записанные до появления цепочки хешей, их целостность не проверяется
+type AuditVerifyReport struct {
+	Events,
+	Legacy,
+	Checkpoints int64
+	LastCheckpoint *time.Time
+}
+
+// AuditFilter фильтр журнала аудита, пустые поля не ограничивают выборку
+// события возвращаются от новых к старым, BeforeID - ИД последнего события предыдущей страницы
+type AuditFilter struct {
+	Actions []AuditAction
+	DataID  uint64
+	Outcome AuditOutcome
+	Since,
+	Until time.Time
+	BeforeID uint64
+	Limit    int
+}
+
+const (
+	AuditDefaultPageSize = 100
+	AuditMaxPageSize     = 1000
+)
@@ -2,3 +2,15 @@ package domain
 
 const AuthorizationMetaKey = "authorization"
 const TokenSubstr = "Bearer"
+
+// VaultMetaKey ИД организации, в хранилище которой выполняется запрос к данным
+const VaultMetaKey = "vault"
+
+// VaultKeyVersionMetaKey версия ключа хранилища организации, которым клиент шифрует изменения
+const VaultKeyVersionMetaKey = "vault-key-version"
+
+// EmergencyMetaKey ИД экстренного доступа, через который доверенное лицо читает хранилище владельца
+const EmergencyMetaKey = "emergency"
+
+// RetryAfterMetaKey число секунд до следующей разрешенной попытки входа или регистрации
+const RetryAfterMetaKey = "retry-after"
@@ -8,14 +8,102 @@ type Data struct {
 	UID uint64
 	Pass,
 	CardNum,
+	// CardExp срок действия карты, зашифрован клиентом
+	CardExp,
+	// Otp ключ одноразовых паролей (otpauth:// URI), зашифрован клиентом
+	Otp,
 	Text,
 	Meta,
-	Login *string
-	FileID *uint64
+	Login,
+	// NameHash слепой индекс названия, вычисляемый клиентом, когда название зашифровано
+	// уникальность названия в этом случае проверяется по нему
+	NameHash,
+	// RecordKey ключ записи, зашифрованный ключом хранилища владельца, nil - запись зашифрована ключом хранилища
+	RecordKey *string
+	FileID,
+	FolderID *uint64
+	// Tags хранятся в отдельной таблице, поэтому не участвуют в отображении строк запроса
+	Tags []string `db:"-"`
+	// Share доступ к чужой записи, заполняется, когда запись запрошена не владельцем
+	Share *DataShare `db:"-"`
 }
 
 // DataName структура для хранения данных в памяти в кратком виде
 type DataName struct {
 	Name string
-	ID   uint64
+	ID,
+	Version uint64
+	UID       uint64
+	FolderID  *uint64
+	RecordKey *string
+	Tags      []string   `db:"-"`
+	Share     *DataShare `db:"-"`
+}
+
+// SharePermission права пользователя на чужую запись
+type SharePermission int
+
+const (
+	SharePermissionRead SharePermission = iota + 1
+	SharePermissionWrite
+)
+
+// DataShare доступ пользователя UID к записи DataID
+// RecordKey - ключ записи, зашифрованный открытым ключом получателя, Owner - логин владельца записи
+type DataShare struct {
+	Owner,
+	RecordKey string
+	DataID,
+	UID uint64
+	Permission SharePermission
+}
+
+// DataType тип записи, определяется по заполненным полям
+type DataType int
+
+const (
+	DataTypeAny DataType = iota
+	DataTypeCredentials
+	DataTypeCard
+	DataTypeText
+	DataTypeFile
+	DataTypeOTP
+)
+
+// DataSort порядок сортировки списка данных
+type DataSort int
+
+const (
+	DataSortNameAsc DataSort = iota
+	DataSortNameDesc
+	DataSortIDAsc
+	DataSortIDDesc
+)
+
+// Ограничения размера страницы списка данных
+const (
+	DataListDefaultPageSize = 50
+	DataListMaxPageSize     = 500
+)
+
+// DataListCursor позиция в списке данных, после которой начинается следующая страница
+type DataListCursor struct {
+	Name string `json:"n"`
+	ID   uint64 `json:"i"`
+}
+
+// DataListFilter параметры выборки списка данных
+// FolderID - 0 для данных из любой папки, Tags - запись должна содержать все перечисленные теги
+// папки и теги принадлежат владельцу, поэтому с фильтром по ним выбираются только собственные записи
+// Shared - только чужие записи, к которым пользователю предоставлен доступ
+type DataListFilter struct {
+	NamePrefix,
+	NameContains string
+	FolderID uint64
+	Tags     []string
+	Type     DataType
+	Sort     DataSort
+	Limit    int
+	After    *DataListCursor
+	Shared   bool
 }
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// EmergencyState состояние экстренного доступа
+type EmergencyState int
+
+const (
+	EmergencyInvited EmergencyState = iota + 1
+	EmergencyAccepted
+	EmergencyRequested
+	EmergencyApproved
+	EmergencyRejected
+	EmergencyAutoApproved
+)
+
+// EmergencyGrant экстренный доступ доверенного лица GranteeUID к хранилищу GrantorUID
+// VaultKey - ключ хранилища владельца, зашифрованный открытым ключом доверенного лица,
+// Wait - время, через которое запрос доступа одобряется, если владелец его не отклонил
+type EmergencyGrant struct {
+	ID,
+	GrantorUID,
+	GranteeUID uint64
+	Grantor,
+	Grantee,
+	VaultKey string
+	State EmergencyState
+	Wait  time.Duration
+	// RequestedAt время последнего запроса доступа, nil - доступ не запрашивался
+	RequestedAt *time.Time
+}
+
+// Granted доступ к хранилищу предоставлен
+func (g EmergencyGrant) Granted() bool {
+	return g.State == EmergencyApproved || g.State == EmergencyAutoApproved
+}
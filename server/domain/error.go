@@ -18,4 +18,37 @@ var (
 	ErrDataNotFound        = errors.New("data not found")
 	ErrBadFileID           = errors.New("bad file id")
 	ErrFileNotFound        = errors.New("file not found")
+	ErrBadPageToken        = errors.New("bad page token")
+	ErrFolderNotFound      = errors.New("folder not found")
+	ErrFolderCycle         = errors.New("folder cannot be moved into itself")
+	ErrBadRefreshToken     = errors.New("bad refresh token")
+	ErrDataReadOnly        = errors.New("data is shared read-only")
+	ErrShareSelf           = errors.New("cannot share data with yourself")
+	ErrNoRecordKey         = errors.New("data has no record key")
+	ErrNoPublicKey         = errors.New("user has no public key")
+	ErrKeysExist           = errors.New("user keys already set")
+	ErrOrgNotFound         = errors.New("organization not found")
+	ErrOrgForbidden        = errors.New("not enough rights in organization")
+	ErrMemberExists        = errors.New("user is already a member of organization")
+	ErrMemberNotFound      = errors.New("organization member not found")
+	ErrLastOwner           = errors.New("organization must have an owner")
+	ErrOrgKeyVersion       = errors.New("organization key was changed")
+	ErrOrgRotation         = errors.New("key change must cover all members, records and folders")
+	ErrOrgMember           = errors.New("user must be removed from organizations with other members first")
+	ErrVaultMethod         = errors.New("method is not available in organization vault")
+	ErrGrantNotFound       = errors.New("emergency access not found")
+	ErrGrantExists         = errors.New("emergency access already granted to user")
+	ErrGrantSelf           = errors.New("cannot grant emergency access to yourself")
+	ErrGrantState          = errors.New("emergency access is in another state")
+	ErrGrantForbidden      = errors.New("emergency access is not approved")
+	ErrEmergencyMethod     = errors.New("emergency access is read-only")
+	ErrSecretNotFound      = errors.New("secret not found, expired or already viewed")
+	ErrSecretLinksDisabled = errors.New("secret links are disabled on server")
+	ErrAuditTampered       = errors.New("audit log was altered")
+	ErrRateLimited         = errors.New("too many attempts")
+	ErrWrongPassword       = errors.New("wrong password")
+	ErrAccountDeleting     = errors.New("account is scheduled for deletion")
+	ErrAccountDeleted      = errors.New("account was deleted")
+	ErrAccountActive       = errors.New("account is not scheduled for deletion")
+	ErrSessionRevoked      = errors.New("session was revoked, log in again")
 )
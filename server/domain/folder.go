@@ -0,0 +1,10 @@
+package domain
+
+// Folder папка для группировки данных пользователя
+// ParentID - nil для папок верхнего уровня
+type Folder struct {
+	Name string
+	ID,
+	UID uint64
+	ParentID *uint64
+}
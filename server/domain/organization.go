@@ -0,0 +1,59 @@
+package domain
+
+// OrgRole роль участника организации, роли упорядочены по возрастанию прав
+type OrgRole int
+
+const (
+	OrgRoleViewer OrgRole = iota + 1
+	OrgRoleEditor
+	OrgRoleAdmin
+	OrgRoleOwner
+)
+
+// Organization организация с общим хранилищем
+// записи, папки и файлы хранилища принадлежат отдельной учетной записи VaultUID без логина и пароля,
+// KeyVersion - версия ключа хранилища, увеличивается при каждой смене ключа
+type Organization struct {
+	ID,
+	VaultUID,
+	KeyVersion uint64
+	Name string
+}
+
+// OrgMember участник организации
+// CollectionKey - ключ хранилища, зашифрованный открытым ключом участника, Accepted - приглашение принято
+type OrgMember struct {
+	OrgID,
+	UID,
+	KeyVersion uint64
+	Login,
+	CollectionKey string
+	Role     OrgRole
+	Accepted bool
+}
+
+// OrgMembership организация и участие в ней пользователя
+type OrgMembership struct {
+	Org    Organization
+	Member OrgMember
+}
+
+// OrgRotation смена ключа хранилища при исключении участника RemoveUID
+// Keys - новый ключ для каждого оставшегося участника по ИД, записи и папки хранилища перешифрованы на новом ключе
+type OrgRotation struct {
+	OrgID,
+	VaultUID,
+	RemoveUID,
+	KeyVersion uint64
+	Keys    map[uint64]string
+	Records []RotatedRecord
+	Folders []Folder
+}
+
+// RotatedRecord ключ записи, слепой индекс названия и теги после смены ключа хранилища
+type RotatedRecord struct {
+	ID        uint64
+	RecordKey string
+	NameHash  *string
+	Tags      []string
+}
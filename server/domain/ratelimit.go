@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// RateLimit ограничение частоты попыток по ключу: корзина на Burst токенов, один токен восстанавливается за Every
+// после MaxFailures неудачных попыток подряд ключ блокируется на LockoutBase, каждая следующая неудача удваивает
+// блокировку до LockoutMax; MaxFailures = 0 - неудачи не учитываются
+type RateLimit struct {
+	Burst       int
+	Every       time.Duration
+	MaxFailures int
+	LockoutBase,
+	LockoutMax time.Duration
+}
+
+// RateBucket состояние ограничения по ключу (логину или IP клиента)
+// UpdatedAt - время последнего пересчета токенов, FailedAt - время последней неудачной попытки
+type RateBucket struct {
+	Key         string
+	Tokens      float64
+	UpdatedAt   time.Time
+	Failures    int
+	FailedAt    time.Time
+	LockedUntil time.Time
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// SecretShare секрет, переданный по ссылке человеку без учетной записи
+// Blob зашифрован на клиенте, ключ есть только во фрагменте ссылки, поэтому сервер секрет прочитать не может;
+// после MaxViews просмотров или по истечении ExpiresAt секрет удаляется
+type SecretShare struct {
+	ID        string
+	UID       uint64
+	Blob      string
+	ExpiresAt time.Time
+	MaxViews,
+	Views int
+}
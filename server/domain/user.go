@@ -1,8 +1,39 @@
 package domain
 
+import "time"
+
 // User структура для хранения пользователя в памяти
 type User struct {
 	ID       uint64 `json:"-"`
 	Login    string `json:"login,omitempty"`
 	Password string `json:"password,omitempty"`
 }
+
+// Tokens токены, выдаваемые пользователю при авторизации
+// Access - для запросов к данным, Refresh - для получения нового Access без пароля
+type Tokens struct {
+	Access,
+	Refresh string
+}
+
+// UserKeys пара ключей X25519 пользователя для обмена записями в base64
+// PrivateKey зашифрован ключом хранилища пользователя на клиенте
+type UserKeys struct {
+	PublicKey,
+	PrivateKey string
+}
+
+// AccountState состояние учетной записи для проверки сессий
+// PurgeAt - время окончательного удаления запланированной к удалению записи, нулевое - удаление не запланировано;
+// токены, выданные раньше SessionsAfter, недействительны
+type AccountState struct {
+	PurgeAt,
+	SessionsAfter time.Time
+}
+
+// AccountPurge что удаляется вместе с учетной записью пользователя
+// UIDs - пользователь и хранилища организаций, где он единственный участник, OrgIDs - эти организации
+type AccountPurge struct {
+	UIDs,
+	OrgIDs []uint64
+}
@@ -0,0 +1,298 @@
+// Package emergency пакет для экстренного доступа доверенного лица к хранилищу пользователя
+// владелец назначает доверенное лицо и передает ему ключ хранилища, зашифрованный его открытым ключом;
+// сервер отдает ключ только после одобрения запроса владельцем или по истечении времени ожидания
+package emergency
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+	"time"
+)
+
+type Service struct {
+	repo     Repository
+	userRepo UserRepository
+	// now текущее время, подменяется в тестах
+	now func() time.Time
+}
+
+// Repository интерфейс для описания методов хранилища экстренных доступов
+type Repository interface {
+	Insert(ctx context.Context, grant *domain2.EmergencyGrant) error
+	Get(ctx context.Context, id uint64) (*domain2.EmergencyGrant, error)
+	GetByPair(ctx context.Context, grantorUID, granteeUID uint64) (*domain2.EmergencyGrant, error)
+	GetByUser(ctx context.Context, uid uint64) ([]domain2.EmergencyGrant, error)
+	Update(ctx context.Context, grant domain2.EmergencyGrant, state domain2.EmergencyState) error
+	Delete(ctx context.Context, id uint64) error
+	AutoApprove(ctx context.Context, now time.Time) (int64, error)
+}
+
+// UserRepository интерфейс хранилища пользователей, необходимый для назначения доверенного лица
+type UserRepository interface {
+	GetByLogin(ctx context.Context, login string) (domain2.User, error)
+	GetKeys(ctx context.Context, id uint64) (domain2.UserKeys, error)
+}
+
+func NewService(repo Repository, userRepo UserRepository) *Service {
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		now:      time.Now,
+	}
+}
+
+// Create назначение пользователя login доверенным лицом владельца uid
+// vaultKey - ключ хранилища владельца, зашифрованный открытым ключом доверенного лица
+func (s *Service) Create(ctx context.Context, uid uint64, login string, wait time.Duration, vaultKey string) (*domain2.EmergencyGrant, error) {
+	u, err := s.userRepo.GetByLogin(ctx, login)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching user", "login", login, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	if u.ID == 0 {
+		return nil, domain2.ErrUserNotFound
+	}
+
+	if u.ID == uid {
+		return nil, domain2.ErrGrantSelf
+	}
+
+	keys, err := s.userRepo.GetKeys(ctx, u.ID)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching user keys", "uid", u.ID, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	if keys.PublicKey == "" {
+		return nil, domain2.ErrNoPublicKey
+	}
+
+	existing, err := s.repo.GetByPair(ctx, uid, u.ID)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching emergency access", "uid", uid, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	if existing != nil {
+		return nil, domain2.ErrGrantExists
+	}
+
+	grant := &domain2.EmergencyGrant{GrantorUID: uid, GranteeUID: u.ID, State: domain2.EmergencyInvited, Wait: wait, VaultKey: vaultKey}
+	if err = s.repo.Insert(ctx, grant); err != nil {
+		internal.Logger.Errorw("error while inserting emergency access", "uid", uid, "err", err)
+		return nil, domain2.ErrDataInsert
+	}
+
+	return grant, nil
+}
+
+// GetList экстренные доступы пользователя в обе стороны
+// ключ хранилища возвращается только доверенному лицу и только после одобрения
+func (s *Service) GetList(ctx context.Context, uid uint64) ([]domain2.EmergencyGrant, error) {
+	if err := s.AutoApprove(ctx); err != nil {
+		return nil, err
+	}
+
+	list, err := s.repo.GetByUser(ctx, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching emergency access", "uid", uid, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	for i := range list {
+		if list[i].GranteeUID != uid || !list[i].Granted() {
+			list[i].VaultKey = ""
+		}
+	}
+
+	return list, nil
+}
+
+// Accept согласие доверенного лица
+func (s *Service) Accept(ctx context.Context, uid, id uint64) error {
+	return s.change(ctx, id, func(g *domain2.EmergencyGrant) error {
+		if g.GranteeUID != uid {
+			return domain2.ErrGrantNotFound
+		}
+
+		if g.State != domain2.EmergencyInvited {
+			return domain2.ErrGrantState
+		}
+
+		g.State = domain2.EmergencyAccepted
+
+		return nil
+	})
+}
+
+// Request запрос доступа доверенным лицом, после отказа доступ можно запросить снова
+func (s *Service) Request(ctx context.Context, uid, id uint64) error {
+	return s.change(ctx, id, func(g *domain2.EmergencyGrant) error {
+		if g.GranteeUID != uid {
+			return domain2.ErrGrantNotFound
+		}
+
+		if g.State != domain2.EmergencyAccepted && g.State != domain2.EmergencyRejected {
+			return domain2.ErrGrantState
+		}
+
+		now := s.now()
+		g.State, g.RequestedAt = domain2.EmergencyRequested, &now
+
+		return nil
+	})
+}
+
+// Approve одобрение запроса владельцем до истечения времени ожидания
+func (s *Service) Approve(ctx context.Context, uid, id uint64) error {
+	return s.change(ctx, id, func(g *domain2.EmergencyGrant) error {
+		if g.GrantorUID != uid {
+			return domain2.ErrGrantNotFound
+		}
+
+		if g.State != domain2.EmergencyRequested {
+			return domain2.ErrGrantState
+		}
+
+		g.State = domain2.EmergencyApproved
+
+		return nil
+	})
+}
+
+// Reject отказ владельца: отклоняет ожидающий запрос или прекращает уже предоставленный доступ
+func (s *Service) Reject(ctx context.Context, uid, id uint64) error {
+	return s.change(ctx, id, func(g *domain2.EmergencyGrant) error {
+		if g.GrantorUID != uid {
+			return domain2.ErrGrantNotFound
+		}
+
+		if g.State != domain2.EmergencyRequested && !g.Granted() {
+			return domain2.ErrGrantState
+		}
+
+		g.State = domain2.EmergencyRejected
+
+		return nil
+	})
+}
+
+// Revoke удаление экстренного доступа владельцем или отказ доверенного лица
+func (s *Service) Revoke(ctx context.Context, uid, id uint64) error {
+	g, err := s.get(ctx, id, uid)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.Delete(ctx, g.ID); err != nil {
+		internal.Logger.Errorw("error while deleting emergency access", "id", id, "err", err)
+		return domain2.ErrDataUpdate
+	}
+
+	return nil
+}
+
+// Vault ИД владельца хранилища для запроса доверенного лица uid к данным по экстренному доступу id
+func (s *Service) Vault(ctx context.Context, id, uid uint64) (uint64, error) {
+	if err := s.AutoApprove(ctx); err != nil {
+		return 0, err
+	}
+
+	g, err := s.get(ctx, id, uid)
+	if err != nil {
+		return 0, err
+	}
+
+	if g.GranteeUID != uid {
+		return 0, domain2.ErrGrantNotFound
+	}
+
+	if !g.Granted() {
+		return 0, domain2.ErrGrantForbidden
+	}
+
+	return g.GrantorUID, nil
+}
+
+// AutoApprove одобрение запросов, время ожидания которых истекло
+func (s *Service) AutoApprove(ctx context.Context) error {
+	n, err := s.repo.AutoApprove(ctx, s.now())
+	if err != nil {
+		internal.Logger.Errorw("error while approving emergency access", "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if n > 0 {
+		internal.Logger.Infow("emergency access approved after waiting period", "count", n)
+	}
+
+	return nil
+}
+
+// Run периодическое одобрение запросов с истекшим временем ожидания до отмены ctx
+// запросы проверяются и при каждом обращении к доступу, таймер нужен, чтобы состояние менялось без обращений
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.AutoApprove(ctx)
+		}
+	}
+}
+
+// change изменение состояния экстренного доступа, в котором участвует пользователь
+// состояние меняется, только если оно не изменилось после чтения, например, автоматическим одобрением
+func (s *Service) change(ctx context.Context, id uint64, f func(g *domain2.EmergencyGrant) error) error {
+	if err := s.AutoApprove(ctx); err != nil {
+		return err
+	}
+
+	g, err := s.repo.Get(ctx, id)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching emergency access", "id", id, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if g == nil {
+		return domain2.ErrGrantNotFound
+	}
+
+	state := g.State
+	if err = f(g); err != nil {
+		return err
+	}
+
+	if err = s.repo.Update(ctx, *g, state); err != nil {
+		if errors.Is(err, domain2.ErrGrantState) {
+			return err
+		}
+
+		internal.Logger.Errorw("error while updating emergency access", "id", id, "err", err)
+		return domain2.ErrDataUpdate
+	}
+
+	return nil
+}
+
+// get экстренный доступ, в котором пользователь владелец или доверенное лицо
+func (s *Service) get(ctx context.Context, id, uid uint64) (*domain2.EmergencyGrant, error) {
+	g, err := s.repo.Get(ctx, id)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching emergency access", "id", id, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	if g == nil || (g.GrantorUID != uid && g.GranteeUID != uid) {
+		return nil, domain2.ErrGrantNotFound
+	}
+
+	return g, nil
+}
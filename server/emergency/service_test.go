@@ -0,0 +1,163 @@
+package emergency
+
+import (
+	"context"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
+	domain2 "gophkeeper/server/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	users := memory.NewUserRepository()
+	service := NewService(memory.NewEmergencyRepository(users), users)
+
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	uids := make(map[string]uint64)
+	for _, login := range []string{"alice", "bob", "carol"} {
+		uid, err := users.Store(ctx, domain2.User{Login: login, Password: "hash"})
+		require.NoError(t, err)
+		uids[login] = uid
+
+		if login != "carol" {
+			require.NoError(t, users.SetKeys(ctx, uid, domain2.UserKeys{PublicKey: "public-" + login, PrivateKey: "private"}))
+		}
+	}
+
+	_, err := service.Create(ctx, uids["alice"], "alice", time.Hour, "key")
+	assert.ErrorIs(t, err, domain2.ErrGrantSelf)
+	_, err = service.Create(ctx, uids["alice"], "nobody", time.Hour, "key")
+	assert.ErrorIs(t, err, domain2.ErrUserNotFound)
+	_, err = service.Create(ctx, uids["alice"], "carol", time.Hour, "key")
+	assert.ErrorIs(t, err, domain2.ErrNoPublicKey)
+
+	grant, err := service.Create(ctx, uids["alice"], "bob", time.Hour, "sealed")
+	require.NoError(t, err)
+	_, err = service.Create(ctx, uids["alice"], "bob", time.Hour, "sealed")
+	assert.ErrorIs(t, err, domain2.ErrGrantExists)
+
+	assert.ErrorIs(t, service.Request(ctx, uids["bob"], grant.ID), domain2.ErrGrantState)
+	assert.ErrorIs(t, service.Accept(ctx, uids["alice"], grant.ID), domain2.ErrGrantNotFound)
+	assert.ErrorIs(t, service.Accept(ctx, uids["carol"], grant.ID), domain2.ErrGrantNotFound)
+	require.NoError(t, service.Accept(ctx, uids["bob"], grant.ID))
+
+	_, err = service.Vault(ctx, grant.ID, uids["bob"])
+	assert.ErrorIs(t, err, domain2.ErrGrantForbidden)
+
+	t.Run("approve", func(t *testing.T) {
+		require.NoError(t, service.Request(ctx, uids["bob"], grant.ID))
+		assert.ErrorIs(t, service.Approve(ctx, uids["bob"], grant.ID), domain2.ErrGrantNotFound)
+		require.NoError(t, service.Approve(ctx, uids["alice"], grant.ID))
+
+		vaultUID, err := service.Vault(ctx, grant.ID, uids["bob"])
+		require.NoError(t, err)
+		assert.Equal(t, uids["alice"], vaultUID)
+
+		_, err = service.Vault(ctx, grant.ID, uids["alice"])
+		assert.ErrorIs(t, err, domain2.ErrGrantNotFound)
+
+		list, err := service.GetList(ctx, uids["bob"])
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "sealed", list[0].VaultKey)
+		assert.Equal(t, "alice", list[0].Grantor)
+
+		// владелец не получает ключ, зашифрованный для доверенного лица
+		list, err = service.GetList(ctx, uids["alice"])
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Empty(t, list[0].VaultKey)
+
+		require.NoError(t, service.Reject(ctx, uids["alice"], grant.ID))
+		_, err = service.Vault(ctx, grant.ID, uids["bob"])
+		assert.ErrorIs(t, err, domain2.ErrGrantForbidden)
+	})
+
+	t.Run("waiting period", func(t *testing.T) {
+		require.NoError(t, service.Request(ctx, uids["bob"], grant.ID))
+
+		list, err := service.GetList(ctx, uids["bob"])
+		require.NoError(t, err)
+		assert.Equal(t, domain2.EmergencyRequested, list[0].State)
+		assert.Empty(t, list[0].VaultKey)
+
+		now = now.Add(59 * time.Minute)
+		_, err = service.Vault(ctx, grant.ID, uids["bob"])
+		assert.ErrorIs(t, err, domain2.ErrGrantForbidden)
+
+		now = now.Add(time.Minute)
+		_, err = service.Vault(ctx, grant.ID, uids["bob"])
+		require.NoError(t, err)
+
+		list, err = service.GetList(ctx, uids["alice"])
+		require.NoError(t, err)
+		assert.Equal(t, domain2.EmergencyAutoApproved, list[0].State)
+		assert.ErrorIs(t, service.Approve(ctx, uids["alice"], grant.ID), domain2.ErrGrantState)
+	})
+
+	assert.ErrorIs(t, service.Revoke(ctx, uids["carol"], grant.ID), domain2.ErrGrantNotFound)
+	require.NoError(t, service.Revoke(ctx, uids["bob"], grant.ID))
+
+	list, err := service.GetList(ctx, uids["alice"])
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+// autoApproveRepo хранилище, в котором запрос одобряется автоматически сразу после чтения экстренного доступа
+type autoApproveRepo struct {
+	Repository
+	at time.Time
+}
+
+func (r autoApproveRepo) Get(ctx context.Context, id uint64) (*domain2.EmergencyGrant, error) {
+	g, err := r.Repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Repository.AutoApprove(ctx, r.at)
+
+	return g, err
+}
+
+func TestService_ChangeAfterAutoApprove(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	users := memory.NewUserRepository()
+	repo := memory.NewEmergencyRepository(users)
+	service := NewService(repo, users)
+
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	uids := make(map[string]uint64)
+	for _, login := range []string{"alice", "bob"} {
+		uid, err := users.Store(ctx, domain2.User{Login: login, Password: "hash"})
+		require.NoError(t, err)
+		require.NoError(t, users.SetKeys(ctx, uid, domain2.UserKeys{PublicKey: "public-" + login, PrivateKey: "private"}))
+		uids[login] = uid
+	}
+
+	grant, err := service.Create(ctx, uids["alice"], "bob", time.Hour, "sealed")
+	require.NoError(t, err)
+	require.NoError(t, service.Accept(ctx, uids["bob"], grant.ID))
+	require.NoError(t, service.Request(ctx, uids["bob"], grant.ID))
+
+	// время ожидания истекает между чтением запроса и его отклонением владельцем
+	service.repo = autoApproveRepo{Repository: repo, at: now.Add(time.Hour)}
+	assert.ErrorIs(t, service.Reject(ctx, uids["alice"], grant.ID), domain2.ErrGrantState)
+
+	g, err := repo.Get(ctx, grant.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain2.EmergencyAutoApproved, g.State)
+}
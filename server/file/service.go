@@ -95,6 +95,16 @@ func (s *Service) Delete(ctx context.Context, id uint64) error {
 	return nil
 }
 
+// DeleteBlobs удаление с диска файлов, записи о которых уже удалены из базы данных
+// ошибки только логируются: записи удалены, а оставшийся на диске файл больше ни на что не ссылается
+func (s *Service) DeleteBlobs(_ context.Context, paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			internal.Logger.Infow("error while removing file", "path", path, "error", err)
+		}
+	}
+}
+
 // GetSaveFileSubDir получить путь к файлу, основанные на ИД пользователя и ИД данных
 func GetSaveFileSubDir(data domain2.Data) string {
 	return "/" + strconv.FormatUint(data.UID, 10) + "/" + strconv.FormatUint(data.ID, 10)
@@ -14,6 +14,8 @@ import (
 )
 
 func TestService_Save(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	testUsersTable := "u_s"
 	testFileTable := "f_s"
 	testDataTable := "d_s"
@@ -0,0 +1,165 @@
+// Package folder пакет для работы с папками пользователя
+// Папки образуют дерево, запись данных может находиться в одной папке
+package folder
+
+import (
+	"context"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+)
+
+type Service struct {
+	repo     Repository
+	dataRepo DataRepository
+}
+
+// Repository интерфейс для описания методов хранилища папок
+type Repository interface {
+	Insert(ctx context.Context, folder *domain2.Folder) error
+	Update(ctx context.Context, folder domain2.Folder) error
+	GetByUser(ctx context.Context, id, uid uint64) (*domain2.Folder, error)
+	GetList(ctx context.Context, uid uint64) ([]domain2.Folder, error)
+	Delete(ctx context.Context, ids []uint64) error
+}
+
+// DataRepository интерфейс хранилища данных, необходимый при удалении папок
+type DataRepository interface {
+	UnsetFolder(ctx context.Context, uid uint64, folderIDs []uint64) error
+}
+
+func NewService(repo Repository, dataRepo DataRepository) *Service {
+	return &Service{
+		repo:     repo,
+		dataRepo: dataRepo,
+	}
+}
+
+// Save добавление или сохранение (если есть ИД) папки
+// родительская папка должна принадлежать пользователю, папку нельзя переместить в саму себя или в дочернюю папку
+func (s *Service) Save(ctx context.Context, folder *domain2.Folder) error {
+	if folder.ParentID != nil && *folder.ParentID == 0 {
+		folder.ParentID = nil
+	}
+
+	list, err := s.repo.GetList(ctx, folder.UID)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching folders", "uid", folder.UID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	parents := make(map[uint64]*uint64, len(list))
+	for _, f := range list {
+		parents[f.ID] = f.ParentID
+	}
+
+	if folder.ID != 0 {
+		if _, ok := parents[folder.ID]; !ok {
+			return domain2.ErrFolderNotFound
+		}
+	}
+
+	for parentID := folder.ParentID; parentID != nil; parentID = parents[*parentID] {
+		if _, ok := parents[*parentID]; !ok {
+			return domain2.ErrFolderNotFound
+		}
+
+		if *parentID == folder.ID {
+			return domain2.ErrFolderCycle
+		}
+	}
+
+	if folder.ID == 0 {
+		if err = s.repo.Insert(ctx, folder); err != nil {
+			internal.Logger.Errorw("error while inserting folder", "err", err)
+			return domain2.ErrDataInsert
+		}
+
+		return nil
+	}
+
+	if err = s.repo.Update(ctx, *folder); err != nil {
+		internal.Logger.Errorw("error while updating folder", "id", folder.ID, "err", err)
+		return domain2.ErrDataUpdate
+	}
+
+	return nil
+}
+
+// Get получить папку пользователя
+func (s *Service) Get(ctx context.Context, id, uid uint64) (*domain2.Folder, error) {
+	folder, err := s.repo.GetByUser(ctx, id, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching folder", "id", id, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	if folder == nil {
+		return nil, domain2.ErrFolderNotFound
+	}
+
+	return folder, nil
+}
+
+// GetList получить все папки пользователя
+func (s *Service) GetList(ctx context.Context, uid uint64) ([]domain2.Folder, error) {
+	list, err := s.repo.GetList(ctx, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching folders", "uid", uid, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	return list, nil
+}
+
+// Delete удалить папку вместе с вложенными папками
+// данные из удаленных папок остаются у пользователя без папки
+func (s *Service) Delete(ctx context.Context, id, uid uint64) error {
+	list, err := s.GetList(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	ids := subtree(list, id)
+	if len(ids) == 0 {
+		return domain2.ErrFolderNotFound
+	}
+
+	if err = s.dataRepo.UnsetFolder(ctx, uid, ids); err != nil {
+		internal.Logger.Errorw("error while unset data folder", "ids", ids, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if err = s.repo.Delete(ctx, ids); err != nil {
+		internal.Logger.Errorw("error while deleting folders", "ids", ids, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// subtree ИД папки и всех вложенных в нее папок, пусто - если папки нет в списке
+func subtree(list []domain2.Folder, id uint64) []uint64 {
+	children := make(map[uint64][]uint64, len(list))
+	found := false
+
+	for _, f := range list {
+		if f.ID == id {
+			found = true
+		}
+
+		if f.ParentID != nil {
+			children[*f.ParentID] = append(children[*f.ParentID], f.ID)
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	res := []uint64{id}
+	for i := 0; i < len(res); i++ {
+		res = append(res, children[res[i]]...)
+	}
+
+	return res
+}
@@ -0,0 +1,103 @@
+package folder
+
+import (
+	"context"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
+	domain2 "gophkeeper/server/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Save(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	service := NewService(memory.NewFolderRepository(), memory.NewDataRepository())
+
+	root := &domain2.Folder{Name: "work", UID: 1}
+	assert.NoError(t, service.Save(ctx, root))
+
+	child := &domain2.Folder{Name: "bank", UID: 1, ParentID: &root.ID}
+	assert.NoError(t, service.Save(ctx, child))
+
+	foreign := &domain2.Folder{Name: "other", UID: 2}
+	assert.NoError(t, service.Save(ctx, foreign))
+
+	tests := []struct {
+		name    string
+		folder  domain2.Folder
+		wantErr error
+	}{
+		{
+			name:   "rename",
+			folder: domain2.Folder{ID: child.ID, Name: "banks", UID: 1, ParentID: &root.ID},
+		},
+		{
+			name:    "move into itself",
+			folder:  domain2.Folder{ID: root.ID, Name: "work", UID: 1, ParentID: &root.ID},
+			wantErr: domain2.ErrFolderCycle,
+		},
+		{
+			name:    "move into child",
+			folder:  domain2.Folder{ID: root.ID, Name: "work", UID: 1, ParentID: &child.ID},
+			wantErr: domain2.ErrFolderCycle,
+		},
+		{
+			name:    "foreign parent",
+			folder:  domain2.Folder{Name: "new", UID: 1, ParentID: &foreign.ID},
+			wantErr: domain2.ErrFolderNotFound,
+		},
+		{
+			name:    "foreign folder",
+			folder:  domain2.Folder{ID: foreign.ID, Name: "mine", UID: 1},
+			wantErr: domain2.ErrFolderNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.Save(ctx, &tt.folder)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	got, err := service.Get(ctx, child.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "banks", got.Name)
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	dataRepo := memory.NewDataRepository()
+	service := NewService(memory.NewFolderRepository(), dataRepo)
+
+	root := &domain2.Folder{Name: "work", UID: 1}
+	assert.NoError(t, service.Save(ctx, root))
+	child := &domain2.Folder{Name: "bank", UID: 1, ParentID: &root.ID}
+	assert.NoError(t, service.Save(ctx, child))
+	other := &domain2.Folder{Name: "home", UID: 1}
+	assert.NoError(t, service.Save(ctx, other))
+
+	inChild := &domain2.Data{Name: "card", UID: 1, Version: 1, FolderID: &child.ID}
+	inOther := &domain2.Data{Name: "wifi", UID: 1, Version: 1, FolderID: &other.ID}
+	assert.NoError(t, dataRepo.Insert(ctx, inChild))
+	assert.NoError(t, dataRepo.Insert(ctx, inOther))
+
+	assert.ErrorIs(t, service.Delete(ctx, root.ID, 2), domain2.ErrFolderNotFound)
+	assert.NoError(t, service.Delete(ctx, root.ID, 1))
+
+	list, err := service.GetList(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain2.Folder{*other}, list)
+
+	got, err := dataRepo.Get(ctx, inChild.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, got.FolderID)
+
+	got, err = dataRepo.Get(ctx, inOther.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, other.ID, *got.FolderID)
+}
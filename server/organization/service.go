@@ -0,0 +1,448 @@
+// Package organization пакет для работы с организациями и их общими хранилищами
+// хранилище организации - отдельная учетная запись, от имени которой участники работают с записями и папками,
+// ключ хранилища передается каждому участнику зашифрованным его открытым ключом
+package organization
+
+import (
+	"context"
+	"errors"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+)
+
+type Service struct {
+	repo       Repository
+	userRepo   UserRepository
+	dataRepo   DataRepository
+	folderRepo FolderRepository
+}
+
+// Repository интерфейс для описания методов хранилища организаций
+type Repository interface {
+	Insert(ctx context.Context, org *domain2.Organization, owner domain2.OrgMember) error
+	Get(ctx context.Context, id uint64) (*domain2.Organization, error)
+	GetByUser(ctx context.Context, uid uint64) ([]domain2.OrgMembership, error)
+	GetMember(ctx context.Context, orgID, uid uint64) (*domain2.OrgMember, error)
+	GetMembers(ctx context.Context, orgID uint64) ([]domain2.OrgMember, error)
+	SaveMember(ctx context.Context, member domain2.OrgMember) error
+	DeleteMember(ctx context.Context, orgID, uid uint64) error
+	Rotate(ctx context.Context, rotation domain2.OrgRotation) error
+}
+
+// UserRepository интерфейс хранилища пользователей, необходимый для приглашения участников
+type UserRepository interface {
+	GetByLogin(ctx context.Context, login string) (domain2.User, error)
+	GetKeys(ctx context.Context, id uint64) (domain2.UserKeys, error)
+}
+
+// DataRepository интерфейс хранилища данных, необходимый для проверки смены ключа
+type DataRepository interface {
+	GetList(ctx context.Context, uid uint64, filter domain2.DataListFilter) ([]domain2.DataName, error)
+}
+
+// FolderRepository интерфейс хранилища папок, необходимый для проверки смены ключа
+type FolderRepository interface {
+	GetList(ctx context.Context, uid uint64) ([]domain2.Folder, error)
+}
+
+func NewService(repo Repository, userRepo UserRepository, dataRepo DataRepository, folderRepo FolderRepository) *Service {
+	return &Service{
+		repo:       repo,
+		userRepo:   userRepo,
+		dataRepo:   dataRepo,
+		folderRepo: folderRepo,
+	}
+}
+
+// Create создание организации, пользователь uid становится ее владельцем
+// collectionKey - ключ хранилища, зашифрованный открытым ключом пользователя
+func (s *Service) Create(ctx context.Context, uid uint64, name, collectionKey string) (*domain2.Organization, error) {
+	if err := s.checkPublicKey(ctx, uid); err != nil {
+		return nil, err
+	}
+
+	org := &domain2.Organization{Name: name, KeyVersion: 1}
+	owner := domain2.OrgMember{UID: uid, Role: domain2.OrgRoleOwner, CollectionKey: collectionKey, KeyVersion: 1, Accepted: true}
+
+	if err := s.repo.Insert(ctx, org, owner); err != nil {
+		internal.Logger.Errorw("error while inserting organization", "uid", uid, "err", err)
+		return nil, domain2.ErrDataInsert
+	}
+
+	return org, nil
+}
+
+// GetList организации пользователя, для непринятых приглашений ключ хранилища не возвращается
+func (s *Service) GetList(ctx context.Context, uid uint64) ([]domain2.OrgMembership, error) {
+	list, err := s.repo.GetByUser(ctx, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching organizations", "uid", uid, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	for i := range list {
+		if !list[i].Member.Accepted {
+			list[i].Member.CollectionKey = ""
+		}
+	}
+
+	return list, nil
+}
+
+// GetMembers участники организации, список доступен любому участнику, принявшему приглашение
+func (s *Service) GetMembers(ctx context.Context, orgID, uid uint64) (*domain2.Organization, []domain2.OrgMember, error) {
+	org, _, err := s.member(ctx, orgID, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list, err := s.repo.GetMembers(ctx, orgID)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching organization members", "id", orgID, "err", err)
+		return nil, nil, domain2.ErrInternalServerError
+	}
+
+	return org, list, nil
+}
+
+// Invite приглашение пользователя login с ролью role
+// приглашать могут администраторы и владельцы, роль приглашенного не может быть выше своей
+func (s *Service) Invite(ctx context.Context, uid, orgID uint64, login string, role domain2.OrgRole, collectionKey string, keyVersion uint64) error {
+	org, actor, err := s.member(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+
+	if actor.Role < domain2.OrgRoleAdmin || role > actor.Role {
+		return domain2.ErrOrgForbidden
+	}
+
+	if keyVersion != org.KeyVersion {
+		return domain2.ErrOrgKeyVersion
+	}
+
+	u, err := s.userRepo.GetByLogin(ctx, login)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching user", "login", login, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if u.ID == 0 {
+		return domain2.ErrUserNotFound
+	}
+
+	if err = s.checkPublicKey(ctx, u.ID); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetMember(ctx, orgID, u.ID)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching organization member", "id", orgID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if existing != nil {
+		return domain2.ErrMemberExists
+	}
+
+	return s.saveMember(ctx, domain2.OrgMember{
+		OrgID:         orgID,
+		UID:           u.ID,
+		Role:          role,
+		CollectionKey: collectionKey,
+		KeyVersion:    keyVersion,
+	})
+}
+
+// Accept принятие приглашения в организацию
+func (s *Service) Accept(ctx context.Context, uid, orgID uint64) error {
+	m, err := s.repo.GetMember(ctx, orgID, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching organization member", "id", orgID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if m == nil {
+		return domain2.ErrOrgNotFound
+	}
+
+	if m.Accepted {
+		return nil
+	}
+
+	m.Accepted = true
+
+	return s.saveMember(ctx, *m)
+}
+
+// SetRole изменение роли участника
+// администратор не может изменять роль владельцев и назначать владельцев, у организации остается хотя бы один владелец
+func (s *Service) SetRole(ctx context.Context, uid, orgID uint64, login string, role domain2.OrgRole) error {
+	_, actor, err := s.member(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+
+	members, target, err := s.target(ctx, orgID, login)
+	if err != nil {
+		return err
+	}
+
+	if actor.Role < domain2.OrgRoleAdmin || target.Role > actor.Role || role > actor.Role {
+		return domain2.ErrOrgForbidden
+	}
+
+	if target.Role == domain2.OrgRoleOwner && role != domain2.OrgRoleOwner && lastOwner(members, target.UID) {
+		return domain2.ErrLastOwner
+	}
+
+	target.Role = role
+
+	return s.saveMember(ctx, target)
+}
+
+// Remove исключение участника login
+// пользователь может сам отклонить приглашение, остальных участников исключают администраторы и владельцы
+// если участник принял приглашение, он знает ключ хранилища, поэтому ключ меняется: keys - новый ключ для каждого
+// оставшегося участника по логину, rotation - записи и папки хранилища, перешифрованные на новом ключе
+func (s *Service) Remove(ctx context.Context, uid, orgID uint64, login string, keys map[string]string, rotation domain2.OrgRotation) error {
+	org, err := s.get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	members, target, err := s.target(ctx, orgID, login)
+	if err != nil {
+		return err
+	}
+
+	if target.UID == uid && target.Accepted {
+		return domain2.ErrOrgForbidden
+	}
+
+	if target.UID != uid {
+		actor, ok := findMember(members, uid)
+		if !ok || !actor.Accepted {
+			return domain2.ErrOrgNotFound
+		}
+
+		if actor.Role < domain2.OrgRoleAdmin || target.Role > actor.Role {
+			return domain2.ErrOrgForbidden
+		}
+	}
+
+	if target.Role == domain2.OrgRoleOwner && lastOwner(members, target.UID) {
+		return domain2.ErrLastOwner
+	}
+
+	if !target.Accepted {
+		if err = s.repo.DeleteMember(ctx, orgID, target.UID); err != nil {
+			internal.Logger.Errorw("error while deleting organization member", "id", orgID, "err", err)
+			return domain2.ErrInternalServerError
+		}
+
+		return nil
+	}
+
+	if rotation.KeyVersion != org.KeyVersion+1 {
+		return domain2.ErrOrgKeyVersion
+	}
+
+	rotation.OrgID, rotation.VaultUID, rotation.RemoveUID = org.ID, org.VaultUID, target.UID
+	rotation.Keys = make(map[uint64]string, len(keys))
+
+	for _, m := range members {
+		if m.UID == target.UID {
+			continue
+		}
+
+		key, ok := keys[m.Login]
+		if !ok {
+			return domain2.ErrOrgRotation
+		}
+
+		rotation.Keys[m.UID] = key
+	}
+
+	if len(rotation.Keys) != len(keys) {
+		return domain2.ErrOrgRotation
+	}
+
+	if err = s.checkRotation(ctx, rotation); err != nil {
+		return err
+	}
+
+	if err = s.repo.Rotate(ctx, rotation); err != nil {
+		if errors.Is(err, domain2.ErrOrgKeyVersion) {
+			return err
+		}
+
+		internal.Logger.Errorw("error while rotating organization key", "id", orgID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Vault ИД учетной записи хранилища организации для запроса пользователя uid к данным
+// изменять данные могут редакторы и выше, и только ключом текущей версии keyVersion
+func (s *Service) Vault(ctx context.Context, orgID, uid uint64, write bool, keyVersion uint64) (uint64, error) {
+	org, m, err := s.member(ctx, orgID, uid)
+	if err != nil {
+		return 0, err
+	}
+
+	if write && m.Role < domain2.OrgRoleEditor {
+		return 0, domain2.ErrOrgForbidden
+	}
+
+	if write && keyVersion != org.KeyVersion {
+		return 0, domain2.ErrOrgKeyVersion
+	}
+
+	return org.VaultUID, nil
+}
+
+// checkRotation проверка, что смена ключа затрагивает ровно все записи и папки хранилища
+func (s *Service) checkRotation(ctx context.Context, rotation domain2.OrgRotation) error {
+	records, err := s.dataRepo.GetList(ctx, rotation.VaultUID, domain2.DataListFilter{})
+	if err != nil {
+		internal.Logger.Errorw("error while fetching vault data", "uid", rotation.VaultUID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	folders, err := s.folderRepo.GetList(ctx, rotation.VaultUID)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching vault folders", "uid", rotation.VaultUID, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	rotated := make(map[uint64]bool, len(rotation.Records))
+	for _, r := range rotation.Records {
+		rotated[r.ID] = true
+	}
+
+	if len(rotated) != len(rotation.Records) || len(rotated) != len(records) {
+		return domain2.ErrOrgRotation
+	}
+
+	for _, r := range records {
+		if !rotated[r.ID] {
+			return domain2.ErrOrgRotation
+		}
+	}
+
+	rotated = make(map[uint64]bool, len(rotation.Folders))
+	for _, f := range rotation.Folders {
+		rotated[f.ID] = true
+	}
+
+	if len(rotated) != len(rotation.Folders) || len(rotated) != len(folders) {
+		return domain2.ErrOrgRotation
+	}
+
+	for _, f := range folders {
+		if !rotated[f.ID] {
+			return domain2.ErrOrgRotation
+		}
+	}
+
+	return nil
+}
+
+// member организация и участие в ней пользователя, принявшего приглашение
+// для остальных пользователей организация не существует
+func (s *Service) member(ctx context.Context, orgID, uid uint64) (*domain2.Organization, domain2.OrgMember, error) {
+	m, err := s.repo.GetMember(ctx, orgID, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching organization member", "id", orgID, "err", err)
+		return nil, domain2.OrgMember{}, domain2.ErrInternalServerError
+	}
+
+	if m == nil || !m.Accepted {
+		return nil, domain2.OrgMember{}, domain2.ErrOrgNotFound
+	}
+
+	org, err := s.get(ctx, orgID)
+	if err != nil {
+		return nil, domain2.OrgMember{}, err
+	}
+
+	return org, *m, nil
+}
+
+// target все участники организации и участник с логином login
+func (s *Service) target(ctx context.Context, orgID uint64, login string) ([]domain2.OrgMember, domain2.OrgMember, error) {
+	members, err := s.repo.GetMembers(ctx, orgID)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching organization members", "id", orgID, "err", err)
+		return nil, domain2.OrgMember{}, domain2.ErrInternalServerError
+	}
+
+	for _, m := range members {
+		if m.Login == login {
+			return members, m, nil
+		}
+	}
+
+	return nil, domain2.OrgMember{}, domain2.ErrMemberNotFound
+}
+
+func (s *Service) get(ctx context.Context, orgID uint64) (*domain2.Organization, error) {
+	org, err := s.repo.Get(ctx, orgID)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching organization", "id", orgID, "err", err)
+		return nil, domain2.ErrInternalServerError
+	}
+
+	if org == nil {
+		return nil, domain2.ErrOrgNotFound
+	}
+
+	return org, nil
+}
+
+func (s *Service) saveMember(ctx context.Context, m domain2.OrgMember) error {
+	if err := s.repo.SaveMember(ctx, m); err != nil {
+		internal.Logger.Errorw("error while saving organization member", "id", m.OrgID, "err", err)
+		return domain2.ErrDataUpdate
+	}
+
+	return nil
+}
+
+// checkPublicKey ключ хранилища шифруется открытым ключом участника, поэтому ключ должен быть задан
+func (s *Service) checkPublicKey(ctx context.Context, uid uint64) error {
+	keys, err := s.userRepo.GetKeys(ctx, uid)
+	if err != nil {
+		internal.Logger.Errorw("error while fetching user keys", "uid", uid, "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	if keys.PublicKey == "" {
+		return domain2.ErrNoPublicKey
+	}
+
+	return nil
+}
+
+func findMember(members []domain2.OrgMember, uid uint64) (domain2.OrgMember, bool) {
+	for _, m := range members {
+		if m.UID == uid {
+			return m, true
+		}
+	}
+
+	return domain2.OrgMember{}, false
+}
+
+// lastOwner участник uid - единственный владелец организации, принявший приглашение
+func lastOwner(members []domain2.OrgMember, uid uint64) bool {
+	for _, m := range members {
+		if m.UID != uid && m.Role == domain2.OrgRoleOwner && m.Accepted {
+			return false
+		}
+	}
+
+	return true
+}
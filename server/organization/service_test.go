@@ -0,0 +1,134 @@
+package organization
+
+import (
+	"context"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
+	domain2 "gophkeeper/server/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	users, data, folders := memory.NewUserRepository(), memory.NewDataRepository(), memory.NewFolderRepository()
+	service := NewService(memory.NewOrganizationRepository(users, data, folders), users, data, folders)
+
+	uids := make(map[string]uint64)
+	for _, login := range []string{"alice", "bob", "carol", "dave"} {
+		uid, err := users.Store(ctx, domain2.User{Login: login, Password: "hash"})
+		require.NoError(t, err)
+		uids[login] = uid
+
+		if login != "dave" {
+			require.NoError(t, users.SetKeys(ctx, uid, domain2.UserKeys{PublicKey: "public-" + login, PrivateKey: "private"}))
+		}
+	}
+
+	_, err := service.Create(ctx, uids["dave"], "team", "key")
+	assert.ErrorIs(t, err, domain2.ErrNoPublicKey)
+
+	org, err := service.Create(ctx, uids["alice"], "team", "key-alice")
+	require.NoError(t, err)
+	assert.NotZero(t, org.VaultUID)
+
+	require.NoError(t, service.Invite(ctx, uids["alice"], org.ID, "bob", domain2.OrgRoleEditor, "key-bob", 1))
+	assert.ErrorIs(t, service.Invite(ctx, uids["alice"], org.ID, "bob", domain2.OrgRoleViewer, "key-bob", 1), domain2.ErrMemberExists)
+	assert.ErrorIs(t, service.Invite(ctx, uids["alice"], org.ID, "dave", domain2.OrgRoleViewer, "key", 1), domain2.ErrNoPublicKey)
+	assert.ErrorIs(t, service.Invite(ctx, uids["alice"], org.ID, "carol", domain2.OrgRoleViewer, "key", 2), domain2.ErrOrgKeyVersion)
+
+	t.Run("pending invite", func(t *testing.T) {
+		list, err := service.GetList(ctx, uids["bob"])
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.False(t, list[0].Member.Accepted)
+		assert.Empty(t, list[0].Member.CollectionKey)
+
+		_, _, err = service.GetMembers(ctx, org.ID, uids["bob"])
+		assert.ErrorIs(t, err, domain2.ErrOrgNotFound)
+
+		_, err = service.Vault(ctx, org.ID, uids["bob"], false, 0)
+		assert.ErrorIs(t, err, domain2.ErrOrgNotFound)
+	})
+
+	require.NoError(t, service.Accept(ctx, uids["bob"], org.ID))
+
+	t.Run("vault access", func(t *testing.T) {
+		vault, err := service.Vault(ctx, org.ID, uids["bob"], true, 1)
+		require.NoError(t, err)
+		assert.Equal(t, org.VaultUID, vault)
+
+		_, err = service.Vault(ctx, org.ID, uids["bob"], true, 0)
+		assert.ErrorIs(t, err, domain2.ErrOrgKeyVersion)
+
+		_, err = service.Vault(ctx, org.ID, uids["carol"], false, 0)
+		assert.ErrorIs(t, err, domain2.ErrOrgNotFound)
+	})
+
+	t.Run("roles", func(t *testing.T) {
+		assert.ErrorIs(t, service.Invite(ctx, uids["bob"], org.ID, "carol", domain2.OrgRoleViewer, "key", 1), domain2.ErrOrgForbidden)
+		assert.ErrorIs(t, service.SetRole(ctx, uids["bob"], org.ID, "bob", domain2.OrgRoleAdmin), domain2.ErrOrgForbidden)
+		assert.ErrorIs(t, service.SetRole(ctx, uids["alice"], org.ID, "alice", domain2.OrgRoleAdmin), domain2.ErrLastOwner)
+
+		require.NoError(t, service.SetRole(ctx, uids["alice"], org.ID, "bob", domain2.OrgRoleViewer))
+
+		_, err := service.Vault(ctx, org.ID, uids["bob"], true, 1)
+		assert.ErrorIs(t, err, domain2.ErrOrgForbidden)
+	})
+
+	record := &domain2.Data{Name: "db", UID: org.VaultUID, Version: 1, RecordKey: new(string), Tags: []string{"old"}}
+	require.NoError(t, data.Insert(ctx, record))
+	folder := &domain2.Folder{Name: "old", UID: org.VaultUID}
+	require.NoError(t, folders.Insert(ctx, folder))
+
+	t.Run("decline invite", func(t *testing.T) {
+		require.NoError(t, service.Invite(ctx, uids["alice"], org.ID, "carol", domain2.OrgRoleViewer, "key-carol", 1))
+		require.NoError(t, service.Remove(ctx, uids["carol"], org.ID, "carol", nil, domain2.OrgRotation{}))
+
+		list, err := service.GetList(ctx, uids["carol"])
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("remove with rotation", func(t *testing.T) {
+		assert.ErrorIs(t, service.Remove(ctx, uids["bob"], org.ID, "bob", nil, domain2.OrgRotation{}), domain2.ErrOrgForbidden)
+
+		rotation := domain2.OrgRotation{
+			KeyVersion: 2,
+			Records:    []domain2.RotatedRecord{{ID: record.ID, RecordKey: "rewrapped", Tags: []string{"new"}}},
+			Folders:    []domain2.Folder{{ID: folder.ID, Name: "new"}},
+		}
+
+		assert.ErrorIs(t, service.Remove(ctx, uids["alice"], org.ID, "bob", map[string]string{"alice": "key2"}, domain2.OrgRotation{KeyVersion: 2}),
+			domain2.ErrOrgRotation)
+		assert.ErrorIs(t, service.Remove(ctx, uids["alice"], org.ID, "bob", map[string]string{"alice": "key2", "bob": "key2"}, rotation),
+			domain2.ErrOrgRotation)
+		assert.ErrorIs(t, service.Remove(ctx, uids["alice"], org.ID, "bob", map[string]string{"alice": "key2"}, domain2.OrgRotation{KeyVersion: 3}),
+			domain2.ErrOrgKeyVersion)
+
+		require.NoError(t, service.Remove(ctx, uids["alice"], org.ID, "bob", map[string]string{"alice": "key2"}, rotation))
+
+		got, members, err := service.GetMembers(ctx, org.ID, uids["alice"])
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), got.KeyVersion)
+		require.Len(t, members, 1)
+		assert.Equal(t, "key2", members[0].CollectionKey)
+		assert.Equal(t, uint64(2), members[0].KeyVersion)
+
+		saved, err := data.Get(ctx, record.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "rewrapped", *saved.RecordKey)
+		assert.Equal(t, []string{"new"}, saved.Tags)
+
+		list, err := folders.GetList(ctx, org.VaultUID)
+		require.NoError(t, err)
+		assert.Equal(t, "new", list[0].Name)
+
+		_, err = service.Vault(ctx, org.ID, uids["bob"], false, 0)
+		assert.ErrorIs(t, err, domain2.ErrOrgNotFound)
+	})
+}
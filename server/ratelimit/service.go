@@ -0,0 +1,199 @@
+// Package ratelimit пакет для защиты входа и регистрации от перебора паролей и массового создания учетных записей
+// попытки ограничиваются корзиной токенов по логину и по IP клиента, после серии неудачных входов ключ блокируется
+// на время, которое удваивается с каждой следующей неудачей
+package ratelimit
+
+import (
+	"context"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+	"math"
+	"time"
+)
+
+// idleAfter время без попыток, после которого состояние ключа удаляется
+const idleAfter = 24 * time.Hour
+
+// Ограничения по умолчанию
+var (
+	// LoginLimit попытки входа в одну учетную запись
+	LoginLimit = domain2.RateLimit{Burst: 5, Every: time.Minute, MaxFailures: 5, LockoutBase: time.Minute, LockoutMax: time.Hour}
+	// IPLimit попытки входа с одного IP в любые учетные записи
+	IPLimit = domain2.RateLimit{Burst: 30, Every: 10 * time.Second, MaxFailures: 20, LockoutBase: time.Minute, LockoutMax: time.Hour}
+	// RegisterLimit регистрации с одного IP
+	RegisterLimit = domain2.RateLimit{Burst: 3, Every: 20 * time.Minute}
+)
+
+type Service struct {
+	repo Repository
+	login,
+	ip,
+	register domain2.RateLimit
+	// now текущее время, подменяется в тестах
+	now func() time.Time
+}
+
+// Repository интерфейс для описания методов хранилища состояния ограничений
+// вызовы Update для одного ключа не должны пересекаться
+type Repository interface {
+	Update(ctx context.Context, key string, update func(bucket *domain2.RateBucket)) error
+	DeleteIdle(ctx context.Context, before time.Time) (int64, error)
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo:     repo,
+		login:    LoginLimit,
+		ip:       IPLimit,
+		register: RegisterLimit,
+		now:      time.Now,
+	}
+}
+
+// Login проверка попытки входа в учетную запись login с адреса ip
+// если попытка запрещена, возвращается время до следующей разрешенной попытки и ErrRateLimited
+func (s *Service) Login(ctx context.Context, login, ip string) (time.Duration, error) {
+	if wait := s.take(ctx, ipKey(ip), s.ip); wait > 0 {
+		return wait, domain2.ErrRateLimited
+	}
+
+	if wait := s.take(ctx, loginKey(login), s.login); wait > 0 {
+		return wait, domain2.ErrRateLimited
+	}
+
+	return 0, nil
+}
+
+// LoginResult учет результата входа: неудача увеличивает счетчики логина и IP, успех сбрасывает счетчик логина
+// счетчик IP успехом не сбрасывается, иначе вход в свою учетную запись позволял бы продолжать перебор чужих
+func (s *Service) LoginResult(ctx context.Context, login, ip string, success bool) {
+	now := s.now()
+
+	if success {
+		s.update(ctx, loginKey(login), func(b *domain2.RateBucket) {
+			b.Failures = 0
+			b.LockedUntil = time.Time{}
+		})
+
+		return
+	}
+
+	s.update(ctx, ipKey(ip), func(b *domain2.RateBucket) { fail(b, s.ip, now) })
+	s.update(ctx, loginKey(login), func(b *domain2.RateBucket) { fail(b, s.login, now) })
+}
+
+// Register проверка попытки регистрации с адреса ip
+func (s *Service) Register(ctx context.Context, ip string) (time.Duration, error) {
+	if wait := s.take(ctx, "register:"+ip, s.register); wait > 0 {
+		return wait, domain2.ErrRateLimited
+	}
+
+	return 0, nil
+}
+
+// Run периодическое удаление состояния ключей, по которым давно не было попыток, до отмены ctx
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.repo.DeleteIdle(ctx, s.now().Add(-idleAfter))
+			if err != nil {
+				internal.Logger.Errorw("error while deleting idle rate limits", "err", err)
+			} else if n > 0 {
+				internal.Logger.Infow("idle rate limits deleted", "count", n)
+			}
+		}
+	}
+}
+
+// take списание токена по ключу, возвращается время ожидания, 0 - попытка разрешена
+func (s *Service) take(ctx context.Context, key string, limit domain2.RateLimit) time.Duration {
+	var (
+		now  = s.now()
+		wait time.Duration
+	)
+
+	s.update(ctx, key, func(b *domain2.RateBucket) {
+		refill(b, limit, now)
+
+		switch {
+		case now.Before(b.LockedUntil):
+			wait = b.LockedUntil.Sub(now)
+		case b.Tokens < 1:
+			wait = time.Duration((1 - b.Tokens) * float64(limit.Every))
+		default:
+			b.Tokens--
+		}
+	})
+
+	return wait
+}
+
+// update изменение состояния ключа; ошибка хранилища только логируется, чтобы сбой базы не закрывал вход всем пользователям
+func (s *Service) update(ctx context.Context, key string, update func(b *domain2.RateBucket)) {
+	if err := s.repo.Update(ctx, key, update); err != nil {
+		internal.Logger.Errorw("error while updating rate limit", "key", key, "err", err)
+	}
+}
+
+// refill пополнение корзины токенами за время с последнего пересчета, новая корзина заполнена
+func refill(b *domain2.RateBucket, limit domain2.RateLimit, now time.Time) {
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = float64(limit.Burst)
+	} else if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(float64(limit.Burst), b.Tokens+float64(elapsed)/float64(limit.Every))
+	}
+
+	if now.After(b.UpdatedAt) {
+		b.UpdatedAt = now
+	}
+}
+
+// fail учет неудачной попытки, счетчик забывается, если после последней неудачи и блокировки прошло больше LockoutMax
+func fail(b *domain2.RateBucket, limit domain2.RateLimit, now time.Time) {
+	if limit.MaxFailures == 0 {
+		return
+	}
+
+	refill(b, limit, now)
+
+	quietSince := b.FailedAt
+	if b.LockedUntil.After(quietSince) {
+		quietSince = b.LockedUntil
+	}
+
+	if now.Sub(quietSince) > limit.LockoutMax {
+		b.Failures = 0
+	}
+
+	b.Failures++
+	b.FailedAt = now
+
+	if b.Failures < limit.MaxFailures {
+		return
+	}
+
+	lockout := limit.LockoutBase
+	for i := limit.MaxFailures; i < b.Failures && lockout < limit.LockoutMax; i++ {
+		lockout *= 2
+	}
+
+	if lockout > limit.LockoutMax {
+		lockout = limit.LockoutMax
+	}
+
+	b.LockedUntil = now.Add(lockout)
+}
+
+func loginKey(login string) string {
+	return "login:" + login
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
+	domain2 "gophkeeper/server/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService() (*Service, *time.Time) {
+	internal.InitLogger()
+
+	service := NewService(memory.NewRateLimitRepository())
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	return service, &now
+}
+
+func TestService_Login(t *testing.T) {
+	ctx := context.Background()
+	service, now := newTestService()
+
+	for i := 0; i < LoginLimit.Burst; i++ {
+		_, err := service.Login(ctx, "alice", "192.0.2.1")
+		require.NoError(t, err)
+	}
+
+	// корзина логина пуста, попытки с других адресов тоже ограничены
+	wait, err := service.Login(ctx, "alice", "192.0.2.2")
+	assert.ErrorIs(t, err, domain2.ErrRateLimited)
+	assert.Equal(t, LoginLimit.Every, wait)
+
+	_, err = service.Login(ctx, "bob", "192.0.2.1")
+	assert.NoError(t, err)
+
+	*now = now.Add(LoginLimit.Every)
+	_, err = service.Login(ctx, "alice", "192.0.2.1")
+	assert.NoError(t, err)
+
+	t.Run("ip", func(t *testing.T) {
+		for i := 0; i < IPLimit.Burst; i++ {
+			_, err := service.Login(ctx, "user"+string(rune('a'+i)), "198.51.100.1")
+			require.NoError(t, err)
+		}
+
+		wait, err := service.Login(ctx, "carol", "198.51.100.1")
+		assert.ErrorIs(t, err, domain2.ErrRateLimited)
+		assert.Equal(t, IPLimit.Every, wait)
+	})
+}
+
+func TestService_Lockout(t *testing.T) {
+	ctx := context.Background()
+	service, now := newTestService()
+
+	attempt := func(success bool) (time.Duration, error) {
+		wait, err := service.Login(ctx, "alice", "192.0.2.1")
+		if err == nil {
+			service.LoginResult(ctx, "alice", "192.0.2.1", success)
+		}
+
+		return wait, err
+	}
+
+	for i := 0; i < LoginLimit.MaxFailures; i++ {
+		_, err := attempt(false)
+		require.NoError(t, err)
+	}
+
+	wait, err := attempt(false)
+	assert.ErrorIs(t, err, domain2.ErrRateLimited)
+	assert.Equal(t, LoginLimit.LockoutBase, wait)
+
+	// каждая неудача после блокировки удваивает ее
+	for _, want := range []time.Duration{2 * time.Minute, 4 * time.Minute, 8 * time.Minute} {
+		*now = now.Add(wait)
+		_, err = attempt(false)
+		require.NoError(t, err)
+
+		wait, err = attempt(false)
+		assert.ErrorIs(t, err, domain2.ErrRateLimited)
+		assert.Equal(t, want, wait)
+	}
+
+	t.Run("max", func(t *testing.T) {
+		b := domain2.RateBucket{Failures: 100, FailedAt: *now}
+		fail(&b, LoginLimit, *now)
+		assert.Equal(t, now.Add(LoginLimit.LockoutMax), b.LockedUntil)
+	})
+
+	// успешный вход снимает блокировку логина
+	*now = now.Add(wait)
+	_, err = attempt(true)
+	require.NoError(t, err)
+
+	*now = now.Add(LoginLimit.Every)
+	_, err = attempt(false)
+	require.NoError(t, err)
+	_, err = attempt(false)
+	assert.NoError(t, err)
+
+	t.Run("forget", func(t *testing.T) {
+		b := domain2.RateBucket{Failures: 10, FailedAt: *now, LockedUntil: now.Add(time.Hour)}
+		fail(&b, LoginLimit, now.Add(2*time.Hour+time.Second))
+		assert.Equal(t, 1, b.Failures)
+		assert.False(t, b.LockedUntil.After(now.Add(time.Hour)))
+	})
+}
+
+func TestService_Register(t *testing.T) {
+	ctx := context.Background()
+	service, now := newTestService()
+
+	for i := 0; i < RegisterLimit.Burst; i++ {
+		_, err := service.Register(ctx, "192.0.2.1")
+		require.NoError(t, err)
+	}
+
+	wait, err := service.Register(ctx, "192.0.2.1")
+	assert.ErrorIs(t, err, domain2.ErrRateLimited)
+	assert.Equal(t, RegisterLimit.Every, wait)
+
+	_, err = service.Register(ctx, "192.0.2.2")
+	assert.NoError(t, err)
+
+	// вход с адреса не зависит от регистраций
+	_, err = service.Login(ctx, "alice", "192.0.2.1")
+	assert.NoError(t, err)
+
+	*now = now.Add(RegisterLimit.Every / 2)
+	wait, err = service.Register(ctx, "192.0.2.1")
+	assert.ErrorIs(t, err, domain2.ErrRateLimited)
+	assert.Equal(t, RegisterLimit.Every/2, wait)
+
+	repo := service.repo.(*memory.RateLimitRepository)
+	n, err := repo.DeleteIdle(ctx, now.Add(idleAfter))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+}
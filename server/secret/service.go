@@ -0,0 +1,106 @@
+// Package secret пакет для одноразовых ссылок на секреты
+// секрет шифруется на клиенте, ключ передается только во фрагменте ссылки, который браузер на сервер не отправляет
+package secret
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"gophkeeper/internal"
+	domain2 "gophkeeper/server/domain"
+	"strings"
+	"time"
+)
+
+// LinkPath путь ссылки на секрет, к нему добавляется ИД секрета
+const LinkPath = "/s/"
+
+// idLength длина случайного ИД секрета в байтах, ИД нельзя подобрать перебором
+const idLength = 16
+
+type Service struct {
+	repo Repository
+	// baseURL адрес, по которому секреты доступны получателям, пустой - ссылки отключены
+	baseURL string
+	// now текущее время, подменяется в тестах
+	now func() time.Time
+}
+
+// Repository интерфейс для описания методов хранилища секретов
+type Repository interface {
+	Insert(ctx context.Context, share domain2.SecretShare) error
+	Take(ctx context.Context, id string, now time.Time) (*domain2.SecretShare, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+func NewService(repo Repository, baseURL string) *Service {
+	return &Service{
+		repo:    repo,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		now:     time.Now,
+	}
+}
+
+// Create сохранение зашифрованного секрета пользователя uid, возвращается секрет и ссылка на него без ключа
+func (s *Service) Create(ctx context.Context, uid uint64, blob string, expire time.Duration, maxViews int) (domain2.SecretShare, string, error) {
+	if s.baseURL == "" {
+		return domain2.SecretShare{}, "", domain2.ErrSecretLinksDisabled
+	}
+
+	raw := make([]byte, idLength)
+	if _, err := rand.Read(raw); err != nil {
+		internal.Logger.Errorw("error while generating secret id", "err", err)
+		return domain2.SecretShare{}, "", domain2.ErrInternalServerError
+	}
+
+	share := domain2.SecretShare{
+		ID:        base64.RawURLEncoding.EncodeToString(raw),
+		UID:       uid,
+		Blob:      blob,
+		ExpiresAt: s.now().Add(expire),
+		MaxViews:  maxViews,
+	}
+
+	if err := s.repo.Insert(ctx, share); err != nil {
+		internal.Logger.Errorw("error while inserting secret", "uid", uid, "err", err)
+		return domain2.SecretShare{}, "", domain2.ErrDataInsert
+	}
+
+	return share, s.baseURL + LinkPath + share.ID, nil
+}
+
+// Take выдача зашифрованного секрета получателю, после последнего просмотра секрет удаляется
+func (s *Service) Take(ctx context.Context, id string) (string, error) {
+	share, err := s.repo.Take(ctx, id, s.now())
+	if err != nil {
+		internal.Logger.Errorw("error while taking secret", "err", err)
+		return "", domain2.ErrInternalServerError
+	}
+
+	if share == nil {
+		return "", domain2.ErrSecretNotFound
+	}
+
+	return share.Blob, nil
+}
+
+// Run периодическое удаление истекших секретов до отмены ctx
+// истекший секрет и так не выдается, удаление нужно, чтобы не хранить зашифрованные данные дольше срока ссылки
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.repo.DeleteExpired(ctx, s.now())
+			if err != nil {
+				internal.Logger.Errorw("error while deleting expired secrets", "err", err)
+			} else if n > 0 {
+				internal.Logger.Infow("expired secrets deleted", "count", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package secret
+
+import (
+	"context"
+	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
+	domain2 "gophkeeper/server/domain"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	internal.InitLogger()
+
+	_, _, err := NewService(memory.NewSecretRepository(), "").Create(ctx, 1, "blob", time.Hour, 1)
+	assert.ErrorIs(t, err, domain2.ErrSecretLinksDisabled)
+
+	service := NewService(memory.NewSecretRepository(), "https://keeper.example/")
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	share, url, err := service.Create(ctx, 1, "blob", time.Hour, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "https://keeper.example/s/"+share.ID, url)
+	assert.Len(t, share.ID, 22)
+
+	other, _, err := service.Create(ctx, 1, "blob", time.Hour, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, share.ID, other.ID)
+
+	t.Run("max views", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			blob, err := service.Take(ctx, share.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "blob", blob)
+		}
+
+		_, err = service.Take(ctx, share.ID)
+		assert.ErrorIs(t, err, domain2.ErrSecretNotFound)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		now = now.Add(time.Hour)
+
+		_, err = service.Take(ctx, other.ID)
+		assert.ErrorIs(t, err, domain2.ErrSecretNotFound)
+	})
+
+	_, err = service.Take(ctx, strings.Repeat("x", 22))
+	assert.ErrorIs(t, err, domain2.ErrSecretNotFound)
+}
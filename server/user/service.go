@@ -7,6 +7,7 @@ import (
 	"gophkeeper/internal"
 	"gophkeeper/internal/server/auth"
 	domain2 "gophkeeper/server/domain"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"golang.org/x/crypto/bcrypt"
@@ -14,13 +15,31 @@ import (
 
 type ContextUserIDKey struct{}
 
+// ContextActorIDKey ИД пользователя, выполняющего запрос, если ContextUserIDKey заменен на ИД хранилища
+// организации или пользователя, предоставившего экстренный доступ
+type ContextActorIDKey struct{}
+
+// ContextIssuedAtKey время выдачи токена доступа запроса, по нему проверяется, что сессия не отозвана
+type ContextIssuedAtKey struct{}
+
 type Service struct {
 	userRepo Repository
+	// Audit журнал аудита, nil - события не записываются
+	Audit Auditor
 }
 
 type Repository interface {
 	GetByLogin(ctx context.Context, login string) (domain2.User, error)
 	Store(ctx context.Context, user domain2.User) (uint64, error)
+	GetKeys(ctx context.Context, id uint64) (domain2.UserKeys, error)
+	SetKeys(ctx context.Context, id uint64, keys domain2.UserKeys) error
+	GetState(ctx context.Context, id uint64) (*domain2.AccountState, error)
+	SetState(ctx context.Context, id uint64, state domain2.AccountState) error
+}
+
+// Auditor интерфейс записи событий в журнал аудита
+type Auditor interface {
+	Record(ctx context.Context, uid uint64, action domain2.AuditAction, dataID uint64, err error)
 }
 
 func NewService(u Repository) *Service {
@@ -30,67 +49,236 @@ func NewService(u Repository) *Service {
 }
 
 // Register регистрация пользователя
-func (u *Service) Register(ctx context.Context, user domain2.User) (string, error) {
+func (u *Service) Register(ctx context.Context, user domain2.User) (domain2.Tokens, error) {
 	dbUser, err := u.userRepo.GetByLogin(ctx, user.Login)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		internal.Logger.Infow("error in get by login", "err", err)
-		return "", domain2.ErrInternalServerError
+		return domain2.Tokens{}, domain2.ErrInternalServerError
 	}
 
 	if (err != nil && errors.Is(err, pgx.ErrNoRows)) || (err == nil && dbUser.ID != 0) {
-		return "", domain2.ErrLoginExist
+		return domain2.Tokens{}, domain2.ErrLoginExist
 	}
 
 	user.Password, err = HashPassword(user.Password)
 	if err != nil {
 		internal.Logger.Infow("error in crypt passwd", "err", err)
-		return "", domain2.ErrInternalServerError
+		return domain2.Tokens{}, domain2.ErrInternalServerError
 	}
 
 	userID, err := u.userRepo.Store(ctx, user)
 	if err != nil {
 		internal.Logger.Infow("error save user", "err", err)
-		return "", domain2.ErrInternalServerError
+		return domain2.Tokens{}, domain2.ErrInternalServerError
+	}
+
+	tokens, err := issueTokens(userID)
+	u.record(ctx, userID, domain2.AuditRegister, err)
+
+	return tokens, err
+}
+
+// Login авторизация пользователя
+func (u *Service) Login(ctx context.Context, user domain2.User) (domain2.Tokens, error) {
+	dbUser, err := u.userRepo.GetByLogin(ctx, user.Login)
+	if err != nil {
+		internal.Logger.Infow("error in get by login", "err", err)
+		return domain2.Tokens{}, domain2.ErrInternalServerError
+	}
+
+	if dbUser.ID == 0 {
+		return domain2.Tokens{}, domain2.ErrUserNotFound
 	}
 
-	token, err := auth.BuildJWTString(userID)
+	passwordCorrect, err := CheckPassword(user.Password, dbUser.Password)
 	if err != nil {
-		internal.Logger.Infow("error generation token", "err", err)
-		return "", domain2.ErrInternalServerError
+		internal.Logger.Infow("error in check passwd", "err", err)
+		return domain2.Tokens{}, domain2.ErrInternalServerError
+	}
+
+	if !passwordCorrect {
+		// неверный пароль к существующей учетной записи - признак подбора, событие видно ее владельцу
+		u.record(ctx, dbUser.ID, domain2.AuditLogin, domain2.ErrUserNotFound)
+		return domain2.Tokens{}, domain2.ErrUserNotFound
+	}
+
+	// удаляемая учетная запись только восстанавливается через Restore
+	state, err := u.userRepo.GetState(ctx, dbUser.ID)
+	if err != nil {
+		internal.Logger.Infow("error in get account state", "err", err)
+		return domain2.Tokens{}, domain2.ErrInternalServerError
+	}
+
+	if state != nil && !state.PurgeAt.IsZero() {
+		u.record(ctx, dbUser.ID, domain2.AuditLogin, domain2.ErrAccountDeleting)
+		return domain2.Tokens{}, domain2.ErrAccountDeleting
 	}
 
-	return token, nil
+	tokens, err := issueTokens(dbUser.ID)
+	u.record(ctx, dbUser.ID, domain2.AuditLogin, err)
+
+	return tokens, err
 }
 
-// Login авторизация пользователя
-func (u *Service) Login(ctx context.Context, user domain2.User) (string, error) {
+// Restore отмена запланированного удаления учетной записи по логину и паролю, выдаются новые токены
+// токены, выданные до запроса на удаление, остаются недействительными
+func (u *Service) Restore(ctx context.Context, user domain2.User) (domain2.Tokens, error) {
 	dbUser, err := u.userRepo.GetByLogin(ctx, user.Login)
 	if err != nil {
 		internal.Logger.Infow("error in get by login", "err", err)
-		return "", domain2.ErrInternalServerError
+		return domain2.Tokens{}, domain2.ErrInternalServerError
 	}
 
 	if dbUser.ID == 0 {
-		return "", domain2.ErrUserNotFound
+		return domain2.Tokens{}, domain2.ErrUserNotFound
 	}
 
-	passwordCorrect, err := checkPassword(user.Password, dbUser.Password)
+	passwordCorrect, err := CheckPassword(user.Password, dbUser.Password)
 	if err != nil {
 		internal.Logger.Infow("error in check passwd", "err", err)
-		return "", domain2.ErrInternalServerError
+		return domain2.Tokens{}, domain2.ErrInternalServerError
 	}
 
 	if !passwordCorrect {
+		u.record(ctx, dbUser.ID, domain2.AuditRestoreAccount, domain2.ErrUserNotFound)
+		return domain2.Tokens{}, domain2.ErrUserNotFound
+	}
+
+	state, err := u.userRepo.GetState(ctx, dbUser.ID)
+	if err != nil {
+		internal.Logger.Infow("error in get account state", "err", err)
+		return domain2.Tokens{}, domain2.ErrInternalServerError
+	}
+
+	if state == nil || state.PurgeAt.IsZero() {
+		return domain2.Tokens{}, domain2.ErrAccountActive
+	}
+
+	state.PurgeAt = time.Time{}
+	if err = u.userRepo.SetState(ctx, dbUser.ID, *state); err != nil {
+		internal.Logger.Infow("error in save account state", "err", err)
+		return domain2.Tokens{}, domain2.ErrInternalServerError
+	}
+
+	tokens, err := issueTokens(dbUser.ID)
+	u.record(ctx, dbUser.ID, domain2.AuditRestoreAccount, err)
+
+	return tokens, err
+}
+
+// CheckSession проверка, что учетная запись uid не удалена и не удаляется, а токен, выданный в issuedAt, не отозван
+func (u *Service) CheckSession(ctx context.Context, uid uint64, issuedAt time.Time) error {
+	state, err := u.userRepo.GetState(ctx, uid)
+	if err != nil {
+		internal.Logger.Infow("error in get account state", "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	switch {
+	case state == nil:
+		return domain2.ErrAccountDeleted
+	case !state.PurgeAt.IsZero():
+		return domain2.ErrAccountDeleting
+	case issuedAt.Before(state.SessionsAfter):
+		return domain2.ErrSessionRevoked
+	}
+
+	return nil
+}
+
+// Refresh выдача новой пары токенов по токену обновления
+func (u *Service) Refresh(ctx context.Context, refreshToken string) (domain2.Tokens, error) {
+	userID, issuedAt, err := auth.GetRefreshSession(refreshToken)
+	if err != nil || userID == 0 {
+		return domain2.Tokens{}, domain2.ErrBadRefreshToken
+	}
+
+	if err = u.CheckSession(ctx, userID, issuedAt); err != nil {
+		u.record(ctx, userID, domain2.AuditRefresh, err)
+		return domain2.Tokens{}, err
+	}
+
+	tokens, err := issueTokens(userID)
+	u.record(ctx, userID, domain2.AuditRefresh, err)
+
+	return tokens, err
+}
+
+// SetKeys сохранение пары ключей пользователя
+// ключи задаются один раз: с новой парой получатели не смогут открыть уже предоставленные им записи
+func (u *Service) SetKeys(ctx context.Context, uid uint64, keys domain2.UserKeys) error {
+	existing, err := u.GetKeys(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if existing.PublicKey != "" {
+		return domain2.ErrKeysExist
+	}
+
+	if err = u.userRepo.SetKeys(ctx, uid, keys); err != nil {
+		internal.Logger.Infow("error save user keys", "err", err)
+		return domain2.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// GetKeys пара ключей пользователя, пустая - если ключи еще не заданы
+func (u *Service) GetKeys(ctx context.Context, uid uint64) (domain2.UserKeys, error) {
+	keys, err := u.userRepo.GetKeys(ctx, uid)
+	if err != nil {
+		internal.Logger.Infow("error in get user keys", "err", err)
+		return keys, domain2.ErrInternalServerError
+	}
+
+	return keys, nil
+}
+
+// GetPublicKey открытый ключ пользователя по логину
+func (u *Service) GetPublicKey(ctx context.Context, login string) (string, error) {
+	dbUser, err := u.userRepo.GetByLogin(ctx, login)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		internal.Logger.Infow("error in get by login", "err", err)
+		return "", domain2.ErrInternalServerError
+	}
+
+	if dbUser.ID == 0 {
 		return "", domain2.ErrUserNotFound
 	}
 
-	token, err := auth.BuildJWTString(dbUser.ID)
+	keys, err := u.GetKeys(ctx, dbUser.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if keys.PublicKey == "" {
+		return "", domain2.ErrNoPublicKey
+	}
+
+	return keys.PublicKey, nil
+}
+
+func (u *Service) record(ctx context.Context, uid uint64, action domain2.AuditAction, err error) {
+	if u.Audit != nil {
+		u.Audit.Record(ctx, uid, action, 0, err)
+	}
+}
+
+func issueTokens(userID uint64) (domain2.Tokens, error) {
+	access, err := auth.BuildJWTString(userID)
 	if err != nil {
 		internal.Logger.Infow("error generation token", "err", err)
-		return "", domain2.ErrInternalServerError
+		return domain2.Tokens{}, domain2.ErrInternalServerError
+	}
+
+	refresh, err := auth.BuildRefreshToken(userID)
+	if err != nil {
+		internal.Logger.Infow("error generation refresh token", "err", err)
+		return domain2.Tokens{}, domain2.ErrInternalServerError
 	}
 
-	return token, nil
+	return domain2.Tokens{Access: access, Refresh: refresh}, nil
 }
 
 // HashPassword кодировка пароля перед сохранением в базе данных
@@ -103,7 +291,8 @@ func HashPassword(password string) (string, error) {
 	return string(hashedPass), nil
 }
 
-func checkPassword(password, passwordHash string) (bool, error) {
+// CheckPassword проверка пароля по хешу из базы данных
+func CheckPassword(password, passwordHash string) (bool, error) {
 	err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password))
 	if err == nil {
 		return true, nil
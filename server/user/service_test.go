@@ -3,10 +3,12 @@ package user
 import (
 	"context"
 	"gophkeeper/internal"
+	"gophkeeper/internal/server/repository/memory"
 	"gophkeeper/internal/server/repository/pgsql"
 	"gophkeeper/internal/test"
 	domain2 "gophkeeper/server/domain"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +19,8 @@ func TestMain(m *testing.M) {
 }
 
 func TestService_Register(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	testUserTable := "reg_test_users"
 	ctx := context.Background()
 	internal.InitLogger()
@@ -89,6 +93,8 @@ func TestService_Register(t *testing.T) {
 }
 
 func TestService_Auth(t *testing.T) {
+	test.SkipWithoutDB(t)
+
 	tableName := "test_users_auth"
 	internal.InitLogger()
 	ctx := context.Background()
@@ -174,3 +180,43 @@ func TestService_Auth(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Restore(t *testing.T) {
+	internal.InitLogger()
+	ctx := context.Background()
+
+	repo := memory.NewUserRepository()
+	service := NewService(repo)
+
+	hashedPass, err := HashPassword("testtest")
+	assert.NoError(t, err)
+	id, err := repo.Store(ctx, domain2.User{Login: "test", Password: hashedPass})
+	assert.NoError(t, err)
+
+	user := domain2.User{Login: "test", Password: "testtest"}
+	now := time.Now().Truncate(time.Second)
+
+	_, err = service.Restore(ctx, user)
+	assert.ErrorIs(t, err, domain2.ErrAccountActive)
+
+	assert.NoError(t, repo.SetState(ctx, id, domain2.AccountState{PurgeAt: now.Add(time.Hour), SessionsAfter: now}))
+
+	_, err = service.Login(ctx, user)
+	assert.ErrorIs(t, err, domain2.ErrAccountDeleting)
+	assert.ErrorIs(t, service.CheckSession(ctx, id, now), domain2.ErrAccountDeleting)
+
+	_, err = service.Restore(ctx, domain2.User{Login: "test", Password: "testtesttt"})
+	assert.ErrorIs(t, err, domain2.ErrUserNotFound)
+
+	tokens, err := service.Restore(ctx, user)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.Access)
+
+	// токены, выданные до запроса на удаление, остаются отозванными
+	assert.NoError(t, service.CheckSession(ctx, id, now))
+	assert.ErrorIs(t, service.CheckSession(ctx, id, now.Add(-time.Second)), domain2.ErrSessionRevoked)
+	assert.ErrorIs(t, service.CheckSession(ctx, id+1, now), domain2.ErrAccountDeleted)
+
+	_, err = service.Login(ctx, user)
+	assert.NoError(t, err)
+}